@@ -0,0 +1,174 @@
+package afero
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+
+	x_afero "github.com/spf13/afero"
+)
+
+// Fs is an afero file system backed by iRODS, implements afero.Fs
+// names are resolved under the root path
+type Fs struct {
+	filesystem *fs.FileSystem
+	rootPath   string
+}
+
+// NewFs creates a new Fs serving iRODS entries under rootPath
+func NewFs(filesystem *fs.FileSystem, rootPath string) *Fs {
+	return &Fs{
+		filesystem: filesystem,
+		rootPath:   rootPath,
+	}
+}
+
+// getIRODSPath returns an iRODS path of the name, the name cannot go above the root path
+func (afs *Fs) getIRODSPath(name string) string {
+	return path.Join(afs.rootPath, path.Clean("/"+name))
+}
+
+// Name returns the name of the file system
+func (afs *Fs) Name() string {
+	return "IRODSFs"
+}
+
+// Create creates or truncates a file
+func (afs *Fs) Create(name string) (x_afero.File, error) {
+	return afs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Mkdir creates a directory, the parent directory must exist
+func (afs *Fs) Mkdir(name string, perm os.FileMode) error {
+	err := afs.filesystem.MakeDir(afs.getIRODSPath(name), false)
+	return fs.ToOSError("mkdir", name, err)
+}
+
+// MkdirAll creates a directory with its parents, it is not an error if the directory exists
+func (afs *Fs) MkdirAll(name string, perm os.FileMode) error {
+	err := afs.filesystem.MakeDir(afs.getIRODSPath(name), true)
+	return fs.ToOSError("mkdir", name, err)
+}
+
+// Open opens a file or a directory for read
+func (afs *Fs) Open(name string) (x_afero.File, error) {
+	return afs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile opens a file or a directory
+func (afs *Fs) OpenFile(name string, flag int, perm os.FileMode) (x_afero.File, error) {
+	irodsPath := afs.getIRODSPath(name)
+
+	entry, err := afs.filesystem.Stat(irodsPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) || flag&os.O_CREATE == 0 {
+			return nil, fs.ToOSError("open", name, err)
+		}
+
+		handle, err := afs.filesystem.CreateFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+		if err != nil {
+			return nil, fs.ToOSError("open", name, err)
+		}
+		return newFile(name, handle), nil
+	}
+
+	if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+		return nil, fs.ToOSError("open", name, types.NewFileAlreadyExistError(irodsPath))
+	}
+
+	if entry.IsDir() {
+		if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: xerrors.Errorf("%s is a directory", irodsPath)}
+		}
+		return newDir(name, afs.filesystem, entry), nil
+	}
+
+	handle, err := afs.filesystem.OpenFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+	if err != nil {
+		return nil, fs.ToOSError("open", name, err)
+	}
+	return newFile(name, handle), nil
+}
+
+// Remove removes a file or an empty directory
+func (afs *Fs) Remove(name string) error {
+	irodsPath := afs.getIRODSPath(name)
+
+	entry, err := afs.filesystem.Stat(irodsPath)
+	if err != nil {
+		return fs.ToOSError("remove", name, err)
+	}
+
+	if entry.IsDir() {
+		err = afs.filesystem.RemoveDir(irodsPath, false, true)
+	} else {
+		err = afs.filesystem.RemoveFile(irodsPath, true)
+	}
+	return fs.ToOSError("remove", name, err)
+}
+
+// RemoveAll removes a file or a directory recursively, it is not an error if the name does not exist
+func (afs *Fs) RemoveAll(name string) error {
+	irodsPath := afs.getIRODSPath(name)
+
+	entry, err := afs.filesystem.Stat(irodsPath)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			return nil
+		}
+		return fs.ToOSError("removeall", name, err)
+	}
+
+	if entry.IsDir() {
+		err = afs.filesystem.RemoveDir(irodsPath, true, true)
+	} else {
+		err = afs.filesystem.RemoveFile(irodsPath, true)
+	}
+	return fs.ToOSError("removeall", name, err)
+}
+
+// Rename renames a file or a directory to the exact new name
+func (afs *Fs) Rename(oldName string, newName string) error {
+	irodsSrcPath := afs.getIRODSPath(oldName)
+	irodsDestPath := afs.getIRODSPath(newName)
+
+	entry, err := afs.filesystem.Stat(irodsSrcPath)
+	if err != nil {
+		return fs.ToOSError("rename", oldName, err)
+	}
+
+	if entry.IsDir() {
+		err = afs.filesystem.RenameDirToDir(irodsSrcPath, irodsDestPath)
+	} else {
+		err = afs.filesystem.RenameFileToFile(irodsSrcPath, irodsDestPath)
+	}
+	return fs.ToOSError("rename", oldName, err)
+}
+
+// Stat returns file info of a file or a directory
+func (afs *Fs) Stat(name string) (os.FileInfo, error) {
+	entry, err := afs.filesystem.Stat(afs.getIRODSPath(name))
+	if err != nil {
+		return nil, fs.ToOSError("stat", name, err)
+	}
+	return entry.ToFileInfo(), nil
+}
+
+// Chmod is not supported, iRODS uses ACLs instead of modes
+func (afs *Fs) Chmod(name string, mode os.FileMode) error {
+	return &os.PathError{Op: "chmod", Path: name, Err: xerrors.Errorf("chmod is not supported")}
+}
+
+// Chown is not supported, iRODS uses ACLs instead of uid and gid
+func (afs *Fs) Chown(name string, uid int, gid int) error {
+	return &os.PathError{Op: "chown", Path: name, Err: xerrors.Errorf("chown is not supported")}
+}
+
+// Chtimes is not supported
+func (afs *Fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return &os.PathError{Op: "chtimes", Path: name, Err: xerrors.Errorf("chtimes is not supported")}
+}
@@ -0,0 +1,134 @@
+package afero_test
+
+import (
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/afero"
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+
+	x_afero "github.com/spf13/afero"
+)
+
+func TestAfero(t *testing.T) {
+	t.Run("test AferoFs", testAferoFs)
+}
+
+func testAferoFs(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	var afs x_afero.Fs = afero.NewFs(filesystem, homedir)
+	util := &x_afero.Afero{Fs: afs}
+
+	err = afs.MkdirAll("/site/posts", 0755)
+	failError(t, err)
+
+	err = afs.Mkdir("/site", 0755)
+	assert.True(t, os.IsExist(err))
+
+	err = util.WriteFile("/site/index.html", []byte("<html></html>"), 0644)
+	failError(t, err)
+
+	err = util.WriteFile("/site/posts/first.md", []byte("# first"), 0644)
+	failError(t, err)
+
+	data, err := util.ReadFile("/site/index.html")
+	failError(t, err)
+	assert.Equal(t, "<html></html>", string(data))
+
+	exists, err := util.Exists("/site/posts/first.md")
+	failError(t, err)
+	assert.True(t, exists)
+
+	_, err = afs.Open("/site/missing.html")
+	assert.True(t, os.IsNotExist(err))
+
+	// directory listing
+	dir, err := afs.Open("/site")
+	failError(t, err)
+
+	names, err := dir.Readdirnames(0)
+	failError(t, err)
+	sort.Strings(names)
+	assert.Equal(t, []string{"index.html", "posts"}, names)
+
+	_, err = dir.Readdirnames(1)
+	assert.Equal(t, io.EOF, err)
+	err = dir.Close()
+	failError(t, err)
+
+	// walk
+	walked := []string{}
+	err = util.Walk("/site", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, p)
+		return nil
+	})
+	failError(t, err)
+	assert.ElementsMatch(t, []string{"/site", "/site/index.html", "/site/posts", "/site/posts/first.md"}, walked)
+
+	// rename and remove
+	err = afs.Rename("/site/posts/first.md", "/site/posts/renamed.md")
+	failError(t, err)
+
+	info, err := afs.Stat("/site/posts/renamed.md")
+	failError(t, err)
+	assert.Equal(t, int64(7), info.Size())
+
+	err = afs.Remove("/site/posts")
+	assert.Error(t, err)
+
+	err = afs.Remove("/site/posts/renamed.md")
+	failError(t, err)
+
+	err = afs.Remove("/site/posts")
+	failError(t, err)
+
+	err = afs.RemoveAll("/site")
+	failError(t, err)
+
+	exists, err = util.DirExists("/site")
+	failError(t, err)
+	assert.False(t, exists)
+
+	err = afs.Chmod("/site", 0700)
+	assert.Error(t, err)
+}
+
+// startMockServer starts a mock iRODS server with users rods and alice, as test/testcases does
+func startMockServer(t *testing.T) *mock.IRODSMockServer {
+	mockServer, err := mock.NewIRODSMockServer("mockzone", "rods", "rods_password")
+	failError(t, err)
+
+	err = mockServer.AddUser("alice", "alice_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	err = mockServer.Start()
+	failError(t, err)
+
+	return mockServer
+}
+
+func failError(t *testing.T, err error) {
+	if err != nil {
+		t.Errorf("%+v", err)
+		t.FailNow()
+	}
+}
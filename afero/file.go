@@ -0,0 +1,175 @@
+package afero
+
+import (
+	"io"
+	"os"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"golang.org/x/xerrors"
+)
+
+// File is an opened iRODS data object, implements afero.File
+type File struct {
+	*fs.FileHandle
+	name string
+}
+
+func newFile(name string, handle *fs.FileHandle) *File {
+	return &File{
+		FileHandle: handle,
+		name:       name,
+	}
+}
+
+// Name returns the name given to open the file
+func (file *File) Name() string {
+	return file.name
+}
+
+// Readdir always fails since a file is not a directory
+func (file *File) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, xerrors.Errorf("failed to read dir, %s is not a directory", file.name)
+}
+
+// Readdirnames always fails since a file is not a directory
+func (file *File) Readdirnames(n int) ([]string, error) {
+	return nil, xerrors.Errorf("failed to read dir, %s is not a directory", file.name)
+}
+
+// Stat returns file info of the file at the time it is opened
+func (file *File) Stat() (os.FileInfo, error) {
+	return file.GetEntry().ToFileInfo(), nil
+}
+
+// Sync does nothing, data is written to iRODS directly
+func (file *File) Sync() error {
+	return nil
+}
+
+// WriteString writes a string
+func (file *File) WriteString(s string) (int, error) {
+	return file.Write([]byte(s))
+}
+
+// Dir is an opened iRODS collection, implements afero.File
+type Dir struct {
+	name       string
+	filesystem *fs.FileSystem
+	entry      *fs.Entry
+	children   []*fs.Entry
+	listed     bool
+}
+
+func newDir(name string, filesystem *fs.FileSystem, entry *fs.Entry) *Dir {
+	return &Dir{
+		name:       name,
+		filesystem: filesystem,
+		entry:      entry,
+		children:   nil,
+		listed:     false,
+	}
+}
+
+// Name returns the name given to open the directory
+func (dir *Dir) Name() string {
+	return dir.name
+}
+
+// Close closes the directory
+func (dir *Dir) Close() error {
+	return nil
+}
+
+// Read always fails since a directory has no content
+func (dir *Dir) Read(buffer []byte) (int, error) {
+	return 0, xerrors.Errorf("failed to read, %s is a directory", dir.name)
+}
+
+// ReadAt always fails since a directory has no content
+func (dir *Dir) ReadAt(buffer []byte, offset int64) (int, error) {
+	return 0, xerrors.Errorf("failed to read, %s is a directory", dir.name)
+}
+
+// Write always fails since a directory has no content
+func (dir *Dir) Write(data []byte) (int, error) {
+	return 0, xerrors.Errorf("failed to write, %s is a directory", dir.name)
+}
+
+// WriteAt always fails since a directory has no content
+func (dir *Dir) WriteAt(data []byte, offset int64) (int, error) {
+	return 0, xerrors.Errorf("failed to write, %s is a directory", dir.name)
+}
+
+// WriteString always fails since a directory has no content
+func (dir *Dir) WriteString(s string) (int, error) {
+	return 0, xerrors.Errorf("failed to write, %s is a directory", dir.name)
+}
+
+// Truncate always fails since a directory has no content
+func (dir *Dir) Truncate(size int64) error {
+	return xerrors.Errorf("failed to truncate, %s is a directory", dir.name)
+}
+
+// Sync does nothing
+func (dir *Dir) Sync() error {
+	return nil
+}
+
+// Seek rewinds directory listing if offset is 0 from start, fails otherwise
+func (dir *Dir) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, xerrors.Errorf("failed to seek, %s is a directory", dir.name)
+	}
+
+	dir.children = nil
+	dir.listed = false
+	return 0, nil
+}
+
+// Readdir returns file info of up to count entries in the directory, all remaining entries if count <= 0
+func (dir *Dir) Readdir(count int) ([]os.FileInfo, error) {
+	if !dir.listed {
+		children, err := dir.filesystem.List(dir.entry.Path)
+		if err != nil {
+			return nil, fs.ToOSError("readdir", dir.name, err)
+		}
+
+		dir.children = children
+		dir.listed = true
+	}
+
+	if count > 0 && len(dir.children) == 0 {
+		return nil, io.EOF
+	}
+
+	entries := dir.children
+	if count > 0 && count < len(entries) {
+		entries = entries[:count]
+	}
+	dir.children = dir.children[len(entries):]
+
+	infos := make([]os.FileInfo, len(entries))
+	for idx, entry := range entries {
+		infos[idx] = entry.ToFileInfo()
+	}
+	return infos, nil
+}
+
+// Readdirnames returns names of up to n entries in the directory, all remaining entries if n <= 0
+func (dir *Dir) Readdirnames(n int) ([]string, error) {
+	infos, err := dir.Readdir(n)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(infos))
+	for idx, info := range infos {
+		names[idx] = info.Name()
+	}
+	return names, nil
+}
+
+// Stat returns file info of the directory
+func (dir *Dir) Stat() (os.FileInfo, error) {
+	return dir.entry.ToFileInfo(), nil
+}
@@ -0,0 +1,195 @@
+package billy
+
+import (
+	"os"
+	"path"
+	"sort"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+
+	x_billy "github.com/go-git/go-billy/v5"
+)
+
+// Filesystem is a billy file system backed by iRODS, implements billy.Filesystem
+// names are resolved under the root path, iRODS has no symlinks, so Symlink and Readlink are not supported
+type Filesystem struct {
+	filesystem *fs.FileSystem
+	rootPath   string
+}
+
+// NewFilesystem creates a new Filesystem serving iRODS entries under rootPath
+func NewFilesystem(filesystem *fs.FileSystem, rootPath string) *Filesystem {
+	return &Filesystem{
+		filesystem: filesystem,
+		rootPath:   rootPath,
+	}
+}
+
+// getIRODSPath returns an iRODS path of the name, the name cannot go above the root path
+func (bfs *Filesystem) getIRODSPath(name string) string {
+	return path.Join(bfs.rootPath, path.Clean("/"+name))
+}
+
+// Capabilities returns features supported
+func (bfs *Filesystem) Capabilities() x_billy.Capability {
+	return x_billy.DefaultCapabilities
+}
+
+// Create creates or truncates a file
+func (bfs *Filesystem) Create(filename string) (x_billy.File, error) {
+	return bfs.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Open opens a file for read
+func (bfs *Filesystem) Open(filename string) (x_billy.File, error) {
+	return bfs.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+// OpenFile opens a file, parent directories are created if the file is created
+func (bfs *Filesystem) OpenFile(filename string, flag int, perm os.FileMode) (x_billy.File, error) {
+	irodsPath := bfs.getIRODSPath(filename)
+
+	entry, err := bfs.filesystem.Stat(irodsPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) || flag&os.O_CREATE == 0 {
+			return nil, fs.ToOSError("open", filename, err)
+		}
+
+		err = bfs.filesystem.MakeDir(path.Dir(irodsPath), true)
+		if err != nil {
+			return nil, fs.ToOSError("open", filename, err)
+		}
+
+		handle, err := bfs.filesystem.CreateFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+		if err != nil {
+			return nil, fs.ToOSError("open", filename, err)
+		}
+		return newFile(filename, handle), nil
+	}
+
+	if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+		return nil, fs.ToOSError("open", filename, types.NewFileAlreadyExistError(irodsPath))
+	}
+
+	if entry.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: xerrors.Errorf("%s is a directory", irodsPath)}
+	}
+
+	handle, err := bfs.filesystem.OpenFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+	if err != nil {
+		return nil, fs.ToOSError("open", filename, err)
+	}
+	return newFile(filename, handle), nil
+}
+
+// Stat returns file info of a file or a directory
+func (bfs *Filesystem) Stat(filename string) (os.FileInfo, error) {
+	entry, err := bfs.filesystem.Stat(bfs.getIRODSPath(filename))
+	if err != nil {
+		return nil, fs.ToOSError("stat", filename, err)
+	}
+	return entry.ToFileInfo(), nil
+}
+
+// Rename renames a file or a directory to the exact new name, parent directories of the new name are created
+func (bfs *Filesystem) Rename(oldpath string, newpath string) error {
+	irodsSrcPath := bfs.getIRODSPath(oldpath)
+	irodsDestPath := bfs.getIRODSPath(newpath)
+
+	entry, err := bfs.filesystem.Stat(irodsSrcPath)
+	if err != nil {
+		return fs.ToOSError("rename", oldpath, err)
+	}
+
+	err = bfs.filesystem.MakeDir(path.Dir(irodsDestPath), true)
+	if err != nil {
+		return fs.ToOSError("rename", newpath, err)
+	}
+
+	if entry.IsDir() {
+		err = bfs.filesystem.RenameDirToDir(irodsSrcPath, irodsDestPath)
+	} else {
+		err = bfs.filesystem.RenameFileToFile(irodsSrcPath, irodsDestPath)
+	}
+	return fs.ToOSError("rename", oldpath, err)
+}
+
+// Remove removes a file or an empty directory
+func (bfs *Filesystem) Remove(filename string) error {
+	irodsPath := bfs.getIRODSPath(filename)
+
+	entry, err := bfs.filesystem.Stat(irodsPath)
+	if err != nil {
+		return fs.ToOSError("remove", filename, err)
+	}
+
+	if entry.IsDir() {
+		err = bfs.filesystem.RemoveDir(irodsPath, false, true)
+	} else {
+		err = bfs.filesystem.RemoveFile(irodsPath, true)
+	}
+	return fs.ToOSError("remove", filename, err)
+}
+
+// Join joins path elements
+func (bfs *Filesystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// TempFile creates a new file with a unique name starting with prefix in dir
+func (bfs *Filesystem) TempFile(dir string, prefix string) (x_billy.File, error) {
+	filename := path.Join(dir, prefix+xid.New().String())
+	return bfs.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+}
+
+// ReadDir returns file info of entries in a directory, sorted by name
+func (bfs *Filesystem) ReadDir(dirname string) ([]os.FileInfo, error) {
+	entries, err := bfs.filesystem.List(bfs.getIRODSPath(dirname))
+	if err != nil {
+		return nil, fs.ToOSError("readdir", dirname, err)
+	}
+
+	infos := make([]os.FileInfo, len(entries))
+	for idx, entry := range entries {
+		infos[idx] = entry.ToFileInfo()
+	}
+
+	sort.Slice(infos, func(i int, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+	return infos, nil
+}
+
+// MkdirAll creates a directory with its parents, it is not an error if the directory exists
+func (bfs *Filesystem) MkdirAll(filename string, perm os.FileMode) error {
+	err := bfs.filesystem.MakeDir(bfs.getIRODSPath(filename), true)
+	return fs.ToOSError("mkdir", filename, err)
+}
+
+// Lstat returns file info of a file or a directory, same as Stat since iRODS has no symlinks
+func (bfs *Filesystem) Lstat(filename string) (os.FileInfo, error) {
+	return bfs.Stat(filename)
+}
+
+// Symlink is not supported
+func (bfs *Filesystem) Symlink(target string, link string) error {
+	return &os.LinkError{Op: "symlink", Old: target, New: link, Err: x_billy.ErrNotSupported}
+}
+
+// Readlink is not supported
+func (bfs *Filesystem) Readlink(link string) (string, error) {
+	return "", &os.PathError{Op: "readlink", Path: link, Err: x_billy.ErrNotSupported}
+}
+
+// Chroot returns a new Filesystem rooted at the given path
+func (bfs *Filesystem) Chroot(p string) (x_billy.Filesystem, error) {
+	return NewFilesystem(bfs.filesystem, bfs.getIRODSPath(p)), nil
+}
+
+// Root returns the root path in iRODS
+func (bfs *Filesystem) Root() string {
+	return bfs.rootPath
+}
@@ -0,0 +1,113 @@
+package billy_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/billy"
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+
+	x_billy "github.com/go-git/go-billy/v5"
+	billy_util "github.com/go-git/go-billy/v5/util"
+)
+
+func TestBilly(t *testing.T) {
+	t.Run("test BillyFilesystem", testBillyFilesystem)
+}
+
+func testBillyFilesystem(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	var bfs x_billy.Filesystem = billy.NewFilesystem(filesystem, homedir)
+
+	// parent directories are created
+	err = billy_util.WriteFile(bfs, "repo/objects/ab/cdef", []byte("object"), 0644)
+	failError(t, err)
+
+	data, err := billy_util.ReadFile(bfs, "repo/objects/ab/cdef")
+	failError(t, err)
+	assert.Equal(t, "object", string(data))
+
+	// temp file and rename into a new directory
+	tempFile, err := bfs.TempFile("repo", "tmp_")
+	failError(t, err)
+	assert.True(t, strings.HasPrefix(tempFile.Name(), "repo/tmp_"))
+
+	_, err = tempFile.Write([]byte("packed"))
+	failError(t, err)
+	err = tempFile.Close()
+	failError(t, err)
+
+	err = bfs.Rename(tempFile.Name(), "repo/objects/pack/pack-1.pack")
+	failError(t, err)
+
+	infos, err := bfs.ReadDir("repo/objects")
+	failError(t, err)
+	assert.Len(t, infos, 2)
+	assert.Equal(t, "ab", infos[0].Name())
+	assert.Equal(t, "pack", infos[1].Name())
+
+	// chroot
+	chrooted, err := bfs.Chroot("repo/objects")
+	failError(t, err)
+	assert.Equal(t, homedir+"/repo/objects", chrooted.Root())
+
+	info, err := chrooted.Stat("pack/pack-1.pack")
+	failError(t, err)
+	assert.Equal(t, int64(6), info.Size())
+
+	_, err = chrooted.Stat("../../repo")
+	assert.True(t, os.IsNotExist(err))
+
+	// no symlinks
+	err = bfs.Symlink("repo/objects/ab/cdef", "link")
+	assert.ErrorIs(t, err, x_billy.ErrNotSupported)
+
+	info, err = bfs.Lstat("repo/objects/ab/cdef")
+	failError(t, err)
+	assert.False(t, info.IsDir())
+
+	_, err = bfs.Open("repo/objects")
+	assert.Error(t, err)
+
+	err = bfs.Remove("repo/objects/ab/cdef")
+	failError(t, err)
+
+	_, err = bfs.Stat("repo/objects/ab/cdef")
+	assert.True(t, os.IsNotExist(err))
+}
+
+// startMockServer starts a mock iRODS server with users rods and alice, as test/testcases does
+func startMockServer(t *testing.T) *mock.IRODSMockServer {
+	mockServer, err := mock.NewIRODSMockServer("mockzone", "rods", "rods_password")
+	failError(t, err)
+
+	err = mockServer.AddUser("alice", "alice_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	err = mockServer.Start()
+	failError(t, err)
+
+	return mockServer
+}
+
+func failError(t *testing.T, err error) {
+	if err != nil {
+		t.Errorf("%+v", err)
+		t.FailNow()
+	}
+}
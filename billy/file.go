@@ -0,0 +1,68 @@
+package billy
+
+import (
+	"github.com/cyverse/go-irodsclient/fs"
+)
+
+// File is an opened iRODS data object, implements billy.File
+type File struct {
+	handle *fs.FileHandle
+	name   string
+}
+
+func newFile(name string, handle *fs.FileHandle) *File {
+	return &File{
+		handle: handle,
+		name:   name,
+	}
+}
+
+// GetFileHandle returns the underlying file handle
+func (file *File) GetFileHandle() *fs.FileHandle {
+	return file.handle
+}
+
+// Name returns the name given to open the file
+func (file *File) Name() string {
+	return file.name
+}
+
+// Read reads the file
+func (file *File) Read(buffer []byte) (int, error) {
+	return file.handle.Read(buffer)
+}
+
+// ReadAt reads data from given offset
+func (file *File) ReadAt(buffer []byte, offset int64) (int, error) {
+	return file.handle.ReadAt(buffer, offset)
+}
+
+// Write writes the file
+func (file *File) Write(data []byte) (int, error) {
+	return file.handle.Write(data)
+}
+
+// Seek moves file pointer
+func (file *File) Seek(offset int64, whence int) (int64, error) {
+	return file.handle.Seek(offset, whence)
+}
+
+// Truncate truncates the file
+func (file *File) Truncate(size int64) error {
+	return file.handle.Truncate(size)
+}
+
+// Close closes the file
+func (file *File) Close() error {
+	return file.handle.Close()
+}
+
+// Lock acquires an exclusive iRODS lock on the data object, waits until the lock is available
+func (file *File) Lock() error {
+	return file.handle.LockDataObject(true)
+}
+
+// Unlock releases the iRODS lock on the data object
+func (file *File) Unlock() error {
+	return file.handle.UnlockDataObject()
+}
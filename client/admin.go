@@ -0,0 +1,274 @@
+package client
+
+import (
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// AdminAPI manages users, groups, quotas and resources, most operations require a rodsadmin account
+// zone is optional in all operations, client zone is used if empty
+type AdminAPI struct {
+	client *Client
+}
+
+func (api *AdminAPI) getZone(zone string) string {
+	if len(zone) == 0 {
+		return api.client.account.ClientZone
+	}
+	return zone
+}
+
+// audit records an operation on the target with the error it returns, called in defer with a pointer to the named error result
+func (api *AdminAPI) audit(operation string, target string, err *error) {
+	api.client.filesystem.RecordAudit(operation, target, "", *err)
+}
+
+// GetUser returns user info
+func (api *AdminAPI) GetUser(username string, zone string) (*types.IRODSUser, error) {
+	return api.client.filesystem.GetUser(username, api.getZone(zone))
+}
+
+// ListUsers lists all users
+func (api *AdminAPI) ListUsers() ([]*types.IRODSUser, error) {
+	return api.client.filesystem.ListUsers()
+}
+
+// GetGroup returns group info with its member count
+func (api *AdminAPI) GetGroup(group string) (*types.IRODSGroup, error) {
+	return api.client.filesystem.GetGroup(group)
+}
+
+// ListGroups lists all groups
+func (api *AdminAPI) ListGroups() ([]*types.IRODSUser, error) {
+	return api.client.filesystem.ListGroups()
+}
+
+// ListGroupUsers lists all users in a group
+func (api *AdminAPI) ListGroupUsers(group string) ([]*types.IRODSUser, error) {
+	return api.client.filesystem.ListGroupUsers(group)
+}
+
+// ListUserGroups lists all groups that a user belongs to
+func (api *AdminAPI) ListUserGroups(username string) ([]*types.IRODSUser, error) {
+	return api.client.filesystem.ListUserGroups(username)
+}
+
+// CreateUser creates a user
+func (api *AdminAPI) CreateUser(username string, zone string, userType types.IRODSUserType) (err error) {
+	defer api.audit("CreateUser", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.CreateUser(username, api.getZone(zone), userType)
+}
+
+// ChangeUserPassword changes the password of a user
+func (api *AdminAPI) ChangeUserPassword(username string, zone string, newPassword string) (err error) {
+	defer api.audit("ChangeUserPassword", username+"#"+api.getZone(zone), &err)
+
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ChangeUserPassword(conn, username, api.getZone(zone), newPassword)
+}
+
+// ChangeUserType changes the type of a user to rodsuser, groupadmin or rodsadmin
+func (api *AdminAPI) ChangeUserType(username string, zone string, newType types.IRODSUserType) (err error) {
+	defer api.audit("ChangeUserType", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.SetUserType(username, api.getZone(zone), newType)
+}
+
+// ChangeUserInfo changes the info of a user
+func (api *AdminAPI) ChangeUserInfo(username string, zone string, info string) (err error) {
+	defer api.audit("ChangeUserInfo", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.SetUserInfo(username, api.getZone(zone), info)
+}
+
+// DisableUser marks a user disabled in its info, iRODS does not block disabled users
+func (api *AdminAPI) DisableUser(username string, zone string) (err error) {
+	defer api.audit("DisableUser", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.DisableUser(username, api.getZone(zone))
+}
+
+// EnableUser clears the disabled mark of a user
+func (api *AdminAPI) EnableUser(username string, zone string) (err error) {
+	defer api.audit("EnableUser", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.EnableUser(username, api.getZone(zone))
+}
+
+// RemoveUser removes a user or a group
+func (api *AdminAPI) RemoveUser(username string, zone string) (err error) {
+	defer api.audit("RemoveUser", username+"#"+api.getZone(zone), &err)
+
+	return api.client.filesystem.RemoveUser(username, api.getZone(zone))
+}
+
+// CreateGroup creates a group
+func (api *AdminAPI) CreateGroup(group string) (err error) {
+	defer api.audit("CreateGroup", group, &err)
+
+	return api.client.filesystem.CreateGroup(group)
+}
+
+// AddGroupMember adds a user to a group
+func (api *AdminAPI) AddGroupMember(group string, username string, zone string) (err error) {
+	defer api.audit("AddGroupMember", group, &err)
+
+	return api.client.filesystem.AddGroupMember(group, username, api.getZone(zone))
+}
+
+// RemoveGroupMember removes a user from a group
+func (api *AdminAPI) RemoveGroupMember(group string, username string, zone string) (err error) {
+	defer api.audit("RemoveGroupMember", group, &err)
+
+	return api.client.filesystem.RemoveGroupMember(group, username, api.getZone(zone))
+}
+
+// ListUserResourceQuota lists all resource quotas of a user or a group
+func (api *AdminAPI) ListUserResourceQuota(username string) ([]*types.IRODSQuota, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ListUserResourceQuota(conn, username)
+}
+
+// GetUserGlobalQuota returns the global quota of a user or a group
+func (api *AdminAPI) GetUserGlobalQuota(username string) (*types.IRODSQuota, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.GetUserGlobalQuota(conn, username)
+}
+
+// ListUserQuotaUsage lists usages of a user or a group on resources counted for quota
+func (api *AdminAPI) ListUserQuotaUsage(username string) ([]*types.IRODSQuotaUsage, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ListUserQuotaUsage(conn, username)
+}
+
+// ListQuotaUsage lists usages of all users and groups on resources counted for quota, e.g., for reports
+// record the usages with their modify times periodically to show consumption over time
+func (api *AdminAPI) ListQuotaUsage() ([]*types.IRODSQuotaUsage, error) {
+	return api.ListUserQuotaUsage("")
+}
+
+// SetUserQuota sets quota of a user for a resource, "total" for global quota
+func (api *AdminAPI) SetUserQuota(username string, resource string, value string) (err error) {
+	defer api.audit("SetUserQuota", username, &err)
+
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.SetUserQuota(conn, username, resource, value)
+}
+
+// SetGroupQuota sets quota of a group for a resource, "total" for global quota
+func (api *AdminAPI) SetGroupQuota(group string, resource string, value string) (err error) {
+	defer api.audit("SetGroupQuota", group, &err)
+
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.SetGroupQuota(conn, group, resource, value)
+}
+
+// GetResource returns a resource
+func (api *AdminAPI) GetResource(resource string) (*types.IRODSResource, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.GetResource(conn, resource)
+}
+
+// GetAvailableSpace returns free space, capacity and status of a resource
+// default resource of the account is used if resource is empty
+func (api *AdminAPI) GetAvailableSpace(resource string) (*types.IRODSResourceSpace, error) {
+	return api.client.filesystem.GetAvailableSpace(resource)
+}
+
+// ListProcesses lists all processes on the server
+func (api *AdminAPI) ListProcesses() ([]*types.IRODSProcess, error) {
+	return api.client.filesystem.ListAllProcesses()
+}
+
+// ListSpecificQueries lists specific queries registered in the catalog
+func (api *AdminAPI) ListSpecificQueries() ([]*types.IRODSSpecificQuery, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ListSpecificQueries(conn)
+}
+
+// AddSpecificQuery registers a specific query by an alias, the query can be run with QueryAPI.SpecificQuery
+func (api *AdminAPI) AddSpecificQuery(alias string, sql string) (err error) {
+	defer api.audit("AddSpecificQuery", alias, &err)
+
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.AddSpecificQuery(conn, alias, sql)
+}
+
+// RemoveSpecificQuery removes a specific query registered by the alias
+func (api *AdminAPI) RemoveSpecificQuery(alias string) (err error) {
+	defer api.audit("RemoveSpecificQuery", alias, &err)
+
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.RemoveSpecificQuery(conn, alias)
+}
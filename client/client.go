@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// Client is a high-level iRODS client, owns a FileSystem and its sessions
+// sub-APIs share the connections of the FileSystem, so applications do not manage raw connections
+type Client struct {
+	account    *types.IRODSAccount
+	filesystem *fs.FileSystem
+	admin      *AdminAPI
+	query      *QueryAPI
+	tickets    *TicketAPI
+}
+
+// NewClient creates a new Client
+func NewClient(account *types.IRODSAccount, config *fs.FileSystemConfig) (*Client, error) {
+	filesystem, err := fs.NewFileSystem(account, config)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create file system: %w", err)
+	}
+
+	return newClient(account, filesystem), nil
+}
+
+// NewClientWithDefault creates a new Client with default configurations
+func NewClientWithDefault(account *types.IRODSAccount, applicationName string) (*Client, error) {
+	filesystem, err := fs.NewFileSystemWithDefault(account, applicationName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create file system: %w", err)
+	}
+
+	return newClient(account, filesystem), nil
+}
+
+// NewClientWithOptions creates a new Client with default configurations overridden by options
+func NewClientWithOptions(account *types.IRODSAccount, applicationName string, options ...fs.FileSystemConfigOption) (*Client, error) {
+	return NewClient(account, fs.NewFileSystemConfigWithOptions(applicationName, options...))
+}
+
+func newClient(account *types.IRODSAccount, filesystem *fs.FileSystem) *Client {
+	client := &Client{
+		account:    account,
+		filesystem: filesystem,
+	}
+
+	client.admin = &AdminAPI{client: client}
+	client.query = &QueryAPI{client: client}
+	client.tickets = &TicketAPI{client: client}
+
+	return client
+}
+
+// Release releases all resources
+func (client *Client) Release() {
+	client.filesystem.Release()
+}
+
+// Shutdown stops accepting new operations, waits for in-flight operations until ctx is done, then releases all resources
+func (client *Client) Shutdown(ctx context.Context) error {
+	return client.filesystem.Shutdown(ctx)
+}
+
+// GetAccount returns the account of the client
+func (client *Client) GetAccount() *types.IRODSAccount {
+	return client.account
+}
+
+// UpdateAccount makes the client authenticate with the account, e.g., after a password is rotated, without losing caches
+func (client *Client) UpdateAccount(account *types.IRODSAccount) error {
+	err := client.filesystem.UpdateAccount(account)
+	if err != nil {
+		return xerrors.Errorf("failed to update account: %w", err)
+	}
+
+	client.account = account
+	return nil
+}
+
+// Ping checks if the client can authenticate and reach the catalog
+func (client *Client) Ping() error {
+	return client.filesystem.Ping()
+}
+
+// AddAuditHandler adds a handler recording every mutating operation performed through the client, returns handler ID
+func (client *Client) AddAuditHandler(handler fs.AuditHandler) string {
+	return client.filesystem.AddAuditHandler(handler)
+}
+
+// RemoveAuditHandler removes an audit handler
+func (client *Client) RemoveAuditHandler(handlerID string) {
+	client.filesystem.RemoveAuditHandler(handlerID)
+}
+
+// FS returns the file system API
+func (client *Client) FS() *fs.FileSystem {
+	return client.filesystem
+}
+
+// Admin returns the administration API for users, groups, quotas and resources
+func (client *Client) Admin() *AdminAPI {
+	return client.admin
+}
+
+// Query returns the catalog query API
+func (client *Client) Query() *QueryAPI {
+	return client.query
+}
+
+// Tickets returns the ticket API
+func (client *Client) Tickets() *TicketAPI {
+	return client.tickets
+}
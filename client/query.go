@@ -0,0 +1,64 @@
+package client
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+)
+
+// QueryAPI runs catalog queries
+type QueryAPI struct {
+	client *Client
+}
+
+// GenQuery runs a GenQuery, returns rows of values in the order of selects
+// conditions are keyed by columns, e.g., "= 'alice'" or "like '/zone/home/%'"
+func (api *QueryAPI) GenQuery(selects []common.ICATColumnNumber, conditions map[common.ICATColumnNumber]string) ([][]string, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ExecuteGenQuery(conn, selects, conditions)
+}
+
+// SpecificQuery runs a specific query registered in the catalog by an alias, e.g., "ShowCollAcls"
+func (api *QueryAPI) SpecificQuery(sqlQuery string, args ...string) ([][]string, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ExecuteSpecificQuery(conn, sqlQuery, args)
+}
+
+// GenQueryWithZone runs a GenQuery against the catalog of the zone, e.g., a remote federated zone
+func (api *QueryAPI) GenQueryWithZone(zone string, selects []common.ICATColumnNumber, conditions map[common.ICATColumnNumber]string) ([][]string, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ExecuteGenQueryWithZone(conn, zone, selects, conditions)
+}
+
+// SpecificQueryWithZone runs a specific query against the catalog of the zone, e.g., a remote federated zone
+func (api *QueryAPI) SpecificQueryWithZone(zone string, sqlQuery string, args ...string) ([][]string, error) {
+	filesystem := api.client.filesystem
+
+	conn, err := filesystem.GetMetadataConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	return irods_fs.ExecuteSpecificQueryWithZone(conn, zone, sqlQuery, args)
+}
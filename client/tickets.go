@@ -0,0 +1,123 @@
+package client
+
+import (
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// TicketAPI manages tickets and their restrictions
+type TicketAPI struct {
+	client *Client
+}
+
+// Get returns ticket information
+func (api *TicketAPI) Get(ticketName string) (*types.IRODSTicket, error) {
+	return api.client.filesystem.GetTicket(ticketName)
+}
+
+// GetForAnonymousAccess returns ticket information for anonymous access
+func (api *TicketAPI) GetForAnonymousAccess(ticketName string) (*types.IRODSTicketForAnonymousAccess, error) {
+	return api.client.filesystem.GetTicketForAnonymousAccess(ticketName)
+}
+
+// List lists all available tickets
+func (api *TicketAPI) List() ([]*types.IRODSTicket, error) {
+	return api.client.filesystem.ListTickets()
+}
+
+// ListForPath lists tickets for the path, including use and write counts
+func (api *TicketAPI) ListForPath(path string) ([]*types.IRODSTicket, error) {
+	return api.client.filesystem.ListTicketsForPath(path)
+}
+
+// ListBasic lists all available tickets with basic information
+func (api *TicketAPI) ListBasic() ([]*types.IRODSTicket, error) {
+	return api.client.filesystem.ListTicketsBasic()
+}
+
+// GetRestrictions returns host, user and group restrictions of a ticket
+func (api *TicketAPI) GetRestrictions(ticketID int64) (*fs.IRODSTicketRestrictions, error) {
+	return api.client.filesystem.GetTicketRestrictions(ticketID)
+}
+
+// Create creates a new ticket for the path
+func (api *TicketAPI) Create(ticketName string, ticketType types.TicketType, path string) error {
+	return api.client.filesystem.CreateTicket(ticketName, ticketType, path)
+}
+
+// Delete deletes a ticket
+func (api *TicketAPI) Delete(ticketName string) error {
+	return api.client.filesystem.DeleteTicket(ticketName)
+}
+
+// ModifyUseLimit sets the use limit of a ticket
+func (api *TicketAPI) ModifyUseLimit(ticketName string, uses int64) error {
+	return api.client.filesystem.ModifyTicketUseLimit(ticketName, uses)
+}
+
+// ClearUseLimit clears the use limit of a ticket
+func (api *TicketAPI) ClearUseLimit(ticketName string) error {
+	return api.client.filesystem.ClearTicketUseLimit(ticketName)
+}
+
+// ModifyWriteFileLimit sets the write file limit of a ticket
+func (api *TicketAPI) ModifyWriteFileLimit(ticketName string, count int64) error {
+	return api.client.filesystem.ModifyTicketWriteFileLimit(ticketName, count)
+}
+
+// ClearWriteFileLimit clears the write file limit of a ticket
+func (api *TicketAPI) ClearWriteFileLimit(ticketName string) error {
+	return api.client.filesystem.ClearTicketWriteFileLimit(ticketName)
+}
+
+// ModifyWriteByteLimit sets the write byte limit of a ticket
+func (api *TicketAPI) ModifyWriteByteLimit(ticketName string, bytes int64) error {
+	return api.client.filesystem.ModifyTicketWriteByteLimit(ticketName, bytes)
+}
+
+// ClearWriteByteLimit clears the write byte limit of a ticket
+func (api *TicketAPI) ClearWriteByteLimit(ticketName string) error {
+	return api.client.filesystem.ClearTicketWriteByteLimit(ticketName)
+}
+
+// ModifyExpirationTime sets the expiration time of a ticket
+func (api *TicketAPI) ModifyExpirationTime(ticketName string, expirationTime time.Time) error {
+	return api.client.filesystem.ModifyTicketExpirationTime(ticketName, expirationTime)
+}
+
+// ClearExpirationTime clears the expiration time of a ticket
+func (api *TicketAPI) ClearExpirationTime(ticketName string) error {
+	return api.client.filesystem.ClearTicketExpirationTime(ticketName)
+}
+
+// AddAllowedUser allows a user to use a ticket
+func (api *TicketAPI) AddAllowedUser(ticketName string, username string) error {
+	return api.client.filesystem.AddTicketAllowedUser(ticketName, username)
+}
+
+// RemoveAllowedUser removes a user from users allowed to use a ticket
+func (api *TicketAPI) RemoveAllowedUser(ticketName string, username string) error {
+	return api.client.filesystem.RemoveTicketAllowedUser(ticketName, username)
+}
+
+// AddAllowedGroup allows a group to use a ticket
+func (api *TicketAPI) AddAllowedGroup(ticketName string, group string) error {
+	return api.client.filesystem.AddTicketAllowedGroup(ticketName, group)
+}
+
+// RemoveAllowedGroup removes a group from groups allowed to use a ticket
+func (api *TicketAPI) RemoveAllowedGroup(ticketName string, group string) error {
+	return api.client.filesystem.RemoveTicketAllowedGroup(ticketName, group)
+}
+
+// AddAllowedHost allows a host to use a ticket
+func (api *TicketAPI) AddAllowedHost(ticketName string, host string) error {
+	return api.client.filesystem.AddTicketAllowedHost(ticketName, host)
+}
+
+// RemoveAllowedHost removes a host from hosts allowed to use a ticket
+func (api *TicketAPI) RemoveAllowedHost(ticketName string, host string) error {
+	return api.client.filesystem.RemoveTicketAllowedHost(ticketName, host)
+}
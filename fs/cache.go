@@ -17,13 +17,25 @@ type MetadataCacheTimeoutSetting struct {
 	Inherit bool
 }
 
+// NegativeEntryCacheSetting defines negative entry cache timeout for path, overriding MetadataCacheTimeoutSetting
+// disable for paths where other clients create files often, e.g., landing zones, so they are seen at once
+type NegativeEntryCacheSetting struct {
+	Path    string
+	Timeout time.Duration
+	Inherit bool
+	// do not cache entries not found
+	Disable bool
+}
+
 // FileSystemCache manages filesystem caches
 type FileSystemCache struct {
 	cacheTimeout                          time.Duration
 	cleanupTimeout                        time.Duration
 	cacheTimeoutPaths                     []MetadataCacheTimeoutSetting
 	cacheTimeoutPathMap                   map[string]MetadataCacheTimeoutSetting
+	negativeEntryCacheSettingMap          map[string]NegativeEntryCacheSetting
 	invalidateParentEntryCacheImmediately bool
+	disabled                              bool // nothing is cached if disabled
 	entryCache                            *gocache.Cache
 	negativeEntryCache                    *gocache.Cache
 	dirCache                              *gocache.Cache
@@ -31,7 +43,9 @@ type FileSystemCache struct {
 	groupUsersCache                       *gocache.Cache
 	userGroupsCache                       *gocache.Cache
 	groupsCache                           *gocache.Cache
+	groupCache                            *gocache.Cache
 	usersCache                            *gocache.Cache
+	userCache                             *gocache.Cache
 	aclCache                              *gocache.Cache
 }
 
@@ -44,7 +58,9 @@ func NewFileSystemCache(cacheTimeout time.Duration, cleanup time.Duration, cache
 	groupUsersCache := gocache.New(cacheTimeout, cleanup)
 	userGroupsCache := gocache.New(cacheTimeout, cleanup)
 	groupsCache := gocache.New(cacheTimeout, cleanup)
+	groupCache := gocache.New(cacheTimeout, cleanup)
 	usersCache := gocache.New(cacheTimeout, cleanup)
+	userCache := gocache.New(cacheTimeout, cleanup)
 	aclCache := gocache.New(cacheTimeout, cleanup)
 
 	if cacheTimeoutSettings == nil {
@@ -62,6 +78,7 @@ func NewFileSystemCache(cacheTimeout time.Duration, cleanup time.Duration, cache
 		cleanupTimeout:                        cleanup,
 		cacheTimeoutPaths:                     cacheTimeoutSettings,
 		cacheTimeoutPathMap:                   cacheTimeoutSettingMap,
+		negativeEntryCacheSettingMap:          map[string]NegativeEntryCacheSetting{},
 		invalidateParentEntryCacheImmediately: invalidateParentEntryCacheImmediately,
 		entryCache:                            entryCache,
 		negativeEntryCache:                    negativeEntryCache,
@@ -70,7 +87,9 @@ func NewFileSystemCache(cacheTimeout time.Duration, cleanup time.Duration, cache
 		groupUsersCache:                       groupUsersCache,
 		userGroupsCache:                       userGroupsCache,
 		groupsCache:                           groupsCache,
+		groupCache:                            groupCache,
 		usersCache:                            usersCache,
+		userCache:                             userCache,
 		aclCache:                              aclCache,
 	}
 }
@@ -105,8 +124,63 @@ func (cache *FileSystemCache) getCacheTTLForPath(path string) time.Duration {
 	return 0
 }
 
+// setNegativeEntryCacheSettings sets negative entry cache timeouts for paths
+func (cache *FileSystemCache) setNegativeEntryCacheSettings(settings []NegativeEntryCacheSetting) {
+	// build a map for quick search
+	settingMap := map[string]NegativeEntryCacheSetting{}
+	for _, setting := range settings {
+		settingMap[setting.Path] = setting
+	}
+
+	cache.negativeEntryCacheSettingMap = settingMap
+}
+
+// getNegativeEntryCacheSettingForPath returns the negative entry cache setting of the path, nil if not set
+func (cache *FileSystemCache) getNegativeEntryCacheSettingForPath(path string) *NegativeEntryCacheSetting {
+	if len(cache.negativeEntryCacheSettingMap) == 0 {
+		// no data
+		return nil
+	}
+
+	// check map first
+	if setting, ok := cache.negativeEntryCacheSettingMap[path]; ok {
+		// exact match
+		return &setting
+	}
+
+	// check inherit
+	parentPaths := util.GetParentIRODSDirs(path)
+	for i := len(parentPaths) - 1; i >= 0; i-- {
+		if setting, ok := cache.negativeEntryCacheSettingMap[parentPaths[i]]; ok {
+			// parent match
+			if setting.Inherit {
+				return &setting
+			}
+		}
+	}
+
+	return nil
+}
+
+// getNegativeEntryCacheTTLForPath returns the negative entry cache timeout of the path, false if disabled for the path
+func (cache *FileSystemCache) getNegativeEntryCacheTTLForPath(path string) (time.Duration, bool) {
+	setting := cache.getNegativeEntryCacheSettingForPath(path)
+	if setting == nil {
+		return cache.getCacheTTLForPath(path), true
+	}
+
+	if setting.Disable {
+		return 0, false
+	}
+	return setting.Timeout, true
+}
+
 // AddEntryCache adds an entry cache
 func (cache *FileSystemCache) AddEntryCache(entry *Entry) {
+	if cache.disabled {
+		return
+	}
+
 	ttl := cache.getCacheTTLForPath(entry.Path)
 	cache.entryCache.Set(entry.Path, entry, ttl)
 }
@@ -141,7 +215,15 @@ func (cache *FileSystemCache) ClearEntryCache() {
 
 // AddNegativeEntryCache adds a negative entry cache
 func (cache *FileSystemCache) AddNegativeEntryCache(path string) {
-	ttl := cache.getCacheTTLForPath(path)
+	if cache.disabled {
+		return
+	}
+
+	ttl, ok := cache.getNegativeEntryCacheTTLForPath(path)
+	if !ok {
+		return
+	}
+
 	cache.negativeEntryCache.Set(path, true, ttl)
 }
 
@@ -182,6 +264,10 @@ func (cache *FileSystemCache) ClearNegativeEntryCache() {
 
 // AddDirCache adds a dir cache
 func (cache *FileSystemCache) AddDirCache(path string, entries []string) {
+	if cache.disabled {
+		return
+	}
+
 	ttl := cache.getCacheTTLForPath(path)
 	cache.dirCache.Set(path, entries, ttl)
 }
@@ -209,6 +295,10 @@ func (cache *FileSystemCache) ClearDirCache() {
 
 // AddMetadataCache adds a metadata cache
 func (cache *FileSystemCache) AddMetadataCache(path string, metas []*types.IRODSMeta) {
+	if cache.disabled {
+		return
+	}
+
 	ttl := cache.getCacheTTLForPath(path)
 	cache.metadataCache.Set(path, metas, ttl)
 }
@@ -236,6 +326,10 @@ func (cache *FileSystemCache) ClearMetadataCache() {
 
 // AddGroupUsersCache adds a group user (users in a group) cache
 func (cache *FileSystemCache) AddGroupUsersCache(group string, users []*types.IRODSUser) {
+	if cache.disabled {
+		return
+	}
+
 	cache.groupUsersCache.Set(group, users, 0)
 }
 
@@ -257,6 +351,10 @@ func (cache *FileSystemCache) GetGroupUsersCache(group string) []*types.IRODSUse
 
 // AddUserGroupsCache adds a user's groups (groups that a user belongs to) cache
 func (cache *FileSystemCache) AddUserGroupsCache(user string, groups []*types.IRODSUser) {
+	if cache.disabled {
+		return
+	}
+
 	cache.userGroupsCache.Set(user, groups, 0)
 }
 
@@ -278,6 +376,10 @@ func (cache *FileSystemCache) GetUserGroupsCache(user string) []*types.IRODSUser
 
 // AddGroupsCache adds a groups cache (cache of a list of all groups)
 func (cache *FileSystemCache) AddGroupsCache(groups []*types.IRODSUser) {
+	if cache.disabled {
+		return
+	}
+
 	cache.groupsCache.Set("groups", groups, 0)
 }
 
@@ -297,8 +399,42 @@ func (cache *FileSystemCache) GetGroupsCache() []*types.IRODSUser {
 	return nil
 }
 
+// AddGroupCache adds a group cache (cache of a group information)
+func (cache *FileSystemCache) AddGroupCache(group *types.IRODSGroup) {
+	if cache.disabled {
+		return
+	}
+
+	cache.groupCache.Set(group.Name, group, 0)
+}
+
+// RemoveGroupCache removes a group cache (cache of a group information)
+func (cache *FileSystemCache) RemoveGroupCache(group string) {
+	cache.groupCache.Delete(group)
+}
+
+// GetGroupCache retrives a group cache (cache of a group information)
+func (cache *FileSystemCache) GetGroupCache(group string) *types.IRODSGroup {
+	irodsGroup, exist := cache.groupCache.Get(group)
+	if exist {
+		if g, ok := irodsGroup.(*types.IRODSGroup); ok {
+			return g
+		}
+	}
+	return nil
+}
+
+// ClearGroupCache clears all group caches
+func (cache *FileSystemCache) ClearGroupCache() {
+	cache.groupCache.Flush()
+}
+
 // AddUsersCache adds a users cache (cache of a list of all users)
 func (cache *FileSystemCache) AddUsersCache(users []*types.IRODSUser) {
+	if cache.disabled {
+		return
+	}
+
 	cache.usersCache.Set("users", users, 0)
 }
 
@@ -318,14 +454,76 @@ func (cache *FileSystemCache) GetUsersCache() []*types.IRODSUser {
 	return nil
 }
 
+func (cache *FileSystemCache) getUserCacheKey(username string, zone string) string {
+	return fmt.Sprintf("%s#%s", username, zone)
+}
+
+// AddUserCache adds a user cache (cache of a user information)
+func (cache *FileSystemCache) AddUserCache(user *types.IRODSUser) {
+	if cache.disabled {
+		return
+	}
+
+	cache.userCache.Set(cache.getUserCacheKey(user.Name, user.Zone), user, 0)
+}
+
+// RemoveUserCache removes a user cache (cache of a user information)
+func (cache *FileSystemCache) RemoveUserCache(username string, zone string) {
+	cache.userCache.Delete(cache.getUserCacheKey(username, zone))
+}
+
+// GetUserCache retrives a user cache (cache of a user information)
+func (cache *FileSystemCache) GetUserCache(username string, zone string) *types.IRODSUser {
+	user, exist := cache.userCache.Get(cache.getUserCacheKey(username, zone))
+	if exist {
+		if irodsUser, ok := user.(*types.IRODSUser); ok {
+			return irodsUser
+		}
+	}
+	return nil
+}
+
+// ClearUserCache clears all user caches
+func (cache *FileSystemCache) ClearUserCache() {
+	cache.userCache.Flush()
+}
+
+// ClearGroupUsersCache clears all group user (users in a group) caches
+func (cache *FileSystemCache) ClearGroupUsersCache() {
+	cache.groupUsersCache.Flush()
+}
+
+// ClearUserGroupsCache clears all user's groups (groups that a user belongs to) caches
+func (cache *FileSystemCache) ClearUserGroupsCache() {
+	cache.userGroupsCache.Flush()
+}
+
+// ClearUserGroupCache clears all user and group caches, including group members and user groups
+func (cache *FileSystemCache) ClearUserGroupCache() {
+	cache.userCache.Flush()
+	cache.usersCache.Flush()
+	cache.groupsCache.Flush()
+	cache.groupCache.Flush()
+	cache.groupUsersCache.Flush()
+	cache.userGroupsCache.Flush()
+}
+
 // AddACLsCache adds a ACLs cache
 func (cache *FileSystemCache) AddACLsCache(path string, accesses []*types.IRODSAccess) {
+	if cache.disabled {
+		return
+	}
+
 	ttl := cache.getCacheTTLForPath(path)
 	cache.aclCache.Set(path, accesses, ttl)
 }
 
 // AddACLsCacheMulti adds multiple ACLs caches
 func (cache *FileSystemCache) AddACLsCacheMulti(accesses []*types.IRODSAccess) {
+	if cache.disabled {
+		return
+	}
+
 	m := map[string][]*types.IRODSAccess{}
 
 	for _, access := range accesses {
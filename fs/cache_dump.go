@@ -0,0 +1,76 @@
+package fs
+
+import (
+	"sort"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// CacheDumpItem is a cached item in a cache dump
+type CacheDumpItem struct {
+	Key string `json:"key"`
+	// how long ago the item was cached, empty if the item never expires
+	Age string `json:"age,omitempty"`
+	// how long until the item expires, empty if the item never expires
+	ExpiresIn string      `json:"expires_in,omitempty"`
+	Value     interface{} `json:"value"`
+}
+
+// CacheDump is a snapshot of file system caches, items are sorted by key
+type CacheDump struct {
+	Time            time.Time        `json:"time"`
+	Entries         []*CacheDumpItem `json:"entries"`
+	Dirs            []*CacheDumpItem `json:"dirs"`
+	NegativeEntries []*CacheDumpItem `json:"negative_entries"`
+	Metadata        []*CacheDumpItem `json:"metadata"`
+	ACLs            []*CacheDumpItem `json:"acls"`
+}
+
+// dump returns a snapshot of caches of paths
+func (cache *FileSystemCache) dump() *CacheDump {
+	now := time.Now()
+
+	return &CacheDump{
+		Time:    now,
+		Entries: cache.dumpItems(cache.entryCache, now, cache.getCacheTTLForPath),
+		Dirs:    cache.dumpItems(cache.dirCache, now, cache.getCacheTTLForPath),
+		NegativeEntries: cache.dumpItems(cache.negativeEntryCache, now, func(path string) time.Duration {
+			ttl, _ := cache.getNegativeEntryCacheTTLForPath(path)
+			return ttl
+		}),
+		Metadata: cache.dumpItems(cache.metadataCache, now, cache.getCacheTTLForPath),
+		ACLs:     cache.dumpItems(cache.aclCache, now, cache.getCacheTTLForPath),
+	}
+}
+
+// dumpItems returns items of the cache not expired, ttlFunc returns the timeout the item of the key was cached with
+// ages are derived from expiration times and timeouts
+func (cache *FileSystemCache) dumpItems(c *gocache.Cache, now time.Time, ttlFunc func(key string) time.Duration) []*CacheDumpItem {
+	items := []*CacheDumpItem{}
+	for key, item := range c.Items() {
+		dumpItem := &CacheDumpItem{
+			Key:   key,
+			Value: item.Object,
+		}
+
+		if item.Expiration > 0 {
+			expiresIn := time.Unix(0, item.Expiration).Sub(now)
+
+			ttl := ttlFunc(key)
+			if ttl == 0 {
+				ttl = cache.cacheTimeout
+			}
+
+			dumpItem.ExpiresIn = expiresIn.String()
+			dumpItem.Age = (ttl - expiresIn).String()
+		}
+
+		items = append(items, dumpItem)
+	}
+
+	sort.Slice(items, func(i int, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
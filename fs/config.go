@@ -1,6 +1,11 @@
 package fs
 
-import "time"
+import (
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
 
 const (
 	// FileSystemConnectionErrorTimeoutDefault is a default timeout value of connection error
@@ -11,7 +16,7 @@ const (
 	FileSystemConnectionMaxMin = 5
 	// FileSystemConnectionMaxDefault is a default number of connection max value
 	FileSystemConnectionMaxDefault = 10
-	// FileSystemConnectionMetaDefault is a default number of metadata operation connection
+	// FileSystemConnectionMetaDefault is a default number of metadata operation connection, FileSystemConnectionMaxMin at least
 	FileSystemConnectionMetaDefault = 2
 	// FileSystemConnectionLifespanDefault is a default lifespan of a connection
 	FileSystemConnectionLifespanDefault = 1 * time.Hour
@@ -34,6 +39,8 @@ type FileSystemConfig struct {
 	CacheTimeout           time.Duration
 	CacheCleanupTime       time.Duration
 	CacheTimeoutSettings   []MetadataCacheTimeoutSetting
+	// negative entry cache timeouts for paths, overriding CacheTimeoutSettings for entries not found
+	NegativeEntryCacheSettings []NegativeEntryCacheSetting
 	// for mysql iCAT backend, this should be true.
 	// for postgresql iCAT backend, this can be false.
 	StartNewTransaction bool
@@ -41,6 +48,68 @@ type FileSystemConfig struct {
 	// at subdir/file creation/deletion
 	// turn to false to allow short cache inconsistency
 	InvalidateParentEntryCacheImmediately bool
+	// normalize unicode characters in paths given to the file system
+	// set to NFC to avoid duplicate-looking entries uploaded from macOS clients
+	UnicodeNormalization util.UnicodeNormalizationForm
+	// send heartbeats on idle connections and held connections not in use at the interval, 0 disables keepalive
+	// set shorter than server-side idle timeout to avoid broken connections after idle periods
+	ConnectionKeepaliveInterval time.Duration
+	// keep previous versions of files being overwritten in the versions dir next to them
+	// applies to creating files, opening files with truncation, and overwriting uploads, copies and renames
+	Versioning bool
+	// disable all metadata caches, every operation reads the catalog
+	DisableCache bool
+	// record every mutating operation, more handlers can be added to the file system
+	AuditHandler AuditHandler
+	// limit requests of all connections to the requests per second, 0 for no limit
+	// use to run bulk jobs without overwhelming a shared catalog provider
+	RequestRateLimit float64
+	// max number of requests allowed at once under the rate limit, 1 if less than 1
+	RequestRateBurst int
+	// max number of data connections used by all transfers of the file system at the same time, 0 for no limit
+	// parallel transfers use fewer tasks when other transfers are running
+	TransferConcurrencyMax int
+	// size of data read or written in a request during transfers, the default is used if 0
+	// larger sizes need fewer round trips on high latency networks
+	TransferBufferSize int
+	// min length of data transferred by a task of parallel transfers, the default is used if 0
+	// decides the number of tasks when it is not given
+	TransferBlockSize int64
+	// number of TLS sessions kept for resumption, the default is used if 0, TLS sessions are not resumed if negative
+	// resuming sessions cuts setup time of SSL connections, e.g., for parallel transfers
+	TLSSessionCacheSize int
+	// defer connecting to the server until the first operation, so the file system can be created while the server is down
+	// connection errors are returned by the first operation
+	LazyConnection bool
+	// connections for metadata operations, e.g., stat and list, are pooled apart from connections for data transfers
+	// so heavy transfers can't starve them, settings above are for data transfers
+	// max number of metadata connections, FileSystemConnectionMetaDefault if 0, FileSystemConnectionMaxMin at least
+	MetadataConnectionMax int
+	// settings of metadata connections, the settings of data transfer connections are used if 0
+	MetadataConnectionInitNumber  int
+	MetadataConnectionLifespan    time.Duration
+	MetadataOperationTimeout      time.Duration
+	MetadataConnectionIdleTimeout time.Duration
+	// reopen files with new connections when connections of file handles break, e.g., by server restarts, and retry the failed I/O
+	// writes of files opened in append modes are not retried as they may have been applied
+	ReopenFileHandles bool
+	// open file handles not used for the timeout release their connections and bind connections again on next use, disabled if 0
+	// when all io connections are in use, the least recently used handle releases its connection for a file being opened or used
+	// use to keep many mostly idle files open, e.g., for FUSE, without holding as many connections
+	FileHandleIdleTimeout time.Duration
+	// queue operations waiting for connections by priority instead of sharing connections in use once all are in use
+	// stat and list go first, parallel operations, e.g., transfers and parallel listing, go last
+	OperationScheduling bool
+	// number of connections of each pool left for stat and list when OperationScheduling is set
+	InteractiveConnectionReserve int
+	// open files for read from a good replica on a resource that is up and not an archive, e.g., a tape
+	// instead of letting the server pick one, the server picks one if no replica is better than the others
+	SelectReadReplica bool
+	// keep retrying to connect up to ServerUnavailableRetryTimeout while the server refuses connections or is down, e.g., in maintenance
+	// operations fail right away if not positive
+	ServerUnavailableRetryTimeout time.Duration
+	// interval between retries while the server is unavailable, session.IRODSSessionServerUnavailableRetryIntervalDefault if not positive
+	ServerUnavailableRetryInterval time.Duration
 }
 
 // NewFileSystemConfig create a FileSystemConfig
@@ -83,5 +152,328 @@ func NewFileSystemConfigWithDefault(applicationName string) *FileSystemConfig {
 		CacheCleanupTime:                      FileSystemTimeoutDefault,
 		StartNewTransaction:                   true,
 		InvalidateParentEntryCacheImmediately: true,
+		UnicodeNormalization:                  util.UnicodeNormalizationNone,
+	}
+}
+
+// getRequestRateLimiter creates a limiter of the request rate, returns nil if no limit is set
+func (config *FileSystemConfig) getRequestRateLimiter() *util.RateLimiter {
+	if config.RequestRateLimit <= 0 {
+		return nil
+	}
+	return util.NewRateLimiter(config.RequestRateLimit, config.RequestRateBurst)
+}
+
+// getIOSessionConfig creates a session config for data transfer connections
+func (config *FileSystemConfig) getIOSessionConfig(requestRateLimiter *util.RateLimiter) *session.IRODSSessionConfig {
+	sessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	sessionConfig.ConnectionKeepaliveInterval = config.ConnectionKeepaliveInterval
+	sessionConfig.RequestRateLimiter = requestRateLimiter
+	sessionConfig.TransferBufferSize = config.TransferBufferSize
+	sessionConfig.TransferBlockSize = config.TransferBlockSize
+	sessionConfig.TLSSessionCacheSize = config.TLSSessionCacheSize
+	sessionConfig.LazyConnection = config.LazyConnection
+	sessionConfig.OperationScheduling = config.OperationScheduling
+	sessionConfig.InteractiveConnectionReserve = config.InteractiveConnectionReserve
+	sessionConfig.ServerUnavailableRetryTimeout = config.ServerUnavailableRetryTimeout
+	sessionConfig.ServerUnavailableRetryInterval = config.ServerUnavailableRetryInterval
+	return sessionConfig
+}
+
+// getMetadataSessionConfig creates a session config for metadata connections
+func (config *FileSystemConfig) getMetadataSessionConfig(requestRateLimiter *util.RateLimiter) *session.IRODSSessionConfig {
+	connectionMax := config.MetadataConnectionMax
+	if connectionMax <= 0 {
+		connectionMax = FileSystemConnectionMetaDefault
+	}
+
+	initNumber := config.MetadataConnectionInitNumber
+	if initNumber <= 0 {
+		initNumber = config.ConnectionInitNumber
+	}
+
+	lifespan := config.MetadataConnectionLifespan
+	if lifespan <= 0 {
+		lifespan = config.ConnectionLifespan
+	}
+
+	operationTimeout := config.MetadataOperationTimeout
+	if operationTimeout <= 0 {
+		operationTimeout = config.OperationTimeout
+	}
+
+	idleTimeout := config.MetadataConnectionIdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = config.ConnectionIdleTimeout
+	}
+
+	sessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, initNumber, lifespan, operationTimeout, idleTimeout, connectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	sessionConfig.ConnectionKeepaliveInterval = config.ConnectionKeepaliveInterval
+	sessionConfig.RequestRateLimiter = requestRateLimiter
+	sessionConfig.TLSSessionCacheSize = config.TLSSessionCacheSize
+	sessionConfig.LazyConnection = config.LazyConnection
+	sessionConfig.OperationScheduling = config.OperationScheduling
+	sessionConfig.InteractiveConnectionReserve = config.InteractiveConnectionReserve
+	sessionConfig.ServerUnavailableRetryTimeout = config.ServerUnavailableRetryTimeout
+	sessionConfig.ServerUnavailableRetryInterval = config.ServerUnavailableRetryInterval
+	return sessionConfig
+}
+
+// FileSystemConfigOption sets an option of FileSystemConfig
+type FileSystemConfigOption func(config *FileSystemConfig)
+
+// NewFileSystemConfigWithOptions create a FileSystemConfig with default settings overridden by options
+func NewFileSystemConfigWithOptions(applicationName string, options ...FileSystemConfigOption) *FileSystemConfig {
+	config := NewFileSystemConfigWithDefault(applicationName)
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithConnectionErrorTimeout sets how long to retry connecting after errors
+func WithConnectionErrorTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ConnectionErrorTimeout = timeout
+	}
+}
+
+// WithConnectionInitNumber sets the number of connections to create in advance
+func WithConnectionInitNumber(number int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ConnectionInitNumber = number
+	}
+}
+
+// WithConnectionLifespan sets the lifespan of a connection
+func WithConnectionLifespan(lifespan time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ConnectionLifespan = lifespan
+	}
+}
+
+// WithOperationTimeout sets the timeout of an operation
+func WithOperationTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.OperationTimeout = timeout
+	}
+}
+
+// WithConnectionIdleTimeout sets how long an idle connection is kept
+func WithConnectionIdleTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ConnectionIdleTimeout = timeout
+	}
+}
+
+// WithConnectionMax sets the max number of connections for IO, FileSystemConnectionMaxMin at least
+func WithConnectionMax(connectionMax int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		if connectionMax < FileSystemConnectionMaxMin {
+			connectionMax = FileSystemConnectionMaxMin
+		}
+		config.ConnectionMax = connectionMax
+	}
+}
+
+// WithTCPBufferSize sets the tcp buffer size of connections
+func WithTCPBufferSize(size int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.TCPBufferSize = size
+	}
+}
+
+// WithConnectionKeepaliveInterval sets the interval to send heartbeats on connections, 0 disables keepalive
+func WithConnectionKeepaliveInterval(interval time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ConnectionKeepaliveInterval = interval
+	}
+}
+
+// WithStartNewTransaction sets whether to start a new transaction, required for mysql iCAT backend
+func WithStartNewTransaction(startNewTransaction bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.StartNewTransaction = startNewTransaction
+	}
+}
+
+// WithCacheTimeout sets the default timeout of metadata caches
+func WithCacheTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.CacheTimeout = timeout
+	}
+}
+
+// WithCacheCleanupTime sets the interval to clean up expired caches
+func WithCacheCleanupTime(cleanupTime time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.CacheCleanupTime = cleanupTime
+	}
+}
+
+// WithCacheTimeoutSettings sets cache timeouts for paths
+func WithCacheTimeoutSettings(settings []MetadataCacheTimeoutSetting) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.CacheTimeoutSettings = settings
+	}
+}
+
+// WithNegativeEntryCacheSettings sets negative entry cache timeouts for paths, or disables negative entry caches for them
+func WithNegativeEntryCacheSettings(settings []NegativeEntryCacheSetting) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.NegativeEntryCacheSettings = settings
+	}
+}
+
+// WithNoCache disables all metadata caches
+func WithNoCache() FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.DisableCache = true
+	}
+}
+
+// WithInvalidateParentEntryCacheImmediately sets whether to invalidate parent dir's entry cache at subdir/file creation/deletion
+func WithInvalidateParentEntryCacheImmediately(invalidate bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.InvalidateParentEntryCacheImmediately = invalidate
+	}
+}
+
+// WithUnicodeNormalization sets the unicode normalization form of paths
+func WithUnicodeNormalization(form util.UnicodeNormalizationForm) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.UnicodeNormalization = form
+	}
+}
+
+// WithVersioning sets whether to keep previous versions of files being overwritten
+func WithVersioning(versioning bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.Versioning = versioning
+	}
+}
+
+// WithAuditHandler sets the handler recording every mutating operation
+func WithAuditHandler(handler AuditHandler) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.AuditHandler = handler
+	}
+}
+
+// WithRequestRateLimit limits requests to requestsPerSecond with bursts up to burst requests, 0 for no limit
+func WithRequestRateLimit(requestsPerSecond float64, burst int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.RequestRateLimit = requestsPerSecond
+		config.RequestRateBurst = burst
+	}
+}
+
+// WithTransferConcurrencyMax limits data connections used by all transfers at the same time, 0 for no limit
+func WithTransferConcurrencyMax(max int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.TransferConcurrencyMax = max
+	}
+}
+
+// WithTransferBufferSize sets the size of data read or written in a request during transfers
+func WithTransferBufferSize(size int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.TransferBufferSize = size
+	}
+}
+
+// WithTransferBlockSize sets the min length of data transferred by a task of parallel transfers
+func WithTransferBlockSize(size int64) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.TransferBlockSize = size
+	}
+}
+
+// WithTLSSessionCacheSize sets the number of TLS sessions kept for resumption, negative disables resumption
+func WithTLSSessionCacheSize(size int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.TLSSessionCacheSize = size
+	}
+}
+
+// WithLazyConnection sets whether to defer connecting to the server until the first operation
+func WithLazyConnection(lazy bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.LazyConnection = lazy
+	}
+}
+
+// WithOperationScheduling queues operations waiting for connections by priority, interactiveReserve connections are left for stat and list
+func WithOperationScheduling(interactiveReserve int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.OperationScheduling = true
+		config.InteractiveConnectionReserve = interactiveReserve
+	}
+}
+
+// WithMetadataConnectionMax sets the max number of connections for metadata operations, FileSystemConnectionMaxMin at least
+func WithMetadataConnectionMax(connectionMax int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		if connectionMax < FileSystemConnectionMaxMin {
+			connectionMax = FileSystemConnectionMaxMin
+		}
+		config.MetadataConnectionMax = connectionMax
+	}
+}
+
+// WithMetadataConnectionInitNumber sets the number of connections for metadata operations to create in advance
+func WithMetadataConnectionInitNumber(number int) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.MetadataConnectionInitNumber = number
+	}
+}
+
+// WithMetadataConnectionLifespan sets the lifespan of a connection for metadata operations
+func WithMetadataConnectionLifespan(lifespan time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.MetadataConnectionLifespan = lifespan
+	}
+}
+
+// WithMetadataOperationTimeout sets the timeout of a metadata operation
+func WithMetadataOperationTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.MetadataOperationTimeout = timeout
+	}
+}
+
+// WithMetadataConnectionIdleTimeout sets how long an idle connection for metadata operations is kept
+func WithMetadataConnectionIdleTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.MetadataConnectionIdleTimeout = timeout
+	}
+}
+
+// WithReopenFileHandles sets whether file handles are reopened transparently when their connections break
+func WithReopenFileHandles(reopen bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ReopenFileHandles = reopen
+	}
+}
+
+// WithFileHandleIdleTimeout sets the timeout after which idle file handles release their connections
+func WithFileHandleIdleTimeout(timeout time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.FileHandleIdleTimeout = timeout
+	}
+}
+
+// WithSelectReadReplica sets whether files opened for read select replicas on online resources
+func WithSelectReadReplica(selectReplica bool) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.SelectReadReplica = selectReplica
+	}
+}
+
+// WithServerUnavailableRetry keeps operations retrying to connect up to timeout at the interval while the server is unavailable, e.g., in maintenance
+func WithServerUnavailableRetry(timeout time.Duration, interval time.Duration) FileSystemConfigOption {
+	return func(config *FileSystemConfig) {
+		config.ServerUnavailableRetryTimeout = timeout
+		config.ServerUnavailableRetryInterval = interval
 	}
 }
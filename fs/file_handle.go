@@ -3,13 +3,29 @@ package fs
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
 )
 
+// FileCloseResult is the state of the data object in the catalog after a file handle is closed
+type FileCloseResult struct {
+	Path string
+	Size int64
+	// ReplicaNumber is the replica the handle opened, or the master replica if unknown
+	ReplicaNumber     int64
+	ResourceHierarchy string
+	// Checksum is nil if the checksum of the replica is not computed
+	Checksum   *types.IRODSChecksum
+	ModifyTime time.Time
+}
+
 // FileHandle is a handle for a file opened
 type FileHandle struct {
 	id                  string
@@ -20,6 +36,7 @@ type FileHandle struct {
 	entry               *Entry
 	offset              int64
 	openMode            types.FileOpenMode
+	lastAccess          time.Time
 	mutex               sync.Mutex
 }
 
@@ -86,6 +103,35 @@ func (handle *FileHandle) Close() error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	return handle.close()
+}
+
+// CloseWithResult closes the file and returns the state of the data object in the catalog after close
+// callers do not need to stat the file after close, which may race the cache
+func (handle *FileHandle) CloseWithResult() (*FileCloseResult, error) {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	// the replica opened is known only while the file is open
+	replicaNumber := int64(-1)
+	resourceHierarchy := ""
+	if handle.connection != nil && handle.connection.IsConnected() {
+		info, err := irods_fs.GetFileDescriptorInfo(handle.connection, handle.irodsFileHandle)
+		if err == nil {
+			replicaNumber = info.ReplicaNumber
+			resourceHierarchy = info.ResourceHierarchy
+		}
+	}
+
+	err := handle.close()
+	if err != nil {
+		return nil, err
+	}
+
+	return handle.filesystem.getFileCloseResult(handle.entry.Path, replicaNumber, resourceHierarchy)
+}
+
+func (handle *FileHandle) close() error {
 	if handle.irodsFileLockHandle != nil {
 		// unlock if locked
 		err := irods_fs.UnlockDataObject(handle.connection, handle.irodsFileLockHandle)
@@ -96,9 +142,8 @@ func (handle *FileHandle) Close() error {
 		handle.irodsFileLockHandle = nil
 	}
 
-	defer handle.filesystem.ioSession.ReturnConnection(handle.connection)
-
-	err := irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
+	// parked handles have no file to close
+	err := handle.releaseConnection()
 	handle.filesystem.fileHandleMap.Remove(handle.id)
 
 	if handle.IsWriteMode() {
@@ -114,7 +159,12 @@ func (handle *FileHandle) Seek(offset int64, whence int) (int64, error) {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
-	newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.Whence(whence))
+	newOffset := int64(0)
+	err := handle.runIO(false, func() error {
+		var seekErr error
+		newOffset, seekErr = irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.Whence(whence))
+		return seekErr
+	})
 	if err != nil {
 		return newOffset, err
 	}
@@ -128,7 +178,9 @@ func (handle *FileHandle) Truncate(size int64) error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
-	err := irods_fs.TruncateDataObjectHandle(handle.connection, handle.irodsFileHandle, size)
+	err := handle.runIO(false, func() error {
+		return irods_fs.TruncateDataObjectHandle(handle.connection, handle.irodsFileHandle, size)
+	})
 	if err != nil {
 		return err
 	}
@@ -145,7 +197,12 @@ func (handle *FileHandle) Read(buffer []byte) (int, error) {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+	readLen := 0
+	err := handle.runIO(false, func() error {
+		var readErr error
+		readLen, readErr = irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+		return readErr
+	})
 	if readLen > 0 {
 		handle.offset += int64(readLen)
 	}
@@ -163,20 +220,17 @@ func (handle *FileHandle) ReadAt(buffer []byte, offset int64) (int, error) {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	if handle.offset != offset {
-		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
-		if err != nil {
-			return 0, err
+	readLen := 0
+	err := handle.runIO(false, func() error {
+		seekErr := handle.seekTo(offset)
+		if seekErr != nil {
+			return seekErr
 		}
 
-		handle.offset = newOffset
-
-		if newOffset != offset {
-			return 0, xerrors.Errorf("failed to seek to %d", offset)
-		}
-	}
-
-	readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+		var readErr error
+		readLen, readErr = irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+		return readErr
+	})
 	if readLen > 0 {
 		handle.offset += int64(readLen)
 	}
@@ -194,7 +248,9 @@ func (handle *FileHandle) Write(data []byte) (int, error) {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	err := irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+	err := handle.runIO(true, func() error {
+		return irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -218,20 +274,14 @@ func (handle *FileHandle) WriteAt(data []byte, offset int64) (int, error) {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	if handle.offset != offset {
-		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
-		if err != nil {
-			return 0, err
-		}
-
-		handle.offset = newOffset
-
-		if newOffset != offset {
-			return 0, xerrors.Errorf("failed to seek to %d", offset)
+	err := handle.runIO(true, func() error {
+		seekErr := handle.seekTo(offset)
+		if seekErr != nil {
+			return seekErr
 		}
-	}
 
-	err := irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+		return irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -246,6 +296,241 @@ func (handle *FileHandle) WriteAt(data []byte, offset int64) (int, error) {
 	return len(data), nil
 }
 
+// seekTo moves file pointer to the offset if it is not there
+func (handle *FileHandle) seekTo(offset int64) error {
+	if handle.offset == offset {
+		return nil
+	}
+
+	newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
+	if err != nil {
+		return err
+	}
+
+	handle.offset = newOffset
+
+	if newOffset != offset {
+		return xerrors.Errorf("failed to seek to %d", offset)
+	}
+	return nil
+}
+
+// GetFileDescriptorInfo returns information the server keeps for the opened file, e.g., replica and bytes written
+func (handle *FileHandle) GetFileDescriptorInfo() (*types.IRODSFileDescriptorInfo, error) {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	err := handle.bind()
+	if err != nil {
+		return nil, err
+	}
+
+	return irods_fs.GetFileDescriptorInfo(handle.connection, handle.irodsFileHandle)
+}
+
+// IsParked returns true if the handle released its connection as it was idle, the file is opened again on next use
+func (handle *FileHandle) IsParked() bool {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	return handle.connection == nil
+}
+
+// Reopen opens the file again with a new connection and moves file pointer back to the current offset
+// use to recover the handle when its connection breaks, e.g., by a server restart
+// locks of the data object held by the handle are released
+func (handle *FileHandle) Reopen() error {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	return handle.reopen()
+}
+
+func (handle *FileHandle) reopen() error {
+	newConn, newHandle, offset, err := handle.openWithNewConnection()
+	if err != nil {
+		return err
+	}
+
+	// the file is closed by the server if the connection is broken
+	handle.releaseConnection()
+	handle.setConnection(newConn, newHandle, offset)
+	return nil
+}
+
+// bind opens the file again with a connection if the handle is parked
+func (handle *FileHandle) bind() error {
+	if handle.connection != nil {
+		return nil
+	}
+
+	// make room for the handle if all connections are in use
+	handle.filesystem.parkLeastRecentlyUsedFileHandle(handle)
+
+	newConn, newHandle, offset, err := handle.openWithNewConnection()
+	if err != nil {
+		return err
+	}
+
+	handle.setConnection(newConn, newHandle, offset)
+	return nil
+}
+
+// openWithNewConnection opens the file with a new connection and moves file pointer to the current offset
+func (handle *FileHandle) openWithNewConnection() (*connection.IRODSConnection, *types.IRODSFileHandle, int64, error) {
+	session := handle.filesystem.ioSession
+
+	newConn, err := session.AcquireConnection()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	newOpenMode := handle.getReopenMode()
+
+	newHandle, offset, err := irods_fs.OpenDataObject(newConn, handle.entry.Path, handle.irodsFileHandle.Resource, string(newOpenMode))
+	if err != nil {
+		session.ReturnConnection(newConn)
+		return nil, nil, 0, err
+	}
+
+	// files opened in append modes are written at the end
+	if !newOpenMode.SeekToEnd() && offset != handle.offset {
+		newOffset, err := irods_fs.SeekDataObject(newConn, newHandle, handle.offset, types.SeekSet)
+		if err != nil {
+			irods_fs.CloseDataObject(newConn, newHandle)
+			session.ReturnConnection(newConn)
+			return nil, nil, 0, err
+		}
+
+		if handle.offset != newOffset {
+			irods_fs.CloseDataObject(newConn, newHandle)
+			session.ReturnConnection(newConn)
+			return nil, nil, 0, xerrors.Errorf("failed to seek to %d", handle.offset)
+		}
+		offset = newOffset
+	}
+
+	return newConn, newHandle, offset, nil
+}
+
+// setConnection binds the connection and the file opened with it to the handle
+func (handle *FileHandle) setConnection(conn *connection.IRODSConnection, irodsFileHandle *types.IRODSFileHandle, offset int64) {
+	handle.connection = conn
+	handle.irodsFileHandle = irodsFileHandle
+	handle.openMode = handle.getReopenMode()
+	handle.offset = offset
+
+	atomic.AddInt64(&handle.filesystem.fileHandleConnections, 1)
+}
+
+// releaseConnection closes the file opened with the connection of the handle and releases the connection
+// locks of the data object held by the handle are released
+func (handle *FileHandle) releaseConnection() error {
+	if handle.connection == nil {
+		return nil
+	}
+
+	session := handle.filesystem.ioSession
+
+	var err error
+	if handle.connection.IsConnected() {
+		err = irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
+		session.ReturnConnection(handle.connection)
+	} else {
+		session.DiscardConnection(handle.connection)
+	}
+
+	handle.connection = nil
+	handle.irodsFileLockHandle = nil
+
+	atomic.AddInt64(&handle.filesystem.fileHandleConnections, -1)
+	return err
+}
+
+// parkIfIdle releases the connection of the handle if the handle has not been used for the idle timeout, returns true if released
+// handles in use or holding locks are not parked
+func (handle *FileHandle) parkIfIdle(idleTimeout time.Duration) bool {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"struct":   "FileHandle",
+		"function": "parkIfIdle",
+	})
+
+	if !handle.mutex.TryLock() {
+		// in use
+		return false
+	}
+	defer handle.mutex.Unlock()
+
+	if handle.connection == nil || handle.irodsFileLockHandle != nil {
+		return false
+	}
+
+	if time.Since(handle.lastAccess) < idleTimeout {
+		return false
+	}
+
+	err := handle.releaseConnection()
+	if err != nil {
+		logger.WithError(err).Debugf("failed to close file %s being parked", handle.entry.Path)
+	}
+
+	if handle.IsWriteMode() {
+		// closing the file applies data written
+		handle.filesystem.invalidateCacheForFileUpdate(handle.entry.Path)
+		handle.filesystem.cachePropagation.PropagateFileUpdate(handle.entry.Path)
+	}
+
+	return true
+}
+
+// getLastAccess returns the time the handle was used last, and true if the handle can be parked
+func (handle *FileHandle) getLastAccess() (time.Time, bool) {
+	if !handle.mutex.TryLock() {
+		// in use
+		return time.Time{}, false
+	}
+	defer handle.mutex.Unlock()
+
+	return handle.lastAccess, handle.connection != nil && handle.irodsFileLockHandle == nil
+}
+
+// getReopenMode returns a mode to open the file again without truncating it
+func (handle *FileHandle) getReopenMode() types.FileOpenMode {
+	if handle.openMode == types.FileOpenModeWriteTruncate {
+		return types.FileOpenModeWriteOnly
+	}
+	return handle.openMode
+}
+
+// runIO runs the I/O, binding a connection if the handle is parked
+// reopens the file and runs the I/O again if it fails as the connection broke
+// write is true for writes, which are not retried for files opened in append modes as they may have been applied
+func (handle *FileHandle) runIO(write bool, io func() error) error {
+	err := handle.bind()
+	if err != nil {
+		return err
+	}
+
+	handle.lastAccess = time.Now()
+
+	err = io()
+	if err == nil || !handle.filesystem.config.ReopenFileHandles || handle.connection.IsConnected() {
+		return err
+	}
+
+	if write && handle.openMode.SeekToEnd() {
+		return err
+	}
+
+	reopenErr := handle.reopen()
+	if reopenErr != nil {
+		return xerrors.Errorf("failed to reopen file %s after %s: %w", handle.entry.Path, err.Error(), reopenErr)
+	}
+
+	return io()
+}
+
 // LockDataObject locks data object with write lock (exclusive)
 func (handle *FileHandle) LockDataObject(wait bool) error {
 	handle.mutex.Lock()
@@ -257,6 +542,11 @@ func (handle *FileHandle) LockDataObject(wait bool) error {
 		lockCommand = types.DataObjectLockCommandSetLockWait
 	}
 
+	err := handle.bind()
+	if err != nil {
+		return err
+	}
+
 	fileLockHandle, err := irods_fs.LockDataObject(handle.connection, handle.irodsFileHandle.Path, lockType, lockCommand)
 	if err != nil {
 		return err
@@ -278,6 +568,11 @@ func (handle *FileHandle) RLockDataObject(wait bool) error {
 		lockCommand = types.DataObjectLockCommandSetLockWait
 	}
 
+	err := handle.bind()
+	if err != nil {
+		return err
+	}
+
 	fileLockHandle, err := irods_fs.LockDataObject(handle.connection, handle.irodsFileHandle.Path, lockType, lockCommand)
 	if err != nil {
 		return err
@@ -308,7 +603,10 @@ func (handle *FileHandle) UnlockDataObject() error {
 // preprocessRename should be called before the file is renamed
 func (handle *FileHandle) preprocessRename() error {
 	// first, we need to close the file
-	err := irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
+	var err error
+	if handle.connection != nil {
+		err = irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
+	}
 
 	if handle.IsWriteMode() {
 		handle.filesystem.invalidateCacheForFileUpdate(handle.entry.Path)
@@ -321,20 +619,14 @@ func (handle *FileHandle) preprocessRename() error {
 // postprocessRename should be called after the file is renamed
 func (handle *FileHandle) postprocessRename(newPath string, newEntry *Entry) error {
 	// apply path change
-	newOpenMode := types.FileOpenModeReadWrite
-	switch handle.openMode {
-	case types.FileOpenModeReadOnly:
-		newOpenMode = handle.openMode
-	case types.FileOpenModeReadWrite:
-		newOpenMode = handle.openMode
-	case types.FileOpenModeWriteOnly:
-		newOpenMode = handle.openMode
-	case types.FileOpenModeWriteTruncate:
-		newOpenMode = types.FileOpenModeWriteOnly
-	case types.FileOpenModeAppend:
-		newOpenMode = handle.openMode
-	case types.FileOpenModeReadAppend:
-		newOpenMode = handle.openMode
+	newOpenMode := handle.getReopenMode()
+
+	if handle.connection == nil {
+		// parked, the file is opened at the new path on next use
+		handle.irodsFileHandle.Path = newPath
+		handle.entry = newEntry
+		handle.openMode = newOpenMode
+		return nil
 	}
 
 	// reopen
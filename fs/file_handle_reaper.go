@@ -0,0 +1,86 @@
+package fs
+
+import (
+	"sync"
+	"time"
+)
+
+// fileHandleReaper parks file handles not used for the idle timeout in background
+// parked handles release their connections, so mostly idle open files do not hold connections
+type fileHandleReaper struct {
+	fileHandleMap *FileHandleMap
+	idleTimeout   time.Duration
+	done          chan bool
+	waitGroup     sync.WaitGroup
+}
+
+// newFileHandleReaper creates a new fileHandleReaper
+func newFileHandleReaper(fileHandleMap *FileHandleMap, idleTimeout time.Duration) *fileHandleReaper {
+	return &fileHandleReaper{
+		fileHandleMap: fileHandleMap,
+		idleTimeout:   idleTimeout,
+		done:          make(chan bool),
+	}
+}
+
+// start starts parking idle handles in background
+func (reaper *fileHandleReaper) start() {
+	reaper.waitGroup.Add(1)
+	go func() {
+		defer reaper.waitGroup.Done()
+
+		ticker := time.NewTicker(reaper.idleTimeout / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-reaper.done:
+				return
+			case <-ticker.C:
+				for _, handle := range reaper.fileHandleMap.List() {
+					handle.parkIfIdle(reaper.idleTimeout)
+				}
+			}
+		}
+	}()
+}
+
+// stop stops parking idle handles
+func (reaper *fileHandleReaper) stop() {
+	close(reaper.done)
+	reaper.waitGroup.Wait()
+}
+
+// parkLeastRecentlyUsedFileHandle parks the least recently used file handle if all io connections are in use
+// does nothing if file handles are not parked, except is not parked
+func (fs *FileSystem) parkLeastRecentlyUsedFileHandle(except *FileHandle) {
+	if fs.fileHandleReaper == nil {
+		return
+	}
+
+	if fs.ioSession.ConnectionsInUse() < fs.ioSession.GetConfig().ConnectionMax {
+		return
+	}
+
+	var leastRecentlyUsed *FileHandle
+	leastRecentlyUsedTime := time.Time{}
+	for _, handle := range fs.fileHandleMap.List() {
+		if handle == except {
+			continue
+		}
+
+		lastAccess, parkable := handle.getLastAccess()
+		if !parkable {
+			continue
+		}
+
+		if leastRecentlyUsed == nil || lastAccess.Before(leastRecentlyUsedTime) {
+			leastRecentlyUsed = handle
+			leastRecentlyUsedTime = lastAccess
+		}
+	}
+
+	if leastRecentlyUsed != nil {
+		leastRecentlyUsed.parkIfIdle(0)
+	}
+}
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -56,6 +57,26 @@ func (mgr *FileLocks) LockFilesForPrefix(pathPrefix string) []string {
 	return lockedFilePaths
 }
 
+// LockFiles locks multiple files in sorted order to avoid deadlocks, returns locked paths to be passed to UnlockFiles
+func (mgr *FileLocks) LockFiles(paths []string) []string {
+	sortedPaths := []string{}
+	pathMap := map[string]bool{}
+	for _, path := range paths {
+		if _, ok := pathMap[path]; !ok {
+			pathMap[path] = true
+			sortedPaths = append(sortedPaths, path)
+		}
+	}
+
+	sort.Strings(sortedPaths)
+
+	for _, path := range sortedPaths {
+		mgr.Lock(path)
+	}
+
+	return sortedPaths
+}
+
 // UnlockFiles unlocks multiple files
 func (mgr *FileLocks) UnlockFiles(paths []string) error {
 	fileLocks := []*FileLock{}
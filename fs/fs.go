@@ -1,10 +1,14 @@
 package fs
 
 import (
+	"context"
 	"path"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/metrics"
@@ -25,18 +29,41 @@ type FileSystem struct {
 	cache                *FileSystemCache
 	cachePropagation     *FileSystemCachePropagation
 	cacheEventHandlerMap *FilesystemCacheEventHandlerMap
+	auditHandlerMap      *auditHandlerMap
+	transferBudget       *TransferBudget // nil if transfers are not limited
 	fileHandleMap        *FileHandleMap
+	pathLocks            *FileLocks        // serializes operations on the same path
+	fileHandleReaper     *fileHandleReaper // nil if file handles are not parked
+
+	// number of io connections held by open file handles, parked handles hold none
+	fileHandleConnections int64
+
+	clientHints      *types.IRODSClientHints // nil until retrieved
+	clientHintsMutex sync.Mutex
+
+	accountMutex sync.RWMutex // guards account, replaced by UpdateAccount
 }
 
 // NewFileSystem creates a new FileSystem
 func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*FileSystem, error) {
-	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	// normalize a copy once here, sessions and connections share the account, and the account may be in use elsewhere
+	normalizedAccount := *account
+	err := normalizedAccount.Normalize()
+	if err != nil {
+		return nil, xerrors.Errorf("invalid account: %w", err)
+	}
+	account = &normalizedAccount
+
+	// io and metadata sessions share the limiter to limit their total rate
+	requestRateLimiter := config.getRequestRateLimiter()
+
+	ioSessionConfig := config.getIOSessionConfig(requestRateLimiter)
 	ioSession, err := session.NewIRODSSession(account, ioSessionConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	metaSessionConfig := config.getMetadataSessionConfig(requestRateLimiter)
 	metaSession, err := session.NewIRODSSession(account, metaSessionConfig)
 	if err != nil {
 		return nil, err
@@ -56,6 +83,8 @@ func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*File
 	metaSession.SetTransactionFailureHandler(metaTransactionFailureHandler)
 
 	cache := NewFileSystemCache(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately)
+	cache.disabled = config.DisableCache
+	cache.setNegativeEntryCacheSettings(config.NegativeEntryCacheSettings)
 
 	fs := &FileSystem{
 		id:                   xid.New().String(), // generate a new ID
@@ -65,24 +94,42 @@ func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*File
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		auditHandlerMap:      newAuditHandlerMap(),
 		fileHandleMap:        NewFileHandleMap(),
+		pathLocks:            NewFileLocks(),
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
 	fs.cachePropagation = cachePropagation
 
+	if config.AuditHandler != nil {
+		fs.auditHandlerMap.AddHandler(config.AuditHandler)
+	}
+
+	if config.TransferConcurrencyMax > 0 {
+		fs.transferBudget = NewTransferBudget(config.TransferConcurrencyMax)
+	}
+
+	if config.FileHandleIdleTimeout > 0 {
+		fs.fileHandleReaper = newFileHandleReaper(fs.fileHandleMap, config.FileHandleIdleTimeout)
+		fs.fileHandleReaper.start()
+	}
+
 	return fs, nil
 }
 
 // NewFileSystemWithAddressResolver creates a new FileSystem
 func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileSystemConfig, addressResolver session.AddressResolver) (*FileSystem, error) {
-	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	// io and metadata sessions share the limiter to limit their total rate
+	requestRateLimiter := config.getRequestRateLimiter()
+
+	ioSessionConfig := config.getIOSessionConfig(requestRateLimiter)
 	ioSession, err := session.NewIRODSSessionWithAddressResolver(account, ioSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
 	}
 
-	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	metaSessionConfig := config.getMetadataSessionConfig(requestRateLimiter)
 	metaSession, err := session.NewIRODSSessionWithAddressResolver(account, metaSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
@@ -102,6 +149,8 @@ func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileS
 	metaSession.SetTransactionFailureHandler(metaTransactionFailureHandler)
 
 	cache := NewFileSystemCache(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately)
+	cache.disabled = config.DisableCache
+	cache.setNegativeEntryCacheSettings(config.NegativeEntryCacheSettings)
 
 	fs := &FileSystem{
 		id:                   xid.New().String(), // generate a new ID
@@ -111,25 +160,47 @@ func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileS
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		auditHandlerMap:      newAuditHandlerMap(),
 		fileHandleMap:        NewFileHandleMap(),
+		pathLocks:            NewFileLocks(),
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
 	fs.cachePropagation = cachePropagation
 
+	if config.AuditHandler != nil {
+		fs.auditHandlerMap.AddHandler(config.AuditHandler)
+	}
+
+	if config.TransferConcurrencyMax > 0 {
+		fs.transferBudget = NewTransferBudget(config.TransferConcurrencyMax)
+	}
+
+	if config.FileHandleIdleTimeout > 0 {
+		fs.fileHandleReaper = newFileHandleReaper(fs.fileHandleMap, config.FileHandleIdleTimeout)
+		fs.fileHandleReaper.start()
+	}
+
 	return fs, nil
 }
 
+// NewFileSystemWithOptions creates a new FileSystem with default configurations overridden by options
+func NewFileSystemWithOptions(account *types.IRODSAccount, applicationName string, options ...FileSystemConfigOption) (*FileSystem, error) {
+	return NewFileSystem(account, NewFileSystemConfigWithOptions(applicationName, options...))
+}
+
 // NewFileSystemWithDefault creates a new FileSystem with default configurations
 func NewFileSystemWithDefault(account *types.IRODSAccount, applicationName string) (*FileSystem, error) {
 	config := NewFileSystemConfigWithDefault(applicationName)
 	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	ioSessionConfig.ConnectionKeepaliveInterval = config.ConnectionKeepaliveInterval
 	ioSession, err := session.NewIRODSSession(account, ioSessionConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	metaSessionConfig.ConnectionKeepaliveInterval = config.ConnectionKeepaliveInterval
 	metaSession, err := session.NewIRODSSession(account, metaSessionConfig)
 	if err != nil {
 		return nil, err
@@ -145,7 +216,9 @@ func NewFileSystemWithDefault(account *types.IRODSAccount, applicationName strin
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		auditHandlerMap:      newAuditHandlerMap(),
 		fileHandleMap:        NewFileHandleMap(),
+		pathLocks:            NewFileLocks(),
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
@@ -163,6 +236,8 @@ func NewFileSystemWithSessionConfig(account *types.IRODSAccount, sessConfig *ses
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	metaSessionConfig.ConnectionKeepaliveInterval = config.ConnectionKeepaliveInterval
+	metaSessionConfig.RequestRateLimiter = sessConfig.RequestRateLimiter
 	metaSession, err := session.NewIRODSSessionWithAddressResolver(account, metaSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
@@ -178,7 +253,9 @@ func NewFileSystemWithSessionConfig(account *types.IRODSAccount, sessConfig *ses
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		auditHandlerMap:      newAuditHandlerMap(),
 		fileHandleMap:        NewFileHandleMap(),
+		pathLocks:            NewFileLocks(),
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
@@ -189,23 +266,97 @@ func NewFileSystemWithSessionConfig(account *types.IRODSAccount, sessConfig *ses
 
 // Release releases all resources
 func (fs *FileSystem) Release() {
+	if fs.fileHandleReaper != nil {
+		fs.fileHandleReaper.stop()
+		fs.fileHandleReaper = nil
+	}
+
 	handles := fs.fileHandleMap.PopAll()
 	for _, handle := range handles {
 		handle.Close()
 	}
 
 	fs.cacheEventHandlerMap.Release()
+	fs.auditHandlerMap.Release()
 	fs.cachePropagation.Release()
 
 	fs.ioSession.Release()
 	fs.metaSession.Release()
 }
 
+// Shutdown stops accepting new operations, waits for in-flight operations until ctx is done,
+// then closes open file handles and releases all resources
+func (fs *FileSystem) Shutdown(ctx context.Context) error {
+	fs.ioSession.StopAcquire()
+	fs.metaSession.StopAcquire()
+
+	// open file handles hold io connections until they are closed, parked handles hold none
+	waitErr := session.WaitUntilIdle(ctx, func() bool {
+		return fs.metaSession.ConnectionsInUse() == 0 && int64(fs.ioSession.ConnectionsInUse()) <= atomic.LoadInt64(&fs.fileHandleConnections)
+	})
+	if waitErr != nil {
+		waitErr = xerrors.Errorf("failed to wait for in-flight operations: %w", waitErr)
+	}
+
+	// flush and close open file handles
+	var closeErr error
+	handles := fs.fileHandleMap.PopAll()
+	for _, handle := range handles {
+		err := handle.Close()
+		if err != nil && closeErr == nil {
+			closeErr = xerrors.Errorf("failed to close file handle for %s: %w", handle.entry.Path, err)
+		}
+	}
+
+	fs.Release()
+
+	if waitErr != nil {
+		return waitErr
+	}
+	return closeErr
+}
+
 // GetID returns file system instance ID
 func (fs *FileSystem) GetID() string {
 	return fs.id
 }
 
+// UpdateAccount makes the file system authenticate with the account, e.g., after a password is rotated
+// connections of the old account are closed when they are not in use, caches and open file handles are kept
+func (fs *FileSystem) UpdateAccount(account *types.IRODSAccount) error {
+	// normalize a copy, the account may be in use elsewhere
+	normalizedAccount := *account
+	err := normalizedAccount.Normalize()
+	if err != nil {
+		return xerrors.Errorf("invalid account: %w", err)
+	}
+	account = &normalizedAccount
+
+	err = fs.ioSession.UpdateAccount(account)
+	if err != nil {
+		return xerrors.Errorf("failed to update account of io session: %w", err)
+	}
+
+	err = fs.metaSession.UpdateAccount(account)
+	if err != nil {
+		return xerrors.Errorf("failed to update account of metadata session: %w", err)
+	}
+
+	fs.accountMutex.Lock()
+	fs.account = account
+	fs.accountMutex.Unlock()
+	return nil
+}
+
+// getAccount returns the account the file system authenticates with
+// the account is replaced, not modified, by UpdateAccount, so fields of the returned account can be read without locks
+func (fs *FileSystem) getAccount() *types.IRODSAccount {
+	fs.accountMutex.RLock()
+	defer fs.accountMutex.RUnlock()
+
+	return fs.account
+}
+
 // GetIOConnection returns irods connection for IO
 func (fs *FileSystem) GetIOConnection() (*connection.IRODSConnection, error) {
 	return fs.ioSession.AcquireConnection()
@@ -221,6 +372,12 @@ func (fs *FileSystem) GetMetadataConnection() (*connection.IRODSConnection, erro
 	return fs.metaSession.AcquireConnection()
 }
 
+// GetMetadataConnectionWithKeywords returns irods connection for metadata operations, sending the keywords with requests
+// the connection is not shared until it is returned by ReturnMetadataConnection
+func (fs *FileSystem) GetMetadataConnectionWithKeywords(keywords map[common.KeyWord]string) (*connection.IRODSConnection, error) {
+	return fs.metaSession.AcquireConnectionWithKeywords(keywords)
+}
+
 // ReturnMetadataConnection returns irods connection for metadata operations back to session
 func (fs *FileSystem) ReturnMetadataConnection(conn *connection.IRODSConnection) {
 	fs.metaSession.ReturnConnection(conn)
@@ -258,9 +415,22 @@ func (fs *FileSystem) GetMetrics() *metrics.IRODSMetrics {
 	return newMetrics
 }
 
+// Ping checks if the filesystem can authenticate and reach the catalog, e.g., for readiness probes
+func (fs *FileSystem) Ping() error {
+	return fs.metaSession.Ping()
+}
+
+// getCorrectIRODSPath corrects the path and normalizes unicode characters if configured
+func (fs *FileSystem) getCorrectIRODSPath(p string) string {
+	return util.NormalizeIRODSPath(util.GetCorrectIRODSPath(p), fs.config.UnicodeNormalization)
+}
+
 // Stat returns file status
 func (fs *FileSystem) Stat(p string) (*Entry, error) {
-	irodsPath := util.GetCorrectIRODSPath(p)
+	irodsPath := fs.getCorrectIRODSPath(p)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
 
 	// check if a negative cache for the given path exists
 	if fs.cache.HasNegativeEntryCache(irodsPath) {
@@ -321,14 +491,20 @@ func (fs *FileSystem) Stat(p string) (*Entry, error) {
 
 // StatDir returns status of a directory
 func (fs *FileSystem) StatDir(path string) (*Entry, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
 
 	return fs.getCollection(irodsPath)
 }
 
 // StatFile returns status of a file
 func (fs *FileSystem) StatFile(path string) (*Entry, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
 
 	return fs.getDataObject(irodsPath)
 }
@@ -362,7 +538,10 @@ func (fs *FileSystem) ExistsFile(path string) bool {
 
 // List lists all file system entries under the given path
 func (fs *FileSystem) List(path string) ([]*Entry, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
 
 	collectionEntry, err := fs.getCollection(irodsPath)
 	if err != nil {
@@ -374,9 +553,133 @@ func (fs *FileSystem) List(path string) ([]*Entry, error) {
 	return fs.listEntries(collection)
 }
 
+// ListOptions is options for listing file system entries
+type ListOptions struct {
+	// attach all replicas to file entries, entries are not cached then
+	WithReplicas bool
+	// list only the replica in the resource, filtered by the server to reduce rows of heavily replicated files
+	// files not having a replica in the resource are not listed, entries are not cached then
+	Resource string
+	// list only the replica of ReplicaNumber, filtered by the server like Resource, ignored if Resource is set
+	FilterByReplicaNumber bool
+	ReplicaNumber         int64
+}
+
+// filtersReplicas returns true if only a replica of each file is listed
+func (options *ListOptions) filtersReplicas() bool {
+	return len(options.Resource) > 0 || options.FilterByReplicaNumber
+}
+
+// ListWithOptions lists all file system entries under the given path with options
+func (fs *FileSystem) ListWithOptions(path string, options *ListOptions) ([]*Entry, error) {
+	if options == nil || (!options.WithReplicas && !options.filtersReplicas()) {
+		return fs.List(path)
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
+
+	collectionEntry, err := fs.getCollection(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := fs.getCollectionFromEntry(collectionEntry)
+
+	return fs.listEntriesWithReplicas(collection, options)
+}
+
+// ListSubTree lists all file system entries under the given path at any depth
+// entries are listed with a few queries for the whole tree instead of listing dirs one by one
+// dirs come first, parents before children, then files
+func (fs *FileSystem) ListSubTree(path string) ([]*Entry, error) {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
+
+	_, err := fs.getCollection(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	collections, err := irods_fs.ListSubCollectionsRecursive(conn, irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dataobjects, err := irods_fs.ListDataObjectsMasterReplicaRecursive(conn, irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.makeSubTreeEntries(irodsPath, collections, dataobjects), nil
+}
+
+// makeSubTreeEntries makes sorted entries of collections and data objects under the path at any depth, and caches them
+// dirs come first, parents before children, then files
+func (fs *FileSystem) makeSubTreeEntries(irodsPath string, collections []*types.IRODSCollection, dataobjects []*types.IRODSDataObject) []*Entry {
+	dirEntries := []*Entry{}
+	for _, coll := range collections {
+		dirEntries = append(dirEntries, fs.getEntryFromCollection(coll))
+	}
+
+	sort.Slice(dirEntries, func(i int, j int) bool {
+		return dirEntries[i].Path < dirEntries[j].Path
+	})
+
+	fileEntries := []*Entry{}
+	for _, dataobject := range dataobjects {
+		if len(dataobject.Replicas) == 0 {
+			continue
+		}
+
+		fileEntries = append(fileEntries, fs.getEntryFromDataObject(dataobject))
+	}
+
+	sort.Slice(fileEntries, func(i int, j int) bool {
+		return fileEntries[i].Path < fileEntries[j].Path
+	})
+
+	entries := append(dirEntries, fileEntries...)
+
+	// cache entries and dir entries of all dirs in the tree
+	dirEntryPaths := map[string][]string{irodsPath: {}}
+	for _, dirEntry := range dirEntries {
+		dirEntryPaths[dirEntry.Path] = []string{}
+	}
+
+	for _, entry := range entries {
+		fs.cache.RemoveNegativeEntryCache(entry.Path)
+		fs.cache.AddEntryCache(entry)
+
+		parentPath := util.GetIRODSPathDirname(entry.Path)
+		dirEntryPaths[parentPath] = append(dirEntryPaths[parentPath], entry.Path)
+	}
+
+	for dirPath, entryPaths := range dirEntryPaths {
+		fs.cache.AddDirCache(dirPath, entryPaths)
+	}
+
+	return entries
+}
+
 // RemoveDir deletes a directory
-func (fs *FileSystem) RemoveDir(path string, recurse bool, force bool) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) RemoveDir(path string, recurse bool, force bool) (err error) {
+	defer fs.audit("RemoveDir", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -395,8 +698,13 @@ func (fs *FileSystem) RemoveDir(path string, recurse bool, force bool) error {
 }
 
 // RemoveFile deletes a file
-func (fs *FileSystem) RemoveFile(path string, force bool) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) RemoveFile(path string, force bool) (err error) {
+	defer fs.audit("RemoveFile", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -434,8 +742,8 @@ func (fs *FileSystem) RemoveFile(path string, force bool) error {
 
 // RenameDir renames a dir
 func (fs *FileSystem) RenameDir(srcPath string, destPath string) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
 
 	destDirPath := irodsDestPath
 	if fs.ExistsDir(irodsDestPath) {
@@ -448,9 +756,14 @@ func (fs *FileSystem) RenameDir(srcPath string, destPath string) error {
 }
 
 // RenameDirToDir renames a dir
-func (fs *FileSystem) RenameDirToDir(srcPath string, destPath string) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+func (fs *FileSystem) RenameDirToDir(srcPath string, destPath string) (err error) {
+	defer fs.auditWithDest("RenameDir", srcPath, destPath, &err)
+
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	lockedPaths := fs.pathLocks.LockFiles([]string{irodsSrcPath, irodsDestPath})
+	defer fs.pathLocks.UnlockFiles(lockedPaths)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -484,8 +797,8 @@ func (fs *FileSystem) RenameDirToDir(srcPath string, destPath string) error {
 
 // RenameFile renames a file
 func (fs *FileSystem) RenameFile(srcPath string, destPath string) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
 
 	destFilePath := irodsDestPath
 	if fs.ExistsDir(irodsDestPath) {
@@ -499,10 +812,20 @@ func (fs *FileSystem) RenameFile(srcPath string, destPath string) error {
 
 // RenameFileToFile renames a file
 func (fs *FileSystem) RenameFileToFile(srcPath string, destPath string) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+	return fs.renameFileToFile(srcPath, destPath, "", false, nil)
+}
 
-	conn, err := fs.metaSession.AcquireConnection()
+// renameFileToFile renames a file, an existing dest file is replaced by the server if force is set
+func (fs *FileSystem) renameFileToFile(srcPath string, destPath string, resource string, force bool, keywords map[common.KeyWord]string) (err error) {
+	defer fs.auditWithDest("RenameFile", srcPath, destPath, &err)
+
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	lockedPaths := fs.pathLocks.LockFiles([]string{irodsSrcPath, irodsDestPath})
+	defer fs.pathLocks.UnlockFiles(lockedPaths)
+
+	conn, err := fs.metaSession.AcquireConnectionWithKeywords(keywords)
 	if err != nil {
 		return err
 	}
@@ -515,13 +838,17 @@ func (fs *FileSystem) RenameFileToFile(srcPath string, destPath string) error {
 	}
 
 	// rename
-	err = irods_fs.MoveDataObject(conn, irodsSrcPath, irodsDestPath)
+	err = irods_fs.MoveDataObjectToResource(conn, irodsSrcPath, irodsDestPath, resource, force)
 	if err != nil {
 		return err
 	}
 
 	fs.invalidateCacheForFileRemove(irodsSrcPath)
 	fs.cachePropagation.PropagateFileRemove(irodsSrcPath)
+	if force {
+		// the dest file may have been replaced
+		fs.invalidateCacheForFileRemove(irodsDestPath)
+	}
 	fs.invalidateCacheForFileCreate(irodsDestPath)
 	fs.cachePropagation.PropagateFileCreate(irodsDestPath)
 
@@ -534,6 +861,55 @@ func (fs *FileSystem) RenameFileToFile(srcPath string, destPath string) error {
 	return nil
 }
 
+// RenameFileOptions is options for renaming a file
+type RenameFileOptions struct {
+	// how to handle an existing dest file, fails if empty
+	OverwritePolicy OverwritePolicy
+	// replace an existing dest file by the server in the rename itself, OverwritePolicy is not applied
+	Force bool
+	// resource given to the server as the dest resource, the rename fails with RenameNotSupportedError if the file is not in the resource
+	Resource string
+	// extra keywords sent with the rename request, e.g., RESC_HIER_STR_KW
+	Keywords map[common.KeyWord]string
+}
+
+// RenameFileToFileWithOptions renames a file, an existing dest file is handled by the overwrite policy or replaced by the server if forced
+// returns a path of the renamed file, which differs from destPath if the file is renamed with a suffix
+func (fs *FileSystem) RenameFileToFileWithOptions(srcPath string, destPath string, options *RenameFileOptions) (string, error) {
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	if options == nil {
+		options = &RenameFileOptions{}
+	}
+
+	if !fs.ExistsFile(irodsSrcPath) {
+		return "", xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
+	}
+
+	if !options.Force {
+		resolvedPath, overwrite, err := fs.resolveOverwrite(irodsDestPath, options.OverwritePolicy)
+		if err != nil {
+			return "", err
+		}
+
+		if overwrite {
+			err = fs.RemoveFile(resolvedPath, true)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		irodsDestPath = resolvedPath
+	}
+
+	err := fs.renameFileToFile(irodsSrcPath, irodsDestPath, options.Resource, options.Force, options.Keywords)
+	if err != nil {
+		return "", err
+	}
+	return irodsDestPath, nil
+}
+
 func (fs *FileSystem) preprocessRenameFileHandle(srcPath string) ([]*FileHandle, error) {
 	handles := fs.fileHandleMap.PopByPath(srcPath)
 	handlesLocked := []*FileHandle{}
@@ -651,8 +1027,13 @@ func (fs *FileSystem) postprocessRenameFileHandleForDir(handles []*FileHandle, c
 }
 
 // MakeDir creates a directory
-func (fs *FileSystem) MakeDir(path string, recurse bool) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) MakeDir(path string, recurse bool) (err error) {
+	defer fs.audit("MakeDir", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -660,7 +1041,7 @@ func (fs *FileSystem) MakeDir(path string, recurse bool) error {
 	}
 	defer fs.metaSession.ReturnConnection(conn)
 
-	dirEntry, err := fs.StatDir(path)
+	dirEntry, err := fs.getCollection(irodsPath)
 	if err == nil {
 		if dirEntry.ID > 0 {
 			// already exists
@@ -682,25 +1063,98 @@ func (fs *FileSystem) MakeDir(path string, recurse bool) error {
 	return nil
 }
 
-// CopyFile copies a file
-func (fs *FileSystem) CopyFile(srcPath string, destPath string, force bool) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+// MakeDirAll creates a directory along with missing parents, like os.MkdirAll
+// existing directory is not an error
+// returns an entry of the directory and paths of directories created, parents first
+func (fs *FileSystem) MakeDirAll(path string) (_ *Entry, _ []string, err error) {
+	defer fs.audit("MakeDir", path, &err)
 
-	destFilePath := irodsDestPath
-	if fs.ExistsDir(irodsDestPath) {
-		// make full file name for dest
-		srcFileName := util.GetIRODSPathFileName(irodsSrcPath)
-		destFilePath = util.MakeIRODSPath(irodsDestPath, srcFileName)
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	// find missing dirs up to the first existing parent
+	createdPaths := []string{}
+	for p := irodsPath; ; p = util.GetIRODSPathDirname(p) {
+		dirEntry, err := fs.getCollection(p)
+		if err == nil {
+			if p == irodsPath {
+				// already exists
+				return dirEntry, createdPaths, nil
+			}
+			break
+		}
+
+		if !types.IsFileNotFoundError(err) {
+			return nil, nil, err
+		}
+
+		_, err = fs.getDataObject(p)
+		if err == nil {
+			return nil, nil, xerrors.Errorf("failed to make dir %s, %s is a file: %w", irodsPath, p, types.NewFileAlreadyExistError(p))
+		}
+
+		createdPaths = append([]string{p}, createdPaths...)
+
+		if p == "/" {
+			break
+		}
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.CreateCollection(conn, irodsPath, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return fs.CopyFileToFile(irodsSrcPath, destFilePath, force)
+	for _, createdPath := range createdPaths {
+		fs.invalidateCacheForDirCreate(createdPath)
+		fs.cachePropagation.PropagateDirCreate(createdPath)
+	}
+	fs.cache.AddDirCache(irodsPath, []string{})
+
+	dirEntry, err := fs.getCollectionNoCache(irodsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dirEntry, createdPaths, nil
+}
+
+// CopyFile copies a file
+func (fs *FileSystem) CopyFile(srcPath string, destPath string, force bool) error {
+	_, err := fs.CopyFileWithOptions(srcPath, destPath, &CopyFileOptions{
+		OverwritePolicy: getOverwritePolicyFromForce(force),
+	})
+	return err
 }
 
 // CopyFileToFile copies a file
 func (fs *FileSystem) CopyFileToFile(srcPath string, destPath string, force bool) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(srcPath)
-	irodsDestPath := util.GetCorrectIRODSPath(destPath)
+	_, err := fs.CopyFileToFileWithOptions(srcPath, destPath, &CopyFileOptions{
+		OverwritePolicy: getOverwritePolicyFromForce(force),
+	})
+	return err
+}
+
+// TruncateFile truncates a file
+func (fs *FileSystem) TruncateFile(path string, size int64) (err error) {
+	defer fs.audit("TruncateFile", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	if size < 0 {
+		size = 0
+	}
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -708,23 +1162,24 @@ func (fs *FileSystem) CopyFileToFile(srcPath string, destPath string, force bool
 	}
 	defer fs.metaSession.ReturnConnection(conn)
 
-	err = irods_fs.CopyDataObject(conn, irodsSrcPath, irodsDestPath, force)
+	err = irods_fs.TruncateDataObject(conn, irodsPath, size)
 	if err != nil {
 		return err
 	}
 
-	fs.invalidateCacheForFileCreate(irodsDestPath)
-	fs.cachePropagation.PropagateFileCreate(irodsDestPath)
+	fs.invalidateCacheForFileUpdate(irodsPath)
+	fs.cachePropagation.PropagateFileUpdate(irodsPath)
 	return nil
 }
 
-// TruncateFile truncates a file
-func (fs *FileSystem) TruncateFile(path string, size int64) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+// TouchFile sets modify time of a file
+func (fs *FileSystem) TouchFile(path string, modifyTime time.Time) (err error) {
+	defer fs.audit("TouchFile", path, &err)
 
-	if size < 0 {
-		size = 0
-	}
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -732,7 +1187,7 @@ func (fs *FileSystem) TruncateFile(path string, size int64) error {
 	}
 	defer fs.metaSession.ReturnConnection(conn)
 
-	err = irods_fs.TruncateDataObject(conn, irodsPath, size)
+	err = irods_fs.TouchDataObject(conn, irodsPath, modifyTime)
 	if err != nil {
 		return err
 	}
@@ -743,8 +1198,13 @@ func (fs *FileSystem) TruncateFile(path string, size int64) error {
 }
 
 // ReplicateFile replicates a file
-func (fs *FileSystem) ReplicateFile(path string, resource string, update bool) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) ReplicateFile(path string, resource string, update bool) (err error) {
+	defer fs.audit("ReplicateFile", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -762,16 +1222,108 @@ func (fs *FileSystem) ReplicateFile(path string, resource string, update bool) e
 	return nil
 }
 
+// ReplicateFileToResources replicates a file to each of the resources using a connection
+// replication continues when it fails for a resource, returns types.ReplicationError having errors of failed resources
+func (fs *FileSystem) ReplicateFileToResources(path string, resources []string, update bool) (err error) {
+	defer fs.audit("ReplicateFile", path, &err)
+
+	if len(resources) == 0 {
+		return xerrors.Errorf("failed to replicate %s, no resources are given", path)
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	targets := []string{}
+	targetSet := map[string]bool{}
+	for _, resource := range resources {
+		if !targetSet[resource] {
+			targetSet[resource] = true
+			targets = append(targets, resource)
+		}
+	}
+
+	errs := map[string]error{}
+	for _, resource := range targets {
+		replErr := irods_fs.ReplicateDataObject(conn, irodsPath, resource, update, false)
+		if replErr != nil {
+			errs[resource] = replErr
+		}
+	}
+
+	if len(errs) < len(targets) {
+		// replicated to some
+		fs.invalidateCacheForFileUpdate(irodsPath)
+		fs.cachePropagation.PropagateFileUpdate(irodsPath)
+	}
+
+	if len(errs) > 0 {
+		return types.NewReplicationError(irodsPath, targets, errs)
+	}
+	return nil
+}
+
+// MoveReplica moves a replica of a file from the source resource to the dest resource without changing the path
+func (fs *FileSystem) MoveReplica(path string, srcResource string, destResource string) (err error) {
+	defer fs.audit("MoveReplica", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.PhysicalMoveDataObject(conn, irodsPath, srcResource, destResource, false)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForFileUpdate(irodsPath)
+	fs.cachePropagation.PropagateFileUpdate(irodsPath)
+	return nil
+}
+
 // OpenFile opens an existing file for read/write
-func (fs *FileSystem) OpenFile(path string, resource string, mode string) (*FileHandle, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) OpenFile(path string, resource string, mode string) (_ *FileHandle, err error) {
+	if types.FileOpenMode(mode).IsWrite() {
+		defer fs.audit("OpenFile", path, &err)
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	if !types.FileOpenMode(mode).IsOpeningExisting() {
+		// truncates the file
+		err := fs.keepFileVersionIfExists(irodsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	// make room for the file if all connections are in use
+	fs.parkLeastRecentlyUsedFileHandle(nil)
 
 	conn, err := fs.ioSession.AcquireConnection()
 	if err != nil {
 		return nil, err
 	}
 
-	handle, offset, err := irods_fs.OpenDataObject(conn, irodsPath, resource, mode)
+	handle, offset, err := fs.openDataObject(conn, irodsPath, resource, mode)
 	if err != nil {
 		fs.ioSession.ReturnConnection(conn)
 		return nil, err
@@ -789,17 +1341,20 @@ func (fs *FileSystem) OpenFile(path string, resource string, mode string) (*File
 
 	if entry == nil {
 		// create a new
+		account := fs.getAccount()
 		entry = &Entry{
 			ID:                0,
 			Type:              FileEntry,
 			Name:              util.GetIRODSPathFileName(irodsPath),
 			Path:              irodsPath,
-			Owner:             fs.account.ClientUser,
+			Owner:             account.ClientUser,
+			OwnerZone:         account.ClientZone,
 			Size:              0,
 			CreateTime:        time.Now(),
 			ModifyTime:        time.Now(),
 			CheckSumAlgorithm: types.ChecksumAlgorithmUnknown,
 			CheckSum:          nil,
+			ReplicaCount:      1,
 		}
 	}
 
@@ -812,15 +1367,30 @@ func (fs *FileSystem) OpenFile(path string, resource string, mode string) (*File
 		entry:           entry,
 		offset:          offset,
 		openMode:        types.FileOpenMode(mode),
+		lastAccess:      time.Now(),
 	}
+	atomic.AddInt64(&fs.fileHandleConnections, 1)
 
 	fs.fileHandleMap.Add(fileHandle)
 	return fileHandle, nil
 }
 
 // CreateFile opens a new file for write
-func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*FileHandle, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) CreateFile(path string, resource string, mode string) (_ *FileHandle, err error) {
+	defer fs.audit("CreateFile", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	err = fs.keepFileVersionIfExists(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	// make room for the file if all connections are in use
+	fs.parkLeastRecentlyUsedFileHandle(nil)
 
 	conn, err := fs.ioSession.AcquireConnection()
 	if err != nil {
@@ -863,7 +1433,9 @@ func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*Fi
 		entry:           entry,
 		offset:          offset,
 		openMode:        types.FileOpenMode(mode),
+		lastAccess:      time.Now(),
 	}
+	atomic.AddInt64(&fs.fileHandleConnections, 1)
 
 	fs.fileHandleMap.Add(fileHandle)
 	fs.invalidateCacheForFileCreate(irodsPath)
@@ -875,7 +1447,7 @@ func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*Fi
 // getCollectionNoCache returns collection entry
 func (fs *FileSystem) getCollectionNoCache(path string) (*Entry, error) {
 	// retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
+	conn, err := fs.metaSession.AcquireConnectionWithPriority(session.OperationPriorityInteractive)
 	if err != nil {
 		return nil, err
 	}
@@ -933,12 +1505,14 @@ func (fs *FileSystem) getEntryFromCollection(collection *types.IRODSCollection)
 		Name:              collection.Name,
 		Path:              collection.Path,
 		Owner:             collection.Owner,
+		OwnerZone:         collection.OwnerZone,
 		Size:              0,
 		DataType:          "",
 		CreateTime:        collection.CreateTime,
 		ModifyTime:        collection.ModifyTime,
 		CheckSumAlgorithm: types.ChecksumAlgorithmUnknown,
 		CheckSum:          nil,
+		Inheritance:       collection.Inheritance,
 	}
 }
 
@@ -959,15 +1533,66 @@ func (fs *FileSystem) getEntryFromDataObject(dataobject *types.IRODSDataObject)
 		Name:              dataobject.Name,
 		Path:              dataobject.Path,
 		Owner:             dataobject.Replicas[0].Owner,
+		OwnerZone:         dataobject.Replicas[0].OwnerZone,
 		Size:              dataobject.Size,
 		DataType:          dataobject.DataType,
 		CreateTime:        dataobject.Replicas[0].CreateTime,
 		ModifyTime:        dataobject.Replicas[0].ModifyTime,
 		CheckSumAlgorithm: checksumAlgorithm,
 		CheckSum:          checksumString,
+		ReplicaCount:      dataobject.GetReplicaCount(),
+		HasStaleReplica:   dataobject.HasStaleReplica(),
+		OnlyStaleReplicas: !dataobject.HasGoodReplica(),
 	}
 }
 
+// listEntriesWithReplicas lists entries in a collection, replicas listed are attached to file entries if requested
+// entries are not cached as cached entries do not have replicas and are made from master replicas
+func (fs *FileSystem) listEntriesWithReplicas(collection *types.IRODSCollection, options *ListOptions) ([]*Entry, error) {
+	conn, err := fs.metaSession.AcquireConnectionWithPriority(session.OperationPriorityInteractive)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	collections, err := irods_fs.ListSubCollections(conn, collection.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{}
+	for _, coll := range collections {
+		entries = append(entries, fs.getEntryFromCollection(coll))
+	}
+
+	var dataobjects []*types.IRODSDataObject
+	switch {
+	case len(options.Resource) > 0:
+		dataobjects, err = irods_fs.ListDataObjectsForResource(conn, collection, options.Resource)
+	case options.FilterByReplicaNumber:
+		dataobjects, err = irods_fs.ListDataObjectsForReplica(conn, collection, options.ReplicaNumber)
+	default:
+		dataobjects, err = irods_fs.ListDataObjects(conn, collection)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dataobject := range dataobjects {
+		if len(dataobject.Replicas) == 0 {
+			continue
+		}
+
+		entry := fs.getEntryFromDataObject(dataobject)
+		if options.WithReplicas {
+			entry.Replicas = dataobject.Replicas
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
 // listEntries lists entries in a collection
 func (fs *FileSystem) listEntries(collection *types.IRODSCollection) ([]*Entry, error) {
 	// check cache first
@@ -996,7 +1621,7 @@ func (fs *FileSystem) listEntries(collection *types.IRODSCollection) ([]*Entry,
 	}
 
 	// otherwise, retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
+	conn, err := fs.metaSession.AcquireConnectionWithPriority(session.OperationPriorityInteractive)
 	if err != nil {
 		return nil, err
 	}
@@ -1073,6 +1698,80 @@ func (fs *FileSystem) getDataObjectWithConnectionNoCache(conn *connection.IRODSC
 	return nil, xerrors.Errorf("failed to find the data object for path %s: %w", path, types.NewFileNotFoundError(path))
 }
 
+// getFileCloseResult returns the state of the replica of the data object in the catalog, and caches the data object
+// the master replica is used if the replica number is negative or not found
+func (fs *FileSystem) getFileCloseResult(path string, replicaNumber int64, resourceHierarchy string) (*FileCloseResult, error) {
+	collectionEntry, err := fs.getCollection(util.GetIRODSPathDirname(path))
+	if err != nil {
+		return nil, err
+	}
+
+	collection := fs.getCollectionFromEntry(collectionEntry)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	dataobject, err := irods_fs.GetDataObject(conn, collection, util.GetIRODSPathFileName(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if dataobject.ID <= 0 || len(dataobject.Replicas) == 0 {
+		return nil, xerrors.Errorf("failed to find the data object for path %s: %w", path, types.NewFileNotFoundError(path))
+	}
+
+	var replica *types.IRODSReplica
+	for _, objReplica := range dataobject.Replicas {
+		if objReplica.Number == replicaNumber {
+			replica = objReplica
+			break
+		}
+	}
+
+	if replica == nil {
+		// master replica is a good replica if the data object has any
+		replica = dataobject.Replicas[0]
+		for _, objReplica := range dataobject.Replicas {
+			if objReplica.IsGood() {
+				replica = objReplica
+				break
+			}
+		}
+	}
+
+	if replica.IsGood() {
+		// the entry is of the master replica
+		masterReplicaObject := *dataobject
+		masterReplicaObject.Replicas = []*types.IRODSReplica{replica}
+
+		entry := fs.getEntryFromDataObject(&masterReplicaObject)
+
+		fs.cache.RemoveNegativeEntryCache(path)
+		fs.cache.AddEntryCache(entry)
+	}
+
+	if len(replica.ResourceHierarchy) > 0 {
+		resourceHierarchy = replica.ResourceHierarchy
+	}
+
+	var checksum *types.IRODSChecksum
+	if replica.Checksum != nil && len(replica.Checksum.Checksum) > 0 {
+		checksum = replica.Checksum
+	}
+
+	return &FileCloseResult{
+		Path:              dataobject.Path,
+		Size:              dataobject.Size,
+		ReplicaNumber:     replica.Number,
+		ResourceHierarchy: resourceHierarchy,
+		Checksum:          checksum,
+		ModifyTime:        replica.ModifyTime,
+	}, nil
+}
+
 // getDataObjectWithConnection returns an entry for data object
 func (fs *FileSystem) getDataObjectWithConnection(conn *connection.IRODSConnection, path string) (*Entry, error) {
 	if fs.cache.HasNegativeEntryCache(path) {
@@ -1099,7 +1798,7 @@ func (fs *FileSystem) getDataObjectNoCache(path string) (*Entry, error) {
 
 	collection := fs.getCollectionFromEntry(collectionEntry)
 
-	conn, err := fs.metaSession.AcquireConnection()
+	conn, err := fs.metaSession.AcquireConnectionWithPriority(session.OperationPriorityInteractive)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,9 @@ package fs
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
@@ -25,9 +27,83 @@ func (fs *FileSystem) ListACLs(path string) ([]*types.IRODSAccess, error) {
 	return nil, xerrors.Errorf("unknown type - %s", stat.Type)
 }
 
+// Access returns the effective access level of a user for the path, resolving ACLs given to the user and groups the user belongs to
+// for a path not existing, the access level for the parent directory is returned as it governs creating the path
+// user is given as "name" or "name#zone", the client zone is used if zone is not given
+func (fs *FileSystem) Access(path string, user string) (types.IRODSAccessLevelType, error) {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	userName, userZone, err := fs.splitUserZone(user)
+	if err != nil {
+		return types.IRODSAccessLevelNull, err
+	}
+
+	targetPath := irodsPath
+	_, err = fs.Stat(irodsPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) {
+			return types.IRODSAccessLevelNull, err
+		}
+
+		// creating the path is governed by access to the parent dir
+		targetPath = util.GetIRODSPathDirname(irodsPath)
+	}
+
+	accesses, err := fs.ListACLs(targetPath)
+	if err != nil {
+		return types.IRODSAccessLevelNull, err
+	}
+
+	groupNames, err := fs.getUserGroupNames(userName)
+	if err != nil {
+		return types.IRODSAccessLevelNull, err
+	}
+
+	return getEffectiveAccessLevel(accesses, userName, userZone, groupNames), nil
+}
+
+// splitUserZone splits a user given as "name" or "name#zone" into the name and the zone, the client zone if not given
+func (fs *FileSystem) splitUserZone(user string) (string, string, error) {
+	identity, err := types.ParseIRODSUserIdentity(user, fs.getAccount().ClientZone)
+	if err != nil {
+		return "", "", err
+	}
+	return identity.Name, identity.Zone, nil
+}
+
+// getUserGroupNames returns names of groups that a user belongs to
+func (fs *FileSystem) getUserGroupNames(userName string) (map[string]bool, error) {
+	groups, err := fs.ListUserGroups(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames := map[string]bool{}
+	for _, group := range groups {
+		groupNames[group.Name] = true
+	}
+	return groupNames, nil
+}
+
+// getEffectiveAccessLevel returns the highest access level in the accesses given to the user or groups the user belongs to
+func getEffectiveAccessLevel(accesses []*types.IRODSAccess, userName string, userZone string, groupNames map[string]bool) types.IRODSAccessLevelType {
+	accessLevel := types.IRODSAccessLevelNull
+	for _, access := range accesses {
+		userMatched := access.UserName == userName && access.UserZone == userZone
+		if !userMatched && !groupNames[access.UserName] {
+			continue
+		}
+
+		if !accessLevel.IsEqualOrHigherThan(access.AccessLevel) {
+			accessLevel = access.AccessLevel
+		}
+	}
+	return accessLevel
+}
+
 // ListACLsForEntries returns ACLs for entries in a collection
 func (fs *FileSystem) ListACLsForEntries(path string) ([]*types.IRODSAccess, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
 
 	collectionEntry, err := fs.getCollection(irodsPath)
 	if err != nil {
@@ -70,7 +146,7 @@ func (fs *FileSystem) ListACLsWithGroupUsers(path string) ([]*types.IRODSAccess,
 
 // GetDirACLInheritance returns ACL inheritance of a directory
 func (fs *FileSystem) GetDirACLInheritance(path string) (*types.IRODSAccessInheritance, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
 
 	// retrieve it
 	conn, err := fs.metaSession.AcquireConnection()
@@ -89,7 +165,7 @@ func (fs *FileSystem) GetDirACLInheritance(path string) (*types.IRODSAccessInher
 
 // ListDirACLs returns ACLs of a directory
 func (fs *FileSystem) ListDirACLs(path string) ([]*types.IRODSAccess, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
 
 	// check cache first
 	cachedAccesses := fs.cache.GetACLsCache(irodsPath)
@@ -161,7 +237,7 @@ func (fs *FileSystem) ListDirACLsWithGroupUsers(path string) ([]*types.IRODSAcce
 
 // ListFileACLs returns ACLs of a file
 func (fs *FileSystem) ListFileACLs(path string) ([]*types.IRODSAccess, error) {
-	irodsPath := util.GetCorrectIRODSPath(path)
+	irodsPath := fs.getCorrectIRODSPath(path)
 
 	// check cache first
 	cachedAccesses := fs.cache.GetACLsCache(irodsPath)
@@ -322,9 +398,17 @@ func (fs *FileSystem) listACLsForEntries(collection *types.IRODSCollection) ([]*
 	// list access
 	dirEntryPathsAdded := map[string]bool{}
 
+	// ACLs the server refuses under strict ACLs are left out, not cached
+	skipped := false
+
 	collectionAccesses, err := irods_fs.ListAccessesForSubCollections(conn, collection.Path)
 	if err != nil {
-		return nil, err
+		if !fs.isSkippableACLError(conn, err) {
+			return nil, err
+		}
+
+		collectionAccesses = []*types.IRODSAccess{}
+		skipped = true
 	}
 
 	accesses := []*types.IRODSAccess{}
@@ -336,7 +420,12 @@ func (fs *FileSystem) listACLsForEntries(collection *types.IRODSCollection) ([]*
 
 	dataobjectAccesses, err := irods_fs.ListAccessesForDataObjects(conn, collection)
 	if err != nil {
-		return nil, err
+		if !fs.isSkippableACLError(conn, err) {
+			return nil, err
+		}
+
+		dataobjectAccesses = []*types.IRODSAccess{}
+		skipped = true
 	}
 
 	accesses = append(accesses, dataobjectAccesses...)
@@ -344,6 +433,10 @@ func (fs *FileSystem) listACLsForEntries(collection *types.IRODSCollection) ([]*
 	// cache it
 	fs.cache.AddACLsCacheMulti(dataobjectAccesses)
 
+	if skipped {
+		return accesses, nil
+	}
+
 	for _, acc := range accesses {
 		dirEntryPathsAdded[acc.Path] = true
 	}
@@ -359,3 +452,289 @@ func (fs *FileSystem) listACLsForEntries(collection *types.IRODSCollection) ([]*
 
 	return accesses, nil
 }
+
+// ChangeACLOptions is options for changing an ACL
+type ChangeACLOptions struct {
+	// apply to all files and dirs under the dir
+	Recursive bool
+	// change as an admin, requires a rodsadmin account
+	AdminFlag bool
+	// return actions to be performed without changing anything
+	DryRun bool
+	// go on with the remaining files and dirs when one fails, failures are reported in the BulkResult
+	// with Recursive, files and dirs are changed one by one instead of by a single recursive request
+	ContinueOnError bool
+}
+
+// ChangeACL changes the access level of a user or a group to a file or a dir, IRODSAccessLevelNull removes the access
+// zone is optional, client zone is used if empty
+func (fs *FileSystem) ChangeACL(path string, access types.IRODSAccessLevelType, user string, zone string) error {
+	_, err := fs.ChangeACLWithOptions(path, access, user, zone, nil)
+	return err
+}
+
+// ChangeACLWithOptions changes the access level with options, see ChangeACL
+// returns files and dirs changed, or to be changed in dry-run, parents first
+func (fs *FileSystem) ChangeACLWithOptions(path string, access types.IRODSAccessLevelType, user string, zone string, options *ChangeACLOptions) ([]*FileSystemAction, error) {
+	result, err := fs.ChangeACLWithResult(path, access, user, zone, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return nil, failed[0].Error
+	}
+	return result.Actions(), nil
+}
+
+// ChangeACLWithResult changes the access level as ChangeACLWithOptions, returns a result per file or dir, parents first
+// the error is for failures before changing any file or dir, failures of files and dirs are in the result
+// files and dirs changed by a single recursive request share the result of the request
+func (fs *FileSystem) ChangeACLWithResult(path string, access types.IRODSAccessLevelType, user string, zone string, options *ChangeACLOptions) (*BulkResult, error) {
+	if options == nil {
+		options = &ChangeACLOptions{}
+	}
+
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	entry, err := fs.Stat(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := fmt.Sprintf("%s#%s:%s", user, zone, access)
+
+	entries := []*Entry{entry}
+	if entry.Type == DirectoryEntry && options.Recursive {
+		dirs, files, err := fs.listTree(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(dirs, files...)
+	}
+
+	result := NewBulkResult()
+	if options.DryRun {
+		for _, e := range entries {
+			result.add(e.Path, FileSystemActionChangeACL, detail, 0, time.Time{}, nil)
+		}
+		return result, nil
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	if options.ContinueOnError && len(entries) > 1 {
+		for _, e := range entries {
+			startTime := time.Now()
+			if e.Type == DirectoryEntry {
+				err = irods_fs.ChangeCollectionAccess(conn, e.Path, access, user, zone, false, options.AdminFlag)
+			} else {
+				err = irods_fs.ChangeDataObjectAccess(conn, e.Path, access, user, zone, options.AdminFlag)
+			}
+
+			fs.RecordAudit("ChangeACL", e.Path, "", err)
+			result.add(e.Path, FileSystemActionChangeACL, detail, 0, startTime, err)
+			fs.cache.RemoveACLsCache(e.Path)
+		}
+		return result, nil
+	}
+
+	startTime := time.Now()
+	if entry.Type == DirectoryEntry {
+		err = irods_fs.ChangeCollectionAccess(conn, irodsPath, access, user, zone, options.Recursive, options.AdminFlag)
+	} else {
+		err = irods_fs.ChangeDataObjectAccess(conn, irodsPath, access, user, zone, options.AdminFlag)
+	}
+
+	fs.RecordAudit("ChangeACL", irodsPath, "", err)
+
+	duration := time.Since(startTime)
+	for _, e := range entries {
+		item := result.add(e.Path, FileSystemActionChangeACL, detail, 0, time.Time{}, err)
+		item.Duration = duration
+
+		if err == nil {
+			fs.cache.RemoveACLsCache(e.Path)
+		}
+	}
+
+	return result, nil
+}
+
+// ChangeOwnerOptions is options for changing the owner of a file or a dir
+type ChangeOwnerOptions struct {
+	// apply to all files and dirs under the dir
+	Recursive bool
+	// change as an admin, requires a rodsadmin account, needed to take over files of other users
+	AdminFlag bool
+	// keep own access of the previous owners, the access is removed if false
+	KeepPreviousOwners bool
+	// return actions to be performed without changing anything
+	DryRun bool
+}
+
+// ChangeOwner transfers ownership of a file or a dir to a user, as "ichmod own" followed by removing own access of other users
+// the owner name recorded in the catalog is not changed by the server, ownership is given by own access
+// zone is optional, client zone is used if empty
+func (fs *FileSystem) ChangeOwner(path string, user string, zone string) error {
+	_, err := fs.ChangeOwnerWithOptions(path, user, zone, nil)
+	return err
+}
+
+// ChangeOwnerWithOptions changes the owner with options, see ChangeOwner
+// returns files and dirs changed, or to be changed in dry-run, parents first, each followed by removals of previous owners
+func (fs *FileSystem) ChangeOwnerWithOptions(path string, user string, zone string, options *ChangeOwnerOptions) ([]*FileSystemAction, error) {
+	if options == nil {
+		options = &ChangeOwnerOptions{}
+	}
+
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	entry, err := fs.Stat(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{entry}
+	if entry.Type == DirectoryEntry && options.Recursive {
+		dirs, files, err := fs.listTree(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(dirs, files...)
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	previousOwners := map[string][]*types.IRODSAccess{}
+	if !options.KeepPreviousOwners {
+		previousOwners, err = listPreviousOwners(conn, entries, user, zone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	detail := fmt.Sprintf("%s#%s", user, zone)
+
+	actions := []*FileSystemAction{}
+	for _, e := range entries {
+		actions = append(actions, newFileSystemAction(FileSystemActionChangeOwner, e.Path, detail))
+
+		for _, access := range previousOwners[e.Path] {
+			accessDetail := fmt.Sprintf("%s#%s:%s", access.UserName, access.UserZone, types.IRODSAccessLevelNull)
+			actions = append(actions, newFileSystemAction(FileSystemActionChangeACL, e.Path, accessDetail))
+		}
+	}
+
+	if options.DryRun {
+		return actions, nil
+	}
+
+	err = fs.changeOwner(conn, entry, entries, user, zone, previousOwners, options)
+
+	fs.RecordAudit("ChangeOwner", irodsPath, "", err)
+
+	for _, e := range entries {
+		fs.cache.RemoveACLsCache(e.Path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// changeOwner gives own access to the user on the entry, then removes access of previous owners of the entries
+func (fs *FileSystem) changeOwner(conn *connection.IRODSConnection, entry *Entry, entries []*Entry, user string, zone string, previousOwners map[string][]*types.IRODSAccess, options *ChangeOwnerOptions) error {
+	var err error
+	if entry.Type == DirectoryEntry {
+		err = irods_fs.ChangeCollectionAccess(conn, entry.Path, types.IRODSAccessLevelOwner, user, zone, options.Recursive, options.AdminFlag)
+	} else {
+		err = irods_fs.ChangeDataObjectAccess(conn, entry.Path, types.IRODSAccessLevelOwner, user, zone, options.AdminFlag)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		for _, access := range previousOwners[e.Path] {
+			if e.Type == DirectoryEntry {
+				err = irods_fs.ChangeCollectionAccess(conn, e.Path, types.IRODSAccessLevelNull, access.UserName, access.UserZone, false, options.AdminFlag)
+			} else {
+				err = irods_fs.ChangeDataObjectAccess(conn, e.Path, types.IRODSAccessLevelNull, access.UserName, access.UserZone, options.AdminFlag)
+			}
+
+			if err != nil {
+				return xerrors.Errorf("failed to remove access of previous owner %s#%s from %s: %w", access.UserName, access.UserZone, e.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// listPreviousOwners returns own accesses of users other than the new owner, keyed by path of the entries
+// groups having own access are kept
+func listPreviousOwners(conn *connection.IRODSConnection, entries []*Entry, user string, zone string) (map[string][]*types.IRODSAccess, error) {
+	dirPaths := []string{}
+	filePaths := []string{}
+	for _, e := range entries {
+		if e.Type == DirectoryEntry {
+			dirPaths = append(dirPaths, e.Path)
+		} else {
+			filePaths = append(filePaths, e.Path)
+		}
+	}
+
+	accesses := []*types.IRODSAccess{}
+	if len(dirPaths) > 0 {
+		dirAccesses, err := irods_fs.ListAccessesForCollectionPaths(conn, dirPaths)
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, dirAccesses...)
+	}
+
+	if len(filePaths) > 0 {
+		fileAccesses, err := irods_fs.ListAccessesForDataObjectPaths(conn, filePaths)
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, fileAccesses...)
+	}
+
+	previousOwners := map[string][]*types.IRODSAccess{}
+	for _, access := range accesses {
+		if access.AccessLevel != types.IRODSAccessLevelOwner || access.UserType == types.IRODSUserRodsGroup {
+			continue
+		}
+
+		if access.UserName == user && access.UserZone == zone {
+			continue
+		}
+
+		previousOwners[access.Path] = append(previousOwners[access.Path], access)
+	}
+
+	return previousOwners, nil
+}
@@ -0,0 +1,143 @@
+package fs
+
+import (
+	"fmt"
+	"sort"
+
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// PermissionReportEntry is ACLs of a file or a directory in a permission report
+type PermissionReportEntry struct {
+	Path     string
+	Type     EntryType
+	Accesses []*types.IRODSAccess
+}
+
+// PermissionReport is ACLs of all files and directories under a directory
+type PermissionReport struct {
+	RootPath string
+	// entries ordered by path
+	Entries []*PermissionReportEntry
+}
+
+// GetAccessesByUser returns accesses in the report grouped by user or group, keyed by "name#zone"
+func (report *PermissionReport) GetAccessesByUser() map[string][]*types.IRODSAccess {
+	accessesByUser := map[string][]*types.IRODSAccess{}
+	for _, entry := range report.Entries {
+		for _, access := range entry.Accesses {
+			key := fmt.Sprintf("%s#%s", access.UserName, access.UserZone)
+			accessesByUser[key] = append(accessesByUser[key], access)
+		}
+	}
+	return accessesByUser
+}
+
+// GetPermissionReport walks a directory tree and returns ACLs of all files and directories under it
+// ACLs are retrieved per directory, for its sub-directories and files at once
+func (fs *FileSystem) GetPermissionReport(path string) (*PermissionReport, error) {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	rootEntry, err := fs.StatDir(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// entries are reported without accesses the server refuses under strict ACLs
+	rootAccesses, err := fs.ListDirACLs(irodsPath)
+	if err != nil {
+		if !types.IsPermissionError(err) || !fs.IsStrictACLs() {
+			return nil, err
+		}
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	report := &PermissionReport{
+		RootPath: irodsPath,
+		Entries: []*PermissionReportEntry{
+			{
+				Path:     rootEntry.Path,
+				Type:     DirectoryEntry,
+				Accesses: rootAccesses,
+			},
+		},
+	}
+
+	collections := []*types.IRODSCollection{fs.getCollectionFromEntry(rootEntry)}
+	for idx := 0; idx < len(collections); idx++ {
+		collection := collections[idx]
+
+		subCollections, err := irods_fs.ListSubCollections(conn, collection.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		subCollectionAccesses, err := irods_fs.ListAccessesForSubCollections(conn, collection.Path)
+		if err != nil {
+			if !fs.isSkippableACLError(conn, err) {
+				return nil, err
+			}
+		}
+
+		dirAccesses := groupAccessesByPath(subCollectionAccesses)
+		for _, subCollection := range subCollections {
+			report.Entries = append(report.Entries, &PermissionReportEntry{
+				Path:     subCollection.Path,
+				Type:     DirectoryEntry,
+				Accesses: dirAccesses[subCollection.Path],
+			})
+		}
+		collections = append(collections, subCollections...)
+
+		dataObjectAccesses, err := irods_fs.ListAccessesForDataObjects(conn, collection)
+		if err != nil {
+			if !fs.isSkippableACLError(conn, err) {
+				return nil, err
+			}
+
+			// files are known from accesses, list them instead
+			dataObjects, err := irods_fs.ListDataObjectsMasterReplica(conn, collection)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, dataObject := range dataObjects {
+				report.Entries = append(report.Entries, &PermissionReportEntry{
+					Path: dataObject.Path,
+					Type: FileEntry,
+				})
+			}
+			continue
+		}
+
+		fileAccesses := groupAccessesByPath(dataObjectAccesses)
+		for filePath, accesses := range fileAccesses {
+			report.Entries = append(report.Entries, &PermissionReportEntry{
+				Path:     filePath,
+				Type:     FileEntry,
+				Accesses: accesses,
+			})
+		}
+	}
+
+	sort.Slice(report.Entries, func(i int, j int) bool {
+		return report.Entries[i].Path < report.Entries[j].Path
+	})
+
+	return report, nil
+}
+
+// groupAccessesByPath groups accesses by path
+func groupAccessesByPath(accesses []*types.IRODSAccess) map[string][]*types.IRODSAccess {
+	accessesByPath := map[string][]*types.IRODSAccess{}
+	for _, access := range accesses {
+		accessesByPath[access.Path] = append(accessesByPath[access.Path], access)
+	}
+	return accessesByPath
+}
@@ -0,0 +1,54 @@
+package fs
+
+import (
+	"fmt"
+)
+
+// FileSystemActionType is a type of a change made by an operation
+type FileSystemActionType string
+
+const (
+	// FileSystemActionMakeDir is for creating a dir
+	FileSystemActionMakeDir FileSystemActionType = "make_dir"
+	// FileSystemActionRemoveDir is for removing a dir
+	FileSystemActionRemoveDir FileSystemActionType = "remove_dir"
+	// FileSystemActionCopyFile is for copying a file
+	FileSystemActionCopyFile FileSystemActionType = "copy_file"
+	// FileSystemActionRemoveFile is for removing a file
+	FileSystemActionRemoveFile FileSystemActionType = "remove_file"
+	// FileSystemActionChangeACL is for changing an ACL of a file or a dir
+	FileSystemActionChangeACL FileSystemActionType = "change_acl"
+	// FileSystemActionChangeOwner is for changing the owner of a file or a dir
+	FileSystemActionChangeOwner FileSystemActionType = "change_owner"
+	// FileSystemActionAddMetadata is for adding a metadata to a file or a dir
+	FileSystemActionAddMetadata FileSystemActionType = "add_metadata"
+	// FileSystemActionDeleteMetadata is for deleting a metadata from a file or a dir
+	FileSystemActionDeleteMetadata FileSystemActionType = "delete_metadata"
+	// FileSystemActionUpdateMetadata is for adding and deleting metadata of a file or a dir to match others
+	FileSystemActionUpdateMetadata FileSystemActionType = "update_metadata"
+)
+
+// FileSystemAction is a change made by an operation, or to be made in dry-run
+type FileSystemAction struct {
+	Type FileSystemActionType
+	Path string
+	// details of the change, e.g., the ACL or the metadata
+	Detail string
+}
+
+// newFileSystemAction creates a FileSystemAction
+func newFileSystemAction(actionType FileSystemActionType, path string, detail string) *FileSystemAction {
+	return &FileSystemAction{
+		Type:   actionType,
+		Path:   path,
+		Detail: detail,
+	}
+}
+
+// ToString stringifies the object
+func (action *FileSystemAction) ToString() string {
+	if len(action.Detail) == 0 {
+		return fmt.Sprintf("<FileSystemAction %s %s>", action.Type, action.Path)
+	}
+	return fmt.Sprintf("<FileSystemAction %s %s %s>", action.Type, action.Path, action.Detail)
+}
@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// AuditResult is a result of an audited operation
+type AuditResult string
+
+const (
+	// AuditResultSuccess is for an operation succeeded
+	AuditResultSuccess AuditResult = "success"
+	// AuditResultFailure is for an operation failed
+	AuditResultFailure AuditResult = "failure"
+)
+
+// AuditRecord is a record of a mutating operation performed through the file system
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// user#zone of the client user
+	Account string `json:"account"`
+	// name of the operation, e.g., RemoveFile
+	Operation string `json:"operation"`
+	// path of the target, or name of the target for user, group, resource and ticket operations
+	Path string `json:"path"`
+	// dest path for copy and rename operations
+	DestPath string      `json:"dest_path,omitempty"`
+	Result   AuditResult `json:"result"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// ToString stringifies the object
+func (record *AuditRecord) ToString() string {
+	return fmt.Sprintf("<AuditRecord %s %s %s %s %s %s %s>", record.Time.Format(time.RFC3339), record.Account, record.Operation, record.Path, record.DestPath, record.Result, record.Error)
+}
+
+// AuditHandler is a handler called with a record of every mutating operation
+// handlers are called synchronously, so they must not block
+type AuditHandler func(record *AuditRecord)
+
+// NewAuditWriterHandler creates an AuditHandler writing records to the writer as JSON lines
+func NewAuditWriterHandler(writer io.Writer) AuditHandler {
+	mutex := sync.Mutex{}
+	encoder := json.NewEncoder(writer)
+
+	return func(record *AuditRecord) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		// an audit trail failing to write must not fail the operation
+		_ = encoder.Encode(record)
+	}
+}
+
+// auditHandlerMap manages AuditHandlers
+type auditHandlerMap struct {
+	mutex    sync.RWMutex
+	handlers map[string]AuditHandler // ID-handler mapping
+}
+
+// newAuditHandlerMap creates a new auditHandlerMap
+func newAuditHandlerMap() *auditHandlerMap {
+	return &auditHandlerMap{
+		mutex:    sync.RWMutex{},
+		handlers: map[string]AuditHandler{},
+	}
+}
+
+// Release releases resources
+func (handlerMap *auditHandlerMap) Release() {
+	handlerMap.mutex.Lock()
+	defer handlerMap.mutex.Unlock()
+
+	handlerMap.handlers = map[string]AuditHandler{}
+}
+
+// AddHandler adds an audit handler
+func (handlerMap *auditHandlerMap) AddHandler(handler AuditHandler) string {
+	handlerID := xid.New().String()
+
+	handlerMap.mutex.Lock()
+	defer handlerMap.mutex.Unlock()
+
+	handlerMap.handlers[handlerID] = handler
+
+	return handlerID
+}
+
+// RemoveHandler removes an audit handler
+func (handlerMap *auditHandlerMap) RemoveHandler(handlerID string) {
+	handlerMap.mutex.Lock()
+	defer handlerMap.mutex.Unlock()
+
+	delete(handlerMap.handlers, handlerID)
+}
+
+// Send sends a record to all handlers
+func (handlerMap *auditHandlerMap) Send(record *AuditRecord) {
+	handlerMap.mutex.RLock()
+	defer handlerMap.mutex.RUnlock()
+
+	for _, handler := range handlerMap.handlers {
+		handler(record)
+	}
+}
+
+// IsEmpty returns true if there is no handler
+func (handlerMap *auditHandlerMap) IsEmpty() bool {
+	handlerMap.mutex.RLock()
+	defer handlerMap.mutex.RUnlock()
+
+	return len(handlerMap.handlers) == 0
+}
+
+// AddAuditHandler adds an audit handler, returns handler ID
+func (fs *FileSystem) AddAuditHandler(handler AuditHandler) string {
+	return fs.auditHandlerMap.AddHandler(handler)
+}
+
+// RemoveAuditHandler removes an audit handler
+func (fs *FileSystem) RemoveAuditHandler(handlerID string) {
+	fs.auditHandlerMap.RemoveHandler(handlerID)
+}
+
+// RecordAudit sends a record of a mutating operation to audit handlers
+// used by operations performed with connections of the file system outside of it, e.g., admin operations
+func (fs *FileSystem) RecordAudit(operation string, path string, destPath string, err error) {
+	if fs.auditHandlerMap.IsEmpty() {
+		return
+	}
+
+	account := fs.getAccount()
+	record := &AuditRecord{
+		Time:      time.Now(),
+		Account:   fmt.Sprintf("%s#%s", account.ClientUser, account.ClientZone),
+		Operation: operation,
+		Path:      path,
+		DestPath:  destPath,
+		Result:    AuditResultSuccess,
+	}
+
+	if err != nil {
+		record.Result = AuditResultFailure
+		record.Error = err.Error()
+	}
+
+	fs.auditHandlerMap.Send(record)
+}
+
+// audit records an operation on the path with the error it returns, called in defer with a pointer to the named error result
+func (fs *FileSystem) audit(operation string, path string, err *error) {
+	fs.RecordAudit(operation, fs.getCorrectIRODSPath(path), "", *err)
+}
+
+// auditWithDest records an operation having a dest path, see audit
+func (fs *FileSystem) auditWithDest(operation string, srcPath string, destPath string, err *error) {
+	fs.RecordAudit(operation, fs.getCorrectIRODSPath(srcPath), fs.getCorrectIRODSPath(destPath), *err)
+}
+
+// auditName records an operation on a named target not being a path, e.g., a ticket, see audit
+func (fs *FileSystem) auditName(operation string, name string, err *error) {
+	fs.RecordAudit(operation, name, "", *err)
+}
@@ -14,41 +14,16 @@ import (
 
 // DownloadFile downloads a file to local
 func (fs *FileSystem) DownloadFile(irodsPath string, resource string, localPath string, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
-	localDestPath := util.GetCorrectLocalPath(localPath)
-
-	localFilePath := localDestPath
-
-	srcStat, err := fs.Stat(irodsSrcPath)
-	if err != nil {
-		return xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
-	}
-
-	if srcStat.Type == DirectoryEntry {
-		return xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
-	}
-
-	destStat, err := os.Stat(localDestPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// file not exists, it's a file
-			// pass
-		} else {
-			return err
-		}
-	} else {
-		if destStat.IsDir() {
-			irodsFileName := util.GetIRODSPathFileName(irodsSrcPath)
-			localFilePath = filepath.Join(localDestPath, irodsFileName)
-		}
-	}
-
-	return irods_fs.DownloadDataObject(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
+	_, err := fs.DownloadFileWithOptions(irodsPath, localPath, &DownloadFileOptions{
+		Resource: resource,
+		Callback: callback,
+	})
+	return err
 }
 
 // DownloadFileResumable downloads a file to local with support of transfer resume
 func (fs *FileSystem) DownloadFileResumable(irodsPath string, resource string, localPath string, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
 	localDestPath := util.GetCorrectLocalPath(localPath)
 
 	localFilePath := localDestPath
@@ -77,12 +52,15 @@ func (fs *FileSystem) DownloadFileResumable(irodsPath string, resource string, l
 		}
 	}
 
+	taskNum := fs.acquireTransferTasks(srcStat.Size, 1)
+	defer fs.releaseTransferTasks(taskNum)
+
 	return irods_fs.DownloadDataObjectResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
 }
 
 // DownloadFileToBuffer downloads a file to buffer
 func (fs *FileSystem) DownloadFileToBuffer(irodsPath string, resource string, buffer bytes.Buffer, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
 
 	srcStat, err := fs.Stat(irodsSrcPath)
 	if err != nil {
@@ -93,12 +71,15 @@ func (fs *FileSystem) DownloadFileToBuffer(irodsPath string, resource string, bu
 		return xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
 	}
 
+	taskNum := fs.acquireTransferTasks(srcStat.Size, 1)
+	defer fs.releaseTransferTasks(taskNum)
+
 	return irods_fs.DownloadDataObjectToBuffer(fs.ioSession, irodsSrcPath, resource, buffer, srcStat.Size, callback)
 }
 
 // DownloadFileParallel downloads a file to local in parallel
 func (fs *FileSystem) DownloadFileParallel(irodsPath string, resource string, localPath string, taskNum int, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
 	localDestPath := util.GetCorrectLocalPath(localPath)
 
 	localFilePath := localDestPath
@@ -127,23 +108,27 @@ func (fs *FileSystem) DownloadFileParallel(irodsPath string, resource string, lo
 		}
 	}
 
+	taskNum = fs.acquireTransferTasks(srcStat.Size, taskNum)
+	defer fs.releaseTransferTasks(taskNum)
+
 	return irods_fs.DownloadDataObjectParallel(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
 }
 
-// DownloadFileParallelResumable downloads a file to local in parallel with support of transfer resume
-func (fs *FileSystem) DownloadFileParallelResumable(irodsPath string, resource string, localPath string, taskNum int, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
+// DownloadFileParallelWithVerify downloads a file to local in parallel, and verifies the downloaded file against the checksum of the iRODS file
+// blocks failed to download are retried, blocks of a file failed to verify are compared to the iRODS file and repaired
+func (fs *FileSystem) DownloadFileParallelWithVerify(irodsPath string, resource string, localPath string, taskNum int, verifyOptions *irods_fs.DownloadVerifyOptions, callback common.TrackerCallBack) (*irods_fs.DownloadVerifyResult, error) {
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
 	localDestPath := util.GetCorrectLocalPath(localPath)
 
 	localFilePath := localDestPath
 
 	srcStat, err := fs.Stat(irodsSrcPath)
 	if err != nil {
-		return xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
+		return nil, xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
 	}
 
 	if srcStat.Type == DirectoryEntry {
-		return xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
+		return nil, xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
 	}
 
 	destStat, err := os.Stat(localDestPath)
@@ -152,7 +137,7 @@ func (fs *FileSystem) DownloadFileParallelResumable(irodsPath string, resource s
 			// file not exists, it's a file
 			// pass
 		} else {
-			return err
+			return nil, err
 		}
 	} else {
 		if destStat.IsDir() {
@@ -161,12 +146,15 @@ func (fs *FileSystem) DownloadFileParallelResumable(irodsPath string, resource s
 		}
 	}
 
-	return irods_fs.DownloadDataObjectParallelResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
+	taskNum = fs.acquireTransferTasks(srcStat.Size, taskNum)
+	defer fs.releaseTransferTasks(taskNum)
+
+	return irods_fs.DownloadDataObjectParallelWithVerify(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, verifyOptions, callback)
 }
 
-// DownloadFileRedirectToResource downloads a file from resource to local in parallel
-func (fs *FileSystem) DownloadFileRedirectToResource(irodsPath string, resource string, localPath string, callback common.TrackerCallBack) error {
-	irodsSrcPath := util.GetCorrectIRODSPath(irodsPath)
+// DownloadFileParallelResumable downloads a file to local in parallel with support of transfer resume
+func (fs *FileSystem) DownloadFileParallelResumable(irodsPath string, resource string, localPath string, taskNum int, callback common.TrackerCallBack) error {
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
 	localDestPath := util.GetCorrectLocalPath(localPath)
 
 	localFilePath := localDestPath
@@ -195,59 +183,67 @@ func (fs *FileSystem) DownloadFileRedirectToResource(irodsPath string, resource
 		}
 	}
 
-	return irods_fs.DownloadDataObjectFromResourceServer(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
+	// a resumed transfer may use as many tasks as the transfer it resumes
+	taskNum = fs.acquireTransferTasks(srcStat.Size, taskNum)
+	defer fs.releaseTransferTasks(taskNum)
+
+	return irods_fs.DownloadDataObjectParallelResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
 }
 
-// UploadFile uploads a local file to irods
-func (fs *FileSystem) UploadFile(localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
-	localSrcPath := util.GetCorrectLocalPath(localPath)
-	irodsDestPath := util.GetCorrectIRODSPath(irodsPath)
+// DownloadFileRedirectToResource downloads a file from resource to local in parallel
+func (fs *FileSystem) DownloadFileRedirectToResource(irodsPath string, resource string, localPath string, callback common.TrackerCallBack) error {
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
+	localDestPath := util.GetCorrectLocalPath(localPath)
 
-	irodsFilePath := irodsDestPath
+	localFilePath := localDestPath
 
-	stat, err := os.Stat(localSrcPath)
+	srcStat, err := fs.Stat(irodsSrcPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// file not exists
-			return xerrors.Errorf("failed to find a file for local path %s: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
-		}
-		return err
+		return xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
 	}
 
-	if stat.IsDir() {
-		return xerrors.Errorf("failed to find a file for local path %s, the path is for a directory: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
+	if srcStat.Type == DirectoryEntry {
+		return xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
 	}
 
-	entry, err := fs.Stat(irodsDestPath)
+	destStat, err := os.Stat(localDestPath)
 	if err != nil {
-		if !types.IsFileNotFoundError(err) {
+		if os.IsNotExist(err) {
+			// file not exists, it's a file
+			// pass
+		} else {
 			return err
 		}
 	} else {
-		switch entry.Type {
-		case FileEntry:
-			// do nothing
-		case DirectoryEntry:
-			localFileName := filepath.Base(localSrcPath)
-			irodsFilePath = util.MakeIRODSPath(irodsDestPath, localFileName)
-		default:
-			return xerrors.Errorf("unknown entry type %s", entry.Type)
+		if destStat.IsDir() {
+			irodsFileName := util.GetIRODSPathFileName(irodsSrcPath)
+			localFilePath = filepath.Join(localDestPath, irodsFileName)
 		}
 	}
 
-	err = irods_fs.UploadDataObject(fs.ioSession, localSrcPath, irodsFilePath, resource, replicate, callback)
-	if err != nil {
-		return err
-	}
+	// the number of tasks is decided by the resource server, reserve as many as for a parallel transfer and request them
+	taskNum := fs.acquireTransferTasks(srcStat.Size, 0)
+	defer fs.releaseTransferTasks(taskNum)
 
-	fs.invalidateCacheForFileCreate(irodsFilePath)
-	fs.cachePropagation.PropagateFileCreate(irodsFilePath)
-	return nil
+	return irods_fs.DownloadDataObjectFromResourceServerWithThreads(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
+}
+
+// UploadFile uploads a local file to irods
+func (fs *FileSystem) UploadFile(localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
+	_, err := fs.UploadFileWithOptions(localPath, irodsPath, &UploadFileOptions{
+		Resource:        resource,
+		Replicate:       replicate,
+		OverwritePolicy: OverwritePolicyOverwrite,
+		Callback:        callback,
+	})
+	return err
 }
 
 // UploadFileFromBuffer uploads buffer data to irods
-func (fs *FileSystem) UploadFileFromBuffer(buffer bytes.Buffer, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
-	irodsDestPath := util.GetCorrectIRODSPath(irodsPath)
+func (fs *FileSystem) UploadFileFromBuffer(buffer bytes.Buffer, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) (err error) {
+	defer fs.audit("UploadFile", irodsPath, &err)
+
+	irodsDestPath := fs.getCorrectIRODSPath(irodsPath)
 
 	irodsFilePath := irodsDestPath
 
@@ -267,6 +263,12 @@ func (fs *FileSystem) UploadFileFromBuffer(buffer bytes.Buffer, irodsPath string
 		}
 	}
 
+	fs.pathLocks.Lock(irodsFilePath)
+	defer fs.pathLocks.Unlock(irodsFilePath)
+
+	taskNum := fs.acquireTransferTasks(int64(buffer.Len()), 1)
+	defer fs.releaseTransferTasks(taskNum)
+
 	err = irods_fs.UploadDataObjectFromBuffer(fs.ioSession, buffer, irodsFilePath, resource, replicate, callback)
 	if err != nil {
 		return err
@@ -278,9 +280,11 @@ func (fs *FileSystem) UploadFileFromBuffer(buffer bytes.Buffer, irodsPath string
 }
 
 // UploadFileParallel uploads a local file to irods in parallel
-func (fs *FileSystem) UploadFileParallel(localPath string, irodsPath string, resource string, taskNum int, replicate bool, callback common.TrackerCallBack) error {
+func (fs *FileSystem) UploadFileParallel(localPath string, irodsPath string, resource string, taskNum int, replicate bool, callback common.TrackerCallBack) (err error) {
+	defer fs.audit("UploadFile", irodsPath, &err)
+
 	localSrcPath := util.GetCorrectLocalPath(localPath)
-	irodsDestPath := util.GetCorrectIRODSPath(irodsPath)
+	irodsDestPath := fs.getCorrectIRODSPath(irodsPath)
 
 	irodsFilePath := irodsDestPath
 
@@ -314,6 +318,12 @@ func (fs *FileSystem) UploadFileParallel(localPath string, irodsPath string, res
 		}
 	}
 
+	fs.pathLocks.Lock(irodsFilePath)
+	defer fs.pathLocks.Unlock(irodsFilePath)
+
+	taskNum = fs.acquireTransferTasks(srcStat.Size(), taskNum)
+	defer fs.releaseTransferTasks(taskNum)
+
 	err = irods_fs.UploadDataObjectParallel(fs.ioSession, localSrcPath, irodsFilePath, resource, taskNum, replicate, callback)
 	if err != nil {
 		return err
@@ -325,9 +335,11 @@ func (fs *FileSystem) UploadFileParallel(localPath string, irodsPath string, res
 }
 
 // UploadFileParallelRedirectToResource uploads a file from local to resource server in parallel
-func (fs *FileSystem) UploadFileParallelRedirectToResource(localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
+func (fs *FileSystem) UploadFileParallelRedirectToResource(localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) (err error) {
+	defer fs.audit("UploadFile", irodsPath, &err)
+
 	localSrcPath := util.GetCorrectLocalPath(localPath)
-	irodsDestPath := util.GetCorrectIRODSPath(irodsPath)
+	irodsDestPath := fs.getCorrectIRODSPath(irodsPath)
 
 	irodsFilePath := irodsDestPath
 
@@ -361,7 +373,14 @@ func (fs *FileSystem) UploadFileParallelRedirectToResource(localPath string, iro
 		}
 	}
 
-	err = irods_fs.UploadDataObjectToResourceServer(fs.ioSession, localSrcPath, irodsFilePath, resource, replicate, callback)
+	fs.pathLocks.Lock(irodsFilePath)
+	defer fs.pathLocks.Unlock(irodsFilePath)
+
+	// the number of tasks is decided by the resource server, reserve as many as for a parallel transfer and request them
+	taskNum := fs.acquireTransferTasks(srcStat.Size(), 0)
+	defer fs.releaseTransferTasks(taskNum)
+
+	err = irods_fs.UploadDataObjectToResourceServerWithThreads(fs.ioSession, localSrcPath, irodsFilePath, resource, taskNum, replicate, callback)
 	if err != nil {
 		return err
 	}
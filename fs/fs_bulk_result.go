@@ -0,0 +1,121 @@
+package fs
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// BulkItemResult is a result of a file or a dir processed by a bulk operation
+type BulkItemResult struct {
+	Path   string
+	Action FileSystemActionType
+	// details of the change, e.g., the ACL or the metadata
+	Detail string
+	// bytes transferred, zero if the action does not transfer data
+	Bytes    int64
+	Duration time.Duration
+	// nil if the item succeeded
+	Error error
+}
+
+// BulkResult is a result of a bulk operation, having a result per file or dir in the order they are processed
+// in dry-run, items are changes to be made
+type BulkResult struct {
+	Items []*BulkItemResult
+}
+
+// NewBulkResult creates a BulkResult
+func NewBulkResult() *BulkResult {
+	return &BulkResult{
+		Items: []*BulkItemResult{},
+	}
+}
+
+// add adds a result of an item processed from startTime, startTime may be zero for items not processed, e.g., in dry-run
+func (result *BulkResult) add(path string, action FileSystemActionType, detail string, bytes int64, startTime time.Time, err error) *BulkItemResult {
+	item := &BulkItemResult{
+		Path:   path,
+		Action: action,
+		Detail: detail,
+		Bytes:  bytes,
+		Error:  err,
+	}
+
+	if !startTime.IsZero() {
+		item.Duration = time.Since(startTime)
+	}
+
+	result.Items = append(result.Items, item)
+	return item
+}
+
+// Succeeded returns items succeeded
+func (result *BulkResult) Succeeded() []*BulkItemResult {
+	items := []*BulkItemResult{}
+	for _, item := range result.Items {
+		if item.Error == nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Failed returns items failed
+func (result *BulkResult) Failed() []*BulkItemResult {
+	items := []*BulkItemResult{}
+	for _, item := range result.Items {
+		if item.Error != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HasFailure returns true if any item failed
+func (result *BulkResult) HasFailure() bool {
+	for _, item := range result.Items {
+		if item.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalBytes returns bytes transferred by items succeeded
+func (result *BulkResult) TotalBytes() int64 {
+	total := int64(0)
+	for _, item := range result.Items {
+		if item.Error == nil {
+			total += item.Bytes
+		}
+	}
+	return total
+}
+
+// Err returns an error wrapping the error of the first failed item, nil if no item failed
+func (result *BulkResult) Err() error {
+	failed := result.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return xerrors.Errorf("%d of %d items failed, first at %s: %w", len(failed), len(result.Items), failed[0].Path, failed[0].Error)
+}
+
+// Actions returns actions of items succeeded
+func (result *BulkResult) Actions() []*FileSystemAction {
+	actions := []*FileSystemAction{}
+	for _, item := range result.Items {
+		if item.Error == nil {
+			actions = append(actions, newFileSystemAction(item.Action, item.Path, item.Detail))
+		}
+	}
+	return actions
+}
+
+// ToString stringifies the object
+func (result *BulkResult) ToString() string {
+	return fmt.Sprintf("<BulkResult %d items, %d failed, %d bytes>", len(result.Items), len(result.Failed()), result.TotalBytes())
+}
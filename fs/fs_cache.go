@@ -1,7 +1,12 @@
 package fs
 
 import (
+	"encoding/json"
+	"io"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
 )
 
 // ClearCache clears all file system caches
@@ -13,7 +18,70 @@ func (fs *FileSystem) ClearCache() {
 	fs.cache.ClearDirCache()
 }
 
+// SnapshotCache returns a snapshot of cached entries, dir listings, negative entries, metadata and ACLs with their ages
+func (fs *FileSystem) SnapshotCache() *CacheDump {
+	return fs.cache.dump()
+}
+
+// DumpCache writes a snapshot of caches to the writer in JSON, e.g., to debug stale views of long-running processes
+func (fs *FileSystem) DumpCache(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(fs.SnapshotCache())
+	if err != nil {
+		return xerrors.Errorf("failed to dump cache: %w", err)
+	}
+	return nil
+}
+
+// ClearUserGroupCache clears cached users and groups, e.g., after changing users or groups out of the file system
+func (fs *FileSystem) ClearUserGroupCache() {
+	fs.cache.ClearUserGroupCache()
+}
+
+// invalidateCacheForUserCreate invalidates cache for creation of the given user or group
+func (fs *FileSystem) invalidateCacheForUserCreate(username string, zone string, userType types.IRODSUserType) {
+	fs.cache.RemoveUserCache(username, zone)
+
+	if userType == types.IRODSUserRodsGroup {
+		fs.cache.RemoveGroupsCache()
+		fs.cache.RemoveGroupCache(username)
+	} else {
+		fs.cache.RemoveUsersCache()
+	}
+}
+
+// invalidateCacheForUserUpdate invalidates cache for update on type or info of the given user
+func (fs *FileSystem) invalidateCacheForUserUpdate(username string, zone string) {
+	fs.cache.RemoveUserCache(username, zone)
+	fs.cache.RemoveUsersCache()
+	fs.cache.RemoveGroupCache(username)
+
+	// members of groups are listed with their types
+	fs.cache.ClearGroupUsersCache()
+}
+
+// invalidateCacheForUserRemove invalidates cache for removal of the given user or group
+func (fs *FileSystem) invalidateCacheForUserRemove(username string, zone string) {
+	fs.cache.RemoveUserCache(username, zone)
+	fs.cache.RemoveUsersCache()
+	fs.cache.RemoveGroupsCache()
+
+	// the user is gone from groups, or the group is gone from its members
+	fs.cache.ClearGroupUsersCache()
+	fs.cache.ClearUserGroupsCache()
+	fs.cache.ClearGroupCache()
+}
+
+// invalidateCacheForGroupMemberUpdate invalidates cache for adding or removing the given user to or from the group
+func (fs *FileSystem) invalidateCacheForGroupMemberUpdate(group string, username string) {
+	fs.cache.RemoveGroupUsersCache(group)
+	fs.cache.RemoveUserGroupsCache(username)
+	fs.cache.RemoveGroupCache(group)
+}
+
 // AddCacheEventHandler adds cache event handler
+// handlers are called while the path is locked, so they must not call FileSystem operations on the same path synchronously
 func (fs *FileSystem) AddCacheEventHandler(handler FilesystemCacheEventHandler) string {
 	return fs.cacheEventHandlerMap.AddEventHandler(handler)
 }
@@ -0,0 +1,454 @@
+package fs
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// CopyFileOptions is options for copying a file
+type CopyFileOptions struct {
+	// how to handle an existing dest file, fails if empty
+	OverwritePolicy OverwritePolicy
+	// resource to store the dest file, the default resource is used if empty
+	Resource string
+	// compare checksums of the source and the dest file after copy, the dest file is removed if they differ
+	VerifyChecksum bool
+	// copy metadata (AVUs) of the source file
+	CopyMetadata bool
+	// copy ACLs of the source file, except the access of the client user who owns the copy
+	CopyACLs bool
+	// extra keywords sent with requests of the copy, e.g., RESC_HIER_STR_KW
+	Keywords map[common.KeyWord]string
+}
+
+// CopyFileWithOptions copies a file, the file is copied into destPath if destPath is an existing dir
+// returns a path of the copy
+func (fs *FileSystem) CopyFileWithOptions(srcPath string, destPath string, options *CopyFileOptions) (string, error) {
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	destFilePath := irodsDestPath
+	if fs.ExistsDir(irodsDestPath) {
+		// make full file name for dest
+		srcFileName := util.GetIRODSPathFileName(irodsSrcPath)
+		destFilePath = util.MakeIRODSPath(irodsDestPath, srcFileName)
+	}
+
+	return fs.CopyFileToFileWithOptions(irodsSrcPath, destFilePath, options)
+}
+
+// CopyFileToFileWithOptions copies a file to destPath
+// returns a path of the copy, which differs from destPath if the file is renamed by the overwrite policy
+func (fs *FileSystem) CopyFileToFileWithOptions(srcPath string, destPath string, options *CopyFileOptions) (_ string, err error) {
+	defer fs.auditWithDest("CopyFile", srcPath, destPath, &err)
+
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	if options == nil {
+		options = &CopyFileOptions{}
+	}
+
+	if !fs.ExistsFile(irodsSrcPath) {
+		return "", xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
+	}
+
+	// read attributes before locking paths, listing them locks paths too
+	var attributes *entryAttributes
+	if options.CopyMetadata || options.CopyACLs {
+		srcAttributes, err := fs.getEntryAttributes(irodsSrcPath, options.CopyMetadata, options.CopyACLs)
+		if err != nil {
+			return "", err
+		}
+		attributes = srcAttributes
+	}
+
+	irodsDestPath, force, err := fs.resolveOverwrite(irodsDestPath, options.OverwritePolicy)
+	if err != nil {
+		return "", err
+	}
+
+	lockedPaths := fs.pathLocks.LockFiles([]string{irodsSrcPath, irodsDestPath})
+	defer fs.pathLocks.UnlockFiles(lockedPaths)
+
+	conn, err := fs.metaSession.AcquireConnectionWithKeywords(options.Keywords)
+	if err != nil {
+		return "", err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.CopyDataObjectToResource(conn, irodsSrcPath, irodsDestPath, options.Resource, force)
+	if err != nil {
+		return "", err
+	}
+
+	fs.invalidateCacheForFileCreate(irodsDestPath)
+	fs.cachePropagation.PropagateFileCreate(irodsDestPath)
+
+	if options.VerifyChecksum {
+		err = verifyCopyChecksum(conn, irodsSrcPath, irodsDestPath, options.Resource)
+		if err != nil {
+			irods_fs.DeleteDataObject(conn, irodsDestPath, true)
+
+			fs.invalidateCacheForFileRemove(irodsDestPath)
+			fs.cachePropagation.PropagateFileRemove(irodsDestPath)
+			return "", err
+		}
+	}
+
+	if attributes != nil {
+		err = fs.setEntryAttributes(conn, irodsDestPath, false, attributes)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return irodsDestPath, nil
+}
+
+// verifyCopyChecksum returns an error if checksums of the source and the dest file differ
+func verifyCopyChecksum(conn *connection.IRODSConnection, srcPath string, destPath string, destResource string) error {
+	srcChecksum, err := irods_fs.GetDataObjectChecksum(conn, srcPath, "")
+	if err != nil {
+		return err
+	}
+
+	destChecksum, err := irods_fs.GetDataObjectChecksum(conn, destPath, destResource)
+	if err != nil {
+		return err
+	}
+
+	if srcChecksum.Algorithm != destChecksum.Algorithm || !bytes.Equal(srcChecksum.Checksum, destChecksum.Checksum) {
+		return xerrors.Errorf("failed to verify copy of %s, checksum of %s (%s) does not match (%s)", srcPath, destPath, destChecksum.IRODSChecksumString, srcChecksum.IRODSChecksumString)
+	}
+	return nil
+}
+
+// CopyDir copies a dir recursively, the dir is copied into destPath if destPath is an existing dir
+func (fs *FileSystem) CopyDir(srcPath string, destPath string, force bool, copyMetadata bool, copyACLs bool, callback common.TrackerCallBack) error {
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	destDirPath := irodsDestPath
+	if fs.ExistsDir(irodsDestPath) {
+		// make full dir name for dest
+		srcDirName := util.GetIRODSPathFileName(irodsSrcPath)
+		destDirPath = util.MakeIRODSPath(irodsDestPath, srcDirName)
+	}
+
+	return fs.CopyDirToDir(irodsSrcPath, destDirPath, force, copyMetadata, copyACLs, callback)
+}
+
+// CopyDirToDir copies a dir recursively to destPath using server-side copy of each file
+// existing files in destPath are overwritten only if force is set
+// callback is called with bytes copied and total bytes of files in the dir
+func (fs *FileSystem) CopyDirToDir(srcPath string, destPath string, force bool, copyMetadata bool, copyACLs bool, callback common.TrackerCallBack) (err error) {
+	defer fs.auditWithDest("CopyDir", srcPath, destPath, &err)
+
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := fs.getCorrectIRODSPath(destPath)
+
+	if irodsSrcPath == irodsDestPath || strings.HasPrefix(irodsDestPath, strings.TrimSuffix(irodsSrcPath, "/")+"/") {
+		return xerrors.Errorf("failed to copy dir %s to %s, cannot copy a dir into itself", irodsSrcPath, irodsDestPath)
+	}
+
+	srcEntry, err := fs.StatDir(irodsSrcPath)
+	if err != nil {
+		return err
+	}
+
+	dirs, files, err := fs.listTree(srcEntry)
+	if err != nil {
+		return err
+	}
+
+	totalSize := int64(0)
+	for _, file := range files {
+		totalSize += file.Size
+	}
+
+	// read attributes before locking paths, listing them locks paths too
+	attributes := map[string]*entryAttributes{}
+	if copyMetadata || copyACLs {
+		for _, entry := range append(dirs, files...) {
+			entryAttributes, err := fs.getEntryAttributes(entry.Path, copyMetadata, copyACLs)
+			if err != nil {
+				return err
+			}
+			attributes[entry.Path] = entryAttributes
+		}
+	}
+
+	if callback != nil {
+		callback(0, totalSize)
+	}
+
+	lockedPaths := fs.pathLocks.LockFiles([]string{irodsSrcPath, irodsDestPath})
+	defer fs.pathLocks.UnlockFiles(lockedPaths)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	// dirs are ordered parents first
+	for _, dir := range dirs {
+		destDirPath := getCopyDestPath(irodsSrcPath, irodsDestPath, dir.Path)
+
+		err = irods_fs.CreateCollection(conn, destDirPath, true)
+		if err != nil {
+			return err
+		}
+
+		fs.invalidateCacheForDirCreate(destDirPath)
+		fs.cachePropagation.PropagateDirCreate(destDirPath)
+
+		if dirAttributes, ok := attributes[dir.Path]; ok {
+			err = fs.setEntryAttributes(conn, destDirPath, true, dirAttributes)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	processed := int64(0)
+	for _, file := range files {
+		destFilePath := getCopyDestPath(irodsSrcPath, irodsDestPath, file.Path)
+
+		err = irods_fs.CopyDataObject(conn, file.Path, destFilePath, force)
+		if err != nil {
+			return err
+		}
+
+		fs.invalidateCacheForFileCreate(destFilePath)
+		fs.cachePropagation.PropagateFileCreate(destFilePath)
+
+		if fileAttributes, ok := attributes[file.Path]; ok {
+			err = fs.setEntryAttributes(conn, destFilePath, false, fileAttributes)
+			if err != nil {
+				return err
+			}
+		}
+
+		processed += file.Size
+		if callback != nil {
+			callback(processed, totalSize)
+		}
+	}
+
+	return nil
+}
+
+// listTree returns all dirs, including the given dir, and files under the dir
+// dirs are ordered parents first
+func (fs *FileSystem) listTree(dirEntry *Entry) ([]*Entry, []*Entry, error) {
+	dirs := []*Entry{dirEntry}
+	files := []*Entry{}
+
+	for idx := 0; idx < len(dirs); idx++ {
+		entries, err := fs.List(dirs[idx].Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Type == DirectoryEntry {
+				dirs = append(dirs, entry)
+			} else {
+				files = append(files, entry)
+			}
+		}
+	}
+
+	return dirs, files, nil
+}
+
+// getCopyDestPath returns a path under destRootPath that corresponds to srcPath under srcRootPath
+func getCopyDestPath(srcRootPath string, destRootPath string, srcPath string) string {
+	relPath := strings.TrimPrefix(srcPath, srcRootPath)
+	if len(relPath) == 0 {
+		return destRootPath
+	}
+	return util.MakeIRODSPath(destRootPath, strings.TrimPrefix(relPath, "/"))
+}
+
+// entryAttributes is metadata and ACLs of a file or a dir
+type entryAttributes struct {
+	metas    []*types.IRODSMeta
+	accesses []*types.IRODSAccess
+}
+
+// getEntryAttributes returns metadata and ACLs of a file or a dir to be copied
+func (fs *FileSystem) getEntryAttributes(path string, copyMetadata bool, copyACLs bool) (*entryAttributes, error) {
+	attributes := &entryAttributes{
+		metas:    []*types.IRODSMeta{},
+		accesses: []*types.IRODSAccess{},
+	}
+
+	if copyMetadata {
+		metas, err := fs.ListMetadata(path)
+		if err != nil {
+			return nil, err
+		}
+		attributes.metas = metas
+	}
+
+	if copyACLs {
+		accesses, err := fs.ListACLs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		account := fs.getAccount()
+		for _, access := range accesses {
+			// the copy is owned by the client user, changing its own access may lock the user out
+			if access.UserName == account.ClientUser && access.UserZone == account.ClientZone {
+				continue
+			}
+			attributes.accesses = append(attributes.accesses, access)
+		}
+	}
+
+	return attributes, nil
+}
+
+// setEntryAttributes adds metadata and ACLs to a file or a dir
+func (fs *FileSystem) setEntryAttributes(conn *connection.IRODSConnection, path string, isDir bool, attributes *entryAttributes) error {
+	for _, meta := range attributes.metas {
+		newMeta := &types.IRODSMeta{
+			Name:  meta.Name,
+			Value: meta.Value,
+			Units: meta.Units,
+		}
+
+		var err error
+		if isDir {
+			err = irods_fs.AddCollectionMeta(conn, path, newMeta)
+		} else {
+			err = irods_fs.AddDataObjectMeta(conn, path, newMeta)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, access := range attributes.accesses {
+		var err error
+		if isDir {
+			err = irods_fs.ChangeCollectionAccess(conn, path, access.AccessLevel, access.UserName, access.UserZone, false, false)
+		} else {
+			err = irods_fs.ChangeDataObjectAccess(conn, path, access.AccessLevel, access.UserName, access.UserZone, false)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	fs.cache.RemoveMetadataCache(path)
+	fs.cache.RemoveACLsCache(path)
+	return nil
+}
+
+// StreamCopyFileOptions is options for copying a file to another FileSystem
+type StreamCopyFileOptions struct {
+	// resource to read the source file from, the default resource of the source is used if empty
+	SourceResource string
+	// resource to store the dest file, the default resource of the dest is used if empty
+	Resource string
+	// how to handle an existing dest file, fails if empty
+	OverwritePolicy OverwritePolicy
+	// number of parallel streams, decided by the file size if zero
+	TaskNum  int
+	Callback common.TrackerCallBack
+}
+
+// StreamCopyFileToFileSystem copies a file to another FileSystem, e.g., of another zone or iRODS server
+// data is streamed through the client, for migrations between servers that cannot copy to each other
+// the file is copied into destPath if destPath is an existing dir of destFS
+// returns a path of the copy, which differs from destPath if the file is renamed by the overwrite policy
+func (fs *FileSystem) StreamCopyFileToFileSystem(srcPath string, destFS *FileSystem, destPath string, options *StreamCopyFileOptions) (_ string, err error) {
+	defer fs.auditWithDest("CopyFile", srcPath, destPath, &err)
+
+	irodsSrcPath := fs.getCorrectIRODSPath(srcPath)
+	irodsDestPath := destFS.getCorrectIRODSPath(destPath)
+
+	if options == nil {
+		options = &StreamCopyFileOptions{}
+	}
+
+	srcEntry, err := fs.StatFile(irodsSrcPath)
+	if err != nil {
+		return "", err
+	}
+
+	destFilePath := irodsDestPath
+	if destFS.ExistsDir(irodsDestPath) {
+		// make full file name for dest
+		srcFileName := util.GetIRODSPathFileName(irodsSrcPath)
+		destFilePath = util.MakeIRODSPath(irodsDestPath, srcFileName)
+	}
+
+	destFilePath, _, err = destFS.resolveOverwrite(destFilePath, options.OverwritePolicy)
+	if err != nil {
+		return "", err
+	}
+
+	if fs == destFS && irodsSrcPath == destFilePath {
+		return "", xerrors.Errorf("failed to copy %s to itself", irodsSrcPath)
+	}
+
+	unlock := lockStreamCopyPaths(fs, irodsSrcPath, destFS, destFilePath)
+	defer unlock()
+
+	// each task uses a connection of both file systems, counted in the budget of the source
+	taskNum := fs.acquireTransferTasks(srcEntry.Size, options.TaskNum)
+	defer fs.releaseTransferTasks(taskNum)
+
+	err = irods_fs.StreamCopyDataObject(fs.ioSession, irodsSrcPath, options.SourceResource, srcEntry.Size, destFS.ioSession, destFilePath, options.Resource, taskNum, options.Callback)
+	if err != nil {
+		return "", err
+	}
+
+	destFS.invalidateCacheForFileCreate(destFilePath)
+	destFS.cachePropagation.PropagateFileCreate(destFilePath)
+
+	return destFilePath, nil
+}
+
+// lockStreamCopyPaths locks the source path for read and the destination path for write, returns a function to unlock them
+// paths are locked in order of file system id and path, copies in the other direction would deadlock otherwise
+func lockStreamCopyPaths(srcFS *FileSystem, srcPath string, destFS *FileSystem, destPath string) func() {
+	if srcFS == destFS {
+		lockedPaths := srcFS.pathLocks.LockFiles([]string{srcPath, destPath})
+		return func() {
+			srcFS.pathLocks.UnlockFiles(lockedPaths)
+		}
+	}
+
+	lockSrc := func() { srcFS.pathLocks.RLock(srcPath) }
+	lockDest := func() { destFS.pathLocks.Lock(destPath) }
+
+	srcFirst := srcFS.id < destFS.id || (srcFS.id == destFS.id && srcPath < destPath)
+	if srcFirst {
+		lockSrc()
+		lockDest()
+	} else {
+		lockDest()
+		lockSrc()
+	}
+
+	return func() {
+		destFS.pathLocks.Unlock(destPath)
+		srcFS.pathLocks.RUnlock(srcPath)
+	}
+}
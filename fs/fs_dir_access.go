@@ -0,0 +1,85 @@
+package fs
+
+import (
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// CreateDirWithAccess creates a directory, sets its ACL inheritance and gives accesses in acls over one connection
+// the parent directory must exist, UserName, UserZone and AccessLevel of acls are used, client zone if UserZone is empty
+// the directory is removed if setting inheritance or accesses fails, so it never stays with partial accesses
+// returns an entry of the directory created
+func (fs *FileSystem) CreateDirWithAccess(path string, acls []*types.IRODSAccess, inherit bool) (_ *Entry, err error) {
+	defer fs.audit("CreateDirWithAccess", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	dirEntry, err := fs.getCollection(irodsPath)
+	if err == nil && dirEntry.ID > 0 {
+		return nil, types.NewFileAlreadyExistError(path)
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.CreateCollection(conn, irodsPath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.invalidateCacheForDirCreate(irodsPath)
+	fs.cachePropagation.PropagateDirCreate(irodsPath)
+
+	accessErr := func() error {
+		if inherit {
+			err := irods_fs.SetAccessInherit(conn, irodsPath, true, false, false)
+			if err != nil {
+				return xerrors.Errorf("failed to set ACL inheritance of %s: %w", irodsPath, err)
+			}
+		}
+
+		for _, acl := range acls {
+			zone := acl.UserZone
+			if len(zone) == 0 {
+				zone = fs.getAccount().ClientZone
+			}
+
+			err := irods_fs.ChangeCollectionAccess(conn, irodsPath, acl.AccessLevel, acl.UserName, zone, false, false)
+			if err != nil {
+				return xerrors.Errorf("failed to give %s#%s access %s to %s: %w", acl.UserName, zone, acl.AccessLevel, irodsPath, err)
+			}
+		}
+		return nil
+	}()
+
+	if accessErr != nil {
+		// remove the dir not to leave it mis-permissioned
+		removeErr := irods_fs.DeleteCollection(conn, irodsPath, true, true)
+		fs.invalidateCacheForRemoveInternal(irodsPath, true)
+		fs.cachePropagation.PropagateDirRemove(irodsPath)
+		if removeErr != nil {
+			return nil, xerrors.Errorf("failed to remove %s after failing to set accesses (%s): %w", irodsPath, accessErr.Error(), removeErr)
+		}
+		return nil, accessErr
+	}
+
+	fs.cache.RemoveACLsCache(irodsPath)
+	fs.cache.AddDirCache(irodsPath, []string{})
+
+	dirEntry, err = fs.getCollection(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if dirEntry.ID <= 0 {
+		return nil, types.NewFileNotFoundError(irodsPath)
+	}
+	return dirEntry, nil
+}
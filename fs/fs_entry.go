@@ -2,6 +2,8 @@ package fs
 
 import (
 	"fmt"
+	iofs "io/fs"
+	"os"
 	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/types"
@@ -19,17 +21,23 @@ const (
 
 // Entry is a struct for filesystem entry
 type Entry struct {
-	ID                int64
-	Type              EntryType
-	Name              string
-	Path              string
-	Owner             string
-	Size              int64
-	DataType          string
-	CreateTime        time.Time
-	ModifyTime        time.Time
-	CheckSumAlgorithm types.ChecksumAlgorithm
-	CheckSum          []byte
+	ID                int64                   `json:"id"`
+	Type              EntryType               `json:"type"`
+	Name              string                  `json:"name"`
+	Path              string                  `json:"path"`
+	Owner             string                  `json:"owner"`
+	OwnerZone         string                  `json:"owner_zone"`
+	Size              int64                   `json:"size"`
+	DataType          string                  `json:"data_type"`
+	CreateTime        time.Time               `json:"create_time"`
+	ModifyTime        time.Time               `json:"modify_time"`
+	CheckSumAlgorithm types.ChecksumAlgorithm `json:"checksum_algorithm,omitempty"`
+	CheckSum          []byte                  `json:"checksum,omitempty"`
+	Inheritance       bool                    `json:"inheritance,omitempty"`         // only for directories
+	ReplicaCount      int                     `json:"replica_count,omitempty"`       // only for files
+	HasStaleReplica   bool                    `json:"has_stale_replica,omitempty"`   // only for files
+	OnlyStaleReplicas bool                    `json:"only_stale_replicas,omitempty"` // only for files
+	Replicas          []*types.IRODSReplica   `json:"replicas,omitempty"`            // only for files listed with replicas, the master replica first
 }
 
 // ToString stringifies the object
@@ -41,3 +49,76 @@ func (entry *Entry) ToString() string {
 func (entry *Entry) IsDir() bool {
 	return entry.Type == DirectoryEntry
 }
+
+// ToFileInfo returns an adapter that makes the entry an os.FileInfo and a fs.DirEntry
+func (entry *Entry) ToFileInfo() *EntryFileInfo {
+	return &EntryFileInfo{
+		entry: entry,
+	}
+}
+
+// ToDirEntries converts entries to fs.DirEntry, e.g., to return from ReadDir
+func ToDirEntries(entries []*Entry) []iofs.DirEntry {
+	dirEntries := make([]iofs.DirEntry, len(entries))
+	for idx, entry := range entries {
+		dirEntries[idx] = entry.ToFileInfo()
+	}
+	return dirEntries
+}
+
+// EntryFileInfo is an adapter of Entry that implements os.FileInfo and fs.DirEntry
+type EntryFileInfo struct {
+	entry *Entry
+}
+
+// Name returns base name of the entry
+func (info *EntryFileInfo) Name() string {
+	return info.entry.Name
+}
+
+// Size returns size of the entry in bytes, 0 for directories
+func (info *EntryFileInfo) Size() int64 {
+	if info.entry.IsDir() {
+		return 0
+	}
+	return info.entry.Size
+}
+
+// Mode returns file mode bits
+// iRODS does not have unix permissions, so fixed permission bits are returned
+func (info *EntryFileInfo) Mode() os.FileMode {
+	if info.entry.IsDir() {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ModTime returns modification time
+func (info *EntryFileInfo) ModTime() time.Time {
+	return info.entry.ModifyTime
+}
+
+// IsDir returns if the entry is for directory
+func (info *EntryFileInfo) IsDir() bool {
+	return info.entry.IsDir()
+}
+
+// Sys returns the underlying Entry
+func (info *EntryFileInfo) Sys() interface{} {
+	return info.entry
+}
+
+// Type returns type bits of the entry
+func (info *EntryFileInfo) Type() iofs.FileMode {
+	return info.Mode().Type()
+}
+
+// Info returns os.FileInfo of the entry
+func (info *EntryFileInfo) Info() (iofs.FileInfo, error) {
+	return info, nil
+}
+
+// GetEntry returns the underlying Entry
+func (info *EntryFileInfo) GetEntry() *Entry {
+	return info.entry
+}
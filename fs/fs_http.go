@@ -0,0 +1,82 @@
+package fs
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// HTTPHandler serves data objects of a FileSystem over HTTP, implements http.Handler
+// GET and HEAD requests are supported with Range and conditional requests, the URL path is resolved under the root path
+// use http.StripPrefix to mount the handler under a URL prefix
+type HTTPHandler struct {
+	filesystem *FileSystem
+	rootPath   string
+}
+
+// NewHTTPHandler creates a new HTTPHandler serving data objects under rootPath
+func NewHTTPHandler(filesystem *FileSystem, rootPath string) *HTTPHandler {
+	return &HTTPHandler{
+		filesystem: filesystem,
+		rootPath:   rootPath,
+	}
+}
+
+// GetETag returns an HTTP entity tag of the entry made from its checksum, empty if the checksum is not available
+func GetETag(entry *Entry) string {
+	if len(entry.CheckSum) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\"%s\"", hex.EncodeToString(entry.CheckSum))
+}
+
+// ServeHTTP serves a data object
+func (handler *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// clean the path to prevent access above the root path
+	irodsPath := path.Join(handler.rootPath, path.Clean("/"+r.URL.Path))
+
+	entry, err := handler.filesystem.Stat(irodsPath)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if entry.IsDir() {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	etag := GetETag(entry)
+	if len(etag) > 0 {
+		w.Header().Set("ETag", etag)
+	}
+
+	handle, err := handler.filesystem.OpenFile(irodsPath, "", "r")
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer handle.Close()
+
+	// handles Range, HEAD, If-None-Match, If-Modified-Since and content-type detection
+	http.ServeContent(w, r, entry.Name, entry.ModifyTime, handle)
+}
@@ -0,0 +1,117 @@
+package fs
+
+import (
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// ListSubTreeOptions is options for listing file system entries at any depth
+type ListSubTreeOptions struct {
+	// list dirs one by one with up to Concurrency metadata connections at the same time
+	// 0 or 1 lists the whole tree with a few queries on a connection, see ListSubTree
+	Concurrency int
+}
+
+// ListSubTreeWithOptions lists all file system entries under the given path at any depth with options
+// dirs come first, parents before children, then files
+func (fs *FileSystem) ListSubTreeWithOptions(path string, options *ListSubTreeOptions) ([]*Entry, error) {
+	if options == nil || options.Concurrency <= 1 {
+		return fs.ListSubTree(path)
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	fs.pathLocks.RLock(irodsPath)
+	defer fs.pathLocks.RUnlock(irodsPath)
+
+	collectionEntry, err := fs.getCollection(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := fs.getCollectionFromEntry(collectionEntry)
+
+	collections, dataobjects, err := fs.listSubTreeParallel(collection, options.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.makeSubTreeEntries(irodsPath, collections, dataobjects), nil
+}
+
+// listSubTreeParallel lists collections and data objects under the collection level by level
+// collections of a level are listed by tasks on separate connections
+func (fs *FileSystem) listSubTreeParallel(collection *types.IRODSCollection, concurrency int) ([]*types.IRODSCollection, []*types.IRODSDataObject, error) {
+	// the session may give fewer connections than requested
+	connections, err := fs.metaSession.AcquireConnectionsMulti(concurrency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		for _, conn := range connections {
+			fs.metaSession.ReturnConnection(conn)
+		}
+	}()
+
+	collections := []*types.IRODSCollection{}
+	dataobjects := []*types.IRODSDataObject{}
+	mutex := sync.Mutex{}
+
+	level := []*types.IRODSCollection{collection}
+	for len(level) > 0 {
+		collectionChan := make(chan *types.IRODSCollection, len(level))
+		for _, coll := range level {
+			collectionChan <- coll
+		}
+		close(collectionChan)
+
+		errChan := make(chan error, len(connections))
+		nextLevel := []*types.IRODSCollection{}
+		taskWaitGroup := sync.WaitGroup{}
+
+		listTask := func(taskConn *connection.IRODSConnection) {
+			defer taskWaitGroup.Done()
+
+			for coll := range collectionChan {
+				subCollections, taskErr := irods_fs.ListSubCollections(taskConn, coll.Path)
+				if taskErr != nil {
+					errChan <- xerrors.Errorf("failed to list sub-collections of %s: %w", coll.Path, taskErr)
+					return
+				}
+
+				collDataObjects, taskErr := irods_fs.ListDataObjectsMasterReplica(taskConn, coll)
+				if taskErr != nil {
+					errChan <- xerrors.Errorf("failed to list data objects in %s: %w", coll.Path, taskErr)
+					return
+				}
+
+				mutex.Lock()
+				nextLevel = append(nextLevel, subCollections...)
+				dataobjects = append(dataobjects, collDataObjects...)
+				mutex.Unlock()
+			}
+		}
+
+		for _, conn := range connections {
+			taskWaitGroup.Add(1)
+
+			go listTask(conn)
+		}
+
+		taskWaitGroup.Wait()
+
+		if len(errChan) > 0 {
+			return nil, nil, <-errChan
+		}
+
+		collections = append(collections, nextLevel...)
+		level = nextLevel
+	}
+
+	return collections, dataobjects, nil
+}
@@ -1,26 +1,53 @@
 package fs
 
 import (
+	"fmt"
+	"time"
+
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
 )
 
+// SearchByMetaOptions is options for searching file system entries with metadata
+type SearchByMetaOptions struct {
+	// search only files (FileEntry) or only directories (DirectoryEntry), both if empty
+	EntryType EntryType
+}
+
 // SearchByMeta searches all file system entries with given metadata
 func (fs *FileSystem) SearchByMeta(metaname string, metavalue string) ([]*Entry, error) {
-	return fs.searchEntriesByMeta(metaname, metavalue)
+	return fs.searchEntriesByMeta(metaname, metavalue, "")
+}
+
+// SearchByMetaWithOptions searches file system entries with given metadata
+// only the queries for the entry type given in options are run
+func (fs *FileSystem) SearchByMetaWithOptions(metaname string, metavalue string, options *SearchByMetaOptions) ([]*Entry, error) {
+	if options == nil {
+		options = &SearchByMetaOptions{}
+	}
+
+	switch options.EntryType {
+	case "", FileEntry, DirectoryEntry:
+	default:
+		return nil, xerrors.Errorf("unknown entry type %q", options.EntryType)
+	}
+
+	return fs.searchEntriesByMeta(metaname, metavalue, options.EntryType)
 }
 
 // ListMetadata lists metadata for the given path
+// AVUs are retrieved in pages, so entries with a large number of AVUs are listed completely
 func (fs *FileSystem) ListMetadata(path string) ([]*types.IRODSMeta, error) {
+	irodsCorrectPath := fs.getCorrectIRODSPath(path)
+
 	// check cache first
-	cachedEntry := fs.cache.GetMetadataCache(path)
+	cachedEntry := fs.cache.GetMetadataCache(irodsCorrectPath)
 	if cachedEntry != nil {
 		return cachedEntry, nil
 	}
 
-	irodsCorrectPath := util.GetCorrectIRODSPath(path)
-
 	// otherwise, retrieve it and add it to cache
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -36,7 +63,7 @@ func (fs *FileSystem) ListMetadata(path string) ([]*types.IRODSMeta, error) {
 			return nil, err
 		}
 	} else {
-		collectionEntry, err := fs.getCollection(util.GetIRODSPathDirname(path))
+		collectionEntry, err := fs.getCollection(util.GetIRODSPathDirname(irodsCorrectPath))
 		if err != nil {
 			return nil, err
 		}
@@ -56,8 +83,10 @@ func (fs *FileSystem) ListMetadata(path string) ([]*types.IRODSMeta, error) {
 }
 
 // AddMetadata adds a metadata for the path
-func (fs *FileSystem) AddMetadata(irodsPath string, attName string, attValue string, attUnits string) error {
-	irodsCorrectPath := util.GetCorrectIRODSPath(irodsPath)
+func (fs *FileSystem) AddMetadata(irodsPath string, attName string, attValue string, attUnits string) (err error) {
+	defer fs.audit("AddMetadata", irodsPath, &err)
+
+	irodsCorrectPath := fs.getCorrectIRODSPath(irodsPath)
 
 	metadata := &types.IRODSMeta{
 		Name:  attName,
@@ -87,9 +116,60 @@ func (fs *FileSystem) AddMetadata(irodsPath string, attName string, attValue str
 	return nil
 }
 
+// AddLargeMetadata adds a metadata for the path, splitting the value into AVUs of chunks if it is too large for an AVU
+// chunks are added all or none if the server supports atomic metadata operations
+// use types.JoinIRODSMeta to join chunks listed, and DeleteMetadataByName to delete them
+func (fs *FileSystem) AddLargeMetadata(irodsPath string, attName string, attValue string, attUnits string) (err error) {
+	defer fs.audit("AddLargeMetadata", irodsPath, &err)
+
+	irodsCorrectPath := fs.getCorrectIRODSPath(irodsPath)
+
+	metadata := &types.IRODSMeta{
+		Name:  attName,
+		Value: attValue,
+		Units: attUnits,
+	}
+
+	chunks := types.SplitIRODSMeta(metadata, types.IRODSMetaValueMaxLength)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	dir := fs.ExistsDir(irodsCorrectPath)
+
+	if len(chunks) > 1 && conn.SupportAtomicMetadata() {
+		itemType := types.IRODSDataObjectMetaItemType
+		if dir {
+			itemType = types.IRODSCollectionMetaItemType
+		}
+
+		err = irods_fs.AddMetaAtomic(conn, itemType, irodsCorrectPath, chunks, false)
+	} else {
+		for _, chunk := range chunks {
+			if dir {
+				err = irods_fs.AddCollectionMeta(conn, irodsCorrectPath, chunk)
+			} else {
+				err = irods_fs.AddDataObjectMeta(conn, irodsCorrectPath, chunk)
+			}
+
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	fs.cache.RemoveMetadataCache(irodsCorrectPath)
+	return err
+}
+
 // DeleteMetadata deletes a metadata for the path
-func (fs *FileSystem) DeleteMetadata(irodsPath string, avuid int64) error {
-	irodsCorrectPath := util.GetCorrectIRODSPath(irodsPath)
+func (fs *FileSystem) DeleteMetadata(irodsPath string, avuid int64) (err error) {
+	defer fs.audit("DeleteMetadata", irodsPath, &err)
+
+	irodsCorrectPath := fs.getCorrectIRODSPath(irodsPath)
 
 	metadata := &types.IRODSMeta{
 		AVUID: avuid,
@@ -118,8 +198,10 @@ func (fs *FileSystem) DeleteMetadata(irodsPath string, avuid int64) error {
 }
 
 // DeleteMetadataByName deletes a metadata for the path by name
-func (fs *FileSystem) DeleteMetadataByName(irodsPath string, attName string) error {
-	irodsCorrectPath := util.GetCorrectIRODSPath(irodsPath)
+func (fs *FileSystem) DeleteMetadataByName(irodsPath string, attName string) (err error) {
+	defer fs.audit("DeleteMetadataByName", irodsPath, &err)
+
+	irodsCorrectPath := fs.getCorrectIRODSPath(irodsPath)
 
 	metadata := &types.IRODSMeta{
 		AVUID: 0,
@@ -148,8 +230,223 @@ func (fs *FileSystem) DeleteMetadataByName(irodsPath string, attName string) err
 	return nil
 }
 
+// MetadataOptions is options for adding or deleting metadata of files and dirs
+type MetadataOptions struct {
+	// apply to all files and dirs under the dir
+	Recursive bool
+	// return actions to be performed without changing anything
+	DryRun bool
+	// go on with the remaining files and dirs when one fails, failures are reported in the BulkResult
+	ContinueOnError bool
+}
+
+// AddMetadataWithOptions adds a metadata to the path, and to all files and dirs under the path if recursive
+// entries already having the same metadata are skipped
+// returns files and dirs changed, or to be changed in dry-run, parents first
+func (fs *FileSystem) AddMetadataWithOptions(irodsPath string, attName string, attValue string, attUnits string, options *MetadataOptions) ([]*FileSystemAction, error) {
+	result, err := fs.AddMetadataWithResult(irodsPath, attName, attValue, attUnits, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return nil, failed[0].Error
+	}
+	return result.Actions(), nil
+}
+
+// AddMetadataWithResult adds a metadata as AddMetadataWithOptions, returns a result per file or dir changed, parents first
+// the error is for failures before changing any file or dir, failures of files and dirs are in the result
+func (fs *FileSystem) AddMetadataWithResult(irodsPath string, attName string, attValue string, attUnits string, options *MetadataOptions) (*BulkResult, error) {
+	if options == nil {
+		options = &MetadataOptions{}
+	}
+
+	entries, err := fs.getMetadataTargetEntries(irodsPath, options.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := fmt.Sprintf("%s=%s (%s)", attName, attValue, attUnits)
+
+	targets := []*Entry{}
+	for _, entry := range entries {
+		metas, err := fs.ListMetadata(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		exist := false
+		for _, meta := range metas {
+			if meta.Name == attName && meta.Value == attValue && meta.Units == attUnits {
+				exist = true
+				break
+			}
+		}
+
+		if !exist {
+			targets = append(targets, entry)
+		}
+	}
+
+	result := NewBulkResult()
+	if options.DryRun {
+		for _, entry := range targets {
+			result.add(entry.Path, FileSystemActionAddMetadata, detail, 0, time.Time{}, nil)
+		}
+		return result, nil
+	}
+
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	for _, entry := range targets {
+		metadata := &types.IRODSMeta{
+			Name:  attName,
+			Value: attValue,
+			Units: attUnits,
+		}
+
+		startTime := time.Now()
+		if entry.Type == DirectoryEntry {
+			err = irods_fs.AddCollectionMeta(conn, entry.Path, metadata)
+		} else {
+			err = irods_fs.AddDataObjectMeta(conn, entry.Path, metadata)
+		}
+
+		fs.RecordAudit("AddMetadata", entry.Path, "", err)
+		result.add(entry.Path, FileSystemActionAddMetadata, detail, 0, startTime, err)
+		if err != nil {
+			if !options.ContinueOnError {
+				break
+			}
+			continue
+		}
+
+		fs.cache.RemoveMetadataCache(entry.Path)
+	}
+
+	return result, nil
+}
+
+// DeleteMetadataByNameWithOptions deletes metadata of the name from the path, and from all files and dirs under the path if recursive
+// entries not having metadata of the name are skipped
+// returns files and dirs changed, or to be changed in dry-run, parents first
+func (fs *FileSystem) DeleteMetadataByNameWithOptions(irodsPath string, attName string, options *MetadataOptions) ([]*FileSystemAction, error) {
+	result, err := fs.DeleteMetadataByNameWithResult(irodsPath, attName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return nil, failed[0].Error
+	}
+	return result.Actions(), nil
+}
+
+// DeleteMetadataByNameWithResult deletes metadata as DeleteMetadataByNameWithOptions, returns a result per file or dir changed, parents first
+// the error is for failures before changing any file or dir, failures of files and dirs are in the result
+func (fs *FileSystem) DeleteMetadataByNameWithResult(irodsPath string, attName string, options *MetadataOptions) (*BulkResult, error) {
+	if options == nil {
+		options = &MetadataOptions{}
+	}
+
+	entries, err := fs.getMetadataTargetEntries(irodsPath, options.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*Entry{}
+	for _, entry := range entries {
+		metas, err := fs.ListMetadata(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, meta := range metas {
+			if meta.Name == attName {
+				targets = append(targets, entry)
+				break
+			}
+		}
+	}
+
+	result := NewBulkResult()
+	if options.DryRun {
+		for _, entry := range targets {
+			result.add(entry.Path, FileSystemActionDeleteMetadata, attName, 0, time.Time{}, nil)
+		}
+		return result, nil
+	}
+
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	for _, entry := range targets {
+		metadata := &types.IRODSMeta{
+			AVUID: 0,
+			Name:  attName,
+		}
+
+		startTime := time.Now()
+		if entry.Type == DirectoryEntry {
+			err = irods_fs.DeleteCollectionMeta(conn, entry.Path, metadata)
+		} else {
+			err = irods_fs.DeleteDataObjectMeta(conn, entry.Path, metadata)
+		}
+
+		fs.RecordAudit("DeleteMetadataByName", entry.Path, "", err)
+		result.add(entry.Path, FileSystemActionDeleteMetadata, attName, 0, startTime, err)
+		if err != nil {
+			if !options.ContinueOnError {
+				break
+			}
+			continue
+		}
+
+		fs.cache.RemoveMetadataCache(entry.Path)
+	}
+
+	return result, nil
+}
+
+// getMetadataTargetEntries returns the entry of the path, and all entries under the path if recursive, parents first
+func (fs *FileSystem) getMetadataTargetEntries(path string, recursive bool) ([]*Entry, error) {
+	entry, err := fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Type != DirectoryEntry || !recursive {
+		return []*Entry{entry}, nil
+	}
+
+	dirs, files, err := fs.listTree(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dirs, files...), nil
+}
+
 // AddUserMetadata adds a user metadata
-func (fs *FileSystem) AddUserMetadata(user string, attName, attValue, attUnits string) error {
+func (fs *FileSystem) AddUserMetadata(user string, attName, attValue, attUnits string) (err error) {
+	defer fs.auditName("AddUserMetadata", user, &err)
+
 	metadata := &types.IRODSMeta{
 		Name:  attName,
 		Value: attValue,
@@ -171,7 +468,9 @@ func (fs *FileSystem) AddUserMetadata(user string, attName, attValue, attUnits s
 }
 
 // DeleteUserMetadata deletes a user metadata
-func (fs *FileSystem) DeleteUserMetadata(user string, avuid int64) error {
+func (fs *FileSystem) DeleteUserMetadata(user string, avuid int64) (err error) {
+	defer fs.auditName("DeleteUserMetadata", user, &err)
+
 	metadata := &types.IRODSMeta{
 		AVUID: avuid,
 	}
@@ -191,7 +490,9 @@ func (fs *FileSystem) DeleteUserMetadata(user string, avuid int64) error {
 }
 
 // DeleteUserMetadataByName deletes a user metadata by name
-func (fs *FileSystem) DeleteUserMetadataByName(user string, attName string) error {
+func (fs *FileSystem) DeleteUserMetadataByName(user string, attName string) (err error) {
+	defer fs.auditName("DeleteUserMetadataByName", user, &err)
+
 	metadata := &types.IRODSMeta{
 		AVUID: 0,
 		Name:  attName,
@@ -227,8 +528,139 @@ func (fs *FileSystem) ListUserMetadata(user string) ([]*types.IRODSMeta, error)
 	return metadataobjects, nil
 }
 
+// AddGroupMetadata adds a group metadata
+func (fs *FileSystem) AddGroupMetadata(group string, attName, attValue, attUnits string) (err error) {
+	defer fs.auditName("AddGroupMetadata", group, &err)
+
+	metadata := &types.IRODSMeta{
+		Name:  attName,
+		Value: attValue,
+		Units: attUnits,
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.AddGroupMeta(conn, group, metadata)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteGroupMetadata deletes a group metadata
+func (fs *FileSystem) DeleteGroupMetadata(group string, avuid int64) (err error) {
+	defer fs.auditName("DeleteGroupMetadata", group, &err)
+
+	metadata := &types.IRODSMeta{
+		AVUID: avuid,
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.DeleteGroupMeta(conn, group, metadata)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteGroupMetadataByName deletes a group metadata by name
+func (fs *FileSystem) DeleteGroupMetadataByName(group string, attName string) (err error) {
+	defer fs.auditName("DeleteGroupMetadataByName", group, &err)
+
+	metadata := &types.IRODSMeta{
+		AVUID: 0,
+		Name:  attName,
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.DeleteGroupMeta(conn, group, metadata)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListGroupMetadata lists all group metadata
+func (fs *FileSystem) ListGroupMetadata(group string) ([]*types.IRODSMeta, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	metadataobjects, err := irods_fs.ListGroupMeta(conn, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadataobjects, nil
+}
+
+// SearchUsersByMeta searches users (excluding groups) by metadata
+func (fs *FileSystem) SearchUsersByMeta(metaname string, metavalue string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchUsersByMeta(conn, metaname, metavalue)
+}
+
+// SearchUsersByMetaWildcard searches users (excluding groups) by metadata, metavalue is in SQL LIKE syntax
+func (fs *FileSystem) SearchUsersByMetaWildcard(metaname string, metavalue string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchUsersByMetaWildcard(conn, metaname, metavalue)
+}
+
+// SearchGroupsByMeta searches groups by metadata
+func (fs *FileSystem) SearchGroupsByMeta(metaname string, metavalue string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchGroupsByMeta(conn, metaname, metavalue)
+}
+
+// SearchGroupsByMetaWildcard searches groups by metadata, metavalue is in SQL LIKE syntax
+func (fs *FileSystem) SearchGroupsByMetaWildcard(metaname string, metavalue string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchGroupsByMetaWildcard(conn, metaname, metavalue)
+}
+
 // AddResourceMetadata adds a resource metadata
-func (fs *FileSystem) AddResourceMetadata(resource string, attName, attValue, attUnits string) error {
+func (fs *FileSystem) AddResourceMetadata(resource string, attName, attValue, attUnits string) (err error) {
+	defer fs.auditName("AddResourceMetadata", resource, &err)
+
 	metadata := &types.IRODSMeta{
 		Name:  attName,
 		Value: attValue,
@@ -250,7 +682,9 @@ func (fs *FileSystem) AddResourceMetadata(resource string, attName, attValue, at
 }
 
 // DeleteResourceMetadata deletes a resource metadata
-func (fs *FileSystem) DeleteResourceMetadata(resource string, avuid int64) error {
+func (fs *FileSystem) DeleteResourceMetadata(resource string, avuid int64) (err error) {
+	defer fs.auditName("DeleteResourceMetadata", resource, &err)
+
 	metadata := &types.IRODSMeta{
 		AVUID: avuid,
 	}
@@ -270,7 +704,9 @@ func (fs *FileSystem) DeleteResourceMetadata(resource string, avuid int64) error
 }
 
 // DeleteResourceMetadataByName deletes a resource metadata by name
-func (fs *FileSystem) DeleteResourceMetadataByName(resource string, attName string) error {
+func (fs *FileSystem) DeleteResourceMetadataByName(resource string, attName string) (err error) {
+	defer fs.auditName("DeleteResourceMetadataByName", resource, &err)
+
 	metadata := &types.IRODSMeta{
 		AVUID: 0,
 		Name:  attName,
@@ -306,19 +742,78 @@ func (fs *FileSystem) ListResourceMetadata(resource string) ([]*types.IRODSMeta,
 	return metadataobjects, nil
 }
 
-// searchEntriesByMeta searches entries by meta
-func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string) ([]*Entry, error) {
+// searchEntriesByMeta searches entries by meta, entries of all types are searched if entryType is empty
+func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string, entryType EntryType) ([]*Entry, error) {
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return nil, err
 	}
 	defer fs.metaSession.ReturnConnection(conn)
 
-	collections, err := irods_fs.SearchCollectionsByMeta(conn, metaName, metaValue)
+	collections := []*types.IRODSCollection{}
+	if entryType != FileEntry {
+		collections, err = irods_fs.SearchCollectionsByMeta(conn, metaName, metaValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dataobjects := []*types.IRODSDataObject{}
+	if entryType != DirectoryEntry {
+		dataobjects, err = irods_fs.SearchDataObjectsMasterReplicaByMeta(conn, metaName, metaValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fs.getEntriesFromSearchResult(collections, dataobjects), nil
+}
+
+// SearchByMetaWithUnits searches all file system entries with given metadata name, value and units
+func (fs *FileSystem) SearchByMetaWithUnits(metaname string, metavalue string, metaunits string) ([]*Entry, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	collections, err := irods_fs.SearchCollectionsByMetaWithUnits(conn, metaname, metavalue, metaunits)
+	if err != nil {
+		return nil, err
+	}
+
+	dataobjects, err := irods_fs.SearchDataObjectsMasterReplicaByMetaWithUnits(conn, metaname, metavalue, metaunits)
 	if err != nil {
 		return nil, err
 	}
 
+	return fs.getEntriesFromSearchResult(collections, dataobjects), nil
+}
+
+// SearchByMetaWithUnitsWildcard searches all file system entries with given metadata name, value and units
+// metavalue and metaunits are in SQL LIKE syntax, use "%" to match any value or units
+func (fs *FileSystem) SearchByMetaWithUnitsWildcard(metaname string, metavalue string, metaunits string) ([]*Entry, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	collections, err := irods_fs.SearchCollectionsByMetaWithUnitsWildcard(conn, metaname, metavalue, metaunits)
+	if err != nil {
+		return nil, err
+	}
+
+	dataobjects, err := irods_fs.SearchDataObjectsMasterReplicaByMetaWithUnitsWildcard(conn, metaname, metavalue, metaunits)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.getEntriesFromSearchResult(collections, dataobjects), nil
+}
+
+// getEntriesFromSearchResult makes entries from collections and data objects found, and caches them
+func (fs *FileSystem) getEntriesFromSearchResult(collections []*types.IRODSCollection, dataobjects []*types.IRODSDataObject) []*Entry {
 	entries := []*Entry{}
 
 	for _, coll := range collections {
@@ -330,11 +825,6 @@ func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string) ([]
 		fs.cache.AddEntryCache(entry)
 	}
 
-	dataobjects, err := irods_fs.SearchDataObjectsMasterReplicaByMeta(conn, metaName, metaValue)
-	if err != nil {
-		return nil, err
-	}
-
 	for _, dataobject := range dataobjects {
 		if len(dataobject.Replicas) == 0 {
 			continue
@@ -348,5 +838,27 @@ func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string) ([]
 		fs.cache.AddEntryCache(entry)
 	}
 
-	return entries, nil
+	return entries
+}
+
+// SearchResourcesByMeta searches resources by metadata
+func (fs *FileSystem) SearchResourcesByMeta(metaname string, metavalue string) ([]*types.IRODSResource, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchResourcesByMeta(conn, metaname, metavalue)
+}
+
+// SearchResourcesByMetaWildcard searches resources by metadata, metavalue is in SQL LIKE syntax
+func (fs *FileSystem) SearchResourcesByMetaWildcard(metaname string, metavalue string) ([]*types.IRODSResource, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.SearchResourcesByMetaWildcard(conn, metaname, metavalue)
 }
@@ -0,0 +1,125 @@
+package fs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// MetadataExportFormat is a format of a metadata export
+type MetadataExportFormat string
+
+const (
+	// MetadataExportFormatCSV writes records as CSV rows of path, type, name, value and units, with a header row
+	MetadataExportFormatCSV MetadataExportFormat = "csv"
+	// MetadataExportFormatJSONL writes records as JSON lines
+	MetadataExportFormatJSONL MetadataExportFormat = "jsonl"
+)
+
+// MetadataRecord is an AVU of a file or a dir in a metadata export
+type MetadataRecord struct {
+	Path  string    `json:"path"`
+	Type  EntryType `json:"type"`
+	Name  string    `json:"name"`
+	Value string    `json:"value"`
+	Units string    `json:"units,omitempty"`
+}
+
+// MetadataWalkFunc is called with each AVU of a metadata walk, the walk stops at the first error returned
+type MetadataWalkFunc func(record *MetadataRecord) error
+
+// WalkMetadata calls walkFunc with AVUs of the dir and all files and dirs under it, AVUs of dirs first
+// AVUs are streamed from the catalog with a few queries for the whole tree, not cached nor held in memory
+func (fs *FileSystem) WalkMetadata(path string, walkFunc MetadataWalkFunc) error {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	_, err := fs.StatDir(irodsPath)
+	if err != nil {
+		return err
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.WalkCollectionMetaRecursive(conn, irodsPath, func(p string, meta *types.IRODSMeta) error {
+		return walkFunc(newMetadataRecord(p, DirectoryEntry, meta))
+	})
+	if err != nil {
+		return err
+	}
+
+	return irods_fs.WalkDataObjectMetaRecursive(conn, irodsPath, func(p string, meta *types.IRODSMeta) error {
+		return walkFunc(newMetadataRecord(p, FileEntry, meta))
+	})
+}
+
+// ExportMetadata writes AVUs of the dir and all files and dirs under it to the writer in the format, see WalkMetadata
+// returns the number of AVUs written
+func (fs *FileSystem) ExportMetadata(path string, writer io.Writer, format MetadataExportFormat) (int, error) {
+	count := 0
+
+	switch format {
+	case MetadataExportFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+
+		err := csvWriter.Write([]string{"path", "type", "name", "value", "units"})
+		if err != nil {
+			return 0, xerrors.Errorf("failed to write metadata export header: %w", err)
+		}
+
+		err = fs.WalkMetadata(path, func(record *MetadataRecord) error {
+			err := csvWriter.Write([]string{record.Path, string(record.Type), record.Name, record.Value, record.Units})
+			if err != nil {
+				return xerrors.Errorf("failed to write metadata of %s: %w", record.Path, err)
+			}
+			count++
+			return nil
+		})
+
+		csvWriter.Flush()
+		if err != nil {
+			return count, err
+		}
+
+		err = csvWriter.Error()
+		if err != nil {
+			return count, xerrors.Errorf("failed to write metadata export: %w", err)
+		}
+	case MetadataExportFormatJSONL:
+		encoder := json.NewEncoder(writer)
+
+		err := fs.WalkMetadata(path, func(record *MetadataRecord) error {
+			err := encoder.Encode(record)
+			if err != nil {
+				return xerrors.Errorf("failed to write metadata of %s: %w", record.Path, err)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	default:
+		return 0, xerrors.Errorf("unknown metadata export format %s", format)
+	}
+
+	return count, nil
+}
+
+// newMetadataRecord creates a MetadataRecord
+func newMetadataRecord(path string, entryType EntryType, meta *types.IRODSMeta) *MetadataRecord {
+	return &MetadataRecord{
+		Path:  path,
+		Type:  entryType,
+		Name:  meta.Name,
+		Value: meta.Value,
+		Units: meta.Units,
+	}
+}
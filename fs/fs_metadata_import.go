@@ -0,0 +1,295 @@
+package fs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// metadataImportBatchSize is the number of records imported with a connection at once
+	metadataImportBatchSize = 1000
+)
+
+// MetadataImportResult is a result of importing a MetadataRecord, Error is nil if the AVU is added
+type MetadataImportResult struct {
+	Record *MetadataRecord
+	Error  error
+}
+
+// MetadataImportResultFunc is called with the result of each record of a metadata import
+type MetadataImportResultFunc func(result *MetadataImportResult)
+
+// ImportMetadata adds AVUs of the records to files and dirs, returns a result per record in the order of records
+// AVUs of a path are added in a single request if the server supports atomic metadata operations, then all or none of them are added
+// the Type of a record is optional, the path is checked if it is empty
+// returns an error only if the import could not run, failures of records are in the results
+func (fs *FileSystem) ImportMetadata(records []*MetadataRecord) ([]*MetadataImportResult, error) {
+	results := make([]*MetadataImportResult, 0, len(records))
+
+	for start := 0; start < len(records); start += metadataImportBatchSize {
+		end := start + metadataImportBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		batchResults, err := fs.importMetadataBatch(records[start:end])
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, batchResults...)
+	}
+
+	return results, nil
+}
+
+// ImportMetadataFromReader reads records in the format from the reader and adds their AVUs, see ImportMetadata
+// a CSV needs a header row naming path, name and value columns, type and units columns are optional, as ExportMetadata writes
+// records are read and imported in batches, resultFunc is called with the result of each record if it is not nil
+// returns the number of AVUs added
+func (fs *FileSystem) ImportMetadataFromReader(reader io.Reader, format MetadataExportFormat, resultFunc MetadataImportResultFunc) (int, error) {
+	var nextRecord func() (*MetadataRecord, error)
+
+	switch format {
+	case MetadataExportFormatCSV:
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+
+		header, err := csvReader.Read()
+		if err != nil {
+			return 0, xerrors.Errorf("failed to read metadata import header: %w", err)
+		}
+
+		columns := map[string]int{}
+		for idx, column := range header {
+			columns[column] = idx
+		}
+
+		for _, column := range []string{"path", "name", "value"} {
+			if _, ok := columns[column]; !ok {
+				return 0, xerrors.Errorf("metadata import header has no %s column", column)
+			}
+		}
+
+		getField := func(row []string, column string) string {
+			idx, ok := columns[column]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return row[idx]
+		}
+
+		nextRecord = func() (*MetadataRecord, error) {
+			row, err := csvReader.Read()
+			if err != nil {
+				return nil, err
+			}
+
+			return &MetadataRecord{
+				Path:  getField(row, "path"),
+				Type:  EntryType(getField(row, "type")),
+				Name:  getField(row, "name"),
+				Value: getField(row, "value"),
+				Units: getField(row, "units"),
+			}, nil
+		}
+	case MetadataExportFormatJSONL:
+		decoder := json.NewDecoder(reader)
+
+		nextRecord = func() (*MetadataRecord, error) {
+			record := &MetadataRecord{}
+			err := decoder.Decode(record)
+			if err != nil {
+				return nil, err
+			}
+			return record, nil
+		}
+	default:
+		return 0, xerrors.Errorf("unknown metadata import format %s", format)
+	}
+
+	count := 0
+	batch := []*MetadataRecord{}
+
+	importBatch := func() error {
+		results, err := fs.importMetadataBatch(batch)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if result.Error == nil {
+				count++
+			}
+
+			if resultFunc != nil {
+				resultFunc(result)
+			}
+		}
+
+		batch = []*MetadataRecord{}
+		return nil
+	}
+
+	for {
+		record, err := nextRecord()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return count, xerrors.Errorf("failed to read metadata import record: %w", err)
+		}
+
+		batch = append(batch, record)
+		if len(batch) >= metadataImportBatchSize {
+			err = importBatch()
+			if err != nil {
+				return count, err
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		err := importBatch()
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// importMetadataBatch adds AVUs of the records with a connection, records of a path are added together
+func (fs *FileSystem) importMetadataBatch(records []*MetadataRecord) ([]*MetadataImportResult, error) {
+	results := make([]*MetadataImportResult, len(records))
+
+	// group records by path, keeping the order of paths
+	paths := []string{}
+	recordIndexes := map[string][]int{}
+	for idx, record := range records {
+		if len(record.Path) == 0 || len(record.Name) == 0 || len(record.Value) == 0 {
+			results[idx] = &MetadataImportResult{
+				Record: record,
+				Error:  xerrors.Errorf("metadata record must have path, name and value"),
+			}
+			continue
+		}
+
+		irodsPath := fs.getCorrectIRODSPath(record.Path)
+		if _, ok := recordIndexes[irodsPath]; !ok {
+			paths = append(paths, irodsPath)
+		}
+		recordIndexes[irodsPath] = append(recordIndexes[irodsPath], idx)
+	}
+
+	if len(paths) > 0 {
+		conn, err := fs.metaSession.AcquireConnection()
+		if err != nil {
+			return nil, err
+		}
+		defer fs.metaSession.ReturnConnection(conn)
+
+		for _, irodsPath := range paths {
+			pathRecords := []*MetadataRecord{}
+			for _, idx := range recordIndexes[irodsPath] {
+				pathRecords = append(pathRecords, records[idx])
+			}
+
+			errs := fs.importMetadataForPath(conn, irodsPath, pathRecords)
+			for i, idx := range recordIndexes[irodsPath] {
+				results[idx] = &MetadataImportResult{
+					Record: records[idx],
+					Error:  errs[i],
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// importMetadataForPath adds AVUs of the records of a path, returns an error per record
+func (fs *FileSystem) importMetadataForPath(conn *connection.IRODSConnection, irodsPath string, records []*MetadataRecord) []error {
+	errs := make([]error, len(records))
+	setAll := func(err error) []error {
+		for idx := range errs {
+			errs[idx] = err
+		}
+		return errs
+	}
+
+	var entryType EntryType
+	for _, record := range records {
+		if len(record.Type) > 0 {
+			entryType = record.Type
+			break
+		}
+	}
+
+	if len(entryType) == 0 {
+		entry, err := fs.Stat(irodsPath)
+		if err != nil {
+			return setAll(err)
+		}
+		entryType = entry.Type
+	}
+
+	var itemType types.IRODSMetaItemType
+	switch entryType {
+	case DirectoryEntry:
+		itemType = types.IRODSCollectionMetaItemType
+	case FileEntry:
+		itemType = types.IRODSDataObjectMetaItemType
+	default:
+		return setAll(xerrors.Errorf("unknown entry type %s of %s", entryType, irodsPath))
+	}
+
+	added := false
+	if conn.SupportAtomicMetadata() {
+		metadata := make([]*types.IRODSMeta, len(records))
+		for idx, record := range records {
+			metadata[idx] = &types.IRODSMeta{
+				Name:  record.Name,
+				Value: record.Value,
+				Units: record.Units,
+			}
+		}
+
+		err := irods_fs.AddMetaAtomic(conn, itemType, irodsPath, metadata, false)
+		setAll(err)
+		added = err == nil
+		fs.RecordAudit("ImportMetadata", irodsPath, "", err)
+	} else {
+		for idx, record := range records {
+			metadata := &types.IRODSMeta{
+				Name:  record.Name,
+				Value: record.Value,
+				Units: record.Units,
+			}
+
+			var err error
+			if itemType == types.IRODSCollectionMetaItemType {
+				err = irods_fs.AddCollectionMeta(conn, irodsPath, metadata)
+			} else {
+				err = irods_fs.AddDataObjectMeta(conn, irodsPath, metadata)
+			}
+
+			errs[idx] = err
+			if err == nil {
+				added = true
+			}
+			fs.RecordAudit("ImportMetadata", irodsPath, "", err)
+		}
+	}
+
+	if added {
+		fs.cache.RemoveMetadataCache(irodsPath)
+	}
+	return errs
+}
@@ -0,0 +1,455 @@
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// MirrorDirOptions is options for mirroring a directory to another FileSystem
+type MirrorDirOptions struct {
+	// dir path in the dest FileSystem, the source path is used if empty
+	DestPath string
+	// resource to store files in the dest, the default resource of the dest is used if empty
+	Resource string
+	// mirror metadata (AVUs) of files and dirs, AVUs not in the source are removed from the dest
+	MirrorMetadata bool
+	// remove files and dirs in the dest that do not exist in the source
+	// file versions in dest dirs that exist in the source are kept
+	Delete bool
+	// number of parallel streams per file, decided by the file size if zero
+	TaskNum int
+	// return changes to be made without changing the dest
+	DryRun bool
+	// go on with the remaining files and dirs when one fails, failures are reported in Results of the result
+	ContinueOnError bool
+}
+
+// MirrorResult is a result of mirroring a directory, paths are of the dest
+// in dry-run, the result has changes to be made
+type MirrorResult struct {
+	CreatedDirs     []string
+	CopiedFiles     []string
+	SkippedFiles    []string
+	Removed         []string
+	MetadataUpdated []string
+	// result per dest file or dir changed, including failures, skipped files are not included
+	Results *BulkResult
+}
+
+// MirrorDir incrementally mirrors a directory tree of srcFS to destFS, e.g., between iRODS deployments
+// files are copied by streaming through the client if they differ in size or checksum, identical files are skipped
+// checksums are computed if not registered, files having checksums in different algorithms are always copied
+func MirrorDir(srcFS *FileSystem, destFS *FileSystem, path string, options *MirrorDirOptions) (*MirrorResult, error) {
+	if options == nil {
+		options = &MirrorDirOptions{}
+	}
+
+	srcRootPath := srcFS.getCorrectIRODSPath(path)
+	destRootPath := srcRootPath
+	if len(options.DestPath) > 0 {
+		destRootPath = destFS.getCorrectIRODSPath(options.DestPath)
+	}
+
+	if srcFS == destFS && srcRootPath == destRootPath {
+		return nil, xerrors.Errorf("failed to mirror %s to itself", srcRootPath)
+	}
+
+	srcDirEntry, err := srcFS.StatDir(srcRootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	srcDirs, srcFiles, err := srcFS.listTree(srcDirEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	destDirs, destFiles, err := destFS.listMirrorDestTree(destRootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MirrorResult{
+		CreatedDirs:     []string{},
+		CopiedFiles:     []string{},
+		SkippedFiles:    []string{},
+		Removed:         []string{},
+		MetadataUpdated: []string{},
+		Results:         NewBulkResult(),
+	}
+
+	// startTime returns the time an item starts, zero in dry-run
+	startTime := func() time.Time {
+		if options.DryRun {
+			return time.Time{}
+		}
+		return time.Now()
+	}
+
+	// dest paths of source entries
+	mirroredPaths := map[string]bool{}
+
+	// dest paths not existing before mirroring or removed, they have no metadata
+	newPaths := map[string]bool{}
+
+	// dirs are ordered parents first
+	for _, srcDir := range srcDirs {
+		destDirPath := getCopyDestPath(srcRootPath, destRootPath, srcDir.Path)
+		mirroredPaths[destDirPath] = true
+
+		if destFile, ok := destFiles[destDirPath]; ok {
+			if !options.Delete {
+				return nil, xerrors.Errorf("failed to mirror dir %s, a file exists at %s: %w", srcDir.Path, destDirPath, types.NewFileAlreadyExistError(destDirPath))
+			}
+
+			start := startTime()
+			if !options.DryRun {
+				err = destFS.RemoveFile(destFile.Path, true)
+			}
+
+			result.Results.add(destDirPath, FileSystemActionRemoveFile, "", 0, start, err)
+			if err != nil {
+				if !options.ContinueOnError {
+					return nil, err
+				}
+				continue
+			}
+			delete(destFiles, destDirPath)
+			result.Removed = append(result.Removed, destDirPath)
+		}
+
+		if _, ok := destDirs[destDirPath]; !ok {
+			start := startTime()
+			if !options.DryRun {
+				err = destFS.MakeDir(destDirPath, true)
+			}
+
+			result.Results.add(destDirPath, FileSystemActionMakeDir, "", 0, start, err)
+			if err != nil {
+				if !options.ContinueOnError {
+					return nil, err
+				}
+				continue
+			}
+			newPaths[destDirPath] = true
+			result.CreatedDirs = append(result.CreatedDirs, destDirPath)
+		}
+	}
+
+	for _, srcFile := range srcFiles {
+		destFilePath := getCopyDestPath(srcRootPath, destRootPath, srcFile.Path)
+		mirroredPaths[destFilePath] = true
+
+		if destDir, ok := destDirs[destFilePath]; ok {
+			if !options.Delete {
+				return nil, xerrors.Errorf("failed to mirror file %s, a dir exists at %s: %w", srcFile.Path, destFilePath, types.NewFileAlreadyExistError(destFilePath))
+			}
+
+			start := startTime()
+			if !options.DryRun {
+				err = destFS.RemoveDir(destDir.Path, true, true)
+			}
+
+			result.Results.add(destFilePath, FileSystemActionRemoveDir, "", 0, start, err)
+			if err != nil {
+				if !options.ContinueOnError {
+					return nil, err
+				}
+				continue
+			}
+			deleteEntriesUnderDir(destDirs, destFilePath)
+			deleteEntriesUnderDir(destFiles, destFilePath)
+			result.Removed = append(result.Removed, destFilePath)
+		}
+
+		if destFile, ok := destFiles[destFilePath]; ok && isIdenticalMirroredFile(srcFS, srcFile, destFS, destFile) {
+			result.SkippedFiles = append(result.SkippedFiles, destFilePath)
+			continue
+		}
+
+		if _, ok := destFiles[destFilePath]; !ok {
+			newPaths[destFilePath] = true
+		}
+
+		start := startTime()
+		if !options.DryRun {
+			_, err = srcFS.StreamCopyFileToFileSystem(srcFile.Path, destFS, destFilePath, &StreamCopyFileOptions{
+				Resource:        options.Resource,
+				OverwritePolicy: OverwritePolicyOverwrite,
+				TaskNum:         options.TaskNum,
+			})
+		}
+
+		result.Results.add(destFilePath, FileSystemActionCopyFile, srcFile.Path, srcFile.Size, start, err)
+		if err != nil {
+			if !options.ContinueOnError {
+				return nil, err
+			}
+			continue
+		}
+		result.CopiedFiles = append(result.CopiedFiles, destFilePath)
+	}
+
+	if options.MirrorMetadata {
+		srcEntries := append(append([]*Entry{}, srcDirs...), srcFiles...)
+		for _, srcEntry := range srcEntries {
+			destPath := getCopyDestPath(srcRootPath, destRootPath, srcEntry.Path)
+
+			start := startTime()
+			updated, err := mirrorMetadata(srcFS, srcEntry.Path, destFS, destPath, newPaths[destPath], options.DryRun)
+			if err != nil {
+				result.Results.add(destPath, FileSystemActionUpdateMetadata, "", 0, start, err)
+				if !options.ContinueOnError {
+					return nil, err
+				}
+				continue
+			}
+
+			if updated {
+				result.Results.add(destPath, FileSystemActionUpdateMetadata, "", 0, start, nil)
+				result.MetadataUpdated = append(result.MetadataUpdated, destPath)
+			}
+		}
+	}
+
+	if options.Delete {
+		err = destFS.removeUnmirroredEntries(destRootPath, destDirs, destFiles, mirroredPaths, result, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// listMirrorDestTree returns dirs and files under the dest dir keyed by path, both are empty if the dir does not exist
+func (fs *FileSystem) listMirrorDestTree(path string) (map[string]*Entry, map[string]*Entry, error) {
+	dirs := map[string]*Entry{}
+	files := map[string]*Entry{}
+
+	dirEntry, err := fs.Stat(path)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			return dirs, files, nil
+		}
+		return nil, nil, err
+	}
+
+	if dirEntry.Type != DirectoryEntry {
+		return nil, nil, xerrors.Errorf("failed to mirror to %s, the path is for a file: %w", path, types.NewFileAlreadyExistError(path))
+	}
+
+	dirEntries, fileEntries, err := fs.listTree(dirEntry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range dirEntries {
+		dirs[entry.Path] = entry
+	}
+
+	for _, entry := range fileEntries {
+		files[entry.Path] = entry
+	}
+
+	return dirs, files, nil
+}
+
+// deleteEntriesUnderDir deletes entries of the dir and its descendants from the map
+func deleteEntriesUnderDir(entries map[string]*Entry, dirPath string) {
+	for p := range entries {
+		if p == dirPath || strings.HasPrefix(p, dirPath+"/") {
+			delete(entries, p)
+		}
+	}
+}
+
+// removeUnmirroredEntries removes dest dirs and files not mirrored from the source, file versions are kept
+// paths removed, or to be removed in dry-run, are added to the result
+func (fs *FileSystem) removeUnmirroredEntries(rootPath string, dirs map[string]*Entry, files map[string]*Entry, mirroredPaths map[string]bool, result *MirrorResult, options *MirrorDirOptions) error {
+	removed := []string{}
+
+	remove := func(p string, action FileSystemActionType, removeFunc func() error) error {
+		var err error
+		startTime := time.Time{}
+		if !options.DryRun {
+			startTime = time.Now()
+			err = removeFunc()
+		}
+
+		result.Results.add(p, action, "", 0, startTime, err)
+		if err != nil {
+			if !options.ContinueOnError {
+				return err
+			}
+			return nil
+		}
+
+		removed = append(removed, p)
+		result.Removed = append(result.Removed, p)
+		return nil
+	}
+
+	isUnderRemovedDir := func(p string) bool {
+		for _, removedPath := range removed {
+			if strings.HasPrefix(p, removedPath+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	isVersion := func(p string) bool {
+		relPath := strings.TrimPrefix(p, rootPath)
+		return strings.Contains(relPath+"/", "/"+VersionsDirName+"/")
+	}
+
+	// sorted paths have parents first, children are removed with them
+	dirPaths := []string{}
+	for dirPath := range dirs {
+		dirPaths = append(dirPaths, dirPath)
+	}
+	sort.Strings(dirPaths)
+
+	for _, dirPath := range dirPaths {
+		if mirroredPaths[dirPath] || isVersion(dirPath) || isUnderRemovedDir(dirPath) {
+			continue
+		}
+
+		err := remove(dirPath, FileSystemActionRemoveDir, func() error {
+			return fs.RemoveDir(dirPath, true, true)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	filePaths := []string{}
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		if mirroredPaths[filePath] || isVersion(filePath) || isUnderRemovedDir(filePath) {
+			continue
+		}
+
+		err := remove(filePath, FileSystemActionRemoveFile, func() error {
+			return fs.RemoveFile(filePath, true)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isIdenticalMirroredFile returns true if files of two FileSystems have the same size and checksum
+// files are treated as different if getting checksums fails
+func isIdenticalMirroredFile(srcFS *FileSystem, srcEntry *Entry, destFS *FileSystem, destEntry *Entry) bool {
+	if srcEntry.Size != destEntry.Size {
+		return false
+	}
+
+	srcChecksum, err := srcFS.getFileChecksum(srcEntry)
+	if err != nil {
+		return false
+	}
+
+	destChecksum, err := destFS.getFileChecksum(destEntry)
+	if err != nil {
+		return false
+	}
+
+	return srcChecksum.Algorithm == destChecksum.Algorithm && bytes.Equal(srcChecksum.Checksum, destChecksum.Checksum)
+}
+
+// getFileChecksum returns a checksum of a file, the checksum is computed if not registered
+func (fs *FileSystem) getFileChecksum(entry *Entry) (*types.IRODSChecksum, error) {
+	if len(entry.CheckSum) > 0 {
+		return &types.IRODSChecksum{
+			Algorithm: entry.CheckSumAlgorithm,
+			Checksum:  entry.CheckSum,
+		}, nil
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.GetDataObjectChecksum(conn, entry.Path, "")
+}
+
+// mirrorMetadata makes metadata of the dest entry the same as the source entry
+// the dest is treated as having no metadata if destIsNew, e.g., not created yet in dry-run
+// returns true if metadata of the dest entry is changed, or to be changed in dry-run
+func mirrorMetadata(srcFS *FileSystem, srcPath string, destFS *FileSystem, destPath string, destIsNew bool, dryRun bool) (bool, error) {
+	srcMetas, err := srcFS.ListMetadata(srcPath)
+	if err != nil {
+		return false, err
+	}
+
+	destMetas := []*types.IRODSMeta{}
+	if !destIsNew || !dryRun {
+		destMetas, err = destFS.ListMetadata(destPath)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	getMetaKey := func(meta *types.IRODSMeta) string {
+		return fmt.Sprintf("%s\x00%s\x00%s", meta.Name, meta.Value, meta.Units)
+	}
+
+	srcMetaKeys := map[string]bool{}
+	for _, meta := range srcMetas {
+		srcMetaKeys[getMetaKey(meta)] = true
+	}
+
+	destMetaKeys := map[string]bool{}
+	updated := false
+	for _, meta := range destMetas {
+		key := getMetaKey(meta)
+		if srcMetaKeys[key] {
+			destMetaKeys[key] = true
+			continue
+		}
+
+		if !dryRun {
+			err = destFS.DeleteMetadata(destPath, meta.AVUID)
+			if err != nil {
+				return false, err
+			}
+		}
+		updated = true
+	}
+
+	for _, meta := range srcMetas {
+		key := getMetaKey(meta)
+		if destMetaKeys[key] {
+			continue
+		}
+
+		if !dryRun {
+			err = destFS.AddMetadata(destPath, meta.Name, meta.Value, meta.Units)
+			if err != nil {
+				return false, err
+			}
+		}
+		destMetaKeys[key] = true
+		updated = true
+	}
+
+	return updated, nil
+}
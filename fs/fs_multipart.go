@@ -0,0 +1,340 @@
+package fs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+)
+
+// MultipartUpload uploads a file in parts of a fixed size, like S3 multipart upload
+// parts can be uploaded in any order and in parallel, each part except the last must be exactly the part size
+// parts are written to a temporary data object in place, which replaces the target on Complete
+type MultipartUpload struct {
+	filesystem        *FileSystem
+	id                string
+	path              string
+	tempPath          string
+	resource          string
+	partSize          int64
+	connection        *connection.IRODSConnection
+	handle            *types.IRODSFileHandle
+	replicaToken      string
+	resourceHierarchy string
+	parts             map[int]int64 // part number to size
+	uploading         int
+	closed            bool
+	mutex             sync.Mutex
+	writeMutex        sync.Mutex // serializes writes on the handle when parallel writes are not supported
+}
+
+// InitiateMultipartUpload starts a multipart upload to the path
+func (fs *FileSystem) InitiateMultipartUpload(path string, resource string, partSize int64) (*MultipartUpload, error) {
+	if partSize <= 0 {
+		return nil, xerrors.Errorf("failed to initiate multipart upload, invalid part size %d", partSize)
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	// use default resource when resource param is empty
+	if len(resource) == 0 {
+		resource = fs.getAccount().DefaultResource
+	}
+
+	id := xid.New().String()
+	tempName := fmt.Sprintf(".%s.%s.multipart", util.GetIRODSPathFileName(irodsPath), id)
+	tempPath := util.MakeIRODSPath(util.GetIRODSPathDirname(irodsPath), tempName)
+
+	conn, err := fs.ioSession.AcquireUnmanagedConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := irods_fs.CreateDataObject(conn, tempPath, resource, "w+", true)
+	if err != nil {
+		fs.ioSession.DiscardConnection(conn)
+		return nil, err
+	}
+
+	replicaToken := ""
+	resourceHierarchy := ""
+	if conn.SupportParallelUpload() {
+		replicaToken, resourceHierarchy, err = irods_fs.GetReplicaAccessInfo(conn, handle)
+		if err != nil {
+			irods_fs.CloseDataObject(conn, handle)
+			irods_fs.DeleteDataObject(conn, tempPath, true)
+			fs.ioSession.DiscardConnection(conn)
+			return nil, err
+		}
+	}
+
+	return &MultipartUpload{
+		filesystem:        fs,
+		id:                id,
+		path:              irodsPath,
+		tempPath:          tempPath,
+		resource:          resource,
+		partSize:          partSize,
+		connection:        conn,
+		handle:            handle,
+		replicaToken:      replicaToken,
+		resourceHierarchy: resourceHierarchy,
+		parts:             map[int]int64{},
+		uploading:         0,
+		closed:            false,
+	}, nil
+}
+
+// GetID returns the upload ID
+func (upload *MultipartUpload) GetID() string {
+	return upload.id
+}
+
+// GetPath returns the iRODS path of the target
+func (upload *MultipartUpload) GetPath() string {
+	return upload.path
+}
+
+// GetPartSize returns the part size
+func (upload *MultipartUpload) GetPartSize() int64 {
+	return upload.partSize
+}
+
+// GetUploadedParts returns sizes of uploaded parts by part number
+func (upload *MultipartUpload) GetUploadedParts() map[int]int64 {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	parts := map[int]int64{}
+	for partNumber, size := range upload.parts {
+		parts[partNumber] = size
+	}
+	return parts
+}
+
+// UploadPart uploads a part, part numbers start from 1, uploading the same part again replaces it
+// returns the number of bytes written
+func (upload *MultipartUpload) UploadPart(partNumber int, reader io.Reader) (int64, error) {
+	if partNumber < 1 {
+		return 0, xerrors.Errorf("failed to upload part, invalid part number %d", partNumber)
+	}
+
+	upload.mutex.Lock()
+	if upload.closed {
+		upload.mutex.Unlock()
+		return 0, xerrors.Errorf("failed to upload part %d, multipart upload %s is closed", partNumber, upload.id)
+	}
+	upload.uploading++
+	upload.mutex.Unlock()
+
+	defer func() {
+		upload.mutex.Lock()
+		upload.uploading--
+		upload.mutex.Unlock()
+	}()
+
+	offset := int64(partNumber-1) * upload.partSize
+
+	var size int64
+	var err error
+	if len(upload.replicaToken) > 0 {
+		size, err = upload.writePartParallel(offset, reader)
+	} else {
+		size, err = upload.writePart(offset, reader)
+	}
+
+	if err != nil {
+		return size, xerrors.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	upload.mutex.Lock()
+	upload.parts[partNumber] = size
+	upload.mutex.Unlock()
+
+	return size, nil
+}
+
+// writePartParallel writes a part on a new connection sharing the replica of the upload
+func (upload *MultipartUpload) writePartParallel(offset int64, reader io.Reader) (int64, error) {
+	session := upload.filesystem.ioSession
+
+	conn, err := session.AcquireUnmanagedConnection()
+	if err != nil {
+		return 0, err
+	}
+	defer session.DiscardConnection(conn)
+
+	handle, _, err := irods_fs.OpenDataObjectWithReplicaToken(conn, upload.tempPath, upload.resource, "w", upload.replicaToken, upload.resourceHierarchy, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := upload.copyPart(conn, handle, offset, reader)
+	if err != nil {
+		irods_fs.CloseDataObjectReplica(conn, handle)
+		return size, err
+	}
+
+	err = irods_fs.CloseDataObjectReplica(conn, handle)
+	if err != nil {
+		return size, err
+	}
+	return size, nil
+}
+
+// writePart writes a part on the handle of the upload
+func (upload *MultipartUpload) writePart(offset int64, reader io.Reader) (int64, error) {
+	upload.writeMutex.Lock()
+	defer upload.writeMutex.Unlock()
+
+	return upload.copyPart(upload.connection, upload.handle, offset, reader)
+}
+
+// copyPart copies data from reader to the handle at offset, fails if data is larger than the part size
+func (upload *MultipartUpload) copyPart(conn *connection.IRODSConnection, handle *types.IRODSFileHandle, offset int64, reader io.Reader) (int64, error) {
+	newOffset, err := irods_fs.SeekDataObject(conn, handle, offset, types.SeekSet)
+	if err != nil {
+		return 0, err
+	}
+
+	if newOffset != offset {
+		return 0, xerrors.Errorf("failed to seek to target offset %d", offset)
+	}
+
+	// read one more byte to detect a part larger than the part size
+	limitedReader := io.LimitReader(reader, upload.partSize+1)
+
+	written := int64(0)
+	buffer := make([]byte, conn.GetTransferBufferSize())
+	for {
+		readLen, readErr := io.ReadFull(limitedReader, buffer)
+		if readLen > 0 {
+			if written+int64(readLen) > upload.partSize {
+				return written, xerrors.Errorf("part is larger than part size %d", upload.partSize)
+			}
+
+			err = irods_fs.WriteDataObject(conn, handle, buffer[:readLen])
+			if err != nil {
+				return written, err
+			}
+
+			written += int64(readLen)
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+				return written, nil
+			}
+			return written, xerrors.Errorf("failed to read part: %w", readErr)
+		}
+	}
+}
+
+// getSize checks if parts are complete and returns the total size
+func (upload *MultipartUpload) getSize() (int64, error) {
+	if len(upload.parts) == 0 {
+		return 0, xerrors.Errorf("no parts are uploaded")
+	}
+
+	partNumbers := []int{}
+	for partNumber := range upload.parts {
+		partNumbers = append(partNumbers, partNumber)
+	}
+	sort.Ints(partNumbers)
+
+	totalSize := int64(0)
+	for idx, partNumber := range partNumbers {
+		if partNumber != idx+1 {
+			return 0, xerrors.Errorf("part %d is missing", idx+1)
+		}
+
+		size := upload.parts[partNumber]
+		if idx < len(partNumbers)-1 && size != upload.partSize {
+			return 0, xerrors.Errorf("part %d has size %d, only the last part can be smaller than part size %d", partNumber, size, upload.partSize)
+		}
+
+		totalSize += size
+	}
+	return totalSize, nil
+}
+
+// Complete assembles uploaded parts into the target, the target is overwritten if exists
+// parts must be numbered from 1 without gaps and no part can be uploading
+func (upload *MultipartUpload) Complete() (*Entry, error) {
+	upload.mutex.Lock()
+	if upload.closed {
+		upload.mutex.Unlock()
+		return nil, xerrors.Errorf("failed to complete multipart upload %s, already closed", upload.id)
+	}
+
+	if upload.uploading > 0 {
+		upload.mutex.Unlock()
+		return nil, xerrors.Errorf("failed to complete multipart upload %s, %d parts are uploading", upload.id, upload.uploading)
+	}
+
+	totalSize, err := upload.getSize()
+	if err != nil {
+		upload.mutex.Unlock()
+		return nil, xerrors.Errorf("failed to complete multipart upload %s: %w", upload.id, err)
+	}
+
+	upload.closed = true
+	upload.mutex.Unlock()
+
+	fs := upload.filesystem
+	defer fs.ioSession.DiscardConnection(upload.connection)
+
+	err = irods_fs.CloseDataObject(upload.connection, upload.handle)
+	if err != nil {
+		irods_fs.DeleteDataObject(upload.connection, upload.tempPath, true)
+		return nil, err
+	}
+
+	// a replaced last part may leave data beyond the total size
+	err = irods_fs.TruncateDataObject(upload.connection, upload.tempPath, totalSize)
+	if err != nil {
+		irods_fs.DeleteDataObject(upload.connection, upload.tempPath, true)
+		return nil, err
+	}
+
+	if fs.ExistsFile(upload.path) {
+		err = fs.RemoveFile(upload.path, true)
+		if err != nil {
+			irods_fs.DeleteDataObject(upload.connection, upload.tempPath, true)
+			return nil, err
+		}
+	}
+
+	err = fs.RenameFileToFile(upload.tempPath, upload.path)
+	if err != nil {
+		irods_fs.DeleteDataObject(upload.connection, upload.tempPath, true)
+		return nil, err
+	}
+
+	return fs.StatFile(upload.path)
+}
+
+// Abort cancels the upload and removes uploaded parts
+func (upload *MultipartUpload) Abort() error {
+	upload.mutex.Lock()
+	if upload.closed {
+		upload.mutex.Unlock()
+		return xerrors.Errorf("failed to abort multipart upload %s, already closed", upload.id)
+	}
+
+	upload.closed = true
+	upload.mutex.Unlock()
+
+	fs := upload.filesystem
+	defer fs.ioSession.DiscardConnection(upload.connection)
+
+	irods_fs.CloseDataObject(upload.connection, upload.handle)
+	return irods_fs.DeleteDataObject(upload.connection, upload.tempPath, true)
+}
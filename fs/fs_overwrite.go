@@ -0,0 +1,98 @@
+package fs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// OverwritePolicy determines how an existing target file is handled when writing a file
+type OverwritePolicy string
+
+const (
+	// OverwritePolicyError fails with FileAlreadyExistError if the target exists
+	OverwritePolicyError OverwritePolicy = "error"
+	// OverwritePolicyOverwrite replaces the target
+	OverwritePolicyOverwrite OverwritePolicy = "overwrite"
+	// OverwritePolicyRenameWithSuffix writes to a new name with a numeric suffix, e.g., "file (1).txt", keeping the target
+	OverwritePolicyRenameWithSuffix OverwritePolicy = "rename"
+	// OverwritePolicyVersion moves the target into the versions dir next to it, then writes
+	OverwritePolicyVersion OverwritePolicy = "version"
+)
+
+// getOverwritePolicyFromForce returns an overwrite policy for force flag
+func getOverwritePolicyFromForce(force bool) OverwritePolicy {
+	if force {
+		return OverwritePolicyOverwrite
+	}
+	return OverwritePolicyError
+}
+
+// resolveOverwrite applies the overwrite policy to the target path, returns a path to write
+// and true if the existing target is to be overwritten
+// an empty policy is treated as OverwritePolicyError
+func (fs *FileSystem) resolveOverwrite(targetPath string, policy OverwritePolicy) (string, bool, error) {
+	entry, err := fs.Stat(targetPath)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			return targetPath, false, nil
+		}
+		return "", false, err
+	}
+
+	if entry.Type != FileEntry {
+		return "", false, xerrors.Errorf("failed to write %s, the path is for a directory: %w", targetPath, types.NewFileAlreadyExistError(targetPath))
+	}
+
+	switch policy {
+	case OverwritePolicyError, "":
+		return "", false, xerrors.Errorf("failed to write %s: %w", targetPath, types.NewFileAlreadyExistError(targetPath))
+	case OverwritePolicyOverwrite:
+		if fs.config.Versioning {
+			_, err := fs.KeepFileVersion(targetPath)
+			if err != nil {
+				return "", false, err
+			}
+			return targetPath, false, nil
+		}
+		return targetPath, true, nil
+	case OverwritePolicyRenameWithSuffix:
+		newPath, err := fs.getSuffixedPath(targetPath)
+		if err != nil {
+			return "", false, err
+		}
+		return newPath, false, nil
+	case OverwritePolicyVersion:
+		_, err := fs.KeepFileVersion(targetPath)
+		if err != nil {
+			return "", false, err
+		}
+		return targetPath, false, nil
+	default:
+		return "", false, xerrors.Errorf("unknown overwrite policy %s", policy)
+	}
+}
+
+// getSuffixedPath returns a path of non-existing file by adding a numeric suffix to the file name
+func (fs *FileSystem) getSuffixedPath(p string) (string, error) {
+	dir := util.GetIRODSPathDirname(p)
+	name := util.GetIRODSPathFileName(p)
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 1; ; i++ {
+		newPath := util.MakeIRODSPath(dir, fmt.Sprintf("%s (%d)%s", base, i, ext))
+
+		_, err := fs.Stat(newPath)
+		if err != nil {
+			if types.IsFileNotFoundError(err) {
+				return newPath, nil
+			}
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package fs
+
+import (
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+// ExistsBatch checks existence of many files/directories at once, returns existence keyed by the given paths
+// paths not in cache are checked with grouped queries instead of a query per path
+func (fs *FileSystem) ExistsBatch(paths []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	uncachedPaths := []string{}
+
+	for _, p := range paths {
+		irodsPath := fs.getCorrectIRODSPath(p)
+
+		if fs.cache.HasNegativeEntryCache(irodsPath) {
+			continue
+		}
+
+		if fs.cache.GetEntryCache(irodsPath) != nil {
+			existing[irodsPath] = true
+			continue
+		}
+
+		uncachedPaths = append(uncachedPaths, irodsPath)
+	}
+
+	if len(uncachedPaths) > 0 {
+		collections, dataObjects, err := fs.findExistingPaths(uncachedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, irodsPath := range uncachedPaths {
+			if collections[irodsPath] || dataObjects[irodsPath] {
+				existing[irodsPath] = true
+			} else {
+				fs.cache.AddNegativeEntryCache(irodsPath)
+			}
+		}
+	}
+
+	exists := map[string]bool{}
+	for _, p := range paths {
+		exists[p] = existing[fs.getCorrectIRODSPath(p)]
+	}
+
+	return exists, nil
+}
+
+// AccessBatch returns effective access levels of a user for many paths at once, keyed by the given paths
+// access levels are resolved as Access does, with grouped queries instead of queries per path
+// the access level is null for a path whose parent directory does not exist either
+func (fs *FileSystem) AccessBatch(paths []string, user string) (map[string]types.IRODSAccessLevelType, error) {
+	userName, userZone, err := fs.splitUserZone(user)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames, err := fs.getUserGroupNames(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	irodsPaths := []string{}
+	for _, p := range paths {
+		irodsPaths = append(irodsPaths, fs.getCorrectIRODSPath(p))
+	}
+
+	collections, dataObjects, err := fs.findExistingPaths(irodsPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	// creating a path not existing is governed by access to the parent dir
+	targetPaths := map[string]string{}
+	collectionTargets := []string{}
+	dataObjectTargets := []string{}
+	for _, irodsPath := range irodsPaths {
+		switch {
+		case collections[irodsPath]:
+			targetPaths[irodsPath] = irodsPath
+			collectionTargets = append(collectionTargets, irodsPath)
+		case dataObjects[irodsPath]:
+			targetPaths[irodsPath] = irodsPath
+			dataObjectTargets = append(dataObjectTargets, irodsPath)
+		default:
+			parentPath := util.GetIRODSPathDirname(irodsPath)
+			targetPaths[irodsPath] = parentPath
+			collectionTargets = append(collectionTargets, parentPath)
+		}
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	accesses := []*types.IRODSAccess{}
+	if len(collectionTargets) > 0 {
+		collectionAccesses, err := irods_fs.ListAccessesForCollectionPaths(conn, collectionTargets)
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, collectionAccesses...)
+	}
+
+	if len(dataObjectTargets) > 0 {
+		dataObjectAccesses, err := irods_fs.ListAccessesForDataObjectPaths(conn, dataObjectTargets)
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, dataObjectAccesses...)
+	}
+
+	accessesPerPath := map[string][]*types.IRODSAccess{}
+	for _, access := range accesses {
+		accessesPerPath[access.Path] = append(accessesPerPath[access.Path], access)
+	}
+
+	accessLevels := map[string]types.IRODSAccessLevelType{}
+	for idx, p := range paths {
+		targetPath := targetPaths[irodsPaths[idx]]
+		accessLevels[p] = getEffectiveAccessLevel(accessesPerPath[targetPath], userName, userZone, groupNames)
+	}
+
+	return accessLevels, nil
+}
+
+// findExistingPaths finds collections and data objects existing among the paths with grouped queries
+func (fs *FileSystem) findExistingPaths(irodsPaths []string) (map[string]bool, map[string]bool, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	collectionPaths, err := irods_fs.ListExistingCollectionPaths(conn, irodsPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	collections := map[string]bool{}
+	for _, collectionPath := range collectionPaths {
+		collections[collectionPath] = true
+	}
+
+	remainingPaths := []string{}
+	for _, irodsPath := range irodsPaths {
+		if !collections[irodsPath] {
+			remainingPaths = append(remainingPaths, irodsPath)
+		}
+	}
+
+	dataObjects := map[string]bool{}
+	if len(remainingPaths) > 0 {
+		dataObjectPaths, err := irods_fs.ListExistingDataObjectPaths(conn, remainingPaths)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, dataObjectPath := range dataObjectPaths {
+			dataObjects[dataObjectPath] = true
+		}
+	}
+
+	return collections, dataObjects, nil
+}
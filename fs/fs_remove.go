@@ -0,0 +1,162 @@
+package fs
+
+import (
+	"context"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// RemoveDirOptions is options for removing a directory
+type RemoveDirOptions struct {
+	// remove all files and dirs under the dir, fails if the dir is not empty otherwise
+	Recurse bool
+	// remove without moving to trash
+	Force bool
+	// return actions to be performed without removing anything
+	DryRun bool
+}
+
+// RemoveDirWithOptions deletes a directory with options
+// returns files and dirs removed, or to be removed in dry-run, files first and children dirs before parents
+func (fs *FileSystem) RemoveDirWithOptions(path string, options *RemoveDirOptions) ([]*FileSystemAction, error) {
+	if options == nil {
+		options = &RemoveDirOptions{}
+	}
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	dirEntry, err := fs.StatDir(irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []*FileSystemAction{}
+	if options.Recurse {
+		dirs, files, err := fs.listTree(dirEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			actions = append(actions, newFileSystemAction(FileSystemActionRemoveFile, file.Path, ""))
+		}
+
+		// dirs are ordered parents first
+		for idx := len(dirs) - 1; idx >= 0; idx-- {
+			actions = append(actions, newFileSystemAction(FileSystemActionRemoveDir, dirs[idx].Path, ""))
+		}
+	} else {
+		if options.DryRun {
+			// the server rejects removing a non-empty dir
+			entries, err := fs.List(irodsPath)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(entries) > 0 {
+				return nil, xerrors.Errorf("failed to remove dir %s: %w", irodsPath, types.NewCollectionNotEmptyError(irodsPath))
+			}
+		}
+
+		actions = append(actions, newFileSystemAction(FileSystemActionRemoveDir, irodsPath, ""))
+	}
+
+	if options.DryRun {
+		return actions, nil
+	}
+
+	err = fs.RemoveDir(irodsPath, options.Recurse, options.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// RemoveDirWithContext deletes a directory recursively, removing files one by one
+// callback is called with files removed and total files in the directory
+// stops when ctx is done, files and directories removed so far are not restored
+func (fs *FileSystem) RemoveDirWithContext(ctx context.Context, path string, force bool, callback common.TrackerCallBack) (err error) {
+	defer fs.audit("RemoveDir", path, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	dirEntry, err := fs.StatDir(irodsPath)
+	if err != nil {
+		return err
+	}
+
+	// list before locking the path, listing locks paths too
+	dirs, files, err := fs.listTree(dirEntry)
+	if err != nil {
+		return err
+	}
+
+	totalFiles := int64(len(files))
+	if callback != nil {
+		callback(0, totalFiles)
+	}
+
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	removed := int64(0)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return xerrors.Errorf("failed to remove dir %s, %d of %d files removed: %w", irodsPath, removed, totalFiles, ctx.Err())
+		}
+
+		err = irods_fs.DeleteDataObject(conn, file.Path, force)
+		if err != nil {
+			return err
+		}
+
+		fs.invalidateCacheForFileRemove(file.Path)
+		fs.cachePropagation.PropagateFileRemove(file.Path)
+
+		removed++
+		if callback != nil {
+			callback(removed, totalFiles)
+		}
+	}
+
+	// dirs are ordered parents first, remove children first
+	for idx := len(dirs) - 1; idx >= 0; idx-- {
+		if ctx.Err() != nil {
+			return xerrors.Errorf("failed to remove dir %s, %d of %d files removed: %w", irodsPath, removed, totalFiles, ctx.Err())
+		}
+
+		dirPath := dirs[idx].Path
+		err = irods_fs.DeleteCollection(conn, dirPath, false, force)
+		if err != nil {
+			return err
+		}
+
+		fs.invalidateCacheForDirRemove(dirPath, false)
+		fs.cachePropagation.PropagateDirRemove(dirPath)
+	}
+
+	return nil
+}
+
+// RemoveDirAsync deletes a directory recursively in background, see RemoveDirWithContext
+// returns a channel receiving the result once the removal is done
+func (fs *FileSystem) RemoveDirAsync(ctx context.Context, path string, force bool, callback common.TrackerCallBack) <-chan error {
+	result := make(chan error, 1)
+
+	go func() {
+		result <- fs.RemoveDirWithContext(ctx, path, force, callback)
+		close(result)
+	}()
+
+	return result
+}
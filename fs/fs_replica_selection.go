@@ -0,0 +1,131 @@
+package fs
+
+import (
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+// replica ranks for reads, higher ones are preferred
+const (
+	replicaRankUnusable int = iota
+	replicaRankStale
+	replicaRankArchive
+	replicaRankOnline
+)
+
+// openDataObject opens a data object, from a replica selected for read if SelectReadReplica is set
+// falls back to letting the server pick a replica if selecting or opening the replica fails
+func (fs *FileSystem) openDataObject(conn *connection.IRODSConnection, irodsPath string, resource string, mode string) (*types.IRODSFileHandle, int64, error) {
+	openMode := types.FileOpenMode(mode)
+	if !fs.config.SelectReadReplica || len(resource) > 0 || !openMode.IsReadOnly() {
+		return irods_fs.OpenDataObject(conn, irodsPath, resource, mode)
+	}
+
+	resourceHierarchy := fs.selectReadReplica(conn, irodsPath)
+	if len(resourceHierarchy) > 0 {
+		handle, offset, err := irods_fs.OpenDataObjectReplica(conn, irodsPath, resourceHierarchy, mode)
+		if err == nil {
+			return handle, offset, nil
+		}
+
+		if types.IsFileNotFoundError(err) || !conn.IsConnected() {
+			return nil, -1, err
+		}
+	}
+
+	return irods_fs.OpenDataObject(conn, irodsPath, resource, mode)
+}
+
+// selectReadReplica returns the resource hierarchy of the best replica of the data object to read
+// returns an empty string to let the server pick one, if replicas are not known or no replica is better than the others
+func (fs *FileSystem) selectReadReplica(conn *connection.IRODSConnection, irodsPath string) string {
+	collectionEntry, err := fs.getCollection(util.GetIRODSPathDirname(irodsPath))
+	if err != nil {
+		return ""
+	}
+
+	collection := fs.getCollectionFromEntry(collectionEntry)
+
+	dataobject, err := irods_fs.GetDataObject(conn, collection, util.GetIRODSPathFileName(irodsPath))
+	if err != nil || len(dataobject.Replicas) < 2 {
+		return ""
+	}
+
+	resources := map[string]*types.IRODSResource{}
+	getResource := func(name string) *types.IRODSResource {
+		if resource, ok := resources[name]; ok {
+			return resource
+		}
+
+		resource, err := irods_fs.GetResource(conn, name)
+		if err != nil {
+			// unknown resources are not ranked down
+			resources[name] = nil
+			return nil
+		}
+
+		resources[name] = resource
+		return resource
+	}
+
+	var bestReplica *types.IRODSReplica
+	bestRank := replicaRankUnusable
+	worstRank := replicaRankOnline
+	for _, replica := range dataobject.Replicas {
+		rank := getReplicaRankForRead(replica, getResource)
+		if rank > bestRank {
+			bestReplica = replica
+			bestRank = rank
+		}
+
+		if rank < worstRank {
+			worstRank = rank
+		}
+	}
+
+	if bestReplica == nil || bestRank == worstRank {
+		return ""
+	}
+
+	if len(bestReplica.ResourceHierarchy) > 0 {
+		return bestReplica.ResourceHierarchy
+	}
+	return bestReplica.ResourceName
+}
+
+// getReplicaRankForRead ranks a replica for reads, by its status and the status and class of resources in its hierarchy
+func getReplicaRankForRead(replica *types.IRODSReplica, getResource func(name string) *types.IRODSResource) int {
+	hierarchy := replica.ResourceHierarchy
+	if len(hierarchy) == 0 {
+		hierarchy = replica.ResourceName
+	}
+
+	archive := false
+	for _, name := range strings.Split(hierarchy, ";") {
+		resource := getResource(name)
+		if resource == nil {
+			continue
+		}
+
+		if resource.IsDown() {
+			return replicaRankUnusable
+		}
+
+		if resource.IsArchive() {
+			archive = true
+		}
+	}
+
+	if !replica.IsGood() {
+		return replicaRankStale
+	}
+
+	if archive {
+		return replicaRankArchive
+	}
+	return replicaRankOnline
+}
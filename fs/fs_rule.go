@@ -0,0 +1,36 @@
+package fs
+
+import (
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// ExecuteRuleOptions is options for executing a rule
+type ExecuteRuleOptions struct {
+	// rule engine plugin instance to run the rule, e.g., "irods_rule_engine_plugin-irods_rule_language-instance"
+	// the server decides if empty
+	RuleEngineInstance string
+}
+
+// ExecuteRule executes a rule like calling a function, inputs and outputs are keyed by labels, e.g., "*name"
+// input values are string, int, map[string]string, or []byte
+// output values are one of them, or *types.IRODSRuleExecOut for ruleExecOut
+// ruleExecOut is returned if no output labels are given
+func (fs *FileSystem) ExecuteRule(rule string, inputs types.IRODSRuleParams, outputs []string) (types.IRODSRuleParams, error) {
+	return fs.ExecuteRuleWithOptions(rule, inputs, outputs, nil)
+}
+
+// ExecuteRuleWithOptions executes a rule with options, see ExecuteRule
+func (fs *FileSystem) ExecuteRuleWithOptions(rule string, inputs types.IRODSRuleParams, outputs []string, options *ExecuteRuleOptions) (types.IRODSRuleParams, error) {
+	if options == nil {
+		options = &ExecuteRuleOptions{}
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.ExecuteRule(conn, rule, inputs, outputs, options.RuleEngineInstance)
+}
@@ -3,13 +3,14 @@ package fs
 import (
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
-	"github.com/cyverse/go-irodsclient/irods/util"
 )
 
 // ExtractStructFile extracts a struct file
-func (fs *FileSystem) ExtractStructFile(path string, targetCollection string, resource string, dataType types.DataType, force bool, bulkReg bool) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
-	targetIrodsPath := util.GetCorrectIRODSPath(targetCollection)
+func (fs *FileSystem) ExtractStructFile(path string, targetCollection string, resource string, dataType types.DataType, force bool, bulkReg bool) (err error) {
+	defer fs.auditWithDest("ExtractStructFile", path, targetCollection, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
+	targetIrodsPath := fs.getCorrectIRODSPath(targetCollection)
 
 	// we create a new connection for extraction because iRODS has a bug that does not clear file descriptors, causing SYS_OUT_OF_FILE_DESC error.
 	// create a new unmanaged connection and throw out after use.
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 )
@@ -25,3 +26,75 @@ func (fs *FileSystem) ListProcesses(address string, zone string) ([]*types.IRODS
 func (fs *FileSystem) ListAllProcesses() ([]*types.IRODSProcess, error) {
 	return fs.ListProcesses("", "")
 }
+
+// GetAvailableSpace returns free space, capacity and status of a resource
+// default resource of the account is used if resource is empty
+func (fs *FileSystem) GetAvailableSpace(resource string) (*types.IRODSResourceSpace, error) {
+	if len(resource) == 0 {
+		resource = fs.getAccount().DefaultResource
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return irods_fs.GetResourceSpace(conn, resource)
+}
+
+// GetClientHints returns hints the server gives clients about its configuration
+// hints are retrieved once and kept for the life of the file system
+func (fs *FileSystem) GetClientHints() (*types.IRODSClientHints, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	return fs.getClientHints(conn)
+}
+
+// getClientHints returns client hints, retrieving them with the given connection if not retrieved yet
+func (fs *FileSystem) getClientHints(conn *connection.IRODSConnection) (*types.IRODSClientHints, error) {
+	fs.clientHintsMutex.Lock()
+	defer fs.clientHintsMutex.Unlock()
+
+	if fs.clientHints != nil {
+		return fs.clientHints, nil
+	}
+
+	hints, err := irods_fs.GetClientHints(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.clientHints = hints
+	return hints, nil
+}
+
+// IsStrictACLs returns true if the server enforces strict ACLs
+// returns false if the server does not give client hints
+func (fs *FileSystem) IsStrictACLs() bool {
+	hints, err := fs.GetClientHints()
+	if err != nil {
+		return false
+	}
+
+	return hints.IsStrictACLs()
+}
+
+// isSkippableACLError returns true if the error is a permission error of the server enforcing strict ACLs
+// ACLs are left out of listings for such errors rather than failing them
+func (fs *FileSystem) isSkippableACLError(conn *connection.IRODSConnection, err error) bool {
+	if !types.IsPermissionError(err) {
+		return false
+	}
+
+	hints, err := fs.getClientHints(conn)
+	if err != nil {
+		return false
+	}
+
+	return hints.IsStrictACLs()
+}
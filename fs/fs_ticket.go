@@ -5,7 +5,6 @@ import (
 
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
-	"github.com/cyverse/go-irodsclient/irods/util"
 )
 
 // GetTicketForAnonymousAccess gets ticket information for anonymous access
@@ -56,6 +55,24 @@ func (fs *FileSystem) ListTickets() ([]*types.IRODSTicket, error) {
 	return tickets, err
 }
 
+// ListTicketsForPath lists ticket information for the path, including use and write counts
+func (fs *FileSystem) ListTicketsForPath(path string) ([]*types.IRODSTicket, error) {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	tickets, err := irods_fs.ListTicketsForPath(conn, irodsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, err
+}
+
 // ListTicketsBasic lists all available basic ticket information
 func (fs *FileSystem) ListTicketsBasic() ([]*types.IRODSTicket, error) {
 	conn, err := fs.metaSession.AcquireConnection()
@@ -151,8 +168,10 @@ func (fs *FileSystem) ListTicketUserGroupRestrictions(ticketID int64) ([]string,
 }
 
 // CreateTicket creates a new ticket
-func (fs *FileSystem) CreateTicket(ticketName string, ticketType types.TicketType, path string) error {
-	irodsPath := util.GetCorrectIRODSPath(path)
+func (fs *FileSystem) CreateTicket(ticketName string, ticketType types.TicketType, path string) (err error) {
+	defer fs.auditName("CreateTicket", ticketName, &err)
+
+	irodsPath := fs.getCorrectIRODSPath(path)
 
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
@@ -169,7 +188,9 @@ func (fs *FileSystem) CreateTicket(ticketName string, ticketType types.TicketTyp
 }
 
 // DeleteTicket deletes the given ticket
-func (fs *FileSystem) DeleteTicket(ticketName string) error {
+func (fs *FileSystem) DeleteTicket(ticketName string) (err error) {
+	defer fs.auditName("DeleteTicket", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -185,7 +206,9 @@ func (fs *FileSystem) DeleteTicket(ticketName string) error {
 }
 
 // ModifyTicketUseLimit modifies the use limit of the given ticket
-func (fs *FileSystem) ModifyTicketUseLimit(ticketName string, uses int64) error {
+func (fs *FileSystem) ModifyTicketUseLimit(ticketName string, uses int64) (err error) {
+	defer fs.auditName("ModifyTicketUseLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -201,7 +224,9 @@ func (fs *FileSystem) ModifyTicketUseLimit(ticketName string, uses int64) error
 }
 
 // ClearTicketUseLimit clears the use limit of the given ticket
-func (fs *FileSystem) ClearTicketUseLimit(ticketName string) error {
+func (fs *FileSystem) ClearTicketUseLimit(ticketName string) (err error) {
+	defer fs.auditName("ClearTicketUseLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -217,7 +242,9 @@ func (fs *FileSystem) ClearTicketUseLimit(ticketName string) error {
 }
 
 // ModifyTicketWriteFileLimit modifies the write file limit of the given ticket
-func (fs *FileSystem) ModifyTicketWriteFileLimit(ticketName string, count int64) error {
+func (fs *FileSystem) ModifyTicketWriteFileLimit(ticketName string, count int64) (err error) {
+	defer fs.auditName("ModifyTicketWriteFileLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -233,7 +260,9 @@ func (fs *FileSystem) ModifyTicketWriteFileLimit(ticketName string, count int64)
 }
 
 // ClearTicketWriteFileLimit clears the write file limit of the given ticket
-func (fs *FileSystem) ClearTicketWriteFileLimit(ticketName string) error {
+func (fs *FileSystem) ClearTicketWriteFileLimit(ticketName string) (err error) {
+	defer fs.auditName("ClearTicketWriteFileLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -249,7 +278,9 @@ func (fs *FileSystem) ClearTicketWriteFileLimit(ticketName string) error {
 }
 
 // ModifyTicketWriteByteLimit modifies the write byte limit of the given ticket
-func (fs *FileSystem) ModifyTicketWriteByteLimit(ticketName string, bytes int64) error {
+func (fs *FileSystem) ModifyTicketWriteByteLimit(ticketName string, bytes int64) (err error) {
+	defer fs.auditName("ModifyTicketWriteByteLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -265,7 +296,9 @@ func (fs *FileSystem) ModifyTicketWriteByteLimit(ticketName string, bytes int64)
 }
 
 // ClearTicketWriteByteLimit clears the write byte limit of the given ticket
-func (fs *FileSystem) ClearTicketWriteByteLimit(ticketName string) error {
+func (fs *FileSystem) ClearTicketWriteByteLimit(ticketName string) (err error) {
+	defer fs.auditName("ClearTicketWriteByteLimit", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -281,7 +314,9 @@ func (fs *FileSystem) ClearTicketWriteByteLimit(ticketName string) error {
 }
 
 // AddTicketAllowedUser adds a user to the allowed user names list of the given ticket
-func (fs *FileSystem) AddTicketAllowedUser(ticketName string, userName string) error {
+func (fs *FileSystem) AddTicketAllowedUser(ticketName string, userName string) (err error) {
+	defer fs.auditName("AddTicketAllowedUser", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -297,7 +332,9 @@ func (fs *FileSystem) AddTicketAllowedUser(ticketName string, userName string) e
 }
 
 // RemoveTicketAllowedUser removes the user from the allowed user names list of the given ticket
-func (fs *FileSystem) RemoveTicketAllowedUser(ticketName string, userName string) error {
+func (fs *FileSystem) RemoveTicketAllowedUser(ticketName string, userName string) (err error) {
+	defer fs.auditName("RemoveTicketAllowedUser", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -313,7 +350,9 @@ func (fs *FileSystem) RemoveTicketAllowedUser(ticketName string, userName string
 }
 
 // AddTicketAllowedGroup adds a group to the allowed group names list of the given ticket
-func (fs *FileSystem) AddTicketAllowedGroup(ticketName string, groupName string) error {
+func (fs *FileSystem) AddTicketAllowedGroup(ticketName string, groupName string) (err error) {
+	defer fs.auditName("AddTicketAllowedGroup", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -329,7 +368,9 @@ func (fs *FileSystem) AddTicketAllowedGroup(ticketName string, groupName string)
 }
 
 // RemoveTicketAllowedGroup removes the group from the allowed group names list of the given ticket
-func (fs *FileSystem) RemoveTicketAllowedGroup(ticketName string, groupName string) error {
+func (fs *FileSystem) RemoveTicketAllowedGroup(ticketName string, groupName string) (err error) {
+	defer fs.auditName("RemoveTicketAllowedGroup", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -345,7 +386,9 @@ func (fs *FileSystem) RemoveTicketAllowedGroup(ticketName string, groupName stri
 }
 
 // AddTicketAllowedHost adds a host to the allowed hosts list of the given ticket
-func (fs *FileSystem) AddTicketAllowedHost(ticketName string, host string) error {
+func (fs *FileSystem) AddTicketAllowedHost(ticketName string, host string) (err error) {
+	defer fs.auditName("AddTicketAllowedHost", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -361,7 +404,9 @@ func (fs *FileSystem) AddTicketAllowedHost(ticketName string, host string) error
 }
 
 // RemoveTicketAllowedHost removes the host from the allowed hosts list of the given ticket
-func (fs *FileSystem) RemoveTicketAllowedHost(ticketName string, host string) error {
+func (fs *FileSystem) RemoveTicketAllowedHost(ticketName string, host string) (err error) {
+	defer fs.auditName("RemoveTicketAllowedHost", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -377,7 +422,9 @@ func (fs *FileSystem) RemoveTicketAllowedHost(ticketName string, host string) er
 }
 
 // ModifyTicketExpirationTime modifies the expiration time of the given ticket
-func (fs *FileSystem) ModifyTicketExpirationTime(ticketName string, expirationTime time.Time) error {
+func (fs *FileSystem) ModifyTicketExpirationTime(ticketName string, expirationTime time.Time) (err error) {
+	defer fs.auditName("ModifyTicketExpirationTime", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
@@ -393,7 +440,9 @@ func (fs *FileSystem) ModifyTicketExpirationTime(ticketName string, expirationTi
 }
 
 // ClearTicketExpirationTime clears the expiration time of the given ticket
-func (fs *FileSystem) ClearTicketExpirationTime(ticketName string) error {
+func (fs *FileSystem) ClearTicketExpirationTime(ticketName string) (err error) {
+	defer fs.auditName("ClearTicketExpirationTime", ticketName, &err)
+
 	conn, err := fs.metaSession.AcquireConnection()
 	if err != nil {
 		return err
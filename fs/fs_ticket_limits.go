@@ -0,0 +1,110 @@
+package fs
+
+import (
+	"fmt"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// TicketLimits is the number of uses, files and bytes a ticket allows from now on, on top of what is used already
+// 0 or negative values are for limits not to be set
+type TicketLimits struct {
+	Uses       int64
+	WriteFiles int64
+	WriteBytes int64
+}
+
+// ToString stringifies the object
+func (limits *TicketLimits) ToString() string {
+	return fmt.Sprintf("<TicketLimits %d %d %d>", limits.Uses, limits.WriteFiles, limits.WriteBytes)
+}
+
+// ExtendTicketLimits raises limits of the given ticket to allow as many more uses, files and bytes as given in limits
+// limits are never lowered, unlimited ones are kept unlimited, limits of 0 in limits are kept as they are
+// returns the ticket updated, e.g., to extend long-running upload tickets without recreating them
+func (fs *FileSystem) ExtendTicketLimits(ticketName string, limits *TicketLimits) (_ *types.IRODSTicket, err error) {
+	defer fs.auditName("ExtendTicketLimits", ticketName, &err)
+
+	return fs.updateTicketLimits(ticketName, limits, func(limit int64, count int64, allowed int64) (int64, bool) {
+		if allowed <= 0 || limit <= 0 {
+			// keep
+			return limit, false
+		}
+
+		newLimit := count + allowed
+		if newLimit <= limit {
+			return limit, false
+		}
+		return newLimit, true
+	})
+}
+
+// ResetTicketLimits sets limits of the given ticket to allow uses, files and bytes given in limits from now on, as if counts were reset
+// limits of 0 in limits are cleared, making the ticket unlimited in the respect
+// returns the ticket updated
+func (fs *FileSystem) ResetTicketLimits(ticketName string, limits *TicketLimits) (_ *types.IRODSTicket, err error) {
+	defer fs.auditName("ResetTicketLimits", ticketName, &err)
+
+	return fs.updateTicketLimits(ticketName, limits, func(limit int64, count int64, allowed int64) (int64, bool) {
+		newLimit := int64(0)
+		if allowed > 0 {
+			newLimit = count + allowed
+		}
+		return newLimit, newLimit != limit
+	})
+}
+
+// updateTicketLimits modifies limits of the ticket to the new limits calculated from the current limits and counts
+// newLimit returns the new limit and true if it should be modified
+func (fs *FileSystem) updateTicketLimits(ticketName string, limits *TicketLimits, newLimit func(limit int64, count int64, allowed int64) (int64, bool)) (*types.IRODSTicket, error) {
+	if limits == nil {
+		limits = &TicketLimits{}
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	ticket, err := irods_fs.GetTicket(conn, ticketName)
+	if err != nil {
+		return nil, err
+	}
+
+	type limitUpdate struct {
+		limit   int64
+		count   int64
+		allowed int64
+		modify  func(conn *connection.IRODSConnection, ticketName string, limit int64) error
+	}
+
+	updates := []limitUpdate{
+		{ticket.UsesLimit, ticket.UsesCount, limits.Uses, irods_fs.ModifyTicketUseLimit},
+		{ticket.WriteFileLimit, ticket.WriteFileCount, limits.WriteFiles, irods_fs.ModifyTicketWriteFileLimit},
+		{ticket.WriteByteLimit, ticket.WriteByteCount, limits.WriteBytes, irods_fs.ModifyTicketWriteByteLimit},
+	}
+
+	modified := false
+	for _, update := range updates {
+		limit, ok := newLimit(update.limit, update.count, update.allowed)
+		if !ok {
+			continue
+		}
+
+		err = update.modify(conn, ticketName, limit)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to modify limits of ticket %s: %w", ticketName, err)
+		}
+		modified = true
+	}
+
+	if !modified {
+		return ticket, nil
+	}
+
+	return irods_fs.GetTicket(conn, ticketName)
+}
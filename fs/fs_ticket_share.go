@@ -0,0 +1,97 @@
+package fs
+
+import (
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TicketShareUser is the user accessing data with shared tickets
+	TicketShareUser string = "anonymous"
+	// ticketShareNameLength is the length of names of tickets created for sharing, 22 letters carry over 128 random bits
+	ticketShareNameLength int = 22
+)
+
+// TicketShare is a ready-to-share description of a read ticket for a path, for anonymous access
+type TicketShare struct {
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+	Zone   string `json:"zone"`
+	Path   string `json:"path"`
+	Ticket string `json:"ticket"`
+	// ExpirationTime is zero if the ticket does not expire
+	ExpirationTime time.Time `json:"expiration_time,omitempty"`
+}
+
+// GetAccount returns an account to access the path anonymously with the ticket
+func (share *TicketShare) GetAccount() (*types.IRODSAccount, error) {
+	return types.CreateIRODSAccountForTicket(share.Host, share.Port, TicketShareUser, share.Zone, types.AuthSchemeNative, "", share.Ticket, "")
+}
+
+// GetURL returns an iRODS URL to access the path anonymously with the ticket, see util.ParseIRODSURL
+func (share *TicketShare) GetURL() (string, error) {
+	account, err := share.GetAccount()
+	if err != nil {
+		return "", err
+	}
+
+	return util.FormatIRODSURL(account, share.Path, false), nil
+}
+
+// IsExpired returns true if the ticket has expired
+func (share *TicketShare) IsExpired() bool {
+	return !share.ExpirationTime.IsZero() && time.Now().After(share.ExpirationTime)
+}
+
+// CreateTicketShare creates a read ticket with a random name for the path, and returns a description to share
+// the ticket does not expire if the expiration time is zero
+func (fs *FileSystem) CreateTicketShare(path string, expirationTime time.Time) (*TicketShare, error) {
+	irodsPath := fs.getCorrectIRODSPath(path)
+
+	// the ticket is a bearer secret, it must not be guessable
+	ticketName, err := util.MakeSecureRandomString(ticketShareNameLength)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make a ticket name: %w", err)
+	}
+
+	err = fs.CreateTicket(ticketName, types.TicketTypeRead, irodsPath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a ticket for path %s: %w", irodsPath, err)
+	}
+
+	if !expirationTime.IsZero() {
+		err = fs.ModifyTicketExpirationTime(ticketName, expirationTime)
+		if err != nil {
+			// do not leave a ticket without expiry
+			fs.DeleteTicket(ticketName)
+			return nil, xerrors.Errorf("failed to set expiration time of a ticket for path %s: %w", irodsPath, err)
+		}
+	}
+
+	account := fs.getAccount()
+	return &TicketShare{
+		Host:           account.Host,
+		Port:           account.Port,
+		Zone:           account.ClientZone,
+		Path:           irodsPath,
+		Ticket:         ticketName,
+		ExpirationTime: expirationTime,
+	}, nil
+}
+
+// NewFileSystemForTicketShare creates a new FileSystem accessing the path of the ticket share anonymously
+func NewFileSystemForTicketShare(share *TicketShare, applicationName string, options ...FileSystemConfigOption) (*FileSystem, error) {
+	if share.IsExpired() {
+		return nil, xerrors.Errorf("ticket for path %s expired at %s", share.Path, share.ExpirationTime.String())
+	}
+
+	account, err := share.GetAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileSystemWithOptions(account, applicationName, options...)
+}
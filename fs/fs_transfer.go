@@ -0,0 +1,620 @@
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TransferStatus is a status of a file transfer
+type TransferStatus string
+
+const (
+	// TransferStatusTransferred is for a file transferred
+	TransferStatusTransferred TransferStatus = "transferred"
+	// TransferStatusSkipped is for a file not transferred as the target is identical
+	TransferStatusSkipped TransferStatus = "skipped"
+)
+
+// TransferResult is a result of a file transfer
+type TransferResult struct {
+	Status    TransferStatus
+	LocalPath string
+	IRODSPath string
+	Size      int64
+	// checksum of the local file computed while uploading, empty if not computed
+	ChecksumAlgorithm types.ChecksumAlgorithm
+	Checksum          []byte
+}
+
+// UploadFileOptions is options for uploading a file
+type UploadFileOptions struct {
+	// resource to store the file, the default resource is used if empty
+	Resource string
+	// replicate the file after upload
+	Replicate bool
+	// skip upload if the iRODS file has the same size and checksum as the local file
+	SkipIdentical bool
+	// how to handle an existing iRODS file, fails if empty
+	OverwritePolicy OverwritePolicy
+	// upload to a temporary name in the same dir, then rename to the final path after successful upload
+	// readers never see a partially uploaded file, an existing file is renamed aside and restored if the final rename fails
+	// iRODS cannot rename over a file, so the path is missing for a moment between the two renames
+	UseTempName bool
+	// compare checksums of the local file and the uploaded file, the uploaded file is removed if they differ
+	// the checksum of the local file is computed while uploading unless the server uses another algorithm
+	VerifyChecksum bool
+	// compute a checksum of the local file while uploading, returned in the result
+	ComputeChecksum bool
+	// algorithm of the checksum computed while uploading, SHA-256 if empty
+	// set to the algorithm of the server to verify checksums without reading the local file again
+	ChecksumAlgorithm types.ChecksumAlgorithm
+	// have the server compute and register a checksum of the uploaded file in the catalog
+	RegisterChecksum bool
+	// give the server a checksum of the local file, the upload fails if the server computes a different checksum
+	// the checksum is registered in the catalog, the local file is read before upload to compute it with ChecksumAlgorithm
+	VerifyChecksumOnServer bool
+	// metadata (AVUs) to add to the uploaded file
+	Metadata []*types.IRODSMeta
+	// ACLs to set on the uploaded file, UserName, UserZone and AccessLevel are used
+	ACLs []*types.IRODSAccess
+	// set modify time of the uploaded file to modify time of the local file
+	PreserveModifyTime bool
+	// abort the upload when no bytes move for the period, including opening and closing the file, disabled if zero
+	StallTimeout time.Duration
+	// retry a stalled upload up to the number of times
+	StallRetries int
+	Callback     common.TrackerCallBack
+}
+
+// DownloadFileOptions is options for downloading a file
+type DownloadFileOptions struct {
+	// resource to read the file from, any resource is used if empty
+	Resource string
+	// skip download if the local file has the same size and checksum as the iRODS file
+	SkipIdentical bool
+	// download to <name>.part, then rename to the final path after successful download
+	// interrupted downloads never leave truncated files at the final path
+	UseTempName bool
+	// flush the downloaded file to disk before it is renamed
+	Sync bool
+	// set modify time of the downloaded file to modify time of the iRODS file
+	PreserveModifyTime bool
+	// abort the download when no bytes move for the period, including opening and closing the file, disabled if zero
+	StallTimeout time.Duration
+	// retry a stalled download up to the number of times
+	StallRetries int
+	Callback     common.TrackerCallBack
+}
+
+// localTempFileSuffix is a suffix of local files being downloaded
+const localTempFileSuffix string = ".part"
+
+// UploadFileWithOptions uploads a local file to irods, the file is uploaded into irodsPath if irodsPath is an existing dir
+// an existing iRODS file is handled by the overwrite policy, the path uploaded is returned in the result
+func (fs *FileSystem) UploadFileWithOptions(localPath string, irodsPath string, options *UploadFileOptions) (_ *TransferResult, err error) {
+	defer fs.audit("UploadFile", irodsPath, &err)
+
+	localSrcPath := util.GetCorrectLocalPath(localPath)
+	irodsDestPath := fs.getCorrectIRODSPath(irodsPath)
+
+	if options == nil {
+		options = &UploadFileOptions{}
+	}
+
+	irodsFilePath := irodsDestPath
+
+	srcStat, err := os.Stat(localSrcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// file not exists
+			return nil, xerrors.Errorf("failed to find a file for local path %s: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
+		}
+		return nil, err
+	}
+
+	if srcStat.IsDir() {
+		return nil, xerrors.Errorf("failed to find a file for local path %s, the path is for a directory: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
+	}
+
+	destEntry, err := fs.Stat(irodsDestPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) {
+			return nil, err
+		}
+	} else {
+		switch destEntry.Type {
+		case FileEntry:
+			// do nothing
+		case DirectoryEntry:
+			localFileName := filepath.Base(localSrcPath)
+			irodsFilePath = util.MakeIRODSPath(irodsDestPath, localFileName)
+
+			destEntry, err = fs.Stat(irodsFilePath)
+			if err != nil {
+				if !types.IsFileNotFoundError(err) {
+					return nil, err
+				}
+				destEntry = nil
+			}
+		default:
+			return nil, xerrors.Errorf("unknown entry type %s", destEntry.Type)
+		}
+	}
+
+	result := &TransferResult{
+		Status:    TransferStatusTransferred,
+		LocalPath: localSrcPath,
+		IRODSPath: irodsFilePath,
+		Size:      srcStat.Size(),
+	}
+
+	if options.SkipIdentical && destEntry != nil && destEntry.Type == FileEntry {
+		if fs.isIdenticalFile(localSrcPath, srcStat.Size(), destEntry, options.Resource) {
+			result.Status = TransferStatusSkipped
+			if options.Callback != nil {
+				options.Callback(srcStat.Size(), srcStat.Size())
+			}
+			return result, nil
+		}
+	}
+
+	irodsFilePath, overwrite, err := fs.resolveOverwrite(irodsFilePath, options.OverwritePolicy)
+	if err != nil {
+		return nil, err
+	}
+	result.IRODSPath = irodsFilePath
+
+	writePath := irodsFilePath
+	if options.UseTempName {
+		writePath = getTempUploadPath(irodsFilePath)
+	}
+
+	var hasher hash.Hash
+	var checksumOptions *irods_fs.PutChecksumOptions
+	if options.ComputeChecksum || options.VerifyChecksum || options.VerifyChecksumOnServer {
+		result.ChecksumAlgorithm = options.ChecksumAlgorithm
+		if result.ChecksumAlgorithm == types.ChecksumAlgorithmUnknown {
+			result.ChecksumAlgorithm = types.ChecksumAlgorithmSHA256
+		}
+
+		if options.VerifyChecksumOnServer {
+			// the server needs the checksum when the file is opened
+			result.Checksum, err = util.HashLocalFile(localSrcPath, string(result.ChecksumAlgorithm))
+			if err != nil {
+				return nil, err
+			}
+
+			checksumString, err := types.MakeIRODSChecksumString(result.ChecksumAlgorithm, result.Checksum)
+			if err != nil {
+				return nil, err
+			}
+
+			checksumOptions = &irods_fs.PutChecksumOptions{
+				VerifyChecksum: checksumString,
+			}
+		} else {
+			hasher, err = util.GetHash(string(result.ChecksumAlgorithm))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if options.RegisterChecksum && checksumOptions == nil {
+		checksumOptions = &irods_fs.PutChecksumOptions{
+			Register: true,
+		}
+	}
+
+	err = fs.uploadFileToPath(localSrcPath, writePath, options, hasher, checksumOptions)
+	if err != nil {
+		if options.UseTempName {
+			fs.RemoveFile(writePath, true)
+		}
+		return nil, err
+	}
+
+	if hasher != nil {
+		result.Checksum = hasher.Sum(nil)
+	}
+
+	if options.VerifyChecksum {
+		err = fs.verifyUploadChecksum(localSrcPath, writePath, options.Resource, result.ChecksumAlgorithm, result.Checksum)
+		if err != nil {
+			fs.RemoveFile(writePath, true)
+			return nil, err
+		}
+	}
+
+	// attributes are applied before rename, so the file is never seen without them
+	err = fs.applyUploadAttributes(writePath, options)
+	if err != nil {
+		if options.UseTempName {
+			fs.RemoveFile(writePath, true)
+		}
+		return nil, err
+	}
+
+	if options.UseTempName {
+		err = fs.replaceFileWithTempFile(writePath, irodsFilePath, overwrite)
+		if err != nil {
+			fs.RemoveFile(writePath, true)
+			return nil, err
+		}
+	}
+
+	if options.PreserveModifyTime {
+		err = fs.TouchFile(irodsFilePath, srcStat.ModTime())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getTempUploadPath returns a temporary path in the same dir to upload a file to
+func getTempUploadPath(p string) string {
+	tempName := fmt.Sprintf(".%s.%s.upload", util.GetIRODSPathFileName(p), xid.New().String())
+	return util.MakeIRODSPath(util.GetIRODSPathDirname(p), tempName)
+}
+
+// getBackupUploadPath returns a temporary path in the same dir to keep an existing file at while it is replaced
+func getBackupUploadPath(p string) string {
+	backupName := fmt.Sprintf(".%s.%s.old", util.GetIRODSPathFileName(p), xid.New().String())
+	return util.MakeIRODSPath(util.GetIRODSPathDirname(p), backupName)
+}
+
+// replaceFileWithTempFile renames the uploaded temp file to the path
+// iRODS does not rename over an existing file, so the existing file is renamed aside first, and restored if the rename fails
+func (fs *FileSystem) replaceFileWithTempFile(tempPath string, irodsPath string, overwrite bool) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"struct":   "FileSystem",
+		"function": "replaceFileWithTempFile",
+	})
+
+	backupPath := ""
+	if overwrite {
+		backupPath = getBackupUploadPath(irodsPath)
+		err := fs.RenameFileToFile(irodsPath, backupPath)
+		if err != nil {
+			if !types.IsFileNotFoundError(err) {
+				return xerrors.Errorf("failed to rename existing file %q aside: %w", irodsPath, err)
+			}
+			backupPath = ""
+		}
+	}
+
+	err := fs.RenameFileToFile(tempPath, irodsPath)
+	if err != nil {
+		if len(backupPath) > 0 {
+			restoreErr := fs.RenameFileToFile(backupPath, irodsPath)
+			if restoreErr != nil {
+				return xerrors.Errorf("failed to rename %q to %q, and failed to restore the existing file from %q (%v): %w", tempPath, irodsPath, backupPath, restoreErr, err)
+			}
+		}
+		return xerrors.Errorf("failed to rename %q to %q: %w", tempPath, irodsPath, err)
+	}
+
+	if len(backupPath) > 0 {
+		// the file is replaced already, do not fail the upload
+		err = fs.RemoveFile(backupPath, true)
+		if err != nil {
+			logger.WithError(err).Warnf("failed to remove the replaced file %q", backupPath)
+		}
+	}
+
+	return nil
+}
+
+// uploadFileToPath uploads a local file to the iRODS path, overwriting the existing file
+// data sent is written to the hasher if it is not nil, the server computes a checksum as checksumOptions asks if it is not nil
+func (fs *FileSystem) uploadFileToPath(localPath string, irodsPath string, options *UploadFileOptions, hasher hash.Hash, checksumOptions *irods_fs.PutChecksumOptions) error {
+	fs.pathLocks.Lock(irodsPath)
+	defer fs.pathLocks.Unlock(irodsPath)
+
+	taskNum := fs.acquireTransferTasks(0, 1)
+	defer fs.releaseTransferTasks(taskNum)
+
+	if options.StallTimeout > 0 {
+		// replication does not report progress, so it runs after the watched upload
+		err := fs.runTransferWithStallRetry(irodsPath, options.StallTimeout, options.StallRetries, options.Callback, func(conn *connection.IRODSConnection, callback common.TrackerCallBack) error {
+			if hasher != nil {
+				// a retry sends the file from the start
+				hasher.Reset()
+			}
+			return irods_fs.UploadDataObjectWithConnectionAndChecksum(conn, localPath, irodsPath, options.Resource, false, hasher, checksumOptions, callback)
+		})
+		if err != nil {
+			return err
+		}
+
+		if options.Replicate {
+			err = fs.replicateUploadedFile(irodsPath)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err := irods_fs.UploadDataObjectWithChecksum(fs.ioSession, localPath, irodsPath, options.Resource, options.Replicate, hasher, checksumOptions, options.Callback)
+		if err != nil {
+			return err
+		}
+	}
+
+	fs.invalidateCacheForFileCreate(irodsPath)
+	fs.cachePropagation.PropagateFileCreate(irodsPath)
+	return nil
+}
+
+// replicateUploadedFile replicates an uploaded file
+func (fs *FileSystem) replicateUploadedFile(irodsPath string) error {
+	conn, err := fs.ioSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.ioSession.ReturnConnection(conn)
+
+	return irods_fs.ReplicateDataObject(conn, irodsPath, "", true, false)
+}
+
+// applyUploadAttributes adds metadata and ACLs given in options to an uploaded file over a single connection
+func (fs *FileSystem) applyUploadAttributes(irodsPath string, options *UploadFileOptions) error {
+	if len(options.Metadata) == 0 && len(options.ACLs) == 0 {
+		return nil
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	attributes := &entryAttributes{
+		metas:    options.Metadata,
+		accesses: options.ACLs,
+	}
+
+	err = fs.setEntryAttributes(conn, irodsPath, false, attributes)
+	if err != nil {
+		return xerrors.Errorf("failed to apply metadata and ACLs to %s: %w", irodsPath, err)
+	}
+	return nil
+}
+
+// verifyUploadChecksum returns an error if checksums of the local file and the uploaded file differ
+// localChecksum computed while uploading is used if the server uses the same algorithm, otherwise the local file is read
+func (fs *FileSystem) verifyUploadChecksum(localPath string, irodsPath string, resource string, localAlgorithm types.ChecksumAlgorithm, localChecksum []byte) error {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	irodsChecksum, err := irods_fs.GetDataObjectChecksum(conn, irodsPath, resource)
+	if err != nil {
+		return err
+	}
+
+	if localChecksum == nil || localAlgorithm != irodsChecksum.Algorithm {
+		localChecksum, err = util.HashLocalFile(localPath, string(irodsChecksum.Algorithm))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !bytes.Equal(localChecksum, irodsChecksum.Checksum) {
+		return xerrors.Errorf("failed to verify upload of %s, checksum of %s (%s) does not match", localPath, irodsPath, irodsChecksum.IRODSChecksumString)
+	}
+	return nil
+}
+
+// DownloadFileWithOptions downloads a file to local, the file is downloaded into localPath if localPath is an existing dir
+func (fs *FileSystem) DownloadFileWithOptions(irodsPath string, localPath string, options *DownloadFileOptions) (*TransferResult, error) {
+	irodsSrcPath := fs.getCorrectIRODSPath(irodsPath)
+	localDestPath := util.GetCorrectLocalPath(localPath)
+
+	if options == nil {
+		options = &DownloadFileOptions{}
+	}
+
+	localFilePath := localDestPath
+
+	srcEntry, err := fs.Stat(irodsSrcPath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find a data object for path %s: %w", irodsSrcPath, types.NewFileNotFoundError(irodsSrcPath))
+	}
+
+	if srcEntry.Type == DirectoryEntry {
+		return nil, xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
+	}
+
+	destStat, err := os.Stat(localDestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// file not exists, it's a file
+			// pass
+		} else {
+			return nil, err
+		}
+	} else {
+		if destStat.IsDir() {
+			irodsFileName := util.GetIRODSPathFileName(irodsSrcPath)
+			localFilePath = filepath.Join(localDestPath, irodsFileName)
+
+			destStat, err = os.Stat(localFilePath)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					return nil, err
+				}
+				destStat = nil
+			}
+		}
+	}
+
+	result := &TransferResult{
+		Status:    TransferStatusTransferred,
+		LocalPath: localFilePath,
+		IRODSPath: irodsSrcPath,
+		Size:      srcEntry.Size,
+	}
+
+	if options.SkipIdentical && destStat != nil && destStat.Mode().IsRegular() {
+		if fs.isIdenticalFile(localFilePath, destStat.Size(), srcEntry, options.Resource) {
+			result.Status = TransferStatusSkipped
+			if options.Callback != nil {
+				options.Callback(srcEntry.Size, srcEntry.Size)
+			}
+			return result, nil
+		}
+	}
+
+	if !options.UseTempName {
+		err = fs.downloadFileToPath(irodsSrcPath, localFilePath, srcEntry.Size, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if options.PreserveModifyTime {
+			err = setLocalFileModifyTime(localFilePath, srcEntry.ModifyTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if options.Sync {
+			err = syncLocalFile(localFilePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	tempPath := localFilePath + localTempFileSuffix
+	err = fs.downloadFileToPath(irodsSrcPath, tempPath, srcEntry.Size, options)
+	if err != nil {
+		os.Remove(tempPath)
+		return nil, err
+	}
+
+	if options.PreserveModifyTime {
+		// rename keeps modify time
+		err = setLocalFileModifyTime(tempPath, srcEntry.ModifyTime)
+		if err != nil {
+			os.Remove(tempPath)
+			return nil, err
+		}
+	}
+
+	if options.Sync {
+		err = syncLocalFile(tempPath)
+		if err != nil {
+			os.Remove(tempPath)
+			return nil, err
+		}
+	}
+
+	err = os.Rename(tempPath, localFilePath)
+	if err != nil {
+		os.Remove(tempPath)
+		return nil, xerrors.Errorf("failed to rename %s to %s: %w", tempPath, localFilePath, err)
+	}
+
+	if options.Sync {
+		// persist the rename
+		err = syncLocalFile(filepath.Dir(localFilePath))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// downloadFileToPath downloads an iRODS file to the local path, overwriting the existing file
+func (fs *FileSystem) downloadFileToPath(irodsPath string, localPath string, size int64, options *DownloadFileOptions) error {
+	taskNum := fs.acquireTransferTasks(size, 1)
+	defer fs.releaseTransferTasks(taskNum)
+
+	if options.StallTimeout > 0 {
+		return fs.runTransferWithStallRetry(irodsPath, options.StallTimeout, options.StallRetries, options.Callback, func(conn *connection.IRODSConnection, callback common.TrackerCallBack) error {
+			return irods_fs.DownloadDataObjectWithConnection(conn, irodsPath, options.Resource, localPath, size, callback)
+		})
+	}
+
+	return irods_fs.DownloadDataObject(fs.ioSession, irodsPath, options.Resource, localPath, size, options.Callback)
+}
+
+// setLocalFileModifyTime sets modify time of a local file, access time is set to now
+func setLocalFileModifyTime(localPath string, modifyTime time.Time) error {
+	err := os.Chtimes(localPath, time.Now(), modifyTime)
+	if err != nil {
+		return xerrors.Errorf("failed to set modify time of %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// syncLocalFile flushes a local file or dir to disk
+func syncLocalFile(localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return xerrors.Errorf("failed to open %s: %w", localPath, err)
+	}
+	defer f.Close()
+
+	err = f.Sync()
+	if err != nil {
+		return xerrors.Errorf("failed to sync %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// isIdenticalFile returns true if a local file has the same size and checksum as an iRODS file
+// the checksum of the iRODS file is computed if not registered, files are treated as different if it fails
+func (fs *FileSystem) isIdenticalFile(localPath string, localSize int64, entry *Entry, resource string) bool {
+	if localSize != entry.Size {
+		return false
+	}
+
+	algorithm := entry.CheckSumAlgorithm
+	checksum := entry.CheckSum
+
+	if len(checksum) == 0 {
+		conn, err := fs.metaSession.AcquireConnection()
+		if err != nil {
+			return false
+		}
+		defer fs.metaSession.ReturnConnection(conn)
+
+		irodsChecksum, err := irods_fs.GetDataObjectChecksum(conn, entry.Path, resource)
+		if err != nil {
+			return false
+		}
+
+		algorithm = irodsChecksum.Algorithm
+		checksum = irodsChecksum.Checksum
+	}
+
+	localChecksum, err := util.HashLocalFile(localPath, string(algorithm))
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(localChecksum, checksum)
+}
@@ -0,0 +1,124 @@
+package fs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// transferWatchdog interrupts the connection of a transfer when no bytes move for a period
+type transferWatchdog struct {
+	conn          *connection.IRODSConnection
+	timeout       time.Duration
+	lastProcessed int64
+	lastProgress  time.Time
+	stalled       bool
+	done          chan bool
+	waitGroup     sync.WaitGroup
+	mutex         sync.Mutex
+}
+
+// newTransferWatchdog creates a new transferWatchdog
+func newTransferWatchdog(conn *connection.IRODSConnection, timeout time.Duration) *transferWatchdog {
+	return &transferWatchdog{
+		conn:          conn,
+		timeout:       timeout,
+		lastProcessed: -1,
+		lastProgress:  time.Now(),
+		done:          make(chan bool),
+	}
+}
+
+// wrapCallback returns a callback recording progress of the transfer before calling the given callback
+func (watchdog *transferWatchdog) wrapCallback(callback common.TrackerCallBack) common.TrackerCallBack {
+	return func(processed int64, total int64) {
+		watchdog.mutex.Lock()
+		if processed != watchdog.lastProcessed {
+			watchdog.lastProcessed = processed
+			watchdog.lastProgress = time.Now()
+		}
+		watchdog.mutex.Unlock()
+
+		if callback != nil {
+			callback(processed, total)
+		}
+	}
+}
+
+// start starts watching the transfer in background
+func (watchdog *transferWatchdog) start() {
+	watchdog.waitGroup.Add(1)
+	go func() {
+		defer watchdog.waitGroup.Done()
+
+		ticker := time.NewTicker(watchdog.timeout / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watchdog.done:
+				return
+			case <-ticker.C:
+				watchdog.mutex.Lock()
+				stalled := time.Since(watchdog.lastProgress) >= watchdog.timeout
+				watchdog.stalled = stalled
+				watchdog.mutex.Unlock()
+
+				if stalled {
+					// unblocks the hung request
+					watchdog.conn.Interrupt()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// stop stops watching the transfer, returns true if the transfer was interrupted as stalled
+func (watchdog *transferWatchdog) stop() bool {
+	close(watchdog.done)
+	watchdog.waitGroup.Wait()
+
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+
+	return watchdog.stalled
+}
+
+// transferFunc transfers a file over the given connection
+type transferFunc func(conn *connection.IRODSConnection, callback common.TrackerCallBack) error
+
+// runTransferWithStallRetry runs a transfer, aborting it when no bytes move for stallTimeout
+// a stalled transfer is retried up to retries times, each on a new connection
+func (fs *FileSystem) runTransferWithStallRetry(irodsPath string, stallTimeout time.Duration, retries int, callback common.TrackerCallBack, transfer transferFunc) error {
+	for attempt := 0; ; attempt++ {
+		err := fs.runTransferWithWatchdog(irodsPath, stallTimeout, callback, transfer)
+		if err == nil || !types.IsTransferStalledError(err) || attempt >= retries {
+			return err
+		}
+	}
+}
+
+// runTransferWithWatchdog runs a transfer on an io connection, aborting it when no bytes move for stallTimeout
+func (fs *FileSystem) runTransferWithWatchdog(irodsPath string, stallTimeout time.Duration, callback common.TrackerCallBack, transfer transferFunc) error {
+	conn, err := fs.ioSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	// an interrupted connection is discarded on return
+	defer fs.ioSession.ReturnConnection(conn)
+
+	watchdog := newTransferWatchdog(conn, stallTimeout)
+	watchdog.start()
+
+	err = transfer(conn, watchdog.wrapCallback(callback))
+
+	if watchdog.stop() {
+		return xerrors.Errorf("failed to transfer %s: %w", irodsPath, types.NewTransferStalledError(irodsPath, stallTimeout))
+	}
+	return err
+}
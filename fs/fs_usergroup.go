@@ -3,8 +3,39 @@ package fs
 import (
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
 )
 
+// GetUser returns user info, zone is optional, client zone is used if empty
+func (fs *FileSystem) GetUser(username string, zone string) (*types.IRODSUser, error) {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	// check cache first
+	cachedUser := fs.cache.GetUserCache(username, zone)
+	if cachedUser != nil {
+		return cachedUser, nil
+	}
+
+	// otherwise, retrieve it and add it to cache
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	user, err := irods_fs.GetUser(conn, username, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	// cache it
+	fs.cache.AddUserCache(user)
+
+	return user, nil
+}
+
 // ListGroupUsers lists all users in a group
 func (fs *FileSystem) ListGroupUsers(group string) ([]*types.IRODSUser, error) {
 	// check cache first
@@ -31,6 +62,57 @@ func (fs *FileSystem) ListGroupUsers(group string) ([]*types.IRODSUser, error) {
 	return users, nil
 }
 
+// GetGroup returns group info with its member count
+func (fs *FileSystem) GetGroup(group string) (*types.IRODSGroup, error) {
+	// check cache first
+	cachedGroup := fs.cache.GetGroupCache(group)
+	if cachedGroup != nil {
+		return cachedGroup, nil
+	}
+
+	// members are cached separately
+	members, err := fs.ListGroupUsers(group)
+	if err != nil {
+		return nil, err
+	}
+
+	// otherwise, retrieve it and add it to cache
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	groupUser, err := irods_fs.GetGroup(conn, group)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCount := 0
+	for _, member := range members {
+		// a group is a member of itself
+		if member.Name == groupUser.Name && member.Zone == groupUser.Zone {
+			continue
+		}
+		memberCount++
+	}
+
+	irodsGroup := &types.IRODSGroup{
+		ID:          groupUser.ID,
+		Name:        groupUser.Name,
+		Zone:        groupUser.Zone,
+		Description: groupUser.Comment,
+		MemberCount: memberCount,
+		CreateTime:  groupUser.CreateTime,
+		ModifyTime:  groupUser.ModifyTime,
+	}
+
+	// cache it
+	fs.cache.AddGroupCache(irodsGroup)
+
+	return irodsGroup, nil
+}
+
 // ListGroups lists all groups
 func (fs *FileSystem) ListGroups() ([]*types.IRODSUser, error) {
 	// check cache first
@@ -118,3 +200,229 @@ func (fs *FileSystem) ListUsers() ([]*types.IRODSUser, error) {
 
 	return users, nil
 }
+
+// ListUsersByNameWildcard lists users whose names match the pattern in SQL LIKE syntax, e.g., "ali%"
+func (fs *FileSystem) ListUsersByNameWildcard(namePattern string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	users, err := irods_fs.ListUsersByNameWildcard(conn, namePattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ListGroupsByNameWildcard lists groups whose names match the pattern in SQL LIKE syntax, e.g., "lab%"
+func (fs *FileSystem) ListGroupsByNameWildcard(namePattern string) ([]*types.IRODSUser, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	groups, err := irods_fs.ListGroupsByNameWildcard(conn, namePattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// CreateUser creates a user of the type, zone is optional, client zone is used if empty
+func (fs *FileSystem) CreateUser(username string, zone string, userType types.IRODSUserType) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.CreateUser(conn, username, zone, string(userType))
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserCreate(username, zone, userType)
+	return nil
+}
+
+// SetUserType changes the type of a user to rodsuser, groupadmin or rodsadmin, zone is optional, client zone is used if empty
+func (fs *FileSystem) SetUserType(username string, zone string, userType types.IRODSUserType) error {
+	switch userType {
+	case types.IRODSUserRodsUser, types.IRODSUserGroupAdmin, types.IRODSUserRodsAdmin:
+		// ok
+	default:
+		return xerrors.Errorf("failed to set type of user %s to %q, a user can be rodsuser, groupadmin or rodsadmin", username, userType)
+	}
+
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.ChangeUserType(conn, username, zone, string(userType))
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserUpdate(username, zone)
+	return nil
+}
+
+// SetUserInfo changes the info of a user, zone is optional, client zone is used if empty
+func (fs *FileSystem) SetUserInfo(username string, zone string, info string) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.ChangeUserInfo(conn, username, zone, info)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserUpdate(username, zone)
+	return nil
+}
+
+// DisableUser marks a user disabled in its info, other info of the user is kept
+// iRODS does not block disabled users, see types.IRODSUserInfoDisabled
+func (fs *FileSystem) DisableUser(username string, zone string) error {
+	return fs.updateUserInfo(username, zone, types.MarkIRODSUserInfoDisabled)
+}
+
+// EnableUser clears the disabled mark of a user, other info of the user is kept
+func (fs *FileSystem) EnableUser(username string, zone string) error {
+	return fs.updateUserInfo(username, zone, types.UnmarkIRODSUserInfoDisabled)
+}
+
+// updateUserInfo reads the info of a user from the server and changes it with update
+// the read and the change are not atomic, a concurrent change of the info in between is lost
+func (fs *FileSystem) updateUserInfo(username string, zone string, update func(info string) string) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	// do not use cache, the info may have changed
+	user, err := irods_fs.GetUser(conn, username, zone)
+	if err != nil {
+		return err
+	}
+
+	info := update(user.Info)
+	if info == user.Info {
+		return nil
+	}
+
+	err = irods_fs.ChangeUserInfo(conn, username, zone, info)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserUpdate(username, zone)
+	return nil
+}
+
+// RemoveUser removes a user or a group, zone is optional, client zone is used if empty
+func (fs *FileSystem) RemoveUser(username string, zone string) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.RemoveUser(conn, username, zone)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserRemove(username, zone)
+	return nil
+}
+
+// CreateGroup creates a group
+func (fs *FileSystem) CreateGroup(group string) error {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.CreateGroup(conn, group, string(types.IRODSUserRodsGroup))
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForUserCreate(group, fs.getAccount().ClientZone, types.IRODSUserRodsGroup)
+	return nil
+}
+
+// AddGroupMember adds a user to a group, zone is optional, client zone is used if empty
+func (fs *FileSystem) AddGroupMember(group string, username string, zone string) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.AddGroupMember(conn, group, username, zone)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForGroupMemberUpdate(group, username)
+	return nil
+}
+
+// RemoveGroupMember removes a user from a group, zone is optional, client zone is used if empty
+func (fs *FileSystem) RemoveGroupMember(group string, username string, zone string) error {
+	if len(zone) == 0 {
+		zone = fs.getAccount().ClientZone
+	}
+
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	err = irods_fs.RemoveGroupMember(conn, group, username, zone)
+	if err != nil {
+		return err
+	}
+
+	fs.invalidateCacheForGroupMemberUpdate(group, username)
+	return nil
+}
@@ -0,0 +1,118 @@
+package fs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+const (
+	// VersionsDirName is a name of the dir keeping previous versions of files
+	VersionsDirName string = ".versions"
+	// VersionAttributeName is a name of the AVU applied to previous versions, the value is the path of the file versioned
+	// and the units is the time the version is made
+	VersionAttributeName string = "go-irodsclient::version_of"
+	// versionTimeFormat is a format of timestamp suffix of previous versions
+	versionTimeFormat string = "20060102T150405.000000000Z"
+)
+
+// GetVersionsDirPath returns a path of the dir keeping previous versions of the file
+func GetVersionsDirPath(p string) string {
+	return util.MakeIRODSPath(util.GetIRODSPathDirname(p), VersionsDirName)
+}
+
+// KeepFileVersion moves a file into the versions dir next to it, the file name is suffixed with the current time
+// the version is tagged with VersionAttributeName AVU
+// returns a path of the previous version
+func (fs *FileSystem) KeepFileVersion(p string) (string, error) {
+	irodsPath := fs.getCorrectIRODSPath(p)
+
+	versionsDirPath := GetVersionsDirPath(irodsPath)
+	err := fs.MakeDir(versionsDirPath, true)
+	if err != nil {
+		return "", err
+	}
+
+	versionTime := time.Now().UTC().Format(versionTimeFormat)
+	versionName := fmt.Sprintf("%s.%s", util.GetIRODSPathFileName(irodsPath), versionTime)
+	versionPath := util.MakeIRODSPath(versionsDirPath, versionName)
+
+	err = fs.RenameFileToFile(irodsPath, versionPath)
+	if err != nil {
+		return "", err
+	}
+
+	err = fs.AddMetadata(versionPath, VersionAttributeName, irodsPath, versionTime)
+	if err != nil {
+		return "", err
+	}
+
+	return versionPath, nil
+}
+
+// keepFileVersionIfExists keeps a previous version of the file if versioning is enabled and the file exists
+func (fs *FileSystem) keepFileVersionIfExists(p string) error {
+	if !fs.config.Versioning {
+		return nil
+	}
+
+	entry, err := fs.Stat(p)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if entry.Type != FileEntry {
+		return nil
+	}
+
+	_, err = fs.KeepFileVersion(p)
+	return err
+}
+
+// ListFileVersions returns previous versions of the file kept in the versions dir, oldest first
+func (fs *FileSystem) ListFileVersions(p string) ([]*Entry, error) {
+	irodsPath := fs.getCorrectIRODSPath(p)
+
+	versionsDirPath := GetVersionsDirPath(irodsPath)
+	if !fs.ExistsDir(versionsDirPath) {
+		return []*Entry{}, nil
+	}
+
+	entries, err := fs.List(versionsDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := util.GetIRODSPathFileName(irodsPath) + "."
+	versions := []*Entry{}
+	for _, entry := range entries {
+		if entry.Type != FileEntry {
+			continue
+		}
+
+		versionTime := strings.TrimPrefix(entry.Name, prefix)
+		if versionTime == entry.Name {
+			continue
+		}
+
+		if _, err := time.Parse(versionTimeFormat, versionTime); err != nil {
+			continue
+		}
+
+		versions = append(versions, entry)
+	}
+
+	// timestamp suffixes sort in time order
+	sort.Slice(versions, func(i int, j int) bool {
+		return versions[i].Name < versions[j].Name
+	})
+
+	return versions, nil
+}
@@ -0,0 +1,33 @@
+package fs
+
+import (
+	"os"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// ToOSError converts iRODS errors to *os.PathError, so they can be tested with os.IsNotExist, os.IsExist and os.IsPermission
+func ToOSError(op string, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case types.IsFileNotFoundError(err):
+		return &os.PathError{Op: op, Path: path, Err: os.ErrNotExist}
+	case types.IsFileAlreadyExistError(err):
+		return &os.PathError{Op: op, Path: path, Err: os.ErrExist}
+	}
+
+	switch types.GetIRODSErrorCode(err) {
+	case common.CAT_UNKNOWN_COLLECTION, common.CAT_UNKNOWN_FILE:
+		return &os.PathError{Op: op, Path: path, Err: os.ErrNotExist}
+	case common.CAT_NAME_EXISTS_AS_COLLECTION, common.CAT_NAME_EXISTS_AS_DATAOBJ:
+		return &os.PathError{Op: op, Path: path, Err: os.ErrExist}
+	case common.CAT_NO_ACCESS_PERMISSION:
+		return &os.PathError{Op: op, Path: path, Err: os.ErrPermission}
+	}
+
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
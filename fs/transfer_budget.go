@@ -0,0 +1,115 @@
+package fs
+
+import (
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+// TransferBudget limits the number of data connections used by transfers at the same time
+// a transfer waits for at least one connection and takes as many as available up to what it asks for,
+// so transfers running together share the budget with fewer tasks instead of waiting for each other
+type TransferBudget struct {
+	max   int
+	inUse int
+	mutex sync.Mutex
+	cond  *sync.Cond
+}
+
+// NewTransferBudget creates a new TransferBudget allowing max data connections at the same time, 1 if less than 1
+func NewTransferBudget(max int) *TransferBudget {
+	if max < 1 {
+		max = 1
+	}
+
+	budget := &TransferBudget{
+		max:   max,
+		inUse: 0,
+		mutex: sync.Mutex{},
+	}
+	budget.cond = sync.NewCond(&budget.mutex)
+
+	return budget
+}
+
+// GetMax returns the max number of data connections
+func (budget *TransferBudget) GetMax() int {
+	return budget.max
+}
+
+// GetInUse returns the number of data connections in use
+func (budget *TransferBudget) GetInUse() int {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	return budget.inUse
+}
+
+// Acquire waits until a data connection is available, returns the number of connections taken, between 1 and want
+func (budget *TransferBudget) Acquire(want int) int {
+	if want < 1 {
+		want = 1
+	}
+
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	for budget.inUse >= budget.max {
+		budget.cond.Wait()
+	}
+
+	taken := budget.max - budget.inUse
+	if taken > want {
+		taken = want
+	}
+
+	budget.inUse += taken
+	return taken
+}
+
+// Release returns data connections taken by Acquire
+func (budget *TransferBudget) Release(taken int) {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	budget.inUse -= taken
+	if budget.inUse < 0 {
+		budget.inUse = 0
+	}
+
+	budget.cond.Broadcast()
+}
+
+// GetTransferBudget returns the budget of data connections shared by transfers, nil if transfers are not limited
+func (fs *FileSystem) GetTransferBudget() *TransferBudget {
+	return fs.transferBudget
+}
+
+// acquireTransferTasks takes data connections for a transfer of the size from the transfer budget
+// taskNum is decided by the size if not positive, returns the number of tasks the transfer can use
+// returns taskNum as is if transfers are not limited
+func (fs *FileSystem) acquireTransferTasks(size int64, taskNum int) int {
+	if fs.transferBudget == nil {
+		return taskNum
+	}
+
+	if taskNum <= 0 {
+		taskNum = util.GetNumTasksForParallelTransferWithBlockSize(size, fs.ioSession.GetConfig().TransferBlockSize)
+	}
+
+	connectionMax := fs.ioSession.GetConfig().ConnectionMax
+	if taskNum > connectionMax {
+		taskNum = connectionMax
+	}
+
+	return fs.transferBudget.Acquire(taskNum)
+}
+
+// releaseTransferTasks returns data connections taken by acquireTransferTasks
+func (fs *FileSystem) releaseTransferTasks(taskNum int) {
+	if fs.transferBudget == nil {
+		return
+	}
+
+	fs.transferBudget.Release(taskNum)
+}
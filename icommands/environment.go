@@ -91,6 +91,9 @@ func CreateIcommandsEnvironmentManagerFromIRODSAccount(account *types.IRODSAccou
 		manager.Environment.EncryptionAlgorithm = account.SSLConfiguration.EncryptionAlgorithm
 		manager.Environment.EncryptionSaltSize = account.SSLConfiguration.SaltSize
 		manager.Environment.EncryptionNumHashRounds = account.SSLConfiguration.HashRounds
+		if account.SSLConfiguration.InsecureSkipVerify {
+			manager.Environment.SSLVerifyServer = "none"
+		}
 	}
 
 	manager.Password = account.Password
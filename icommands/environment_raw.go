@@ -111,6 +111,7 @@ func (env *ICommandsEnvironment) ToIRODSAccount() *types.IRODSAccount {
 			EncryptionAlgorithm: env.EncryptionAlgorithm,
 			SaltSize:            env.EncryptionSaltSize,
 			HashRounds:          env.EncryptionNumHashRounds,
+			InsecureSkipVerify:  strings.ToLower(env.SSLVerifyServer) == "none",
 		},
 	}
 
@@ -264,4 +264,7 @@ const (
 	SPEC_COLL_REPL_NUM     KeyWord = "spec_coll_repl_num"
 
 	DISABLE_STRICT_ACL_KW KeyWord = "disable_strict_acls"
+
+	// rule engine plugin instance to run a rule
+	INSTANCE_NAME_KW KeyWord = "instance_name"
 )
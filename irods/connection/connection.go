@@ -26,6 +26,9 @@ import (
 
 const (
 	TCPBufferSizeDefault int = 4 * 1024 * 1024
+
+	// servers older than this major version are not supported
+	minServerMajorVersion int = 4
 )
 
 // IRODSConnection connects to iRODS
@@ -34,6 +37,8 @@ type IRODSConnection struct {
 	requestTimeout  time.Duration
 	tcpBufferSize   int
 	applicationName string
+	// size of data read or written in a request during transfers
+	transferBufferSize int
 
 	connected            bool
 	isSSLSocket          bool
@@ -44,10 +49,17 @@ type IRODSConnection struct {
 	lastSuccessfulAccess time.Time
 	clientSignature      string
 	dirtyTransaction     bool
+	nativeProtocol       bool // true if messages are packed in native protocol
 	mutex                sync.Mutex
-	locked               bool // true if mutex is locked
-
-	metrics *metrics.IRODSMetrics
+	locked               bool       // true if mutex is locked
+	socketMutex          sync.Mutex // guards socket replacement against Interrupt
+
+	metrics     *metrics.IRODSMetrics
+	rateLimiter *util.RateLimiter // if set, requests wait for the limiter, which can be shared with other connections
+	// if set, the TLS config and TLS sessions are shared with other connections
+	tlsConfigCache *TLSConfigCache
+	// keywords added to requests having a KeyValPair
+	keywords map[common.KeyWord]string
 }
 
 // NewIRODSConnection create a IRODSConnection
@@ -58,6 +70,8 @@ func NewIRODSConnection(account *types.IRODSAccount, requestTimeout time.Duratio
 		tcpBufferSize:   TCPBufferSizeDefault,
 		applicationName: applicationName,
 
+		transferBufferSize: common.ReadWriteBufferSize,
+
 		creationTime:     time.Now(),
 		clientSignature:  "",
 		dirtyTransaction: false,
@@ -75,6 +89,8 @@ func NewIRODSConnectionWithMetrics(account *types.IRODSAccount, requestTimeout t
 		tcpBufferSize:   TCPBufferSizeDefault,
 		applicationName: applicationName,
 
+		transferBufferSize: common.ReadWriteBufferSize,
+
 		creationTime:     time.Now(),
 		clientSignature:  "",
 		dirtyTransaction: false,
@@ -90,6 +106,15 @@ func (conn *IRODSConnection) Lock() {
 	conn.locked = true
 }
 
+// TryLock locks connection if it is not in use, returns false if it is in use
+func (conn *IRODSConnection) TryLock() bool {
+	if !conn.mutex.TryLock() {
+		return false
+	}
+	conn.locked = true
+	return true
+}
+
 // Unlock unlocks connection
 func (conn *IRODSConnection) Unlock() {
 	conn.locked = false
@@ -111,12 +136,61 @@ func (conn *IRODSConnection) SetTCPBufferSize(bufferSize int) {
 	conn.tcpBufferSize = bufferSize
 }
 
+// SetTransferBufferSize sets the size of data read or written in a request during transfers, common.ReadWriteBufferSize if not positive
+func (conn *IRODSConnection) SetTransferBufferSize(bufferSize int) {
+	if bufferSize <= 0 {
+		bufferSize = common.ReadWriteBufferSize
+	}
+	conn.transferBufferSize = bufferSize
+}
+
+// GetTransferBufferSize returns the size of data read or written in a request during transfers
+func (conn *IRODSConnection) GetTransferBufferSize() int {
+	return conn.transferBufferSize
+}
+
+// SetTLSConfigCache sets a cache of the TLS config shared with other connections, nil builds a TLS config for the connection
+func (conn *IRODSConnection) SetTLSConfigCache(cache *TLSConfigCache) {
+	conn.tlsConfigCache = cache
+}
+
+// SetRateLimiter sets a limiter of the request rate, nil disables rate limiting
+func (conn *IRODSConnection) SetRateLimiter(limiter *util.RateLimiter) {
+	conn.rateLimiter = limiter
+}
+
+// SetKeywords sets keywords to be added to all requests having a KeyValPair sent over the connection, e.g., FORCE_FLAG_KW or TICKET_KW
+// keywords already given by a request are sent first, the server takes the first one of the same keyword
+// nil clears the keywords
+func (conn *IRODSConnection) SetKeywords(keywords map[common.KeyWord]string) {
+	if len(keywords) == 0 {
+		conn.keywords = nil
+		return
+	}
+
+	conn.keywords = map[common.KeyWord]string{}
+	for key, val := range keywords {
+		conn.keywords[key] = val
+	}
+}
+
+// GetKeywords returns keywords added to requests sent over the connection
+func (conn *IRODSConnection) GetKeywords() map[common.KeyWord]string {
+	return conn.keywords
+}
+
 // SupportParallelUpload checks if the server supports parallel upload
 // available from 4.2.9
 func (conn *IRODSConnection) SupportParallelUpload() bool {
 	return conn.serverVersion.HasHigherVersionThan(4, 2, 9)
 }
 
+// SupportAtomicMetadata checks if the server supports atomic metadata operations
+// available from 4.2.8
+func (conn *IRODSConnection) SupportAtomicMetadata() bool {
+	return conn.serverVersion.HasHigherVersionThan(4, 2, 8)
+}
+
 func (conn *IRODSConnection) requiresCSNegotiation() bool {
 	return conn.account.ClientServerNegotiation
 }
@@ -136,6 +210,11 @@ func (conn *IRODSConnection) IsConnected() bool {
 	return conn.connected
 }
 
+// IsNativeProtocol returns if the connection uses native protocol
+func (conn *IRODSConnection) IsNativeProtocol() bool {
+	return conn.nativeProtocol
+}
+
 // IsSSL returns if the connection is ssl
 func (conn *IRODSConnection) IsSSL() bool {
 	return conn.isSSLSocket
@@ -201,6 +280,16 @@ func (conn *IRODSConnection) setSocketOpt(socket net.Conn, bufferSize int) {
 	}
 }
 
+// getStartupError returns an error for the startup rejected by the server
+// rejections by server connection control are returned as ServerUnavailableError, to wait for the server to be back
+func (conn *IRODSConnection) getStartupError(err error) error {
+	code := types.GetIRODSErrorCode(err)
+	if types.IsServerUnavailableErrorCode(code) {
+		return xerrors.Errorf("server rejected startup (%s): %w", err.Error(), types.NewServerUnavailableError(conn.account.Host, conn.account.Port, code))
+	}
+	return xerrors.Errorf("server rejected startup (%s): %w", err.Error(), types.NewConnectionError())
+}
+
 // Connect connects to iRODS
 func (conn *IRODSConnection) Connect() error {
 	logger := log.WithFields(log.Fields{
@@ -210,12 +299,13 @@ func (conn *IRODSConnection) Connect() error {
 	})
 
 	conn.connected = false
+	conn.nativeProtocol = false
 
 	conn.account.FixAuthConfiguration()
 
 	err := conn.account.Validate()
 	if err != nil {
-		return xerrors.Errorf("invalid account (%s): %w", err.Error(), types.NewConnectionConfigError(conn.account))
+		return xerrors.Errorf("invalid account: %w", err)
 	}
 
 	// lock the connection
@@ -232,7 +322,7 @@ func (conn *IRODSConnection) Connect() error {
 
 	socket, err := dialer.DialContext(ctx, "tcp", server)
 	if err != nil {
-		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.account.Host, conn.account.Port, err.Error(), types.NewConnectionError())
+		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.account.Host, conn.account.Port, err.Error(), types.NewConnectionRefusedError(conn.account.Host, conn.account.Port))
 		logger.Errorf("%+v", connErr)
 
 		if conn.metrics != nil {
@@ -247,7 +337,7 @@ func (conn *IRODSConnection) Connect() error {
 		conn.metrics.IncreaseConnectionsOpened(1)
 	}
 
-	conn.socket = socket
+	conn.setSocket(socket)
 	var irodsVersion *types.IRODSVersion
 
 	if conn.requiresCSNegotiation() {
@@ -259,7 +349,7 @@ func (conn *IRODSConnection) Connect() error {
 	}
 
 	if err != nil {
-		connErr := xerrors.Errorf("failed to startup an iRODS connection to server %s and port %d (%s): %w", conn.account.Host, conn.account.Port, err.Error(), types.NewConnectionError())
+		connErr := xerrors.Errorf("failed to startup an iRODS connection to server %s and port %d: %w", conn.account.Host, conn.account.Port, err)
 		logger.Errorf("%+v", connErr)
 		_ = conn.disconnectNow()
 		if conn.metrics != nil {
@@ -268,8 +358,18 @@ func (conn *IRODSConnection) Connect() error {
 		return connErr
 	}
 
+	if !irodsVersion.HasHigherVersionThan(minServerMajorVersion, 0, 0) {
+		connErr := xerrors.Errorf("failed to startup an iRODS connection to server %s and port %d: %w", conn.account.Host, conn.account.Port, types.NewVersionIncompatibleError(irodsVersion.ReleaseVersion, fmt.Sprintf("rods%d.0.0", minServerMajorVersion)))
+		logger.Errorf("%+v", connErr)
+		_ = conn.disconnectNow()
+		return connErr
+	}
+
 	conn.serverVersion = irodsVersion
 
+	// startup, negotiation and version messages are always in XML, the rest follows the protocol requested in startup pack
+	conn.nativeProtocol = conn.account.Protocol == types.IRODSProtocolNative
+
 	switch conn.account.AuthenticationScheme {
 	case types.AuthSchemeNative:
 		err = conn.loginNative()
@@ -296,7 +396,7 @@ func (conn *IRODSConnection) Connect() error {
 		req := message.NewIRODSMessageTicketAdminRequest("session", conn.account.Ticket)
 		err := conn.RequestAndCheck(req, &message.IRODSMessageAdminResponse{}, nil)
 		if err != nil {
-			return xerrors.Errorf("received supply ticket error (%s): %w", err.Error(), types.NewAuthError(conn.account))
+			return xerrors.Errorf("received supply ticket error: %w", conn.getLoginError(err))
 		}
 	}
 
@@ -347,6 +447,11 @@ func (conn *IRODSConnection) connectWithCSNegotiation() (*types.IRODSVersion, er
 			return nil, xerrors.Errorf("failed to receive negotiation message (%s): %w", err.Error(), types.NewConnectionError())
 		}
 
+		err = version.CheckError()
+		if err != nil {
+			return nil, conn.getStartupError(err)
+		}
+
 		return version.GetVersion(), nil
 	} else if negotiationMessage.Body.Type == message.RODS_MESSAGE_CS_NEG_TYPE {
 		// Server responds with its own negotiation policy
@@ -360,7 +465,7 @@ func (conn *IRODSConnection) connectWithCSNegotiation() (*types.IRODSVersion, er
 
 		serverPolicy, err := types.GetCSNegotiationRequire(negotiation.Result)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to parse server policy (%s): %w", err.Error(), types.NewConnectionError())
+			return nil, xerrors.Errorf("failed to parse server policy: %w", types.NewSSLNegotiationError(conn.account.Host, conn.account.Port, err.Error()))
 		}
 
 		logger.Debugf("Client policy - %s, server policy - %s", clientPolicy, serverPolicy)
@@ -370,7 +475,8 @@ func (conn *IRODSConnection) connectWithCSNegotiation() (*types.IRODSVersion, er
 
 		// If negotiation failed we're done
 		if policyResult == types.CSNegotiationFailure {
-			return nil, xerrors.Errorf("client-server negotiation failed - %s, %s: %w", string(clientPolicy), string(serverPolicy), types.NewConnectionError())
+			reason := fmt.Sprintf("client policy %s, server policy %s", string(clientPolicy), string(serverPolicy))
+			return nil, xerrors.Errorf("client-server negotiation failed: %w", types.NewSSLNegotiationError(conn.account.Host, conn.account.Port, reason))
 		}
 
 		// Send negotiation result to server
@@ -381,6 +487,11 @@ func (conn *IRODSConnection) connectWithCSNegotiation() (*types.IRODSVersion, er
 			return nil, xerrors.Errorf("failed to receive version message (%s): %w", err.Error(), types.NewConnectionError())
 		}
 
+		err = version.CheckError()
+		if err != nil {
+			return nil, conn.getStartupError(err)
+		}
+
 		if policyResult == types.CSNegotiationUseSSL {
 			err := conn.sslStartup()
 			if err != nil {
@@ -412,6 +523,11 @@ func (conn *IRODSConnection) connectWithoutCSNegotiation() (*types.IRODSVersion,
 		return nil, xerrors.Errorf("failed to receive version message (%s): %w", err.Error(), types.NewConnectionError())
 	}
 
+	err = version.CheckError()
+	if err != nil {
+		return nil, conn.getStartupError(err)
+	}
+
 	return version.GetVersion(), nil
 }
 
@@ -429,21 +545,21 @@ func (conn *IRODSConnection) sslStartup() error {
 		return xerrors.Errorf("SSL Configuration is not set: %w", types.NewConnectionConfigError(conn.account))
 	}
 
-	caCertPool, err := irodsSSLConfig.LoadCACert()
-	if err != nil {
-		return xerrors.Errorf("Failed to load CA Certificates: %w", err)
-	}
-
 	serverName := conn.account.Host
 
 	if conn.account.ServerNameTLS != "" {
 		serverName = conn.account.ServerNameTLS
 	}
 
-	sslConf := &tls.Config{
-		RootCAs:            caCertPool,
-		ServerName:         serverName,
-		InsecureSkipVerify: conn.account.SkipVerifyTLS,
+	var sslConf *tls.Config
+	var err error
+	if conn.tlsConfigCache != nil {
+		sslConf, err = conn.tlsConfigCache.GetTLSConfig(irodsSSLConfig, serverName, conn.account.SkipVerifyTLS)
+	} else {
+		sslConf, err = irodsSSLConfig.GetTLSConfig(serverName, conn.account.SkipVerifyTLS)
+	}
+	if err != nil {
+		return xerrors.Errorf("failed to create TLS config (%s): %w", err.Error(), types.NewConnectionConfigError(conn.account))
 	}
 
 	// Create a side connection using the existing socket
@@ -451,11 +567,15 @@ func (conn *IRODSConnection) sslStartup() error {
 
 	err = sslSocket.Handshake()
 	if err != nil {
-		return xerrors.Errorf("SSL Handshake error (%s): %w", err.Error(), types.NewConnectionError())
+		return xerrors.Errorf("SSL Handshake error: %w", types.NewSSLNegotiationError(conn.account.Host, conn.account.Port, err.Error()))
+	}
+
+	if sslSocket.ConnectionState().DidResume {
+		logger.Debug("Resumed TLS session")
 	}
 
 	// from now on use ssl socket
-	conn.socket = sslSocket
+	conn.setSocket(sslSocket)
 	conn.isSSLSocket = true
 
 	// Generate a key (shared secret)
@@ -490,7 +610,7 @@ func (conn *IRODSConnection) login(password string) error {
 	authChallenge := message.IRODSMessageAuthChallengeResponse{}
 	err := conn.Request(authRequest, &authChallenge, nil)
 	if err != nil {
-		return xerrors.Errorf("failed to receive authentication challenge message body (%s): %w", err.Error(), types.NewAuthError(conn.account))
+		return xerrors.Errorf("failed to receive authentication challenge message body (%s): %w", err.Error(), types.NewConnectionError())
 	}
 
 	challengeBytes, err := authChallenge.GetChallenge()
@@ -503,15 +623,24 @@ func (conn *IRODSConnection) login(password string) error {
 
 	encodedPassword := auth.GenerateAuthResponse(challengeBytes, password)
 
-	authResponse := message.NewIRODSMessageAuthResponse(encodedPassword, conn.account.ProxyUser)
+	// the server looks up the proxy user in its local zone if the zone is not given
+	authResponse := message.NewIRODSMessageAuthResponse(encodedPassword, conn.account.GetProxyIdentity().String())
 	authResult := message.IRODSMessageAuthResult{}
 	err = conn.RequestAndCheck(authResponse, &authResult, nil)
 	if err != nil {
-		return xerrors.Errorf("received irods authentication error (%s): %w", err.Error(), types.NewAuthError(conn.account))
+		return conn.getLoginError(err)
 	}
 	return nil
 }
 
+// getLoginError returns AuthError if the server rejected authentication, ConnectionError otherwise
+func (conn *IRODSConnection) getLoginError(err error) error {
+	if types.IsIRODSError(err) {
+		return xerrors.Errorf("received irods authentication error (%s): %w", err.Error(), types.NewAuthError(conn.account))
+	}
+	return xerrors.Errorf("failed to authenticate (%s): %w", err.Error(), types.NewConnectionError())
+}
+
 func (conn *IRODSConnection) loginNative() error {
 	logger := log.WithFields(log.Fields{
 		"package":  "connection",
@@ -538,7 +667,7 @@ func (conn *IRODSConnection) loginPAMWithPassword() error {
 
 	// Check whether ssl has already started, if not, start ssl.
 	if _, ok := conn.socket.(*tls.Conn); !ok {
-		return xerrors.Errorf("connection should be using SSL: %w", types.NewConnectionError())
+		return xerrors.Errorf("connection should be using SSL: %w", types.NewSSLNegotiationError(conn.account.Host, conn.account.Port, "PAM authentication requires SSL"))
 	}
 
 	ttl := conn.account.PamTTL
@@ -547,11 +676,12 @@ func (conn *IRODSConnection) loginPAMWithPassword() error {
 	}
 
 	// authenticate
-	pamAuthRequest := message.NewIRODSMessagePamAuthRequest(conn.account.ClientUser, conn.account.Password, ttl)
+	// the proxy user authenticates, as with native authentication
+	pamAuthRequest := message.NewIRODSMessagePamAuthRequest(conn.account.ProxyUser, conn.account.Password, ttl)
 	pamAuthResponse := message.IRODSMessagePamAuthResponse{}
-	err := conn.Request(pamAuthRequest, &pamAuthResponse, nil)
+	err := conn.RequestAndCheck(pamAuthRequest, &pamAuthResponse, nil)
 	if err != nil {
-		return xerrors.Errorf("failed to receive an authentication challenge message (%s): %w", err.Error(), types.NewAuthError(conn.account))
+		return conn.getLoginError(err)
 	}
 
 	// save irods generated password for possible future use
@@ -572,7 +702,7 @@ func (conn *IRODSConnection) loginPAMWithToken() error {
 
 	// Check whether ssl has already started, if not, start ssl.
 	if _, ok := conn.socket.(*tls.Conn); !ok {
-		return xerrors.Errorf("connection should be using SSL: %w", types.NewConnectionError())
+		return xerrors.Errorf("connection should be using SSL: %w", types.NewSSLNegotiationError(conn.account.Host, conn.account.Port, "PAM authentication requires SSL"))
 	}
 
 	ttl := conn.account.PamTTL
@@ -590,7 +720,7 @@ func (conn *IRODSConnection) disconnectNow() error {
 	var err error
 	if conn.socket != nil {
 		err = conn.socket.Close()
-		conn.socket = nil
+		conn.setSocket(nil)
 	}
 
 	if conn.metrics != nil {
@@ -635,6 +765,81 @@ func (conn *IRODSConnection) Disconnect() error {
 	return nil
 }
 
+// Heartbeat sends a heartbeat message and waits for the server to echo it back
+// this keeps a long idle connection from being dropped by the server or middleboxes, without touching the catalog
+func (conn *IRODSConnection) Heartbeat() error {
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	return conn.heartbeat()
+}
+
+// TryHeartbeat sends a heartbeat message if the connection is not in use, see Heartbeat
+// returns false if the connection is in use and the heartbeat is skipped
+func (conn *IRODSConnection) TryHeartbeat() (bool, error) {
+	if !conn.TryLock() {
+		return false, nil
+	}
+	defer conn.Unlock()
+
+	return true, conn.heartbeat()
+}
+
+func (conn *IRODSConnection) heartbeat() error {
+	if !conn.connected {
+		return xerrors.Errorf("connection is disconnected")
+	}
+
+	heartbeat := message.NewIRODSMessageHeartbeat()
+	heartbeatMessage, err := heartbeat.GetMessage()
+	if err != nil {
+		return xerrors.Errorf("failed to make a heartbeat message: %w", err)
+	}
+
+	err = conn.SendMessage(heartbeatMessage)
+	if err != nil {
+		return xerrors.Errorf("failed to send a heartbeat message: %w", err)
+	}
+
+	responseMessage, err := conn.ReadMessage(nil)
+	if err != nil {
+		return xerrors.Errorf("failed to receive a heartbeat message: %w", err)
+	}
+
+	err = heartbeat.FromMessage(responseMessage)
+	if err != nil {
+		return xerrors.Errorf("failed to parse a heartbeat message: %w", err)
+	}
+
+	return nil
+}
+
+// setSocket replaces the socket
+func (conn *IRODSConnection) setSocket(socket net.Conn) {
+	conn.socketMutex.Lock()
+	defer conn.socketMutex.Unlock()
+
+	conn.socket = socket
+}
+
+// Interrupt closes the socket without locking the connection, to abort a hung request from another goroutine
+// the request in progress fails and the connection is not usable afterwards
+func (conn *IRODSConnection) Interrupt() error {
+	conn.socketMutex.Lock()
+	defer conn.socketMutex.Unlock()
+
+	if conn.socket == nil {
+		return nil
+	}
+
+	err := conn.socket.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close socket: %w", err)
+	}
+	return nil
+}
+
 func (conn *IRODSConnection) socketFail() {
 	if conn.metrics != nil {
 		conn.metrics.IncreaseCounterForConnectionFailures(1)
@@ -1040,7 +1245,7 @@ func (conn *IRODSConnection) poorMansEndTransaction(dummyCol string, commit bool
 // RawBind binds an IRODSConnection to a raw net.Conn socket - to be used for e.g. a proxy server setup
 func (conn *IRODSConnection) RawBind(socket net.Conn) {
 	conn.connected = true
-	conn.socket = socket
+	conn.setSocket(socket)
 }
 
 // GetMetrics returns metrics
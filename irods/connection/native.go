@@ -0,0 +1,47 @@
+package connection
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/message"
+)
+
+// PackNativeMessage packs body of a request message in native protocol.
+// Message structs are packed directly, see message.MarshalNative.
+// Requests that are not message structs, e.g., ones building XML bodies by hand, have the XML body translated.
+func (conn *IRODSConnection) PackNativeMessage(request Request, msg *message.IRODSMessage) error {
+	if msg.Body == nil || len(msg.Body.Message) == 0 {
+		return nil
+	}
+
+	var nativeBytes []byte
+	var err error
+	if message.CanMarshalNative(request) {
+		nativeBytes, err = message.MarshalNative(request)
+	} else {
+		nativeBytes, err = message.ConvertXMLToNative(msg.Body.Message)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg.Body.Message = nativeBytes
+	msg.Header.MessageLen = uint32(len(msg.Body.Message))
+	return nil
+}
+
+// UnpackNativeMessage marks body of a response message as packed in native protocol.
+// Responses unpack it into their structs directly, see message.UnmarshalIRODSMessage.
+func (conn *IRODSConnection) UnpackNativeMessage(msg *message.IRODSMessage) error {
+	if msg.Body == nil || msg.Body.Message == nil {
+		return nil
+	}
+
+	if len(msg.Body.Message) == 0 {
+		// same as XML, empty body has no message
+		msg.Body.Message = nil
+		return nil
+	}
+
+	msg.Body.NativePacked = true
+	return nil
+}
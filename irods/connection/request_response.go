@@ -1,6 +1,8 @@
 package connection
 
 import (
+	"sort"
+
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/message"
 	"golang.org/x/xerrors"
@@ -16,6 +18,12 @@ type Response interface {
 	FromMessage(*message.IRODSMessage) error
 }
 
+// KeyValRequest is a Request having a KeyValPair, to which keywords of the connection are added.
+type KeyValRequest interface {
+	Request
+	AddKeyVal(key common.KeyWord, val string)
+}
+
 // CheckErrorResponse is a Response on which CheckError can be called.
 type CheckErrorResponse interface {
 	Response
@@ -81,6 +89,56 @@ func (conn *IRODSConnection) RequestWithTrackerCallBack(request Request, respons
 	return nil
 }
 
+// RequestRaw calls an API with a message body that is already packed, and returns the response as is.
+// This is for APIs that are not wrapped by the message package yet.
+// packedInput must be packed in the protocol of the connection, XML (irods-dialect) or native (see IsNativeProtocol).
+// requestBs is the byte stream sent with the request, e.g., data to write, nil if the API takes none.
+// responseBsBuffer is an optional buffer to read the byte stream of the response into, returned in Bs of the response.
+// The server error in the response is not checked, use CheckError of the response.
+func (conn *IRODSConnection) RequestRaw(apiNumber common.APINumber, packedInput []byte, requestBs []byte, responseBsBuffer []byte) (*message.IRODSMessageRawResponse, error) {
+	// set transaction dirty
+	conn.SetTransactionDirty(true)
+
+	request := message.NewIRODSMessageRawRequest(apiNumber, packedInput, requestBs)
+	response := message.IRODSMessageRawResponse{}
+
+	requestMessage, err := conn.getRequestMessage(request, false, false)
+	if err != nil {
+		if conn.metrics != nil {
+			conn.metrics.IncreaseCounterForRequestResponseFailures(1)
+		}
+		return nil, err
+	}
+
+	err = conn.SendMessage(requestMessage)
+	if err != nil {
+		if conn.metrics != nil {
+			conn.metrics.IncreaseCounterForRequestResponseFailures(1)
+		}
+		return nil, xerrors.Errorf("failed to send a request message: %w", err)
+	}
+
+	// Server responds with results
+	// external bs buffer
+	responseMessage, err := conn.ReadMessage(responseBsBuffer)
+	if err != nil {
+		if conn.metrics != nil {
+			conn.metrics.IncreaseCounterForRequestResponseFailures(1)
+		}
+		return nil, xerrors.Errorf("failed to receive a response message: %w", err)
+	}
+
+	err = conn.getResponse(responseMessage, &response, false)
+	if err != nil {
+		if conn.metrics != nil {
+			conn.metrics.IncreaseCounterForRequestResponseFailures(1)
+		}
+		return nil, xerrors.Errorf("failed to parse response message: %w", err)
+	}
+
+	return &response, nil
+}
+
 // RequestAsyncWithTrackerCallBack sends multiple requests and expects responses.
 func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan RequestResponsePair) chan RequestResponsePair {
 	waitResponseChan := make(chan RequestResponsePair, 100)
@@ -291,13 +349,36 @@ func (conn *IRODSConnection) RequestAndCheckForPassword(request Request, respons
 	return response.CheckError()
 }
 
+// getRequestMessage makes a message for the request, waits for the rate limiter if set as every request is made through this
 func (conn *IRODSConnection) getRequestMessage(request Request, xml bool, forPassword bool) (*message.IRODSMessage, error) {
+	if conn.rateLimiter != nil {
+		conn.rateLimiter.Wait()
+	}
+
+	if keyValRequest, ok := request.(KeyValRequest); ok && xml && len(conn.keywords) > 0 {
+		keys := make([]string, 0, len(conn.keywords))
+		for key := range conn.keywords {
+			keys = append(keys, string(key))
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			keyValRequest.AddKeyVal(common.KeyWord(key), conn.keywords[common.KeyWord(key)])
+		}
+	}
+
 	requestMessage, err := request.GetMessage()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to make a request message: %w", err)
 	}
 
-	if xml {
+	if xml && conn.nativeProtocol {
+		// pack the request in native protocol instead of XML
+		err = conn.PackNativeMessage(request, requestMessage)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to pack message in native protocol: %w", err)
+		}
+	} else if xml {
 		// translate xml.Marshal XML into irods-understandable XML (among others, replace &#34; by &quot;)
 		err = conn.PreprocessMessage(requestMessage, forPassword)
 		if err != nil {
@@ -309,7 +390,13 @@ func (conn *IRODSConnection) getRequestMessage(request Request, xml bool, forPas
 }
 
 func (conn *IRODSConnection) getResponse(responseMessage *message.IRODSMessage, response Response, xml bool) error {
-	if xml {
+	if xml && conn.nativeProtocol {
+		// the response unpacks native packing into its struct
+		err := conn.UnpackNativeMessage(responseMessage)
+		if err != nil {
+			return xerrors.Errorf("failed to unpack message in native protocol: %w", err)
+		}
+	} else if xml {
 		// translate irods-dialect XML into valid XML
 		err := conn.PostprocessMessage(responseMessage)
 		if err != nil {
@@ -168,7 +168,7 @@ func (conn *IRODSResourceServerConnection) Connect() error {
 
 	socket, err := dialer.DialContext(ctx, "tcp", server)
 	if err != nil {
-		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.serverInfo.Host, conn.serverInfo.Port, err.Error(), types.NewConnectionError())
+		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.serverInfo.Host, conn.serverInfo.Port, err.Error(), types.NewConnectionRefusedError(conn.serverInfo.Host, conn.serverInfo.Port))
 		logger.Errorf("%+v", connErr)
 
 		if conn.metrics != nil {
@@ -0,0 +1,72 @@
+package connection
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TLSSessionCacheSizeDefault is a default number of TLS sessions kept for resumption
+	TLSSessionCacheSizeDefault int = 64
+)
+
+// TLSConfigCache shares a TLS config between connections to the same server
+// the config is built once, so certificates are not loaded for every connection,
+// and connections resume TLS sessions of earlier connections instead of doing full handshakes
+type TLSConfigCache struct {
+	sessionCache tls.ClientSessionCache // nil if sessions are not resumed
+	configs      map[string]*tls.Config // key is server name and verification
+	mutex        sync.Mutex
+}
+
+// NewTLSConfigCache creates a new TLSConfigCache keeping sessionCacheSize TLS sessions for resumption
+// TLSSessionCacheSizeDefault is used if sessionCacheSize is 0, sessions are not resumed if it is negative
+func NewTLSConfigCache(sessionCacheSize int) *TLSConfigCache {
+	var sessionCache tls.ClientSessionCache
+	if sessionCacheSize == 0 {
+		sessionCacheSize = TLSSessionCacheSizeDefault
+	}
+
+	if sessionCacheSize > 0 {
+		sessionCache = tls.NewLRUClientSessionCache(sessionCacheSize)
+	}
+
+	return &TLSConfigCache{
+		sessionCache: sessionCache,
+		configs:      map[string]*tls.Config{},
+		mutex:        sync.Mutex{},
+	}
+}
+
+// GetTLSConfig returns a TLS config for the SSL config, built at the first call for the server name and verification
+// connections sharing the cache must use the same SSL config
+func (cache *TLSConfigCache) GetTLSConfig(sslConfig *types.IRODSSSLConfig, serverName string, skipVerify bool) (*tls.Config, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	key := fmt.Sprintf("%s/%t", serverName, skipVerify)
+	if config, ok := cache.configs[key]; ok {
+		return config, nil
+	}
+
+	config, err := sslConfig.GetTLSConfig(serverName, skipVerify)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create TLS config: %w", err)
+	}
+
+	if config.ClientSessionCache == nil {
+		config.ClientSessionCache = cache.sessionCache
+	}
+
+	cache.configs[key] = config
+	return config, nil
+}
+
+// SupportSessionResumption returns true if connections resume TLS sessions
+func (cache *TLSConfigCache) SupportSessionResumption() bool {
+	return cache.sessionCache != nil
+}
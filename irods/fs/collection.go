@@ -2,8 +2,8 @@ package fs
 
 import (
 	"encoding/binary"
-	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
@@ -55,14 +55,16 @@ func GetCollection(conn *connection.IRODSConnection, path string) (*types.IRODSC
 	defer conn.Unlock()
 
 	query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, 0, 0, 0)
-	query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+	query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 	query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
 	query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
 	query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_NAME, 1)
+	query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_ZONE, 1)
+	query.AddSelect(common.ICAT_COLUMN_COLL_INHERITANCE, 1)
 	query.AddSelect(common.ICAT_COLUMN_COLL_CREATE_TIME, 1)
 	query.AddSelect(common.ICAT_COLUMN_COLL_MODIFY_TIME, 1)
 
-	condVal := fmt.Sprintf("= '%s'", path)
+	condVal := util.MakeGenQueryEqualCondition(path)
 	query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
 
 	queryResult := message.IRODSMessageQueryResponse{}
@@ -94,6 +96,8 @@ func GetCollection(conn *connection.IRODSConnection, path string) (*types.IRODSC
 	var collectionID int64 = -1
 	collectionPath := ""
 	collectionOwner := ""
+	collectionOwnerZone := ""
+	inheritance := false
 	createTime := time.Time{}
 	modifyTime := time.Time{}
 	for idx := 0; idx < queryResult.AttributeCount; idx++ {
@@ -115,6 +119,14 @@ func GetCollection(conn *connection.IRODSConnection, path string) (*types.IRODSC
 			collectionPath = value
 		case int(common.ICAT_COLUMN_COLL_OWNER_NAME):
 			collectionOwner = value
+		case int(common.ICAT_COLUMN_COLL_OWNER_ZONE):
+			collectionOwnerZone = value
+		case int(common.ICAT_COLUMN_COLL_INHERITANCE):
+			inherit, err := parseInheritance(value)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse inheritance '%s': %w", value, err)
+			}
+			inheritance = inherit
 		case int(common.ICAT_COLUMN_COLL_CREATE_TIME):
 			cT, err := util.GetIRODSDateTime(value)
 			if err != nil {
@@ -137,12 +149,14 @@ func GetCollection(conn *connection.IRODSConnection, path string) (*types.IRODSC
 	}
 
 	return &types.IRODSCollection{
-		ID:         collectionID,
-		Path:       collectionPath,
-		Name:       util.GetIRODSPathFileName(collectionPath),
-		Owner:      collectionOwner,
-		CreateTime: createTime,
-		ModifyTime: modifyTime,
+		ID:          collectionID,
+		Path:        collectionPath,
+		Name:        util.GetIRODSPathFileName(collectionPath),
+		Owner:       collectionOwner,
+		OwnerZone:   collectionOwnerZone,
+		Inheritance: inheritance,
+		CreateTime:  createTime,
+		ModifyTime:  modifyTime,
 	}, nil
 }
 
@@ -167,7 +181,7 @@ func ListCollectionMeta(conn *connection.IRODSConnection, path string) ([]*types
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 		query.AddSelect(common.ICAT_COLUMN_META_COLL_ATTR_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_COLL_ATTR_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_COLL_ATTR_VALUE, 1)
@@ -175,7 +189,7 @@ func ListCollectionMeta(conn *connection.IRODSConnection, path string) ([]*types
 		query.AddSelect(common.ICAT_COLUMN_META_COLL_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_COLL_MODIFY_TIME, 1)
 
-		condVal := fmt.Sprintf("= '%s'", path)
+		condVal := util.MakeGenQueryEqualCondition(path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -291,10 +305,10 @@ func GetCollectionAccessInheritance(conn *connection.IRODSConnection, path strin
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 		query.AddSelect(common.ICAT_COLUMN_COLL_INHERITANCE, 1)
 
-		condVal := fmt.Sprintf("= '%s'", path)
+		condVal := util.MakeGenQueryEqualCondition(path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -388,7 +402,7 @@ func ListCollectionAccesses(conn *connection.IRODSConnection, path string) ([]*t
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQuerySpecificRequest("ShowCollAcls", []string{path}, common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -397,7 +411,7 @@ func ListCollectionAccesses(conn *connection.IRODSConnection, path string) ([]*t
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("failed to receive a collection access query result message: %w", err)
+			return nil, xerrors.Errorf("failed to receive a collection access query result message: %w", getAccessQueryError(path, err))
 		}
 
 		err = queryResult.CheckError()
@@ -406,7 +420,7 @@ func ListCollectionAccesses(conn *connection.IRODSConnection, path string) ([]*t
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("received collection access query error: %w", err)
+			return nil, xerrors.Errorf("received collection access query error: %w", getAccessQueryError(path, err))
 		}
 
 		if queryResult.RowCount == 0 {
@@ -492,13 +506,13 @@ func ListCollectionAccesses(conn *connection.IRODSConnection, path string) ([]*t
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 		query.AddSelect(common.ICAT_COLUMN_COLL_ACCESS_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 
-		condVal := fmt.Sprintf("= '%s'", path)
+		condVal := util.MakeGenQueryEqualCondition(path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
 
 		logger.Infof("sending a request for checking ACLs of path %s", path)
@@ -602,20 +616,24 @@ func ListAccessesForSubCollections(conn *connection.IRODSConnection, path string
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_ACCESS_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 
-		condVal := fmt.Sprintf("= '%s'", path)
+		condVal := util.MakeGenQueryEqualCondition(path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_PARENT_NAME, condVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a collection access query result message: %w", err)
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("failed to receive a collection access query result message: %w", getAccessQueryError(path, err))
 		}
 
 		err = queryResult.CheckError()
@@ -624,7 +642,7 @@ func ListAccessesForSubCollections(conn *connection.IRODSConnection, path string
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("received collection access query error: %w", err)
+			return nil, xerrors.Errorf("received collection access query error: %w", getAccessQueryError(path, err))
 		}
 
 		if queryResult.RowCount == 0 {
@@ -687,6 +705,45 @@ func ListAccessesForSubCollections(conn *connection.IRODSConnection, path string
 
 // ListSubCollections lists subcollections in the given collection
 func ListSubCollections(conn *connection.IRODSConnection, path string) ([]*types.IRODSCollection, error) {
+	return listCollections(conn, path, common.ICAT_COLUMN_COLL_PARENT_NAME, util.MakeGenQueryEqualCondition(path))
+}
+
+// ListSubCollectionsRecursive lists all collections under the given collection at any depth with a query
+func ListSubCollectionsRecursive(conn *connection.IRODSConnection, path string) ([]*types.IRODSCollection, error) {
+	collections, err := listCollections(conn, path, common.ICAT_COLUMN_COLL_NAME, getSubTreeCondition(path))
+	if err != nil {
+		return nil, err
+	}
+
+	// "_" and "%" in the path are wildcards in the condition
+	subCollections := []*types.IRODSCollection{}
+	for _, collection := range collections {
+		if isInSubTree(path, collection.Path) {
+			subCollections = append(subCollections, collection)
+		}
+	}
+
+	return subCollections, nil
+}
+
+// getSubTreeCondition returns a condition for paths under the given collection at any depth
+func getSubTreeCondition(path string) string {
+	if path == "/" {
+		return "like '/%'"
+	}
+	return util.MakeGenQueryPrefixCondition(path + "/")
+}
+
+// isInSubTree returns true if p is under the given collection at any depth
+func isInSubTree(path string, p string) bool {
+	if path == "/" {
+		return p != "/" && strings.HasPrefix(p, "/")
+	}
+	return strings.HasPrefix(p, path+"/")
+}
+
+// listCollections lists collections matching the condition on the column, path is used to direct the query to its zone
+func listCollections(conn *connection.IRODSConnection, path string, condColumn common.ICATColumnNumber, condVal string) ([]*types.IRODSCollection, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -706,15 +763,16 @@ func ListSubCollections(conn *connection.IRODSConnection, path string) ([]*types
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, path))
 		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_ZONE, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_INHERITANCE, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_MODIFY_TIME, 1)
 
-		condVal := fmt.Sprintf("= '%s'", path)
-		query.AddCondition(common.ICAT_COLUMN_COLL_PARENT_NAME, condVal)
+		query.AddCondition(condColumn, condVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -774,6 +832,14 @@ func ListSubCollections(conn *connection.IRODSConnection, path string) ([]*types
 					pagenatedCollections[row].Name = util.GetIRODSPathFileName(value)
 				case int(common.ICAT_COLUMN_COLL_OWNER_NAME):
 					pagenatedCollections[row].Owner = value
+				case int(common.ICAT_COLUMN_COLL_OWNER_ZONE):
+					pagenatedCollections[row].OwnerZone = value
+				case int(common.ICAT_COLUMN_COLL_INHERITANCE):
+					inherit, err := parseInheritance(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse inheritance '%s': %w", value, err)
+					}
+					pagenatedCollections[row].Inheritance = inherit
 				case int(common.ICAT_COLUMN_COLL_CREATE_TIME):
 					cT, err := util.GetIRODSDateTime(value)
 					if err != nil {
@@ -910,6 +976,11 @@ func AddCollectionMeta(conn *connection.IRODSConnection, path string, metadata *
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	err := validateMeta(metadata)
+	if err != nil {
+		return err
+	}
+
 	metrics := conn.GetMetrics()
 	if metrics != nil {
 		metrics.IncreaseCounterForMetadataCreate(1)
@@ -921,8 +992,11 @@ func AddCollectionMeta(conn *connection.IRODSConnection, path string, metadata *
 
 	request := message.NewIRODSMessageAddMetadataRequest(types.IRODSCollectionMetaItemType, path, metadata)
 	response := message.IRODSMessageModifyMetadataResponse{}
-	err := conn.RequestAndCheck(request, &response, nil)
+	err = conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
+		if metaErr := getMetaTooLargeError(err, metadata); metaErr != nil {
+			return xerrors.Errorf("received add collection meta error: %w", metaErr)
+		}
 		return xerrors.Errorf("received add collection meta error: %w", err)
 	}
 	return nil
@@ -967,6 +1041,34 @@ func DeleteCollectionMeta(conn *connection.IRODSConnection, path string, metadat
 
 // SearchCollectionsByMeta searches collections by metadata
 func SearchCollectionsByMeta(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSCollection, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	return searchCollectionsByMeta(conn, metaName, metaValueCondVal, "")
+}
+
+// SearchCollectionsByMetaWildcard searches collections by metadata
+// Caution: This is a very slow operation
+func SearchCollectionsByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSCollection, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	return searchCollectionsByMeta(conn, metaName, metaValueCondVal, "")
+}
+
+// SearchCollectionsByMetaWithUnits searches collections by metadata name, value and units
+func SearchCollectionsByMetaWithUnits(conn *connection.IRODSConnection, metaName string, metaValue string, metaUnits string) ([]*types.IRODSCollection, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	metaUnitsCondVal := util.MakeGenQueryEqualCondition(metaUnits)
+	return searchCollectionsByMeta(conn, metaName, metaValueCondVal, metaUnitsCondVal)
+}
+
+// SearchCollectionsByMetaWithUnitsWildcard searches collections by metadata name, value and units
+// metaValue and metaUnits are in SQL LIKE syntax, e.g., "ns:%"
+// Caution: This is a very slow operation
+func SearchCollectionsByMetaWithUnitsWildcard(conn *connection.IRODSConnection, metaName string, metaValue string, metaUnits string) ([]*types.IRODSCollection, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	metaUnitsCondVal := util.MakeGenQueryLikeCondition(metaUnits)
+	return searchCollectionsByMeta(conn, metaName, metaValueCondVal, metaUnitsCondVal)
+}
+
+func searchCollectionsByMeta(conn *connection.IRODSConnection, metaName string, metaValueCondVal string, metaUnitsCondVal string) ([]*types.IRODSCollection, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -990,13 +1092,17 @@ func SearchCollectionsByMeta(conn *connection.IRODSConnection, metaName string,
 		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_ZONE, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_INHERITANCE, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_MODIFY_TIME, 1)
 
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
+		metaNameCondVal := util.MakeGenQueryEqualCondition(metaName)
 		query.AddCondition(common.ICAT_COLUMN_META_COLL_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("= '%s'", metaValue)
 		query.AddCondition(common.ICAT_COLUMN_META_COLL_ATTR_VALUE, metaValueCondVal)
+		if len(metaUnitsCondVal) > 0 {
+			query.AddCondition(common.ICAT_COLUMN_META_COLL_ATTR_UNITS, metaUnitsCondVal)
+		}
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -1056,6 +1162,14 @@ func SearchCollectionsByMeta(conn *connection.IRODSConnection, metaName string,
 					pagenatedCollections[row].Name = util.GetIRODSPathFileName(value)
 				case int(common.ICAT_COLUMN_COLL_OWNER_NAME):
 					pagenatedCollections[row].Owner = value
+				case int(common.ICAT_COLUMN_COLL_OWNER_ZONE):
+					pagenatedCollections[row].OwnerZone = value
+				case int(common.ICAT_COLUMN_COLL_INHERITANCE):
+					inherit, err := parseInheritance(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse inheritance '%s': %w", value, err)
+					}
+					pagenatedCollections[row].Inheritance = inherit
 				case int(common.ICAT_COLUMN_COLL_CREATE_TIME):
 					cT, err := util.GetIRODSDateTime(value)
 					if err != nil {
@@ -1085,134 +1199,6 @@ func SearchCollectionsByMeta(conn *connection.IRODSConnection, metaName string,
 	return collections, nil
 }
 
-// SearchCollectionsByMetaWildcard searches collections by metadata
-// Caution: This is a very slow operation
-func SearchCollectionsByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSCollection, error) {
-	if conn == nil || !conn.IsConnected() {
-		return nil, xerrors.Errorf("connection is nil or disconnected")
-	}
-
-	metrics := conn.GetMetrics()
-	if metrics != nil {
-		metrics.IncreaseCounterForSearch(1)
-	}
-
-	// lock the connection
-	conn.Lock()
-	defer conn.Unlock()
-
-	collections := []*types.IRODSCollection{}
-
-	continueQuery := true
-	continueIndex := 0
-	for continueQuery {
-		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
-		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
-		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_COLL_CREATE_TIME, 1)
-		query.AddSelect(common.ICAT_COLUMN_COLL_MODIFY_TIME, 1)
-
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
-		query.AddCondition(common.ICAT_COLUMN_META_COLL_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("like '%s'", metaValue)
-		query.AddCondition(common.ICAT_COLUMN_META_COLL_ATTR_VALUE, metaValueCondVal)
-
-		queryResult := message.IRODSMessageQueryResponse{}
-		err := conn.Request(query, &queryResult, nil)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a collection query result message: %w", err)
-		}
-
-		err = queryResult.CheckError()
-		if err != nil {
-			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
-				// empty
-				break
-			}
-			return nil, xerrors.Errorf("received collection query error: %w", err)
-		}
-
-		if queryResult.RowCount == 0 {
-			break
-		}
-
-		if queryResult.AttributeCount > len(queryResult.SQLResult) {
-			return nil, xerrors.Errorf("failed to receive collection attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
-		}
-
-		pagenatedCollections := make([]*types.IRODSCollection, queryResult.RowCount)
-
-		for attr := 0; attr < queryResult.AttributeCount; attr++ {
-			sqlResult := queryResult.SQLResult[attr]
-			if len(sqlResult.Values) != queryResult.RowCount {
-				return nil, xerrors.Errorf("failed to receive data object rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
-			}
-
-			for attr := 0; attr < queryResult.AttributeCount; attr++ {
-				sqlResult := queryResult.SQLResult[attr]
-				if len(sqlResult.Values) != queryResult.RowCount {
-					return nil, xerrors.Errorf("failed to receive collection rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
-				}
-
-				for row := 0; row < queryResult.RowCount; row++ {
-					value := sqlResult.Values[row]
-
-					if pagenatedCollections[row] == nil {
-						// create a new
-						pagenatedCollections[row] = &types.IRODSCollection{
-							ID:         -1,
-							Path:       "",
-							Name:       "",
-							Owner:      "",
-							CreateTime: time.Time{},
-							ModifyTime: time.Time{},
-						}
-					}
-
-					switch sqlResult.AttributeIndex {
-					case int(common.ICAT_COLUMN_COLL_ID):
-						cID, err := strconv.ParseInt(value, 10, 64)
-						if err != nil {
-							return nil, xerrors.Errorf("failed to parse collection id '%s': %w", value, err)
-						}
-						pagenatedCollections[row].ID = cID
-					case int(common.ICAT_COLUMN_COLL_NAME):
-						pagenatedCollections[row].Path = value
-						pagenatedCollections[row].Name = util.GetIRODSPathFileName(value)
-					case int(common.ICAT_COLUMN_COLL_OWNER_NAME):
-						pagenatedCollections[row].Owner = value
-					case int(common.ICAT_COLUMN_COLL_CREATE_TIME):
-						cT, err := util.GetIRODSDateTime(value)
-						if err != nil {
-							return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
-						}
-						pagenatedCollections[row].CreateTime = cT
-					case int(common.ICAT_COLUMN_COLL_MODIFY_TIME):
-						mT, err := util.GetIRODSDateTime(value)
-						if err != nil {
-							return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
-						}
-						pagenatedCollections[row].ModifyTime = mT
-					default:
-						// ignore
-					}
-				}
-			}
-		}
-
-		collections = append(collections, pagenatedCollections...)
-
-		continueIndex = queryResult.ContinueIndex
-		if continueIndex == 0 {
-			continueQuery = false
-		}
-	}
-
-	return collections, nil
-}
-
 // ChangeCollectionAccess changes access on a collection.
 func ChangeCollectionAccess(conn *connection.IRODSConnection, path string, access types.IRODSAccessLevelType, userName, zoneName string, recursive bool, adminFlag bool) error {
 	if conn == nil || !conn.IsConnected() {
@@ -1272,3 +1258,11 @@ func SetAccessInherit(conn *connection.IRODSConnection, path string, inherit, re
 	}
 	return nil
 }
+
+// parseInheritance parses ACL inheritance flag of a collection, empty means no inheritance
+func parseInheritance(value string) (bool, error) {
+	if len(value) == 0 {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
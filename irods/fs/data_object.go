@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -75,7 +76,7 @@ func GetDataObject(conn *connection.IRODSConnection, collection *types.IRODSColl
 	for continueQuery {
 		// data object
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
@@ -84,6 +85,7 @@ func GetDataObject(conn *connection.IRODSConnection, collection *types.IRODSColl
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -92,10 +94,10 @@ func GetDataObject(conn *connection.IRODSConnection, collection *types.IRODSColl
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
 
-		pathCondVal := fmt.Sprintf("= '%s'", filename)
+		pathCondVal := util.MakeGenQueryEqualCondition(filename)
 		query.AddCondition(common.ICAT_COLUMN_DATA_NAME, pathCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -181,6 +183,8 @@ func GetDataObject(conn *connection.IRODSConnection, collection *types.IRODSColl
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -225,28 +229,83 @@ func GetDataObject(conn *connection.IRODSConnection, collection *types.IRODSColl
 		return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
+	mergedDataObjects := mergeReplicas(dataObjects)
+	if len(mergedDataObjects) == 0 {
+		return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
+	}
+
+	return mergedDataObjects[0], nil
+}
+
+// isBetterMasterReplica returns true if the replica is a better master replica than the current master
+// a good replica is preferred, then an older replica, then a replica with a smaller number
+func isBetterMasterReplica(replica *types.IRODSReplica, master *types.IRODSReplica) bool {
+	if replica.IsGood() != master.IsGood() {
+		return replica.IsGood()
+	}
+
+	if !replica.CreateTime.Equal(master.CreateTime) {
+		return replica.CreateTime.Before(master.CreateTime)
+	}
+
+	return replica.Number < master.Number
+}
+
+// mergeReplicas merges data objects of a replica each into data objects having all replicas, the master replica first
+// the size of the data object is the size of the master replica, replicas are counted in ReplicaCount and StaleReplicaCount
+func mergeReplicas(dataObjects []*types.IRODSDataObject) []*types.IRODSDataObject {
+	mergedDataObjects := []*types.IRODSDataObject{}
+	mergedDataObjectsMap := map[int64]int{}
+
 	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// merge
-			existingObj.Replicas = append(existingObj.Replicas, object.Replicas[0])
+		if len(object.Replicas) == 0 {
+			continue
+		}
+
+		staleReplicaCount := 0
+		if object.Replicas[0].IsStale() {
+			staleReplicaCount = 1
+		}
+
+		idx, exists := mergedDataObjectsMap[object.ID]
+		if !exists {
+			object.ReplicaCount = 1
+			object.StaleReplicaCount = staleReplicaCount
+
+			mergedDataObjectsMap[object.ID] = len(mergedDataObjects)
+			mergedDataObjects = append(mergedDataObjects, object)
+			continue
+		}
+
+		existingObj := mergedDataObjects[idx]
+		existingObj.ReplicaCount++
+		existingObj.StaleReplicaCount += staleReplicaCount
+
+		if isBetterMasterReplica(object.Replicas[0], existingObj.Replicas[0]) {
+			existingObj.Replicas = append([]*types.IRODSReplica{object.Replicas[0]}, existingObj.Replicas...)
+			existingObj.Size = object.Size
 		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
+			existingObj.Replicas = append(existingObj.Replicas, object.Replicas[0])
 		}
 	}
 
-	for _, object := range mergedDataObjectsMap {
-		// returns only the first object
-		return object, nil
+	return mergedDataObjects
+}
+
+// mergeMasterReplicas merges replicas of data objects into data objects having only master replicas
+// the size and checksum of a stale replica are used only if the data object has no good replica
+// ReplicaCount and StaleReplicaCount still count all replicas
+func mergeMasterReplicas(dataObjects []*types.IRODSDataObject) []*types.IRODSDataObject {
+	mergedDataObjects := mergeReplicas(dataObjects)
+	for _, object := range mergedDataObjects {
+		object.Replicas = object.Replicas[:1]
 	}
 
-	return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
+	return mergedDataObjects
 }
 
 // GetDataObjectMasterReplica returns a data object for the path, returns only master replica
+// the master replica is a good replica if the data object has any
 func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *types.IRODSCollection, filename string) (*types.IRODSDataObject, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
@@ -269,7 +328,7 @@ func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *ty
 	for continueQuery {
 		// data object
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
@@ -278,6 +337,7 @@ func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *ty
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -286,11 +346,10 @@ func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *ty
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
-		pathCondVal := fmt.Sprintf("= '%s'", filename)
+		pathCondVal := util.MakeGenQueryEqualCondition(filename)
 		query.AddCondition(common.ICAT_COLUMN_DATA_NAME, pathCondVal)
-		query.AddCondition(common.ICAT_COLUMN_D_REPL_STATUS, "= '1'")
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -375,6 +434,8 @@ func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *ty
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -419,33 +480,12 @@ func GetDataObjectMasterReplica(conn *connection.IRODSConnection, collection *ty
 		return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// compare and replace
-			if len(existingObj.Replicas) == 0 {
-				// replace
-				mergedDataObjectsMap[object.ID] = object
-			} else if len(object.Replicas) > 0 {
-				if existingObj.Replicas[0].CreateTime.After(object.Replicas[0].CreateTime) {
-					// found old replica (meaning master) - replace
-					mergedDataObjectsMap[object.ID] = object
-				}
-			}
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
-	}
-
-	for _, object := range mergedDataObjectsMap {
-		// returns only the first object
-		return object, nil
+	mergedDataObjects := mergeMasterReplicas(dataObjects)
+	if len(mergedDataObjects) == 0 {
+		return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
 	}
 
-	return nil, xerrors.Errorf("failed to find the data object for path %s: %w", filepath, types.NewFileNotFoundError(filepath))
+	return mergedDataObjects[0], nil
 }
 
 // ListDataObjects lists data objects in the given collection
@@ -470,7 +510,7 @@ func ListDataObjects(conn *connection.IRODSConnection, collection *types.IRODSCo
 	for continueQuery {
 		// data object
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
@@ -479,6 +519,7 @@ func ListDataObjects(conn *connection.IRODSConnection, collection *types.IRODSCo
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -487,7 +528,7 @@ func ListDataObjects(conn *connection.IRODSConnection, collection *types.IRODSCo
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -575,6 +616,8 @@ func ListDataObjects(conn *connection.IRODSConnection, collection *types.IRODSCo
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -615,26 +658,7 @@ func ListDataObjects(conn *connection.IRODSConnection, collection *types.IRODSCo
 		}
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// merge
-			existingObj.Replicas = append(existingObj.Replicas, object.Replicas[0])
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
-	}
-
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
-	}
-
-	return mergedDataObjects, nil
+	return mergeReplicas(dataObjects), nil
 }
 
 // ListDataObjectsMasterReplica lists data objects in the given collection, returns only master replica
@@ -659,7 +683,7 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 	for continueQuery {
 		// data object
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
@@ -668,6 +692,7 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -676,9 +701,8 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
-		query.AddCondition(common.ICAT_COLUMN_D_REPL_STATUS, "= '1'")
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -765,6 +789,8 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -805,35 +831,81 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 		}
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
+	return mergeMasterReplicas(dataObjects), nil
+}
 
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// compare and replace
-			if len(existingObj.Replicas) == 0 {
-				// replace
-				mergedDataObjectsMap[object.ID] = object
-			} else if len(object.Replicas) > 0 {
-				if existingObj.Replicas[0].CreateTime.After(object.Replicas[0].CreateTime) {
-					// found old replica (meaning master) - replace
-					mergedDataObjectsMap[object.ID] = object
-				}
-			}
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
+// ListDataObjectsForReplica lists data objects in the given collection, returns only the replica of the replica number
+// replicas are filtered in the query, data objects not having the replica are not listed
+func ListDataObjectsForReplica(conn *connection.IRODSConnection, collection *types.IRODSCollection, replicaNumber int64) ([]*types.IRODSDataObject, error) {
+	return listDataObjectsWithReplicaCondition(conn, collection, common.ICAT_COLUMN_DATA_REPL_NUM, fmt.Sprintf("= '%d'", replicaNumber))
+}
+
+// ListDataObjectsForResource lists data objects in the given collection, returns only the replica in the resource
+// replicas are filtered in the query, data objects not having a replica in the resource are not listed
+func ListDataObjectsForResource(conn *connection.IRODSConnection, collection *types.IRODSCollection, resource string) ([]*types.IRODSDataObject, error) {
+	return listDataObjectsWithReplicaCondition(conn, collection, common.ICAT_COLUMN_D_RESC_NAME, util.MakeGenQueryEqualCondition(resource))
+}
+
+func listDataObjectsWithReplicaCondition(conn *connection.IRODSConnection, collection *types.IRODSCollection, column common.ICATColumnNumber, condVal string) ([]*types.IRODSDataObject, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
 
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	return queryDataObjectsMasterReplica(conn, getZoneHint(conn, collection.Path), map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryEqualCondition(collection.Path),
+		column:                       condVal,
+	})
+}
+
+// ListDataObjectsMasterReplicaRecursive lists all data objects under the given collection at any depth, returns only master replica
+// data objects are listed with two queries, one for the collection and one for all collections under it
+func ListDataObjectsMasterReplicaRecursive(conn *connection.IRODSConnection, path string) ([]*types.IRODSDataObject, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	zone := getZoneHint(conn, path)
+
+	dataObjects, err := queryDataObjectsMasterReplica(conn, zone, map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryEqualCondition(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subTreeDataObjects, err := queryDataObjectsMasterReplica(conn, zone, map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: getSubTreeCondition(path),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return mergedDataObjects, nil
+	// "_" and "%" in the path are wildcards in the condition
+	for _, dataObject := range subTreeDataObjects {
+		if isInSubTree(path, util.GetIRODSPathDirname(dataObject.Path)) {
+			dataObjects = append(dataObjects, dataObject)
+		}
+	}
+
+	return dataObjects, nil
 }
 
 // ListDataObjectMeta returns a data object metadata for the path
@@ -857,7 +929,7 @@ func ListDataObjectMeta(conn *connection.IRODSConnection, collection *types.IROD
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_META_DATA_ATTR_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_DATA_ATTR_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_DATA_ATTR_VALUE, 1)
@@ -865,9 +937,9 @@ func ListDataObjectMeta(conn *connection.IRODSConnection, collection *types.IROD
 		query.AddSelect(common.ICAT_COLUMN_META_DATA_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_DATA_MODIFY_TIME, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
-		nameCondVal := fmt.Sprintf("= '%s'", filename)
+		nameCondVal := util.MakeGenQueryEqualCondition(filename)
 		query.AddCondition(common.ICAT_COLUMN_DATA_NAME, nameCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -979,21 +1051,25 @@ func ListDataObjectAccesses(conn *connection.IRODSConnection, collection *types.
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_DATA_ACCESS_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
-		nameCondVal := fmt.Sprintf("= '%s'", filename)
+		nameCondVal := util.MakeGenQueryEqualCondition(filename)
 		query.AddCondition(common.ICAT_COLUMN_DATA_NAME, nameCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a data object access query result message: %w", err)
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("failed to receive a data object access query result message: %w", getAccessQueryError(util.MakeIRODSPath(collection.Path, filename), err))
 		}
 
 		err = queryResult.CheckError()
@@ -1002,7 +1078,7 @@ func ListDataObjectAccesses(conn *connection.IRODSConnection, collection *types.
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("received data object access query error: %w", err)
+			return nil, xerrors.Errorf("received data object access query error: %w", getAccessQueryError(util.MakeIRODSPath(collection.Path, filename), err))
 		}
 
 		if queryResult.RowCount == 0 {
@@ -1082,20 +1158,24 @@ func ListAccessesForDataObjects(conn *connection.IRODSConnection, collection *ty
 	continueIndex := 0
 	for continueQuery {
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, getZoneHint(conn, collection.Path))
 		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_DATA_ACCESS_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 
-		collCondVal := fmt.Sprintf("= '%s'", collection.Path)
+		collCondVal := util.MakeGenQueryEqualCondition(collection.Path)
 		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a data object access query result message: %w", err)
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("failed to receive a data object access query result message: %w", getAccessQueryError(collection.Path, err))
 		}
 
 		err = queryResult.CheckError()
@@ -1104,7 +1184,7 @@ func ListAccessesForDataObjects(conn *connection.IRODSConnection, collection *ty
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("received data object access query error: %w", err)
+			return nil, xerrors.Errorf("received data object access query error: %w", getAccessQueryError(collection.Path, err))
 		}
 
 		if queryResult.RowCount == 0 {
@@ -1194,6 +1274,12 @@ func DeleteDataObject(conn *connection.IRODSConnection, path string, force bool)
 
 // MoveDataObject moves a data object for the path to another path
 func MoveDataObject(conn *connection.IRODSConnection, srcPath string, destPath string) error {
+	return MoveDataObjectToResource(conn, srcPath, destPath, "", false)
+}
+
+// MoveDataObjectToResource moves a data object for the path to another path, an existing dest data object is replaced if force is set
+// the resource is given to the server as the dest resource, the server refuses the rename if the data object is not in the resource
+func MoveDataObjectToResource(conn *connection.IRODSConnection, srcPath string, destPath string, resource string, force bool) error {
 	if conn == nil || !conn.IsConnected() {
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -1208,19 +1294,55 @@ func MoveDataObject(conn *connection.IRODSConnection, srcPath string, destPath s
 	defer conn.Unlock()
 
 	request := message.NewIRODSMessageMoveDataObjectRequest(srcPath, destPath)
+
+	if len(resource) > 0 {
+		request.AddKeyVal(common.DEST_RESC_NAME_KW, resource)
+	}
+
+	if force {
+		request.AddKeyVal(common.FORCE_FLAG_KW, "")
+	}
+
 	response := message.IRODSMessageMoveDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
-		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+		code := types.GetIRODSErrorCode(err)
+		switch {
+		case code == common.CAT_NO_ROWS_FOUND:
 			return xerrors.Errorf("failed to find the data object for path %s: %w", srcPath, types.NewFileNotFoundError(srcPath))
+		case code == common.CAT_NAME_EXISTS_AS_DATAOBJ || code == common.CAT_NAME_EXISTS_AS_COLLECTION:
+			return xerrors.Errorf("failed to move data object to %s: %w", destPath, types.NewFileAlreadyExistError(destPath))
+		case isRenameNotSupportedErrorCode(code):
+			return xerrors.Errorf("failed to move data object: %w", types.NewRenameNotSupportedError(srcPath, destPath, code))
 		}
 		return xerrors.Errorf("failed to move data object: %w", err)
 	}
 	return nil
 }
 
+// isRenameNotSupportedErrorCode returns true if the error code is for a rename the server does not do in place
+func isRenameNotSupportedErrorCode(code common.ErrorCode) bool {
+	mainCode, subCode := common.SplitIRODSErrorCode(code)
+
+	switch mainCode {
+	case common.SYS_CROSS_ZONE_MV_NOT_SUPPORTED, common.SYS_SRC_DEST_SPEC_COLL_CONFLICT, common.CANT_RM_MV_BUNDLE_TYPE:
+		return true
+	case common.UNIX_FILE_RENAME_ERR:
+		// rename(2) across devices, e.g., vaults of different resources
+		return subCode == -common.EXDEV
+	default:
+		return false
+	}
+}
+
 // CopyDataObject creates a copy of a data object for the path
 func CopyDataObject(conn *connection.IRODSConnection, srcPath string, destPath string, force bool) error {
+	return CopyDataObjectToResource(conn, srcPath, destPath, "", force)
+}
+
+// CopyDataObjectToResource creates a copy of a data object for the path in the resource
+// the default resource is used if resource is empty
+func CopyDataObjectToResource(conn *connection.IRODSConnection, srcPath string, destPath string, resource string, force bool) error {
 	if conn == nil || !conn.IsConnected() {
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -1235,6 +1357,10 @@ func CopyDataObject(conn *connection.IRODSConnection, srcPath string, destPath s
 	defer conn.Unlock()
 
 	request := message.NewIRODSMessageCopyDataObjectRequest(srcPath, destPath, force)
+	if len(resource) > 0 {
+		request.AddKeyVal(common.DEST_RESC_NAME_KW, resource)
+	}
+
 	response := message.IRODSMessageCopyDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
@@ -1273,6 +1399,37 @@ func TruncateDataObject(conn *connection.IRODSConnection, path string, size int6
 	return nil
 }
 
+// TouchDataObject sets modify time of a data object for the path, the data object is not created if not exist
+func TouchDataObject(conn *connection.IRODSConnection, path string, modifyTime time.Time) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForDataObjectUpdate(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	if !conn.GetVersion().HasHigherVersionThan(4, 2, 9) {
+		return xerrors.Errorf("does not support touch in current iRODS Version")
+	}
+
+	request := message.NewIRODSMessageTouchRequest(path, true, modifyTime.Unix())
+	response := message.IRODSMessageTouchResponse{}
+	err := conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND || types.GetIRODSErrorCode(err) == common.OBJ_PATH_DOES_NOT_EXIST {
+			return xerrors.Errorf("failed to find the data object for path %s: %w", path, types.NewFileNotFoundError(path))
+		}
+		return xerrors.Errorf("failed to touch data object: %w", err)
+	}
+	return nil
+}
+
 // ReplicateDataObject replicates a data object for the path to the given reousrce
 func ReplicateDataObject(conn *connection.IRODSConnection, path string, resource string, update bool, adminFlag bool) error {
 	if conn == nil || !conn.IsConnected() {
@@ -1315,6 +1472,45 @@ func ReplicateDataObject(conn *connection.IRODSConnection, path string, resource
 	return nil
 }
 
+// PhysicalMoveDataObject moves a replica of a data object for the path from the source resource to the dest resource
+// the logical path is not changed, a replica on any resource is moved if srcResource is empty
+func PhysicalMoveDataObject(conn *connection.IRODSConnection, path string, srcResource string, destResource string, adminFlag bool) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForDataObjectUpdate(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	// use default resource when resource param is empty
+	if len(destResource) == 0 {
+		account := conn.GetAccount()
+		destResource = account.DefaultResource
+	}
+
+	request := message.NewIRODSMessagePhysicalMoveDataObjectRequest(path, srcResource, destResource)
+
+	if adminFlag {
+		request.AddKeyVal(common.ADMIN_KW, "")
+	}
+
+	response := message.IRODSMessagePhysicalMoveDataObjectResponse{}
+	err := conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+			return xerrors.Errorf("failed to find the data object for path %s: %w", path, types.NewFileNotFoundError(path))
+		}
+		return xerrors.Errorf("failed to move data object replica: %w", err)
+	}
+	return nil
+}
+
 // TrimDataObject trims replicas for a data object
 func TrimDataObject(conn *connection.IRODSConnection, path string, resource string, minCopies int, minAgeMinutes int, adminFlag bool) error {
 	if conn == nil || !conn.IsConnected() {
@@ -1398,6 +1594,37 @@ func OpenDataObject(conn *connection.IRODSConnection, path string, resource stri
 		return nil, -1, xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	// use default resource when resource param is empty
+	if len(resource) == 0 {
+		account := conn.GetAccount()
+		resource = account.DefaultResource
+	}
+
+	request := message.NewIRODSMessageOpenDataObjectRequest(path, resource, types.FileOpenMode(mode))
+	return openDataObject(conn, path, resource, mode, request)
+}
+
+// OpenDataObjectReplica opens the replica of a data object in the resource hierarchy, returns a file handle
+// the replica is given instead of letting the server choose one, e.g., to read a replica not in an archive
+func OpenDataObjectReplica(conn *connection.IRODSConnection, path string, resourceHierarchy string, mode string) (*types.IRODSFileHandle, int64, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, -1, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	request := message.NewIRODSMessageOpenDataObjectRequest(path, "", types.FileOpenMode(mode))
+	request.AddKeyVal(common.RESC_HIER_STR_KW, resourceHierarchy)
+
+	// the leaf resource has the replica
+	resource := resourceHierarchy
+	if idx := strings.LastIndex(resourceHierarchy, ";"); idx >= 0 {
+		resource = resourceHierarchy[idx+1:]
+	}
+
+	return openDataObject(conn, path, resource, mode, request)
+}
+
+// openDataObject sends the open request, returns a file handle
+func openDataObject(conn *connection.IRODSConnection, path string, resource string, mode string, request *message.IRODSMessageOpenDataObjectRequest) (*types.IRODSFileHandle, int64, error) {
 	metrics := conn.GetMetrics()
 	if metrics != nil {
 		metrics.IncreaseCounterForDataObjectOpen(1)
@@ -1407,15 +1634,8 @@ func OpenDataObject(conn *connection.IRODSConnection, path string, resource stri
 	conn.Lock()
 	defer conn.Unlock()
 
-	// use default resource when resource param is empty
-	if len(resource) == 0 {
-		account := conn.GetAccount()
-		resource = account.DefaultResource
-	}
-
 	fileOpenMode := types.FileOpenMode(mode)
 
-	request := message.NewIRODSMessageOpenDataObjectRequest(path, resource, fileOpenMode)
 	response := message.IRODSMessageOpenDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
@@ -1507,8 +1727,23 @@ func OpenDataObjectWithReplicaToken(conn *connection.IRODSConnection, path strin
 	return handle, offset, nil
 }
 
+// PutChecksumOptions asks the server to compute a checksum of a data object being written when it is closed
+type PutChecksumOptions struct {
+	// register the checksum in the catalog
+	Register bool
+	// checksum of the data written, in iRODS checksum string format, e.g., sha2:<base64 digest>
+	// closing fails with USER_CHKSUM_MISMATCH if the checksum computed by the server differs, the checksum is registered otherwise
+	VerifyChecksum string
+}
+
 // OpenDataObjectWithOperation opens a data object for the path, returns a file handle
 func OpenDataObjectWithOperation(conn *connection.IRODSConnection, path string, resource string, mode string, oper common.OperationType) (*types.IRODSFileHandle, error) {
+	return OpenDataObjectWithOperationAndChecksum(conn, path, resource, mode, oper, nil)
+}
+
+// OpenDataObjectWithOperationAndChecksum opens a data object for the path, returns a file handle
+// the server computes a checksum of the data object when the handle is closed as checksumOptions asks, nil for no checksum
+func OpenDataObjectWithOperationAndChecksum(conn *connection.IRODSConnection, path string, resource string, mode string, oper common.OperationType, checksumOptions *PutChecksumOptions) (*types.IRODSFileHandle, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -1531,6 +1766,15 @@ func OpenDataObjectWithOperation(conn *connection.IRODSConnection, path string,
 	fileOpenMode := types.FileOpenMode(mode)
 
 	request := message.NewIRODSMessageOpenobjRequestWithOperation(path, resource, fileOpenMode, oper)
+	if checksumOptions != nil {
+		if len(checksumOptions.VerifyChecksum) > 0 {
+			request.AddKeyVal(common.VERIFY_CHKSUM_KW, "")
+			request.AddKeyVal(common.CHKSUM_KW, checksumOptions.VerifyChecksum)
+		} else if checksumOptions.Register {
+			request.AddKeyVal(common.REG_CHKSUM_KW, "")
+		}
+	}
+
 	response := message.IRODSMessageOpenDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
@@ -1619,10 +1863,10 @@ func OpenDataObjectForPutParallel(conn *connection.IRODSConnection, path string,
 	return handle, nil
 }
 
-// GetReplicaAccessInfo returns replica token and resource hierarchy
-func GetReplicaAccessInfo(conn *connection.IRODSConnection, handle *types.IRODSFileHandle) (string, string, error) {
+// GetFileDescriptorInfo returns information the server keeps for the file descriptor of an opened data object
+func GetFileDescriptorInfo(conn *connection.IRODSConnection, handle *types.IRODSFileHandle) (*types.IRODSFileDescriptorInfo, error) {
 	if conn == nil || !conn.IsConnected() {
-		return "", "", xerrors.Errorf("connection is nil or disconnected")
+		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
 
 	metrics := conn.GetMetrics()
@@ -1639,22 +1883,49 @@ func GetReplicaAccessInfo(conn *connection.IRODSConnection, handle *types.IRODSF
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
 		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
-			return "", "", xerrors.Errorf("failed to find the data object for path %s: %w", handle.Path, types.NewFileNotFoundError(handle.Path))
+			return nil, xerrors.Errorf("failed to find the data object for path %s: %w", handle.Path, types.NewFileNotFoundError(handle.Path))
 		}
-		return "", "", xerrors.Errorf("failed to get replica access info: %w", err)
+		return nil, xerrors.Errorf("failed to get file descriptor info: %w", err)
+	}
+
+	info := &types.IRODSFileDescriptorInfo{
+		FileDescriptor: handle.FileDescriptor,
+		Path:           handle.Path,
+		ReplicaToken:   response.ReplicaToken,
+		DataSize:       response.DataSize,
+		BytesWritten:   response.BytesWritten,
+		Checksum:       response.Checksum,
 	}
 
 	// handle fields buried in other structs
-	// ResourceHierarchy
-	resourceHierarchy := ""
 	if response.DataObjectInfo != nil {
-		if resourceHierarchyInfo, ok := response.DataObjectInfo["resource_hierarchy"]; ok {
-			resourceHierarchy = fmt.Sprintf("%v", resourceHierarchyInfo)
-			resourceHierarchy = strings.TrimSpace(resourceHierarchy)
+		if objectPath, ok := response.DataObjectInfo["object_path"]; ok {
+			info.Path = strings.TrimSpace(fmt.Sprintf("%v", objectPath))
+		}
+
+		if resourceHierarchy, ok := response.DataObjectInfo["resource_hierarchy"]; ok {
+			info.ResourceHierarchy = strings.TrimSpace(fmt.Sprintf("%v", resourceHierarchy))
+		}
+
+		if replicaNumber, ok := response.DataObjectInfo["replica_number"]; ok {
+			number, err := strconv.ParseInt(strings.TrimSpace(fmt.Sprintf("%v", replicaNumber)), 10, 64)
+			if err == nil {
+				info.ReplicaNumber = number
+			}
 		}
 	}
 
-	return response.ReplicaToken, resourceHierarchy, nil
+	return info, nil
+}
+
+// GetReplicaAccessInfo returns replica token and resource hierarchy
+func GetReplicaAccessInfo(conn *connection.IRODSConnection, handle *types.IRODSFileHandle) (string, string, error) {
+	info, err := GetFileDescriptorInfo(conn, handle)
+	if err != nil {
+		return "", "", xerrors.Errorf("failed to get replica access info: %w", err)
+	}
+
+	return info.ReplicaToken, info.ResourceHierarchy, nil
 }
 
 // SeekDataObject moves file pointer of a data object, returns offset
@@ -2103,6 +2374,11 @@ func AddDataObjectMeta(conn *connection.IRODSConnection, path string, metadata *
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	err := validateMeta(metadata)
+	if err != nil {
+		return err
+	}
+
 	metrics := conn.GetMetrics()
 	if metrics != nil {
 		metrics.IncreaseCounterForMetadataCreate(1)
@@ -2114,11 +2390,14 @@ func AddDataObjectMeta(conn *connection.IRODSConnection, path string, metadata *
 
 	request := message.NewIRODSMessageAddMetadataRequest(types.IRODSDataObjectMetaItemType, path, metadata)
 	response := message.IRODSMessageModifyMetadataResponse{}
-	err := conn.RequestAndCheck(request, &response, nil)
+	err = conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
 		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
 			return xerrors.Errorf("failed to find the data object for path %s: %w", path, types.NewFileNotFoundError(path))
 		}
+		if metaErr := getMetaTooLargeError(err, metadata); metaErr != nil {
+			return xerrors.Errorf("failed to add data object meta: %w", metaErr)
+		}
 		return xerrors.Errorf("failed to add data object meta: %w", err)
 	}
 	return nil
@@ -2194,6 +2473,7 @@ func SearchDataObjectsByMeta(conn *connection.IRODSConnection, metaName string,
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -2202,9 +2482,9 @@ func SearchDataObjectsByMeta(conn *connection.IRODSConnection, metaName string,
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
+		metaNameCondVal := util.MakeGenQueryEqualCondition(metaName)
 		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("= '%s'", metaValue)
+		metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
 		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_VALUE, metaValueCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -2308,6 +2588,8 @@ func SearchDataObjectsByMeta(conn *connection.IRODSConnection, metaName string,
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -2348,30 +2630,39 @@ func SearchDataObjectsByMeta(conn *connection.IRODSConnection, metaName string,
 		}
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// merge
-			existingObj.Replicas = append(existingObj.Replicas, object.Replicas[0])
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
-	}
-
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
-	}
-
-	return mergedDataObjects, nil
+	return mergeReplicas(dataObjects), nil
 }
 
 // SearchDataObjectsMasterReplicaByMeta searches data objects by metadata, returns only master replica
 func SearchDataObjectsMasterReplicaByMeta(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSDataObject, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	return searchDataObjectsMasterReplicaByMeta(conn, metaName, metaValueCondVal, "")
+}
+
+// SearchDataObjectsMasterReplicaByMetaWildcard searches data objects by metadata, returns only master replica
+// Caution: This is a very slow operation
+func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSDataObject, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	return searchDataObjectsMasterReplicaByMeta(conn, metaName, metaValueCondVal, "")
+}
+
+// SearchDataObjectsMasterReplicaByMetaWithUnits searches data objects by metadata name, value and units, returns only master replica
+func SearchDataObjectsMasterReplicaByMetaWithUnits(conn *connection.IRODSConnection, metaName string, metaValue string, metaUnits string) ([]*types.IRODSDataObject, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	metaUnitsCondVal := util.MakeGenQueryEqualCondition(metaUnits)
+	return searchDataObjectsMasterReplicaByMeta(conn, metaName, metaValueCondVal, metaUnitsCondVal)
+}
+
+// SearchDataObjectsMasterReplicaByMetaWithUnitsWildcard searches data objects by metadata name, value and units, returns only master replica
+// metaValue and metaUnits are in SQL LIKE syntax, e.g., "ns:%"
+// Caution: This is a very slow operation
+func SearchDataObjectsMasterReplicaByMetaWithUnitsWildcard(conn *connection.IRODSConnection, metaName string, metaValue string, metaUnits string) ([]*types.IRODSDataObject, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	metaUnitsCondVal := util.MakeGenQueryLikeCondition(metaUnits)
+	return searchDataObjectsMasterReplicaByMeta(conn, metaName, metaValueCondVal, metaUnitsCondVal)
+}
+
+func searchDataObjectsMasterReplicaByMeta(conn *connection.IRODSConnection, metaName string, metaValueCondVal string, metaUnitsCondVal string) ([]*types.IRODSDataObject, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -2385,224 +2676,25 @@ func SearchDataObjectsMasterReplicaByMeta(conn *connection.IRODSConnection, meta
 	conn.Lock()
 	defer conn.Unlock()
 
-	dataObjects := []*types.IRODSDataObject{}
-
-	continueQuery := true
-	continueIndex := 0
-	for continueQuery {
-		// data object
-		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
-		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
-		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
-		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
-		query.AddSelect(common.ICAT_COLUMN_DATA_TYPE_NAME, 1)
-
-		// replica
-		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_DATA_PATH, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_RESC_HIER, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
-		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
-
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
-		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("= '%s'", metaValue)
-		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_VALUE, metaValueCondVal)
-		query.AddCondition(common.ICAT_COLUMN_D_REPL_STATUS, "= '1'")
-
-		queryResult := message.IRODSMessageQueryResponse{}
-		err := conn.Request(query, &queryResult, nil)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a data object query result message: %w", err)
-		}
-
-		err = queryResult.CheckError()
-		if err != nil {
-			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
-				// empty
-				break
-			}
-			return nil, xerrors.Errorf("received data object query error: %w", err)
-		}
-
-		if queryResult.RowCount == 0 {
-			break
-		}
-
-		if queryResult.AttributeCount > len(queryResult.SQLResult) {
-			return nil, xerrors.Errorf("failed to receive data object attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
-		}
-
-		pagenatedDataObjects := make([]*types.IRODSDataObject, queryResult.RowCount)
-
-		for attr := 0; attr < queryResult.AttributeCount; attr++ {
-			sqlResult := queryResult.SQLResult[attr]
-			if len(sqlResult.Values) != queryResult.RowCount {
-				return nil, xerrors.Errorf("failed to receive data object rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
-			}
-
-			for row := 0; row < queryResult.RowCount; row++ {
-				value := sqlResult.Values[row]
-
-				if pagenatedDataObjects[row] == nil {
-					// create a new
-					replica := &types.IRODSReplica{
-						Number:            -1,
-						Owner:             "",
-						Checksum:          nil,
-						Status:            "",
-						ResourceName:      "",
-						Path:              "",
-						ResourceHierarchy: "",
-						CreateTime:        time.Time{},
-						ModifyTime:        time.Time{},
-					}
-
-					pagenatedDataObjects[row] = &types.IRODSDataObject{
-						ID:           -1,
-						CollectionID: -1,
-						Path:         "",
-						Name:         "",
-						Size:         0,
-						DataType:     "",
-						Replicas:     []*types.IRODSReplica{replica},
-					}
-				}
-
-				switch sqlResult.AttributeIndex {
-				case int(common.ICAT_COLUMN_COLL_ID):
-					collID, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse collection id '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].CollectionID = collID
-				case int(common.ICAT_COLUMN_COLL_NAME):
-					if len(pagenatedDataObjects[row].Path) > 0 {
-						pagenatedDataObjects[row].Path = util.MakeIRODSPath(value, pagenatedDataObjects[row].Path)
-					} else {
-						pagenatedDataObjects[row].Path = value
-					}
-				case int(common.ICAT_COLUMN_D_DATA_ID):
-					objID, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse data object id '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].ID = objID
-				case int(common.ICAT_COLUMN_DATA_NAME):
-					if len(pagenatedDataObjects[row].Path) > 0 {
-						pagenatedDataObjects[row].Path = util.MakeIRODSPath(pagenatedDataObjects[row].Path, value)
-					} else {
-						pagenatedDataObjects[row].Path = value
-					}
-					pagenatedDataObjects[row].Name = value
-				case int(common.ICAT_COLUMN_DATA_SIZE):
-					objSize, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse data object size '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].Size = objSize
-				case int(common.ICAT_COLUMN_DATA_TYPE_NAME):
-					pagenatedDataObjects[row].DataType = value
-				case int(common.ICAT_COLUMN_DATA_REPL_NUM):
-					repNum, err := strconv.ParseInt(value, 10, 64)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse data object replica number '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].Replicas[0].Number = repNum
-				case int(common.ICAT_COLUMN_D_OWNER_NAME):
-					pagenatedDataObjects[row].Replicas[0].Owner = value
-				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
-					checksum, err := types.CreateIRODSChecksum(value)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse data object checksum '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].Replicas[0].Checksum = checksum
-				case int(common.ICAT_COLUMN_D_REPL_STATUS):
-					pagenatedDataObjects[row].Replicas[0].Status = value
-				case int(common.ICAT_COLUMN_D_RESC_NAME):
-					pagenatedDataObjects[row].Replicas[0].ResourceName = value
-				case int(common.ICAT_COLUMN_D_DATA_PATH):
-					pagenatedDataObjects[row].Replicas[0].Path = value
-				case int(common.ICAT_COLUMN_D_RESC_HIER):
-					pagenatedDataObjects[row].Replicas[0].ResourceHierarchy = value
-				case int(common.ICAT_COLUMN_D_CREATE_TIME):
-					cT, err := util.GetIRODSDateTime(value)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].Replicas[0].CreateTime = cT
-				case int(common.ICAT_COLUMN_D_MODIFY_TIME):
-					mT, err := util.GetIRODSDateTime(value)
-					if err != nil {
-						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
-					}
-					pagenatedDataObjects[row].Replicas[0].ModifyTime = mT
-				default:
-					// ignore
-				}
-			}
-		}
-
-		dataObjects = append(dataObjects, pagenatedDataObjects...)
-
-		continueIndex = queryResult.ContinueIndex
-		if continueIndex == 0 {
-			continueQuery = false
-		}
+	conditions := map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_META_DATA_ATTR_NAME:  util.MakeGenQueryEqualCondition(metaName),
+		common.ICAT_COLUMN_META_DATA_ATTR_VALUE: metaValueCondVal,
 	}
-
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// compare and replace
-			if len(existingObj.Replicas) == 0 {
-				// replace
-				mergedDataObjectsMap[object.ID] = object
-			} else if len(object.Replicas) > 0 {
-				if existingObj.Replicas[0].CreateTime.After(object.Replicas[0].CreateTime) {
-					// found old replica (meaning master) - replace
-					mergedDataObjectsMap[object.ID] = object
-				}
-			}
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
+	if len(metaUnitsCondVal) > 0 {
+		conditions[common.ICAT_COLUMN_META_DATA_ATTR_UNITS] = metaUnitsCondVal
 	}
 
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
-	}
-
-	return mergedDataObjects, nil
+	return queryDataObjectsMasterReplica(conn, conn.GetAccount().ClientZone, conditions)
 }
 
-// SearchDataObjectsByMetaWildcard searches data objects by metadata
-// Caution: This is a very slow operation
-func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSDataObject, error) {
-	if conn == nil || !conn.IsConnected() {
-		return nil, xerrors.Errorf("connection is nil or disconnected")
-	}
-
-	metrics := conn.GetMetrics()
-	if metrics != nil {
-		metrics.IncreaseCounterForSearch(1)
+// queryDataObjectsMasterReplica queries data objects matching the conditions in the zone, returns only master replica
+// the connection must be locked by the caller
+func queryDataObjectsMasterReplica(conn *connection.IRODSConnection, zone string, conditions map[common.ICATColumnNumber]string) ([]*types.IRODSDataObject, error) {
+	conditionColumns := []int{}
+	for column := range conditions {
+		conditionColumns = append(conditionColumns, int(column))
 	}
-
-	// lock the connection
-	conn.Lock()
-	defer conn.Unlock()
+	sort.Ints(conditionColumns)
 
 	dataObjects := []*types.IRODSDataObject{}
 
@@ -2611,7 +2703,7 @@ func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName
 	for continueQuery {
 		// data object
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
-		query.AddKeyVal(common.ZONE_KW, conn.GetAccount().ClientZone)
+		query.AddKeyVal(common.ZONE_KW, zone)
 		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
 		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
@@ -2622,6 +2714,7 @@ func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -2630,10 +2723,9 @@ func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
-		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("like '%s'", metaValue)
-		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_VALUE, metaValueCondVal)
+		for _, column := range conditionColumns {
+			query.AddCondition(common.ICATColumnNumber(column), conditions[common.ICATColumnNumber(column)])
+		}
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -2736,6 +2828,8 @@ func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -2776,31 +2870,12 @@ func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName
 		}
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// merge
-			existingObj.Replicas = append(existingObj.Replicas, object.Replicas[0])
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
-	}
-
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
-	}
-
-	return mergedDataObjects, nil
+	return mergeMasterReplicas(dataObjects), nil
 }
 
-// SearchDataObjectsMasterReplicaByMetaWildcard searches data objects by metadata, returns only master replica
+// SearchDataObjectsByMetaWildcard searches data objects by metadata
 // Caution: This is a very slow operation
-func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSDataObject, error) {
+func SearchDataObjectsByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSDataObject, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -2832,6 +2907,7 @@ func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnecti
 		// replica
 		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_ZONE, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
@@ -2840,11 +2916,10 @@ func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnecti
 		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
 
-		metaNameCondVal := fmt.Sprintf("= '%s'", metaName)
+		metaNameCondVal := util.MakeGenQueryEqualCondition(metaName)
 		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_NAME, metaNameCondVal)
-		metaValueCondVal := fmt.Sprintf("like '%s'", metaValue)
+		metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
 		query.AddCondition(common.ICAT_COLUMN_META_DATA_ATTR_VALUE, metaValueCondVal)
-		query.AddCondition(common.ICAT_COLUMN_D_REPL_STATUS, "= '1'")
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
@@ -2947,6 +3022,8 @@ func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnecti
 					pagenatedDataObjects[row].Replicas[0].Number = repNum
 				case int(common.ICAT_COLUMN_D_OWNER_NAME):
 					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_OWNER_ZONE):
+					pagenatedDataObjects[row].Replicas[0].OwnerZone = value
 				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
 					checksum, err := types.CreateIRODSChecksum(value)
 					if err != nil {
@@ -2987,34 +3064,7 @@ func SearchDataObjectsMasterReplicaByMetaWildcard(conn *connection.IRODSConnecti
 		}
 	}
 
-	// merge data objects per file
-	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
-	for _, object := range dataObjects {
-		existingObj, exists := mergedDataObjectsMap[object.ID]
-		if exists {
-			// compare and replace
-			if len(existingObj.Replicas) == 0 {
-				// replace
-				mergedDataObjectsMap[object.ID] = object
-			} else if len(object.Replicas) > 0 {
-				if existingObj.Replicas[0].CreateTime.After(object.Replicas[0].CreateTime) {
-					// found old replica (meaning master) - replace
-					mergedDataObjectsMap[object.ID] = object
-				}
-			}
-		} else {
-			// add
-			mergedDataObjectsMap[object.ID] = object
-		}
-	}
-
-	// convert map to array
-	mergedDataObjects := []*types.IRODSDataObject{}
-	for _, object := range mergedDataObjectsMap {
-		mergedDataObjects = append(mergedDataObjects, object)
-	}
-
-	return mergedDataObjects, nil
+	return mergeReplicas(dataObjects), nil
 }
 
 // ChangeDataObjectAccess changes access control on a data object.
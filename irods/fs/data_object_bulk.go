@@ -2,6 +2,7 @@ package fs
 
 import (
 	"bytes"
+	"hash"
 	"io"
 	"os"
 	"sync"
@@ -106,18 +107,25 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 		"function": "UploadDataObject",
 	})
 
-	// use default resource when resource param is empty
-	if len(resource) == 0 {
-		account := session.GetAccount()
-		resource = account.DefaultResource
-	}
+	logger.Debugf("upload data object %s", localPath)
 
-	stat, err := os.Stat(localPath)
+	conn, err := session.AcquireConnection()
 	if err != nil {
-		return xerrors.Errorf("failed to stat file %s: %w", localPath, err)
+		return xerrors.Errorf("failed to get connection: %w", err)
 	}
+	defer session.ReturnConnection(conn)
 
-	fileLength := stat.Size()
+	return UploadDataObjectWithConnection(conn, localPath, irodsPath, resource, replicate, callback)
+}
+
+// UploadDataObjectWithChecksum put a data object at the local path to the iRODS path, writing the data sent to the hasher
+// the checksum of the file is computed without reading the file again
+// the server computes a checksum of the data object as checksumOptions asks, nil for no checksum
+func UploadDataObjectWithChecksum(session *session.IRODSSession, localPath string, irodsPath string, resource string, replicate bool, hasher hash.Hash, checksumOptions *PutChecksumOptions, callback common.TrackerCallBack) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"function": "UploadDataObjectWithChecksum",
+	})
 
 	logger.Debugf("upload data object %s", localPath)
 
@@ -127,10 +135,34 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 	}
 	defer session.ReturnConnection(conn)
 
+	return UploadDataObjectWithConnectionAndChecksum(conn, localPath, irodsPath, resource, replicate, hasher, checksumOptions, callback)
+}
+
+// UploadDataObjectWithConnection put a data object at the local path to the iRODS path over the given connection
+func UploadDataObjectWithConnection(conn *connection.IRODSConnection, localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
+	return UploadDataObjectWithConnectionAndChecksum(conn, localPath, irodsPath, resource, replicate, nil, nil, callback)
+}
+
+// UploadDataObjectWithConnectionAndChecksum put a data object at the local path to the iRODS path over the given connection, writing the data sent to the hasher
+// hasher is not used if nil, the server computes a checksum of the data object as checksumOptions asks, nil for no checksum
+func UploadDataObjectWithConnectionAndChecksum(conn *connection.IRODSConnection, localPath string, irodsPath string, resource string, replicate bool, hasher hash.Hash, checksumOptions *PutChecksumOptions, callback common.TrackerCallBack) error {
 	if conn == nil || !conn.IsConnected() {
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	// use default resource when resource param is empty
+	if len(resource) == 0 {
+		account := conn.GetAccount()
+		resource = account.DefaultResource
+	}
+
+	stat, err := os.Stat(localPath)
+	if err != nil {
+		return xerrors.Errorf("failed to stat file %s: %w", localPath, err)
+	}
+
+	fileLength := stat.Size()
+
 	f, err := os.OpenFile(localPath, os.O_RDONLY, 0)
 	if err != nil {
 		return xerrors.Errorf("failed to open file %s: %w", localPath, err)
@@ -138,7 +170,7 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 	defer f.Close()
 
 	// open a new file
-	handle, err := OpenDataObjectWithOperation(conn, irodsPath, resource, "w+", common.OPER_TYPE_NONE)
+	handle, err := OpenDataObjectWithOperationAndChecksum(conn, irodsPath, resource, "w+", common.OPER_TYPE_NONE, checksumOptions)
 	if err != nil {
 		return xerrors.Errorf("failed to open data object %s: %w", irodsPath, err)
 	}
@@ -156,7 +188,7 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 	}
 
 	// copy
-	buffer := make([]byte, common.ReadWriteBufferSize)
+	buffer := make([]byte, conn.GetTransferBufferSize())
 	var writeErr error
 	for {
 		bytesRead, readErr := f.Read(buffer)
@@ -166,6 +198,11 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 				break
 			}
 
+			if hasher != nil {
+				// hash.Hash never returns an error
+				hasher.Write(buffer[:bytesRead])
+			}
+
 			totalBytesUploaded += int64(bytesRead)
 			if callback != nil {
 				callback(totalBytesUploaded, fileLength)
@@ -182,12 +219,17 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 		}
 	}
 
-	CloseDataObject(conn, handle)
+	closeErr := CloseDataObject(conn, handle)
 
 	if writeErr != nil {
 		return writeErr
 	}
 
+	if closeErr != nil && checksumOptions != nil {
+		// the server computes the checksum when closing, a mismatch is reported here
+		return closeErr
+	}
+
 	// replicate
 	if replicate {
 		replErr := ReplicateDataObject(conn, irodsPath, "", true, false)
@@ -227,7 +269,7 @@ func UploadDataObjectParallel(session *session.IRODSSession, localPath string, i
 
 	numTasks := taskNum
 	if numTasks <= 0 {
-		numTasks = util.GetNumTasksForParallelTransfer(fileLength)
+		numTasks = util.GetNumTasksForParallelTransferWithBlockSize(fileLength, session.GetConfig().TransferBlockSize)
 	}
 
 	if numTasks == 1 {
@@ -320,10 +362,10 @@ func UploadDataObjectParallel(session *session.IRODSSession, localPath string, i
 		taskRemain := taskLength
 
 		// copy
-		buffer := make([]byte, common.ReadWriteBufferSize)
+		buffer := make([]byte, taskConn.GetTransferBufferSize())
 		var taskWriteErr error
 		for taskRemain > 0 {
-			bufferLen := common.ReadWriteBufferSize
+			bufferLen := taskConn.GetTransferBufferSize()
 			if taskRemain < int64(bufferLen) {
 				bufferLen = int(taskRemain)
 			}
@@ -439,7 +481,7 @@ func DownloadDataObjectToBuffer(session *session.IRODSSession, irodsPath string,
 		}
 	}
 
-	buffer2 := make([]byte, common.ReadWriteBufferSize)
+	buffer2 := make([]byte, conn.GetTransferBufferSize())
 	var writeErr error
 	// copy
 	for {
@@ -481,22 +523,27 @@ func DownloadDataObject(session *session.IRODSSession, irodsPath string, resourc
 
 	logger.Debugf("download data object %s", irodsPath)
 
-	// use default resource when resource param is empty
-	if len(resource) == 0 {
-		account := session.GetAccount()
-		resource = account.DefaultResource
-	}
-
 	conn, err := session.AcquireConnection()
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
 	defer session.ReturnConnection(conn)
 
+	return DownloadDataObjectWithConnection(conn, irodsPath, resource, localPath, fileLength, callback)
+}
+
+// DownloadDataObjectWithConnection downloads a data object at the iRODS path to the local path over the given connection
+func DownloadDataObjectWithConnection(conn *connection.IRODSConnection, irodsPath string, resource string, localPath string, fileLength int64, callback common.TrackerCallBack) error {
 	if conn == nil || !conn.IsConnected() {
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	// use default resource when resource param is empty
+	if len(resource) == 0 {
+		account := conn.GetAccount()
+		resource = account.DefaultResource
+	}
+
 	handle, _, err := OpenDataObject(conn, irodsPath, resource, "r")
 	if err != nil {
 		return xerrors.Errorf("failed to open data object %s: %w", irodsPath, err)
@@ -522,7 +569,7 @@ func DownloadDataObject(session *session.IRODSSession, irodsPath string, resourc
 	}
 
 	// copy
-	buffer := make([]byte, common.ReadWriteBufferSize)
+	buffer := make([]byte, conn.GetTransferBufferSize())
 	var writeErr error
 	for {
 		bytesRead, readErr := ReadDataObjectWithTrackerCallBack(conn, handle, buffer, blockReadCallback)
@@ -658,7 +705,7 @@ func DownloadDataObjectResumable(session *session.IRODSSession, irodsPath string
 	}
 
 	// copy
-	buffer := make([]byte, common.ReadWriteBufferSize)
+	buffer := make([]byte, conn.GetTransferBufferSize())
 	var writeErr error
 	for {
 		bytesRead, readErr := ReadDataObjectWithTrackerCallBack(conn, handle, buffer, blockReadCallback)
@@ -721,7 +768,7 @@ func DownloadDataObjectParallel(session *session.IRODSSession, irodsPath string,
 
 	numTasks := taskNum
 	if numTasks <= 0 {
-		numTasks = util.GetNumTasksForParallelTransfer(fileLength)
+		numTasks = util.GetNumTasksForParallelTransferWithBlockSize(fileLength, session.GetConfig().TransferBlockSize)
 	}
 
 	if numTasks > session.GetConfig().ConnectionMax {
@@ -816,10 +863,10 @@ func DownloadDataObjectParallel(session *session.IRODSSession, irodsPath string,
 		taskRemain := taskLength
 
 		// copy
-		buffer := make([]byte, common.ReadWriteBufferSize)
+		buffer := make([]byte, taskConn.GetTransferBufferSize())
 		var taskWriteErr error
 		for taskRemain > 0 {
-			bufferLen := common.ReadWriteBufferSize
+			bufferLen := taskConn.GetTransferBufferSize()
 			if taskRemain < int64(bufferLen) {
 				bufferLen = int(taskRemain)
 			}
@@ -891,7 +938,7 @@ func DownloadDataObjectParallelResumable(session *session.IRODSSession, irodsPat
 
 	numTasks := taskNum
 	if numTasks <= 0 {
-		numTasks = util.GetNumTasksForParallelTransfer(fileLength)
+		numTasks = util.GetNumTasksForParallelTransferWithBlockSize(fileLength, session.GetConfig().TransferBlockSize)
 	}
 
 	if numTasks > session.GetConfig().ConnectionMax {
@@ -1039,10 +1086,10 @@ func DownloadDataObjectParallelResumable(session *session.IRODSSession, irodsPat
 		}
 
 		// copy
-		buffer := make([]byte, common.ReadWriteBufferSize)
+		buffer := make([]byte, taskConn.GetTransferBufferSize())
 		var taskWriteErr error
 		for taskRemain > 0 {
-			bufferLen := common.ReadWriteBufferSize
+			bufferLen := taskConn.GetTransferBufferSize()
 			if taskRemain < int64(bufferLen) {
 				bufferLen = int(taskRemain)
 			}
@@ -1114,3 +1161,250 @@ func DownloadDataObjectParallelResumable(session *session.IRODSSession, irodsPat
 
 	return nil
 }
+
+// StreamCopyDataObject copies a data object at the source iRODS path to the dest iRODS path of another iRODS server
+// Data is read from the source and written to the dest through the client, so servers do not need to reach each other
+// Partitions the data object into n (taskNum) tasks and copies in parallel if the dest server supports parallel upload
+func StreamCopyDataObject(srcSession *session.IRODSSession, srcPath string, srcResource string, fileLength int64, destSession *session.IRODSSession, destPath string, destResource string, taskNum int, callback common.TrackerCallBack) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"function": "StreamCopyDataObject",
+	})
+
+	// use default resource when resource param is empty
+	if len(srcResource) == 0 {
+		account := srcSession.GetAccount()
+		srcResource = account.DefaultResource
+	}
+
+	if len(destResource) == 0 {
+		account := destSession.GetAccount()
+		destResource = account.DefaultResource
+	}
+
+	numTasks := taskNum
+	if numTasks <= 0 {
+		numTasks = util.GetNumTasksForParallelTransferWithBlockSize(fileLength, srcSession.GetConfig().TransferBlockSize)
+	}
+
+	if numTasks == 1 || !destSession.SupportParallelUpload() {
+		// serial copy
+		return streamCopyDataObjectSerial(srcSession, srcPath, srcResource, fileLength, destSession, destPath, destResource, callback)
+	}
+
+	conn, err := destSession.AcquireUnmanagedConnection()
+	if err != nil {
+		return xerrors.Errorf("failed to get connection: %w", err)
+	}
+	defer destSession.DiscardConnection(conn)
+
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	logger.Debugf("stream copy data object %s to %s in parallel, size(%d), threads(%d)", srcPath, destPath, fileLength, numTasks)
+
+	// open a new file
+	handle, err := OpenDataObjectForPutParallel(conn, destPath, destResource, "w+", common.OPER_TYPE_NONE, numTasks, fileLength)
+	if err != nil {
+		return err
+	}
+
+	replicaToken, resourceHierarchy, err := GetReplicaAccessInfo(conn, handle)
+	if err != nil {
+		CloseDataObject(conn, handle)
+		return err
+	}
+
+	errChan := make(chan error, numTasks*2)
+	taskWaitGroup := sync.WaitGroup{}
+
+	totalBytesCopied := int64(0)
+	if callback != nil {
+		callback(totalBytesCopied, fileLength)
+	}
+
+	progress := func(copied int64) {
+		newTotal := atomic.AddInt64(&totalBytesCopied, copied)
+		if callback != nil {
+			callback(newTotal, fileLength)
+		}
+	}
+
+	copyTask := func(taskOffset int64, taskLength int64) {
+		defer taskWaitGroup.Done()
+
+		// we will not reuse connections from the pools, as they should use fresh ones
+		srcTaskConn, taskErr := srcSession.AcquireUnmanagedConnection()
+		if taskErr != nil {
+			errChan <- xerrors.Errorf("failed to get connection: %w", taskErr)
+			return
+		}
+		defer srcSession.DiscardConnection(srcTaskConn)
+
+		destTaskConn, taskErr := destSession.AcquireUnmanagedConnection()
+		if taskErr != nil {
+			errChan <- xerrors.Errorf("failed to get connection: %w", taskErr)
+			return
+		}
+		defer destSession.DiscardConnection(destTaskConn)
+
+		if srcTaskConn == nil || !srcTaskConn.IsConnected() || destTaskConn == nil || !destTaskConn.IsConnected() {
+			errChan <- xerrors.Errorf("connection is nil or disconnected")
+			return
+		}
+
+		srcTaskHandle, _, taskErr := OpenDataObject(srcTaskConn, srcPath, srcResource, "r")
+		if taskErr != nil {
+			errChan <- xerrors.Errorf("failed to open data object %s: %w", srcPath, taskErr)
+			return
+		}
+		defer CloseDataObject(srcTaskConn, srcTaskHandle)
+
+		// open the file with read-write mode
+		// to not seek to end
+		destTaskHandle, _, taskErr := OpenDataObjectWithReplicaToken(destTaskConn, destPath, destResource, "w", replicaToken, resourceHierarchy, numTasks, fileLength)
+		if taskErr != nil {
+			errChan <- taskErr
+			return
+		}
+		defer func() {
+			errClose := CloseDataObjectReplica(destTaskConn, destTaskHandle)
+			if errClose != nil {
+				errChan <- errClose
+			}
+		}()
+
+		taskErr = copyDataObjectRange(srcTaskConn, srcTaskHandle, destTaskConn, destTaskHandle, taskOffset, taskLength, progress)
+		if taskErr != nil {
+			errChan <- taskErr
+		}
+	}
+
+	lengthPerThread := fileLength / int64(numTasks)
+	if fileLength%int64(numTasks) > 0 {
+		lengthPerThread++
+	}
+
+	offset := int64(0)
+
+	for i := 0; i < numTasks; i++ {
+		taskWaitGroup.Add(1)
+
+		go copyTask(offset, lengthPerThread)
+		offset += lengthPerThread
+	}
+
+	taskWaitGroup.Wait()
+
+	if len(errChan) > 0 {
+		CloseDataObject(conn, handle)
+		return <-errChan
+	}
+
+	return CloseDataObject(conn, handle)
+}
+
+// streamCopyDataObjectSerial copies a data object to another iRODS server through the client over a single stream
+func streamCopyDataObjectSerial(srcSession *session.IRODSSession, srcPath string, srcResource string, fileLength int64, destSession *session.IRODSSession, destPath string, destResource string, callback common.TrackerCallBack) error {
+	srcConn, err := srcSession.AcquireConnection()
+	if err != nil {
+		return xerrors.Errorf("failed to get connection: %w", err)
+	}
+	defer srcSession.ReturnConnection(srcConn)
+
+	destConn, err := destSession.AcquireConnection()
+	if err != nil {
+		return xerrors.Errorf("failed to get connection: %w", err)
+	}
+	defer destSession.ReturnConnection(destConn)
+
+	if srcConn == nil || !srcConn.IsConnected() || destConn == nil || !destConn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	srcHandle, _, err := OpenDataObject(srcConn, srcPath, srcResource, "r")
+	if err != nil {
+		return xerrors.Errorf("failed to open data object %s: %w", srcPath, err)
+	}
+	defer CloseDataObject(srcConn, srcHandle)
+
+	// open a new file
+	destHandle, err := OpenDataObjectWithOperation(destConn, destPath, destResource, "w+", common.OPER_TYPE_NONE)
+	if err != nil {
+		return xerrors.Errorf("failed to open data object %s: %w", destPath, err)
+	}
+
+	totalBytesCopied := int64(0)
+	if callback != nil {
+		callback(totalBytesCopied, fileLength)
+	}
+
+	progress := func(copied int64) {
+		totalBytesCopied += copied
+		if callback != nil {
+			callback(totalBytesCopied, fileLength)
+		}
+	}
+
+	err = copyDataObjectRange(srcConn, srcHandle, destConn, destHandle, 0, fileLength, progress)
+	if err != nil {
+		CloseDataObject(destConn, destHandle)
+		return err
+	}
+
+	return CloseDataObject(destConn, destHandle)
+}
+
+// copyDataObjectRange copies length bytes at the offset of the source data object to the same offset of the dest data object
+// progress is called with bytes copied by each write
+func copyDataObjectRange(srcConn *connection.IRODSConnection, srcHandle *types.IRODSFileHandle, destConn *connection.IRODSConnection, destHandle *types.IRODSFileHandle, offset int64, length int64, progress func(copied int64)) error {
+	if offset > 0 {
+		srcNewOffset, err := SeekDataObject(srcConn, srcHandle, offset, types.SeekSet)
+		if err != nil {
+			return err
+		}
+
+		destNewOffset, err := SeekDataObject(destConn, destHandle, offset, types.SeekSet)
+		if err != nil {
+			return err
+		}
+
+		if srcNewOffset != offset || destNewOffset != offset {
+			return xerrors.Errorf("failed to seek to target offset %d", offset)
+		}
+	}
+
+	remain := length
+
+	// copy
+	buffer := make([]byte, srcConn.GetTransferBufferSize())
+	for remain > 0 {
+		bufferLen := srcConn.GetTransferBufferSize()
+		if remain < int64(bufferLen) {
+			bufferLen = int(remain)
+		}
+
+		bytesRead, readErr := ReadDataObject(srcConn, srcHandle, buffer[:bufferLen])
+		if bytesRead > 0 {
+			writeErr := WriteDataObject(destConn, destHandle, buffer[:bytesRead])
+			if writeErr != nil {
+				return writeErr
+			}
+
+			remain -= int64(bytesRead)
+			if progress != nil {
+				progress(int64(bytesRead))
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF {
+				break
+			}
+			return xerrors.Errorf("failed to read data object %s: %w", srcHandle.Path, readErr)
+		}
+	}
+
+	return nil
+}
@@ -17,7 +17,14 @@ import (
 )
 
 // GetDataObjectRedirectionInfoForGet returns a redirection info for accessing the data object for downloading
+// the server decides the number of threads
 func GetDataObjectRedirectionInfoForGet(conn *connection.IRODSConnection, path string, resource string, fileLength int64) (*types.IRODSFileOpenRedirectionHandle, error) {
+	return GetDataObjectRedirectionInfoForGetWithThreads(conn, path, resource, fileLength, 0)
+}
+
+// GetDataObjectRedirectionInfoForGetWithThreads returns a redirection info for accessing the data object for downloading
+// threads is the number of threads requested, the server may cap or disable parallel transfer, 0 lets the server decide
+func GetDataObjectRedirectionInfoForGetWithThreads(conn *connection.IRODSConnection, path string, resource string, fileLength int64, threads int) (*types.IRODSFileOpenRedirectionHandle, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -38,6 +45,10 @@ func GetDataObjectRedirectionInfoForGet(conn *connection.IRODSConnection, path s
 	}
 
 	request := message.NewIRODSMessageGetDataObjectRequest(path, resource, fileLength)
+	if threads > 0 {
+		request.Threads = threads
+	}
+
 	response := message.IRODSMessageGetDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
@@ -51,32 +62,19 @@ func GetDataObjectRedirectionInfoForGet(conn *connection.IRODSConnection, path s
 		metrics.IncreaseCounterForOpenFileHandles(1)
 	}
 
-	info := &types.IRODSFileOpenRedirectionHandle{
-		FileDescriptor:  response.FileDescriptor,
-		Path:            path,
-		Resource:        resource,
-		Threads:         response.Threads,
-		CheckSum:        response.CheckSum,
-		RedirectionInfo: nil,
-	}
-
-	if response.PortList != nil {
-		redirection := &types.IRODSRedirectionInfo{
-			Port:         response.PortList.Port,
-			Cookie:       response.PortList.Cookie,
-			ServerSocket: response.PortList.ServerSocket,
-			WindowSize:   response.PortList.WindowSize,
-			Host:         response.PortList.HostAddress,
-		}
-
-		info.RedirectionInfo = redirection
-	}
-
-	return info, nil
+	portalResponse := message.IRODSMessagePortalResponse(response)
+	return getRedirectionHandle(path, resource, &portalResponse), nil
 }
 
 // GetDataObjectRedirectionInfoForPut returns a redirection info for accessing the data object for uploading
+// the server decides the number of threads
 func GetDataObjectRedirectionInfoForPut(conn *connection.IRODSConnection, path string, resource string, fileLength int64) (*types.IRODSFileOpenRedirectionHandle, error) {
+	return GetDataObjectRedirectionInfoForPutWithThreads(conn, path, resource, fileLength, 0)
+}
+
+// GetDataObjectRedirectionInfoForPutWithThreads returns a redirection info for accessing the data object for uploading
+// threads is the number of threads requested, the server may cap or disable parallel transfer, 0 lets the server decide
+func GetDataObjectRedirectionInfoForPutWithThreads(conn *connection.IRODSConnection, path string, resource string, fileLength int64, threads int) (*types.IRODSFileOpenRedirectionHandle, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -97,6 +95,10 @@ func GetDataObjectRedirectionInfoForPut(conn *connection.IRODSConnection, path s
 	}
 
 	request := message.NewIRODSMessagePutDataObjectRequest(path, resource, fileLength)
+	if threads > 0 {
+		request.Threads = threads
+	}
+
 	response := message.IRODSMessagePutDataObjectResponse{}
 	err := conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
@@ -110,7 +112,13 @@ func GetDataObjectRedirectionInfoForPut(conn *connection.IRODSConnection, path s
 		metrics.IncreaseCounterForOpenFileHandles(1)
 	}
 
-	info := &types.IRODSFileOpenRedirectionHandle{
+	portalResponse := message.IRODSMessagePortalResponse(response)
+	return getRedirectionHandle(path, resource, &portalResponse), nil
+}
+
+// getRedirectionHandle makes a redirection handle from the portal operation the server returned
+func getRedirectionHandle(path string, resource string, response *message.IRODSMessagePortalResponse) *types.IRODSFileOpenRedirectionHandle {
+	handle := &types.IRODSFileOpenRedirectionHandle{
 		FileDescriptor:  response.FileDescriptor,
 		Path:            path,
 		Resource:        resource,
@@ -128,10 +136,25 @@ func GetDataObjectRedirectionInfoForPut(conn *connection.IRODSConnection, path s
 			Host:         response.PortList.HostAddress,
 		}
 
-		info.RedirectionInfo = redirection
+		handle.RedirectionInfo = redirection
 	}
 
-	return info, nil
+	return handle
+}
+
+// getRedirectionFallbackTaskNum returns the number of tasks to transfer the data object without the portal, and true if the portal can't be used
+// the portal is used only if the server returned threads and a valid port to connect,
+// otherwise the number of threads the server returned is honored, and a single task is used if the server disabled parallel transfer
+func getRedirectionFallbackTaskNum(handle *types.IRODSFileOpenRedirectionHandle) (int, bool) {
+	if handle.Threads <= 0 {
+		return 1, true
+	}
+
+	if handle.RedirectionInfo == nil || handle.RedirectionInfo.Validate() != nil {
+		return handle.Threads, true
+	}
+
+	return 0, false
 }
 
 // CompleteDataObjectRedirection completes a redirection for accessing the data object for downloading and uploading
@@ -535,7 +558,14 @@ func uploadDataObjectChunkToResourceServer(sess *session.IRODSSession, controlCo
 }
 
 // DownloadDataObjectFromResourceServer downloads a data object at the iRODS path to the local path
+// the server decides the number of threads
 func DownloadDataObjectFromResourceServer(session *session.IRODSSession, irodsPath string, resource string, localPath string, fileLength int64, callback common.TrackerCallBack) error {
+	return DownloadDataObjectFromResourceServerWithThreads(session, irodsPath, resource, localPath, fileLength, 0, callback)
+}
+
+// DownloadDataObjectFromResourceServerWithThreads downloads a data object at the iRODS path to the local path
+// taskNum is the number of threads requested, the number of threads the server returns is used
+func DownloadDataObjectFromResourceServerWithThreads(session *session.IRODSSession, irodsPath string, resource string, localPath string, fileLength int64, taskNum int, callback common.TrackerCallBack) error {
 	logger := log.WithFields(log.Fields{
 		"package":  "fs",
 		"function": "DownloadDataObjectFromResourceServer",
@@ -559,7 +589,7 @@ func DownloadDataObjectFromResourceServer(session *session.IRODSSession, irodsPa
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
-	handle, err := GetDataObjectRedirectionInfoForGet(conn, irodsPath, resource, fileLength)
+	handle, err := GetDataObjectRedirectionInfoForGetWithThreads(conn, irodsPath, resource, fileLength, taskNum)
 	if err != nil {
 		logger.Debugf("failed to get redirection info for data object %s, switch to DownloadDataObjectParallel: %s", irodsPath, err.Error())
 
@@ -572,14 +602,16 @@ func DownloadDataObjectFromResourceServer(session *session.IRODSSession, irodsPa
 
 	defer CompleteDataObjectRedirection(conn, handle)
 
-	if handle.Threads <= 0 || handle.RedirectionInfo == nil {
+	if fallbackTaskNum, fallback := getRedirectionFallbackTaskNum(handle); fallback {
+		logger.Debugf("no portal to resource for data object %s, download with %d threads the server returned", irodsPath, fallbackTaskNum)
+
 		// get file
-		err = DownloadDataObjectParallel(session, irodsPath, resource, localPath, fileLength, 0, callback)
+		err = DownloadDataObjectParallel(session, irodsPath, resource, localPath, fileLength, fallbackTaskNum, callback)
 		if err != nil {
 			return xerrors.Errorf("failed to download data object %s from resource server: %w", irodsPath, err)
 		}
 		return nil
-	} else if handle.RedirectionInfo != nil {
+	} else {
 		logger.Debugf("Redirect to resource: path %s, threads %d, addr %s, port %d, cookie %d", handle.Path, handle.Threads, handle.RedirectionInfo.Host, handle.RedirectionInfo.Port, handle.RedirectionInfo.Cookie)
 		// get from portal
 
@@ -640,12 +672,17 @@ func DownloadDataObjectFromResourceServer(session *session.IRODSSession, irodsPa
 
 		return nil
 	}
-
-	return xerrors.Errorf("unhandled case, thread number is %d", handle.Threads)
 }
 
 // UploadDataObjectToResourceServer uploads a data object at the local path to the iRODS path
+// the server decides the number of threads
 func UploadDataObjectToResourceServer(session *session.IRODSSession, localPath string, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
+	return UploadDataObjectToResourceServerWithThreads(session, localPath, irodsPath, resource, 0, replicate, callback)
+}
+
+// UploadDataObjectToResourceServerWithThreads uploads a data object at the local path to the iRODS path
+// taskNum is the number of threads requested, the number of threads the server returns is used
+func UploadDataObjectToResourceServerWithThreads(session *session.IRODSSession, localPath string, irodsPath string, resource string, taskNum int, replicate bool, callback common.TrackerCallBack) error {
 	logger := log.WithFields(log.Fields{
 		"package":  "fs",
 		"function": "UploadDataObjectToResourceServer",
@@ -676,7 +713,7 @@ func UploadDataObjectToResourceServer(session *session.IRODSSession, localPath s
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
-	handle, err := GetDataObjectRedirectionInfoForPut(conn, irodsPath, resource, fileLength)
+	handle, err := GetDataObjectRedirectionInfoForPutWithThreads(conn, irodsPath, resource, fileLength, taskNum)
 	if err != nil {
 		logger.Debugf("failed to get redirection info for data object %s, switch to UploadDataObjctParallel: %s", irodsPath, err.Error())
 
@@ -689,14 +726,16 @@ func UploadDataObjectToResourceServer(session *session.IRODSSession, localPath s
 
 	defer CompleteDataObjectRedirection(conn, handle)
 
-	if handle.Threads <= 0 || handle.RedirectionInfo == nil {
+	if fallbackTaskNum, fallback := getRedirectionFallbackTaskNum(handle); fallback {
+		logger.Debugf("no portal to resource for data object %s, upload with %d threads the server returned", irodsPath, fallbackTaskNum)
+
 		// put file
-		err = UploadDataObjectParallel(session, localPath, irodsPath, resource, 0, replicate, callback)
+		err = UploadDataObjectParallel(session, localPath, irodsPath, resource, fallbackTaskNum, replicate, callback)
 		if err != nil {
 			return xerrors.Errorf("failed to upload data object %s to resource server: %w", localPath, err)
 		}
 		return nil
-	} else if handle.RedirectionInfo != nil {
+	} else {
 		logger.Debugf("Redirect to resource: path %s, threads %d, addr %s, port %d, cookie %d", handle.Path, handle.Threads, handle.RedirectionInfo.Host, handle.RedirectionInfo.Port, handle.RedirectionInfo.Cookie)
 		// put to portal
 		errChan := make(chan error, handle.Threads)
@@ -749,6 +788,4 @@ func UploadDataObjectToResourceServer(session *session.IRODSSession, localPath s
 
 		return nil
 	}
-
-	return xerrors.Errorf("unhandled case, thread number is %d", handle.Threads)
 }
@@ -0,0 +1,370 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DownloadVerifyOptions is options for verifying a data object downloaded in parallel
+// iRODS does not give checksums of parts of data objects, so the whole file is verified against the checksum of the data object
+// blocks failed to download are retried, blocks of a file failed to verify are compared to the data object and repaired
+type DownloadVerifyOptions struct {
+	// checksum to verify the downloaded file against, the checksum of the data object is retrieved (computed if not registered) if nil
+	Checksum *types.IRODSChecksum
+	// length of blocks downloaded, retried and repaired, util.TransferBlockSize if not positive
+	BlockSize int64
+	// retry blocks failed to download or repair up to the number of times
+	BlockRetries int
+}
+
+// DownloadVerifyResult is a result of a verified parallel download
+type DownloadVerifyResult struct {
+	Blocks int
+	// number of block downloads retried
+	RetriedBlocks int
+	// number of blocks rewritten as they differ from the data object
+	RepairedBlocks int
+	Checksum       *types.IRODSChecksum
+}
+
+// DownloadDataObjectParallelWithVerify downloads a data object at the iRODS path to the local path in parallel, and verifies the downloaded file
+// Partitions a file into blocks downloaded by n (taskNum) tasks, blocks failed to download or found corrupted are downloaded again
+func DownloadDataObjectParallelWithVerify(session *session.IRODSSession, irodsPath string, resource string, localPath string, fileLength int64, taskNum int, verifyOptions *DownloadVerifyOptions, callback common.TrackerCallBack) (*DownloadVerifyResult, error) {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"function": "DownloadDataObjectParallelWithVerify",
+	})
+
+	if verifyOptions == nil {
+		verifyOptions = &DownloadVerifyOptions{}
+	}
+
+	// use default resource when resource param is empty
+	if len(resource) == 0 {
+		account := session.GetAccount()
+		resource = account.DefaultResource
+	}
+
+	blockSize := verifyOptions.BlockSize
+	if blockSize <= 0 {
+		blockSize = util.GetBlockSizeForParallelTransfer(fileLength)
+	}
+
+	blockOffsets := []int64{}
+	for offset := int64(0); offset < fileLength; offset += blockSize {
+		blockOffsets = append(blockOffsets, offset)
+	}
+
+	numTasks := taskNum
+	if numTasks <= 0 {
+		numTasks = util.GetNumTasksForParallelTransferWithBlockSize(fileLength, session.GetConfig().TransferBlockSize)
+	}
+
+	if numTasks > session.GetConfig().ConnectionMax {
+		numTasks = session.GetConfig().ConnectionMax
+	}
+
+	if numTasks > len(blockOffsets) {
+		numTasks = len(blockOffsets)
+	}
+
+	if numTasks < 1 {
+		numTasks = 1
+	}
+
+	logger.Debugf("download data object in parallel with verification %s, size(%d), threads(%d), blocks(%d)", irodsPath, fileLength, numTasks, len(blockOffsets))
+
+	// create an empty file
+	f, err := os.Create(localPath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create file %s: %w", localPath, err)
+	}
+
+	err = f.Truncate(fileLength)
+	f.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to truncate file %s: %w", localPath, err)
+	}
+
+	result := &DownloadVerifyResult{
+		Blocks: len(blockOffsets),
+	}
+
+	totalBytesDownloaded := int64(0)
+	if callback != nil {
+		callback(totalBytesDownloaded, fileLength)
+	}
+
+	blockCallback := func(blockLength int64) {
+		processed := atomic.AddInt64(&totalBytesDownloaded, blockLength)
+		if callback != nil {
+			callback(processed, fileLength)
+		}
+	}
+
+	// download, then retry failed blocks
+	err = downloadDataObjectBlocksWithRetry(session, irodsPath, resource, localPath, fileLength, blockSize, blockOffsets, numTasks, false, verifyOptions.BlockRetries, result, blockCallback)
+	if err != nil {
+		return result, err
+	}
+
+	checksum := verifyOptions.Checksum
+	if checksum == nil {
+		checksum, err = getDataObjectChecksumWithSession(session, irodsPath, resource)
+		if err != nil {
+			return result, err
+		}
+	}
+	result.Checksum = checksum
+
+	verified, err := verifyLocalFileChecksum(localPath, checksum)
+	if err != nil {
+		return result, err
+	}
+
+	if verified {
+		return result, nil
+	}
+
+	logger.Debugf("failed to verify checksum of %s, repairing blocks differ from data object %s", localPath, irodsPath)
+
+	// compare all blocks to the data object, rewrite blocks differ
+	err = downloadDataObjectBlocksWithRetry(session, irodsPath, resource, localPath, fileLength, blockSize, blockOffsets, numTasks, true, verifyOptions.BlockRetries, result, nil)
+	if err != nil {
+		return result, err
+	}
+
+	verified, err = verifyLocalFileChecksum(localPath, checksum)
+	if err != nil {
+		return result, err
+	}
+
+	if !verified {
+		return result, xerrors.Errorf("failed to verify download of %s, checksum of %s (%s) does not match", localPath, irodsPath, checksum.IRODSChecksumString)
+	}
+
+	return result, nil
+}
+
+// downloadDataObjectBlocksWithRetry downloads blocks at the offsets, blocks failed are retried up to retries times
+// blocks are compared to the local file and written only if they differ if repair is true
+func downloadDataObjectBlocksWithRetry(session *session.IRODSSession, irodsPath string, resource string, localPath string, fileLength int64, blockSize int64, blockOffsets []int64, numTasks int, repair bool, retries int, result *DownloadVerifyResult, blockCallback func(blockLength int64)) error {
+	failedOffsets, err := downloadDataObjectBlocks(session, irodsPath, resource, localPath, fileLength, blockSize, blockOffsets, numTasks, repair, result, blockCallback)
+	if err != nil {
+		return err
+	}
+
+	for retry := 0; len(failedOffsets) > 0 && retry < retries; retry++ {
+		result.RetriedBlocks += len(failedOffsets)
+
+		taskNum := numTasks
+		if taskNum > len(failedOffsets) {
+			taskNum = len(failedOffsets)
+		}
+
+		failedOffsets, err = downloadDataObjectBlocks(session, irodsPath, resource, localPath, fileLength, blockSize, failedOffsets, taskNum, repair, result, blockCallback)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failedOffsets) > 0 {
+		return xerrors.Errorf("failed to download %d blocks of data object %s", len(failedOffsets), irodsPath)
+	}
+
+	return nil
+}
+
+// downloadDataObjectBlocks downloads blocks at the offsets in parallel, returns offsets of blocks failed to download
+// errors of the local file are returned as an error as retrying does not help
+func downloadDataObjectBlocks(session *session.IRODSSession, irodsPath string, resource string, localPath string, fileLength int64, blockSize int64, blockOffsets []int64, numTasks int, repair bool, result *DownloadVerifyResult, blockCallback func(blockLength int64)) ([]int64, error) {
+	logger := log.WithFields(log.Fields{
+		"package":  "fs",
+		"function": "downloadDataObjectBlocks",
+	})
+
+	connections, err := session.AcquireConnectionsMulti(numTasks)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get connection: %w", err)
+	}
+
+	blockChan := make(chan int64, len(blockOffsets))
+	for _, offset := range blockOffsets {
+		blockChan <- offset
+	}
+	close(blockChan)
+
+	// the session may share connections if it cannot create as many as tasks
+	errChan := make(chan error, len(connections))
+	taskWaitGroup := sync.WaitGroup{}
+
+	failedOffsets := []int64{}
+	mutex := sync.Mutex{}
+
+	downloadTask := func(taskConn *connection.IRODSConnection) {
+		defer taskWaitGroup.Done()
+		defer session.ReturnConnection(taskConn)
+
+		f, taskErr := os.OpenFile(localPath, os.O_RDWR, 0)
+		if taskErr != nil {
+			errChan <- xerrors.Errorf("failed to open file %s: %w", localPath, taskErr)
+			return
+		}
+		defer f.Close()
+
+		var taskHandle *types.IRODSFileHandle
+		defer func() {
+			if taskHandle != nil {
+				CloseDataObject(taskConn, taskHandle)
+			}
+		}()
+
+		buffer := make([]byte, blockSize)
+		localBuffer := []byte{}
+		if repair {
+			localBuffer = make([]byte, blockSize)
+		}
+
+		for offset := range blockChan {
+			blockLength := blockSize
+			if offset+blockLength > fileLength {
+				blockLength = fileLength - offset
+			}
+
+			if taskHandle == nil {
+				taskHandle, _, taskErr = OpenDataObject(taskConn, irodsPath, resource, "r")
+				if taskErr != nil {
+					logger.Debugf("failed to open data object %s for block at %d: %s", irodsPath, offset, taskErr.Error())
+					taskHandle = nil
+
+					mutex.Lock()
+					failedOffsets = append(failedOffsets, offset)
+					mutex.Unlock()
+					continue
+				}
+			}
+
+			taskErr = readDataObjectBlock(taskConn, taskHandle, offset, buffer[:blockLength])
+			if taskErr != nil {
+				logger.Debugf("failed to read block at %d of data object %s: %s", offset, irodsPath, taskErr.Error())
+
+				// the handle may be in a bad state, reopen for the next block
+				CloseDataObject(taskConn, taskHandle)
+				taskHandle = nil
+
+				mutex.Lock()
+				failedOffsets = append(failedOffsets, offset)
+				mutex.Unlock()
+				continue
+			}
+
+			if repair {
+				_, taskErr = f.ReadAt(localBuffer[:blockLength], offset)
+				if taskErr != nil {
+					errChan <- xerrors.Errorf("failed to read file %s: %w", localPath, taskErr)
+					return
+				}
+
+				if bytes.Equal(localBuffer[:blockLength], buffer[:blockLength]) {
+					continue
+				}
+
+				mutex.Lock()
+				result.RepairedBlocks++
+				mutex.Unlock()
+			}
+
+			_, taskErr = f.WriteAt(buffer[:blockLength], offset)
+			if taskErr != nil {
+				errChan <- xerrors.Errorf("failed to write file %s: %w", localPath, taskErr)
+				return
+			}
+
+			if blockCallback != nil {
+				blockCallback(blockLength)
+			}
+		}
+	}
+
+	for _, conn := range connections {
+		taskWaitGroup.Add(1)
+
+		go downloadTask(conn)
+	}
+
+	taskWaitGroup.Wait()
+
+	if len(errChan) > 0 {
+		return nil, <-errChan
+	}
+
+	return failedOffsets, nil
+}
+
+// readDataObjectBlock reads a block at the offset of a data object into the buffer, fails if the block is short
+func readDataObjectBlock(conn *connection.IRODSConnection, handle *types.IRODSFileHandle, offset int64, buffer []byte) error {
+	newOffset, err := SeekDataObject(conn, handle, offset, types.SeekSet)
+	if err != nil {
+		return err
+	}
+
+	if newOffset != offset {
+		return xerrors.Errorf("failed to seek to target offset %d", offset)
+	}
+
+	read := 0
+	for read < len(buffer) {
+		bufferLen := conn.GetTransferBufferSize()
+		if len(buffer)-read < bufferLen {
+			bufferLen = len(buffer) - read
+		}
+
+		bytesRead, err := ReadDataObject(conn, handle, buffer[read:read+bufferLen])
+		read += bytesRead
+
+		if err != nil {
+			if err == io.EOF {
+				if read < len(buffer) {
+					return xerrors.Errorf("failed to read block at %d, got %d bytes of %d: %w", offset, read, len(buffer), io.ErrUnexpectedEOF)
+				}
+				break
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDataObjectChecksumWithSession returns a data object checksum using a connection of the session
+func getDataObjectChecksumWithSession(session *session.IRODSSession, irodsPath string, resource string) (*types.IRODSChecksum, error) {
+	conn, err := session.AcquireConnection()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get connection: %w", err)
+	}
+	defer session.ReturnConnection(conn)
+
+	return GetDataObjectChecksum(conn, irodsPath, resource)
+}
+
+// verifyLocalFileChecksum returns true if the checksum of the local file matches
+func verifyLocalFileChecksum(localPath string, checksum *types.IRODSChecksum) (bool, error) {
+	localChecksum, err := util.HashLocalFile(localPath, string(checksum.Algorithm))
+	if err != nil {
+		return false, xerrors.Errorf("failed to compute checksum of %s: %w", localPath, err)
+	}
+
+	return bytes.Equal(localChecksum, checksum.Checksum), nil
+}
@@ -0,0 +1,59 @@
+package fs
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// AddMetaAtomic adds AVUs to a collection, a data object, a resource or a user in a single request
+// all AVUs are added or none, supported v4.2.8 or above
+func AddMetaAtomic(conn *connection.IRODSConnection, itemType types.IRODSMetaItemType, name string, metadata []*types.IRODSMeta, adminFlag bool) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	err := validateMeta(metadata...)
+	if err != nil {
+		return err
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForMetadataCreate(uint64(len(metadata)))
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	if !conn.SupportAtomicMetadata() {
+		return xerrors.Errorf("does not support atomic metadata operations in current iRODS Version")
+	}
+
+	request := message.NewIRODSMessageAtomicMetadataRequest(itemType, name)
+	request.AdminMode = adminFlag
+	for _, meta := range metadata {
+		request.AddOperation("add", meta)
+	}
+
+	response := message.IRODSMessageAtomicMetadataResponse{}
+	err = conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		switch types.GetIRODSErrorCode(err) {
+		case common.CAT_NO_ROWS_FOUND, common.CAT_UNKNOWN_FILE, common.CAT_UNKNOWN_COLLECTION:
+			return xerrors.Errorf("failed to find the entry for %s: %w", name, types.NewFileNotFoundError(name))
+		}
+		if metaErr := getMetaTooLargeError(err, metadata...); metaErr != nil {
+			return xerrors.Errorf("failed to add metadata atomically: %w", metaErr)
+		}
+		return xerrors.Errorf("failed to add metadata atomically: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package fs
+
+import (
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// validateMeta returns MetadataTooLargeError if a field of any of the AVUs is too long to store in the catalog
+func validateMeta(metadata ...*types.IRODSMeta) error {
+	for _, meta := range metadata {
+		err := meta.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getMetaTooLargeError returns MetadataTooLargeError for the longest field of the AVUs if the server refused them as too long, otherwise returns nil
+// servers may limit lengths under the defaults, e.g., by the database schema
+func getMetaTooLargeError(err error, metadata ...*types.IRODSMeta) error {
+	switch types.GetIRODSErrorCode(err) {
+	case common.USER_STRLEN_TOOLONG:
+	case common.CAT_SQL_ERR:
+		// databases report values longer than columns
+		if !strings.Contains(strings.ToLower(err.Error()), "too long") {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	var longest *types.IRODSMeta
+	field := ""
+	length := -1
+	for _, meta := range metadata {
+		for _, candidate := range []struct {
+			field string
+			value string
+		}{{"name", meta.Name}, {"value", meta.Value}, {"units", meta.Units}} {
+			if len(candidate.value) > length {
+				longest = meta
+				field = candidate.field
+				length = len(candidate.value)
+			}
+		}
+	}
+
+	if longest == nil {
+		return nil
+	}
+
+	return types.NewMetadataTooLargeError(longest.Name, field, length, 0)
+}
@@ -0,0 +1,153 @@
+package fs
+
+import (
+	"strconv"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// MetaWalkFunc is called with an AVU and the path of the collection or the data object having it
+// the connection is locked while it is called, so it must not make requests on the connection
+type MetaWalkFunc func(path string, meta *types.IRODSMeta) error
+
+// WalkCollectionMetaRecursive calls walkFunc with AVUs of the collection and all collections under it
+// AVUs are passed page by page as the catalog returns them, not held in memory, the walk stops at the first error walkFunc returns
+func WalkCollectionMetaRecursive(conn *connection.IRODSConnection, path string, walkFunc MetaWalkFunc) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForMetadataList(1)
+	}
+
+	selects := []common.ICATColumnNumber{
+		common.ICAT_COLUMN_COLL_NAME,
+		common.ICAT_COLUMN_META_COLL_ATTR_ID,
+		common.ICAT_COLUMN_META_COLL_ATTR_NAME,
+		common.ICAT_COLUMN_META_COLL_ATTR_VALUE,
+		common.ICAT_COLUMN_META_COLL_ATTR_UNITS,
+		common.ICAT_COLUMN_META_COLL_CREATE_TIME,
+		common.ICAT_COLUMN_META_COLL_MODIFY_TIME,
+	}
+
+	for _, condVal := range []string{util.MakeGenQueryEqualCondition(path), getSubTreeCondition(path)} {
+		conditions := map[common.ICATColumnNumber]string{
+			common.ICAT_COLUMN_COLL_NAME: condVal,
+		}
+
+		err := WalkGenQueryWithZone(conn, getZoneHint(conn, path), selects, conditions, func(rows [][]string) error {
+			for _, row := range rows {
+				// "_" in the path is a wildcard in the like condition
+				if row[0] != path && !isInSubTree(path, row[0]) {
+					continue
+				}
+
+				meta, err := getMetaFromRow(row[1:])
+				if err != nil {
+					return xerrors.Errorf("failed to parse collection metadata of %s: %w", row[0], err)
+				}
+
+				err = walkFunc(row[0], meta)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkDataObjectMetaRecursive calls walkFunc with AVUs of all data objects in the collection and collections under it
+// AVUs are passed page by page as the catalog returns them, not held in memory, the walk stops at the first error walkFunc returns
+func WalkDataObjectMetaRecursive(conn *connection.IRODSConnection, path string, walkFunc MetaWalkFunc) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForMetadataList(1)
+	}
+
+	selects := []common.ICATColumnNumber{
+		common.ICAT_COLUMN_COLL_NAME,
+		common.ICAT_COLUMN_DATA_NAME,
+		common.ICAT_COLUMN_META_DATA_ATTR_ID,
+		common.ICAT_COLUMN_META_DATA_ATTR_NAME,
+		common.ICAT_COLUMN_META_DATA_ATTR_VALUE,
+		common.ICAT_COLUMN_META_DATA_ATTR_UNITS,
+		common.ICAT_COLUMN_META_DATA_CREATE_TIME,
+		common.ICAT_COLUMN_META_DATA_MODIFY_TIME,
+	}
+
+	for _, condVal := range []string{util.MakeGenQueryEqualCondition(path), getSubTreeCondition(path)} {
+		conditions := map[common.ICATColumnNumber]string{
+			common.ICAT_COLUMN_COLL_NAME: condVal,
+		}
+
+		err := WalkGenQueryWithZone(conn, getZoneHint(conn, path), selects, conditions, func(rows [][]string) error {
+			for _, row := range rows {
+				// "_" in the path is a wildcard in the like condition
+				if row[0] != path && !isInSubTree(path, row[0]) {
+					continue
+				}
+
+				dataObjectPath := util.MakeIRODSPath(row[0], row[1])
+
+				meta, err := getMetaFromRow(row[2:])
+				if err != nil {
+					return xerrors.Errorf("failed to parse data object metadata of %s: %w", dataObjectPath, err)
+				}
+
+				err = walkFunc(dataObjectPath, meta)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getMetaFromRow makes an AVU from a row of id, name, value, units, create time and modify time
+func getMetaFromRow(row []string) (*types.IRODSMeta, error) {
+	avuID, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse metadata id '%s': %w", row[0], err)
+	}
+
+	createTime, err := util.GetIRODSDateTime(row[4])
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse create time '%s': %w", row[4], err)
+	}
+
+	modifyTime, err := util.GetIRODSDateTime(row[5])
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse modify time '%s': %w", row[5], err)
+	}
+
+	return &types.IRODSMeta{
+		AVUID:      avuID,
+		Name:       row[1],
+		Value:      row[2],
+		Units:      row[3],
+		CreateTime: createTime,
+		ModifyTime: modifyTime,
+	}, nil
+}
@@ -0,0 +1,205 @@
+package fs
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// PathBatchSizeMax is the max number of paths given to a query at once, long "in" conditions are slow in the catalog
+	PathBatchSizeMax int = 100
+)
+
+// splitBatches splits the values into batches of PathBatchSizeMax values at most
+func splitBatches(values []string) [][]string {
+	batches := [][]string{}
+	for start := 0; start < len(values); start += PathBatchSizeMax {
+		end := start + PathBatchSizeMax
+		if end > len(values) {
+			end = len(values)
+		}
+		batches = append(batches, values[start:end])
+	}
+	return batches
+}
+
+// groupPaths groups paths by the key given by keyFunc, keeping the order of first appearance, duplicates are removed
+func groupPaths(paths []string, keyFunc func(p string) string) ([]string, map[string][]string) {
+	keys := []string{}
+	groups := map[string][]string{}
+	seen := map[string]bool{}
+
+	for _, p := range paths {
+		p = util.GetCorrectIRODSPath(p)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		key := keyFunc(p)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	return keys, groups
+}
+
+// ListExistingCollectionPaths returns paths of collections existing among the paths
+// paths are checked in groups of PathBatchSizeMax per zone, not one by one
+func ListExistingCollectionPaths(conn *connection.IRODSConnection, paths []string) ([]string, error) {
+	zones, pathsPerZone := groupPaths(paths, func(p string) string {
+		return getZoneHint(conn, p)
+	})
+
+	existing := []string{}
+	for _, zone := range zones {
+		for _, batch := range splitBatches(pathsPerZone[zone]) {
+			selects := []common.ICATColumnNumber{common.ICAT_COLUMN_COLL_NAME}
+			conditions := map[common.ICATColumnNumber]string{
+				common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryInCondition(batch),
+			}
+
+			rows, err := ExecuteGenQueryWithZone(conn, zone, selects, conditions)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to find collections in zone %s: %w", zone, err)
+			}
+
+			for _, row := range rows {
+				existing = append(existing, row[0])
+			}
+		}
+	}
+
+	return existing, nil
+}
+
+// ListExistingDataObjectPaths returns paths of data objects existing among the paths
+// paths are checked in groups of PathBatchSizeMax per parent collection, not one by one
+func ListExistingDataObjectPaths(conn *connection.IRODSConnection, paths []string) ([]string, error) {
+	parents, pathsPerParent := groupPaths(paths, util.GetIRODSPathDirname)
+
+	existing := []string{}
+	for _, parent := range parents {
+		names := []string{}
+		for _, p := range pathsPerParent[parent] {
+			names = append(names, util.GetIRODSPathFileName(p))
+		}
+
+		for _, batch := range splitBatches(names) {
+			selects := []common.ICATColumnNumber{common.ICAT_COLUMN_DATA_NAME}
+			conditions := map[common.ICATColumnNumber]string{
+				common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryEqualCondition(parent),
+				common.ICAT_COLUMN_DATA_NAME: util.MakeGenQueryInCondition(batch),
+			}
+
+			rows, err := ExecuteGenQueryWithZone(conn, getZoneHint(conn, parent), selects, conditions)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to find data objects in collection %s: %w", parent, err)
+			}
+
+			// rows are returned per replica
+			seen := map[string]bool{}
+			for _, row := range rows {
+				if seen[row[0]] {
+					continue
+				}
+				seen[row[0]] = true
+
+				existing = append(existing, util.MakeIRODSPath(parent, row[0]))
+			}
+		}
+	}
+
+	return existing, nil
+}
+
+// ListAccessesForCollectionPaths returns accesses of collections for the paths
+// paths are queried in groups of PathBatchSizeMax per zone, not one by one
+func ListAccessesForCollectionPaths(conn *connection.IRODSConnection, paths []string) ([]*types.IRODSAccess, error) {
+	zones, pathsPerZone := groupPaths(paths, func(p string) string {
+		return getZoneHint(conn, p)
+	})
+
+	accesses := []*types.IRODSAccess{}
+	for _, zone := range zones {
+		for _, batch := range splitBatches(pathsPerZone[zone]) {
+			selects := []common.ICATColumnNumber{
+				common.ICAT_COLUMN_COLL_NAME,
+				common.ICAT_COLUMN_COLL_ACCESS_NAME,
+				common.ICAT_COLUMN_USER_NAME,
+				common.ICAT_COLUMN_USER_ZONE,
+				common.ICAT_COLUMN_USER_TYPE,
+			}
+			conditions := map[common.ICATColumnNumber]string{
+				common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryInCondition(batch),
+			}
+
+			rows, err := ExecuteGenQueryWithZone(conn, zone, selects, conditions)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to list collection accesses in zone %s: %w", zone, getAccessQueryError(batch[0], err))
+			}
+
+			for _, row := range rows {
+				accesses = append(accesses, &types.IRODSAccess{
+					Path:        row[0],
+					AccessLevel: types.GetIRODSAccessLevelType(row[1]),
+					UserName:    row[2],
+					UserZone:    row[3],
+					UserType:    types.IRODSUserType(row[4]),
+				})
+			}
+		}
+	}
+
+	return accesses, nil
+}
+
+// ListAccessesForDataObjectPaths returns accesses of data objects for the paths
+// paths are queried in groups of PathBatchSizeMax per parent collection, not one by one
+func ListAccessesForDataObjectPaths(conn *connection.IRODSConnection, paths []string) ([]*types.IRODSAccess, error) {
+	parents, pathsPerParent := groupPaths(paths, util.GetIRODSPathDirname)
+
+	accesses := []*types.IRODSAccess{}
+	for _, parent := range parents {
+		names := []string{}
+		for _, p := range pathsPerParent[parent] {
+			names = append(names, util.GetIRODSPathFileName(p))
+		}
+
+		for _, batch := range splitBatches(names) {
+			selects := []common.ICATColumnNumber{
+				common.ICAT_COLUMN_DATA_NAME,
+				common.ICAT_COLUMN_DATA_ACCESS_NAME,
+				common.ICAT_COLUMN_USER_NAME,
+				common.ICAT_COLUMN_USER_ZONE,
+				common.ICAT_COLUMN_USER_TYPE,
+			}
+			conditions := map[common.ICATColumnNumber]string{
+				common.ICAT_COLUMN_COLL_NAME: util.MakeGenQueryEqualCondition(parent),
+				common.ICAT_COLUMN_DATA_NAME: util.MakeGenQueryInCondition(batch),
+			}
+
+			rows, err := ExecuteGenQueryWithZone(conn, getZoneHint(conn, parent), selects, conditions)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to list data object accesses in collection %s: %w", parent, getAccessQueryError(parent, err))
+			}
+
+			for _, row := range rows {
+				accesses = append(accesses, &types.IRODSAccess{
+					Path:        util.MakeIRODSPath(parent, row[0]),
+					AccessLevel: types.GetIRODSAccessLevelType(row[1]),
+					UserName:    row[2],
+					UserZone:    row[3],
+					UserType:    types.IRODSUserType(row[4]),
+				})
+			}
+		}
+	}
+
+	return accesses, nil
+}
@@ -0,0 +1,314 @@
+package fs
+
+import (
+	"sort"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// getZoneHint returns the zone to direct a query for the path to, the zone of the path or the client zone
+// queries for paths in a remote federated zone must be run against the remote zone's catalog
+func getZoneHint(conn *connection.IRODSConnection, p string) string {
+	zone, err := util.GetIRODSZone(p)
+	if err != nil || len(zone) == 0 {
+		return conn.GetAccount().ClientZone
+	}
+	return zone
+}
+
+// getAccessQueryError returns PermissionError if the server refuses an access query, e.g., enforcing strict ACLs
+// otherwise, returns the error as is
+func getAccessQueryError(p string, err error) error {
+	code := types.GetIRODSErrorCode(err)
+	switch code {
+	case common.CAT_NO_ACCESS_PERMISSION, common.CAT_INSUFFICIENT_PRIVILEGE_LEVEL, common.SYS_NO_API_PRIV:
+		return types.NewPermissionError(p, code)
+	default:
+		return err
+	}
+}
+
+// ExecuteGenQuery runs a GenQuery, returns rows of values in the order of selects
+// conditions are keyed by columns, e.g., "= 'alice'" or "like '/zone/home/%'"
+func ExecuteGenQuery(conn *connection.IRODSConnection, selects []common.ICATColumnNumber, conditions map[common.ICATColumnNumber]string) ([][]string, error) {
+	return ExecuteGenQueryWithZone(conn, "", selects, conditions)
+}
+
+// ExecuteGenQueryWithZone runs a GenQuery against the catalog of the zone, e.g., a remote federated zone
+// the zone of the server connected is used if zone is empty
+func ExecuteGenQueryWithZone(conn *connection.IRODSConnection, zone string, selects []common.ICATColumnNumber, conditions map[common.ICATColumnNumber]string) ([][]string, error) {
+	rows := [][]string{}
+
+	err := WalkGenQueryWithZone(conn, zone, selects, conditions, func(pagenatedRows [][]string) error {
+		rows = append(rows, pagenatedRows...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GenQueryWalkFunc is called with rows of a page of a GenQuery result
+// the connection is locked while it is called, so it must not make requests on the connection
+type GenQueryWalkFunc func(rows [][]string) error
+
+// WalkGenQueryWithZone runs a GenQuery as ExecuteGenQueryWithZone does, calling walkFunc with rows page by page instead of returning all rows
+// the query is closed and the error is returned if walkFunc returns an error
+func WalkGenQueryWithZone(conn *connection.IRODSConnection, zone string, selects []common.ICATColumnNumber, conditions map[common.ICATColumnNumber]string, walkFunc GenQueryWalkFunc) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if len(selects) == 0 {
+		return xerrors.Errorf("failed to run a query, no columns are selected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	conditionColumns := []int{}
+	for column := range conditions {
+		conditionColumns = append(conditionColumns, int(column))
+	}
+	sort.Ints(conditionColumns)
+
+	makeQuery := func(maxRows int, continueIndex int) *message.IRODSMessageQueryRequest {
+		query := message.NewIRODSMessageQueryRequest(maxRows, continueIndex, 0, 0)
+		if len(zone) > 0 {
+			query.AddKeyVal(common.ZONE_KW, zone)
+		}
+
+		for _, column := range selects {
+			query.AddSelect(column, 1)
+		}
+
+		for _, column := range conditionColumns {
+			query.AddCondition(common.ICATColumnNumber(column), conditions[common.ICATColumnNumber(column)])
+		}
+		return query
+	}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := makeQuery(common.MaxQueryRows, continueIndex)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return xerrors.Errorf("failed to receive a query result message: %w", err)
+		}
+
+		pagenatedRows, err := getQueryResultRows(&queryResult, selects)
+		if err != nil {
+			return err
+		}
+
+		if len(pagenatedRows) == 0 {
+			break
+		}
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+
+		err = walkFunc(pagenatedRows)
+		if err != nil {
+			if continueIndex != 0 {
+				// close the query, a query with no rows requested releases the statement in the server
+				closeResult := message.IRODSMessageQueryResponse{}
+				conn.Request(makeQuery(0, continueIndex), &closeResult, nil)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExecuteSpecificQuery runs a specific query registered in the catalog by an alias, e.g., "ShowCollAcls"
+// up to 10 args are passed to the query, returns rows of values in the order of the query columns
+func ExecuteSpecificQuery(conn *connection.IRODSConnection, sqlQuery string, args []string) ([][]string, error) {
+	return ExecuteSpecificQueryWithZone(conn, "", sqlQuery, args)
+}
+
+// ExecuteSpecificQueryWithZone runs a specific query against the catalog of the zone, e.g., a remote federated zone
+// the zone of the server connected is used if zone is empty
+func ExecuteSpecificQueryWithZone(conn *connection.IRODSConnection, zone string, sqlQuery string, args []string) ([][]string, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if len(args) > 10 {
+		return nil, xerrors.Errorf("failed to run a specific query, too many args %d", len(args))
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	rows := [][]string{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQuerySpecificRequest(sqlQuery, args, common.MaxQueryRows, continueIndex, 0, 0)
+		if len(zone) > 0 {
+			query.AddKeyVal(common.ZONE_KW, zone)
+		}
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a specific query result message: %w", err)
+		}
+
+		pagenatedRows, err := getQueryResultRows(&queryResult, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pagenatedRows) == 0 {
+			break
+		}
+
+		rows = append(rows, pagenatedRows...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return rows, nil
+}
+
+// AddSpecificQuery registers a specific query in the catalog by an alias, requires a rodsadmin account
+func AddSpecificQuery(conn *connection.IRODSConnection, alias string, sql string) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageAdminRequest("add", "specificQuery", sql, alias)
+
+	err := conn.RequestAndCheck(req, &message.IRODSMessageAdminResponse{}, nil)
+	if err != nil {
+		return xerrors.Errorf("received add specific query error: %w", err)
+	}
+	return nil
+}
+
+// RemoveSpecificQuery removes a specific query registered in the catalog by the alias, requires a rodsadmin account
+func RemoveSpecificQuery(conn *connection.IRODSConnection, alias string) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageAdminRequest("rm", "specificQuery", alias)
+
+	err := conn.RequestAndCheck(req, &message.IRODSMessageAdminResponse{}, nil)
+	if err != nil {
+		return xerrors.Errorf("received remove specific query error: %w", err)
+	}
+	return nil
+}
+
+// ListSpecificQueries lists specific queries registered in the catalog
+func ListSpecificQueries(conn *connection.IRODSConnection) ([]*types.IRODSSpecificQuery, error) {
+	// "ls" is a built-in query listing aliases and sqls
+	rows, err := ExecuteSpecificQuery(conn, "ls", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list specific queries: %w", err)
+	}
+
+	queries := []*types.IRODSSpecificQuery{}
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, xerrors.Errorf("failed to receive specific query attributes - requires 2, but received %d attributes", len(row))
+		}
+
+		queries = append(queries, &types.IRODSSpecificQuery{
+			Alias: row[0],
+			SQL:   row[1],
+		})
+	}
+
+	return queries, nil
+}
+
+// getQueryResultRows converts a query result to rows
+// if selects are given, values are ordered by selects, otherwise by attributes in the result
+func getQueryResultRows(queryResult *message.IRODSMessageQueryResponse, selects []common.ICATColumnNumber) ([][]string, error) {
+	err := queryResult.CheckError()
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+			// empty
+			return [][]string{}, nil
+		}
+		return nil, xerrors.Errorf("received a query error: %w", err)
+	}
+
+	if queryResult.RowCount == 0 {
+		return [][]string{}, nil
+	}
+
+	if queryResult.AttributeCount > len(queryResult.SQLResult) {
+		return nil, xerrors.Errorf("failed to receive query attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+	}
+
+	columnCount := queryResult.AttributeCount
+	if len(selects) > 0 {
+		columnCount = len(selects)
+	}
+
+	rows := make([][]string, queryResult.RowCount)
+	for row := range rows {
+		rows[row] = make([]string, columnCount)
+	}
+
+	for attr := 0; attr < queryResult.AttributeCount; attr++ {
+		sqlResult := queryResult.SQLResult[attr]
+		if len(sqlResult.Values) != queryResult.RowCount {
+			return nil, xerrors.Errorf("failed to receive query rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+		}
+
+		column := attr
+		if len(selects) > 0 {
+			column = -1
+			for idx, selected := range selects {
+				if int(selected) == sqlResult.AttributeIndex {
+					column = idx
+					break
+				}
+			}
+
+			if column < 0 {
+				// not selected
+				continue
+			}
+		}
+
+		for row := 0; row < queryResult.RowCount; row++ {
+			rows[row][column] = sqlResult.Values[row]
+		}
+	}
+
+	return rows, nil
+}
@@ -1,8 +1,8 @@
 package fs
 
 import (
-	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
@@ -33,10 +33,11 @@ func GetResource(conn *connection.IRODSConnection, name string) (*types.IRODSRes
 	query.AddSelect(common.ICAT_COLUMN_R_LOC, 1)
 	query.AddSelect(common.ICAT_COLUMN_R_VAULT_PATH, 1)
 	query.AddSelect(common.ICAT_COLUMN_R_RESC_CONTEXT, 1)
+	query.AddSelect(common.ICAT_COLUMN_R_RESC_STATUS, 1)
 	query.AddSelect(common.ICAT_COLUMN_R_CREATE_TIME, 1)
 	query.AddSelect(common.ICAT_COLUMN_R_MODIFY_TIME, 1)
 
-	rescCondVal := fmt.Sprintf("= '%s'", name)
+	rescCondVal := util.MakeGenQueryEqualCondition(name)
 	query.AddCondition(common.ICAT_COLUMN_R_RESC_NAME, rescCondVal)
 
 	queryResult := message.IRODSMessageQueryResponse{}
@@ -94,6 +95,8 @@ func GetResource(conn *connection.IRODSConnection, name string) (*types.IRODSRes
 			resource.Path = value
 		case int(common.ICAT_COLUMN_R_RESC_CONTEXT):
 			resource.Context = value
+		case int(common.ICAT_COLUMN_R_RESC_STATUS):
+			resource.Status = value
 		case int(common.ICAT_COLUMN_R_CREATE_TIME):
 			cT, err := util.GetIRODSDateTime(value)
 			if err != nil {
@@ -114,6 +117,110 @@ func GetResource(conn *connection.IRODSConnection, name string) (*types.IRODSRes
 	return resource, nil
 }
 
+// GetResourceSpace returns free space and status of a resource
+func GetResourceSpace(conn *connection.IRODSConnection, name string) (*types.IRODSResourceSpace, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	// query with AUTO_CLOSE option
+	query := message.NewIRODSMessageQueryRequest(1, 0, 0, 0x100)
+	query.AddSelect(common.ICAT_COLUMN_R_RESC_NAME, 1)
+	query.AddSelect(common.ICAT_COLUMN_R_FREE_SPACE, 1)
+	query.AddSelect(common.ICAT_COLUMN_R_FREE_SPACE_TIME, 1)
+	query.AddSelect(common.ICAT_COLUMN_R_RESC_STATUS, 1)
+	query.AddSelect(common.ICAT_COLUMN_R_RESC_CONTEXT, 1)
+
+	rescCondVal := util.MakeGenQueryEqualCondition(name)
+	query.AddCondition(common.ICAT_COLUMN_R_RESC_NAME, rescCondVal)
+
+	queryResult := message.IRODSMessageQueryResponse{}
+	err := conn.Request(query, &queryResult, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to receive a resource query result message: %w", err)
+	}
+
+	err = queryResult.CheckError()
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+			return nil, xerrors.Errorf("failed to find the resource %s: %w", name, types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST))
+		}
+		return nil, xerrors.Errorf("received a resource query error: %w", err)
+	}
+
+	if queryResult.RowCount == 0 {
+		return nil, xerrors.Errorf("failed to find the resource %s: %w", name, types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST))
+	}
+
+	if queryResult.AttributeCount > len(queryResult.SQLResult) {
+		return nil, xerrors.Errorf("failed to receive resource attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+	}
+
+	space := &types.IRODSResourceSpace{
+		FreeSpace: -1,
+		Capacity:  -1,
+	}
+
+	for attr := 0; attr < queryResult.AttributeCount; attr++ {
+		sqlResult := queryResult.SQLResult[attr]
+		if len(sqlResult.Values) != queryResult.RowCount {
+			return nil, xerrors.Errorf("failed to receive resource rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+		}
+
+		value := sqlResult.Values[0]
+
+		switch sqlResult.AttributeIndex {
+		case int(common.ICAT_COLUMN_R_RESC_NAME):
+			space.Name = value
+		case int(common.ICAT_COLUMN_R_FREE_SPACE):
+			if len(value) > 0 {
+				freeSpace, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, xerrors.Errorf("failed to parse free space '%s': %w", value, err)
+				}
+				space.FreeSpace = freeSpace
+			}
+		case int(common.ICAT_COLUMN_R_FREE_SPACE_TIME):
+			if len(value) > 0 {
+				fT, err := util.GetIRODSDateTime(value)
+				if err != nil {
+					return nil, xerrors.Errorf("failed to parse free space time '%s': %w", value, err)
+				}
+				space.FreeSpaceTime = fT
+			}
+		case int(common.ICAT_COLUMN_R_RESC_STATUS):
+			space.Status = value
+		case int(common.ICAT_COLUMN_R_RESC_CONTEXT):
+			capacity, err := getResourceCapacity(value)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse capacity in resource context '%s': %w", value, err)
+			}
+			space.Capacity = capacity
+		default:
+			// ignore
+		}
+	}
+
+	return space, nil
+}
+
+// getResourceCapacity returns capacity set in resource context, e.g., "capacity=1099511627776", -1 if not set
+func getResourceCapacity(context string) (int64, error) {
+	for _, kv := range strings.Split(context, ";") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(key) != "capacity" {
+			continue
+		}
+
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	}
+	return -1, nil
+}
+
 // AddResourceMeta sets metadata of a resource to the given key values.
 // metadata.AVUID is ignored
 func AddResourceMeta(conn *connection.IRODSConnection, name string, metadata *types.IRODSMeta) error {
@@ -121,14 +228,22 @@ func AddResourceMeta(conn *connection.IRODSConnection, name string, metadata *ty
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	err := validateMeta(metadata)
+	if err != nil {
+		return err
+	}
+
 	// lock the connection
 	conn.Lock()
 	defer conn.Unlock()
 
 	request := message.NewIRODSMessageAddMetadataRequest(types.IRODSResourceMetaItemType, name, metadata)
 	response := message.IRODSMessageModifyMetadataResponse{}
-	err := conn.RequestAndCheck(request, &response, nil)
+	err = conn.RequestAndCheck(request, &response, nil)
 	if err != nil {
+		if metaErr := getMetaTooLargeError(err, metadata); metaErr != nil {
+			return xerrors.Errorf("received an add data resource meta error: %w", metaErr)
+		}
 		return xerrors.Errorf("received an add data resource meta error: %w", err)
 	}
 	return nil
@@ -186,7 +301,7 @@ func ListResourceMeta(conn *connection.IRODSConnection, name string) ([]*types.I
 		query.AddSelect(common.ICAT_COLUMN_META_RESC_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_RESC_MODIFY_TIME, 1)
 
-		nameCondVal := fmt.Sprintf("= '%s'", name)
+		nameCondVal := util.MakeGenQueryEqualCondition(name)
 		query.AddCondition(common.ICAT_COLUMN_R_RESC_NAME, nameCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -276,3 +391,142 @@ func ListResourceMeta(conn *connection.IRODSConnection, name string) ([]*types.I
 
 	return metas, nil
 }
+
+// SearchResourcesByMeta searches resources by metadata
+func SearchResourcesByMeta(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSResource, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	return searchResourcesByMeta(conn, metaName, metaValueCondVal)
+}
+
+// SearchResourcesByMetaWildcard searches resources by metadata
+// metaValue is in SQL LIKE syntax, e.g., "arch%"
+func SearchResourcesByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSResource, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	return searchResourcesByMeta(conn, metaName, metaValueCondVal)
+}
+
+func searchResourcesByMeta(conn *connection.IRODSConnection, metaName string, metaValueCondVal string) ([]*types.IRODSResource, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForSearch(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	resources := []*types.IRODSResource{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_R_RESC_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_RESC_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_ZONE_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_TYPE_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_CLASS_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_LOC, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_VAULT_PATH, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_RESC_CONTEXT, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_CREATE_TIME, 1)
+		query.AddSelect(common.ICAT_COLUMN_R_MODIFY_TIME, 1)
+
+		metaNameCondVal := util.MakeGenQueryEqualCondition(metaName)
+		query.AddCondition(common.ICAT_COLUMN_META_RESC_ATTR_NAME, metaNameCondVal)
+		query.AddCondition(common.ICAT_COLUMN_META_RESC_ATTR_VALUE, metaValueCondVal)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a resource query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a resource query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive resource attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedResources := make([]*types.IRODSResource, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive resource rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedResources[row] == nil {
+					// create a new
+					pagenatedResources[row] = &types.IRODSResource{
+						RescID: -1,
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_R_RESC_ID):
+					objID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse resource id '%s': %w", value, err)
+					}
+					pagenatedResources[row].RescID = objID
+				case int(common.ICAT_COLUMN_R_RESC_NAME):
+					pagenatedResources[row].Name = value
+				case int(common.ICAT_COLUMN_R_ZONE_NAME):
+					pagenatedResources[row].Zone = value
+				case int(common.ICAT_COLUMN_R_TYPE_NAME):
+					pagenatedResources[row].Type = value
+				case int(common.ICAT_COLUMN_R_CLASS_NAME):
+					pagenatedResources[row].Class = value
+				case int(common.ICAT_COLUMN_R_LOC):
+					pagenatedResources[row].Location = value
+				case int(common.ICAT_COLUMN_R_VAULT_PATH):
+					pagenatedResources[row].Path = value
+				case int(common.ICAT_COLUMN_R_RESC_CONTEXT):
+					pagenatedResources[row].Context = value
+				case int(common.ICAT_COLUMN_R_CREATE_TIME):
+					cT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
+					}
+					pagenatedResources[row].CreateTime = cT
+				case int(common.ICAT_COLUMN_R_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedResources[row].ModifyTime = mT
+				default:
+					// ignore
+				}
+			}
+		}
+
+		resources = append(resources, pagenatedResources...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return resources, nil
+}
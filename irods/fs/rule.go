@@ -0,0 +1,62 @@
+package fs
+
+import (
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// RuleExecOutLabel is the label of the output param having stdout and stderr of a rule execution
+	RuleExecOutLabel string = "ruleExecOut"
+)
+
+// ExecuteRule executes a rule with input params, and returns output params of the given labels
+// inputs are keyed by labels, e.g., "*name", values are string, int, map[string]string, or []byte
+// ruleExecOut is returned if no output labels are given
+// instanceName selects a rule engine plugin instance, the server decides if empty
+// only XML protocol is supported
+func ExecuteRule(conn *connection.IRODSConnection, rule string, inputs types.IRODSRuleParams, outputs []string, instanceName string) (types.IRODSRuleParams, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if conn.IsNativeProtocol() {
+		return nil, xerrors.Errorf("failed to execute a rule, native protocol is not supported")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	outParamDesc := RuleExecOutLabel
+	if len(outputs) > 0 {
+		outParamDesc = strings.Join(outputs, "%")
+	}
+
+	request, err := message.NewIRODSMessageExecMyRuleRequest(rule, inputs, outParamDesc)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make a rule execution request: %w", err)
+	}
+
+	if len(instanceName) > 0 {
+		request.AddKeyVal(common.INSTANCE_NAME_KW, instanceName)
+	}
+
+	response := message.IRODSMessageExecMyRuleResponse{}
+	err = conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to execute a rule: %w", err)
+	}
+
+	outParams, err := response.GetParams()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get rule output params: %w", err)
+	}
+
+	return outParams, nil
+}
@@ -104,3 +104,28 @@ func StatProcess(conn *connection.IRODSConnection, address string, zone string)
 	processes = append(processes, pagenatedProcesses...)
 	return processes, nil
 }
+
+// GetClientHints returns hints the server gives clients about its configuration, e.g., whether strict ACLs are enforced
+func GetClientHints(conn *connection.IRODSConnection) (*types.IRODSClientHints, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageClientHintsRequest()
+	response := message.IRODSMessageClientHintsResponse{}
+	err := conn.Request(req, &response, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to receive a client hints result message: %w", err)
+	}
+
+	err = response.CheckError()
+	if err != nil {
+		return nil, xerrors.Errorf("received a client hints error: %w", err)
+	}
+
+	return &response.ClientHints, nil
+}
@@ -33,7 +33,7 @@ func GetTicketForAnonymousAccess(conn *connection.IRODSConnection, ticketName st
 	query.AddSelect(common.ICAT_COLUMN_TICKET_EXPIRY_TS, 1)
 	// We can't get common.ICAT_COLUMN_TICKET_STRING using query since it's not available for anonymous access
 
-	condVal := fmt.Sprintf("= '%s'", ticketName)
+	condVal := util.MakeGenQueryEqualCondition(ticketName)
 	query.AddCondition(common.ICAT_COLUMN_TICKET_STRING, condVal)
 
 	queryResult := message.IRODSMessageQueryResponse{}
@@ -167,7 +167,7 @@ func GetTicketForDataObjects(conn *connection.IRODSConnection, ticketName string
 	query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_NAME, 1)
 	query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_ZONE, 1)
 
-	condVal := fmt.Sprintf("= '%s'", ticketName)
+	condVal := util.MakeGenQueryEqualCondition(ticketName)
 	query.AddCondition(common.ICAT_COLUMN_TICKET_STRING, condVal)
 
 	queryResult := message.IRODSMessageQueryResponse{}
@@ -335,7 +335,7 @@ func GetTicketForCollections(conn *connection.IRODSConnection, ticketName string
 	query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_NAME, 1)
 	query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_ZONE, 1)
 
-	condVal := fmt.Sprintf("= '%s'", ticketName)
+	condVal := util.MakeGenQueryEqualCondition(ticketName)
 	query.AddCondition(common.ICAT_COLUMN_TICKET_STRING, condVal)
 
 	queryResult := message.IRODSMessageQueryResponse{}
@@ -497,8 +497,38 @@ func ListTickets(conn *connection.IRODSConnection) ([]*types.IRODSTicket, error)
 	return tickets, nil
 }
 
+// ListTicketsForPath returns tickets for the path, a data object or a collection
+func ListTicketsForPath(conn *connection.IRODSConnection, path string) ([]*types.IRODSTicket, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	tickets := []*types.IRODSTicket{}
+
+	ticketsColl, err := listTicketsForCollections(conn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets = append(tickets, ticketsColl...)
+
+	ticketsDataObj, err := listTicketsForDataObjects(conn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets = append(tickets, ticketsDataObj...)
+
+	return tickets, nil
+}
+
 // ListTicketsForDataObjects returns tickets for data objects
 func ListTicketsForDataObjects(conn *connection.IRODSConnection) ([]*types.IRODSTicket, error) {
+	return listTicketsForDataObjects(conn, "")
+}
+
+// listTicketsForDataObjects returns tickets for the data object at the path, for all data objects if path is empty
+func listTicketsForDataObjects(conn *connection.IRODSConnection, path string) ([]*types.IRODSTicket, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -529,6 +559,13 @@ func ListTicketsForDataObjects(conn *connection.IRODSConnection) ([]*types.IRODS
 		query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_ZONE, 1)
 
+		if len(path) > 0 {
+			collCondVal := util.MakeGenQueryEqualCondition(util.GetIRODSPathDirname(path))
+			query.AddCondition(common.ICAT_COLUMN_TICKET_DATA_COLL_NAME, collCondVal)
+			nameCondVal := util.MakeGenQueryEqualCondition(util.GetIRODSPathFileName(path))
+			query.AddCondition(common.ICAT_COLUMN_TICKET_DATA_NAME, nameCondVal)
+		}
+
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
@@ -680,6 +717,11 @@ func ListTicketsForDataObjects(conn *connection.IRODSConnection) ([]*types.IRODS
 
 // ListTicketsForCollections returns tickets for collections
 func ListTicketsForCollections(conn *connection.IRODSConnection) ([]*types.IRODSTicket, error) {
+	return listTicketsForCollections(conn, "")
+}
+
+// listTicketsForCollections returns tickets for the collection at the path, for all collections if path is empty
+func listTicketsForCollections(conn *connection.IRODSConnection, path string) ([]*types.IRODSTicket, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -709,6 +751,11 @@ func ListTicketsForCollections(conn *connection.IRODSConnection) ([]*types.IRODS
 		query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_TICKET_OWNER_ZONE, 1)
 
+		if len(path) > 0 {
+			condVal := util.MakeGenQueryEqualCondition(path)
+			query.AddCondition(common.ICAT_COLUMN_TICKET_COLL_NAME, condVal)
+		}
+
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
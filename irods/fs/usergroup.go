@@ -13,6 +13,138 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// GetUser returns the user, zone is optional, client zone is used if empty
+func GetUser(conn *connection.IRODSConnection, username string, zone string) (*types.IRODSUser, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if len(zone) == 0 {
+		zone = conn.GetAccount().ClientZone
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	users := []*types.IRODSUser{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_USER_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_INFO, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_COMMENT, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_CREATE_TIME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_MODIFY_TIME, 1)
+
+		condNameVal := util.MakeGenQueryEqualCondition(username)
+		query.AddCondition(common.ICAT_COLUMN_USER_NAME, condNameVal)
+		condZoneVal := util.MakeGenQueryEqualCondition(zone)
+		query.AddCondition(common.ICAT_COLUMN_USER_ZONE, condZoneVal)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a user query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a user query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive user attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedUsers := make([]*types.IRODSUser, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive user rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedUsers[row] == nil {
+					// create a new
+					pagenatedUsers[row] = &types.IRODSUser{
+						ID:         -1,
+						Zone:       "",
+						Name:       "",
+						Type:       types.IRODSUserRodsUser,
+						Info:       "",
+						Comment:    "",
+						CreateTime: time.Time{},
+						ModifyTime: time.Time{},
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_USER_ID):
+					userID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
+					}
+					pagenatedUsers[row].ID = userID
+				case int(common.ICAT_COLUMN_USER_ZONE):
+					pagenatedUsers[row].Zone = value
+				case int(common.ICAT_COLUMN_USER_NAME):
+					pagenatedUsers[row].Name = value
+				case int(common.ICAT_COLUMN_USER_TYPE):
+					pagenatedUsers[row].Type = types.IRODSUserType(value)
+				case int(common.ICAT_COLUMN_USER_INFO):
+					pagenatedUsers[row].Info = value
+				case int(common.ICAT_COLUMN_USER_COMMENT):
+					pagenatedUsers[row].Comment = value
+				case int(common.ICAT_COLUMN_USER_CREATE_TIME):
+					cT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
+					}
+					pagenatedUsers[row].CreateTime = cT
+				case int(common.ICAT_COLUMN_USER_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedUsers[row].ModifyTime = mT
+				default:
+					// ignore
+				}
+			}
+		}
+
+		users = append(users, pagenatedUsers...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	if len(users) == 0 {
+		return nil, xerrors.Errorf("failed to find the user for name %s: %w", username, types.NewUserNotFoundError(username))
+	}
+
+	return users[0], nil
+}
+
 // GetGroup returns the group
 func GetGroup(conn *connection.IRODSConnection, group string) (*types.IRODSUser, error) {
 	if conn == nil || !conn.IsConnected() {
@@ -33,10 +165,14 @@ func GetGroup(conn *connection.IRODSConnection, group string) (*types.IRODSUser,
 		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_INFO, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_COMMENT, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_CREATE_TIME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_MODIFY_TIME, 1)
 
-		condNameVal := fmt.Sprintf("= '%s'", group)
+		condNameVal := util.MakeGenQueryEqualCondition(group)
 		query.AddCondition(common.ICAT_COLUMN_USER_NAME, condNameVal)
-		condTypeVal := fmt.Sprintf("= '%s'", types.IRODSUserRodsGroup)
+		condTypeVal := util.MakeGenQueryEqualCondition(string(types.IRODSUserRodsGroup))
 		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -47,6 +183,10 @@ func GetGroup(conn *connection.IRODSConnection, group string) (*types.IRODSUser,
 
 		err = queryResult.CheckError()
 		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
 			return nil, xerrors.Errorf("received a group query error: %w", err)
 		}
 
@@ -92,6 +232,22 @@ func GetGroup(conn *connection.IRODSConnection, group string) (*types.IRODSUser,
 					pagenatedUsers[row].Name = value
 				case int(common.ICAT_COLUMN_USER_TYPE):
 					pagenatedUsers[row].Type = types.IRODSUserType(value)
+				case int(common.ICAT_COLUMN_USER_INFO):
+					pagenatedUsers[row].Info = value
+				case int(common.ICAT_COLUMN_USER_COMMENT):
+					pagenatedUsers[row].Comment = value
+				case int(common.ICAT_COLUMN_USER_CREATE_TIME):
+					cT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
+					}
+					pagenatedUsers[row].CreateTime = cT
+				case int(common.ICAT_COLUMN_USER_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedUsers[row].ModifyTime = mT
 				default:
 					// ignore
 				}
@@ -134,7 +290,7 @@ func ListGroupUsers(conn *connection.IRODSConnection, group string) ([]*types.IR
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 
-		condNameVal := fmt.Sprintf("= '%s'", group)
+		condNameVal := util.MakeGenQueryEqualCondition(group)
 		query.AddCondition(common.ICAT_COLUMN_COLL_USER_GROUP_NAME, condNameVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -173,7 +329,206 @@ func ListGroupUsers(conn *connection.IRODSConnection, group string) ([]*types.IR
 
 				if pagenatedUsers[row] == nil {
 					// create a new
-					pagenatedUsers[row] = &types.IRODSUser{
+					pagenatedUsers[row] = &types.IRODSUser{
+						ID:   -1,
+						Zone: "",
+						Name: "",
+						Type: types.IRODSUserRodsUser,
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_USER_ID):
+					userID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
+					}
+					pagenatedUsers[row].ID = userID
+				case int(common.ICAT_COLUMN_USER_ZONE):
+					pagenatedUsers[row].Zone = value
+				case int(common.ICAT_COLUMN_USER_NAME):
+					pagenatedUsers[row].Name = value
+				case int(common.ICAT_COLUMN_USER_TYPE):
+					pagenatedUsers[row].Type = types.IRODSUserType(value)
+				default:
+					// ignore
+				}
+			}
+		}
+
+		users = append(users, pagenatedUsers...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return users, nil
+}
+
+// ListGroups returns all groups
+func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	groups := []*types.IRODSUser{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_USER_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
+
+		condTypeVal := util.MakeGenQueryEqualCondition(string(types.IRODSUserRodsGroup))
+		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, condTypeVal)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a group query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a group query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive group attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedGroups := make([]*types.IRODSUser, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive group rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedGroups[row] == nil {
+					// create a new
+					pagenatedGroups[row] = &types.IRODSUser{
+						ID:   -1,
+						Zone: "",
+						Name: "",
+						Type: types.IRODSUserRodsUser,
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_USER_ID):
+					userID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
+					}
+					pagenatedGroups[row].ID = userID
+				case int(common.ICAT_COLUMN_USER_ZONE):
+					pagenatedGroups[row].Zone = value
+				case int(common.ICAT_COLUMN_USER_NAME):
+					pagenatedGroups[row].Name = value
+				case int(common.ICAT_COLUMN_USER_TYPE):
+					pagenatedGroups[row].Type = types.IRODSUserType(value)
+				default:
+					// ignore
+				}
+			}
+		}
+
+		groups = append(groups, pagenatedGroups...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return groups, nil
+}
+
+// ListGroupsByNameWildcard returns groups whose names match the pattern
+// the pattern is in SQL LIKE syntax, e.g., "lab%" or "lab_1"
+func ListGroupsByNameWildcard(conn *connection.IRODSConnection, namePattern string) ([]*types.IRODSUser, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	groups := []*types.IRODSUser{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_USER_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
+
+		condNameVal := util.MakeGenQueryLikeCondition(namePattern)
+		query.AddCondition(common.ICAT_COLUMN_USER_NAME, condNameVal)
+		condTypeVal := util.MakeGenQueryEqualCondition(string(types.IRODSUserRodsGroup))
+		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, condTypeVal)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a group query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a group query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive group attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedGroups := make([]*types.IRODSUser, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive group rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedGroups[row] == nil {
+					// create a new
+					pagenatedGroups[row] = &types.IRODSUser{
 						ID:   -1,
 						Zone: "",
 						Name: "",
@@ -187,20 +542,20 @@ func ListGroupUsers(conn *connection.IRODSConnection, group string) ([]*types.IR
 					if err != nil {
 						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
 					}
-					pagenatedUsers[row].ID = userID
+					pagenatedGroups[row].ID = userID
 				case int(common.ICAT_COLUMN_USER_ZONE):
-					pagenatedUsers[row].Zone = value
+					pagenatedGroups[row].Zone = value
 				case int(common.ICAT_COLUMN_USER_NAME):
-					pagenatedUsers[row].Name = value
+					pagenatedGroups[row].Name = value
 				case int(common.ICAT_COLUMN_USER_TYPE):
-					pagenatedUsers[row].Type = types.IRODSUserType(value)
+					pagenatedGroups[row].Type = types.IRODSUserType(value)
 				default:
 					// ignore
 				}
 			}
 		}
 
-		users = append(users, pagenatedUsers...)
+		groups = append(groups, pagenatedGroups...)
 
 		continueIndex = queryResult.ContinueIndex
 		if continueIndex == 0 {
@@ -208,11 +563,11 @@ func ListGroupUsers(conn *connection.IRODSConnection, group string) ([]*types.IR
 		}
 	}
 
-	return users, nil
+	return groups, nil
 }
 
-// ListGroups returns all groups
-func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
+// ListUsers lists all users
+func ListUsers(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -221,7 +576,7 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 	conn.Lock()
 	defer conn.Unlock()
 
-	groups := []*types.IRODSUser{}
+	users := []*types.IRODSUser{}
 
 	continueQuery := true
 	continueIndex := 0
@@ -232,13 +587,13 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 
-		condTypeVal := fmt.Sprintf("= '%s'", types.IRODSUserRodsGroup)
+		condTypeVal := util.MakeGenQueryCondition("<>", string(types.IRODSUserRodsGroup))
 		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
 		err := conn.Request(query, &queryResult, nil)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to receive a group query result message: %w", err)
+			return nil, xerrors.Errorf("failed to receive a user query result message: %w", err)
 		}
 
 		err = queryResult.CheckError()
@@ -247,7 +602,7 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 				// empty
 				break
 			}
-			return nil, xerrors.Errorf("received a group query error: %w", err)
+			return nil, xerrors.Errorf("received a user query error: %w", err)
 		}
 
 		if queryResult.RowCount == 0 {
@@ -255,23 +610,23 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 		}
 
 		if queryResult.AttributeCount > len(queryResult.SQLResult) {
-			return nil, xerrors.Errorf("failed to receive group attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+			return nil, xerrors.Errorf("failed to receive user attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
 		}
 
-		pagenatedGroups := make([]*types.IRODSUser, queryResult.RowCount)
+		pagenatedUsers := make([]*types.IRODSUser, queryResult.RowCount)
 
 		for attr := 0; attr < queryResult.AttributeCount; attr++ {
 			sqlResult := queryResult.SQLResult[attr]
 			if len(sqlResult.Values) != queryResult.RowCount {
-				return nil, xerrors.Errorf("failed to receive group rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+				return nil, xerrors.Errorf("failed to receive user rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
 			}
 
 			for row := 0; row < queryResult.RowCount; row++ {
 				value := sqlResult.Values[row]
 
-				if pagenatedGroups[row] == nil {
+				if pagenatedUsers[row] == nil {
 					// create a new
-					pagenatedGroups[row] = &types.IRODSUser{
+					pagenatedUsers[row] = &types.IRODSUser{
 						ID:   -1,
 						Zone: "",
 						Name: "",
@@ -285,20 +640,20 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 					if err != nil {
 						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
 					}
-					pagenatedGroups[row].ID = userID
+					pagenatedUsers[row].ID = userID
 				case int(common.ICAT_COLUMN_USER_ZONE):
-					pagenatedGroups[row].Zone = value
+					pagenatedUsers[row].Zone = value
 				case int(common.ICAT_COLUMN_USER_NAME):
-					pagenatedGroups[row].Name = value
+					pagenatedUsers[row].Name = value
 				case int(common.ICAT_COLUMN_USER_TYPE):
-					pagenatedGroups[row].Type = types.IRODSUserType(value)
+					pagenatedUsers[row].Type = types.IRODSUserType(value)
 				default:
 					// ignore
 				}
 			}
 		}
 
-		groups = append(groups, pagenatedGroups...)
+		users = append(users, pagenatedUsers...)
 
 		continueIndex = queryResult.ContinueIndex
 		if continueIndex == 0 {
@@ -306,11 +661,12 @@ func ListGroups(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 		}
 	}
 
-	return groups, nil
+	return users, nil
 }
 
-// ListUsers lists all users
-func ListUsers(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
+// ListUsersByNameWildcard returns users whose names match the pattern
+// the pattern is in SQL LIKE syntax, e.g., "ali%" or "_lice"
+func ListUsersByNameWildcard(conn *connection.IRODSConnection, namePattern string) ([]*types.IRODSUser, error) {
 	if conn == nil || !conn.IsConnected() {
 		return nil, xerrors.Errorf("connection is nil or disconnected")
 	}
@@ -330,7 +686,9 @@ func ListUsers(conn *connection.IRODSConnection) ([]*types.IRODSUser, error) {
 		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
 		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
 
-		condTypeVal := fmt.Sprintf("<> '%s'", types.IRODSUserRodsGroup)
+		condNameVal := util.MakeGenQueryLikeCondition(namePattern)
+		query.AddCondition(common.ICAT_COLUMN_USER_NAME, condNameVal)
+		condTypeVal := util.MakeGenQueryCondition("<>", string(types.IRODSUserRodsGroup))
 		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -425,7 +783,7 @@ func ListUserGroupNames(conn *connection.IRODSConnection, user string) ([]string
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
 		query.AddSelect(common.ICAT_COLUMN_COLL_USER_GROUP_NAME, 1)
 
-		condTypeVal := fmt.Sprintf("= '%s'", user)
+		condTypeVal := util.MakeGenQueryEqualCondition(user)
 		query.AddCondition(common.ICAT_COLUMN_USER_NAME, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -540,6 +898,23 @@ func ChangeUserType(conn *connection.IRODSConnection, username string, zone stri
 	return nil
 }
 
+// ChangeUserInfo changes the info of a user object
+func ChangeUserInfo(conn *connection.IRODSConnection, username string, zone string, info string) error {
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	userZoneName := fmt.Sprintf("%s#%s", username, zone)
+
+	req := message.NewIRODSMessageAdminRequest("modify", "user", userZoneName, "info", info, zone)
+
+	err := conn.RequestAndCheck(req, &message.IRODSMessageAdminResponse{}, nil)
+	if err != nil {
+		return xerrors.Errorf("received change user info error: %w", err)
+	}
+	return nil
+}
+
 // RemoveUser removes a user or a group.
 func RemoveUser(conn *connection.IRODSConnection, username string, zone string) error {
 	// lock the connection
@@ -619,7 +994,7 @@ func ListUserResourceQuota(conn *connection.IRODSConnection, user string) ([]*ty
 		query.AddSelect(common.ICAT_COLUMN_QUOTA_RESC_NAME, 1)
 		query.AddSelect(common.ICAT_COLUMN_QUOTA_LIMIT, 1)
 
-		condTypeVal := fmt.Sprintf("= '%s'", user)
+		condTypeVal := util.MakeGenQueryEqualCondition(user)
 		query.AddCondition(common.ICAT_COLUMN_QUOTA_USER_NAME, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -708,9 +1083,9 @@ func GetUserGlobalQuota(conn *connection.IRODSConnection, user string) (*types.I
 		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
 		query.AddSelect(common.ICAT_COLUMN_QUOTA_LIMIT, 1)
 
-		condTypeVal := fmt.Sprintf("= '%s'", user)
+		condTypeVal := util.MakeGenQueryEqualCondition(user)
 		query.AddCondition(common.ICAT_COLUMN_QUOTA_USER_NAME, condTypeVal)
-		condTypeVal = fmt.Sprintf("= '%s'", "0")
+		condTypeVal = util.MakeGenQueryEqualCondition("0")
 		query.AddCondition(common.ICAT_COLUMN_QUOTA_RESC_ID, condTypeVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -775,19 +1150,139 @@ func GetUserGlobalQuota(conn *connection.IRODSConnection, user string) (*types.I
 	return quota[0], nil
 }
 
+// ListUserQuotaUsage lists usages of a user or group on resources counted for quota, usages of all users and groups if user is empty
+func ListUserQuotaUsage(conn *connection.IRODSConnection, user string) ([]*types.IRODSQuotaUsage, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	usages := []*types.IRODSQuotaUsage{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_QUOTA_USER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_QUOTA_USER_ZONE, 1)
+		query.AddSelect(common.ICAT_COLUMN_QUOTA_RESC_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_QUOTA_USAGE, 1)
+		query.AddSelect(common.ICAT_COLUMN_QUOTA_USAGE_MODIFY_TIME, 1)
+
+		if len(user) > 0 {
+			condTypeVal := util.MakeGenQueryEqualCondition(user)
+			query.AddCondition(common.ICAT_COLUMN_QUOTA_USER_NAME, condTypeVal)
+		}
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a quota usage query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a quota usage query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive quota usage attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedUsages := make([]*types.IRODSQuotaUsage, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive quota usage rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedUsages[row] == nil {
+					// create a new
+					pagenatedUsages[row] = &types.IRODSQuotaUsage{
+						UserName:   "",
+						UserZone:   "",
+						RescName:   "",
+						Usage:      0,
+						ModifyTime: time.Time{},
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_QUOTA_USER_NAME):
+					pagenatedUsages[row].UserName = value
+				case int(common.ICAT_COLUMN_QUOTA_USER_ZONE):
+					pagenatedUsages[row].UserZone = value
+				case int(common.ICAT_COLUMN_QUOTA_RESC_NAME):
+					pagenatedUsages[row].RescName = value
+				case int(common.ICAT_COLUMN_QUOTA_USAGE):
+					usage, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse quota usage '%s': %w", value, err)
+					}
+					pagenatedUsages[row].Usage = usage
+				case int(common.ICAT_COLUMN_QUOTA_USAGE_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedUsages[row].ModifyTime = mT
+				default:
+					// ignore
+				}
+			}
+		}
+
+		usages = append(usages, pagenatedUsages...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return usages, nil
+}
+
 // AddUserMeta sets metadata of a user object to given key values.
 func AddUserMeta(conn *connection.IRODSConnection, user string, metadata *types.IRODSMeta) error {
 	if conn == nil || !conn.IsConnected() {
 		return xerrors.Errorf("connection is nil or disconnected")
 	}
 
+	err := validateMeta(metadata)
+	if err != nil {
+		return err
+	}
+
 	// lock the connection
 	conn.Lock()
 	defer conn.Unlock()
 
 	request := message.NewIRODSMessageAddMetadataRequest(types.IRODSUserMetaItemType, user, metadata)
 	response := message.IRODSMessageModifyMetadataResponse{}
-	return conn.RequestAndCheck(request, &response, nil)
+	err = conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		if metaErr := getMetaTooLargeError(err, metadata); metaErr != nil {
+			return xerrors.Errorf("received add user meta error: %w", metaErr)
+		}
+		return err
+	}
+	return nil
 }
 
 // DeleteUserMeta removes the metadata of a user object.
@@ -838,7 +1333,7 @@ func ListUserMeta(conn *connection.IRODSConnection, user string) ([]*types.IRODS
 		query.AddSelect(common.ICAT_COLUMN_META_USER_CREATE_TIME, 1)
 		query.AddSelect(common.ICAT_COLUMN_META_USER_MODIFY_TIME, 1)
 
-		nameCondVal := fmt.Sprintf("= '%s'", user)
+		nameCondVal := util.MakeGenQueryEqualCondition(user)
 		query.AddCondition(common.ICAT_COLUMN_USER_NAME, nameCondVal)
 
 		queryResult := message.IRODSMessageQueryResponse{}
@@ -929,6 +1424,157 @@ func ListUserMeta(conn *connection.IRODSConnection, user string) ([]*types.IRODS
 	return metas, nil
 }
 
+// AddGroupMeta sets metadata of a group object to given key values.
+// iRODS stores groups as users, so group metadata are managed as user metadata.
+func AddGroupMeta(conn *connection.IRODSConnection, group string, metadata *types.IRODSMeta) error {
+	return AddUserMeta(conn, group, metadata)
+}
+
+// DeleteGroupMeta removes the metadata of a group object.
+// The metadata AVU is selected on basis of AVUID if it is supplied, otherwise on basis of Name, Value and Units.
+func DeleteGroupMeta(conn *connection.IRODSConnection, group string, metadata *types.IRODSMeta) error {
+	return DeleteUserMeta(conn, group, metadata)
+}
+
+// ListGroupMeta returns all metadata for the group
+func ListGroupMeta(conn *connection.IRODSConnection, group string) ([]*types.IRODSMeta, error) {
+	return ListUserMeta(conn, group)
+}
+
+// SearchUsersByMeta searches users (excluding groups) by metadata
+func SearchUsersByMeta(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSUser, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	userTypeCondVal := util.MakeGenQueryCondition("<>", string(types.IRODSUserRodsGroup))
+	return searchUsersByMeta(conn, metaName, metaValueCondVal, userTypeCondVal)
+}
+
+// SearchUsersByMetaWildcard searches users (excluding groups) by metadata
+// metaValue is in SQL LIKE syntax, e.g., "0000-0002-%"
+func SearchUsersByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSUser, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	userTypeCondVal := util.MakeGenQueryCondition("<>", string(types.IRODSUserRodsGroup))
+	return searchUsersByMeta(conn, metaName, metaValueCondVal, userTypeCondVal)
+}
+
+// SearchGroupsByMeta searches groups by metadata
+func SearchGroupsByMeta(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSUser, error) {
+	metaValueCondVal := util.MakeGenQueryEqualCondition(metaValue)
+	userTypeCondVal := util.MakeGenQueryEqualCondition(string(types.IRODSUserRodsGroup))
+	return searchUsersByMeta(conn, metaName, metaValueCondVal, userTypeCondVal)
+}
+
+// SearchGroupsByMetaWildcard searches groups by metadata
+// metaValue is in SQL LIKE syntax, e.g., "project-%"
+func SearchGroupsByMetaWildcard(conn *connection.IRODSConnection, metaName string, metaValue string) ([]*types.IRODSUser, error) {
+	metaValueCondVal := util.MakeGenQueryLikeCondition(metaValue)
+	userTypeCondVal := util.MakeGenQueryEqualCondition(string(types.IRODSUserRodsGroup))
+	return searchUsersByMeta(conn, metaName, metaValueCondVal, userTypeCondVal)
+}
+
+func searchUsersByMeta(conn *connection.IRODSConnection, metaName string, metaValueCondVal string, userTypeCondVal string) ([]*types.IRODSUser, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForSearch(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	users := []*types.IRODSUser{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_USER_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_TYPE, 1)
+		query.AddSelect(common.ICAT_COLUMN_USER_ZONE, 1)
+
+		metaNameCondVal := util.MakeGenQueryEqualCondition(metaName)
+		query.AddCondition(common.ICAT_COLUMN_META_USER_ATTR_NAME, metaNameCondVal)
+		query.AddCondition(common.ICAT_COLUMN_META_USER_ATTR_VALUE, metaValueCondVal)
+		query.AddCondition(common.ICAT_COLUMN_USER_TYPE, userTypeCondVal)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a user query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received a user query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive user attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedUsers := make([]*types.IRODSUser, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive user rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedUsers[row] == nil {
+					// create a new
+					pagenatedUsers[row] = &types.IRODSUser{
+						ID:   -1,
+						Zone: "",
+						Name: "",
+						Type: types.IRODSUserRodsUser,
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_USER_ID):
+					userID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse user id '%s': %w", value, err)
+					}
+					pagenatedUsers[row].ID = userID
+				case int(common.ICAT_COLUMN_USER_ZONE):
+					pagenatedUsers[row].Zone = value
+				case int(common.ICAT_COLUMN_USER_NAME):
+					pagenatedUsers[row].Name = value
+				case int(common.ICAT_COLUMN_USER_TYPE):
+					pagenatedUsers[row].Type = types.IRODSUserType(value)
+				default:
+					// ignore
+				}
+			}
+		}
+
+		users = append(users, pagenatedUsers...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return users, nil
+}
+
 // AddChildToResc adds a child to a parent resource
 func AddChildToResc(conn *connection.IRODSConnection, parent string, child string, options string) error {
 	// lock the connection
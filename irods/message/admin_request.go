@@ -84,26 +84,5 @@ func (msg *IRODSMessageAdminRequest) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageAdminRequest) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.GENERAL_ADMIN_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.GENERAL_ADMIN_AN)
 }
@@ -0,0 +1,125 @@
+package message
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageAtomicMetadataOperation stores an operation of atomic metadata request
+type IRODSMessageAtomicMetadataOperation struct {
+	Operation string `json:"operation"` // add or remove
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+	Units     string `json:"units,omitempty"`
+}
+
+// IRODSMessageAtomicMetadataRequest stores atomic metadata request, all operations are applied or none
+// Uses JSON, not XML
+// Supported v4.2.8 or above
+type IRODSMessageAtomicMetadataRequest struct {
+	AdminMode  bool                                  `json:"admin_mode,omitempty"`
+	EntityName string                                `json:"entity_name"`
+	EntityType string                                `json:"entity_type"`
+	Operations []IRODSMessageAtomicMetadataOperation `json:"operations"`
+}
+
+// NewIRODSMessageAtomicMetadataRequest creates a IRODSMessageAtomicMetadataRequest message
+func NewIRODSMessageAtomicMetadataRequest(itemType types.IRODSMetaItemType, name string) *IRODSMessageAtomicMetadataRequest {
+	entityType := ""
+	switch itemType {
+	case types.IRODSDataObjectMetaItemType:
+		entityType = "data_object"
+	case types.IRODSCollectionMetaItemType:
+		entityType = "collection"
+	case types.IRODSResourceMetaItemType:
+		entityType = "resource"
+	case types.IRODSUserMetaItemType:
+		entityType = "user"
+	}
+
+	return &IRODSMessageAtomicMetadataRequest{
+		EntityName: name,
+		EntityType: entityType,
+		Operations: []IRODSMessageAtomicMetadataOperation{},
+	}
+}
+
+// AddOperation adds an operation for the metadata, operation is add or remove
+func (msg *IRODSMessageAtomicMetadataRequest) AddOperation(operation string, metadata *types.IRODSMeta) {
+	msg.Operations = append(msg.Operations, IRODSMessageAtomicMetadataOperation{
+		Operation: operation,
+		Attribute: metadata.Name,
+		Value:     metadata.Value,
+		Units:     metadata.Units,
+	})
+}
+
+// getMessageBody returns BinBytesBuf carrying the message in JSON
+func (msg *IRODSMessageAtomicMetadataRequest) getMessageBody() (interface{}, error) {
+	return newIRODSMessageBinBytesBufJSON(msg)
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageAtomicMetadataRequest) GetBytes() ([]byte, error) {
+	binBytesBuf, err := newIRODSMessageBinBytesBufJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	xmlBytes, err := xml.Marshal(binBytesBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageAtomicMetadataRequest) FromBytes(bytes []byte) error {
+	binBytesBuf := IRODSMessageBinBytesBuf{}
+	err := xml.Unmarshal(bytes, &binBytesBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+
+	jsonBody, err := base64.StdEncoding.DecodeString(binBytesBuf.Data)
+	if err != nil {
+		return xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	err = json.Unmarshal(jsonBody, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal json to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageAtomicMetadataRequest) GetMessage() (*IRODSMessage, error) {
+	bytes, err := msg.GetBytes()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
+	}
+
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: bytes,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.ATOMIC_APPLY_METADATA_OPERATIONS_APN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
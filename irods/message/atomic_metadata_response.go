@@ -0,0 +1,25 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// IRODSMessageAtomicMetadataResponse stores atomic metadata response
+type IRODSMessageAtomicMetadataResponse struct {
+	// error details in JSON are ignored
+	Result int `xml:"-" irods:"intinfo"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageAtomicMetadataResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageAtomicMetadataResponse) FromMessage(msgIn *IRODSMessage) error {
+	return UnmarshalIRODSMessage(msgIn, msg)
+}
@@ -64,7 +64,7 @@ func (msg *IRODSMessageAuthChallengeResponse) FromMessage(msgIn *IRODSMessage) e
 		return xerrors.Errorf("empty message body")
 	}
 
-	err := msg.FromBytes(msgIn.Body.Message)
+	err := unmarshalIRODSMessageBody(msgIn.Body, msg)
 	if err != nil {
 		return xerrors.Errorf("failed to get irods message from message body")
 	}
@@ -3,6 +3,8 @@ package message
 import (
 	"encoding/xml"
 
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
 	"golang.org/x/xerrors"
 )
 
@@ -10,6 +12,16 @@ import (
 type IRODSMessagePamAuthResponse struct {
 	XMLName           xml.Name `xml:"pamAuthRequestOut_PI"`
 	GeneratedPassword string   `xml:"irodsPamPassword"`
+	// stores error return
+	Result int `xml:"-" irods:"intinfo"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessagePamAuthResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
 }
 
 // GetBytes returns byte array
@@ -32,13 +44,5 @@ func (msg *IRODSMessagePamAuthResponse) FromBytes(bytes []byte) error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessagePamAuthResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	err := msg.FromBytes(msgIn.Body.Message)
-	if err != nil {
-		return xerrors.Errorf("failed to get irods message from message body")
-	}
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
@@ -43,7 +43,7 @@ func (msg *IRODSMessageAuthPluginResponse) FromMessage(msgIn *IRODSMessage) erro
 		return xerrors.Errorf("empty message body")
 	}
 
-	err := msg.FromBytes(msgIn.Body.Message)
+	err := unmarshalIRODSMessageBody(msgIn.Body, msg)
 	if err != nil {
 		return xerrors.Errorf("failed to get irods message from message body")
 	}
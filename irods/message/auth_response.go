@@ -42,28 +42,7 @@ func (msg *IRODSMessageAuthResponse) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageAuthResponse) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.AUTH_RESPONSE_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.AUTH_RESPONSE_AN)
 }
 
 // FromMessage returns struct from IRODSMessage
@@ -72,7 +51,7 @@ func (msg *IRODSMessageAuthResponse) FromMessage(msgIn *IRODSMessage) error {
 		return xerrors.Errorf("empty message body")
 	}
 
-	err := msg.FromBytes(msgIn.Body.Message)
+	err := unmarshalIRODSMessageBody(msgIn.Body, msg)
 	if err != nil {
 		return xerrors.Errorf("failed to get irods message from message body")
 	}
@@ -1,7 +1,11 @@
 package message
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"encoding/xml"
+
+	"golang.org/x/xerrors"
 )
 
 // IRODSMessageBinBytesBuf stores bytes buffer
@@ -11,5 +15,35 @@ type IRODSMessageBinBytesBuf struct {
 	Length int    `xml:"buflen"`
 	Data   string `xml:"buf"` // data is base64 encoded
 
-	Result int
+	Result int `xml:"-"`
+}
+
+// newIRODSMessageBinBytesBufJSON creates a IRODSMessageBinBytesBuf carrying the message in JSON
+func newIRODSMessageBinBytesBufJSON(msg interface{}) (*IRODSMessageBinBytesBuf, error) {
+	jsonBody, err := json.Marshal(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to json: %w", err)
+	}
+
+	return &IRODSMessageBinBytesBuf{
+		Length: len(jsonBody), // use original data's length
+		Data:   base64.StdEncoding.EncodeToString(jsonBody),
+	}, nil
+}
+
+// getJSONBody returns JSON carried in the buffer, without trailing \x00
+func (msg *IRODSMessageBinBytesBuf) getJSONBody() ([]byte, error) {
+	jsonBody, err := base64.StdEncoding.DecodeString(msg.Data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	// remove trail \x00
+	actualLen := len(jsonBody)
+	for i := len(jsonBody) - 1; i >= 0; i-- {
+		if jsonBody[i] == '\x00' {
+			actualLen = i
+		}
+	}
+	return jsonBody[:actualLen], nil
 }
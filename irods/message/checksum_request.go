@@ -61,26 +61,5 @@ func (msg *IRODSMessageChecksumRequest) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageChecksumRequest) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.DATA_OBJ_CHKSUM_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.DATA_OBJ_CHKSUM_AN)
 }
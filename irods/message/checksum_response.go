@@ -12,12 +12,17 @@ import (
 type IRODSMessageChecksumResponse struct {
 	Checksum string `xml:"myStr"`
 	// stores error return
-	Result int `xml:"-"`
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 type STRI_PI struct {
 }
 
+// GetPackingInstruction returns the name of packing instruction for native protocol
+func (msg *IRODSMessageChecksumResponse) GetPackingInstruction() string {
+	return "STR_PI"
+}
+
 // GetBytes returns byte array
 func (msg *IRODSMessageChecksumResponse) GetBytes() ([]byte, error) {
 	xmlBytes, err := xml.Marshal(msg)
@@ -51,18 +56,5 @@ func (msg *IRODSMessageChecksumResponse) FromBytes(bytes []byte) error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageChecksumResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-
-	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
-		if err != nil {
-			return xerrors.Errorf("failed to get irods message from message body")
-		}
-	}
-
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
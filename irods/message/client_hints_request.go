@@ -0,0 +1,35 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageClientHintsRequest stores client hints request, the request has no input
+type IRODSMessageClientHintsRequest struct{}
+
+// NewIRODSMessageClientHintsRequest creates a IRODSMessageClientHintsRequest message
+func NewIRODSMessageClientHintsRequest() *IRODSMessageClientHintsRequest {
+	return &IRODSMessageClientHintsRequest{}
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageClientHintsRequest) GetMessage() (*IRODSMessage, error) {
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: nil,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.CLIENT_HINTS_AN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package message
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageClientHintsResponse stores client hints response
+// Uses JSON in BinBytesBuf, not XML
+type IRODSMessageClientHintsResponse struct {
+	ClientHints types.IRODSClientHints
+
+	// stores error return
+	Result int `json:"-"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageClientHintsResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// GetPackingInstruction returns the name of packing instruction for native protocol
+func (msg *IRODSMessageClientHintsResponse) GetPackingInstruction() string {
+	return "BinBytesBuf_PI"
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageClientHintsResponse) FromBytes(bytes []byte) error {
+	binBytesBuf := IRODSMessageBinBytesBuf{}
+	err := xml.Unmarshal(bytes, &binBytesBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+
+	return msg.fromBinBytesBuf(&binBytesBuf)
+}
+
+// fromBinBytesBuf returns struct from JSON in BinBytesBuf
+func (msg *IRODSMessageClientHintsResponse) fromBinBytesBuf(binBytesBuf *IRODSMessageBinBytesBuf) error {
+	jsonBody, err := binBytesBuf.getJSONBody()
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(jsonBody, &msg.ClientHints)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal json to irods message: %w", err)
+	}
+
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageClientHintsResponse) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	msg.Result = int(msgIn.Body.IntInfo)
+
+	if msgIn.Body.Message != nil {
+		binBytesBuf := IRODSMessageBinBytesBuf{}
+		err := unmarshalIRODSMessageBody(msgIn.Body, &binBytesBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal irods message: %w", err)
+		}
+
+		err = msg.fromBinBytesBuf(&binBytesBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to get irods message from message body: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,13 +3,12 @@ package message
 import (
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/types"
-	"golang.org/x/xerrors"
 )
 
 // IRODSMessageCloseDataObjectResponse stores data object close response
 type IRODSMessageCloseDataObjectResponse struct {
 	// empty structure
-	Result int
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 // CheckError returns error if server returned an error
@@ -22,10 +21,5 @@ func (msg *IRODSMessageCloseDataObjectResponse) CheckError() error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageCloseDataObjectResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
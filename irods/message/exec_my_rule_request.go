@@ -0,0 +1,66 @@
+package message
+
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageExecMyRuleRequest stores rule execution request
+type IRODSMessageExecMyRuleRequest struct {
+	XMLName      xml.Name                  `xml:"ExecMyRuleInp_PI"`
+	Rule         string                    `xml:"myRule"`
+	Host         IRODSMessageHost          `xml:"RHostAddr_PI"` // empty to run on the connected server
+	KeyVals      IRODSMessageSSKeyVal      `xml:"KeyValPair_PI"`
+	OutParamDesc string                    `xml:"outParamDesc"` // output labels joined with %
+	Params       *IRODSMessageMsParamArray `xml:"MsParamArray_PI"`
+}
+
+// NewIRODSMessageExecMyRuleRequest creates a IRODSMessageExecMyRuleRequest message
+func NewIRODSMessageExecMyRuleRequest(rule string, inputs types.IRODSRuleParams, outParamDesc string) (*IRODSMessageExecMyRuleRequest, error) {
+	params, err := NewIRODSMessageMsParamArray(inputs)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make rule input params: %w", err)
+	}
+
+	request := &IRODSMessageExecMyRuleRequest{
+		Rule: rule,
+		KeyVals: IRODSMessageSSKeyVal{
+			Length: 0,
+		},
+		OutParamDesc: outParamDesc,
+		Params:       params,
+	}
+
+	return request, nil
+}
+
+// AddKeyVal adds a key-value pair
+func (msg *IRODSMessageExecMyRuleRequest) AddKeyVal(key common.KeyWord, val string) {
+	msg.KeyVals.Add(string(key), val)
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageExecMyRuleRequest) GetBytes() ([]byte, error) {
+	xmlBytes, err := xml.Marshal(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageExecMyRuleRequest) FromBytes(bytes []byte) error {
+	err := xml.Unmarshal(bytes, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageExecMyRuleRequest) GetMessage() (*IRODSMessage, error) {
+	return MarshalIRODSMessage(msg, common.EXEC_MY_RULE_AN)
+}
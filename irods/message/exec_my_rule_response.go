@@ -0,0 +1,26 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// IRODSMessageExecMyRuleResponse stores rule execution response
+type IRODSMessageExecMyRuleResponse struct {
+	IRODSMessageMsParamArray
+	// stores error return
+	Result int `xml:"-" irods:"intinfo"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageExecMyRuleResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageExecMyRuleResponse) FromMessage(msgIn *IRODSMessage) error {
+	return UnmarshalIRODSMessage(msgIn, msg)
+}
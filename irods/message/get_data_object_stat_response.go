@@ -22,7 +22,7 @@ type IRODSMessageGetDataObjectStatResponse struct {
 	ModifyTime               string                         `xml:"modifyTime"`
 	SpecialCollectionPointer *IRODSMessageSpecialCollection `xml:"SpecColl_PI"`
 	// stores error return
-	Result int `xml:"-"`
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 // GetBytes returns byte array
@@ -53,18 +53,5 @@ func (msg *IRODSMessageGetDataObjectStatResponse) FromBytes(bytes []byte) error
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageGetDataObjectStatResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-
-	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
-		if err != nil {
-			return xerrors.Errorf("failed to get irods message from message body")
-		}
-	}
-
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
@@ -21,18 +21,16 @@ func NewIRODSMessageGetDescriptorInfoRequest(desc int) *IRODSMessageGetDescripto
 	}
 }
 
+// getMessageBody returns BinBytesBuf carrying the message in JSON
+func (msg *IRODSMessageGetDescriptorInfoRequest) getMessageBody() (interface{}, error) {
+	return newIRODSMessageBinBytesBufJSON(msg)
+}
+
 // GetBytes returns byte array
 func (msg *IRODSMessageGetDescriptorInfoRequest) GetBytes() ([]byte, error) {
-	jsonBody, err := json.Marshal(msg)
+	binBytesBuf, err := newIRODSMessageBinBytesBufJSON(msg)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to marshal irods message to json: %w", err)
-	}
-
-	jsonBodyBin := base64.StdEncoding.EncodeToString(jsonBody)
-
-	binBytesBuf := IRODSMessageBinBytesBuf{
-		Length: len(jsonBody), // use original data's length
-		Data:   jsonBodyBin,
+		return nil, err
 	}
 
 	xmlBytes, err := xml.Marshal(binBytesBuf)
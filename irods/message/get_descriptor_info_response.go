@@ -1,7 +1,6 @@
 package message
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 
@@ -50,6 +49,11 @@ func (msg *IRODSMessageGetDescriptorInfoResponse) CheckError() error {
 	return nil
 }
 
+// GetPackingInstruction returns the name of packing instruction for native protocol
+func (msg *IRODSMessageGetDescriptorInfoResponse) GetPackingInstruction() string {
+	return "BinBytesBuf_PI"
+}
+
 // FromBytes returns struct from bytes
 func (msg *IRODSMessageGetDescriptorInfoResponse) FromBytes(bytes []byte) error {
 	binBytesBuf := IRODSMessageBinBytesBuf{}
@@ -58,19 +62,15 @@ func (msg *IRODSMessageGetDescriptorInfoResponse) FromBytes(bytes []byte) error
 		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
 	}
 
-	jsonBody, err := base64.StdEncoding.DecodeString(binBytesBuf.Data)
-	if err != nil {
-		return xerrors.Errorf("failed to decode base64 data: %w", err)
-	}
+	return msg.fromBinBytesBuf(&binBytesBuf)
+}
 
-	// remove trail \x00
-	actualLen := len(jsonBody)
-	for i := len(jsonBody) - 1; i >= 0; i-- {
-		if jsonBody[i] == '\x00' {
-			actualLen = i
-		}
+// fromBinBytesBuf returns struct from JSON in BinBytesBuf
+func (msg *IRODSMessageGetDescriptorInfoResponse) fromBinBytesBuf(binBytesBuf *IRODSMessageBinBytesBuf) error {
+	jsonBody, err := binBytesBuf.getJSONBody()
+	if err != nil {
+		return err
 	}
-	jsonBody = jsonBody[:actualLen]
 
 	err = json.Unmarshal(jsonBody, msg)
 	if err != nil {
@@ -89,7 +89,13 @@ func (msg *IRODSMessageGetDescriptorInfoResponse) FromMessage(msgIn *IRODSMessag
 	msg.Result = int(msgIn.Body.IntInfo)
 
 	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
+		binBytesBuf := IRODSMessageBinBytesBuf{}
+		err := unmarshalIRODSMessageBody(msgIn.Body, &binBytesBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal irods message: %w", err)
+		}
+
+		err = msg.fromBinBytesBuf(&binBytesBuf)
 		if err != nil {
 			return xerrors.Errorf("failed to get irods message from message body")
 		}
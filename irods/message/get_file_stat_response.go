@@ -26,7 +26,7 @@ type IRODSMessageGetFileStatResponse struct {
 	Blocks     int      `xml:"st_blocks"`
 
 	// stores error return
-	Result int `xml:"-"`
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 // GetBytes returns byte array
@@ -57,18 +57,5 @@ func (msg *IRODSMessageGetFileStatResponse) FromBytes(bytes []byte) error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageGetFileStatResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-
-	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
-		if err != nil {
-			return xerrors.Errorf("failed to get irods message from message body")
-		}
-	}
-
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
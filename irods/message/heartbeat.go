@@ -0,0 +1,44 @@
+package message
+
+import (
+	"golang.org/x/xerrors"
+)
+
+const (
+	// RODS_MESSAGE_HEARTBEAT_TYPE is a message type for heartbeat, the server echoes it back
+	RODS_MESSAGE_HEARTBEAT_TYPE MessageType = "RODS_HEARTBEAT"
+)
+
+// IRODSMessageHeartbeat stores heartbeat request and response
+type IRODSMessageHeartbeat struct {
+	// empty structure
+}
+
+// NewIRODSMessageHeartbeat creates a IRODSMessageHeartbeat message
+func NewIRODSMessageHeartbeat() *IRODSMessageHeartbeat {
+	return &IRODSMessageHeartbeat{}
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageHeartbeat) GetMessage() (*IRODSMessage, error) {
+	msgHeader := IRODSMessageHeader{
+		Type:       RODS_MESSAGE_HEARTBEAT_TYPE,
+		MessageLen: 0,
+		ErrorLen:   0,
+		BsLen:      0,
+		IntInfo:    0,
+	}
+
+	return &IRODSMessage{
+		Header: &msgHeader,
+		Body:   nil,
+	}, nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageHeartbeat) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Header == nil || msgIn.Header.Type != RODS_MESSAGE_HEARTBEAT_TYPE {
+		return xerrors.Errorf("received a non-heartbeat message")
+	}
+	return nil
+}
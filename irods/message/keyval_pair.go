@@ -1,6 +1,11 @@
 package message
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
 
 // IRODSMessageRawString ...
 type IRODSMessageRawString struct {
@@ -47,6 +52,38 @@ func (kv *IRODSMessageSSKeyVal) Add(key string, val string) {
 	kv.Length = len(kv.Keys)
 }
 
+// NewIRODSMessageSSKeyValFromKeyVals creates a new IRODSMessageSSKeyVal from keywords
+func NewIRODSMessageSSKeyValFromKeyVals(keyVals *types.IRODSKeyVals) *IRODSMessageSSKeyVal {
+	kv := NewIRODSMessageSSKeyVal()
+	kv.AddKeyVals(keyVals)
+	return kv
+}
+
+// AddKeyVals adds keywords in order
+func (kv *IRODSMessageSSKeyVal) AddKeyVals(keyVals *types.IRODSKeyVals) {
+	if keyVals == nil {
+		return
+	}
+
+	for _, keyVal := range keyVals.GetKeyVals() {
+		kv.Add(string(keyVal.Key), keyVal.Value)
+	}
+}
+
+// GetKeyVals returns key-val pairs as keywords, later pairs override earlier ones of the same key
+func (kv *IRODSMessageSSKeyVal) GetKeyVals() *types.IRODSKeyVals {
+	keyVals := types.NewIRODSKeyVals()
+	for idx, key := range kv.Keys {
+		val := ""
+		if idx < len(kv.Values) {
+			val = kv.Values[idx].Value
+		}
+
+		keyVals.Set(common.KeyWord(key), val)
+	}
+	return keyVals
+}
+
 // NewIRODSMessageIIKeyVal creates a new IRODSMessageIIKeyVal
 func NewIRODSMessageIIKeyVal() *IRODSMessageIIKeyVal {
 	return &IRODSMessageIIKeyVal{
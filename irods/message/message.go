@@ -27,6 +27,8 @@ type IRODSMessageBody struct {
 	Error   []byte
 	Bs      []byte
 	IntInfo int32
+
+	NativePacked bool // Message is in native packing, unpacked into message structs directly
 }
 
 // IRODSMessage defines a message
@@ -24,8 +24,13 @@ func NewIRODSMessageModifyAccessRequest(accessLevel, user, zone, path string, re
 		accessLevel = fmt.Sprintf("admin:%s", accessLevel)
 	}
 
+	recursiveFlag := 0
+	if recursive {
+		recursiveFlag = 1
+	}
+
 	request := &IRODSMessageModifyAccessRequest{
-		RecursiveFlag: 0,
+		RecursiveFlag: recursiveFlag,
 		AccessLevel:   accessLevel,
 		UserName:      user,
 		Zone:          zone,
@@ -55,26 +60,5 @@ func (msg *IRODSMessageModifyAccessRequest) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageModifyAccessRequest) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.MOD_ACCESS_CONTROL_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.MOD_ACCESS_CONTROL_AN)
 }
@@ -45,6 +45,13 @@ func NewIRODSMessageMoveDataObjectRequest(srcPath string, destPath string) *IROD
 	}
 }
 
+// AddKeyVal adds a key-value pair
+func (msg *IRODSMessageMoveDataObjectRequest) AddKeyVal(key common.KeyWord, val string) {
+	if len(msg.Paths) >= 2 {
+		msg.Paths[1].KeyVals.Add(string(key), val)
+	}
+}
+
 // GetBytes returns byte array
 func (msg *IRODSMessageMoveDataObjectRequest) GetBytes() ([]byte, error) {
 	xmlBytes, err := xml.Marshal(msg)
@@ -65,26 +72,5 @@ func (msg *IRODSMessageMoveDataObjectRequest) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageMoveDataObjectRequest) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.DATA_OBJ_RENAME_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.DATA_OBJ_RENAME_AN)
 }
@@ -0,0 +1,237 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"html"
+	"sort"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MsParam types
+	MS_PARAM_TYPE_STR          string = "STR_PI"
+	MS_PARAM_TYPE_INT          string = "INT_PI"
+	MS_PARAM_TYPE_KEYVAL       string = "KeyValPair_PI"
+	MS_PARAM_TYPE_BUF_LEN      string = "BUF_LEN_PI"
+	MS_PARAM_TYPE_EXEC_CMD_OUT string = "ExecCmdOut_PI"
+)
+
+// IRODSMessageString stores string message
+type IRODSMessageString struct {
+	XMLName xml.Name `xml:"STR_PI"`
+	Value   string   `xml:"myStr"`
+}
+
+// IRODSMessageBufLen stores length of a buffer in BinBytesBuf
+type IRODSMessageBufLen struct {
+	XMLName xml.Name `xml:"BUF_LEN_PI"`
+	Length  int      `xml:"myInt"`
+}
+
+// IRODSMessageExecCmdOut stores stdout and stderr of a command or a rule execution
+type IRODSMessageExecCmdOut struct {
+	XMLName xml.Name                  `xml:"ExecCmdOut_PI"`
+	Buffers []IRODSMessageBinBytesBuf `xml:"BinBytesBuf_PI"` // stdout and stderr
+	Status  int                       `xml:"status"`
+}
+
+// IRODSMessageMsParam stores a labeled rule parameter, the value struct is chosen by Type
+type IRODSMessageMsParam struct {
+	XMLName    xml.Name                 `xml:"MsParam_PI"`
+	Label      string                   `xml:"label"`
+	Type       string                   `xml:"type"`
+	String     *IRODSMessageString      `xml:"STR_PI,omitempty"`
+	Int        *IRODSMessageInt         `xml:"INT_PI,omitempty"`
+	KeyVals    *IRODSMessageSSKeyVal    `xml:"KeyValPair_PI,omitempty"`
+	BufLen     *IRODSMessageBufLen      `xml:"BUF_LEN_PI,omitempty"`
+	ExecCmdOut *IRODSMessageExecCmdOut  `xml:"ExecCmdOut_PI,omitempty"`
+	Buffer     *IRODSMessageBinBytesBuf `xml:"BinBytesBuf_PI,omitempty"`
+}
+
+// IRODSMessageMsParamArray stores rule parameters
+type IRODSMessageMsParamArray struct {
+	XMLName xml.Name              `xml:"MsParamArray_PI"`
+	Length  int                   `xml:"paramLen"`
+	OprType int                   `xml:"oprType"`
+	Params  []IRODSMessageMsParam `xml:"MsParam_PI,omitempty"`
+}
+
+// NewIRODSMessageMsParam creates a IRODSMessageMsParam from a Go value
+// value must be one of string, int, int32, int64, map[string]string, or []byte
+func NewIRODSMessageMsParam(label string, value interface{}) (*IRODSMessageMsParam, error) {
+	param := &IRODSMessageMsParam{
+		Label: label,
+	}
+
+	switch v := value.(type) {
+	case string:
+		param.Type = MS_PARAM_TYPE_STR
+		param.String = &IRODSMessageString{
+			Value: v,
+		}
+	case int:
+		param.Type = MS_PARAM_TYPE_INT
+		param.Int = &IRODSMessageInt{
+			Value: v,
+		}
+	case int32:
+		param.Type = MS_PARAM_TYPE_INT
+		param.Int = &IRODSMessageInt{
+			Value: int(v),
+		}
+	case int64:
+		param.Type = MS_PARAM_TYPE_INT
+		param.Int = &IRODSMessageInt{
+			Value: int(v),
+		}
+	case map[string]string:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		keyVals := NewIRODSMessageSSKeyVal()
+		for _, key := range keys {
+			// values are raw inner xml
+			keyVals.Add(key, util.EscapeXMLSpecialChars(v[key]))
+		}
+
+		param.Type = MS_PARAM_TYPE_KEYVAL
+		param.KeyVals = keyVals
+	case []byte:
+		param.Type = MS_PARAM_TYPE_BUF_LEN
+		param.BufLen = &IRODSMessageBufLen{
+			Length: len(v),
+		}
+		param.Buffer = &IRODSMessageBinBytesBuf{
+			Length: len(v),
+			Data:   base64.StdEncoding.EncodeToString(v),
+		}
+	default:
+		return nil, xerrors.Errorf("unsupported rule param type %T of %s", value, label)
+	}
+
+	return param, nil
+}
+
+// GetValue returns a Go value of the param
+// returns one of string, int, map[string]string, []byte, or *types.IRODSRuleExecOut
+func (param *IRODSMessageMsParam) GetValue() (interface{}, error) {
+	switch param.Type {
+	case MS_PARAM_TYPE_STR:
+		if param.String == nil {
+			return "", nil
+		}
+		return param.String.Value, nil
+	case MS_PARAM_TYPE_INT:
+		if param.Int == nil {
+			return 0, nil
+		}
+		return param.Int.Value, nil
+	case MS_PARAM_TYPE_KEYVAL:
+		keyVals := map[string]string{}
+		if param.KeyVals == nil {
+			return keyVals, nil
+		}
+
+		for idx, key := range param.KeyVals.Keys {
+			if idx < len(param.KeyVals.Values) {
+				// values are raw inner xml
+				keyVals[key] = html.UnescapeString(param.KeyVals.Values[idx].Value)
+			}
+		}
+		return keyVals, nil
+	case MS_PARAM_TYPE_BUF_LEN:
+		return decodeMsParamBuffer(param.Buffer)
+	case MS_PARAM_TYPE_EXEC_CMD_OUT:
+		execOut := &types.IRODSRuleExecOut{}
+		if param.ExecCmdOut == nil {
+			return execOut, nil
+		}
+
+		buffers := [][]byte{}
+		for idx := range param.ExecCmdOut.Buffers {
+			data, err := decodeMsParamBuffer(&param.ExecCmdOut.Buffers[idx])
+			if err != nil {
+				return nil, err
+			}
+			// stdout and stderr are null-terminated
+			buffers = append(buffers, bytes.TrimRight(data, "\x00"))
+		}
+
+		if len(buffers) > 0 {
+			execOut.Stdout = buffers[0]
+		}
+
+		if len(buffers) > 1 {
+			execOut.Stderr = buffers[1]
+		}
+
+		execOut.Status = param.ExecCmdOut.Status
+		return execOut, nil
+	default:
+		return nil, xerrors.Errorf("unsupported rule param type %s of %s", param.Type, param.Label)
+	}
+}
+
+// decodeMsParamBuffer returns data in BinBytesBuf
+func decodeMsParamBuffer(buffer *IRODSMessageBinBytesBuf) ([]byte, error) {
+	if buffer == nil {
+		return []byte{}, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(buffer.Data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	if buffer.Length >= 0 && buffer.Length < len(data) {
+		data = data[:buffer.Length]
+	}
+	return data, nil
+}
+
+// NewIRODSMessageMsParamArray creates a IRODSMessageMsParamArray from params, ordered by labels
+func NewIRODSMessageMsParamArray(params types.IRODSRuleParams) (*IRODSMessageMsParamArray, error) {
+	labels := []string{}
+	for label := range params {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	paramArray := &IRODSMessageMsParamArray{
+		Params: []IRODSMessageMsParam{},
+	}
+
+	for _, label := range labels {
+		param, err := NewIRODSMessageMsParam(label, params[label])
+		if err != nil {
+			return nil, err
+		}
+		paramArray.Params = append(paramArray.Params, *param)
+	}
+
+	paramArray.Length = len(paramArray.Params)
+	return paramArray, nil
+}
+
+// GetParams returns params keyed by labels
+func (paramArray *IRODSMessageMsParamArray) GetParams() (types.IRODSRuleParams, error) {
+	params := types.IRODSRuleParams{}
+	for idx := range paramArray.Params {
+		param := &paramArray.Params[idx]
+
+		value, err := param.GetValue()
+		if err != nil {
+			return nil, err
+		}
+		params[param.Label] = value
+	}
+	return params, nil
+}
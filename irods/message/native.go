@@ -0,0 +1,667 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// iRODS native protocol packs messages as described by packing instructions (rodsPackInstruct.h).
+// ints are 4 bytes and doubles are 8 bytes in network byte order, strings are null-terminated,
+// and a NULL pointer is packed as a special string.
+// Message structs are packed and unpacked directly, see MarshalNative; XML bodies built otherwise are translated here.
+
+const (
+	nativeNullPointerString string = "%@#ANULLSTR$%"
+)
+
+var (
+	nativePackingConstants = map[string]int{
+		"NAME_LEN":        64,
+		"LONG_NAME_LEN":   256,
+		"MAX_NAME_LEN":    1088,
+		"HEADER_TYPE_LEN": 128,
+		"TIME_LEN":        32,
+		"ERR_MSG_LEN":     1024,
+		"CHALLENGE_LEN":   64,
+		"RESPONSE_LEN":    16,
+		"MAX_SQL_ATTR":    50,
+	}
+
+	nativePackingInstructions = map[string]string{
+		"MsgHeader_PI":              "str type[HEADER_TYPE_LEN]; int msgLen; int errorLen; int bsLen; int intInfo;",
+		"StartupPack_PI":            "int irodsProt; int reconnFlag; int connectCnt; str proxyUser[NAME_LEN]; str proxyRcatZone[NAME_LEN]; str clientUser[NAME_LEN]; str clientRcatZone[NAME_LEN]; str relVersion[NAME_LEN]; str apiVersion[NAME_LEN]; str option[LONG_NAME_LEN];",
+		"Version_PI":                "int status; str relVersion[NAME_LEN]; str apiVersion[NAME_LEN]; int reconnPort; str reconnAddr[LONG_NAME_LEN]; int cookie;",
+		"CS_NEG_PI":                 "int status; str result[MAX_NAME_LEN];",
+		"RErrMsg_PI":                "int status; str msg[ERR_MSG_LEN];",
+		"RError_PI":                 "int count; struct *RErrMsg_PI[count];",
+		"RHostAddr_PI":              "str hostAddr[LONG_NAME_LEN]; str rodsZone[NAME_LEN]; int port; int dummyInt;",
+		"INT_PI":                    "int myInt;",
+		"STR_PI":                    "str myStr;",
+		"BinBytesBuf_PI":            "int buflen; bin *buf(buflen);",
+		"KeyValPair_PI":             "int ssLen; str *keyWord[ssLen]; str *svalue[ssLen];",
+		"InxIvalPair_PI":            "int iiLen; int *inx(iiLen); int *ivalue(iiLen);",
+		"InxValPair_PI":             "int isLen; int *inx(isLen); str *svalue[isLen];",
+		"SpecColl_PI":               "int collClass; int type; str collection[MAX_NAME_LEN]; str objPath[MAX_NAME_LEN]; str resource[NAME_LEN]; str rescHier[MAX_NAME_LEN]; str phyPath[MAX_NAME_LEN]; str cacheDir[MAX_NAME_LEN]; int cacheDirty; int replNum;",
+		"DataObjInp_PI":             "str objPath[MAX_NAME_LEN]; int createMode; int openFlags; double offset; double dataSize; int numThreads; int oprType; struct *SpecColl_PI; struct KeyValPair_PI;",
+		"DataObjCopyInp_PI":         "struct DataObjInp_PI; struct DataObjInp_PI;",
+		"OpenedDataObjInp_PI":       "int l1descInx; int len; int whence; int oprType; double offset; double bytesWritten; struct KeyValPair_PI;",
+		"PortList_PI":               "int portNum; int cookie; int sock; int windowSize; str hostAddr[LONG_NAME_LEN];",
+		"PortalOprOut_PI":           "int status; int l1descInx; int numThreads; str chksum[NAME_LEN]; struct PortList_PI;",
+		"CollInpNew_PI":             "str collName[MAX_NAME_LEN]; int flags; int oprType; struct KeyValPair_PI;",
+		"GenQueryInp_PI":            "int maxRows; int continueInx; int partialStartIndex; int options; struct KeyValPair_PI; struct InxIvalPair_PI; struct InxValPair_PI;",
+		"SqlResult_PI":              "int attriInx; int reslen; str *value(rowCnt)(reslen);",
+		"GenQueryOut_PI":            "int rowCnt; int attriCnt; int continueInx; int totalRowCount; struct SqlResult_PI[MAX_SQL_ATTR];",
+		"specificQueryInp_PI":       "str *sql; str *arg1; str *arg2; str *arg3; str *arg4; str *arg5; str *arg6; str *arg7; str *arg8; str *arg9; str *arg10; int maxRows; int continueInx; int rowOffset; int options; struct KeyValPair_PI;",
+		"ModAVUMetadataInp_PI":      "str *arg0; str *arg1; str *arg2; str *arg3; str *arg4; str *arg5; str *arg6; str *arg7; str *arg8; str *arg9; struct KeyValPair_PI;",
+		"modAccessControlInp_PI":    "int recursiveFlag; str *accessLevel; str *userName; str *zone; str *path;",
+		"generalAdminInp_PI":        "str *arg0; str *arg1; str *arg2; str *arg3; str *arg4; str *arg5; str *arg6; str *arg7; str *arg8; str *arg9;",
+		"userAdminInp_PI":           "str *arg0; str *arg1; str *arg2; str *arg3; str *arg4; str *arg5; str *arg6; str *arg7; str *arg8; str *arg9;",
+		"ticketAdminInp_PI":         "str *arg1; str *arg2; str *arg3; str *arg4; str *arg5; str *arg6; struct KeyValPair_PI;",
+		"endTransactionInp_PI":      "str *arg0; str *arg1;",
+		"authRequestOut_PI":         "bin *challenge(CHALLENGE_LEN);",
+		"authResponseInp_PI":        "bin *response(RESPONSE_LEN); str *username;",
+		"pamAuthRequestInp_PI":      "str *pamUser; str *pamPassword; int timeToLive;",
+		"pamAuthRequestOut_PI":      "str *irodsPamPassword;",
+		"authPlugReqInp_PI":         "str auth_scheme_[NAME_LEN]; str context_[MAX_NAME_LEN];",
+		"authPlugReqOut_PI":         "str result_[MAX_NAME_LEN];",
+		"fileLseekOut_PI":           "double offset;",
+		"fileStatInp_PI":            "struct RHostAddr_PI; str fileName[MAX_NAME_LEN]; str rescHier[MAX_NAME_LEN]; str objPath[MAX_NAME_LEN]; double rescId;",
+		"RODS_STAT_T_PI":            "double st_size; int st_dev; int st_ino; int st_mode; int st_nlink; int st_uid; int st_gid; int st_rdev; int st_atim; int st_mtim; int st_ctim; int st_blksize; int st_blocks;",
+		"RodsObjStat_PI":            "double objSize; int objType; int dataMode; str dataId[NAME_LEN]; str chksum[NAME_LEN]; str ownerName[NAME_LEN]; str ownerZone[NAME_LEN]; str createTime[TIME_LEN]; str modifyTime[TIME_LEN]; struct *SpecColl_PI; str rescHier[MAX_NAME_LEN];",
+		"StructFileExtAndRegInp_PI": "str objPath[MAX_NAME_LEN]; str collection[MAX_NAME_LEN]; int oprType; int flags; struct *SpecColl_PI; struct KeyValPair_PI;",
+		"ProcStatInp_PI":            "str addr[LONG_NAME_LEN]; str rodsZone[NAME_LEN]; struct KeyValPair_PI;",
+	}
+
+	nativePackingItemsCache      = map[string][]nativePackingItem{}
+	nativePackingItemsCacheMutex = sync.Mutex{}
+)
+
+// IRODSMessagePackingInstructionProvider is implemented by responses that can't tell the packing instruction from XMLName
+type IRODSMessagePackingInstructionProvider interface {
+	GetPackingInstruction() string
+}
+
+// nativePackingItem is an item in a packing instruction, e.g., "str *keyWord[ssLen];"
+type nativePackingItem struct {
+	Type        string
+	Name        string
+	Pointer     bool
+	ArrayDims   []string // [] dims
+	PointeeDims []string // () dims
+}
+
+// xmlNode is a simple XML tree
+type xmlNode struct {
+	Name     string
+	Text     string
+	Children []*xmlNode
+}
+
+// nativeScope holds int values decoded so far, for resolving dims that refer to other fields
+type nativeScope struct {
+	values map[string]int
+	parent *nativeScope
+}
+
+func newNativeScope(parent *nativeScope) *nativeScope {
+	return &nativeScope{
+		values: map[string]int{},
+		parent: parent,
+	}
+}
+
+func (scope *nativeScope) lookup(name string) (int, bool) {
+	for s := scope; s != nil; s = s.parent {
+		if v, ok := s.values[name]; ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// RegisterPackingInstruction registers a native packing instruction for a custom message type.
+// name must match XMLName of the message, e.g., "myApiInp_PI", and instruction follows rodsPackInstruct.h,
+// e.g., "str *path; int flags; struct KeyValPair_PI;". It overrides an existing instruction of the same name.
+func RegisterPackingInstruction(name string, instruction string) error {
+	if len(name) == 0 {
+		return xerrors.Errorf("empty packing instruction name")
+	}
+
+	items, err := parseNativePackingInstruction(instruction)
+	if err != nil {
+		return xerrors.Errorf("failed to parse packing instruction %s: %w", name, err)
+	}
+
+	nativePackingItemsCacheMutex.Lock()
+	defer nativePackingItemsCacheMutex.Unlock()
+
+	nativePackingInstructions[name] = instruction
+	nativePackingItemsCache[name] = items
+	return nil
+}
+
+// HasPackingInstruction returns true if native packing instruction is known for the name
+func HasPackingInstruction(name string) bool {
+	_, err := getNativePackingItems(name)
+	return err == nil
+}
+
+// GetPackingInstructionName returns the name of the packing instruction for the response
+// it returns empty string if unknown
+func GetPackingInstructionName(response interface{}) string {
+	if provider, ok := response.(IRODSMessagePackingInstructionProvider); ok {
+		return provider.GetPackingInstruction()
+	}
+
+	t := reflect.TypeOf(response)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	name := getTaggedPackingInstructionName(t)
+	if len(name) > 0 && HasPackingInstruction(name) {
+		return name
+	}
+	return ""
+}
+
+// ConvertXMLToNative translates XML message into native packing
+func ConvertXMLToNative(xmlBytes []byte) ([]byte, error) {
+	root, err := parseXMLNode(xmlBytes)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse xml message: %w", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	err = packNativeStruct(buffer, root.Name, root, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to pack %s in native protocol: %w", root.Name, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// ConvertNativeToXML translates native packing into XML message
+func ConvertNativeToXML(nativeBytes []byte, packingInstruction string) ([]byte, error) {
+	reader := bytes.NewReader(nativeBytes)
+	root, err := unpackNativeStruct(reader, packingInstruction, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unpack %s in native protocol: %w", packingInstruction, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := xml.NewEncoder(buffer)
+	err = writeXMLNode(encoder, root)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to write xml message: %w", err)
+	}
+
+	err = encoder.Flush()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to write xml message: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func getNativePackingItems(name string) ([]nativePackingItem, error) {
+	nativePackingItemsCacheMutex.Lock()
+	defer nativePackingItemsCacheMutex.Unlock()
+
+	if items, ok := nativePackingItemsCache[name]; ok {
+		return items, nil
+	}
+
+	instruction, ok := nativePackingInstructions[name]
+	if !ok {
+		return nil, xerrors.Errorf("unknown packing instruction %s", name)
+	}
+
+	items, err := parseNativePackingInstruction(instruction)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse packing instruction %s: %w", name, err)
+	}
+
+	nativePackingItemsCache[name] = items
+	return items, nil
+}
+
+func parseNativePackingInstruction(instruction string) ([]nativePackingItem, error) {
+	items := []nativePackingItem{}
+
+	for _, def := range strings.Split(instruction, ";") {
+		def = strings.TrimSpace(def)
+		if len(def) == 0 {
+			continue
+		}
+
+		fields := strings.Fields(def)
+		if len(fields) != 2 {
+			return nil, xerrors.Errorf("invalid packing item %q", def)
+		}
+
+		item := nativePackingItem{
+			Type: fields[0],
+		}
+
+		switch item.Type {
+		case "int", "int16", "double", "rlong", "str", "bin", "struct":
+		default:
+			return nil, xerrors.Errorf("unknown type %q in packing item %q", item.Type, def)
+		}
+
+		name := fields[1]
+		if strings.HasPrefix(name, "*") {
+			item.Pointer = true
+			name = name[1:]
+		}
+
+		for {
+			idx := strings.IndexAny(name, "[(")
+			if idx < 0 {
+				break
+			}
+
+			closing := "]"
+			if name[idx] == '(' {
+				closing = ")"
+			}
+
+			end := strings.Index(name[idx:], closing)
+			if end < 0 {
+				return nil, xerrors.Errorf("invalid packing item %q", def)
+			}
+
+			dim := name[idx+1 : idx+end]
+			if closing == "]" {
+				item.ArrayDims = append(item.ArrayDims, dim)
+			} else {
+				item.PointeeDims = append(item.PointeeDims, dim)
+			}
+
+			name = name[:idx] + name[idx+end+1:]
+		}
+
+		item.Name = name
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func resolveNativeDims(dims []string, scope *nativeScope) (int, error) {
+	count := 1
+	for _, dim := range dims {
+		if v, err := strconv.Atoi(dim); err == nil {
+			count *= v
+			continue
+		}
+
+		if v, ok := nativePackingConstants[dim]; ok {
+			count *= v
+			continue
+		}
+
+		if v, ok := scope.lookup(dim); ok {
+			count *= v
+			continue
+		}
+
+		return 0, xerrors.Errorf("failed to resolve dimension %s", dim)
+	}
+
+	if count < 0 {
+		count = 0
+	}
+	return count, nil
+}
+
+// getNativeElementCount returns the number of elements to pack for the item
+func getNativeElementCount(item *nativePackingItem, scope *nativeScope) (int, error) {
+	switch item.Type {
+	case "str":
+		if item.Pointer && len(item.ArrayDims) > 0 {
+			// array of string pointers
+			return resolveNativeDims(item.ArrayDims, scope)
+		}
+
+		// the last dim is the max length of a string
+		dims := item.ArrayDims
+		if item.Pointer {
+			dims = item.PointeeDims
+		}
+
+		if len(dims) <= 1 {
+			return 1, nil
+		}
+		return resolveNativeDims(dims[:len(dims)-1], scope)
+	default:
+		return resolveNativeDims(append(append([]string{}, item.ArrayDims...), item.PointeeDims...), scope)
+	}
+}
+
+func getXMLChildren(node *xmlNode, name string) []*xmlNode {
+	children := []*xmlNode{}
+	if node == nil {
+		return children
+	}
+
+	for _, child := range node.Children {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func packNativeStruct(buffer *bytes.Buffer, name string, node *xmlNode, parentScope *nativeScope) error {
+	items, err := getNativePackingItems(name)
+	if err != nil {
+		return err
+	}
+
+	scope := newNativeScope(parentScope)
+
+	// the same struct can appear multiple times, e.g., DataObjCopyInp_PI
+	used := map[string]int{}
+
+	for idx := range items {
+		item := &items[idx]
+
+		elements := getXMLChildren(node, item.Name)
+		offset := used[item.Name]
+		if offset < len(elements) {
+			elements = elements[offset:]
+		} else {
+			elements = []*xmlNode{}
+		}
+
+		if item.Pointer && len(elements) == 0 {
+			buffer.WriteString(nativeNullPointerString)
+			buffer.WriteByte(0)
+			continue
+		}
+
+		count, err := getNativeElementCount(item, scope)
+		if err != nil {
+			return err
+		}
+
+		if item.Type == "struct" && !item.Pointer && len(item.ArrayDims) == 0 {
+			count = 1
+		}
+
+		used[item.Name] += count
+
+		switch item.Type {
+		case "int", "int16", "double", "rlong":
+			for i := 0; i < count; i++ {
+				text := ""
+				if i < len(elements) {
+					text = strings.TrimSpace(elements[i].Text)
+				}
+
+				value := int64(0)
+				if len(text) > 0 {
+					value, err = strconv.ParseInt(text, 10, 64)
+					if err != nil {
+						return xerrors.Errorf("failed to parse %s value %q: %w", item.Name, text, err)
+					}
+				}
+
+				switch item.Type {
+				case "int":
+					binary.Write(buffer, binary.BigEndian, int32(value))
+				case "int16":
+					binary.Write(buffer, binary.BigEndian, int16(value))
+				default:
+					binary.Write(buffer, binary.BigEndian, value)
+				}
+
+				if count == 1 && !item.Pointer {
+					scope.values[item.Name] = int(value)
+				}
+			}
+		case "str":
+			for i := 0; i < count; i++ {
+				if i < len(elements) {
+					buffer.WriteString(elements[i].Text)
+				}
+				buffer.WriteByte(0)
+			}
+		case "bin":
+			data := []byte{}
+			if len(elements) > 0 {
+				data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(elements[0].Text))
+				if err != nil {
+					return xerrors.Errorf("failed to decode base64 %s: %w", item.Name, err)
+				}
+			}
+
+			padded := make([]byte, count)
+			copy(padded, data)
+			buffer.Write(padded)
+		case "struct":
+			for i := 0; i < count; i++ {
+				var element *xmlNode
+				if i < len(elements) {
+					element = elements[i]
+				}
+
+				err = packNativeStruct(buffer, item.Name, element, scope)
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			return xerrors.Errorf("unsupported packing type %s", item.Type)
+		}
+	}
+
+	return nil
+}
+
+func isNativeNullPointer(reader *bytes.Reader) bool {
+	marker := make([]byte, len(nativeNullPointerString)+1)
+	n, _ := reader.ReadAt(marker, reader.Size()-int64(reader.Len()))
+	if n == len(marker) && string(marker[:len(nativeNullPointerString)]) == nativeNullPointerString && marker[len(nativeNullPointerString)] == 0 {
+		reader.Seek(int64(len(marker)), io.SeekCurrent)
+		return true
+	}
+	return false
+}
+
+func readNativeString(reader *bytes.Reader) (string, error) {
+	sb := strings.Builder{}
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", xerrors.Errorf("failed to read null-terminated string: %w", err)
+		}
+
+		if b == 0 {
+			return sb.String(), nil
+		}
+		sb.WriteByte(b)
+	}
+}
+
+func unpackNativeStruct(reader *bytes.Reader, name string, parentScope *nativeScope) (*xmlNode, error) {
+	items, err := getNativePackingItems(name)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &xmlNode{
+		Name: name,
+	}
+	scope := newNativeScope(parentScope)
+
+	for idx := range items {
+		item := &items[idx]
+
+		if item.Pointer && isNativeNullPointer(reader) {
+			continue
+		}
+
+		count, err := getNativeElementCount(item, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if item.Type == "struct" && !item.Pointer && len(item.ArrayDims) == 0 {
+			count = 1
+		}
+
+		switch item.Type {
+		case "int", "int16", "double", "rlong":
+			for i := 0; i < count; i++ {
+				value := int64(0)
+				switch item.Type {
+				case "int":
+					v := int32(0)
+					err = binary.Read(reader, binary.BigEndian, &v)
+					value = int64(v)
+				case "int16":
+					v := int16(0)
+					err = binary.Read(reader, binary.BigEndian, &v)
+					value = int64(v)
+				default:
+					err = binary.Read(reader, binary.BigEndian, &value)
+				}
+
+				if err != nil {
+					return nil, xerrors.Errorf("failed to read %s: %w", item.Name, err)
+				}
+
+				if count == 1 && !item.Pointer {
+					scope.values[item.Name] = int(value)
+				}
+
+				node.Children = append(node.Children, &xmlNode{
+					Name: item.Name,
+					Text: strconv.FormatInt(value, 10),
+				})
+			}
+		case "str":
+			for i := 0; i < count; i++ {
+				value, err := readNativeString(reader)
+				if err != nil {
+					return nil, xerrors.Errorf("failed to read %s: %w", item.Name, err)
+				}
+
+				node.Children = append(node.Children, &xmlNode{
+					Name: item.Name,
+					Text: value,
+				})
+			}
+		case "bin":
+			data := make([]byte, count)
+			_, err = io.ReadFull(reader, data)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to read %s: %w", item.Name, err)
+			}
+
+			node.Children = append(node.Children, &xmlNode{
+				Name: item.Name,
+				Text: base64.StdEncoding.EncodeToString(data),
+			})
+		case "struct":
+			for i := 0; i < count; i++ {
+				child, err := unpackNativeStruct(reader, item.Name, scope)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+			}
+		default:
+			return nil, xerrors.Errorf("unsupported packing type %s", item.Type)
+		}
+	}
+
+	return node, nil
+}
+
+func parseXMLNode(xmlBytes []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(xmlBytes))
+
+	var root *xmlNode
+	stack := []*xmlNode{}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{
+				Name: t.Name.Local,
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, xerrors.Errorf("no root element")
+	}
+	return root, nil
+}
+
+func writeXMLNode(encoder *xml.Encoder, node *xmlNode) error {
+	start := xml.StartElement{
+		Name: xml.Name{Local: node.Name},
+	}
+
+	err := encoder.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	if len(node.Text) > 0 {
+		err = encoder.EncodeToken(xml.CharData(node.Text))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, child := range node.Children {
+		err = writeXMLNode(encoder, child)
+		if err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
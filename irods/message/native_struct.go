@@ -0,0 +1,783 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Message structs are packed in native protocol and unpacked from it directly, without going through XML.
+// Fields are matched to items of packing instructions by their XML names, as encoding/xml does:
+// fields of embedded structs are promoted, slices are repeated items, nil pointers and omitempty fields are absent,
+// and a struct of a ",innerxml" or ",chardata" field is packed as the text of the field, unescaped if it is inner XML.
+
+// irodsMessageBodyProvider is implemented by messages packed in a body of another struct, e.g., JSON in BinBytesBuf_PI
+type irodsMessageBodyProvider interface {
+	getMessageBody() (interface{}, error)
+}
+
+// nativeField is a struct field matched to items of packing instructions by its XML name
+type nativeField struct {
+	Name      string
+	Index     []int
+	OmitEmpty bool
+}
+
+// nativeStructInfo describes how a struct type is packed in native protocol
+type nativeStructInfo struct {
+	Fields    []nativeField
+	TextField []int // ",innerxml" or ",chardata" field, nil if not defined
+	InnerXML  bool  // TextField holds escaped XML text
+}
+
+var (
+	nativeStructInfoCache      = map[reflect.Type]*nativeStructInfo{}
+	nativeStructInfoCacheMutex = sync.Mutex{}
+)
+
+// CanMarshalNative returns true if MarshalNative can pack the message, the packing instruction of the message is known
+func CanMarshalNative(msg interface{}) bool {
+	if _, ok := msg.(irodsMessageBodyProvider); ok {
+		return true
+	}
+	return len(GetPackingInstructionName(msg)) > 0
+}
+
+// MarshalNative packs a message struct in native protocol, with the packing instruction of the message
+// see GetPackingInstructionName for how the packing instruction is told
+func MarshalNative(msg interface{}) ([]byte, error) {
+	if provider, ok := msg.(irodsMessageBodyProvider); ok {
+		body, err := provider.getMessageBody()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get message body: %w", err)
+		}
+		return MarshalNative(body)
+	}
+
+	v, err := getIRODSMessageValue(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	packingInstruction := GetPackingInstructionName(msg)
+	if len(packingInstruction) == 0 {
+		return nil, xerrors.Errorf("unknown packing instruction of %s", v.Type().Name())
+	}
+
+	buffer := &bytes.Buffer{}
+	err = packNativeValue(buffer, packingInstruction, v, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to pack %s in native protocol: %w", packingInstruction, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalNative unpacks native packing of the packing instruction into a message struct
+func UnmarshalNative(nativeBytes []byte, packingInstruction string, msg interface{}) error {
+	v, err := getIRODSMessageValue(msg)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(nativeBytes)
+	err = unpackNativeValue(reader, packingInstruction, v, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to unpack %s in native protocol: %w", packingInstruction, err)
+	}
+	return nil
+}
+
+// unmarshalIRODSMessageBody unmarshals the message of the body into the struct, in XML or in native protocol
+// native packing that is not valid is ignored if the server returned an error, the body may not be valid
+func unmarshalIRODSMessageBody(body *IRODSMessageBody, msg interface{}) error {
+	if !body.NativePacked {
+		return xml.Unmarshal(body.Message, msg)
+	}
+
+	packingInstruction := GetPackingInstructionName(msg)
+	if len(packingInstruction) == 0 {
+		if body.IntInfo < 0 {
+			return nil
+		}
+		return xerrors.Errorf("unknown packing instruction of %s", reflect.TypeOf(msg).String())
+	}
+
+	err := UnmarshalNative(body.Message, packingInstruction, msg)
+	if err != nil && body.IntInfo < 0 {
+		return nil
+	}
+	return err
+}
+
+// getNativeStructInfo returns how the struct type is packed in native protocol
+func getNativeStructInfo(t reflect.Type) (*nativeStructInfo, error) {
+	nativeStructInfoCacheMutex.Lock()
+	defer nativeStructInfoCacheMutex.Unlock()
+
+	if info, ok := nativeStructInfoCache[t]; ok {
+		return info, nil
+	}
+
+	info := &nativeStructInfo{}
+	err := addNativeFields(info, t, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nativeStructInfoCache[t] = info
+	return info, nil
+}
+
+// addNativeFields adds fields of the struct type, fields of embedded structs are promoted
+func addNativeFields(info *nativeStructInfo, t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if (!field.IsExported() && !field.Anonymous) || field.Name == irodsTagXMLNameKey {
+			continue
+		}
+
+		tag := field.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		omitEmpty := false
+		text := false
+		innerXML := false
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "":
+			case "omitempty":
+				omitEmpty = true
+			case "innerxml":
+				text = true
+				innerXML = true
+			case "chardata":
+				text = true
+			default:
+				return xerrors.Errorf("unsupported xml option %s of field %s", option, field.Name)
+			}
+		}
+
+		if text {
+			if field.Type.Kind() != reflect.String {
+				return xerrors.Errorf("text field %s must be a string, but %s", field.Name, field.Type.String())
+			}
+			info.TextField = fieldIndex
+			info.InnerXML = innerXML
+			continue
+		}
+
+		if strings.Contains(name, ">") {
+			return xerrors.Errorf("unsupported xml path %s of field %s", name, field.Name)
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && len(name) == 0 && fieldType.Kind() == reflect.Struct {
+			err := addNativeFields(info, fieldType, fieldIndex)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(name) == 0 {
+			name = getNativeElementName(field)
+		}
+
+		info.Fields = append(info.Fields, nativeField{
+			Name:      name,
+			Index:     fieldIndex,
+			OmitEmpty: omitEmpty,
+		})
+	}
+
+	return nil
+}
+
+// getNativeElementName returns the element name of a field without a name in the tag, XMLName of the field type or the field name
+func getNativeElementName(field reflect.StructField) string {
+	elemType := field.Type
+	for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array {
+		elemType = elemType.Elem()
+	}
+
+	if elemType.Kind() == reflect.Struct {
+		if xmlNameField, ok := elemType.FieldByName(irodsTagXMLNameKey); ok {
+			name, _, _ := strings.Cut(xmlNameField.Tag.Get("xml"), ",")
+			if len(name) > 0 {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+// getNativeFieldValue returns the field of the struct, invalid if an embedded pointer on the way is nil
+func getNativeFieldValue(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// getNativeFieldTarget returns the field of the struct to set, allocating embedded pointers on the way
+func getNativeFieldTarget(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// isEmptyNativeValue returns true if the value is empty for omitempty, as encoding/xml does
+func isEmptyNativeValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// appendNativeElements appends elements of the field value, slices are repeated elements and nil values are absent
+func appendNativeElements(elements []reflect.Value, v reflect.Value, omitEmpty bool) []reflect.Value {
+	if !v.IsValid() {
+		return elements
+	}
+
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return elements
+		}
+		v = v.Elem()
+	}
+
+	if omitEmpty && isEmptyNativeValue(v) {
+		return elements
+	}
+
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			elements = appendNativeElements(elements, v.Index(i), false)
+		}
+		return elements
+	}
+
+	return append(elements, v)
+}
+
+// getNativeText returns the text of a scalar value, as encoding/xml marshals it
+func getNativeText(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(getNativeBytes(v)), nil
+		}
+	case reflect.Struct:
+		info, err := getNativeStructInfo(v.Type())
+		if err != nil {
+			return "", err
+		}
+
+		if info.TextField != nil {
+			text := getNativeFieldValue(v, info.TextField).String()
+			if info.InnerXML {
+				return unescapeNativeXMLText(text)
+			}
+			return text, nil
+		}
+	}
+	return "", xerrors.Errorf("failed to get text of %s", v.Type().String())
+}
+
+// unescapeNativeXMLText returns text of the inner XML, as parsed when XML is translated into native packing
+func unescapeNativeXMLText(innerXML string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	text := strings.Builder{}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return text.String(), nil
+		}
+
+		if err != nil {
+			return "", xerrors.Errorf("failed to parse inner xml: %w", err)
+		}
+
+		if charData, ok := token.(xml.CharData); ok {
+			text.Write(charData)
+		}
+	}
+}
+
+// escapeNativeXMLText returns inner XML of the text, as written when native packing is translated into XML
+func escapeNativeXMLText(text string) (string, error) {
+	buffer := bytes.Buffer{}
+	encoder := xml.NewEncoder(&buffer)
+	err := encoder.EncodeToken(xml.CharData(text))
+	if err != nil {
+		return "", xerrors.Errorf("failed to escape xml text: %w", err)
+	}
+
+	err = encoder.Flush()
+	if err != nil {
+		return "", xerrors.Errorf("failed to escape xml text: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+// getNativeBytes returns bytes of a byte slice or array
+func getNativeBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+
+	data := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(data), v)
+	return data
+}
+
+// getNativeInt returns the int of a scalar value
+func getNativeInt(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), nil
+	}
+
+	text, err := getNativeText(v)
+	if err != nil {
+		return 0, err
+	}
+
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
+
+// packNativeValue packs the struct value with the packing instruction, v is invalid for an absent struct
+func packNativeValue(buffer *bytes.Buffer, name string, v reflect.Value, parentScope *nativeScope) error {
+	items, err := getNativePackingItems(name)
+	if err != nil {
+		return err
+	}
+
+	elementsByName := map[string][]reflect.Value{}
+	if v.IsValid() {
+		info, err := getNativeStructInfo(v.Type())
+		if err != nil {
+			return err
+		}
+
+		for _, field := range info.Fields {
+			elementsByName[field.Name] = appendNativeElements(elementsByName[field.Name], getNativeFieldValue(v, field.Index), field.OmitEmpty)
+		}
+	}
+
+	scope := newNativeScope(parentScope)
+
+	// the same struct can appear multiple times, e.g., DataObjCopyInp_PI
+	used := map[string]int{}
+
+	for idx := range items {
+		item := &items[idx]
+
+		elements := elementsByName[item.Name]
+		offset := used[item.Name]
+		if offset < len(elements) {
+			elements = elements[offset:]
+		} else {
+			elements = []reflect.Value{}
+		}
+
+		if item.Pointer && len(elements) == 0 {
+			buffer.WriteString(nativeNullPointerString)
+			buffer.WriteByte(0)
+			continue
+		}
+
+		count, err := getNativeElementCount(item, scope)
+		if err != nil {
+			return err
+		}
+
+		if item.Type == "struct" && !item.Pointer && len(item.ArrayDims) == 0 {
+			count = 1
+		}
+
+		used[item.Name] += count
+
+		switch item.Type {
+		case "int", "int16", "double", "rlong":
+			for i := 0; i < count; i++ {
+				value := int64(0)
+				if i < len(elements) {
+					value, err = getNativeInt(elements[i])
+					if err != nil {
+						return xerrors.Errorf("failed to get %s value: %w", item.Name, err)
+					}
+				}
+
+				switch item.Type {
+				case "int":
+					binary.Write(buffer, binary.BigEndian, int32(value))
+				case "int16":
+					binary.Write(buffer, binary.BigEndian, int16(value))
+				default:
+					binary.Write(buffer, binary.BigEndian, value)
+				}
+
+				if count == 1 && !item.Pointer {
+					scope.values[item.Name] = int(value)
+				}
+			}
+		case "str":
+			for i := 0; i < count; i++ {
+				if i < len(elements) {
+					text, err := getNativeText(elements[i])
+					if err != nil {
+						return xerrors.Errorf("failed to get %s value: %w", item.Name, err)
+					}
+					buffer.WriteString(text)
+				}
+				buffer.WriteByte(0)
+			}
+		case "bin":
+			data := []byte{}
+			if len(elements) > 0 {
+				data, err = getNativeBinData(elements[0])
+				if err != nil {
+					return xerrors.Errorf("failed to get %s value: %w", item.Name, err)
+				}
+			}
+
+			padded := make([]byte, count)
+			copy(padded, data)
+			buffer.Write(padded)
+		case "struct":
+			for i := 0; i < count; i++ {
+				element := reflect.Value{}
+				if i < len(elements) {
+					element = elements[i]
+					if element.Kind() != reflect.Struct {
+						return xerrors.Errorf("failed to pack %s, %s is not a struct", item.Name, element.Type().String())
+					}
+				}
+
+				err = packNativeValue(buffer, item.Name, element, scope)
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			return xerrors.Errorf("unsupported packing type %s", item.Type)
+		}
+	}
+
+	return nil
+}
+
+// getNativeBinData returns binary data of the value, byte slices as they are and strings decoded from base64
+func getNativeBinData(v reflect.Value) ([]byte, error) {
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8 {
+		return getNativeBytes(v), nil
+	}
+
+	text, err := getNativeText(v)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode base64: %w", err)
+	}
+	return data, nil
+}
+
+// getNativeElementTarget returns the value to set an element of the field to, slices get a new element appended
+func getNativeElementTarget(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		return getNativeElementTarget(v.Index(v.Len() - 1))
+	}
+	return v
+}
+
+// setNativeText sets the scalar value to the text, as encoding/xml unmarshals it
+func setNativeText(v reflect.Value, text string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value := int64(0)
+		if trimmed := strings.TrimSpace(text); len(trimmed) > 0 {
+			parsed, err := strconv.ParseInt(trimmed, 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			value = parsed
+		}
+		v.SetInt(value)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value := uint64(0)
+		if trimmed := strings.TrimSpace(text); len(trimmed) > 0 {
+			parsed, err := strconv.ParseUint(trimmed, 10, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			value = parsed
+		}
+		v.SetUint(value)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		value := float64(0)
+		if trimmed := strings.TrimSpace(text); len(trimmed) > 0 {
+			parsed, err := strconv.ParseFloat(trimmed, v.Type().Bits())
+			if err != nil {
+				return err
+			}
+			value = parsed
+		}
+		v.SetFloat(value)
+		return nil
+	case reflect.Bool:
+		value := false
+		if trimmed := strings.TrimSpace(text); len(trimmed) > 0 {
+			parsed, err := strconv.ParseBool(trimmed)
+			if err != nil {
+				return err
+			}
+			value = parsed
+		}
+		v.SetBool(value)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(text))
+			return nil
+		}
+	case reflect.Struct:
+		info, err := getNativeStructInfo(v.Type())
+		if err != nil {
+			return err
+		}
+
+		if info.TextField != nil {
+			if info.InnerXML {
+				escaped, err := escapeNativeXMLText(text)
+				if err != nil {
+					return err
+				}
+				text = escaped
+			}
+
+			getNativeFieldTarget(v, info.TextField).SetString(text)
+			return nil
+		}
+	}
+	return xerrors.Errorf("failed to set text to %s", v.Type().String())
+}
+
+// unpackNativeValue unpacks the packing instruction into the struct value
+// items without fields are read and dropped
+func unpackNativeValue(reader *bytes.Reader, name string, v reflect.Value, parentScope *nativeScope) error {
+	items, err := getNativePackingItems(name)
+	if err != nil {
+		return err
+	}
+
+	info, err := getNativeStructInfo(v.Type())
+	if err != nil {
+		return err
+	}
+
+	// encoding/xml records the element name, name of the packing instruction
+	if xmlName := v.FieldByName("XMLName"); xmlName.IsValid() && xmlName.Type() == reflect.TypeOf(xml.Name{}) {
+		xmlName.Set(reflect.ValueOf(xml.Name{Local: name}))
+	}
+
+	fieldsByName := map[string][]int{}
+	for _, field := range info.Fields {
+		if _, ok := fieldsByName[field.Name]; !ok {
+			fieldsByName[field.Name] = field.Index
+		}
+	}
+
+	scope := newNativeScope(parentScope)
+
+	for idx := range items {
+		item := &items[idx]
+
+		if item.Pointer && isNativeNullPointer(reader) {
+			continue
+		}
+
+		count, err := getNativeElementCount(item, scope)
+		if err != nil {
+			return err
+		}
+
+		if item.Type == "struct" && !item.Pointer && len(item.ArrayDims) == 0 {
+			count = 1
+		}
+
+		fieldIndex, hasField := fieldsByName[item.Name]
+		getTarget := func() reflect.Value {
+			return getNativeElementTarget(getNativeFieldTarget(v, fieldIndex))
+		}
+
+		switch item.Type {
+		case "int", "int16", "double", "rlong":
+			for i := 0; i < count; i++ {
+				value := int64(0)
+				switch item.Type {
+				case "int":
+					intValue := int32(0)
+					err = binary.Read(reader, binary.BigEndian, &intValue)
+					value = int64(intValue)
+				case "int16":
+					int16Value := int16(0)
+					err = binary.Read(reader, binary.BigEndian, &int16Value)
+					value = int64(int16Value)
+				default:
+					err = binary.Read(reader, binary.BigEndian, &value)
+				}
+
+				if err != nil {
+					return xerrors.Errorf("failed to read %s: %w", item.Name, err)
+				}
+
+				if count == 1 && !item.Pointer {
+					scope.values[item.Name] = int(value)
+				}
+
+				if hasField {
+					err = setNativeText(getTarget(), strconv.FormatInt(value, 10))
+					if err != nil {
+						return xerrors.Errorf("failed to set %s: %w", item.Name, err)
+					}
+				}
+			}
+		case "str":
+			for i := 0; i < count; i++ {
+				value, err := readNativeString(reader)
+				if err != nil {
+					return xerrors.Errorf("failed to read %s: %w", item.Name, err)
+				}
+
+				if hasField {
+					err = setNativeText(getTarget(), value)
+					if err != nil {
+						return xerrors.Errorf("failed to set %s: %w", item.Name, err)
+					}
+				}
+			}
+		case "bin":
+			data := make([]byte, count)
+			_, err = io.ReadFull(reader, data)
+			if err != nil {
+				return xerrors.Errorf("failed to read %s: %w", item.Name, err)
+			}
+
+			if hasField {
+				target := getTarget()
+				if target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.Uint8 {
+					target.SetBytes(data)
+				} else {
+					err = setNativeText(target, base64.StdEncoding.EncodeToString(data))
+					if err != nil {
+						return xerrors.Errorf("failed to set %s: %w", item.Name, err)
+					}
+				}
+			}
+		case "struct":
+			for i := 0; i < count; i++ {
+				if !hasField {
+					// read and drop
+					_, err = unpackNativeStruct(reader, item.Name, scope)
+					if err != nil {
+						return err
+					}
+					continue
+				}
+
+				target := getTarget()
+				if target.Kind() != reflect.Struct {
+					return xerrors.Errorf("failed to unpack %s, %s is not a struct", item.Name, target.Type().String())
+				}
+
+				err = unpackNativeValue(reader, item.Name, target, scope)
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			return xerrors.Errorf("unsupported packing type %s", item.Type)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package message
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// Messages declare how they are packed with struct tags, then use MarshalIRODSMessage and UnmarshalIRODSMessage
+// instead of building message bodies by hand.
+//
+//	XMLName xml.Name `xml:"STR_PI" irods:"pi=STR_PI"` - XML name, and packing instruction for native protocol if different
+//	Result  int      `xml:"-" irods:"intinfo"`       - IntInfo of message body, e.g., result of a response
+//	Data    []byte   `xml:"-" irods:"bs"`            - Bs (binary stream) of message body
+//
+// All other fields are packed in XML, or in native protocol if the connection uses it, see MarshalNative.
+const (
+	irodsTagKey        string = "irods"
+	irodsTagIntInfo    string = "intinfo"
+	irodsTagBs         string = "bs"
+	irodsTagPIPrefix   string = "pi="
+	irodsTagXMLNameKey string = "XMLName"
+)
+
+// irodsMessageLayout describes struct tags of a message type
+type irodsMessageLayout struct {
+	IntInfoField       int // -1 if not defined
+	BsField            int // -1 if not defined
+	PackingInstruction string
+	HasXMLFields       bool
+}
+
+var (
+	irodsMessageLayoutCache      = map[reflect.Type]*irodsMessageLayout{}
+	irodsMessageLayoutCacheMutex = sync.Mutex{}
+)
+
+// getIRODSMessageLayout returns the layout of the message from struct tags
+func getIRODSMessageLayout(t reflect.Type) (*irodsMessageLayout, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, xerrors.Errorf("message must be a struct, but %s", t.Kind().String())
+	}
+
+	irodsMessageLayoutCacheMutex.Lock()
+	defer irodsMessageLayoutCacheMutex.Unlock()
+
+	if layout, ok := irodsMessageLayoutCache[t]; ok {
+		return layout, nil
+	}
+
+	layout := &irodsMessageLayout{
+		IntInfoField: -1,
+		BsField:      -1,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(irodsTagKey)
+
+		if field.Name == irodsTagXMLNameKey {
+			layout.HasXMLFields = true
+			continue
+		}
+
+		switch tag {
+		case irodsTagIntInfo:
+			switch field.Type.Kind() {
+			case reflect.Int, reflect.Int32, reflect.Int64:
+			default:
+				return nil, xerrors.Errorf("field %s tagged %s must be an int, but %s", field.Name, tag, field.Type.Kind().String())
+			}
+			layout.IntInfoField = i
+		case irodsTagBs:
+			if field.Type != reflect.TypeOf([]byte{}) {
+				return nil, xerrors.Errorf("field %s tagged %s must be []byte, but %s", field.Name, tag, field.Type.String())
+			}
+			layout.BsField = i
+		case "":
+			if field.IsExported() && field.Tag.Get("xml") != "-" {
+				layout.HasXMLFields = true
+			}
+		default:
+			return nil, xerrors.Errorf("unknown tag %s of field %s", tag, field.Name)
+		}
+	}
+
+	// XMLName may be promoted from an embedded struct
+	if field, ok := t.FieldByName(irodsTagXMLNameKey); ok {
+		layout.PackingInstruction = strings.Split(field.Tag.Get("xml"), ",")[0]
+
+		tag := field.Tag.Get(irodsTagKey)
+		if strings.HasPrefix(tag, irodsTagPIPrefix) {
+			layout.PackingInstruction = tag[len(irodsTagPIPrefix):]
+		}
+	}
+
+	irodsMessageLayoutCache[t] = layout
+	return layout, nil
+}
+
+// getIRODSMessageValue returns struct value of a message pointer
+func getIRODSMessageValue(msg interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, xerrors.Errorf("message must be a non-nil pointer to a struct")
+	}
+	return v.Elem(), nil
+}
+
+// MarshalIRODSMessage builds an API request message from a struct that declares packing with struct tags
+func MarshalIRODSMessage(msg interface{}, apiNumber common.APINumber) (*IRODSMessage, error) {
+	v, err := getIRODSMessageValue(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := getIRODSMessageLayout(v.Type())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get layout of %s: %w", v.Type().Name(), err)
+	}
+
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: nil,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(apiNumber),
+	}
+
+	if layout.HasXMLFields {
+		bytes, err := xml.Marshal(msg)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+		}
+		msgBody.Message = bytes
+	}
+
+	if layout.BsField >= 0 {
+		msgBody.Bs = v.Field(layout.BsField).Bytes()
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
+
+// UnmarshalIRODSMessage fills a struct that declares packing with struct tags from an API response message
+func UnmarshalIRODSMessage(msgIn *IRODSMessage, msg interface{}) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	v, err := getIRODSMessageValue(msg)
+	if err != nil {
+		return err
+	}
+
+	layout, err := getIRODSMessageLayout(v.Type())
+	if err != nil {
+		return xerrors.Errorf("failed to get layout of %s: %w", v.Type().Name(), err)
+	}
+
+	if layout.HasXMLFields && len(msgIn.Body.Message) > 0 {
+		err = unmarshalIRODSMessageBody(msgIn.Body, msg)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal irods message: %w", err)
+		}
+	}
+
+	if layout.IntInfoField >= 0 {
+		v.Field(layout.IntInfoField).SetInt(int64(msgIn.Body.IntInfo))
+	}
+
+	if layout.BsField >= 0 {
+		v.Field(layout.BsField).SetBytes(msgIn.Body.Bs)
+	}
+
+	return nil
+}
+
+// getTaggedPackingInstructionName returns the packing instruction name declared with struct tags
+func getTaggedPackingInstructionName(t reflect.Type) string {
+	layout, err := getIRODSMessageLayout(t)
+	if err != nil {
+		return ""
+	}
+	return layout.PackingInstruction
+}
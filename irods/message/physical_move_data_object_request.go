@@ -0,0 +1,65 @@
+package message
+
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessagePhysicalMoveDataObjectRequest stores data object physical move request
+type IRODSMessagePhysicalMoveDataObjectRequest IRODSMessageDataObjectRequest
+
+// NewIRODSMessagePhysicalMoveDataObjectRequest creates a IRODSMessagePhysicalMoveDataObjectRequest message
+func NewIRODSMessagePhysicalMoveDataObjectRequest(path string, srcResource string, destResource string) *IRODSMessagePhysicalMoveDataObjectRequest {
+	request := &IRODSMessagePhysicalMoveDataObjectRequest{
+		Path:          path,
+		CreateMode:    0,
+		OpenFlags:     0,
+		Offset:        0,
+		Size:          -1,
+		Threads:       0,
+		OperationType: int(common.OPER_TYPE_PHYMV),
+		KeyVals: IRODSMessageSSKeyVal{
+			Length: 0,
+		},
+	}
+
+	if len(srcResource) > 0 {
+		request.KeyVals.Add(string(common.RESC_NAME_KW), srcResource)
+	}
+
+	if len(destResource) > 0 {
+		request.KeyVals.Add(string(common.DEST_RESC_NAME_KW), destResource)
+	}
+
+	return request
+}
+
+// AddKeyVal adds a key-value pair
+func (msg *IRODSMessagePhysicalMoveDataObjectRequest) AddKeyVal(key common.KeyWord, val string) {
+	msg.KeyVals.Add(string(key), val)
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessagePhysicalMoveDataObjectRequest) GetBytes() ([]byte, error) {
+	xmlBytes, err := xml.Marshal(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessagePhysicalMoveDataObjectRequest) FromBytes(bytes []byte) error {
+	err := xml.Unmarshal(bytes, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessagePhysicalMoveDataObjectRequest) GetMessage() (*IRODSMessage, error) {
+	return MarshalIRODSMessage(msg, common.DATA_OBJ_PHYMV_AN)
+}
@@ -0,0 +1,25 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+)
+
+// IRODSMessagePhysicalMoveDataObjectResponse stores data object physical move response
+type IRODSMessagePhysicalMoveDataObjectResponse struct {
+	// empty structure
+	Result int `xml:"-" irods:"intinfo"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessagePhysicalMoveDataObjectResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessagePhysicalMoveDataObjectResponse) FromMessage(msgIn *IRODSMessage) error {
+	return UnmarshalIRODSMessage(msgIn, msg)
+}
@@ -16,7 +16,7 @@ type IRODSMessagePortalResponse struct {
 	// error if result < 0
 	// data is included if result == 0
 	// any value >= 0 is fine
-	Result int `xml:"-"`
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 type IRODSMessagePortList struct {
@@ -39,18 +39,5 @@ func (msg *IRODSMessagePutDataObjectResponse) FromBytes(bytes []byte) error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessagePutDataObjectResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-
-	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
-		if err != nil {
-			return xerrors.Errorf("failed to get irods message from message body")
-		}
-	}
-
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
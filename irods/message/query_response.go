@@ -18,7 +18,7 @@ type IRODSMessageQueryResponse struct {
 	SQLResult      []IRODSMessageSQLResult `xml:"SqlResult_PI"`
 
 	// stores error result
-	Result int `xml:"-"`
+	Result int `xml:"-" irods:"intinfo"`
 }
 
 // GetBytes returns byte array
@@ -49,18 +49,5 @@ func (msg *IRODSMessageQueryResponse) FromBytes(bytes []byte) error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageQueryResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-
-	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
-		if err != nil {
-			return xerrors.Errorf("failed to get irods message from message body")
-		}
-	}
-
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
@@ -29,28 +29,7 @@ func (msg *IRODSMessageQuerySpecialCollection) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageQuerySpecialCollection) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      nil,
-		IntInfo: int32(common.QUERY_SPEC_COLL_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.QUERY_SPEC_COLL_AN)
 }
 
 // FromMessage returns struct from IRODSMessage
@@ -59,7 +38,7 @@ func (msg *IRODSMessageQuerySpecialCollection) FromMessage(msgIn *IRODSMessage)
 		return xerrors.Errorf("empty message body")
 	}
 
-	err := msg.FromBytes(msgIn.Body.Message)
+	err := unmarshalIRODSMessageBody(msgIn.Body, msg)
 	if err != nil {
 		return xerrors.Errorf("failed to get irods message from message body")
 	}
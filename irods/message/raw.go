@@ -0,0 +1,73 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageRawRequest stores a request to an arbitrary API, with a message body that is already packed
+type IRODSMessageRawRequest struct {
+	APINumber common.APINumber
+	Message   []byte // packed in the protocol of the connection, XML or native
+	Bs        []byte // can be null
+}
+
+// IRODSMessageRawResponse stores a response of an arbitrary API, with a message body that is not parsed
+type IRODSMessageRawResponse struct {
+	Result  int
+	Message []byte // packed in the protocol of the connection, XML or native
+	Error   []byte
+	Bs      []byte
+}
+
+// NewIRODSMessageRawRequest creates a IRODSMessageRawRequest message
+func NewIRODSMessageRawRequest(apiNumber common.APINumber, packedInput []byte, bs []byte) *IRODSMessageRawRequest {
+	return &IRODSMessageRawRequest{
+		APINumber: apiNumber,
+		Message:   packedInput,
+		Bs:        bs,
+	}
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageRawRequest) GetMessage() (*IRODSMessage, error) {
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: msg.Message,
+		Error:   nil,
+		Bs:      msg.Bs,
+		IntInfo: int32(msg.APINumber),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageRawResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageRawResponse) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	msg.Result = int(msgIn.Body.IntInfo)
+	msg.Message = msgIn.Body.Message
+	msg.Error = msgIn.Body.Error
+	msg.Bs = msgIn.Body.Bs
+	return nil
+}
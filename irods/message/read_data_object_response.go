@@ -3,14 +3,13 @@ package message
 import (
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/types"
-	"golang.org/x/xerrors"
 )
 
 // IRODSMessageReadDataObjectResponse stores data object read response
 type IRODSMessageReadDataObjectResponse struct {
 	// empty structure
-	Result int
-	Data   []byte
+	Result int    `xml:"-" irods:"intinfo"`
+	Data   []byte `xml:"-" irods:"bs"`
 }
 
 // CheckError returns error if server returned an error
@@ -23,11 +22,5 @@ func (msg *IRODSMessageReadDataObjectResponse) CheckError() error {
 
 // FromMessage returns struct from IRODSMessage
 func (msg *IRODSMessageReadDataObjectResponse) FromMessage(msgIn *IRODSMessage) error {
-	if msgIn.Body == nil {
-		return xerrors.Errorf("empty message body")
-	}
-
-	msg.Result = int(msgIn.Body.IntInfo)
-	msg.Data = msgIn.Body.Bs
-	return nil
+	return UnmarshalIRODSMessage(msgIn, msg)
 }
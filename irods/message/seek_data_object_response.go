@@ -38,7 +38,7 @@ func (msg *IRODSMessageSeekDataObjectResponse) FromMessage(msgIn *IRODSMessage)
 	}
 
 	if msgIn.Body.Message != nil {
-		err := msg.FromBytes(msgIn.Body.Message)
+		err := unmarshalIRODSMessageBody(msgIn.Body, msg)
 		if err != nil {
 			return xerrors.Errorf("failed to get irods message from message body")
 		}
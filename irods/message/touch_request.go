@@ -0,0 +1,103 @@
+package message
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageTouchOptions stores options of touch request
+type IRODSMessageTouchOptions struct {
+	NoCreate          bool  `json:"no_create"`
+	SecondsSinceEpoch int64 `json:"seconds_since_epoch,omitempty"`
+}
+
+// IRODSMessageTouchRequest stores touch request, updates modify time of a data object or a collection
+// Uses JSON, not XML
+// Supported v4.2.9 or above
+type IRODSMessageTouchRequest struct {
+	Path    string                   `json:"logical_path"`
+	Options IRODSMessageTouchOptions `json:"options"`
+}
+
+// NewIRODSMessageTouchRequest creates a IRODSMessageTouchRequest message
+// modify time is set to the given seconds since epoch, or current time if secondsSinceEpoch is zero
+func NewIRODSMessageTouchRequest(path string, noCreate bool, secondsSinceEpoch int64) *IRODSMessageTouchRequest {
+	request := &IRODSMessageTouchRequest{
+		Path: path,
+		Options: IRODSMessageTouchOptions{
+			NoCreate:          noCreate,
+			SecondsSinceEpoch: secondsSinceEpoch,
+		},
+	}
+
+	return request
+}
+
+// getMessageBody returns BinBytesBuf carrying the message in JSON
+func (msg *IRODSMessageTouchRequest) getMessageBody() (interface{}, error) {
+	return newIRODSMessageBinBytesBufJSON(msg)
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageTouchRequest) GetBytes() ([]byte, error) {
+	binBytesBuf, err := newIRODSMessageBinBytesBufJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	xmlBytes, err := xml.Marshal(binBytesBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageTouchRequest) FromBytes(bytes []byte) error {
+	binBytesBuf := IRODSMessageBinBytesBuf{}
+	err := xml.Unmarshal(bytes, &binBytesBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+
+	jsonBody, err := base64.StdEncoding.DecodeString(binBytesBuf.Data)
+	if err != nil {
+		return xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	err = json.Unmarshal(jsonBody, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal json to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageTouchRequest) GetMessage() (*IRODSMessage, error) {
+	bytes, err := msg.GetBytes()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
+	}
+
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: bytes,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.TOUCH_APN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
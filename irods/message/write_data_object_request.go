@@ -11,7 +11,7 @@ import (
 // type IRODSMessageWriteDataObjectRequest IRODSMessageOpenedDataObjectRequest
 type IRODSMessageWriteDataObjectRequest struct {
 	IRODSMessageOpenedDataObjectRequest
-	Data []byte `xml:"-"`
+	Data []byte `xml:"-" irods:"bs"`
 }
 
 // NewIRODSMessageWriteDataObjectRequest creates a IRODSMessageWriteDataObjectRequest message
@@ -59,26 +59,5 @@ func (msg *IRODSMessageWriteDataObjectRequest) FromBytes(bytes []byte) error {
 
 // GetMessage builds a message
 func (msg *IRODSMessageWriteDataObjectRequest) GetMessage() (*IRODSMessage, error) {
-	bytes, err := msg.GetBytes()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
-	}
-
-	msgBody := IRODSMessageBody{
-		Type:    RODS_MESSAGE_API_REQ_TYPE,
-		Message: bytes,
-		Error:   nil,
-		Bs:      msg.Data,
-		IntInfo: int32(common.DATA_OBJ_WRITE_AN),
-	}
-
-	msgHeader, err := msgBody.BuildHeader()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
-	}
-
-	return &IRODSMessage{
-		Header: msgHeader,
-		Body:   &msgBody,
-	}, nil
+	return MarshalIRODSMessage(msg, common.DATA_OBJ_WRITE_AN)
 }
@@ -0,0 +1,186 @@
+package message
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// iRODS servers do not use standard XML.
+// They only decode the named entities &amp; &lt; &gt; &quot; &apos; and pass everything else through as is.
+// Servers older than 4.2.9 also map ` to &apos; (and back) instead of '.
+var (
+	// escapes from xml.Marshal
+	escQuot = []byte("&#34;") // shorter than "&quot;", \"
+	escApos = []byte("&#39;") // shorter than "&apos;", \'
+	escTab  = []byte("&#x9;")
+	escNL   = []byte("&#xA;")
+	escCR   = []byte("&#xD;")
+	escFFFD = []byte("\uFFFD") // Unicode replacement character
+
+	// escapes for irods
+	irodsEscQuot = []byte("&quot;")
+	irodsEscApos = []byte("&apos;")
+)
+
+// ErrInvalidUTF8 is returned if an invalid utf-8 character is found.
+var ErrInvalidUTF8 = xerrors.Errorf("invalid utf-8 character")
+
+// TalksCorrectXML returns true if the server escapes ' as &apos; (4.2.9 or higher)
+func TalksCorrectXML(version *types.IRODSVersion) bool {
+	if version == nil {
+		// We don't know the server version yet, assume the best
+		return true
+	}
+
+	if !strings.HasPrefix(version.ReleaseVersion, "rods") {
+		// Strange, but hopefully it talks correct xml
+		return true
+	}
+
+	return version.HasHigherVersionThan(4, 2, 9)
+}
+
+// EncodeIRODSXML translates output of xml.Marshal into XML that iRODS understands.
+func EncodeIRODSXML(in []byte, correctXML bool) ([]byte, error) {
+	buf := in
+	out := &bytes.Buffer{}
+
+	for len(buf) > 0 {
+		switch {
+		// turn &#34; into &quot;
+		case bytes.HasPrefix(buf, escQuot):
+			out.Write(irodsEscQuot)
+			buf = buf[len(escQuot):]
+		// turn &#39; into &apos; or '
+		case bytes.HasPrefix(buf, escApos):
+			if correctXML {
+				out.Write(irodsEscApos)
+			} else {
+				out.WriteByte('\'')
+			}
+			buf = buf[len(escApos):]
+		// irods does not decode encoded tabs
+		case bytes.HasPrefix(buf, escTab):
+			out.WriteByte('\t')
+			buf = buf[len(escTab):]
+		// irods does not decode encoded carriage returns
+		case bytes.HasPrefix(buf, escCR):
+			out.WriteByte('\r')
+			buf = buf[len(escCR):]
+		// irods does not decode encoded newlines
+		case bytes.HasPrefix(buf, escNL):
+			out.WriteByte('\n')
+			buf = buf[len(escNL):]
+		// turn ` into &apos;
+		case buf[0] == '`' && !correctXML:
+			out.Write(irodsEscApos)
+			buf = buf[1:]
+		// pass utf8 characters
+		default:
+			r, size := utf8.DecodeRune(buf)
+			if r == utf8.RuneError && size == 1 {
+				return in, ErrInvalidUTF8
+			}
+
+			out.Write(buf[:size])
+			buf = buf[size:]
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// EncodeIRODSXMLForPassword translates output of xml.Marshal into XML that iRODS understands.
+// Quotes are not escaped at all as passwords are not decoded by iRODS.
+func EncodeIRODSXMLForPassword(in []byte) ([]byte, error) {
+	buf := in
+	out := &bytes.Buffer{}
+
+	for len(buf) > 0 {
+		switch {
+		// turn &#34; into \"
+		case bytes.HasPrefix(buf, escQuot):
+			out.WriteByte('"')
+			buf = buf[len(escQuot):]
+		// turn &#39; into \'
+		case bytes.HasPrefix(buf, escApos):
+			out.WriteByte('\'')
+			buf = buf[len(escApos):]
+		// irods does not decode encoded tabs
+		case bytes.HasPrefix(buf, escTab):
+			out.WriteByte('\t')
+			buf = buf[len(escTab):]
+		// irods does not decode encoded carriage returns
+		case bytes.HasPrefix(buf, escCR):
+			out.WriteByte('\r')
+			buf = buf[len(escCR):]
+		// irods does not decode encoded newlines
+		case bytes.HasPrefix(buf, escNL):
+			out.WriteByte('\n')
+			buf = buf[len(escNL):]
+		// pass utf8 characters
+		default:
+			r, size := utf8.DecodeRune(buf)
+			if r == utf8.RuneError && size == 1 {
+				return in, ErrInvalidUTF8
+			}
+
+			out.Write(buf[:size])
+			buf = buf[size:]
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// DecodeIRODSXML translates XML received from iRODS into valid XML for xml.Unmarshal.
+func DecodeIRODSXML(in []byte, correctXML bool) ([]byte, error) {
+	buf := in
+	out := &bytes.Buffer{}
+
+	for len(buf) > 0 {
+		switch {
+		// turn &apos; into `
+		case bytes.HasPrefix(buf, irodsEscApos) && !correctXML:
+			out.WriteByte('`')
+			buf = buf[len(irodsEscApos):]
+		// turn ' into &#39;
+		case buf[0] == '\'' && !correctXML:
+			out.Write(escApos)
+			buf = buf[1:]
+		// xml.Unmarshal turns raw carriage returns into newlines
+		case buf[0] == '\r':
+			out.Write(escCR)
+			buf = buf[1:]
+		// check utf8 characters for validity
+		default:
+			r, size := utf8.DecodeRune(buf)
+			if r == utf8.RuneError && size == 1 {
+				return in, ErrInvalidUTF8
+			}
+
+			if isValidXMLChar(r) {
+				out.Write(buf[:size])
+			} else {
+				out.Write(escFFFD)
+			}
+
+			buf = buf[size:]
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+func isValidXMLChar(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
@@ -2,6 +2,8 @@ package session
 
 import (
 	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/util"
 )
 
 const (
@@ -19,6 +21,12 @@ const (
 	IRODSSessionTimeoutDefault = 5 * time.Minute
 	// IRODSSessionTCPBufferSizeDefault is a default value of tcp buffer size
 	IRODSSessionTCPBufferSizeDefault = 4 * 1024 * 1024
+	// IRODSSessionManagerConnectionMaxTotalDefault is a default value of total connections across sessions in a manager
+	IRODSSessionManagerConnectionMaxTotalDefault = 100
+	// IRODSSessionManagerSessionIdleTimeoutDefault is a default value of session idle timeout in a manager
+	IRODSSessionManagerSessionIdleTimeoutDefault = 30 * time.Minute
+	// IRODSSessionServerUnavailableRetryIntervalDefault is a default value of the interval to retry connecting to unavailable servers
+	IRODSSessionServerUnavailableRetryIntervalDefault = 10 * time.Second
 )
 
 // IRODSSessionConfig is for session configuration
@@ -33,6 +41,33 @@ type IRODSSessionConfig struct {
 	ConnectionMaxIdle      int
 	TcpBufferSize          int
 	StartNewTransaction    bool
+	// ConnectionKeepaliveInterval is an interval to send heartbeats on idle connections and occupied connections not in use, 0 disables keepalive
+	ConnectionKeepaliveInterval time.Duration
+	// RequestRateLimiter limits the rate of requests of all connections in the session, nil for no limit
+	// share a limiter between sessions to limit their total rate
+	RequestRateLimiter *util.RateLimiter
+	// TransferBufferSize is a size of data read or written in a request during transfers, common.ReadWriteBufferSize if not positive
+	// larger sizes need fewer round trips on high latency networks
+	TransferBufferSize int
+	// TransferBlockSize is a min length of data transferred by a task of parallel transfers, util.TransferTaskMinLength if not positive
+	// it decides the number of tasks when the number is not given
+	TransferBlockSize int64
+	// TLSSessionCacheSize is a number of TLS sessions kept for resumption by connections of the session
+	// connection.TLSSessionCacheSizeDefault if 0, TLS sessions are not resumed if negative
+	TLSSessionCacheSize int
+	// LazyConnection defers creating ConnectionInitNumber connections until the first connection is acquired
+	// connection errors are returned by the first operation instead of at creation of the session
+	LazyConnection bool
+	// OperationScheduling queues acquisitions of connections by OperationPriority once ConnectionMax connections are in use
+	// connections in use are shared instead if not set
+	OperationScheduling bool
+	// InteractiveConnectionReserve is a number of connections left for interactive operations when OperationScheduling is set
+	InteractiveConnectionReserve int
+	// ServerUnavailableRetryTimeout is a max time to keep retrying to connect while the server refuses connections or is down, e.g., in maintenance
+	// acquisitions of connections fail right away if not positive
+	ServerUnavailableRetryTimeout time.Duration
+	// ServerUnavailableRetryInterval is an interval between retries, IRODSSessionServerUnavailableRetryIntervalDefault if not positive
+	ServerUnavailableRetryInterval time.Duration
 }
 
 // NewIRODSSessionConfig create a IRODSSessionConfig
@@ -70,3 +105,159 @@ func NewIRODSSessionConfigWithDefault(applicationName string) *IRODSSessionConfi
 		StartNewTransaction:    true,
 	}
 }
+
+// IRODSSessionManagerConfig is for session manager configuration
+type IRODSSessionManagerConfig struct {
+	SessionConfig      *IRODSSessionConfig
+	ConnectionMaxTotal int           // max number of connections across all sessions, 0 for no limit
+	SessionIdleTimeout time.Duration // a session with no connections in use is released after the timeout, 0 disables eviction
+}
+
+// NewIRODSSessionManagerConfigWithDefault create a IRODSSessionManagerConfig with a default settings
+func NewIRODSSessionManagerConfigWithDefault(applicationName string) *IRODSSessionManagerConfig {
+	return &IRODSSessionManagerConfig{
+		SessionConfig:      NewIRODSSessionConfigWithDefault(applicationName),
+		ConnectionMaxTotal: IRODSSessionManagerConnectionMaxTotalDefault,
+		SessionIdleTimeout: IRODSSessionManagerSessionIdleTimeoutDefault,
+	}
+}
+
+// IRODSSessionConfigOption sets an option of IRODSSessionConfig
+type IRODSSessionConfigOption func(config *IRODSSessionConfig)
+
+// NewIRODSSessionConfigWithOptions create a IRODSSessionConfig with default settings overridden by options
+func NewIRODSSessionConfigWithOptions(applicationName string, options ...IRODSSessionConfigOption) *IRODSSessionConfig {
+	config := NewIRODSSessionConfigWithDefault(applicationName)
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithConnectionErrorTimeout sets how long to retry connecting after errors
+func WithConnectionErrorTimeout(timeout time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionErrorTimeout = timeout
+	}
+}
+
+// WithConnectionInitNumber sets the number of connections to create in advance
+func WithConnectionInitNumber(number int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionInitNumber = number
+	}
+}
+
+// WithConnectionLifespan sets the lifespan of a connection
+func WithConnectionLifespan(lifespan time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionLifespan = lifespan
+	}
+}
+
+// WithOperationTimeout sets the timeout of an operation
+func WithOperationTimeout(timeout time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.OperationTimeout = timeout
+	}
+}
+
+// WithConnectionIdleTimeout sets how long an idle connection is kept
+func WithConnectionIdleTimeout(timeout time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionIdleTimeout = timeout
+	}
+}
+
+// WithConnectionMax sets the max number of connections, IRODSSessionConnectionMaxMin at least
+func WithConnectionMax(connectionMax int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		if connectionMax < IRODSSessionConnectionMaxMin {
+			connectionMax = IRODSSessionConnectionMaxMin
+		}
+		config.ConnectionMax = connectionMax
+	}
+}
+
+// WithConnectionMaxIdle sets the max number of idle connections
+func WithConnectionMaxIdle(connectionMaxIdle int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionMaxIdle = connectionMaxIdle
+	}
+}
+
+// WithTCPBufferSize sets the tcp buffer size of connections
+func WithTCPBufferSize(size int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.TcpBufferSize = size
+	}
+}
+
+// WithStartNewTransaction sets whether to start a new transaction, required for mysql iCAT backend
+func WithStartNewTransaction(startNewTransaction bool) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.StartNewTransaction = startNewTransaction
+	}
+}
+
+// WithConnectionKeepaliveInterval sets the interval to send heartbeats on connections, 0 disables keepalive
+func WithConnectionKeepaliveInterval(interval time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ConnectionKeepaliveInterval = interval
+	}
+}
+
+// WithRequestRateLimit limits requests of the session to requestsPerSecond with bursts up to burst requests, 0 for no limit
+func WithRequestRateLimit(requestsPerSecond float64, burst int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		if requestsPerSecond <= 0 {
+			config.RequestRateLimiter = nil
+			return
+		}
+		config.RequestRateLimiter = util.NewRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+// WithTransferBufferSize sets the size of data read or written in a request during transfers
+func WithTransferBufferSize(size int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.TransferBufferSize = size
+	}
+}
+
+// WithTransferBlockSize sets the min length of data transferred by a task of parallel transfers
+func WithTransferBlockSize(size int64) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.TransferBlockSize = size
+	}
+}
+
+// WithTLSSessionCacheSize sets the number of TLS sessions kept for resumption, negative disables resumption
+func WithTLSSessionCacheSize(size int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.TLSSessionCacheSize = size
+	}
+}
+
+// WithLazyConnection sets whether to defer connecting to the server until the first operation
+func WithLazyConnection(lazy bool) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.LazyConnection = lazy
+	}
+}
+
+// WithOperationScheduling queues acquisitions of connections by priority, interactiveReserve connections are left for interactive operations
+func WithOperationScheduling(interactiveReserve int) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.OperationScheduling = true
+		config.InteractiveConnectionReserve = interactiveReserve
+	}
+}
+
+// WithServerUnavailableRetry keeps retrying to connect up to timeout at the interval while the server is unavailable, e.g., in maintenance
+func WithServerUnavailableRetry(timeout time.Duration, interval time.Duration) IRODSSessionConfigOption {
+	return func(config *IRODSSessionConfig) {
+		config.ServerUnavailableRetryTimeout = timeout
+		config.ServerUnavailableRetryInterval = interval
+	}
+}
@@ -0,0 +1,369 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sessionManagerEvictionIntervalMax = 1 * time.Minute
+)
+
+// managedSession is a session in a manager
+type managedSession struct {
+	session    *IRODSSession
+	lastAccess time.Time
+}
+
+// IRODSSessionManager holds sessions for multiple accounts
+// connections of all sessions are counted against a single limit, and idle connections of an account are evicted to make room for other accounts
+type IRODSSessionManager struct {
+	config        *IRODSSessionManagerConfig
+	sessions      map[string]*managedSession
+	terminateChan chan bool
+	terminated    bool
+	mutex         sync.Mutex
+
+	// connections is guarded by connectionMutex since it is updated while connection pools are locked
+	connections     int
+	connectionMutex sync.Mutex
+}
+
+var (
+	// accountCredentialKey keys fingerprints of credentials, so account keys do not expose hashes of passwords
+	accountCredentialKey = newAccountCredentialKey()
+)
+
+func newAccountCredentialKey() []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	if err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return key
+}
+
+// getAccountIdentityKey returns a key identifying the user and server of the account, without credentials
+func getAccountIdentityKey(account *types.IRODSAccount) string {
+	return fmt.Sprintf("%s#%s:%s#%s@%s:%d", account.ProxyUser, account.ProxyZone, account.ClientUser, account.ClientZone, account.Host, account.Port)
+}
+
+// getAccountCredentialFingerprint returns a fingerprint of credentials of the account
+func getAccountCredentialFingerprint(account *types.IRODSAccount) string {
+	mac := hmac.New(sha256.New, accountCredentialKey)
+	mac.Write([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s", account.AuthenticationScheme, account.Password, account.PamToken, account.Ticket)))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// GetAccountKey returns a key identifying sessions of the account in a manager
+// the key includes a fingerprint of credentials, so an account with wrong credentials never gets a session authenticated by others
+func GetAccountKey(account *types.IRODSAccount) string {
+	key := fmt.Sprintf("%s?credential=%s", getAccountIdentityKey(account), getAccountCredentialFingerprint(account))
+	if account.UseTicket() {
+		key += "&ticket=" + account.Ticket
+	}
+	return key
+}
+
+// NewIRODSSessionManager creates a new IRODSSessionManager
+func NewIRODSSessionManager(config *IRODSSessionManagerConfig) *IRODSSessionManager {
+	manager := &IRODSSessionManager{
+		config:        config,
+		sessions:      map[string]*managedSession{},
+		terminateChan: make(chan bool),
+		terminated:    false,
+		mutex:         sync.Mutex{},
+
+		connections:     0,
+		connectionMutex: sync.Mutex{},
+	}
+
+	if config.SessionIdleTimeout > 0 {
+		interval := config.SessionIdleTimeout
+		if interval > sessionManagerEvictionIntervalMax {
+			interval = sessionManagerEvictionIntervalMax
+		}
+
+		go func() {
+			ticker := time.NewTicker(interval)
+
+			for {
+				select {
+				case <-manager.terminateChan:
+					ticker.Stop()
+					return
+				case <-ticker.C:
+					manager.EvictIdleSessions()
+				}
+			}
+		}()
+	}
+
+	return manager
+}
+
+// GetConfig returns the config
+func (manager *IRODSSessionManager) GetConfig() *IRODSSessionManagerConfig {
+	return manager.config
+}
+
+// GetSession returns the session for the account, creates a new session if not exists
+// the session may be released once it becomes idle, so callers must not keep it for later use
+func (manager *IRODSSessionManager) GetSession(account *types.IRODSAccount) (*IRODSSession, error) {
+	// key the session by the normalized account, e.g., "alice#zone" and "alice" in zone are the same user
+	normalizedAccount := *account
+	err := normalizedAccount.Normalize()
+	if err != nil {
+		return nil, xerrors.Errorf("invalid account: %w", err)
+	}
+	account = &normalizedAccount
+
+	key := GetAccountKey(account)
+
+	sess, err := manager.getManagedSession(key)
+	if err != nil || sess != nil {
+		return sess, err
+	}
+
+	// create the session without holding the lock, connecting to a slow server must not block other accounts
+	newSess, err := newIRODSSession(account, manager.config.SessionConfig, nil, manager)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a session for %s: %w", getAccountIdentityKey(account), err)
+	}
+
+	manager.mutex.Lock()
+
+	if manager.terminated {
+		manager.mutex.Unlock()
+		newSess.Release()
+		return nil, xerrors.Errorf("failed to get a session: %w", types.NewSessionShutdownError())
+	}
+
+	// another caller may have created a session for the account meanwhile
+	if managed, ok := manager.sessions[key]; ok {
+		managed.lastAccess = time.Now()
+		manager.mutex.Unlock()
+		newSess.Release()
+		return managed.session, nil
+	}
+
+	manager.sessions[key] = &managedSession{
+		session:    newSess,
+		lastAccess: time.Now(),
+	}
+
+	manager.mutex.Unlock()
+	return newSess, nil
+}
+
+// getManagedSession returns the session for the account key, nil if not exists
+func (manager *IRODSSessionManager) getManagedSession(key string) (*IRODSSession, error) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if manager.terminated {
+		return nil, xerrors.Errorf("failed to get a session: %w", types.NewSessionShutdownError())
+	}
+
+	if managed, ok := manager.sessions[key]; ok {
+		managed.lastAccess = time.Now()
+		return managed.session, nil
+	}
+	return nil, nil
+}
+
+// updateSessionKey moves the session to the key of its new account, e.g., after a password is rotated
+func (manager *IRODSSessionManager) updateSessionKey(sess *IRODSSession, oldKey string, newKey string) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if oldKey == newKey {
+		return nil
+	}
+
+	if managed, ok := manager.sessions[newKey]; ok && managed.session != sess {
+		return xerrors.Errorf("failed to update account of a managed session, another session uses the account")
+	}
+
+	managed, ok := manager.sessions[oldKey]
+	if !ok || managed.session != sess {
+		// released already
+		return nil
+	}
+
+	delete(manager.sessions, oldKey)
+	manager.sessions[newKey] = managed
+	return nil
+}
+
+// GetSessionByKey returns the session for the account key, false if not exists
+func (manager *IRODSSessionManager) GetSessionByKey(key string) (*IRODSSession, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if managed, ok := manager.sessions[key]; ok {
+		managed.lastAccess = time.Now()
+		return managed.session, true
+	}
+	return nil, false
+}
+
+// GetAccountKeys returns account keys of all sessions
+func (manager *IRODSSessionManager) GetAccountKeys() []string {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	keys := []string{}
+	for key := range manager.sessions {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ReleaseSession releases the session for the account key
+func (manager *IRODSSessionManager) ReleaseSession(key string) {
+	manager.mutex.Lock()
+	managed, ok := manager.sessions[key]
+	if ok {
+		delete(manager.sessions, key)
+	}
+	manager.mutex.Unlock()
+
+	if ok {
+		managed.session.StopAcquire()
+		managed.session.Release()
+	}
+}
+
+// EvictIdleSessions releases sessions that have no connections in use and have not been accessed for the idle timeout
+// returns the number of sessions released
+func (manager *IRODSSessionManager) EvictIdleSessions() int {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "IRODSSessionManager",
+		"function": "EvictIdleSessions",
+	})
+
+	if manager.config.SessionIdleTimeout <= 0 {
+		return 0
+	}
+
+	manager.mutex.Lock()
+
+	now := time.Now()
+	evictedSessions := []*IRODSSession{}
+	for key, managed := range manager.sessions {
+		if managed.lastAccess.Add(manager.config.SessionIdleTimeout).After(now) {
+			continue
+		}
+
+		if managed.session.stopAcquireIfIdle() {
+			logger.Debugf("Releasing an idle session for %s", key)
+			delete(manager.sessions, key)
+			evictedSessions = append(evictedSessions, managed.session)
+		}
+	}
+
+	manager.mutex.Unlock()
+
+	for _, sess := range evictedSessions {
+		sess.Release()
+	}
+
+	return len(evictedSessions)
+}
+
+// evictIdleConnection closes the oldest idle connection of sessions other than the given session
+// returns false if there's no idle connection to close
+func (manager *IRODSSessionManager) evictIdleConnection(exclude *IRODSSession) bool {
+	manager.mutex.Lock()
+	pools := []*ConnectionPool{}
+	for _, managed := range manager.sessions {
+		if managed.session != exclude {
+			pools = append(pools, managed.session.connectionPool)
+		}
+	}
+	manager.mutex.Unlock()
+
+	var oldestPool *ConnectionPool
+	var oldestTime time.Time
+	for _, pool := range pools {
+		idleTime, ok := pool.getOldestIdleTime()
+		if !ok {
+			continue
+		}
+
+		if oldestPool == nil || idleTime.Before(oldestTime) {
+			oldestPool = pool
+			oldestTime = idleTime
+		}
+	}
+
+	if oldestPool == nil {
+		return false
+	}
+
+	return oldestPool.EvictIdleConnection()
+}
+
+// ReserveConnection reserves a slot for a new connection, implements ConnectionLimiter
+func (manager *IRODSSessionManager) ReserveConnection() error {
+	manager.connectionMutex.Lock()
+	defer manager.connectionMutex.Unlock()
+
+	if manager.config.ConnectionMaxTotal > 0 && manager.connections >= manager.config.ConnectionMaxTotal {
+		return types.NewConnectionPoolFullError(manager.connections, manager.config.ConnectionMaxTotal)
+	}
+
+	manager.connections++
+	return nil
+}
+
+// FreeConnection frees the slot of a closed connection, implements ConnectionLimiter
+func (manager *IRODSSessionManager) FreeConnection() {
+	manager.connectionMutex.Lock()
+	defer manager.connectionMutex.Unlock()
+
+	if manager.connections > 0 {
+		manager.connections--
+	}
+}
+
+// ConnectionTotal returns the number of connections across all sessions
+func (manager *IRODSSessionManager) ConnectionTotal() int {
+	manager.connectionMutex.Lock()
+	defer manager.connectionMutex.Unlock()
+
+	return manager.connections
+}
+
+// Release releases all sessions
+func (manager *IRODSSessionManager) Release() {
+	manager.mutex.Lock()
+	if manager.terminated {
+		manager.mutex.Unlock()
+		return
+	}
+
+	manager.terminated = true
+	close(manager.terminateChan)
+
+	sessions := manager.sessions
+	manager.sessions = map[string]*managedSession{}
+	manager.mutex.Unlock()
+
+	for _, managed := range sessions {
+		managed.session.StopAcquire()
+		managed.session.Release()
+	}
+}
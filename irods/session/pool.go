@@ -8,6 +8,7 @@ import (
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	"github.com/cyverse/go-irodsclient/irods/metrics"
 	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
 	"golang.org/x/xerrors"
 
 	log "github.com/sirupsen/logrus"
@@ -15,22 +16,40 @@ import (
 
 // ConnectionPoolConfig is for connection pool configuration
 type ConnectionPoolConfig struct {
-	Account          *types.IRODSAccount
-	ApplicationName  string
-	InitialCap       int
-	MaxIdle          int
-	MaxCap           int           // output warning if total connections exceeds maxcap number
-	Lifespan         time.Duration // if a connection exceeds its lifespan, the connection will die
-	IdleTimeout      time.Duration // if there's no activity on a connection for the timeout time, the connection will die
-	OperationTimeout time.Duration // if there's no response for the timeout time, the request will fail
-	TcpBufferSize    int
+	Account            *types.IRODSAccount
+	ApplicationName    string
+	InitialCap         int
+	MaxIdle            int
+	MaxCap             int           // output warning if total connections exceeds maxcap number
+	Lifespan           time.Duration // if a connection exceeds its lifespan, the connection will die
+	IdleTimeout        time.Duration // if there's no activity on a connection for the timeout time, the connection will die
+	OperationTimeout   time.Duration // if there's no response for the timeout time, the request will fail
+	TcpBufferSize      int
+	KeepaliveInterval  time.Duration              // if set, idle and unused occupied connections receive a heartbeat at the interval to survive server-side idle timeouts
+	Limiter            ConnectionLimiter          // if set, new connections are counted against a limit shared with other pools
+	RateLimiter        *util.RateLimiter          // if set, requests of all connections wait for the limiter
+	TransferBufferSize int                        // size of data read or written in a request during transfers, the default is used if not positive
+	TLSConfigCache     *connection.TLSConfigCache // if set, connections share the TLS config and resume TLS sessions of each other
+	Lazy               bool                       // if set, initial connections are created at the first Get instead of at creation of the pool
+}
+
+// ConnectionLimiter limits the total number of connections across connection pools
+type ConnectionLimiter interface {
+	// ReserveConnection reserves a slot for a new connection, returns ConnectionPoolFullError if no slot is available
+	ReserveConnection() error
+	// FreeConnection frees the slot of a closed connection
+	FreeConnection()
 }
 
 // ConnectionPool is a struct for connection pool
 type ConnectionPool struct {
 	config              *ConnectionPoolConfig
-	idleConnections     *list.List // list of *connection.IRODSConnection
+	idleConnections     *list.List                                // list of *connection.IRODSConnection
+	idleSince           map[*connection.IRODSConnection]time.Time // last use of idle connections that received keepalive requests
 	occupiedConnections map[*connection.IRODSConnection]bool
+	staleConnections    map[*connection.IRODSConnection]bool // connections in use authenticated with an old account, closed when returned
+	keepaliveConns      map[*connection.IRODSConnection]bool // idle connections taken out of the idle list during keepalive
+	initialized         bool                                 // initial connections are created
 	metrics             *metrics.IRODSMetrics
 	mutex               sync.Mutex
 	terminateChan       chan bool
@@ -43,15 +62,21 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 		config:              config,
 		idleConnections:     list.New(),
 		occupiedConnections: map[*connection.IRODSConnection]bool{},
+		staleConnections:    map[*connection.IRODSConnection]bool{},
+		keepaliveConns:      map[*connection.IRODSConnection]bool{},
+		idleSince:           map[*connection.IRODSConnection]time.Time{},
+		initialized:         false,
 		metrics:             metrics,
 		mutex:               sync.Mutex{},
 		terminateChan:       make(chan bool),
 		terminated:          false,
 	}
 
-	err := pool.init()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to init connection pool: %w", err)
+	if !config.Lazy {
+		err := pool.init()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to init connection pool: %w", err)
+		}
 	}
 
 	go func() {
@@ -75,14 +100,16 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 					// if the front conn expired idle timeout, continue next
 					idleConnObj := elem.Value
 					if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-						if idleConn.GetLastSuccessfulAccess().Add(pool.config.IdleTimeout).Before(now) {
+						if pool.getIdleSince(idleConn).Add(pool.config.IdleTimeout).Before(now) {
 							// timeout
 							pool.idleConnections.Remove(elem)
-							idleConn.Disconnect()
+							delete(pool.idleSince, idleConn)
+							pool.closeConnection(idleConn)
 						} else if idleConn.GetCreationTime().Add(pool.config.Lifespan).Before(now) {
 							// too old
 							pool.idleConnections.Remove(elem)
-							idleConn.Disconnect()
+							delete(pool.idleSince, idleConn)
+							pool.closeConnection(idleConn)
 						} else {
 							break
 						}
@@ -97,9 +124,120 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 		}
 	}()
 
+	if config.KeepaliveInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(config.KeepaliveInterval)
+
+			for {
+				select {
+				case <-pool.terminateChan:
+					ticker.Stop()
+					return
+				case <-ticker.C:
+					pool.keepaliveIdleConnections()
+					pool.keepaliveOccupiedConnections()
+				}
+			}
+		}()
+	}
+
 	return pool, nil
 }
 
+// getIdleSince returns the time the idle connection was used last, not counting keepalive requests
+func (pool *ConnectionPool) getIdleSince(conn *connection.IRODSConnection) time.Time {
+	if idleSince, ok := pool.idleSince[conn]; ok {
+		return idleSince
+	}
+	return conn.GetLastSuccessfulAccess()
+}
+
+// keepaliveIdleConnections sends heartbeats on idle connections that have not been accessed for the keepalive interval
+func (pool *ConnectionPool) keepaliveIdleConnections() {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "ConnectionPool",
+		"function": "keepaliveIdleConnections",
+	})
+
+	pool.mutex.Lock()
+
+	// take connections out of idle list, so they are not given out during keepalive
+	now := time.Now()
+	keepaliveConnections := []*connection.IRODSConnection{}
+	elem := pool.idleConnections.Front()
+	for elem != nil {
+		next := elem.Next()
+
+		if idleConn, ok := elem.Value.(*connection.IRODSConnection); ok {
+			if idleConn.GetLastSuccessfulAccess().Add(pool.config.KeepaliveInterval).Before(now) {
+				pool.idleConnections.Remove(elem)
+				pool.idleSince[idleConn] = pool.getIdleSince(idleConn)
+				pool.keepaliveConns[idleConn] = true
+				keepaliveConnections = append(keepaliveConnections, idleConn)
+			}
+		}
+
+		elem = next
+	}
+
+	pool.mutex.Unlock()
+
+	// put back in reverse order to keep older connections in front
+	for idx := len(keepaliveConnections) - 1; idx >= 0; idx-- {
+		keepaliveConn := keepaliveConnections[idx]
+		err := keepaliveConn.Heartbeat()
+
+		pool.mutex.Lock()
+		delete(pool.keepaliveConns, keepaliveConn)
+
+		// the account may have been updated during keepalive
+		stale := pool.staleConnections[keepaliveConn]
+		delete(pool.staleConnections, keepaliveConn)
+
+		if err != nil || stale || pool.terminated {
+			if err != nil {
+				logger.WithError(err).Debug("failed to send keepalive on an idle connection. discarding...")
+			}
+
+			delete(pool.idleSince, keepaliveConn)
+			pool.closeConnection(keepaliveConn)
+		} else {
+			pool.idleConnections.PushFront(keepaliveConn)
+		}
+		pool.mutex.Unlock()
+	}
+}
+
+// keepaliveOccupiedConnections sends heartbeats on occupied connections that are not in use
+// e.g., control connections held during long transfers on other connections
+func (pool *ConnectionPool) keepaliveOccupiedConnections() {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "ConnectionPool",
+		"function": "keepaliveOccupiedConnections",
+	})
+
+	pool.mutex.Lock()
+
+	// occupied connections are accessed by their owners, check them only under their locks
+	keepaliveConnections := []*connection.IRODSConnection{}
+	for occupiedConn := range pool.occupiedConnections {
+		keepaliveConnections = append(keepaliveConnections, occupiedConn)
+	}
+
+	pool.mutex.Unlock()
+
+	for _, keepaliveConn := range keepaliveConnections {
+		// connections in use are skipped, they are not idle
+		_, err := keepaliveConn.TryHeartbeat()
+		if err != nil {
+			// the owner finds the connection broken on next use
+			logger.WithError(err).Debug("failed to send heartbeat on an occupied connection")
+		}
+	}
+}
+
 // Release releases all resources
 func (pool *ConnectionPool) Release() {
 	pool.mutex.Lock()
@@ -110,7 +248,7 @@ func (pool *ConnectionPool) Release() {
 	}
 
 	pool.terminated = true
-	pool.terminateChan <- true
+	close(pool.terminateChan)
 
 	for pool.idleConnections.Len() > 0 {
 		elem := pool.idleConnections.Front()
@@ -120,16 +258,18 @@ func (pool *ConnectionPool) Release() {
 
 		idleConnObj := pool.idleConnections.Remove(elem)
 		if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-			idleConn.Disconnect()
+			pool.closeConnection(idleConn)
 		}
 	}
 
 	for occupiedConn := range pool.occupiedConnections {
-		occupiedConn.Disconnect()
+		pool.closeConnection(occupiedConn)
 	}
 
 	// clear
 	pool.occupiedConnections = map[*connection.IRODSConnection]bool{}
+	pool.staleConnections = map[*connection.IRODSConnection]bool{}
+	pool.idleSince = map[*connection.IRODSConnection]time.Time{}
 
 	pool.metrics.ClearConnections()
 }
@@ -138,12 +278,27 @@ func (pool *ConnectionPool) init() error {
 	pool.mutex.Lock()
 	defer pool.mutex.Unlock()
 
+	return pool.initConnections()
+}
+
+// initConnections creates initial connections, the pool must be locked
+// connections created before a failure are kept, so only missing connections are created at the next call
+func (pool *ConnectionPool) initConnections() error {
 	// create connections
-	for i := 0; i < pool.config.InitialCap; i++ {
+	for i := pool.idleConnections.Len(); i < pool.config.InitialCap; i++ {
+		err := pool.reserveConnection()
+		if err != nil {
+			return xerrors.Errorf("failed to create a new connection: %w", err)
+		}
+
 		newConn := connection.NewIRODSConnectionWithMetrics(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName, pool.metrics)
 		newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
-		err := newConn.Connect()
+		newConn.SetRateLimiter(pool.config.RateLimiter)
+		newConn.SetTLSConfigCache(pool.config.TLSConfigCache)
+		newConn.SetTransferBufferSize(pool.config.TransferBufferSize)
+		err = newConn.Connect()
 		if err != nil {
+			pool.freeConnection()
 			pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
 			return xerrors.Errorf("failed to connect to irods server: %w", err)
 		}
@@ -151,6 +306,7 @@ func (pool *ConnectionPool) init() error {
 		pool.idleConnections.PushBack(newConn)
 	}
 
+	pool.initialized = true
 	return nil
 }
 
@@ -170,6 +326,13 @@ func (pool *ConnectionPool) Get() (*connection.IRODSConnection, bool, error) {
 		return nil, false, types.NewConnectionPoolFullError(len(pool.occupiedConnections), pool.config.MaxCap)
 	}
 
+	if !pool.initialized {
+		err := pool.initConnections()
+		if err != nil {
+			return nil, false, xerrors.Errorf("failed to init connection pool: %w", err)
+		}
+	}
+
 	var err error
 	// check if there's idle connection
 	if pool.idleConnections.Len() > 0 {
@@ -179,6 +342,8 @@ func (pool *ConnectionPool) Get() (*connection.IRODSConnection, bool, error) {
 		if elem != nil {
 			idleConnObj := pool.idleConnections.Remove(elem)
 			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
+				delete(pool.idleSince, idleConn)
+
 				if idleConn.IsConnected() {
 					// move to occupied connections
 					pool.occupiedConnections[idleConn] = true
@@ -189,15 +354,25 @@ func (pool *ConnectionPool) Get() (*connection.IRODSConnection, bool, error) {
 				}
 
 				logger.Warn("failed to reuse an idle connection because it is already disconnected. discarding...")
+				pool.closeConnection(idleConn)
 			}
 		}
 	}
 
 	// create a new if not exists
+	err = pool.reserveConnection()
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to create a new connection: %w", err)
+	}
+
 	newConn := connection.NewIRODSConnectionWithMetrics(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName, pool.metrics)
 	newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
+	newConn.SetRateLimiter(pool.config.RateLimiter)
+	newConn.SetTLSConfigCache(pool.config.TLSConfigCache)
+	newConn.SetTransferBufferSize(pool.config.TransferBufferSize)
 	err = newConn.Connect()
 	if err != nil {
+		pool.freeConnection()
 		pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
 		return nil, false, xerrors.Errorf("failed to connect to irods server: %w", err)
 	}
@@ -224,6 +399,13 @@ func (pool *ConnectionPool) GetNew() (*connection.IRODSConnection, error) {
 		return nil, types.NewConnectionPoolFullError(len(pool.occupiedConnections), pool.config.MaxCap)
 	}
 
+	if !pool.initialized {
+		err := pool.initConnections()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to init connection pool: %w", err)
+		}
+	}
+
 	// full - close an idle connection and create a new one
 	if pool.idleConnections.Len() > 0 {
 		// close
@@ -231,9 +413,8 @@ func (pool *ConnectionPool) GetNew() (*connection.IRODSConnection, error) {
 		if elem != nil {
 			idleConnObj := pool.idleConnections.Remove(elem)
 			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-				if idleConn.IsConnected() {
-					idleConn.Disconnect()
-				}
+				delete(pool.idleSince, idleConn)
+				pool.closeConnection(idleConn)
 			}
 		}
 	}
@@ -241,10 +422,19 @@ func (pool *ConnectionPool) GetNew() (*connection.IRODSConnection, error) {
 	// create a new one
 	if len(pool.occupiedConnections)+pool.idleConnections.Len() < pool.config.MaxCap {
 		// create a new one
+		err := pool.reserveConnection()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create a new connection: %w", err)
+		}
+
 		newConn := connection.NewIRODSConnection(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName)
 		newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
-		err := newConn.Connect()
+		newConn.SetRateLimiter(pool.config.RateLimiter)
+		newConn.SetTLSConfigCache(pool.config.TLSConfigCache)
+		newConn.SetTransferBufferSize(pool.config.TransferBufferSize)
+		err = newConn.Connect()
 		if err != nil {
+			pool.freeConnection()
 			pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
 			return nil, xerrors.Errorf("failed to connect to irods server: %w", err)
 		}
@@ -282,13 +472,23 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 
 	if !conn.IsConnected() {
 		logger.Warn("failed to return the connection because it is already closed. discarding...")
+		delete(pool.staleConnections, conn)
+		pool.closeConnection(conn)
+		return nil
+	}
+
+	// do not return if the connection is authenticated with an old account
+	if pool.staleConnections[conn] {
+		delete(pool.staleConnections, conn)
+		pool.closeConnection(conn)
+		logger.Debug("Returning and destroying a connection of an old account")
 		return nil
 	}
 
 	// do not return if the connection is too old
 	now := time.Now()
 	if conn.GetCreationTime().Add(pool.config.Lifespan).Before(now) {
-		conn.Disconnect()
+		pool.closeConnection(conn)
 		logger.Debug("Returning and destroying an old connection")
 		return nil
 	}
@@ -302,7 +502,8 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 		if elem != nil {
 			idleConnObj := pool.idleConnections.Remove(elem)
 			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-				idleConn.Disconnect()
+				delete(pool.idleSince, idleConn)
+				pool.closeConnection(idleConn)
 			}
 		}
 	}
@@ -318,13 +519,119 @@ func (pool *ConnectionPool) Discard(conn *connection.IRODSConnection) {
 	defer pool.mutex.Unlock()
 
 	// find it from occupied map
+	if _, ok := pool.occupiedConnections[conn]; !ok {
+		// released already
+		if conn.IsConnected() {
+			conn.Disconnect()
+		}
+		return
+	}
+
 	delete(pool.occupiedConnections, conn)
+	delete(pool.staleConnections, conn)
 
 	pool.metrics.DecreaseConnectionsOccupied(1)
 
+	pool.closeConnection(conn)
+}
+
+// UpdateAccount makes new connections authenticate with the account
+// idle connections are closed, and connections in use are closed when returned
+func (pool *ConnectionPool) UpdateAccount(account *types.IRODSAccount) {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "ConnectionPool",
+		"function": "UpdateAccount",
+	})
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.config.Account = account
+
+	for pool.idleConnections.Len() > 0 {
+		elem := pool.idleConnections.Front()
+		if elem == nil {
+			break
+		}
+
+		idleConnObj := pool.idleConnections.Remove(elem)
+		if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
+			delete(pool.idleSince, idleConn)
+			pool.closeConnection(idleConn)
+		}
+	}
+
+	for occupiedConn := range pool.occupiedConnections {
+		pool.staleConnections[occupiedConn] = true
+	}
+
+	// idle connections in keepalive are closed when keepalive puts them back
+	for keepaliveConn := range pool.keepaliveConns {
+		pool.staleConnections[keepaliveConn] = true
+	}
+
+	// initial connections are created again with the account
+	pool.initialized = false
+
+	logger.Debugf("Updated account, %d connections in use will be closed when returned", len(pool.staleConnections))
+}
+
+// reserveConnection reserves a slot for a new connection from the limiter
+func (pool *ConnectionPool) reserveConnection() error {
+	if pool.config.Limiter == nil {
+		return nil
+	}
+	return pool.config.Limiter.ReserveConnection()
+}
+
+// freeConnection frees a slot reserved from the limiter
+func (pool *ConnectionPool) freeConnection() {
+	if pool.config.Limiter != nil {
+		pool.config.Limiter.FreeConnection()
+	}
+}
+
+// closeConnection disconnects the connection that is taken out of the pool
+func (pool *ConnectionPool) closeConnection(conn *connection.IRODSConnection) {
 	if conn.IsConnected() {
 		conn.Disconnect()
 	}
+	pool.freeConnection()
+}
+
+// getOldestIdleTime returns the last access time of the oldest idle connection, false if there's no idle connection
+func (pool *ConnectionPool) getOldestIdleTime() (time.Time, bool) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	elem := pool.idleConnections.Front()
+	if elem == nil {
+		return time.Time{}, false
+	}
+
+	if idleConn, ok := elem.Value.(*connection.IRODSConnection); ok {
+		return pool.getIdleSince(idleConn), true
+	}
+	return time.Time{}, true
+}
+
+// EvictIdleConnection closes the oldest idle connection, returns false if there's no idle connection
+func (pool *ConnectionPool) EvictIdleConnection() bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	elem := pool.idleConnections.Front()
+	if elem == nil {
+		return false
+	}
+
+	idleConnObj := pool.idleConnections.Remove(elem)
+	if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
+		delete(pool.idleSince, idleConn)
+		pool.closeConnection(idleConn)
+	}
+	return true
 }
 
 // OpenConnections returns total number of connections
@@ -0,0 +1,177 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// OperationPriority is a priority of operations competing for connections of a session
+type OperationPriority int
+
+const (
+	// OperationPriorityBackground is for bulk operations, e.g., parallel transfers
+	OperationPriorityBackground OperationPriority = 0
+	// OperationPriorityNormal is for operations acquiring connections without a priority
+	OperationPriorityNormal OperationPriority = 1
+	// OperationPriorityInteractive is for operations users wait for, e.g., stat and list
+	OperationPriorityInteractive OperationPriority = 2
+)
+
+// operationWaiter is an operation waiting in the queue of OperationScheduler
+type operationWaiter struct {
+	priority OperationPriority
+	ready    chan struct{}
+}
+
+// OperationScheduler queues operations waiting for connections, higher priorities first, then in arrival order
+// operations below OperationPriorityInteractive cannot use the last reserved slots
+type OperationScheduler struct {
+	slots    int
+	reserved int
+	running  int
+	waiters  []*operationWaiter
+	mutex    sync.Mutex
+}
+
+// NewOperationScheduler creates a new OperationScheduler running up to slots operations at the same time
+// reserved slots are kept for interactive operations, at least a slot is left for other operations
+func NewOperationScheduler(slots int, reserved int) *OperationScheduler {
+	if slots < 1 {
+		slots = 1
+	}
+
+	if reserved < 0 {
+		reserved = 0
+	}
+
+	if reserved > slots-1 {
+		reserved = slots - 1
+	}
+
+	return &OperationScheduler{
+		slots:    slots,
+		reserved: reserved,
+		waiters:  []*operationWaiter{},
+	}
+}
+
+// canRun returns true if an operation of the priority can take a slot now
+func (scheduler *OperationScheduler) canRun(priority OperationPriority) bool {
+	limit := scheduler.slots
+	if priority < OperationPriorityInteractive {
+		limit -= scheduler.reserved
+	}
+	return scheduler.running < limit
+}
+
+// canRunNow returns true if an operation of the priority can take a slot without overtaking waiters of the same or higher priorities
+func (scheduler *OperationScheduler) canRunNow(priority OperationPriority) bool {
+	if len(scheduler.waiters) > 0 && scheduler.waiters[0].priority >= priority {
+		return false
+	}
+	return scheduler.canRun(priority)
+}
+
+// dispatch gives slots to waiters in order, waiters behind a waiter that cannot run have lower priorities and cannot run either
+func (scheduler *OperationScheduler) dispatch() {
+	for len(scheduler.waiters) > 0 && scheduler.canRun(scheduler.waiters[0].priority) {
+		waiter := scheduler.waiters[0]
+		scheduler.waiters = scheduler.waiters[1:]
+
+		scheduler.running++
+		close(waiter.ready)
+	}
+}
+
+// Acquire waits for a slot until ctx is done
+func (scheduler *OperationScheduler) Acquire(ctx context.Context, priority OperationPriority) error {
+	scheduler.mutex.Lock()
+
+	if scheduler.canRunNow(priority) {
+		scheduler.running++
+		scheduler.mutex.Unlock()
+		return nil
+	}
+
+	waiter := &operationWaiter{
+		priority: priority,
+		ready:    make(chan struct{}),
+	}
+
+	// after waiters of the same priority
+	idx := sort.Search(len(scheduler.waiters), func(i int) bool {
+		return scheduler.waiters[i].priority < priority
+	})
+	scheduler.waiters = append(scheduler.waiters, nil)
+	copy(scheduler.waiters[idx+1:], scheduler.waiters[idx:])
+	scheduler.waiters[idx] = waiter
+
+	scheduler.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		scheduler.mutex.Lock()
+		defer scheduler.mutex.Unlock()
+
+		select {
+		case <-waiter.ready:
+			// got a slot at the same time, give it back
+			scheduler.running--
+		default:
+			for i, w := range scheduler.waiters {
+				if w == waiter {
+					scheduler.waiters = append(scheduler.waiters[:i], scheduler.waiters[i+1:]...)
+					break
+				}
+			}
+		}
+
+		scheduler.dispatch()
+		return xerrors.Errorf("failed to wait for a connection slot: %w", ctx.Err())
+	}
+}
+
+// TryAcquire takes a slot if an operation of the priority can run now without waiting
+func (scheduler *OperationScheduler) TryAcquire(priority OperationPriority) bool {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.canRunNow(priority) {
+		scheduler.running++
+		return true
+	}
+	return false
+}
+
+// Release gives back a slot
+func (scheduler *OperationScheduler) Release() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.running > 0 {
+		scheduler.running--
+	}
+
+	scheduler.dispatch()
+}
+
+// Running returns the number of slots taken
+func (scheduler *OperationScheduler) Running() int {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	return scheduler.running
+}
+
+// Waiting returns the number of operations waiting for slots
+func (scheduler *OperationScheduler) Waiting() int {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	return len(scheduler.waiters)
+}
@@ -1,16 +1,25 @@
 package session
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
 	"github.com/cyverse/go-irodsclient/irods/metrics"
 	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 )
 
+const (
+	// shutdownPollInterval is an interval to check connections in use during shutdown
+	shutdownPollInterval = 50 * time.Millisecond
+)
+
 // TransactionFailureHandler is an handler that is called when transaction operation fails
 type TransactionFailureHandler func(commitFail bool, poormansRollbackFail bool)
 
@@ -19,6 +28,7 @@ type IRODSSession struct {
 	account                   *types.IRODSAccount
 	config                    *IRODSSessionConfig
 	connectionPool            *ConnectionPool
+	tlsConfigCache            *connection.TLSConfigCache // shared by all connections of the session
 	sharedConnections         map[*connection.IRODSConnection]int
 	startNewTransaction       bool
 	commitFail                bool
@@ -32,6 +42,14 @@ type IRODSSession struct {
 	supportParallelUpload    bool
 	supportParallelUploadSet bool
 
+	shuttingDown bool
+
+	// nil if operations are not scheduled, slots taken are counted per connection given out
+	scheduler            *OperationScheduler
+	scheduledConnections map[*connection.IRODSConnection]int
+
+	manager *IRODSSessionManager
+
 	metrics metrics.IRODSMetrics
 	mutex   sync.Mutex
 }
@@ -41,12 +59,31 @@ func NewIRODSSession(account *types.IRODSAccount, config *IRODSSessionConfig) (*
 	return NewIRODSSessionWithAddressResolver(account, config, nil)
 }
 
+// NewIRODSSessionWithOptions create a IRODSSession with default settings overridden by options
+func NewIRODSSessionWithOptions(account *types.IRODSAccount, applicationName string, options ...IRODSSessionConfigOption) (*IRODSSession, error) {
+	return NewIRODSSession(account, NewIRODSSessionConfigWithOptions(applicationName, options...))
+}
+
 // NewIRODSSessionWithAddressResolver create a IRODSSession
 func NewIRODSSessionWithAddressResolver(account *types.IRODSAccount, config *IRODSSessionConfig, addressResolver AddressResolver) (*IRODSSession, error) {
+	return newIRODSSession(account, config, addressResolver, nil)
+}
+
+// newIRODSSession create a IRODSSession, connections are counted against the manager's limit if the manager is given
+func newIRODSSession(account *types.IRODSAccount, config *IRODSSessionConfig, addressResolver AddressResolver, manager *IRODSSessionManager) (*IRODSSession, error) {
+	// normalize a copy once here, connections share the account, and the account may be in use elsewhere
+	normalizedAccount := *account
+	err := normalizedAccount.Normalize()
+	if err != nil {
+		return nil, xerrors.Errorf("invalid account: %w", err)
+	}
+	account = &normalizedAccount
+
 	sess := IRODSSession{
 		account:           account,
 		config:            config,
 		sharedConnections: map[*connection.IRODSConnection]int{},
+		tlsConfigCache:    connection.NewTLSConfigCache(config.TLSSessionCacheSize),
 
 		// transaction
 		startNewTransaction:       config.StartNewTransaction,
@@ -63,9 +100,17 @@ func NewIRODSSessionWithAddressResolver(account *types.IRODSAccount, config *IRO
 
 		metrics: metrics.IRODSMetrics{},
 
+		manager: manager,
+
+		scheduledConnections: map[*connection.IRODSConnection]int{},
+
 		mutex: sync.Mutex{},
 	}
 
+	if config.OperationScheduling {
+		sess.scheduler = NewOperationScheduler(config.ConnectionMax, config.InteractiveConnectionReserve)
+	}
+
 	// resolve host address
 	poolAccount := *account
 	if addressResolver != nil {
@@ -73,15 +118,24 @@ func NewIRODSSessionWithAddressResolver(account *types.IRODSAccount, config *IRO
 	}
 
 	poolConfig := ConnectionPoolConfig{
-		Account:          &poolAccount,
-		ApplicationName:  config.ApplicationName,
-		InitialCap:       config.ConnectionInitNumber,
-		MaxIdle:          config.ConnectionMaxIdle,
-		MaxCap:           config.ConnectionMax,
-		Lifespan:         config.ConnectionLifespan,
-		IdleTimeout:      config.ConnectionIdleTimeout,
-		OperationTimeout: config.OperationTimeout,
-		TcpBufferSize:    config.TcpBufferSize,
+		Account:            &poolAccount,
+		ApplicationName:    config.ApplicationName,
+		InitialCap:         config.ConnectionInitNumber,
+		MaxIdle:            config.ConnectionMaxIdle,
+		MaxCap:             config.ConnectionMax,
+		Lifespan:           config.ConnectionLifespan,
+		IdleTimeout:        config.ConnectionIdleTimeout,
+		OperationTimeout:   config.OperationTimeout,
+		TcpBufferSize:      config.TcpBufferSize,
+		KeepaliveInterval:  config.ConnectionKeepaliveInterval,
+		RateLimiter:        config.RequestRateLimiter,
+		TransferBufferSize: config.TransferBufferSize,
+		TLSConfigCache:     sess.tlsConfigCache,
+		Lazy:               config.LazyConnection,
+	}
+
+	if manager != nil {
+		poolConfig.Limiter = manager
 	}
 
 	pool, err := NewConnectionPool(&poolConfig, &sess.metrics)
@@ -130,6 +184,62 @@ func (sess *IRODSSession) getPendingError() error {
 	return sess.lastConnectionError
 }
 
+// isServerUnavailable returns true if the error tells the server refuses connections or is down, so connecting later may succeed
+func isServerUnavailable(err error) bool {
+	return types.IsServerUnavailableError(err) || types.IsConnectionRefusedError(err)
+}
+
+// retryWhileServerUnavailable calls acquire again while it fails as the server is unavailable, up to ServerUnavailableRetryTimeout
+// returns the last error if the server is still unavailable after the timeout
+func (sess *IRODSSession) retryWhileServerUnavailable(acquire func() error) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "IRODSSession",
+		"function": "retryWhileServerUnavailable",
+	})
+
+	err := acquire()
+	if err == nil || sess.config.ServerUnavailableRetryTimeout <= 0 {
+		return err
+	}
+
+	interval := sess.config.ServerUnavailableRetryInterval
+	if interval <= 0 {
+		interval = IRODSSessionServerUnavailableRetryIntervalDefault
+	}
+
+	deadline := time.Now().Add(sess.config.ServerUnavailableRetryTimeout)
+	for isServerUnavailable(err) {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return err
+		}
+
+		if wait > interval {
+			wait = interval
+		}
+
+		logger.Debugf("server is unavailable, retrying in %s - %s", wait.String(), err.Error())
+		time.Sleep(wait)
+
+		sess.clearServerUnavailableError()
+		err = acquire()
+	}
+
+	return err
+}
+
+// clearServerUnavailableError forgets the last connection error if the server was unavailable, to connect again
+func (sess *IRODSSession) clearServerUnavailableError() {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	if isServerUnavailable(sess.lastConnectionError) {
+		sess.lastConnectionError = nil
+		sess.lastConnectionErrorTime = time.Time{}
+	}
+}
+
 // IsPermanantFailure returns if there is a failure that is unfixable, permanant
 func (sess *IRODSSession) IsPermanantFailure() bool {
 	sess.mutex.Lock()
@@ -145,9 +255,56 @@ func (sess *IRODSSession) GetConfig() *IRODSSessionConfig {
 
 // GetAccount returns an account
 func (sess *IRODSSession) GetAccount() *types.IRODSAccount {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
 	return sess.account
 }
 
+// UpdateAccount makes the session authenticate new connections with the account, e.g., after a password is rotated
+// idle connections are closed, and connections in use are closed when returned
+// the account must be for the same user and server if the session belongs to a session manager
+func (sess *IRODSSession) UpdateAccount(account *types.IRODSAccount) error {
+	// normalize a copy, the account may be in use elsewhere
+	normalizedAccount := *account
+	err := normalizedAccount.Normalize()
+	if err != nil {
+		return xerrors.Errorf("invalid account: %w", err)
+	}
+	account = &normalizedAccount
+
+	if sess.manager != nil {
+		// the manager keys the session by its credentials, sess.mutex must not be held while the manager is locked
+		oldAccount := sess.GetAccount()
+		if getAccountIdentityKey(account) != getAccountIdentityKey(oldAccount) {
+			return xerrors.Errorf("failed to update account of a managed session to a different user or server %q", getAccountIdentityKey(account))
+		}
+
+		err = sess.manager.updateSessionKey(sess, GetAccountKey(oldAccount), GetAccountKey(account))
+		if err != nil {
+			return err
+		}
+	}
+
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	// resolve host address
+	poolAccount := *account
+	if sess.addressResolver != nil {
+		poolAccount.Host = sess.addressResolver(poolAccount.Host)
+	}
+
+	sess.account = account
+	sess.connectionPool.UpdateAccount(&poolAccount)
+
+	// errors of the old account, e.g., authentication failures, do not apply to the new account
+	sess.lastConnectionError = nil
+	sess.lastConnectionErrorTime = time.Time{}
+
+	return nil
+}
+
 // SetTransactionFailureHandler sets transaction failure handler
 func (sess *IRODSSession) SetTransactionFailureHandler(handler TransactionFailureHandler) {
 	sess.transactionFailureHandler = handler
@@ -222,7 +379,85 @@ func (sess *IRODSSession) endTransaction(conn *connection.IRODSConnection) error
 }
 
 // AcquireConnection returns an idle connection
+// if operations are scheduled, it waits for a connection as an operation of OperationPriorityNormal
 func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, error) {
+	return sess.AcquireConnectionWithPriority(OperationPriorityNormal)
+}
+
+// AcquireConnectionWithPriority returns an idle connection for an operation of the priority
+// if operations are not scheduled, the priority is ignored
+func (sess *IRODSSession) AcquireConnectionWithPriority(priority OperationPriority) (*connection.IRODSConnection, error) {
+	if sess.scheduler == nil {
+		return sess.acquireConnection()
+	}
+
+	err := sess.waitForSlot(priority)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sess.acquireConnection()
+	if err != nil {
+		sess.scheduler.Release()
+		return nil, err
+	}
+
+	sess.addScheduledConnections(conn)
+	return conn, nil
+}
+
+// waitForSlot waits for a slot of the scheduler up to the operation timeout
+func (sess *IRODSSession) waitForSlot(priority OperationPriority) error {
+	ctx := context.Background()
+	if sess.config.OperationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sess.config.OperationTimeout)
+		defer cancel()
+	}
+
+	return sess.scheduler.Acquire(ctx, priority)
+}
+
+// addScheduledConnections records connections given out with slots of the scheduler
+func (sess *IRODSSession) addScheduledConnections(connections ...*connection.IRODSConnection) {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	for _, conn := range connections {
+		sess.scheduledConnections[conn]++
+	}
+}
+
+// releaseScheduledConnection gives back a slot of the scheduler taken for the connection
+// the session must be locked by the caller
+func (sess *IRODSSession) releaseScheduledConnection(conn *connection.IRODSConnection) {
+	slots, ok := sess.scheduledConnections[conn]
+	if !ok {
+		return
+	}
+
+	if slots <= 1 {
+		delete(sess.scheduledConnections, conn)
+	} else {
+		sess.scheduledConnections[conn] = slots - 1
+	}
+
+	sess.scheduler.Release()
+}
+
+// acquireConnection calls acquireConnectionOnce, retrying while the server is unavailable
+func (sess *IRODSSession) acquireConnection() (*connection.IRODSConnection, error) {
+	var conn *connection.IRODSConnection
+	err := sess.retryWhileServerUnavailable(func() error {
+		var acquireErr error
+		conn, acquireErr = sess.acquireConnectionOnce()
+		return acquireErr
+	})
+	return conn, err
+}
+
+// acquireConnectionOnce returns an idle connection, or shares a connection in use if no more connections can be created
+func (sess *IRODSSession) acquireConnectionOnce() (*connection.IRODSConnection, error) {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
 		"struct":   "IRODSSession",
@@ -232,6 +467,10 @@ func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, erro
 	sess.mutex.Lock()
 	defer sess.mutex.Unlock()
 
+	if sess.shuttingDown {
+		return nil, xerrors.Errorf("failed to get a connection from the pool: %w", types.NewSessionShutdownError())
+	}
+
 	// return last error
 	pendingErr := sess.getPendingError()
 	if pendingErr != nil {
@@ -241,7 +480,7 @@ func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, erro
 	// check if there are available connections in the pool
 	if sess.connectionPool.AvailableConnections() > 0 {
 		// try to get it from the pool
-		conn, _, err := sess.connectionPool.Get()
+		conn, err := sess.getPooledConnection()
 		// ignore error this happens when connections in the pool are all occupied
 		if err != nil {
 			if types.IsConnectionPoolFullError(err) {
@@ -278,6 +517,11 @@ func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, erro
 	minShare := 0
 	var minShareConn *connection.IRODSConnection
 	for sharedConn, shareCount := range sess.sharedConnections {
+		if len(sharedConn.GetKeywords()) > 0 {
+			// keywords are for the caller only
+			continue
+		}
+
 		if minShare == 0 || shareCount < minShare {
 			minShare = shareCount
 			minShareConn = sharedConn
@@ -301,8 +545,125 @@ func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, erro
 	return minShareConn, nil
 }
 
+// AcquireConnectionWithKeywords returns an idle connection sending the keywords with requests, see IRODSConnection.SetKeywords
+// the connection is not shared until it is returned, keywords are cleared when it is returned
+func (sess *IRODSSession) AcquireConnectionWithKeywords(keywords map[common.KeyWord]string) (*connection.IRODSConnection, error) {
+	if len(keywords) == 0 {
+		return sess.AcquireConnection()
+	}
+
+	if sess.scheduler == nil {
+		return sess.acquireConnectionWithKeywords(keywords)
+	}
+
+	err := sess.waitForSlot(OperationPriorityNormal)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sess.acquireConnectionWithKeywords(keywords)
+	if err != nil {
+		sess.scheduler.Release()
+		return nil, err
+	}
+
+	sess.addScheduledConnections(conn)
+	return conn, nil
+}
+
+// acquireConnectionWithKeywords calls acquireConnectionWithKeywordsOnce, retrying while the server is unavailable
+func (sess *IRODSSession) acquireConnectionWithKeywords(keywords map[common.KeyWord]string) (*connection.IRODSConnection, error) {
+	var conn *connection.IRODSConnection
+	err := sess.retryWhileServerUnavailable(func() error {
+		var acquireErr error
+		conn, acquireErr = sess.acquireConnectionWithKeywordsOnce(keywords)
+		return acquireErr
+	})
+	return conn, err
+}
+
+// acquireConnectionWithKeywordsOnce returns an idle connection with the keywords set, not shared until it is returned
+func (sess *IRODSSession) acquireConnectionWithKeywordsOnce(keywords map[common.KeyWord]string) (*connection.IRODSConnection, error) {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	if sess.shuttingDown {
+		return nil, xerrors.Errorf("failed to get a connection from the pool: %w", types.NewSessionShutdownError())
+	}
+
+	// return last error
+	pendingErr := sess.getPendingError()
+	if pendingErr != nil {
+		return nil, xerrors.Errorf("failed to get a connection from the pool because pending error is found: %w", pendingErr)
+	}
+
+	conn, err := sess.getPooledConnection()
+	if err != nil {
+		if !types.IsConnectionPoolFullError(err) {
+			sess.lastConnectionError = err
+			sess.lastConnectionErrorTime = time.Now()
+		}
+		return nil, xerrors.Errorf("failed to get an idle connection for keywords: %w", err)
+	}
+
+	sess.sharedConnections[conn] = 1
+	conn.SetKeywords(keywords)
+
+	if !sess.supportParallelUploadSet {
+		sess.supportParallelUpload = conn.SupportParallelUpload()
+		sess.supportParallelUploadSet = true
+	}
+
+	return conn, nil
+}
+
 // AcquireConnectionsMulti returns idle connections
+// if operations are scheduled, it waits for a connection as an operation of OperationPriorityBackground
+// and takes more connections only if they are free
 func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRODSConnection, error) {
+	if sess.scheduler == nil {
+		return sess.acquireConnectionsMulti(number)
+	}
+
+	err := sess.waitForSlot(OperationPriorityBackground)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := 1
+	for slots < number && sess.scheduler.TryAcquire(OperationPriorityBackground) {
+		slots++
+	}
+
+	connections, err := sess.acquireConnectionsMulti(slots)
+	if err != nil {
+		for i := 0; i < slots; i++ {
+			sess.scheduler.Release()
+		}
+		return nil, err
+	}
+
+	for i := len(connections); i < slots; i++ {
+		sess.scheduler.Release()
+	}
+
+	sess.addScheduledConnections(connections...)
+	return connections, nil
+}
+
+// acquireConnectionsMulti calls acquireConnectionsMultiOnce, retrying while the server is unavailable
+func (sess *IRODSSession) acquireConnectionsMulti(number int) ([]*connection.IRODSConnection, error) {
+	var connections []*connection.IRODSConnection
+	err := sess.retryWhileServerUnavailable(func() error {
+		var acquireErr error
+		connections, acquireErr = sess.acquireConnectionsMultiOnce(number)
+		return acquireErr
+	})
+	return connections, err
+}
+
+// acquireConnectionsMultiOnce returns number connections from the pool, sharing connections in use if the pool runs out
+func (sess *IRODSSession) acquireConnectionsMultiOnce(number int) ([]*connection.IRODSConnection, error) {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
 		"struct":   "IRODSSession",
@@ -312,6 +673,10 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 	sess.mutex.Lock()
 	defer sess.mutex.Unlock()
 
+	if sess.shuttingDown {
+		return nil, xerrors.Errorf("failed to get a connection from the pool: %w", types.NewSessionShutdownError())
+	}
+
 	// return last error
 	pendingErr := sess.getPendingError()
 	if pendingErr != nil {
@@ -324,7 +689,7 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 	for i := 0; i < number; i++ {
 		if sess.connectionPool.AvailableConnections() > 0 {
 			// try to get it from the pool
-			conn, _, err := sess.connectionPool.Get()
+			conn, err := sess.getPooledConnection()
 			if err != nil {
 				if types.IsConnectionPoolFullError(err) {
 					logger.WithError(err).Debug("failed to get a connection from the pool, the pool is full")
@@ -353,13 +718,29 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 		}
 	}
 
+	shareableConnections := 0
+	for sharedConn := range sess.sharedConnections {
+		// keywords are for the caller only
+		if len(sharedConn.GetKeywords()) == 0 {
+			shareableConnections++
+		}
+	}
+
 	connectionsInNeed := number - len(connections)
+	if connectionsInNeed > 0 && shareableConnections == 0 {
+		sess.metrics.IncreaseCounterForConnectionPoolFailures(1)
+		return nil, xerrors.Errorf("failed to get a shared connection, too many connections created")
+	}
 
 	// failed to get connection from pool
 	// find a connection from shared connection
 	logger.Debug("Share an in-use connection as it cannot create a new connection")
 	for connectionsInNeed > 0 {
 		for sharedConn, shareCount := range sess.sharedConnections {
+			if len(sharedConn.GetKeywords()) > 0 {
+				continue
+			}
+
 			shareCount++
 
 			connections[sharedConn] = true
@@ -387,8 +768,31 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 	return acquiredConnections, nil
 }
 
+// getPooledConnection gets a connection from the pool
+// if the manager's limit is reached, an idle connection of other sessions is evicted to make room
+func (sess *IRODSSession) getPooledConnection() (*connection.IRODSConnection, error) {
+	conn, _, err := sess.connectionPool.Get()
+	if err != nil && types.IsConnectionPoolFullError(err) && sess.manager != nil {
+		if sess.manager.evictIdleConnection(sess) {
+			conn, _, err = sess.connectionPool.Get()
+		}
+	}
+	return conn, err
+}
+
 // AcquireUnmanagedConnection returns a connection that is not managed
 func (sess *IRODSSession) AcquireUnmanagedConnection() (*connection.IRODSConnection, error) {
+	var conn *connection.IRODSConnection
+	err := sess.retryWhileServerUnavailable(func() error {
+		var acquireErr error
+		conn, acquireErr = sess.acquireUnmanagedConnection()
+		return acquireErr
+	})
+	return conn, err
+}
+
+// acquireUnmanagedConnection creates a connection that is not managed
+func (sess *IRODSSession) acquireUnmanagedConnection() (*connection.IRODSConnection, error) {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
 		"struct":   "IRODSSession",
@@ -398,6 +802,10 @@ func (sess *IRODSSession) AcquireUnmanagedConnection() (*connection.IRODSConnect
 	sess.mutex.Lock()
 	defer sess.mutex.Unlock()
 
+	if sess.shuttingDown {
+		return nil, xerrors.Errorf("failed to get a connection: %w", types.NewSessionShutdownError())
+	}
+
 	// return last error
 	pendingErr := sess.getPendingError()
 	if pendingErr != nil {
@@ -406,6 +814,9 @@ func (sess *IRODSSession) AcquireUnmanagedConnection() (*connection.IRODSConnect
 
 	// create a new one
 	newConn := connection.NewIRODSConnection(sess.account, sess.config.OperationTimeout, sess.config.ApplicationName)
+	newConn.SetRateLimiter(sess.config.RequestRateLimiter)
+	newConn.SetTransferBufferSize(sess.config.TransferBufferSize)
+	newConn.SetTLSConfigCache(sess.tlsConfigCache)
 	err := newConn.Connect()
 	if err != nil {
 		sess.lastConnectionError = err
@@ -435,11 +846,14 @@ func (sess *IRODSSession) ReturnConnection(conn *connection.IRODSConnection) err
 	sess.mutex.Lock()
 	defer sess.mutex.Unlock()
 
+	sess.releaseScheduledConnection(conn)
+
 	if share, ok := sess.sharedConnections[conn]; ok {
 		share--
 		if share <= 0 {
 			// no share
 			delete(sess.sharedConnections, conn)
+			conn.SetKeywords(nil)
 
 			conn.Lock()
 			if conn.IsTransactionDirty() {
@@ -481,6 +895,8 @@ func (sess *IRODSSession) DiscardConnection(conn *connection.IRODSConnection) er
 	sess.mutex.Lock()
 	defer sess.mutex.Unlock()
 
+	sess.releaseScheduledConnection(conn)
+
 	if share, ok := sess.sharedConnections[conn]; ok {
 		share--
 		if share <= 0 {
@@ -502,6 +918,67 @@ func (sess *IRODSSession) DiscardConnection(conn *connection.IRODSConnection) er
 	return nil
 }
 
+// StopAcquire stops giving out connections, connections in use can still be returned
+func (sess *IRODSSession) StopAcquire() {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	sess.shuttingDown = true
+}
+
+// stopAcquireIfIdle stops giving out connections if no connections are in use, returns false if the session is busy
+func (sess *IRODSSession) stopAcquireIfIdle() bool {
+	// do not wait for the session, it may be waiting for the manager to evict connections
+	if !sess.mutex.TryLock() {
+		return false
+	}
+	defer sess.mutex.Unlock()
+
+	if len(sess.sharedConnections) > 0 {
+		return false
+	}
+
+	sess.shuttingDown = true
+	return true
+}
+
+// ConnectionsInUse returns the number of connections acquired and not returned yet, a shared connection is counted per share
+func (sess *IRODSSession) ConnectionsInUse() int {
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	inUse := 0
+	for _, share := range sess.sharedConnections {
+		inUse += share
+	}
+	return inUse
+}
+
+// Shutdown stops giving out connections, waits until connections in use are returned or ctx is done, then releases all connections
+func (sess *IRODSSession) Shutdown(ctx context.Context) error {
+	sess.StopAcquire()
+	defer sess.Release()
+
+	return WaitUntilIdle(ctx, func() bool {
+		return sess.ConnectionsInUse() == 0
+	})
+}
+
+// WaitUntilIdle polls the idle condition, e.g., no connections in use, until it is met or ctx is done
+func WaitUntilIdle(ctx context.Context, idle func() bool) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for !idle() {
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("failed to wait for connections in use: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 // Release releases all connections
 func (sess *IRODSSession) Release() {
 	sess.mutex.Lock()
@@ -510,6 +987,7 @@ func (sess *IRODSSession) Release() {
 	// we don't disconnect connections here,
 	// we will disconnect it when calling pool.Release
 	sess.sharedConnections = map[*connection.IRODSConnection]int{}
+	sess.scheduledConnections = map[*connection.IRODSConnection]int{}
 
 	sess.lastConnectionError = nil
 
@@ -580,3 +1058,60 @@ func (sess *IRODSSession) GetRedirectionConnection(controlConnection *connection
 
 	return connection.NewIRODSResourceServerConnectionWithMetrics(controlConnection, &resourceServerInfo, &sess.metrics)
 }
+
+// Ping checks if the session can authenticate and reach the catalog, using a pooled connection
+func (sess *IRODSSession) Ping() error {
+	conn, err := sess.AcquireConnection()
+	if err != nil {
+		return xerrors.Errorf("failed to get a connection: %w", err)
+	}
+
+	err = pingCatalog(conn)
+	if err != nil {
+		// the connection may be broken, do not return it to the pool
+		sess.DiscardConnection(conn)
+		return xerrors.Errorf("failed to reach the catalog: %w", err)
+	}
+
+	sess.ReturnConnection(conn)
+	return nil
+}
+
+// pingCatalog runs a cheap catalog query that looks up the client user
+func pingCatalog(conn *connection.IRODSConnection) error {
+	if conn == nil || !conn.IsConnected() {
+		return xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	account := conn.GetAccount()
+
+	// query with AUTO_CLOSE option
+	query := message.NewIRODSMessageQueryRequest(1, 0, 0, 0x100)
+	query.AddSelect(common.ICAT_COLUMN_USER_ID, 1)
+
+	nameCondVal := util.MakeGenQueryEqualCondition(account.ClientUser)
+	query.AddCondition(common.ICAT_COLUMN_USER_NAME, nameCondVal)
+	zoneCondVal := util.MakeGenQueryEqualCondition(account.ClientZone)
+	query.AddCondition(common.ICAT_COLUMN_USER_ZONE, zoneCondVal)
+
+	queryResult := message.IRODSMessageQueryResponse{}
+	err := conn.Request(query, &queryResult, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to receive a user query result message: %w", err)
+	}
+
+	err = queryResult.CheckError()
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+			// catalog is reachable, e.g., anonymous or ticket users may not be visible
+			return nil
+		}
+		return xerrors.Errorf("received a user query error: %w", err)
+	}
+
+	return nil
+}
@@ -106,13 +106,65 @@ func (accessType IRODSAccessLevelType) ChmodString() string {
 	}
 }
 
+// accessLevelOrder lists access levels from the lowest to the highest
+var accessLevelOrder = []IRODSAccessLevelType{
+	IRODSAccessLevelNull,
+	IRODSAccessLevelExecute,
+	IRODSAccessLevelReadAnnotation,
+	IRODSAccessLevelReadSystemMetadata,
+	IRODSAccessLevelReadMetadata,
+	IRODSAccessLevelReadObject,
+	IRODSAccessLevelWriteAnnotation,
+	IRODSAccessLevelCreateMetadata,
+	IRODSAccessLevelModifyMetadata,
+	IRODSAccessLevelDeleteMetadata,
+	IRODSAccessLevelAdministerObject,
+	IRODSAccessLevelCreateObject,
+	IRODSAccessLevelModifyObject,
+	IRODSAccessLevelDeleteObject,
+	IRODSAccessLevelCreateToken,
+	IRODSAccessLevelDeleteToken,
+	IRODSAccessLevelCurate,
+	IRODSAccessLevelOwner,
+}
+
+// getRank returns the rank of the access level, unknown access levels have the lowest rank
+func (accessType IRODSAccessLevelType) getRank() int {
+	for rank, level := range accessLevelOrder {
+		if level == accessType {
+			return rank
+		}
+	}
+	return 0
+}
+
+// IsEqualOrHigherThan returns true if the access level grants the other access level
+func (accessType IRODSAccessLevelType) IsEqualOrHigherThan(other IRODSAccessLevelType) bool {
+	return accessType.getRank() >= other.getRank()
+}
+
+// CanRead returns true if the access level allows reading data
+func (accessType IRODSAccessLevelType) CanRead() bool {
+	return accessType.IsEqualOrHigherThan(IRODSAccessLevelReadObject)
+}
+
+// CanWrite returns true if the access level allows writing data
+func (accessType IRODSAccessLevelType) CanWrite() bool {
+	return accessType.IsEqualOrHigherThan(IRODSAccessLevelModifyObject)
+}
+
+// IsOwner returns true if the access level is owner
+func (accessType IRODSAccessLevelType) IsOwner() bool {
+	return accessType == IRODSAccessLevelOwner
+}
+
 // IRODSAccess contains irods access information
 type IRODSAccess struct {
-	Path        string
-	UserName    string
-	UserZone    string
-	UserType    IRODSUserType
-	AccessLevel IRODSAccessLevelType
+	Path        string               `json:"path"`
+	UserName    string               `json:"user_name"`
+	UserZone    string               `json:"user_zone"`
+	UserType    IRODSUserType        `json:"user_type"`
+	AccessLevel IRODSAccessLevelType `json:"access_level"`
 }
 
 // ToString stringifies the object
@@ -120,6 +172,11 @@ func (access *IRODSAccess) ToString() string {
 	return fmt.Sprintf("<IRODSAccess %s %s %s %s %s>", access.Path, access.UserName, access.UserZone, string(access.UserType), string(access.AccessLevel))
 }
 
+// GetUserIdentity returns the zone-qualified user of the access
+func (access *IRODSAccess) GetUserIdentity() IRODSUserIdentity {
+	return NewIRODSUserIdentity(access.UserName, access.UserZone)
+}
+
 // IRODSAccessInheritance contains irods access inheritance information
 type IRODSAccessInheritance struct {
 	Path        string
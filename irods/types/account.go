@@ -1,7 +1,9 @@
 package types
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"golang.org/x/xerrors"
@@ -12,6 +14,10 @@ const (
 	// PamTTLDefault is a default value for Pam TTL
 	PamTTLDefault       int    = 1
 	UsernameRegexString string = "^((\\w|[-.@])+)$"
+	// ZoneNameRegexString is a regex for valid zone names
+	ZoneNameRegexString string = "^((\\w|[-.])+)$"
+	// ResourceNameRegexString is a regex for valid resource names
+	ResourceNameRegexString string = "^((\\w|[-.])+)$"
 )
 
 // IRODSAccount contains irods login information
@@ -31,8 +37,9 @@ type IRODSAccount struct {
 	PamTTL                  int
 	PamToken                string
 	SSLConfiguration        *IRODSSSLConfig
-	ServerNameTLS           string // Optional TLS Server Name for SNI connection and TLS verification - defaults to Host
-	SkipVerifyTLS           bool   // Skip TLS verification
+	ServerNameTLS           string        // Optional TLS Server Name for SNI connection and TLS verification - defaults to Host
+	SkipVerifyTLS           bool          // Skip TLS verification
+	Protocol                IRODSProtocol // XML (default) or NATIVE packing of messages
 }
 
 // CreateIRODSAccount creates IRODSAccount
@@ -54,6 +61,7 @@ func CreateIRODSAccount(host string, port int, user string, zone string,
 		PamTTL:                  PamTTLDefault,
 		PamToken:                "",
 		SSLConfiguration:        nil,
+		Protocol:                IRODSProtocolXML,
 	}
 
 	account.FixAuthConfiguration()
@@ -80,6 +88,7 @@ func CreateIRODSAccountForTicket(host string, port int, user string, zone string
 		PamTTL:                  PamTTLDefault,
 		PamToken:                "",
 		SSLConfiguration:        nil,
+		Protocol:                IRODSProtocolXML,
 	}
 
 	account.FixAuthConfiguration()
@@ -107,6 +116,7 @@ func CreateIRODSProxyAccount(host string, port int, clientUser string, clientZon
 		PamTTL:                  PamTTLDefault,
 		PamToken:                "",
 		SSLConfiguration:        nil,
+		Protocol:                IRODSProtocolXML,
 	}
 
 	account.FixAuthConfiguration()
@@ -144,6 +154,14 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		}
 	}
 
+	protocol := IRODSProtocolXML
+	if val, ok := y["protocol"]; ok {
+		protocol, err = GetIRODSProtocol(val.(string))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse protocol: %w", err)
+		}
+	}
+
 	host := make(map[string]interface{})
 	if val, ok := y["host"]; ok {
 		host = val.(map[string]interface{})
@@ -289,12 +307,60 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		hashRounds = val.(int)
 	}
 
+	clientCertFile := ""
+	if val, ok := sslConfig["client_cert_file"]; ok {
+		clientCertFile = val.(string)
+	}
+
+	clientKeyFile := ""
+	if val, ok := sslConfig["client_key_file"]; ok {
+		clientKeyFile = val.(string)
+	}
+
+	serverName := ""
+	if val, ok := sslConfig["server_name"]; ok {
+		serverName = val.(string)
+	}
+
+	insecureSkipVerify := false
+	if val, ok := sslConfig["insecure_skip_verify"]; ok {
+		insecureSkipVerify = val.(bool)
+	}
+
+	minVersion := uint16(0)
+	if val, ok := sslConfig["min_version"]; ok {
+		minVersion, err = GetTLSVersion(val.(string))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse tls min version: %w", err)
+		}
+	}
+
+	cipherSuites := []uint16{}
+	if val, ok := sslConfig["cipher_suites"]; ok {
+		cipherSuiteNames := []string{}
+		for _, name := range val.([]interface{}) {
+			cipherSuiteNames = append(cipherSuiteNames, name.(string))
+		}
+
+		cipherSuites, err = GetTLSCipherSuites(cipherSuiteNames)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse tls cipher suites: %w", err)
+		}
+	}
+
 	var irodsSSLConfig *IRODSSSLConfig = nil
 	if hasSSLConfig {
 		irodsSSLConfig, err = CreateIRODSSSLConfig(caCertFile, caCertPath, keySize, algorithm, saltSize, hashRounds)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to create irods ssl config: %w", err)
 		}
+
+		irodsSSLConfig.ClientCertificateFile = clientCertFile
+		irodsSSLConfig.ClientKeyFile = clientKeyFile
+		irodsSSLConfig.ServerName = serverName
+		irodsSSLConfig.InsecureSkipVerify = insecureSkipVerify
+		irodsSSLConfig.MinVersion = minVersion
+		irodsSSLConfig.CipherSuites = cipherSuites
 	}
 
 	account := &IRODSAccount{
@@ -313,6 +379,7 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		PamTTL:                  pamTTL,
 		PamToken:                pamToken,
 		SSLConfiguration:        irodsSSLConfig,
+		Protocol:                protocol,
 	}
 
 	account.FixAuthConfiguration()
@@ -334,8 +401,26 @@ func (account *IRODSAccount) SetCSNegotiation(requireNegotiation bool, requirePo
 }
 
 // UseProxyAccess returns whether it uses proxy access or not
+// the proxy user acts for the client user if their names or zones differ
 func (account *IRODSAccount) UseProxyAccess() bool {
-	return len(account.ProxyUser) > 0 && len(account.ClientUser) > 0 && account.ProxyUser != account.ClientUser
+	if len(account.ProxyUser) == 0 || len(account.ClientUser) == 0 {
+		return false
+	}
+
+	if len(account.ClientZone) == 0 {
+		return account.ProxyUser != account.ClientUser
+	}
+	return !account.GetProxyIdentity().Equals(account.GetClientIdentity())
+}
+
+// GetProxyIdentity returns the zone-qualified proxy user that authenticates
+func (account *IRODSAccount) GetProxyIdentity() IRODSUserIdentity {
+	return NewIRODSUserIdentity(account.ProxyUser, account.ProxyZone)
+}
+
+// GetClientIdentity returns the zone-qualified client user that operations are done as
+func (account *IRODSAccount) GetClientIdentity() IRODSUserIdentity {
+	return NewIRODSUserIdentity(account.ClientUser, account.ClientZone)
 }
 
 // UseTicket returns whether it uses ticket for access control
@@ -343,56 +428,140 @@ func (account *IRODSAccount) UseTicket() bool {
 	return len(account.Ticket) > 0
 }
 
-// Validate validates iRODS account
+// Normalize normalizes user and zone formats of iRODS account
+// a username in "user#zone" format is split into the username and the zone
+// the client user and zone default to the proxy user and zone
+func (account *IRODSAccount) Normalize() error {
+	account.Host = strings.TrimSpace(account.Host)
+	account.ProxyUser = strings.TrimSpace(account.ProxyUser)
+	account.ProxyZone = strings.TrimSpace(account.ProxyZone)
+	account.ClientUser = strings.TrimSpace(account.ClientUser)
+	account.ClientZone = strings.TrimSpace(account.ClientZone)
+	account.DefaultResource = strings.TrimSpace(account.DefaultResource)
+
+	proxyUser, proxyZone, err := splitUsernameAndZone("proxy user", account.ProxyUser, account.ProxyZone)
+	if err != nil {
+		return err
+	}
+	account.ProxyUser = proxyUser
+	account.ProxyZone = proxyZone
+
+	clientUser, clientZone, err := splitUsernameAndZone("client user", account.ClientUser, account.ClientZone)
+	if err != nil {
+		return err
+	}
+	account.ClientUser = clientUser
+	account.ClientZone = clientZone
+
+	if len(account.ClientUser) == 0 {
+		account.ClientUser = account.ProxyUser
+	}
+
+	if len(account.ClientZone) == 0 {
+		account.ClientZone = account.ProxyZone
+	}
+
+	return nil
+}
+
+// Validate validates iRODS account as normalized, returns AccountValidationError if invalid
+// the account is not modified, call Normalize to normalize it
 func (account *IRODSAccount) Validate() error {
+	normalized := *account
+	err := normalized.Normalize()
+	if err != nil {
+		return err
+	}
+
+	return normalized.validateNormalized()
+}
+
+// validateNormalized validates the normalized account
+func (account *IRODSAccount) validateNormalized() error {
 	if len(account.Host) == 0 {
-		return xerrors.Errorf("empty host")
+		return NewAccountValidationError("host", "empty host")
 	}
 
-	if account.Port <= 0 {
-		return xerrors.Errorf("empty port")
+	if account.Port <= 0 || account.Port > 65535 {
+		return NewAccountValidationError("port", fmt.Sprintf("port %d out of range", account.Port))
 	}
 
 	if len(account.ProxyUser) == 0 {
-		return xerrors.Errorf("empty user")
+		return NewAccountValidationError("proxy user", "empty user")
 	}
 
-	err := account.validateUsername(account.ProxyUser)
+	err := validateUsername(account.ProxyUser)
 	if err != nil {
-		return xerrors.Errorf("failed to validate username %s: %w", account.ProxyUser, err)
+		return NewAccountValidationError("proxy user", fmt.Sprintf("username '%s', %s", account.ProxyUser, err.Error()))
 	}
 
-	if len(account.ClientUser) > 0 {
-		err = account.validateUsername(account.ClientUser)
-		if err != nil {
-			return xerrors.Errorf("failed to validate username %s: %w", account.ProxyUser, err)
-		}
+	err = validateUsername(account.ClientUser)
+	if err != nil {
+		return NewAccountValidationError("client user", fmt.Sprintf("username '%s', %s", account.ClientUser, err.Error()))
 	}
 
 	if len(account.ProxyZone) == 0 {
-		return xerrors.Errorf("empty zone")
+		return NewAccountValidationError("proxy zone", "empty zone")
+	}
+
+	err = validateName(account.ProxyZone, ZoneNameRegexString)
+	if err != nil {
+		return NewAccountValidationError("proxy zone", fmt.Sprintf("zone '%s', %s", account.ProxyZone, err.Error()))
+	}
+
+	err = validateName(account.ClientZone, ZoneNameRegexString)
+	if err != nil {
+		return NewAccountValidationError("client zone", fmt.Sprintf("zone '%s', %s", account.ClientZone, err.Error()))
+	}
+
+	if len(account.DefaultResource) > 0 {
+		err = validateName(account.DefaultResource, ResourceNameRegexString)
+		if err != nil {
+			return NewAccountValidationError("default resource", fmt.Sprintf("resource '%s', %s", account.DefaultResource, err.Error()))
+		}
 	}
 
 	if account.AuthenticationScheme == AuthSchemeUnknown {
-		return xerrors.Errorf("unknown authentication scheme")
+		return NewAccountValidationError("authentication scheme", "unknown authentication scheme")
 	}
 
 	if account.AuthenticationScheme != AuthSchemeNative && account.CSNegotiationPolicy != CSNegotiationRequireSSL {
-		return xerrors.Errorf("SSL is required for non-native authentication scheme")
+		return NewAccountValidationError("authentication scheme", "SSL is required for non-native authentication scheme")
 	}
 
 	if account.CSNegotiationPolicy == CSNegotiationRequireSSL && !account.ClientServerNegotiation {
-		return xerrors.Errorf("client-server negotiation is required for SSL")
+		return NewAccountValidationError("client-server negotiation", "client-server negotiation is required for SSL")
 	}
 
 	if account.CSNegotiationPolicy == CSNegotiationRequireSSL && account.SSLConfiguration == nil {
-		return xerrors.Errorf("SSL configuration is empty")
+		return NewAccountValidationError("SSL configuration", "SSL configuration is empty")
 	}
 
 	return nil
 }
 
-func (account *IRODSAccount) validateUsername(username string) error {
+// splitUsernameAndZone splits a username in "user#zone" format, the zone must match the given zone if both are given
+func splitUsernameAndZone(field string, username string, zone string) (string, string, error) {
+	idx := strings.Index(username, "#")
+	if idx < 0 {
+		return username, zone, nil
+	}
+
+	userZone := username[idx+1:]
+	username = username[:idx]
+
+	if len(userZone) == 0 {
+		return username, zone, nil
+	}
+
+	if len(zone) > 0 && zone != userZone {
+		return "", "", NewAccountValidationError(field, fmt.Sprintf("zone '%s' in username conflicts with zone '%s'", userZone, zone))
+	}
+
+	return username, userZone, nil
+}
+
+func validateUsername(username string) error {
 	if len(username) >= common.MaxNameLength {
 		return xerrors.Errorf("username too long")
 	}
@@ -412,6 +581,27 @@ func (account *IRODSAccount) validateUsername(username string) error {
 	return nil
 }
 
+// validateName validates a zone or resource name
+func validateName(name string, regexString string) error {
+	if len(name) >= common.MaxNameLength {
+		return xerrors.Errorf("name too long")
+	}
+
+	if name == "." || name == ".." {
+		return xerrors.Errorf("invalid name")
+	}
+
+	nameRegEx, err := regexp.Compile(regexString)
+	if err != nil {
+		return xerrors.Errorf("failed to compile regex: %w", err)
+	}
+
+	if !nameRegEx.Match([]byte(name)) {
+		return xerrors.Errorf("invalid name, containing invalid chars")
+	}
+	return nil
+}
+
 func (account *IRODSAccount) FixAuthConfiguration() {
 	if account.AuthenticationScheme == AuthSchemeUnknown {
 		account.AuthenticationScheme = AuthSchemeNative
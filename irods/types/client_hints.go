@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IRODSClientHints contains hints the server gives clients about its configuration
+type IRODSClientHints struct {
+	HashScheme      string `json:"hash_scheme"`
+	MatchHashPolicy string `json:"match_hash_policy"`
+	// "on" if the server enforces strict ACLs, users see only files and dirs they have access to
+	StrictACLs      string                   `json:"strict_acls"`
+	SpecificQueries []string                 `json:"specific_queries"`
+	Rules           []map[string]interface{} `json:"rules"`
+	Plugins         []map[string]interface{} `json:"plugins"`
+}
+
+// IsStrictACLs returns true if the server enforces strict ACLs
+func (hints *IRODSClientHints) IsStrictACLs() bool {
+	return strings.ToLower(hints.StrictACLs) == "on"
+}
+
+// ToString stringifies the object
+func (hints *IRODSClientHints) ToString() string {
+	return fmt.Sprintf("<IRODSClientHints %s %s %s>", hints.HashScheme, hints.MatchHashPolicy, hints.StrictACLs)
+}
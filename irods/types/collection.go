@@ -14,6 +14,10 @@ type IRODSCollection struct {
 	Name string
 	// Owner has the owner's name
 	Owner string
+	// OwnerZone has the owner's zone
+	OwnerZone string
+	// Inheritance is true if ACL inheritance is set on the collection
+	Inheritance bool
 	// CreateTime has creation time
 	CreateTime time.Time
 	// ModifyTime has last modified time
@@ -18,9 +18,46 @@ type IRODSDataObject struct {
 	DataType string
 	// Replicas has replication information
 	Replicas []*IRODSReplica
+	// ReplicaCount has the number of all replicas, Replicas may have only the master replica
+	// 0 if not known, the number of Replicas is used then
+	ReplicaCount int
+	// StaleReplicaCount has the number of all stale replicas, including ones not in Replicas
+	StaleReplicaCount int
 }
 
 // ToString stringifies the object
 func (obj *IRODSDataObject) ToString() string {
 	return fmt.Sprintf("<IRODSDataObject %d %s %d %s>", obj.ID, obj.Path, obj.Size, obj.DataType)
 }
+
+// GetReplicaCount returns the number of all replicas, including ones not in Replicas
+func (obj *IRODSDataObject) GetReplicaCount() int {
+	if obj.ReplicaCount > len(obj.Replicas) {
+		return obj.ReplicaCount
+	}
+	return len(obj.Replicas)
+}
+
+// HasStaleReplica returns true if any of replicas is stale, including ones not in Replicas
+func (obj *IRODSDataObject) HasStaleReplica() bool {
+	if obj.StaleReplicaCount > 0 {
+		return true
+	}
+
+	for _, replica := range obj.Replicas {
+		if replica.IsStale() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasGoodReplica returns true if any of replicas is good
+func (obj *IRODSDataObject) HasGoodReplica() bool {
+	for _, replica := range obj.Replicas {
+		if replica.IsGood() {
+			return true
+		}
+	}
+	return false
+}
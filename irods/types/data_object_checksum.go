@@ -44,6 +44,24 @@ func (checksum *IRODSChecksum) ToString() string {
 	return fmt.Sprintf("<IRODSChecksum %s %x>", checksum.Algorithm, checksum.Checksum)
 }
 
+// MakeIRODSChecksumString makes iRODS checksum string from the algorithm and the checksum, the reverse of ParseIRODSChecksum
+func MakeIRODSChecksumString(algorithm ChecksumAlgorithm, checksum []byte) (string, error) {
+	switch algorithm {
+	case ChecksumAlgorithmSHA256:
+		return "sha2:" + base64.StdEncoding.EncodeToString(checksum), nil
+	case ChecksumAlgorithmSHA512:
+		return "sha512:" + base64.StdEncoding.EncodeToString(checksum), nil
+	case ChecksumAlgorithmSHA1:
+		return "sha1:" + base64.StdEncoding.EncodeToString(checksum), nil
+	case ChecksumAlgorithmADLER32:
+		return "adler32:" + hex.EncodeToString(checksum), nil
+	case ChecksumAlgorithmMD5:
+		return hex.EncodeToString(checksum), nil
+	default:
+		return "", xerrors.Errorf("unknown checksum algorithm: %s", algorithm)
+	}
+}
+
 // ParseIRODSChecksum parses iRODS checksum string
 func ParseIRODSChecksum(checksumString string) (ChecksumAlgorithm, []byte, error) {
 	sp := strings.Split(checksumString, ":")
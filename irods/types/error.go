@@ -3,6 +3,8 @@ package types
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
 )
@@ -40,6 +42,45 @@ func IsConnectionConfigError(err error) bool {
 	return errors.Is(err, &ConnectionConfigError{})
 }
 
+// AccountValidationError contains invalid account field information
+type AccountValidationError struct {
+	Field  string
+	Reason string
+}
+
+// NewAccountValidationError creates an error for an invalid account field
+func NewAccountValidationError(field string, reason string) error {
+	return &AccountValidationError{
+		Field:  field,
+		Reason: reason,
+	}
+}
+
+// Error returns error message
+func (err *AccountValidationError) Error() string {
+	return fmt.Sprintf("invalid account %s - %s", err.Field, err.Reason)
+}
+
+// Is tests type of error, account validation error is also connection config error
+func (err *AccountValidationError) Is(other error) bool {
+	switch other.(type) {
+	case *AccountValidationError, *ConnectionConfigError:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToString stringifies the object
+func (err *AccountValidationError) ToString() string {
+	return fmt.Sprintf("<AccountValidationError %s %s>", err.Field, err.Reason)
+}
+
+// IsAccountValidationError evaluates if the given error is invalid account field
+func IsAccountValidationError(err error) bool {
+	return errors.Is(err, &AccountValidationError{})
+}
+
 // ResourceServerConnectionConfigError contains resource server connection config error information
 type ResourceServerConnectionConfigError struct {
 	Config *IRODSRedirectionInfo
@@ -103,7 +144,177 @@ func IsConnectionError(err error) bool {
 	return errors.Is(err, &ConnectionError{})
 }
 
-// AuthError contains auth error information
+// ConnectionRefusedError contains information of a TCP connection to the server that could not be established
+// it is also a ConnectionError
+type ConnectionRefusedError struct {
+	Host string
+	Port int
+}
+
+// NewConnectionRefusedError creates an error for TCP connection failure
+func NewConnectionRefusedError(host string, port int) error {
+	return &ConnectionRefusedError{
+		Host: host,
+		Port: port,
+	}
+}
+
+// Error returns error message
+func (err *ConnectionRefusedError) Error() string {
+	return fmt.Sprintf("connection refused (iRODS server: '%s:%d')", err.Host, err.Port)
+}
+
+// Is tests type of error
+func (err *ConnectionRefusedError) Is(other error) bool {
+	switch other.(type) {
+	case *ConnectionRefusedError, *ConnectionError:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToString stringifies the object
+func (err *ConnectionRefusedError) ToString() string {
+	return fmt.Sprintf("<ConnectionRefusedError %s:%d>", err.Host, err.Port)
+}
+
+// IsConnectionRefusedError evaluates if the given error is TCP connection failure
+func IsConnectionRefusedError(err error) bool {
+	return errors.Is(err, &ConnectionRefusedError{})
+}
+
+// ServerUnavailableError contains information of the server refusing connections by its connection control, e.g., in maintenance
+// it is also a ConnectionError
+type ServerUnavailableError struct {
+	Host string
+	Port int
+	Code common.ErrorCode
+}
+
+// NewServerUnavailableError creates an error for connections refused by server connection control
+func NewServerUnavailableError(host string, port int, code common.ErrorCode) error {
+	return &ServerUnavailableError{
+		Host: host,
+		Port: port,
+		Code: code,
+	}
+}
+
+// Error returns error message
+func (err *ServerUnavailableError) Error() string {
+	return fmt.Sprintf("server unavailable - %s (iRODS server: '%s:%d')", common.GetIRODSErrorString(err.Code), err.Host, err.Port)
+}
+
+// Is tests type of error
+func (err *ServerUnavailableError) Is(other error) bool {
+	switch other.(type) {
+	case *ServerUnavailableError, *ConnectionError:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetCode returns error code
+func (err *ServerUnavailableError) GetCode() common.ErrorCode {
+	return err.Code
+}
+
+// ToString stringifies the object
+func (err *ServerUnavailableError) ToString() string {
+	return fmt.Sprintf("<ServerUnavailableError %s:%d %d>", err.Host, err.Port, err.Code)
+}
+
+// IsServerUnavailableError evaluates if the given error is connections refused by server connection control
+func IsServerUnavailableError(err error) bool {
+	return errors.Is(err, &ServerUnavailableError{})
+}
+
+// IsServerUnavailableErrorCode returns true if the server refuses startup with the code by its connection control
+// iRODS has no dedicated code for servers down, connection control and agent start failures are reported instead
+func IsServerUnavailableErrorCode(code common.ErrorCode) bool {
+	switch code {
+	case common.SYS_USER_NOT_ALLOWED_TO_CONN, common.SYS_EXCEED_CONNECT_CNT, common.SYS_MAX_CONNECT_COUNT_EXCEEDED, common.SYS_AGENT_INIT_ERR:
+		return true
+	default:
+		return false
+	}
+}
+
+// SSLNegotiationError contains client-server negotiation or SSL handshake failure information
+type SSLNegotiationError struct {
+	Host   string
+	Port   int
+	Reason string
+}
+
+// NewSSLNegotiationError creates an error for client-server negotiation or SSL handshake failure
+func NewSSLNegotiationError(host string, port int, reason string) error {
+	return &SSLNegotiationError{
+		Host:   host,
+		Port:   port,
+		Reason: reason,
+	}
+}
+
+// Error returns error message
+func (err *SSLNegotiationError) Error() string {
+	return fmt.Sprintf("SSL negotiation error - %s (iRODS server: '%s:%d')", err.Reason, err.Host, err.Port)
+}
+
+// Is tests type of error
+func (err *SSLNegotiationError) Is(other error) bool {
+	_, ok := other.(*SSLNegotiationError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *SSLNegotiationError) ToString() string {
+	return fmt.Sprintf("<SSLNegotiationError %s:%d %s>", err.Host, err.Port, err.Reason)
+}
+
+// IsSSLNegotiationError evaluates if the given error is client-server negotiation or SSL handshake failure
+func IsSSLNegotiationError(err error) bool {
+	return errors.Is(err, &SSLNegotiationError{})
+}
+
+// VersionIncompatibleError contains server version information that the client does not support
+type VersionIncompatibleError struct {
+	ServerVersion   string
+	RequiredVersion string
+}
+
+// NewVersionIncompatibleError creates an error for incompatible server version
+func NewVersionIncompatibleError(serverVersion string, requiredVersion string) error {
+	return &VersionIncompatibleError{
+		ServerVersion:   serverVersion,
+		RequiredVersion: requiredVersion,
+	}
+}
+
+// Error returns error message
+func (err *VersionIncompatibleError) Error() string {
+	return fmt.Sprintf("incompatible server version '%s', requires '%s' or above", err.ServerVersion, err.RequiredVersion)
+}
+
+// Is tests type of error
+func (err *VersionIncompatibleError) Is(other error) bool {
+	_, ok := other.(*VersionIncompatibleError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *VersionIncompatibleError) ToString() string {
+	return fmt.Sprintf("<VersionIncompatibleError %s %s>", err.ServerVersion, err.RequiredVersion)
+}
+
+// IsVersionIncompatibleError evaluates if the given error is incompatible server version
+func IsVersionIncompatibleError(err error) bool {
+	return errors.Is(err, &VersionIncompatibleError{})
+}
+
+// AuthError contains auth error information, the server rejected authentication
 type AuthError struct {
 	Config *IRODSAccount
 }
@@ -146,7 +357,7 @@ type ConnectionPoolFullError struct {
 func NewConnectionPoolFullError(requested int, max int) error {
 	return &ConnectionPoolFullError{
 		Occupied: requested,
-		Max:      requested,
+		Max:      max,
 	}
 }
 
@@ -171,6 +382,36 @@ func IsConnectionPoolFullError(err error) bool {
 	return errors.Is(err, &ConnectionPoolFullError{})
 }
 
+// SessionShutdownError contains session shutdown error information
+type SessionShutdownError struct {
+}
+
+// NewSessionShutdownError creates an error for session shutdown
+func NewSessionShutdownError() error {
+	return &SessionShutdownError{}
+}
+
+// Error returns error message
+func (err *SessionShutdownError) Error() string {
+	return "session is shutting down, no new operations are accepted"
+}
+
+// Is tests type of error
+func (err *SessionShutdownError) Is(other error) bool {
+	_, ok := other.(*SessionShutdownError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *SessionShutdownError) ToString() string {
+	return "<SessionShutdownError>"
+}
+
+// IsSessionShutdownError evaluates if the given error is session shutdown error
+func IsSessionShutdownError(err error) bool {
+	return errors.Is(err, &SessionShutdownError{})
+}
+
 // CollectionNotEmptyError contains collection not empty error information
 type CollectionNotEmptyError struct {
 	Path string
@@ -336,6 +577,223 @@ func IsUserNotFoundError(err error) bool {
 	return errors.Is(err, &UserNotFoundError{})
 }
 
+// TransferStalledError contains transfer stalled error information
+type TransferStalledError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+// NewTransferStalledError creates an error for transfer stalled
+func NewTransferStalledError(p string, timeout time.Duration) error {
+	return &TransferStalledError{
+		Path:    p,
+		Timeout: timeout,
+	}
+}
+
+// Error returns error message
+func (err *TransferStalledError) Error() string {
+	return fmt.Sprintf("transfer of %s stalled, no bytes moved for %s", err.Path, err.Timeout)
+}
+
+// Is tests type of error
+func (err *TransferStalledError) Is(other error) bool {
+	_, ok := other.(*TransferStalledError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *TransferStalledError) ToString() string {
+	return fmt.Sprintf("<TransferStalledError %s %s>", err.Path, err.Timeout)
+}
+
+// IsTransferStalledError checks if the given error is TransferStalledError
+func IsTransferStalledError(err error) bool {
+	return errors.Is(err, &TransferStalledError{})
+}
+
+// ReplicationError contains errors of replicating a data object to multiple resources
+type ReplicationError struct {
+	Path      string
+	Resources []string         // target resources in the order requested
+	Errors    map[string]error // key is a target resource failed
+}
+
+// NewReplicationError creates an error for replication failed for some of the resources
+func NewReplicationError(p string, resources []string, errs map[string]error) error {
+	return &ReplicationError{
+		Path:      p,
+		Resources: resources,
+		Errors:    errs,
+	}
+}
+
+// GetFailedResources returns target resources failed in the order requested
+func (err *ReplicationError) GetFailedResources() []string {
+	failed := []string{}
+	for _, resource := range err.Resources {
+		if _, ok := err.Errors[resource]; ok {
+			failed = append(failed, resource)
+		}
+	}
+	return failed
+}
+
+// Error returns error message
+func (err *ReplicationError) Error() string {
+	failed := err.GetFailedResources()
+
+	msgs := make([]string, len(failed))
+	for idx, resource := range failed {
+		msgs[idx] = fmt.Sprintf("%s: %s", resource, err.Errors[resource].Error())
+	}
+
+	return fmt.Sprintf("failed to replicate %s to %d of %d resources (%s)", err.Path, len(failed), len(err.Resources), strings.Join(msgs, "; "))
+}
+
+// Is tests type of error
+func (err *ReplicationError) Is(other error) bool {
+	_, ok := other.(*ReplicationError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *ReplicationError) ToString() string {
+	return fmt.Sprintf("<ReplicationError %s %v>", err.Path, err.GetFailedResources())
+}
+
+// IsReplicationError checks if the given error is ReplicationError
+func IsReplicationError(err error) bool {
+	return errors.Is(err, &ReplicationError{})
+}
+
+// RenameNotSupportedError contains error information for a rename the server refuses to do in place
+// e.g., across zones or across resources (devices), the file has to be copied instead
+type RenameNotSupportedError struct {
+	SrcPath  string
+	DestPath string
+	Code     common.ErrorCode
+}
+
+// NewRenameNotSupportedError creates an error for rename not supported
+func NewRenameNotSupportedError(srcPath string, destPath string, code common.ErrorCode) error {
+	return &RenameNotSupportedError{
+		SrcPath:  srcPath,
+		DestPath: destPath,
+		Code:     code,
+	}
+}
+
+// Error returns error message
+func (err *RenameNotSupportedError) Error() string {
+	return fmt.Sprintf("rename of %s to %s is not supported by the server (%s)", err.SrcPath, err.DestPath, common.GetIRODSErrorString(err.Code))
+}
+
+// Is tests type of error
+func (err *RenameNotSupportedError) Is(other error) bool {
+	_, ok := other.(*RenameNotSupportedError)
+	return ok
+}
+
+// GetCode returns iRODS error code the server refused the rename with
+func (err *RenameNotSupportedError) GetCode() common.ErrorCode {
+	return err.Code
+}
+
+// ToString stringifies the object
+func (err *RenameNotSupportedError) ToString() string {
+	return fmt.Sprintf("<RenameNotSupportedError %s %s %d>", err.SrcPath, err.DestPath, err.Code)
+}
+
+// IsRenameNotSupportedError checks if the given error is RenameNotSupportedError
+func IsRenameNotSupportedError(err error) bool {
+	return errors.Is(err, &RenameNotSupportedError{})
+}
+
+// PermissionError contains error information for a request the server refuses for lack of permission
+// e.g., ACL queries of a server enforcing strict ACLs
+type PermissionError struct {
+	Path string
+	Code common.ErrorCode
+}
+
+// NewPermissionError creates an error for permission denied
+func NewPermissionError(p string, code common.ErrorCode) error {
+	return &PermissionError{
+		Path: p,
+		Code: code,
+	}
+}
+
+// Error returns error message
+func (err *PermissionError) Error() string {
+	return fmt.Sprintf("permission denied for %s (%s)", err.Path, common.GetIRODSErrorString(err.Code))
+}
+
+// Is tests type of error
+func (err *PermissionError) Is(other error) bool {
+	_, ok := other.(*PermissionError)
+	return ok
+}
+
+// GetCode returns iRODS error code the server refused the request with
+func (err *PermissionError) GetCode() common.ErrorCode {
+	return err.Code
+}
+
+// ToString stringifies the object
+func (err *PermissionError) ToString() string {
+	return fmt.Sprintf("<PermissionError %s %d>", err.Path, err.Code)
+}
+
+// IsPermissionError checks if the given error is PermissionError
+func IsPermissionError(err error) bool {
+	return errors.Is(err, &PermissionError{})
+}
+
+// MetadataTooLargeError contains error information for an AVU having a field too long to store in the catalog
+type MetadataTooLargeError struct {
+	Name   string
+	Field  string
+	Length int
+	// MaxLength is 0 if the limit is unknown, e.g., the server refused the AVU
+	MaxLength int
+}
+
+// NewMetadataTooLargeError creates an error for an AVU field too long
+func NewMetadataTooLargeError(name string, field string, length int, maxLength int) error {
+	return &MetadataTooLargeError{
+		Name:      name,
+		Field:     field,
+		Length:    length,
+		MaxLength: maxLength,
+	}
+}
+
+// Error returns error message
+func (err *MetadataTooLargeError) Error() string {
+	if err.MaxLength > 0 {
+		return fmt.Sprintf("metadata %s is too large, %s has %d bytes exceeding %d bytes", err.Name, err.Field, err.Length, err.MaxLength)
+	}
+	return fmt.Sprintf("metadata %s is too large, %s has %d bytes refused by the server", err.Name, err.Field, err.Length)
+}
+
+// Is tests type of error
+func (err *MetadataTooLargeError) Is(other error) bool {
+	_, ok := other.(*MetadataTooLargeError)
+	return ok
+}
+
+// ToString stringifies the object
+func (err *MetadataTooLargeError) ToString() string {
+	return fmt.Sprintf("<MetadataTooLargeError %s %s %d %d>", err.Name, err.Field, err.Length, err.MaxLength)
+}
+
+// IsMetadataTooLargeError checks if the given error is MetadataTooLargeError
+func IsMetadataTooLargeError(err error) bool {
+	return errors.Is(err, &MetadataTooLargeError{})
+}
+
 // IRODSError contains irods error information
 type IRODSError struct {
 	Code              common.ErrorCode
@@ -413,6 +871,10 @@ func IsPermanantFailure(err error) bool {
 		return true
 	} else if IsConnectionConfigError(err) {
 		return true
+	} else if IsSSLNegotiationError(err) {
+		return true
+	} else if IsVersionIncompatibleError(err) {
+		return true
 	} else if IsConnectionError(err) {
 		return false
 	} else if IsConnectionPoolFullError(err) {
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"os"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -54,6 +56,28 @@ const (
 	O_TRUNC FileOpenFlag = 512
 )
 
+// GetFileOpenModeFromFlag returns file open mode for the flag given to os.OpenFile
+func GetFileOpenModeFromFlag(flag int) FileOpenMode {
+	switch {
+	case flag&os.O_RDWR != 0:
+		if flag&os.O_APPEND != 0 {
+			return FileOpenModeReadAppend
+		} else if flag&os.O_TRUNC != 0 {
+			return FileOpenModeWriteTruncate
+		}
+		return FileOpenModeReadWrite
+	case flag&os.O_WRONLY != 0:
+		if flag&os.O_APPEND != 0 {
+			return FileOpenModeAppend
+		} else if flag&os.O_TRUNC != 0 {
+			return FileOpenModeWriteTruncate
+		}
+		return FileOpenModeWriteOnly
+	default:
+		return FileOpenModeReadOnly
+	}
+}
+
 // GetFlag returns file open flag
 func (mode FileOpenMode) GetFlag() int {
 	flag, _ := mode.GetFlagSeekToEnd()
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"fmt"
+)
+
+// IRODSFileDescriptorInfo contains information the server keeps for a file descriptor of an opened data object
+type IRODSFileDescriptorInfo struct {
+	FileDescriptor int
+	// Path has an absolute path to the data object
+	Path              string
+	ResourceHierarchy string
+	ReplicaNumber     int64
+	// ReplicaToken is given to other connections to write the same replica in parallel
+	ReplicaToken string
+	// DataSize is the size of the replica when opened
+	DataSize     int64
+	BytesWritten int64
+	Checksum     string
+}
+
+// ToString stringifies the object
+func (info *IRODSFileDescriptorInfo) ToString() string {
+	return fmt.Sprintf("<IRODSFileDescriptorInfo %d %s %s %d %d %d>", info.FileDescriptor, info.Path, info.ResourceHierarchy, info.ReplicaNumber, info.DataSize, info.BytesWritten)
+}
@@ -0,0 +1,219 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+)
+
+// IRODSKeyVal is a keyword and its value passed to the server in condInput of requests
+// flag keywords, e.g., forceFlag, have empty values
+type IRODSKeyVal struct {
+	Key   common.KeyWord
+	Value string
+}
+
+// ToString stringifies the object
+func (kv *IRODSKeyVal) ToString() string {
+	return fmt.Sprintf("<IRODSKeyVal %s %s>", kv.Key, kv.Value)
+}
+
+// NewIRODSKeyVal creates a new IRODSKeyVal
+func NewIRODSKeyVal(key common.KeyWord, value string) IRODSKeyVal {
+	return IRODSKeyVal{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// NewIRODSKeyValFlag creates a new IRODSKeyVal for a flag keyword having no value
+func NewIRODSKeyValFlag(key common.KeyWord) IRODSKeyVal {
+	return NewIRODSKeyVal(key, "")
+}
+
+// KeyValForce returns a keyword to overwrite existing data objects
+func KeyValForce() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.FORCE_FLAG_KW)
+}
+
+// KeyValAllReplicas returns a keyword to apply the operation to all replicas
+func KeyValAllReplicas() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.ALL_KW)
+}
+
+// KeyValRecursive returns a keyword to apply the operation recursively
+func KeyValRecursive() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.RECURSIVE_OPR_KW)
+}
+
+// KeyValAdmin returns a keyword to run the operation as an administrator
+func KeyValAdmin() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.ADMIN_KW)
+}
+
+// KeyValResource returns a keyword to select a resource of the source replica
+func KeyValResource(resource string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.RESC_NAME_KW, resource)
+}
+
+// KeyValDestResource returns a keyword to select a resource to create or write a replica in
+func KeyValDestResource(resource string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.DEST_RESC_NAME_KW, resource)
+}
+
+// KeyValResourceHierarchy returns a keyword to select a resource hierarchy of the source replica
+func KeyValResourceHierarchy(hierarchy string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.RESC_HIER_STR_KW, hierarchy)
+}
+
+// KeyValDestResourceHierarchy returns a keyword to select a resource hierarchy to create or write a replica in
+func KeyValDestResourceHierarchy(hierarchy string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.DEST_RESC_HIER_STR_KW, hierarchy)
+}
+
+// KeyValReplicaNumber returns a keyword to select a replica
+func KeyValReplicaNumber(replicaNumber int64) IRODSKeyVal {
+	return NewIRODSKeyVal(common.REPL_NUM_KW, strconv.FormatInt(replicaNumber, 10))
+}
+
+// KeyValReplicaToken returns a keyword to open a replica opened by another connection, e.g., for parallel writes
+func KeyValReplicaToken(replicaToken string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.REPLICA_TOKEN_KW, replicaToken)
+}
+
+// KeyValDataType returns a keyword to set the data type of data objects
+func KeyValDataType(dataType DataType) IRODSKeyVal {
+	return NewIRODSKeyVal(common.DATA_TYPE_KW, string(dataType))
+}
+
+// KeyValChecksum returns a keyword to give the checksum of data, e.g., "sha2:<base64 digest>"
+func KeyValChecksum(checksum string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.CHKSUM_KW, checksum)
+}
+
+// KeyValRegisterChecksum returns a keyword to compute and register the checksum of data written
+func KeyValRegisterChecksum() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.REG_CHKSUM_KW)
+}
+
+// KeyValVerifyChecksum returns a keyword to verify the checksum of data written against the checksum given
+func KeyValVerifyChecksum() IRODSKeyVal {
+	return NewIRODSKeyValFlag(common.VERIFY_CHKSUM_KW)
+}
+
+// KeyValTicket returns a keyword to access data with a ticket
+func KeyValTicket(ticket string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.TICKET_KW, ticket)
+}
+
+// KeyValZone returns a keyword to run the operation in a zone
+func KeyValZone(zone string) IRODSKeyVal {
+	return NewIRODSKeyVal(common.ZONE_KW, zone)
+}
+
+// IRODSKeyVals is a set of keywords and values in the order added, keywords are unique
+type IRODSKeyVals struct {
+	keyVals []IRODSKeyVal
+}
+
+// NewIRODSKeyVals creates a new IRODSKeyVals with keywords, later ones override earlier ones of the same keyword
+func NewIRODSKeyVals(keyVals ...IRODSKeyVal) *IRODSKeyVals {
+	kvs := &IRODSKeyVals{
+		keyVals: []IRODSKeyVal{},
+	}
+
+	for _, kv := range keyVals {
+		kvs.Set(kv.Key, kv.Value)
+	}
+
+	return kvs
+}
+
+// NewIRODSKeyValsFromMap creates a new IRODSKeyVals with keywords in a map, ordered by keyword
+func NewIRODSKeyValsFromMap(keywords map[common.KeyWord]string) *IRODSKeyVals {
+	keys := make([]string, 0, len(keywords))
+	for key := range keywords {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	kvs := NewIRODSKeyVals()
+	for _, key := range keys {
+		kvs.Set(common.KeyWord(key), keywords[common.KeyWord(key)])
+	}
+
+	return kvs
+}
+
+// Add adds keywords, overriding values of keywords set
+func (kvs *IRODSKeyVals) Add(keyVals ...IRODSKeyVal) {
+	for _, kv := range keyVals {
+		kvs.Set(kv.Key, kv.Value)
+	}
+}
+
+// Set sets the value of a keyword
+func (kvs *IRODSKeyVals) Set(key common.KeyWord, value string) {
+	for idx := range kvs.keyVals {
+		if kvs.keyVals[idx].Key == key {
+			kvs.keyVals[idx].Value = value
+			return
+		}
+	}
+
+	kvs.keyVals = append(kvs.keyVals, NewIRODSKeyVal(key, value))
+}
+
+// Get returns the value of a keyword, and true if the keyword is set
+func (kvs *IRODSKeyVals) Get(key common.KeyWord) (string, bool) {
+	for _, kv := range kvs.keyVals {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return "", false
+}
+
+// Has returns true if the keyword is set
+func (kvs *IRODSKeyVals) Has(key common.KeyWord) bool {
+	_, ok := kvs.Get(key)
+	return ok
+}
+
+// Remove removes a keyword
+func (kvs *IRODSKeyVals) Remove(key common.KeyWord) {
+	for idx, kv := range kvs.keyVals {
+		if kv.Key == key {
+			kvs.keyVals = append(kvs.keyVals[:idx], kvs.keyVals[idx+1:]...)
+			return
+		}
+	}
+}
+
+// Len returns the number of keywords
+func (kvs *IRODSKeyVals) Len() int {
+	return len(kvs.keyVals)
+}
+
+// GetKeyVals returns keywords and values in the order added
+func (kvs *IRODSKeyVals) GetKeyVals() []IRODSKeyVal {
+	keyVals := make([]IRODSKeyVal, len(kvs.keyVals))
+	copy(keyVals, kvs.keyVals)
+	return keyVals
+}
+
+// ToMap returns keywords and values in a map, e.g., for connection keywords
+func (kvs *IRODSKeyVals) ToMap() map[common.KeyWord]string {
+	keywords := make(map[common.KeyWord]string, len(kvs.keyVals))
+	for _, kv := range kvs.keyVals {
+		keywords[kv.Key] = kv.Value
+	}
+	return keywords
+}
+
+// ToString stringifies the object
+func (kvs *IRODSKeyVals) ToString() string {
+	return fmt.Sprintf("<IRODSKeyVals %v>", kvs.keyVals)
+}
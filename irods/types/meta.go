@@ -1,12 +1,30 @@
 package types
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"golang.org/x/xerrors"
 )
 
+const (
+	// IRODSMetaNameMaxLength is the max length of metadata names in bytes the catalog stores
+	IRODSMetaNameMaxLength int = 2700
+	// IRODSMetaValueMaxLength is the max length of metadata values in bytes the catalog stores
+	IRODSMetaValueMaxLength int = 2700
+	// IRODSMetaUnitsMaxLength is the max length of metadata units in bytes the catalog stores
+	IRODSMetaUnitsMaxLength int = 250
+
+	// irodsMetaChunkTag separates units and the index of a chunk in units of AVUs split by SplitIRODSMeta
+	irodsMetaChunkTag string = "#chunk:"
+)
+
 // IRODSMetaItemType describes a type to set metadata on
 type IRODSMetaItemType string
 
@@ -37,17 +55,178 @@ func GetIRODSMetaItemType(data interface{}) (IRODSMetaItemType, error) {
 
 // IRODSMeta contains irods metadata
 type IRODSMeta struct {
-	AVUID int64 // is ignored on metadata operations (set, add, mod, rm)
-	Name  string
-	Value string
-	Units string
+	AVUID int64  `json:"avu_id"` // is ignored on metadata operations (set, add, mod, rm)
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Units string `json:"units"`
 	// CreateTime has creation time
-	CreateTime time.Time
+	CreateTime time.Time `json:"create_time"`
 	// ModifyTime has last modified time
-	ModifyTime time.Time
+	ModifyTime time.Time `json:"modify_time"`
 }
 
 // ToString stringifies the object
 func (meta *IRODSMeta) ToString() string {
 	return fmt.Sprintf("<IRODSMeta %d %s %s %s %s %s>", meta.AVUID, meta.Name, meta.Value, meta.Units, meta.CreateTime, meta.ModifyTime)
 }
+
+// Validate returns MetadataTooLargeError if a field of the AVU is too long to store in the catalog
+func (meta *IRODSMeta) Validate() error {
+	if len(meta.Name) > IRODSMetaNameMaxLength {
+		return NewMetadataTooLargeError(meta.Name, "name", len(meta.Name), IRODSMetaNameMaxLength)
+	}
+
+	if len(meta.Value) > IRODSMetaValueMaxLength {
+		return NewMetadataTooLargeError(meta.Name, "value", len(meta.Value), IRODSMetaValueMaxLength)
+	}
+
+	if len(meta.Units) > IRODSMetaUnitsMaxLength {
+		return NewMetadataTooLargeError(meta.Name, "units", len(meta.Units), IRODSMetaUnitsMaxLength)
+	}
+
+	return nil
+}
+
+// SplitIRODSMeta splits an AVU having a value longer than the chunk size into AVUs of chunks of the value, in order
+// chunks have the same name, and units tagged with a group id, the index and the number of chunks, e.g., "units#chunk:1f2e3d4c5b6a7988:1/3"
+// the group id tells chunks of values split under the same name and units apart, as AVUs are multi-valued
+// the value is split at UTF-8 character boundaries, the AVU is returned as it is if the value fits in a chunk
+func SplitIRODSMeta(meta *IRODSMeta, chunkSize int) []*IRODSMeta {
+	if len(meta.Value) <= chunkSize || chunkSize < utf8.UTFMax {
+		return []*IRODSMeta{meta}
+	}
+
+	values := []string{}
+	value := meta.Value
+	for len(value) > chunkSize {
+		end := chunkSize
+		for end > 0 && !utf8.RuneStart(value[end]) {
+			end--
+		}
+
+		values = append(values, value[:end])
+		value = value[end:]
+	}
+	values = append(values, value)
+
+	groupID := makeIRODSMetaChunkGroupID()
+
+	chunks := make([]*IRODSMeta, 0, len(values))
+	for idx, chunkValue := range values {
+		chunks = append(chunks, &IRODSMeta{
+			Name:  meta.Name,
+			Value: chunkValue,
+			Units: fmt.Sprintf("%s%s%s:%d/%d", meta.Units, irodsMetaChunkTag, groupID, idx+1, len(values)),
+		})
+	}
+
+	return chunks
+}
+
+// makeIRODSMetaChunkGroupID returns a random id of chunks split from a value
+func makeIRODSMetaChunkGroupID() string {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		// unique within the process at least
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
+
+// JoinIRODSMeta joins AVUs of chunks split by SplitIRODSMeta back, other AVUs are returned as they are
+// returns an error if chunks of an AVU are missing
+func JoinIRODSMeta(metadata []*IRODSMeta) ([]*IRODSMeta, error) {
+	type chunkedMeta struct {
+		meta   *IRODSMeta
+		chunks map[int]string
+		total  int
+	}
+
+	joined := []*IRODSMeta{}
+	chunked := map[string]*chunkedMeta{}
+	chunkedKeys := []string{}
+
+	for _, meta := range metadata {
+		units, groupID, index, total, ok := parseIRODSMetaChunkUnits(meta.Units)
+		if !ok {
+			joined = append(joined, meta)
+			continue
+		}
+
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%d", meta.Name, units, groupID, total)
+		chunkMeta, ok := chunked[key]
+		if !ok {
+			chunkMeta = &chunkedMeta{
+				meta: &IRODSMeta{
+					AVUID:      meta.AVUID,
+					Name:       meta.Name,
+					Units:      units,
+					CreateTime: meta.CreateTime,
+					ModifyTime: meta.ModifyTime,
+				},
+				chunks: map[int]string{},
+				total:  total,
+			}
+			chunked[key] = chunkMeta
+			chunkedKeys = append(chunkedKeys, key)
+		}
+
+		chunkMeta.chunks[index] = meta.Value
+		if meta.ModifyTime.After(chunkMeta.meta.ModifyTime) {
+			chunkMeta.meta.ModifyTime = meta.ModifyTime
+		}
+	}
+
+	sort.Strings(chunkedKeys)
+	for _, key := range chunkedKeys {
+		chunkMeta := chunked[key]
+
+		sb := strings.Builder{}
+		for index := 1; index <= chunkMeta.total; index++ {
+			value, ok := chunkMeta.chunks[index]
+			if !ok {
+				return nil, xerrors.Errorf("failed to find chunk %d/%d of metadata %s", index, chunkMeta.total, chunkMeta.meta.Name)
+			}
+			sb.WriteString(value)
+		}
+
+		chunkMeta.meta.Value = sb.String()
+		joined = append(joined, chunkMeta.meta)
+	}
+
+	return joined, nil
+}
+
+// parseIRODSMetaChunkUnits parses units of a chunk made by SplitIRODSMeta, returns original units, the group id, the index and the number of chunks
+// the group id is empty for chunks tagged without one, e.g., "units#chunk:1/3"
+func parseIRODSMetaChunkUnits(units string) (string, string, int, int, bool) {
+	idx := strings.LastIndex(units, irodsMetaChunkTag)
+	if idx < 0 {
+		return "", "", 0, 0, false
+	}
+
+	tag := units[idx+len(irodsMetaChunkTag):]
+	groupID := ""
+	if sep := strings.LastIndex(tag, ":"); sep >= 0 {
+		groupID = tag[:sep]
+		tag = tag[sep+1:]
+	}
+
+	indexString, totalString, ok := strings.Cut(tag, "/")
+	if !ok {
+		return "", "", 0, 0, false
+	}
+
+	index, err := strconv.Atoi(indexString)
+	if err != nil {
+		return "", "", 0, 0, false
+	}
+
+	total, err := strconv.Atoi(totalString)
+	if err != nil || index < 1 || index > total {
+		return "", "", 0, 0, false
+	}
+
+	return units[:idx], groupID, index, total, true
+}
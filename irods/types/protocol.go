@@ -0,0 +1,36 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IRODSProtocol defines serialization of iRODS API messages
+type IRODSProtocol string
+
+const (
+	// IRODSProtocolXML serializes messages in XML, default
+	IRODSProtocolXML IRODSProtocol = "XML"
+	// IRODSProtocolNative serializes messages in iRODS native (binary) packing
+	IRODSProtocolNative IRODSProtocol = "NATIVE"
+)
+
+// GetIRODSProtocol returns IRODSProtocol value from string
+func GetIRODSProtocol(protocol string) (IRODSProtocol, error) {
+	switch strings.TrimSpace(strings.ToUpper(protocol)) {
+	case string(IRODSProtocolXML), "":
+		return IRODSProtocolXML, nil
+	case string(IRODSProtocolNative):
+		return IRODSProtocolNative, nil
+	default:
+		return IRODSProtocolXML, fmt.Errorf("cannot parse string %s", protocol)
+	}
+}
+
+// GetStartupPackValue returns a value for irodsProt field in startup pack
+func (protocol IRODSProtocol) GetStartupPackValue() int {
+	if protocol == IRODSProtocolNative {
+		return 0
+	}
+	return 1
+}
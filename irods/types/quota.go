@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"time"
 )
 
 // IRODSQuota describes a resource quota
@@ -14,3 +15,18 @@ type IRODSQuota struct {
 func (q *IRODSQuota) ToString() string {
 	return fmt.Sprintf("<IRODSQuota %s: %v>", q.RescName, q.Limit)
 }
+
+// IRODSQuotaUsage describes usage of a user or a group on a resource, counted for quota enforcement
+// the server updates usages periodically, ModifyTime is when the usage was calculated
+type IRODSQuotaUsage struct {
+	UserName   string
+	UserZone   string
+	RescName   string
+	Usage      int64
+	ModifyTime time.Time
+}
+
+// ToString stringifies the object
+func (u *IRODSQuotaUsage) ToString() string {
+	return fmt.Sprintf("<IRODSQuotaUsage %s#%s %s: %d at %s>", u.UserName, u.UserZone, u.RescName, u.Usage, u.ModifyTime)
+}
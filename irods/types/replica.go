@@ -5,12 +5,21 @@ import (
 	"time"
 )
 
+const (
+	// IRODSReplicaStatusStale is a replica status for a stale replica
+	IRODSReplicaStatusStale string = "0"
+	// IRODSReplicaStatusGood is a replica status for a good replica
+	IRODSReplicaStatusGood string = "1"
+)
+
 // IRODSReplica contains irods data object replication information
 type IRODSReplica struct {
 	Number int64
 
 	// Owner has the owner's name
 	Owner string
+	// OwnerZone has the owner's zone
+	OwnerZone string
 
 	Checksum     *IRODSChecksum
 	Status       string
@@ -30,3 +39,13 @@ type IRODSReplica struct {
 func (obj *IRODSReplica) ToString() string {
 	return fmt.Sprintf("<IRODSReplica %d %s %s %s %s>", obj.Number, obj.Status, obj.ResourceName, obj.CreateTime, obj.ModifyTime)
 }
+
+// IsStale returns true if the replica is stale
+func (obj *IRODSReplica) IsStale() bool {
+	return obj.Status == IRODSReplicaStatusStale
+}
+
+// IsGood returns true if the replica is good
+func (obj *IRODSReplica) IsGood() bool {
+	return obj.Status == IRODSReplicaStatusGood
+}
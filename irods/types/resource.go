@@ -20,6 +20,9 @@ type IRODSResource struct {
 	// Context has the context string
 	Context string
 
+	// Status has the status string, "up", "down" or empty
+	Status string
+
 	// CreateTime has creation time
 	CreateTime time.Time
 	// ModifyTime has last modified time
@@ -30,3 +33,57 @@ type IRODSResource struct {
 func (res *IRODSResource) ToString() string {
 	return fmt.Sprintf("<IRODSResource %s: %v>", res.Name, res)
 }
+
+// IsDown returns true if the resource is marked down
+func (res *IRODSResource) IsDown() bool {
+	return res.Status == IRODSResourceStatusDown
+}
+
+// IsArchive returns true if the resource is an archive, e.g., a tape, reading from it may stall until data is staged
+func (res *IRODSResource) IsArchive() bool {
+	return res.Class == IRODSResourceClassArchive || res.Type == IRODSResourceTypeUnivMSS
+}
+
+const (
+	// IRODSResourceStatusUp is a status for a resource that is up
+	IRODSResourceStatusUp string = "up"
+	// IRODSResourceStatusDown is a status for a resource that is down
+	IRODSResourceStatusDown string = "down"
+
+	// IRODSResourceClassArchive is a class for a resource that archives data, e.g., in a compound resource
+	IRODSResourceClassArchive string = "archive"
+	// IRODSResourceTypeUnivMSS is a type for a resource that stores data in a mass storage system, e.g., tapes
+	IRODSResourceTypeUnivMSS string = "univmss"
+)
+
+// IRODSResourceSpace describes free space and status of a resource
+type IRODSResourceSpace struct {
+	Name string
+	// FreeSpace has free space in bytes, -1 if not reported
+	FreeSpace int64
+	// Capacity has capacity in bytes, -1 if not reported
+	// iRODS does not keep capacity of resources, it is read from "capacity" key in resource context if set
+	Capacity int64
+	// Status has the status string, "up", "down" or empty
+	Status string
+	// FreeSpaceTime has the time free space was last updated
+	FreeSpaceTime time.Time
+}
+
+// IsDown returns true if the resource is marked down
+func (space *IRODSResourceSpace) IsDown() bool {
+	return space.Status == IRODSResourceStatusDown
+}
+
+// HasSpaceFor returns true if the resource has free space for the given size, or free space is not reported
+func (space *IRODSResourceSpace) HasSpaceFor(size int64) bool {
+	if space.FreeSpace < 0 {
+		return true
+	}
+	return space.FreeSpace >= size
+}
+
+// ToString stringifies the object
+func (space *IRODSResourceSpace) ToString() string {
+	return fmt.Sprintf("<IRODSResourceSpace %s %d %d %s %s>", space.Name, space.FreeSpace, space.Capacity, space.Status, space.FreeSpaceTime)
+}
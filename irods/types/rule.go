@@ -0,0 +1,92 @@
+package types
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// IRODSRuleExecOut is stdout and stderr of a rule execution, returned as ruleExecOut
+type IRODSRuleExecOut struct {
+	Stdout []byte
+	Stderr []byte
+	Status int
+}
+
+// IRODSRuleParams is labeled input or output parameters of a rule, keyed by labels, e.g., "*name"
+// a value is one of string, int, map[string]string (KeyValPair), []byte (buffer), or *IRODSRuleExecOut
+type IRODSRuleParams map[string]interface{}
+
+// Has returns true if the params have the label
+func (params IRODSRuleParams) Has(label string) bool {
+	_, ok := params[label]
+	return ok
+}
+
+// GetString returns a string param
+func (params IRODSRuleParams) GetString(label string) (string, error) {
+	value, ok := params[label]
+	if !ok {
+		return "", xerrors.Errorf("failed to find rule param %s", label)
+	}
+
+	stringValue, ok := value.(string)
+	if !ok {
+		return "", xerrors.Errorf("rule param %s is not a string, %T", label, value)
+	}
+	return stringValue, nil
+}
+
+// GetInt returns an int param
+func (params IRODSRuleParams) GetInt(label string) (int, error) {
+	value, ok := params[label]
+	if !ok {
+		return 0, xerrors.Errorf("failed to find rule param %s", label)
+	}
+
+	intValue, ok := value.(int)
+	if !ok {
+		return 0, xerrors.Errorf("rule param %s is not an int, %T", label, value)
+	}
+	return intValue, nil
+}
+
+// GetKeyVals returns a KeyValPair param
+func (params IRODSRuleParams) GetKeyVals(label string) (map[string]string, error) {
+	value, ok := params[label]
+	if !ok {
+		return nil, xerrors.Errorf("failed to find rule param %s", label)
+	}
+
+	keyVals, ok := value.(map[string]string)
+	if !ok {
+		return nil, xerrors.Errorf("rule param %s is not a KeyValPair, %T", label, value)
+	}
+	return keyVals, nil
+}
+
+// GetBytes returns a buffer param
+func (params IRODSRuleParams) GetBytes(label string) ([]byte, error) {
+	value, ok := params[label]
+	if !ok {
+		return nil, xerrors.Errorf("failed to find rule param %s", label)
+	}
+
+	bytesValue, ok := value.([]byte)
+	if !ok {
+		return nil, xerrors.Errorf("rule param %s is not a buffer, %T", label, value)
+	}
+	return bytesValue, nil
+}
+
+// GetExecOut returns stdout and stderr of a rule execution param, e.g., ruleExecOut
+func (params IRODSRuleParams) GetExecOut(label string) (*IRODSRuleExecOut, error) {
+	value, ok := params[label]
+	if !ok {
+		return nil, xerrors.Errorf("failed to find rule param %s", label)
+	}
+
+	execOut, ok := value.(*IRODSRuleExecOut)
+	if !ok {
+		return nil, xerrors.Errorf("rule param %s is not an exec out, %T", label, value)
+	}
+	return execOut, nil
+}
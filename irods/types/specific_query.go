@@ -0,0 +1,16 @@
+package types
+
+import (
+	"fmt"
+)
+
+// IRODSSpecificQuery contains a specific query registered in the catalog
+type IRODSSpecificQuery struct {
+	Alias string
+	SQL   string
+}
+
+// ToString stringifies the object
+func (obj *IRODSSpecificQuery) ToString() string {
+	return fmt.Sprintf("<IRODSSpecificQuery %s %s>", obj.Alias, obj.SQL)
+}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"crypto/tls"
 	"crypto/x509"
+	"strings"
 
 	"github.com/hashicorp/go-rootcerts"
 	"golang.org/x/xerrors"
@@ -15,6 +17,24 @@ type IRODSSSLConfig struct {
 	EncryptionAlgorithm string
 	SaltSize            int
 	HashRounds          int
+
+	// PEM-encoded CA certificate or bundle, used if CACertificateFile is not set
+	CACertificate []byte
+	// client certificate and key for mutual TLS, either as files or PEM-encoded bytes
+	ClientCertificateFile string
+	ClientKeyFile         string
+	ClientCertificate     []byte
+	ClientKey             []byte
+	// Optional TLS Server Name, overrides IRODSAccount.ServerNameTLS
+	ServerName string
+	// Skip TLS verification, in addition to IRODSAccount.SkipVerifyTLS
+	InsecureSkipVerify bool
+	// minimum TLS version, e.g., tls.VersionTLS12, 0 uses Go's default
+	MinVersion uint16
+	// TLS cipher suites, empty uses Go's default
+	CipherSuites []uint16
+	// Custom TLS config, other TLS fields are applied on top of a clone of this if set
+	TLSConfig *tls.Config
 }
 
 // CreateIRODSSSLConfig creates IRODSSSLConfig
@@ -30,12 +50,25 @@ func CreateIRODSSSLConfig(caCertFile string, caCertPath string, keySize int, alg
 	}, nil
 }
 
+// CreateIRODSSSLConfigWithTLSConfig creates IRODSSSLConfig with a custom TLS config
+func CreateIRODSSSLConfigWithTLSConfig(tlsConfig *tls.Config, keySize int, algorithm string, saltSize int,
+	hashRounds int) (*IRODSSSLConfig, error) {
+	return &IRODSSSLConfig{
+		EncryptionKeySize:   keySize,
+		EncryptionAlgorithm: algorithm,
+		SaltSize:            saltSize,
+		HashRounds:          hashRounds,
+		TLSConfig:           tlsConfig,
+	}, nil
+}
+
 // LoadCACert loads CA Cert
 func (config *IRODSSSLConfig) LoadCACert() (*x509.CertPool, error) {
 
 	certConfig := &rootcerts.Config{
-		CAFile: config.CACertificateFile,
-		CAPath: config.CACertificatePath,
+		CAFile:        config.CACertificateFile,
+		CACertificate: config.CACertificate,
+		CAPath:        config.CACertificatePath,
 	}
 
 	certPool, err := rootcerts.LoadCACerts(certConfig)
@@ -45,3 +78,112 @@ func (config *IRODSSSLConfig) LoadCACert() (*x509.CertPool, error) {
 
 	return certPool, nil
 }
+
+// HasClientCertificate returns true if client certificate is set
+func (config *IRODSSSLConfig) HasClientCertificate() bool {
+	return len(config.ClientCertificateFile) > 0 || len(config.ClientCertificate) > 0
+}
+
+// LoadClientCertificate loads client certificate and key
+func (config *IRODSSSLConfig) LoadClientCertificate() (tls.Certificate, error) {
+	if len(config.ClientCertificateFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(config.ClientCertificateFile, config.ClientKeyFile)
+		if err != nil {
+			return tls.Certificate{}, xerrors.Errorf("failed to load client certificate file %s: %w", config.ClientCertificateFile, err)
+		}
+		return cert, nil
+	}
+
+	cert, err := tls.X509KeyPair(config.ClientCertificate, config.ClientKey)
+	if err != nil {
+		return tls.Certificate{}, xerrors.Errorf("failed to load client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// GetTLSConfig returns a TLS config for connecting to the given server
+// serverName and skipVerify are used unless ServerName and InsecureSkipVerify are set
+func (config *IRODSSSLConfig) GetTLSConfig(serverName string, skipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+	}
+
+	if tlsConfig.RootCAs == nil {
+		caCertPool, err := config.LoadCACert()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load CA Certificates: %w", err)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if config.HasClientCertificate() {
+		cert, err := config.LoadClientCertificate()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	if len(config.ServerName) > 0 {
+		tlsConfig.ServerName = config.ServerName
+	} else if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = serverName
+	}
+
+	if config.InsecureSkipVerify || skipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if config.MinVersion > 0 {
+		tlsConfig.MinVersion = config.MinVersion
+	}
+
+	if len(config.CipherSuites) > 0 {
+		tlsConfig.CipherSuites = config.CipherSuites
+	}
+
+	return tlsConfig, nil
+}
+
+// GetTLSVersion returns TLS version from string, e.g., "1.2" or "TLS1.2"
+func GetTLSVersion(version string) (uint16, error) {
+	v := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS")
+	v = strings.TrimPrefix(v, "V")
+
+	switch v {
+	case "":
+		return 0, nil
+	case "1.0", "1":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, xerrors.Errorf("unknown TLS version %s", version)
+	}
+}
+
+// GetTLSCipherSuites returns TLS cipher suite IDs from names, e.g., "TLS_AES_128_GCM_SHA256"
+func GetTLSCipherSuites(names []string) ([]uint16, error) {
+	suites := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+
+	ids := []uint16{}
+	for _, name := range names {
+		id, ok := suites[strings.TrimSpace(name)]
+		if !ok {
+			return nil, xerrors.Errorf("unknown TLS cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
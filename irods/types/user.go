@@ -1,6 +1,12 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
 
 // IRODSUserType is a type of iRODS User
 type IRODSUserType string
@@ -16,12 +22,22 @@ const (
 	IRODSUserGroupAdmin IRODSUserType = "groupadmin"
 )
 
+const (
+	// IRODSUserInfoDisabled is a space-separated token in user info marking a disabled user
+	// iRODS does not disable users by itself, applications check IsDisabled, e.g., before granting access
+	IRODSUserInfoDisabled string = "disabled"
+)
+
 // IRODSUser contains irods user information
 type IRODSUser struct {
-	ID   int64
-	Name string
-	Zone string
-	Type IRODSUserType
+	ID         int64         `json:"id"`
+	Name       string        `json:"name"`
+	Zone       string        `json:"zone"`
+	Type       IRODSUserType `json:"type"`
+	Info       string        `json:"info,omitempty"`
+	Comment    string        `json:"comment,omitempty"`
+	CreateTime time.Time     `json:"create_time"`
+	ModifyTime time.Time     `json:"modify_time"`
 }
 
 // IsGroup returns true if type is IRODSUserRodsGroup
@@ -44,7 +60,109 @@ func (user *IRODSUser) IsAdminUser() bool {
 	return user.Type == IRODSUserRodsAdmin
 }
 
+// IsDisabled returns true if the user is marked disabled in info
+func (user *IRODSUser) IsDisabled() bool {
+	for _, token := range strings.Split(user.Info, " ") {
+		if token == IRODSUserInfoDisabled {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkIRODSUserInfoDisabled returns the user info with the disabled marker added, other info is kept
+func MarkIRODSUserInfoDisabled(info string) string {
+	user := IRODSUser{Info: info}
+	if user.IsDisabled() {
+		return info
+	}
+
+	if len(info) == 0 {
+		return IRODSUserInfoDisabled
+	}
+	return info + " " + IRODSUserInfoDisabled
+}
+
+// UnmarkIRODSUserInfoDisabled returns the user info with the disabled marker removed, other info is kept
+func UnmarkIRODSUserInfoDisabled(info string) string {
+	tokens := []string{}
+	for _, token := range strings.Split(info, " ") {
+		if token != IRODSUserInfoDisabled {
+			tokens = append(tokens, token)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
 // ToString stringifies the object
 func (user *IRODSUser) ToString() string {
 	return fmt.Sprintf("<IRODSUser %d %s %s %s>", user.ID, user.Name, user.Zone, string(user.Type))
 }
+
+// GetIdentity returns the zone-qualified identity of the user
+func (user *IRODSUser) GetIdentity() IRODSUserIdentity {
+	return NewIRODSUserIdentity(user.Name, user.Zone)
+}
+
+// IRODSUserIdentity is a zone-qualified user, "user#zone" in text
+// users of the same name in different zones, e.g., federated zones, are different users
+type IRODSUserIdentity struct {
+	Name string `json:"name"`
+	Zone string `json:"zone"`
+}
+
+// NewIRODSUserIdentity creates a new IRODSUserIdentity
+func NewIRODSUserIdentity(name string, zone string) IRODSUserIdentity {
+	return IRODSUserIdentity{
+		Name: name,
+		Zone: zone,
+	}
+}
+
+// ParseIRODSUserIdentity parses a user in "user" or "user#zone" format, defaultZone is used if the zone is not given
+func ParseIRODSUserIdentity(user string, defaultZone string) (IRODSUserIdentity, error) {
+	name, zone, found := strings.Cut(strings.TrimSpace(user), "#")
+	if len(name) == 0 {
+		return IRODSUserIdentity{}, xerrors.Errorf("empty user name in %q", user)
+	}
+
+	if strings.Contains(zone, "#") {
+		return IRODSUserIdentity{}, xerrors.Errorf("multiple zones in %q", user)
+	}
+
+	if !found || len(zone) == 0 {
+		zone = defaultZone
+	}
+
+	return NewIRODSUserIdentity(name, zone), nil
+}
+
+// String returns the identity in "user#zone" format, or the user name if the zone is empty
+func (identity IRODSUserIdentity) String() string {
+	if len(identity.Zone) == 0 {
+		return identity.Name
+	}
+	return fmt.Sprintf("%s#%s", identity.Name, identity.Zone)
+}
+
+// Equals returns true if both name and zone are the same
+func (identity IRODSUserIdentity) Equals(other IRODSUserIdentity) bool {
+	return identity.Name == other.Name && identity.Zone == other.Zone
+}
+
+// IRODSGroup contains irods group information with its member count
+type IRODSGroup struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Zone string `json:"zone"`
+	// Description is the comment of the group
+	Description string    `json:"description,omitempty"`
+	MemberCount int       `json:"member_count"`
+	CreateTime  time.Time `json:"create_time"`
+	ModifyTime  time.Time `json:"modify_time"`
+}
+
+// ToString stringifies the object
+func (group *IRODSGroup) ToString() string {
+	return fmt.Sprintf("<IRODSGroup %d %s %s %d>", group.ID, group.Name, group.Zone, group.MemberCount)
+}
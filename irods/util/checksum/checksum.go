@@ -0,0 +1,171 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// algorithms are checksum algorithms iRODS supports, digest sizes of them are all different
+var algorithms = []types.ChecksumAlgorithm{
+	types.ChecksumAlgorithmMD5,
+	types.ChecksumAlgorithmSHA1,
+	types.ChecksumAlgorithmSHA256,
+	types.ChecksumAlgorithmSHA512,
+	types.ChecksumAlgorithmADLER32,
+}
+
+// Parse parses a checksum string, e.g., "sha2:<base64>", "md5:<hex>" or "<hex>"
+// digests are accepted in hex or base64 for any algorithm, algorithm names in any case, e.g., "SHA256:<hex>"
+// algorithms of digests without algorithm names are told from the digest sizes, 16 bytes are md5 as in iRODS
+// returns a checksum with IRODSChecksumString in the format iRODS uses
+func Parse(checksumString string) (*types.IRODSChecksum, error) {
+	checksumString = strings.TrimSpace(checksumString)
+	if len(checksumString) == 0 {
+		return nil, xerrors.Errorf("empty checksum")
+	}
+
+	algorithmName := ""
+	encodedDigest := checksumString
+	if idx := strings.Index(checksumString, ":"); idx >= 0 {
+		algorithmName = checksumString[:idx]
+		encodedDigest = checksumString[idx+1:]
+	}
+
+	candidates, err := getCandidateAlgorithms(algorithmName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse checksum %q: %w", checksumString, err)
+	}
+
+	for _, digest := range decodeDigest(encodedDigest) {
+		for _, algorithm := range candidates {
+			if len(digest) != types.GetChecksumDigestSize(algorithm) {
+				continue
+			}
+
+			irodsChecksumString, err := types.MakeIRODSChecksumString(algorithm, digest)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to make iRODS checksum string: %w", err)
+			}
+
+			return &types.IRODSChecksum{
+				IRODSChecksumString: irodsChecksumString,
+				Algorithm:           algorithm,
+				Checksum:            digest,
+			}, nil
+		}
+	}
+
+	return nil, xerrors.Errorf("failed to parse checksum %q, digest is not in hex or base64 of the algorithm", checksumString)
+}
+
+// getCandidateAlgorithms returns algorithms the algorithm name may stand for, all algorithms if the name is empty
+func getCandidateAlgorithms(algorithmName string) ([]types.ChecksumAlgorithm, error) {
+	switch strings.ToLower(algorithmName) {
+	case "":
+		return algorithms, nil
+	case "sha2":
+		// iRODS uses sha2 for both
+		return []types.ChecksumAlgorithm{types.ChecksumAlgorithmSHA256, types.ChecksumAlgorithmSHA512}, nil
+	}
+
+	algorithm := types.GetChecksumAlgorithm(algorithmName)
+	if algorithm == types.ChecksumAlgorithmUnknown {
+		return nil, xerrors.Errorf("unknown checksum algorithm %s", algorithmName)
+	}
+	return []types.ChecksumAlgorithm{algorithm}, nil
+}
+
+// decodeDigest returns digests decoded from hex and base64, in the order
+// base64 digests of the supported sizes are padded, so they are never valid hex
+func decodeDigest(encodedDigest string) [][]byte {
+	digests := [][]byte{}
+
+	digest, err := hex.DecodeString(encodedDigest)
+	if err == nil {
+		digests = append(digests, digest)
+	}
+
+	digest, err = base64.StdEncoding.DecodeString(encodedDigest)
+	if err == nil {
+		digests = append(digests, digest)
+	}
+
+	return digests
+}
+
+// Format returns the checksum string of the digest in the format iRODS uses
+func Format(algorithm types.ChecksumAlgorithm, digest []byte) (string, error) {
+	if len(digest) != types.GetChecksumDigestSize(algorithm) {
+		return "", xerrors.Errorf("unexpected digest size %d for checksum algorithm %s", len(digest), algorithm)
+	}
+
+	return types.MakeIRODSChecksumString(algorithm, digest)
+}
+
+// Compute computes the checksum of data read from r until EOF
+func Compute(r io.Reader, algorithm types.ChecksumAlgorithm) (*types.IRODSChecksum, error) {
+	hasher, err := util.GetHash(string(algorithm))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get hash of checksum algorithm %s: %w", algorithm, err)
+	}
+
+	_, err = io.Copy(hasher, r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to compute checksum: %w", err)
+	}
+
+	digest := hasher.Sum(nil)
+
+	irodsChecksumString, err := types.MakeIRODSChecksumString(algorithm, digest)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make iRODS checksum string: %w", err)
+	}
+
+	return &types.IRODSChecksum{
+		IRODSChecksumString: irodsChecksumString,
+		Algorithm:           algorithm,
+		Checksum:            digest,
+	}, nil
+}
+
+// Equal returns true if the checksum strings have the same digest, in any format Parse accepts
+// returns an error if they are of different algorithms, they cannot be compared
+func Equal(checksumString1 string, checksumString2 string) (bool, error) {
+	checksum1, err := Parse(checksumString1)
+	if err != nil {
+		return false, err
+	}
+
+	checksum2, err := Parse(checksumString2)
+	if err != nil {
+		return false, err
+	}
+
+	if checksum1.Algorithm != checksum2.Algorithm {
+		return false, xerrors.Errorf("failed to compare checksums of different algorithms %s and %s", checksum1.Algorithm, checksum2.Algorithm)
+	}
+
+	return bytes.Equal(checksum1.Checksum, checksum2.Checksum), nil
+}
+
+// Verify returns true if data read from r until EOF has the checksum, computed with the algorithm of the checksum
+func Verify(r io.Reader, checksumString string) (bool, error) {
+	checksum, err := Parse(checksumString)
+	if err != nil {
+		return false, err
+	}
+
+	computed, err := Compute(r, checksum.Algorithm)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(checksum.Checksum, computed.Checksum), nil
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	genQueryLikePatternReplacer = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+)
+
+// EscapeGenQueryValue escapes single quotes in a value of GenQuery conditions by doubling them, as in SQL
+func EscapeGenQueryValue(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// QuoteGenQueryValue single-quotes a value of GenQuery conditions, escaping quotes in the value
+func QuoteGenQueryValue(value string) string {
+	return fmt.Sprintf("'%s'", EscapeGenQueryValue(value))
+}
+
+// EscapeGenQueryLikePattern escapes wildcards and backslashes in a value, to match it literally in like conditions
+// quotes are not escaped, use MakeGenQueryLikeCondition to make a condition of the pattern
+func EscapeGenQueryLikePattern(value string) string {
+	return genQueryLikePatternReplacer.Replace(value)
+}
+
+// MakeGenQueryCondition makes a GenQuery condition of the operator and the value quoted, e.g., "<> 'rodsgroup'"
+func MakeGenQueryCondition(operator string, value string) string {
+	return fmt.Sprintf("%s %s", operator, QuoteGenQueryValue(value))
+}
+
+// MakeGenQueryEqualCondition makes a GenQuery condition matching the value, e.g., "= '/zone/home/alice'"
+func MakeGenQueryEqualCondition(value string) string {
+	return MakeGenQueryCondition("=", value)
+}
+
+// MakeGenQueryLikeCondition makes a GenQuery condition matching the pattern in SQL LIKE syntax, e.g., "like 'lab%'"
+// wildcards in the pattern are kept, escape literal parts of the pattern with EscapeGenQueryLikePattern
+func MakeGenQueryLikeCondition(pattern string) string {
+	return MakeGenQueryCondition("like", pattern)
+}
+
+// MakeGenQueryPrefixCondition makes a GenQuery condition matching values starting with the prefix, e.g., "like '/zone/home/%'"
+func MakeGenQueryPrefixCondition(prefix string) string {
+	return MakeGenQueryLikeCondition(EscapeGenQueryLikePattern(prefix) + "%")
+}
+
+// MakeGenQueryInCondition makes a GenQuery condition matching any of the values, e.g., "in ('a', 'b')"
+// long lists are slow in the catalog, split values into batches of a hundred or so
+func MakeGenQueryInCondition(values []string) string {
+	quoted := make([]string, len(values))
+	for idx, value := range values {
+		quoted[idx] = QuoteGenQueryValue(value)
+	}
+	return fmt.Sprintf("in (%s)", strings.Join(quoted, ", "))
+}
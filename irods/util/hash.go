@@ -16,38 +16,38 @@ import (
 	"github.com/cyverse/go-irodsclient/irods/types"
 )
 
-func HashStrings(strs []string, hashAlg string) ([]byte, error) {
+// GetHash returns a new hash of the algorithm
+func GetHash(hashAlg string) (hash.Hash, error) {
 	switch strings.ToLower(hashAlg) {
 	case strings.ToLower(string(types.ChecksumAlgorithmMD5)):
-		return GetHashStrings(strs, md5.New())
+		return md5.New(), nil
 	case strings.ToLower(string(types.ChecksumAlgorithmADLER32)):
-		return GetHashStrings(strs, adler32.New())
+		return adler32.New(), nil
 	case strings.ToLower(string(types.ChecksumAlgorithmSHA1)):
-		return GetHashStrings(strs, sha1.New())
+		return sha1.New(), nil
 	case strings.ToLower(string(types.ChecksumAlgorithmSHA256)):
-		return GetHashStrings(strs, sha256.New())
+		return sha256.New(), nil
 	case strings.ToLower(string(types.ChecksumAlgorithmSHA512)):
-		return GetHashStrings(strs, sha512.New())
+		return sha512.New(), nil
 	default:
 		return nil, xerrors.Errorf("unknown hash algorithm %s", hashAlg)
 	}
 }
 
+func HashStrings(strs []string, hashAlg string) ([]byte, error) {
+	hasher, err := GetHash(hashAlg)
+	if err != nil {
+		return nil, err
+	}
+	return GetHashStrings(strs, hasher)
+}
+
 func HashLocalFile(sourcePath string, hashAlg string) ([]byte, error) {
-	switch strings.ToLower(hashAlg) {
-	case strings.ToLower(string(types.ChecksumAlgorithmMD5)):
-		return GetHashLocalFile(sourcePath, md5.New())
-	case strings.ToLower(string(types.ChecksumAlgorithmADLER32)):
-		return GetHashLocalFile(sourcePath, adler32.New())
-	case strings.ToLower(string(types.ChecksumAlgorithmSHA1)):
-		return GetHashLocalFile(sourcePath, sha1.New())
-	case strings.ToLower(string(types.ChecksumAlgorithmSHA256)):
-		return GetHashLocalFile(sourcePath, sha256.New())
-	case strings.ToLower(string(types.ChecksumAlgorithmSHA512)):
-		return GetHashLocalFile(sourcePath, sha512.New())
-	default:
-		return nil, xerrors.Errorf("unknown hash algorithm %s", hashAlg)
+	hasher, err := GetHash(hashAlg)
+	if err != nil {
+		return nil, err
 	}
+	return GetHashLocalFile(sourcePath, hasher)
 }
 
 func GetHashStrings(strs []string, hashAlg hash.Hash) ([]byte, error) {
@@ -1,8 +1,12 @@
 package util
 
 import (
+	cryptorand "crypto/rand"
+	"math/big"
 	"math/rand"
 	"time"
+
+	"golang.org/x/xerrors"
 )
 
 var (
@@ -21,3 +25,20 @@ func MakeRandomString(size int) string {
 	bs := string(b)
 	return bs
 }
+
+// MakeSecureRandomString returns a random string from crypto/rand, for secrets such as ticket names
+// each letter carries about 5.95 bits, use 22 letters or more for 128 bits
+func MakeSecureRandomString(size int) (string, error) {
+	max := big.NewInt(int64(len(letters)))
+
+	b := make([]rune, size)
+	for i := 0; i < size; i++ {
+		n, err := cryptorand.Int(cryptorand.Reader, max)
+		if err != nil {
+			return "", xerrors.Errorf("failed to read random bytes: %w", err)
+		}
+		b[i] = letters[n.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of events with a token bucket, safe for concurrent use
+// waiters are served in the order they call Wait
+type RateLimiter struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64 // negative if tokens are reserved by waiters
+	last   time.Time
+	mutex  sync.Mutex
+}
+
+// NewRateLimiter creates a new RateLimiter allowing rate events per second with bursts up to burst events
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		mutex:  sync.Mutex{},
+	}
+}
+
+// GetRate returns the number of events allowed per second
+func (limiter *RateLimiter) GetRate() float64 {
+	return limiter.rate
+}
+
+// GetBurst returns the max number of events allowed at once
+func (limiter *RateLimiter) GetBurst() int {
+	return int(limiter.burst)
+}
+
+// Wait blocks until an event is allowed, does not block if rate is not positive
+func (limiter *RateLimiter) Wait() {
+	delay := limiter.reserve()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// reserve takes a token, returns how long to wait until the token is available
+func (limiter *RateLimiter) reserve() time.Duration {
+	if limiter.rate <= 0 {
+		return 0
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.rate
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+	limiter.last = now
+
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+}
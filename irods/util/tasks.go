@@ -11,12 +11,22 @@ const (
 
 // GetNumTasksForParallelTransfer returns the number transfer tasks to be used
 func GetNumTasksForParallelTransfer(dataObjectLength int64) int {
-	if dataObjectLength <= TransferTaskMinLength {
+	return GetNumTasksForParallelTransferWithBlockSize(dataObjectLength, TransferTaskMinLength)
+}
+
+// GetNumTasksForParallelTransferWithBlockSize returns the number transfer tasks to be used, each task transfers blockSize at least
+// TransferTaskMinLength is used if blockSize is not positive
+func GetNumTasksForParallelTransferWithBlockSize(dataObjectLength int64, blockSize int64) int {
+	if blockSize <= 0 {
+		blockSize = TransferTaskMinLength
+	}
+
+	if dataObjectLength <= blockSize {
 		return 1
 	}
 
-	numTasks := int(dataObjectLength / TransferTaskMinLength)
-	if dataObjectLength%TransferTaskMinLength > 0 {
+	numTasks := int(dataObjectLength / blockSize)
+	if dataObjectLength%blockSize > 0 {
 		numTasks++
 	}
 
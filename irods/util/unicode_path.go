@@ -0,0 +1,124 @@
+package util
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/xerrors"
+)
+
+// UnicodeNormalizationForm determines how unicode paths are normalized
+type UnicodeNormalizationForm string
+
+const (
+	// UnicodeNormalizationNone does not normalize paths
+	UnicodeNormalizationNone UnicodeNormalizationForm = ""
+	// UnicodeNormalizationNFC normalizes paths to NFC, used by most linux and windows clients
+	UnicodeNormalizationNFC UnicodeNormalizationForm = "NFC"
+	// UnicodeNormalizationNFD normalizes paths to NFD, used by macOS (HFS+) clients
+	UnicodeNormalizationNFD UnicodeNormalizationForm = "NFD"
+)
+
+// GetUnicodeNormalizationForm returns UnicodeNormalizationForm from string
+func GetUnicodeNormalizationForm(form string) (UnicodeNormalizationForm, error) {
+	switch strings.ToUpper(strings.TrimSpace(form)) {
+	case "", "NONE":
+		return UnicodeNormalizationNone, nil
+	case string(UnicodeNormalizationNFC):
+		return UnicodeNormalizationNFC, nil
+	case string(UnicodeNormalizationNFD):
+		return UnicodeNormalizationNFD, nil
+	default:
+		return UnicodeNormalizationNone, xerrors.Errorf("unknown unicode normalization form %s", form)
+	}
+}
+
+// NormalizeIRODSPath normalizes unicode characters in the path to the given form
+func NormalizeIRODSPath(p string, form UnicodeNormalizationForm) string {
+	switch form {
+	case UnicodeNormalizationNFC:
+		return NormalizeIRODSPathNFC(p)
+	case UnicodeNormalizationNFD:
+		return NormalizeIRODSPathNFD(p)
+	default:
+		return p
+	}
+}
+
+// NormalizeIRODSPathNFC normalizes unicode characters in the path to NFC
+func NormalizeIRODSPathNFC(p string) string {
+	return norm.NFC.String(p)
+}
+
+// NormalizeIRODSPathNFD normalizes unicode characters in the path to NFD
+func NormalizeIRODSPathNFD(p string) string {
+	return norm.NFD.String(p)
+}
+
+// IsNormalizedIRODSPath checks if the path is already in the given form
+func IsNormalizedIRODSPath(p string, form UnicodeNormalizationForm) bool {
+	switch form {
+	case UnicodeNormalizationNFC:
+		return norm.NFC.IsNormalString(p)
+	case UnicodeNormalizationNFD:
+		return norm.NFD.IsNormalString(p)
+	default:
+		return true
+	}
+}
+
+// ValidateIRODSObjectName checks if the name can be used as a data object or collection name
+func ValidateIRODSObjectName(name string) error {
+	if len(name) == 0 {
+		return xerrors.Errorf("empty name")
+	}
+
+	if name == "." || name == ".." {
+		return xerrors.Errorf("invalid name %s", name)
+	}
+
+	if strings.Contains(name, "/") {
+		return xerrors.Errorf("name %s must not contain '/'", name)
+	}
+
+	return validateIRODSPathCharacters(name)
+}
+
+// ValidateIRODSPath checks if the path is an absolute, valid iRODS path
+func ValidateIRODSPath(p string) error {
+	if !strings.HasPrefix(p, "/") {
+		return xerrors.Errorf("path %s is not absolute", p)
+	}
+
+	if len(p) > common.MaxPathLength {
+		return xerrors.Errorf("path %s is too long, must be at most %d bytes", p, common.MaxPathLength)
+	}
+
+	if p == "/" {
+		return nil
+	}
+
+	for _, name := range strings.Split(strings.TrimSuffix(p[1:], "/"), "/") {
+		err := ValidateIRODSObjectName(name)
+		if err != nil {
+			return xerrors.Errorf("invalid path %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func validateIRODSPathCharacters(p string) error {
+	if !utf8.ValidString(p) {
+		return xerrors.Errorf("name %q contains invalid utf-8 characters", p)
+	}
+
+	for _, r := range p {
+		// iRODS uses null-terminated strings and other control characters can't be transferred in XML
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return xerrors.Errorf("name %q contains a control character %U", p, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,992 @@
+package mock
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+const (
+	// MockResourceName is a name of the only resource the mock server has
+	MockResourceName string = "demoResc"
+	// MockVaultPath is a (fake) physical path of the resource vault
+	MockVaultPath string = "/var/lib/irods/Vault"
+)
+
+type mockUser struct {
+	ID         int64
+	Name       string
+	Zone       string
+	Password   string
+	Type       types.IRODSUserType
+	Info       string
+	Comment    string
+	CreateTime time.Time
+	ModifyTime time.Time
+	Meta       []*types.IRODSMeta
+	Groups     []string // names of groups the user belongs to
+
+	QuotaUsage     int64     // usage on the mock resource
+	QuotaUsageTime time.Time // zero if usage is not calculated
+}
+
+type mockCollection struct {
+	ID          int64
+	Path        string
+	Owner       string
+	Inheritance bool
+	Access      map[string]types.IRODSAccessLevelType // access levels of users other than the owner
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	Meta        []*types.IRODSMeta
+}
+
+type mockDataObject struct {
+	ID         int64
+	Collection *mockCollection
+	Name       string
+	Owner      string
+	DataType   string
+	Data       []byte
+	Checksum   string                                // iRODS checksum string, cleared when data is modified
+	Replicas   []string                              // resources having replicas, the mock resource when created
+	Stale      map[string]int64                      // resources having stale replicas, with sizes the replicas were left at
+	Access     map[string]types.IRODSAccessLevelType // access levels of users other than the owner
+	CreateTime time.Time
+	ModifyTime time.Time
+	Meta       []*types.IRODSMeta
+}
+
+type mockTicket struct {
+	ID             int64
+	Name           string
+	Type           types.TicketType
+	Owner          string
+	ObjectType     types.ObjectType
+	Path           string
+	UsesLimit      int64
+	UsesCount      int64
+	WriteFileLimit int64
+	WriteFileCount int64
+	WriteByteLimit int64
+	WriteByteCount int64
+}
+
+// GetPath returns a full path of the data object
+func (obj *mockDataObject) GetPath() string {
+	return util.MakeIRODSPath(obj.Collection.Path, obj.Name)
+}
+
+// hasReplica returns true if the data object has a replica in the resource
+func (obj *mockDataObject) hasReplica(resource string) bool {
+	for _, replica := range obj.Replicas {
+		if replica == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// mockResource is a resource other than the mock resource
+type mockResource struct {
+	ID     int64
+	Name   string
+	Type   string
+	Class  string
+	Status string
+}
+
+// mockCatalog is an in-memory iCAT
+type mockCatalog struct {
+	zone        string
+	remoteZones map[string]bool // federated zones, their collections are kept in this catalog
+	nextID      int64
+	users       map[string]*mockUser
+	collections map[string]*mockCollection
+	dataObjects map[string]*mockDataObject
+	rescMeta    []*types.IRODSMeta
+	rescNames   map[string]bool          // resources other than the mock resource, replica locations only
+	rescInfo    map[string]*mockResource // types, classes and statuses of resources other than the mock resource, found by queries
+	sqlQueries  map[string]string        // sqls of specific queries registered, key is alias
+	tickets     map[string]*mockTicket
+	mutex       sync.Mutex
+
+	rescContext       string
+	rescFreeSpace     string
+	rescFreeSpaceTime string
+	rescStatus        string
+}
+
+// newMockCatalog creates a mockCatalog with a zone skeleton
+func newMockCatalog(zone string) *mockCatalog {
+	catalog := &mockCatalog{
+		zone:        zone,
+		remoteZones: map[string]bool{},
+		nextID:      10000,
+		users:       map[string]*mockUser{},
+		collections: map[string]*mockCollection{},
+		dataObjects: map[string]*mockDataObject{},
+		rescNames:   map[string]bool{},
+		rescInfo:    map[string]*mockResource{},
+		sqlQueries:  map[string]string{},
+		tickets:     map[string]*mockTicket{},
+		mutex:       sync.Mutex{},
+	}
+
+	for _, p := range []string{"/", "/" + zone, "/" + zone + "/home", "/" + zone + "/trash", "/" + zone + "/home/public"} {
+		catalog.collections[p] = catalog.newCollection(p, "")
+	}
+
+	return catalog
+}
+
+func (catalog *mockCatalog) newID() int64 {
+	catalog.nextID++
+	return catalog.nextID
+}
+
+func (catalog *mockCatalog) newCollection(path string, owner string) *mockCollection {
+	now := time.Now()
+	return &mockCollection{
+		ID:         catalog.newID(),
+		Path:       path,
+		Owner:      owner,
+		Access:     map[string]types.IRODSAccessLevelType{},
+		CreateTime: now,
+		ModifyTime: now,
+		Meta:       []*types.IRODSMeta{},
+	}
+}
+
+// addUser adds a user and its home collection
+func (catalog *mockCatalog) addUser(name string, password string, userType types.IRODSUserType) error {
+	if _, ok := catalog.users[name]; ok {
+		return types.NewIRODSError(common.CATALOG_ALREADY_HAS_ITEM_BY_THAT_NAME)
+	}
+
+	now := time.Now()
+	catalog.users[name] = &mockUser{
+		ID:         catalog.newID(),
+		Name:       name,
+		Zone:       catalog.zone,
+		Password:   password,
+		Type:       userType,
+		CreateTime: now,
+		ModifyTime: now,
+		Meta:       []*types.IRODSMeta{},
+	}
+
+	if userType != types.IRODSUserRodsGroup {
+		home := util.MakeIRODSPath("/"+catalog.zone+"/home", name)
+		if _, ok := catalog.collections[home]; !ok {
+			catalog.collections[home] = catalog.newCollection(home, name)
+		}
+	}
+	return nil
+}
+
+// addRemoteUser adds a user of a federated zone and its home collection in the zone
+func (catalog *mockCatalog) addRemoteUser(name string, zone string, password string, userType types.IRODSUserType) error {
+	if !catalog.remoteZones[zone] {
+		return types.NewIRODSError(common.SYS_INVALID_ZONE_NAME)
+	}
+
+	key := name + "#" + zone
+	if _, ok := catalog.users[key]; ok {
+		return types.NewIRODSError(common.CATALOG_ALREADY_HAS_ITEM_BY_THAT_NAME)
+	}
+
+	now := time.Now()
+	catalog.users[key] = &mockUser{
+		ID:         catalog.newID(),
+		Name:       name,
+		Zone:       zone,
+		Password:   password,
+		Type:       userType,
+		CreateTime: now,
+		ModifyTime: now,
+		Meta:       []*types.IRODSMeta{},
+	}
+
+	if userType != types.IRODSUserRodsGroup {
+		home := util.MakeIRODSPath("/"+zone+"/home", name)
+		if _, ok := catalog.collections[home]; !ok {
+			catalog.collections[home] = catalog.newCollection(home, key)
+		}
+	}
+	return nil
+}
+
+// createTicket creates a ticket for a data object or a collection
+func (catalog *mockCatalog) createTicket(name string, ticketType types.TicketType, path string, owner string) error {
+	if _, ok := catalog.tickets[name]; ok {
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	path = util.GetCorrectIRODSPath(path)
+
+	objectType := types.ObjectTypeDataObject
+	if _, ok := catalog.collections[path]; ok {
+		objectType = types.ObjectTypeCollection
+	} else if _, ok := catalog.dataObjects[path]; !ok {
+		return types.NewIRODSError(common.CAT_UNKNOWN_FILE)
+	}
+
+	catalog.tickets[name] = &mockTicket{
+		ID:         catalog.newID(),
+		Name:       name,
+		Type:       ticketType,
+		Owner:      owner,
+		ObjectType: objectType,
+		Path:       path,
+	}
+	return nil
+}
+
+// modifyTicket sets a limit of a ticket, restrictions and expiry are accepted, but not kept
+func (catalog *mockCatalog) modifyTicket(name string, field string, value string) error {
+	ticket, ok := catalog.tickets[name]
+	if !ok {
+		return types.NewIRODSError(common.CAT_TICKET_INVALID)
+	}
+
+	var limit *int64
+	switch field {
+	case "uses":
+		limit = &ticket.UsesLimit
+	case "write-file":
+		limit = &ticket.WriteFileLimit
+	case "write-bytes":
+		limit = &ticket.WriteByteLimit
+	default:
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	*limit = parsed
+	return nil
+}
+
+// deleteTicket deletes a ticket
+func (catalog *mockCatalog) deleteTicket(name string) error {
+	if _, ok := catalog.tickets[name]; !ok {
+		return types.NewIRODSError(common.CAT_TICKET_INVALID)
+	}
+
+	delete(catalog.tickets, name)
+	return nil
+}
+
+// hasZone returns true if the zone is the local zone or a federated zone, empty means the local zone
+func (catalog *mockCatalog) hasZone(zone string) bool {
+	return len(zone) == 0 || zone == catalog.zone || catalog.remoteZones[zone]
+}
+
+// getUser returns a user, name can be in 'user#zone' form, users of other zones are found in that form only
+func (catalog *mockCatalog) getUser(name string) (*mockUser, error) {
+	name, zone, _ := strings.Cut(name, "#")
+	if len(zone) > 0 && zone != catalog.zone {
+		name = name + "#" + zone
+	}
+
+	user, ok := catalog.users[name]
+	if !ok {
+		return nil, types.NewIRODSError(common.CAT_INVALID_USER)
+	}
+	return user, nil
+}
+
+// userKey returns the key of a user in users, 'user#zone' for users of other zones
+func (catalog *mockCatalog) userKey(user *mockUser) string {
+	if user.Zone != catalog.zone {
+		return user.Name + "#" + user.Zone
+	}
+	return user.Name
+}
+
+// splitOwner returns the name and the zone of an owner kept as a user key
+func (catalog *mockCatalog) splitOwner(owner string) (string, string) {
+	if name, zone, ok := strings.Cut(owner, "#"); ok {
+		return name, zone
+	}
+	return owner, catalog.zone
+}
+
+// addGroupMember adds a user to a group
+func (catalog *mockCatalog) addGroupMember(group string, user string) error {
+	mockGroup, err := catalog.getUser(group)
+	if err != nil {
+		return err
+	}
+
+	if mockGroup.Type != types.IRODSUserRodsGroup {
+		return types.NewIRODSError(common.CAT_INVALID_GROUP)
+	}
+
+	mockUser, err := catalog.getUser(user)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range mockUser.Groups {
+		if g == mockGroup.Name {
+			return nil
+		}
+	}
+
+	mockUser.Groups = append(mockUser.Groups, mockGroup.Name)
+	return nil
+}
+
+// removeGroupMember removes a user from a group
+func (catalog *mockCatalog) removeGroupMember(group string, user string) error {
+	mockGroup, err := catalog.getUser(group)
+	if err != nil {
+		return err
+	}
+
+	if mockGroup.Type != types.IRODSUserRodsGroup {
+		return types.NewIRODSError(common.CAT_INVALID_GROUP)
+	}
+
+	mockUser, err := catalog.getUser(user)
+	if err != nil {
+		return err
+	}
+
+	groups := []string{}
+	for _, g := range mockUser.Groups {
+		if g != mockGroup.Name {
+			groups = append(groups, g)
+		}
+	}
+
+	mockUser.Groups = groups
+	return nil
+}
+
+// modifyUser changes the type or info of a user
+func (catalog *mockCatalog) modifyUser(name string, field string, value string) error {
+	mockUser, err := catalog.getUser(name)
+	if err != nil {
+		return err
+	}
+
+	switch field {
+	case "type":
+		mockUser.Type = types.IRODSUserType(value)
+	case "info":
+		mockUser.Info = value
+	case "comment":
+		mockUser.Comment = value
+	default:
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	mockUser.ModifyTime = time.Now()
+	return nil
+}
+
+// removeUser removes a user or a group, members of a group are removed from the group
+func (catalog *mockCatalog) removeUser(name string) error {
+	mockUser, err := catalog.getUser(name)
+	if err != nil {
+		return err
+	}
+
+	if mockUser.Type == types.IRODSUserRodsGroup {
+		for _, user := range catalog.users {
+			groups := []string{}
+			for _, g := range user.Groups {
+				if g != mockUser.Name {
+					groups = append(groups, g)
+				}
+			}
+			user.Groups = groups
+		}
+	}
+
+	delete(catalog.users, catalog.userKey(mockUser))
+	return nil
+}
+
+// makeCollection creates a collection
+func (catalog *mockCatalog) makeCollection(path string, owner string, recurse bool) error {
+	path = util.GetCorrectIRODSPath(path)
+
+	if _, ok := catalog.dataObjects[path]; ok {
+		return types.NewIRODSError(common.CAT_NAME_EXISTS_AS_DATAOBJ)
+	}
+
+	if _, ok := catalog.collections[path]; ok {
+		if recurse {
+			return nil
+		}
+		return types.NewIRODSError(common.CAT_NAME_EXISTS_AS_COLLECTION)
+	}
+
+	parent := util.GetIRODSPathDirname(path)
+	if _, ok := catalog.collections[parent]; !ok {
+		if !recurse {
+			return types.NewIRODSError(common.CAT_UNKNOWN_COLLECTION)
+		}
+
+		err := catalog.makeCollection(parent, owner, recurse)
+		if err != nil {
+			return err
+		}
+	}
+
+	catalog.collections[path] = catalog.newCollection(path, owner)
+	return nil
+}
+
+// removeCollection removes a collection
+func (catalog *mockCatalog) removeCollection(path string, recurse bool) error {
+	path = util.GetCorrectIRODSPath(path)
+
+	if _, ok := catalog.collections[path]; !ok {
+		return types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	prefix := path + "/"
+	children := []string{}
+	for p := range catalog.collections {
+		if strings.HasPrefix(p, prefix) {
+			children = append(children, p)
+		}
+	}
+
+	objects := []string{}
+	for p := range catalog.dataObjects {
+		if strings.HasPrefix(p, prefix) {
+			objects = append(objects, p)
+		}
+	}
+
+	if !recurse && (len(children) > 0 || len(objects) > 0) {
+		return types.NewIRODSError(common.CAT_COLLECTION_NOT_EMPTY)
+	}
+
+	for _, p := range objects {
+		delete(catalog.dataObjects, p)
+	}
+
+	for _, p := range children {
+		delete(catalog.collections, p)
+	}
+
+	delete(catalog.collections, path)
+	return nil
+}
+
+// createDataObject creates an empty data object, or truncates existing one if force is set
+func (catalog *mockCatalog) createDataObject(path string, owner string, dataType string, force bool) (*mockDataObject, error) {
+	path = util.GetCorrectIRODSPath(path)
+
+	if _, ok := catalog.collections[path]; ok {
+		return nil, types.NewIRODSError(common.CAT_NAME_EXISTS_AS_COLLECTION)
+	}
+
+	if obj, ok := catalog.dataObjects[path]; ok {
+		if !force {
+			return nil, types.NewIRODSError(common.OVERWRITE_WITHOUT_FORCE_FLAG)
+		}
+
+		obj.Data = []byte{}
+		obj.Checksum = ""
+		obj.ModifyTime = time.Now()
+		return obj, nil
+	}
+
+	collPath := util.GetIRODSPathDirname(path)
+	name := util.GetIRODSPathFileName(path)
+	coll, ok := catalog.collections[collPath]
+	if !ok {
+		return nil, types.NewIRODSError(common.CAT_UNKNOWN_COLLECTION)
+	}
+
+	if len(dataType) == 0 {
+		dataType = string(types.GENERIC_DT)
+	}
+
+	now := time.Now()
+	obj := &mockDataObject{
+		ID:         catalog.newID(),
+		Collection: coll,
+		Name:       name,
+		Owner:      owner,
+		DataType:   dataType,
+		Data:       []byte{},
+		Replicas:   []string{MockResourceName},
+		Stale:      map[string]int64{},
+		Access:     map[string]types.IRODSAccessLevelType{},
+		CreateTime: now,
+		ModifyTime: now,
+		Meta:       []*types.IRODSMeta{},
+	}
+
+	catalog.dataObjects[path] = obj
+	return obj, nil
+}
+
+// getDataObject returns a data object
+func (catalog *mockCatalog) getDataObject(path string) (*mockDataObject, error) {
+	path = util.GetCorrectIRODSPath(path)
+
+	obj, ok := catalog.dataObjects[path]
+	if !ok {
+		return nil, types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+	return obj, nil
+}
+
+// hasResource returns true if the resource exists
+func (catalog *mockCatalog) hasResource(resource string) bool {
+	return resource == MockResourceName || catalog.rescNames[resource]
+}
+
+// replicateDataObject makes a replica of a data object on the resource, the mock resource if resource is empty
+func (catalog *mockCatalog) replicateDataObject(path string, resource string) error {
+	obj, err := catalog.getDataObject(path)
+	if err != nil {
+		return err
+	}
+
+	if len(resource) == 0 {
+		resource = MockResourceName
+	}
+
+	if !catalog.hasResource(resource) {
+		return types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST)
+	}
+
+	for _, replica := range obj.Replicas {
+		if replica == resource {
+			// brings a stale replica up to date
+			delete(obj.Stale, resource)
+			return nil
+		}
+	}
+
+	obj.Replicas = append(obj.Replicas, resource)
+	return nil
+}
+
+// moveReplica moves a replica of a data object from the source resource to the dest resource
+func (catalog *mockCatalog) moveReplica(path string, srcResource string, destResource string) error {
+	obj, err := catalog.getDataObject(path)
+	if err != nil {
+		return err
+	}
+
+	if !catalog.hasResource(destResource) {
+		return types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST)
+	}
+
+	srcIdx := -1
+	for idx, replica := range obj.Replicas {
+		if replica == destResource {
+			return types.NewIRODSError(common.SYS_COPY_ALREADY_IN_RESC)
+		}
+
+		if replica == srcResource || (len(srcResource) == 0 && srcIdx < 0) {
+			srcIdx = idx
+		}
+	}
+
+	if srcIdx < 0 {
+		return types.NewIRODSError(common.SYS_REPLICA_DOES_NOT_EXIST)
+	}
+
+	if size, ok := obj.Stale[obj.Replicas[srcIdx]]; ok {
+		delete(obj.Stale, obj.Replicas[srcIdx])
+		obj.Stale[destResource] = size
+	}
+
+	obj.Replicas[srcIdx] = destResource
+	return nil
+}
+
+// removeDataObject removes a data object
+func (catalog *mockCatalog) removeDataObject(path string) error {
+	path = util.GetCorrectIRODSPath(path)
+
+	if _, ok := catalog.dataObjects[path]; !ok {
+		return types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	delete(catalog.dataObjects, path)
+	return nil
+}
+
+// rename moves a data object or a collection
+// an existing dest data object is replaced if force is set, a data object is kept in the resource if resource is given
+func (catalog *mockCatalog) rename(srcPath string, destPath string, resource string, force bool) error {
+	srcPath = util.GetCorrectIRODSPath(srcPath)
+	destPath = util.GetCorrectIRODSPath(destPath)
+
+	srcZone, _ := util.GetIRODSZone(srcPath)
+	destZone, _ := util.GetIRODSZone(destPath)
+	if srcZone != destZone {
+		return types.NewIRODSError(common.SYS_CROSS_ZONE_MV_NOT_SUPPORTED)
+	}
+
+	if len(resource) > 0 && !catalog.hasResource(resource) {
+		return types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST)
+	}
+
+	if _, ok := catalog.collections[destPath]; ok {
+		return types.NewIRODSError(common.CAT_NAME_EXISTS_AS_COLLECTION)
+	}
+
+	if _, ok := catalog.dataObjects[destPath]; ok {
+		// only a data object replaces the dest data object
+		if _, srcIsDataObject := catalog.dataObjects[srcPath]; !force || !srcIsDataObject || srcPath == destPath {
+			return types.NewIRODSError(common.CAT_NAME_EXISTS_AS_DATAOBJ)
+		}
+	}
+
+	destCollPath := util.GetIRODSPathDirname(destPath)
+	destName := util.GetIRODSPathFileName(destPath)
+	destColl, ok := catalog.collections[destCollPath]
+	if !ok {
+		return types.NewIRODSError(common.CAT_UNKNOWN_COLLECTION)
+	}
+
+	if obj, ok := catalog.dataObjects[srcPath]; ok {
+		// a rename does not move replicas between resources, like rename(2) across devices
+		if len(resource) > 0 && !obj.hasReplica(resource) {
+			return types.NewIRODSError(common.ErrorCode(int(common.UNIX_FILE_RENAME_ERR) - int(common.EXDEV)))
+		}
+
+		delete(catalog.dataObjects, srcPath)
+		obj.Collection = destColl
+		obj.Name = destName
+		catalog.dataObjects[destPath] = obj
+		return nil
+	}
+
+	coll, ok := catalog.collections[srcPath]
+	if !ok {
+		return types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	if strings.HasPrefix(destPath, srcPath+"/") {
+		return types.NewIRODSError(common.SYS_INVALID_FILE_PATH)
+	}
+
+	// move sub-collections first, data objects refer to collections
+	prefix := srcPath + "/"
+	for p, c := range catalog.collections {
+		if strings.HasPrefix(p, prefix) {
+			delete(catalog.collections, p)
+			c.Path = destPath + "/" + p[len(prefix):]
+			catalog.collections[c.Path] = c
+		}
+	}
+
+	delete(catalog.collections, srcPath)
+	coll.Path = destPath
+	catalog.collections[destPath] = coll
+
+	for p, obj := range catalog.dataObjects {
+		if strings.HasPrefix(p, prefix) {
+			delete(catalog.dataObjects, p)
+			catalog.dataObjects[obj.GetPath()] = obj
+		}
+	}
+
+	return nil
+}
+
+// copyDataObject copies a data object
+func (catalog *mockCatalog) copyDataObject(srcPath string, destPath string, owner string, force bool) error {
+	src, err := catalog.getDataObject(srcPath)
+	if err != nil {
+		return err
+	}
+
+	dest, err := catalog.createDataObject(destPath, owner, src.DataType, force)
+	if err != nil {
+		return err
+	}
+
+	dest.Data = make([]byte, len(src.Data))
+	copy(dest.Data, src.Data)
+	dest.Checksum = src.Checksum
+	return nil
+}
+
+// setInheritance sets ACL inheritance of a collection
+func (catalog *mockCatalog) setInheritance(path string, inherit bool) error {
+	coll, ok := catalog.collections[util.GetCorrectIRODSPath(path)]
+	if !ok {
+		return types.NewIRODSError(common.CAT_UNKNOWN_COLLECTION)
+	}
+
+	coll.Inheritance = inherit
+	return nil
+}
+
+// setAccess sets an access level of a user on a data object or a collection, null access removes it
+func (catalog *mockCatalog) setAccess(path string, user string, accessLevel types.IRODSAccessLevelType, recurse bool) error {
+	path = util.GetCorrectIRODSPath(path)
+
+	mockUser, err := catalog.getUser(user)
+	if err != nil {
+		return err
+	}
+	user = catalog.userKey(mockUser)
+
+	if obj, ok := catalog.dataObjects[path]; ok {
+		setAccessLevel(obj.Access, obj.Owner, user, accessLevel)
+		return nil
+	}
+
+	coll, ok := catalog.collections[path]
+	if !ok {
+		return types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	setAccessLevel(coll.Access, coll.Owner, user, accessLevel)
+
+	if recurse {
+		prefix := strings.TrimSuffix(path, "/") + "/"
+		for p, c := range catalog.collections {
+			if strings.HasPrefix(p, prefix) {
+				setAccessLevel(c.Access, c.Owner, user, accessLevel)
+			}
+		}
+
+		for p, obj := range catalog.dataObjects {
+			if strings.HasPrefix(p, prefix) {
+				setAccessLevel(obj.Access, obj.Owner, user, accessLevel)
+			}
+		}
+	}
+	return nil
+}
+
+// setAccessLevel updates an access map, the owner has own access unless set otherwise
+func setAccessLevel(access map[string]types.IRODSAccessLevelType, owner string, user string, accessLevel types.IRODSAccessLevelType) {
+	if user == owner {
+		// the owner name is kept as in the iCAT, only the access changes, null marks the access removed
+		access[user] = accessLevel
+		return
+	}
+
+	if accessLevel == types.IRODSAccessLevelNull {
+		delete(access, user)
+		return
+	}
+	access[user] = accessLevel
+}
+
+// mockAccess is an access level of a user
+type mockAccess struct {
+	User        *mockUser
+	AccessLevel types.IRODSAccessLevelType
+}
+
+// getAccesses returns accesses of the owner and other users, sorted by user name
+func (catalog *mockCatalog) getAccesses(owner string, access map[string]types.IRODSAccessLevelType) []mockAccess {
+	accesses := []mockAccess{}
+	if _, ok := access[owner]; !ok {
+		if user, err := catalog.getUser(owner); err == nil {
+			accesses = append(accesses, mockAccess{User: user, AccessLevel: types.IRODSAccessLevelOwner})
+		}
+	}
+
+	for name, accessLevel := range access {
+		if accessLevel == types.IRODSAccessLevelNull {
+			continue
+		}
+
+		if user, err := catalog.getUser(name); err == nil {
+			accesses = append(accesses, mockAccess{User: user, AccessLevel: accessLevel})
+		}
+	}
+
+	sort.Slice(accesses, func(i int, j int) bool {
+		return accesses[i].User.Name < accesses[j].User.Name
+	})
+	return accesses
+}
+
+// getMetaHolder returns a pointer to the metadata list of an item
+func (catalog *mockCatalog) getMetaHolder(itemType types.IRODSMetaItemType, name string) (*[]*types.IRODSMeta, error) {
+	switch itemType {
+	case types.IRODSDataObjectMetaItemType:
+		obj, err := catalog.getDataObject(name)
+		if err != nil {
+			return nil, types.NewIRODSError(common.CAT_UNKNOWN_FILE)
+		}
+		return &obj.Meta, nil
+	case types.IRODSCollectionMetaItemType:
+		coll, ok := catalog.collections[util.GetCorrectIRODSPath(name)]
+		if !ok {
+			return nil, types.NewIRODSError(common.CAT_UNKNOWN_COLLECTION)
+		}
+		return &coll.Meta, nil
+	case types.IRODSUserMetaItemType:
+		user, err := catalog.getUser(name)
+		if err != nil {
+			return nil, err
+		}
+		return &user.Meta, nil
+	case types.IRODSResourceMetaItemType:
+		if name != MockResourceName {
+			return nil, types.NewIRODSError(common.CAT_INVALID_RESOURCE)
+		}
+		return &catalog.rescMeta, nil
+	default:
+		return nil, types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+}
+
+// modifyMeta runs an imeta-like operation
+func (catalog *mockCatalog) modifyMeta(operation string, itemType types.IRODSMetaItemType, name string, meta *types.IRODSMeta) error {
+	holder, err := catalog.getMetaHolder(itemType, name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	switch operation {
+	case "add", "adda":
+		for _, m := range *holder {
+			if m.Name == meta.Name && m.Value == meta.Value && m.Units == meta.Units {
+				return types.NewIRODSError(common.CATALOG_ALREADY_HAS_ITEM_BY_THAT_NAME)
+			}
+		}
+
+		*holder = append(*holder, &types.IRODSMeta{
+			AVUID:      catalog.newID(),
+			Name:       meta.Name,
+			Value:      meta.Value,
+			Units:      meta.Units,
+			CreateTime: now,
+			ModifyTime: now,
+		})
+	case "set":
+		kept := []*types.IRODSMeta{}
+		for _, m := range *holder {
+			if m.Name != meta.Name {
+				kept = append(kept, m)
+			}
+		}
+
+		*holder = append(kept, &types.IRODSMeta{
+			AVUID:      catalog.newID(),
+			Name:       meta.Name,
+			Value:      meta.Value,
+			Units:      meta.Units,
+			CreateTime: now,
+			ModifyTime: now,
+		})
+	case "rm", "rmw", "rmi":
+		kept := []*types.IRODSMeta{}
+		for _, m := range *holder {
+			if !matchMeta(operation, m, meta) {
+				kept = append(kept, m)
+			}
+		}
+
+		if len(kept) == len(*holder) {
+			return types.NewIRODSError(common.CAT_SUCCESS_BUT_WITH_NO_INFO)
+		}
+		*holder = kept
+	default:
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	return nil
+}
+
+func matchMeta(operation string, m *types.IRODSMeta, target *types.IRODSMeta) bool {
+	switch operation {
+	case "rmi":
+		return m.AVUID == target.AVUID
+	case "rmw":
+		return matchLike(m.Name, target.Name) && matchLike(m.Value, target.Value) && (len(target.Units) == 0 || matchLike(m.Units, target.Units))
+	default:
+		return m.Name == target.Name && m.Value == target.Value && (len(target.Units) == 0 || m.Units == target.Units)
+	}
+}
+
+// sortedCollections returns collections sorted by path
+func (catalog *mockCatalog) sortedCollections() []*mockCollection {
+	colls := make([]*mockCollection, 0, len(catalog.collections))
+	for _, coll := range catalog.collections {
+		colls = append(colls, coll)
+	}
+
+	sort.Slice(colls, func(i int, j int) bool {
+		return colls[i].Path < colls[j].Path
+	})
+	return colls
+}
+
+// sortedDataObjects returns data objects sorted by path
+func (catalog *mockCatalog) sortedDataObjects() []*mockDataObject {
+	objs := make([]*mockDataObject, 0, len(catalog.dataObjects))
+	for _, obj := range catalog.dataObjects {
+		objs = append(objs, obj)
+	}
+
+	sort.Slice(objs, func(i int, j int) bool {
+		return objs[i].GetPath() < objs[j].GetPath()
+	})
+	return objs
+}
+
+// sortedTickets returns tickets sorted by name
+func (catalog *mockCatalog) sortedTickets() []*mockTicket {
+	tickets := make([]*mockTicket, 0, len(catalog.tickets))
+	for _, ticket := range catalog.tickets {
+		tickets = append(tickets, ticket)
+	}
+
+	sort.Slice(tickets, func(i int, j int) bool {
+		return tickets[i].Name < tickets[j].Name
+	})
+	return tickets
+}
+
+// sortedUsers returns users sorted by name
+func (catalog *mockCatalog) sortedUsers() []*mockUser {
+	users := make([]*mockUser, 0, len(catalog.users))
+	for _, user := range catalog.users {
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i int, j int) bool {
+		return users[i].Name < users[j].Name
+	})
+	return users
+}
+
+// sortedResources returns resources with types, classes and statuses, sorted by name
+func (catalog *mockCatalog) sortedResources() []*mockResource {
+	resources := make([]*mockResource, 0, len(catalog.rescInfo))
+	for _, resource := range catalog.rescInfo {
+		resources = append(resources, resource)
+	}
+
+	sort.Slice(resources, func(i int, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+	return resources
+}
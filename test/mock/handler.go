@@ -0,0 +1,1361 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/auth"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mockFileDescriptor is an opened data object
+type mockFileDescriptor struct {
+	object *mockDataObject
+	offset int64
+	flags  int
+	// checksum is computed and registered on close if set
+	registerChecksum bool
+	// checksum given by the client to verify on close, empty if not verified
+	verifyChecksum string
+	// number of bytes written through the descriptor
+	bytesWritten int64
+}
+
+// mockConnectionHandler serves a single client connection
+type mockConnectionHandler struct {
+	server         *IRODSMockServer
+	socket         net.Conn
+	startup        *message.IRODSMessageStartupPack
+	user           *mockUser
+	version        *types.IRODSVersion
+	challenge      []byte
+	descriptors    map[int]*mockFileDescriptor
+	nextDescriptor int
+	// descriptors of portal operations to complete, with paths of data objects
+	portals map[int]string
+}
+
+func newMockConnectionHandler(server *IRODSMockServer, socket net.Conn) *mockConnectionHandler {
+	return &mockConnectionHandler{
+		server:         server,
+		socket:         socket,
+		descriptors:    map[int]*mockFileDescriptor{},
+		nextDescriptor: 3,
+		portals:        map[int]string{},
+	}
+}
+
+// Serve handles the connection until the client disconnects
+func (handler *mockConnectionHandler) Serve() {
+	logger := log.WithFields(log.Fields{
+		"package":  "mock",
+		"struct":   "mockConnectionHandler",
+		"function": "Serve",
+	})
+
+	defer handler.socket.Close()
+
+	err := handler.startUp()
+	if err != nil {
+		logger.Debugf("failed to start up a connection: %v", err)
+		return
+	}
+
+	for {
+		msg, err := handler.readMessage()
+		if err != nil {
+			if err != io.EOF {
+				logger.Debugf("failed to read a message: %v", err)
+			}
+			return
+		}
+
+		switch msg.Header.Type {
+		case message.RODS_MESSAGE_DISCONNECT_TYPE:
+			return
+		case message.RODS_MESSAGE_HEARTBEAT_TYPE:
+			// echo back
+			delay := handler.server.countHeartbeat()
+			time.Sleep(delay)
+
+			heartbeat, _ := message.NewIRODSMessageHeartbeat().GetMessage()
+			err = handler.writeMessage(heartbeat)
+			if err != nil {
+				logger.Debugf("failed to write a message: %v", err)
+				return
+			}
+		case message.RODS_MESSAGE_API_REQ_TYPE:
+			if handler.isStalledTransfer(msg) {
+				continue
+			}
+
+			var reply *message.IRODSMessage
+			if handler.isNativeProtocol() {
+				err = handler.unpackNativeRequest(msg)
+				if err != nil {
+					logger.Debugf("failed to unpack a message: %v", err)
+					reply = makeReply(int32(common.SYS_PACK_INSTRUCT_FORMAT_ERR), nil, nil)
+				}
+			}
+
+			if reply == nil {
+				reply = handler.handleAPI(msg)
+			}
+
+			if handler.isNativeProtocol() {
+				err = handler.packNativeReply(reply)
+				if err != nil {
+					logger.Debugf("failed to pack a message: %v", err)
+					return
+				}
+			}
+
+			err = handler.writeMessage(reply)
+			if err != nil {
+				logger.Debugf("failed to write a message: %v", err)
+				return
+			}
+		default:
+			logger.Debugf("unexpected message type %s", msg.Header.Type)
+			return
+		}
+	}
+}
+
+func (handler *mockConnectionHandler) startUp() error {
+	msg, err := handler.readMessage()
+	if err != nil {
+		return err
+	}
+
+	if msg.Header.Type != message.RODS_MESSAGE_CONNECT_TYPE {
+		return xerrors.Errorf("unexpected message type %s", msg.Header.Type)
+	}
+
+	startup := message.IRODSMessageStartupPack{}
+	err = startup.FromMessage(msg)
+	if err != nil {
+		return err
+	}
+	handler.startup = &startup
+
+	if strings.Contains(startup.Option, message.RequestNegotiationOptionString) {
+		// we only talk plain TCP
+		negotiation := &message.IRODSMessageCSNegotiation{
+			Status: 1,
+			Result: string(types.CSNegotiationRequireTCP),
+		}
+
+		negotiationMessage, err := negotiation.GetMessage()
+		if err != nil {
+			return err
+		}
+
+		err = handler.writeMessage(negotiationMessage)
+		if err != nil {
+			return err
+		}
+
+		resultMessage, err := handler.readMessage()
+		if err != nil {
+			return err
+		}
+
+		result := message.IRODSMessageCSNegotiation{}
+		err = result.FromMessage(resultMessage)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(result.Result, string(types.CSNegotiationUseTCP)) {
+			return xerrors.Errorf("unsupported negotiation result %s", result.Result)
+		}
+	}
+
+	startupError := handler.server.getStartupError()
+
+	version := &message.IRODSMessageVersion{
+		Status:         int(startupError),
+		ReleaseVersion: handler.server.getReleaseVersion(),
+		APIVersion:     MockServerAPIVersion,
+	}
+
+	versionMessage, err := version.GetMessage()
+	if err != nil {
+		return err
+	}
+
+	handler.version = version.GetVersion()
+	err = handler.writeMessage(versionMessage)
+	if err != nil {
+		return err
+	}
+
+	if startupError != 0 {
+		return xerrors.Errorf("refused startup with %d", startupError)
+	}
+	return nil
+}
+
+// readMessage reads a message in the same framing the client uses
+func (handler *mockConnectionHandler) readMessage() (*message.IRODSMessage, error) {
+	headerLenBuffer := make([]byte, 4)
+	_, err := io.ReadFull(handler.socket, headerLenBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	headerBuffer := make([]byte, binary.BigEndian.Uint32(headerLenBuffer))
+	_, err = io.ReadFull(handler.socket, headerBuffer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read header: %w", err)
+	}
+
+	header := message.IRODSMessageHeader{}
+	err = header.FromBytes(headerBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBuffer := make([]byte, int(header.MessageLen)+int(header.ErrorLen))
+	_, err = io.ReadFull(handler.socket, bodyBuffer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read body: %w", err)
+	}
+
+	bsBuffer := make([]byte, int(header.BsLen))
+	_, err = io.ReadFull(handler.socket, bsBuffer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read body (BS): %w", err)
+	}
+
+	body := message.IRODSMessageBody{}
+	err = body.FromBytes(&header, bodyBuffer, bsBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	body.Type = header.Type
+	body.IntInfo = header.IntInfo
+
+	return &message.IRODSMessage{
+		Header: &header,
+		Body:   &body,
+	}, nil
+}
+
+// writeMessage writes a message
+func (handler *mockConnectionHandler) writeMessage(msg *message.IRODSMessage) error {
+	header := msg.Header
+	if header == nil {
+		h, err := msg.Body.BuildHeader()
+		if err != nil {
+			return err
+		}
+		header = h
+	}
+
+	headerBytes, err := header.GetBytes()
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	headerLenBuffer := make([]byte, 4)
+	binary.BigEndian.PutUint32(headerLenBuffer, uint32(len(headerBytes)))
+	buffer.Write(headerLenBuffer)
+	buffer.Write(headerBytes)
+
+	if msg.Body != nil {
+		bodyBytes, err := msg.Body.GetBytes()
+		if err != nil {
+			return err
+		}
+		buffer.Write(bodyBytes)
+	}
+
+	_, err = handler.socket.Write(buffer.Bytes())
+	return err
+}
+
+// makeReply makes an api reply message
+func makeReply(intInfo int32, body []byte, bs []byte) *message.IRODSMessage {
+	msgBody := message.IRODSMessageBody{
+		Type:    message.RODS_MESSAGE_API_REPLY_TYPE,
+		Message: body,
+		Error:   nil,
+		Bs:      bs,
+		IntInfo: intInfo,
+	}
+
+	msgHeader, _ := msgBody.BuildHeader()
+	return &message.IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}
+}
+
+// makeJSONBody makes a message body of json wrapped in BinBytesBuf
+func makeJSONBody(v interface{}) ([]byte, error) {
+	jsonBody, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	binBytesBuf := message.IRODSMessageBinBytesBuf{
+		Length: len(jsonBody),
+		Data:   base64.StdEncoding.EncodeToString(jsonBody),
+	}
+	return xml.Marshal(binBytesBuf)
+}
+
+// makeErrorReply makes an api reply message from an error
+func makeErrorReply(err error) *message.IRODSMessage {
+	code := types.GetIRODSErrorCode(err)
+	if code == 0 {
+		code = common.SYS_API_INPUT_ERR
+	}
+	return makeReply(int32(code), nil, nil)
+}
+
+func getKeyVal(keyVals message.IRODSMessageSSKeyVal, key common.KeyWord) (string, bool) {
+	for idx, k := range keyVals.Keys {
+		if k == string(key) && idx < len(keyVals.Values) {
+			return unescapeXMLText(keyVals.Values[idx].Value), true
+		}
+	}
+	return "", false
+}
+
+// checkResource returns an error if a resource other than the mock resource is requested
+func checkResource(keyVals message.IRODSMessageSSKeyVal) error {
+	if resource, ok := getKeyVal(keyVals, common.DEST_RESC_NAME_KW); ok && len(resource) > 0 && resource != MockResourceName {
+		return types.NewIRODSError(common.SYS_RESC_DOES_NOT_EXIST)
+	}
+	return nil
+}
+
+// isStalledTransfer returns true if the request is a data object read or write to be left unanswered
+func (handler *mockConnectionHandler) isStalledTransfer(msg *message.IRODSMessage) bool {
+	switch common.APINumber(msg.Header.IntInfo) {
+	case common.DATA_OBJ_READ_AN, common.DATA_OBJ_WRITE_AN:
+		return handler.server.takeStalledTransfer()
+	default:
+		return false
+	}
+}
+
+func (handler *mockConnectionHandler) handleAPI(msg *message.IRODSMessage) *message.IRODSMessage {
+	apiNumber := common.APINumber(msg.Header.IntInfo)
+
+	switch apiNumber {
+	case common.AUTH_REQUEST_AN:
+		return handler.handleAuthRequest()
+	case common.AUTH_RESPONSE_AN:
+		return handler.handleAuthResponse(msg)
+	}
+
+	if handler.user == nil {
+		return makeReply(int32(common.CAT_INVALID_AUTHENTICATION), nil, nil)
+	}
+
+	catalog := handler.server.catalog
+	catalog.mutex.Lock()
+	defer catalog.mutex.Unlock()
+
+	switch apiNumber {
+	case common.GEN_QUERY_AN:
+		return handler.handleGenQuery(msg)
+	case common.SPECIFIC_QUERY_AN:
+		return handler.handleSpecificQuery(msg)
+	case common.CLIENT_HINTS_AN:
+		return handler.handleClientHints()
+	case common.COLL_CREATE_AN:
+		return handler.handleMakeCollection(msg)
+	case common.RM_COLL_AN:
+		return handler.handleRemoveCollection(msg)
+	case common.DATA_OBJ_CREATE_AN, common.DATA_OBJ_OPEN_AN:
+		return handler.handleOpenDataObject(msg, apiNumber == common.DATA_OBJ_CREATE_AN)
+	case common.DATA_OBJ_GET_AN, common.DATA_OBJ_PUT_AN:
+		return handler.handlePortalOperation(msg, apiNumber == common.DATA_OBJ_PUT_AN)
+	case common.OPR_COMPLETE_AN:
+		return handler.handleCompletePortalOperation(msg)
+	case common.DATA_OBJ_READ_AN:
+		return handler.handleReadDataObject(msg)
+	case common.DATA_OBJ_WRITE_AN:
+		return handler.handleWriteDataObject(msg)
+	case common.DATA_OBJ_LSEEK_AN:
+		return handler.handleSeekDataObject(msg)
+	case common.DATA_OBJ_CLOSE_AN:
+		return handler.handleCloseDataObject(msg)
+	case common.GET_FILE_DESCRIPTOR_INFO_APN:
+		return handler.handleGetDescriptorInfo(msg)
+	case common.REPLICA_CLOSE_APN:
+		return handler.handleCloseDataObjectReplica(msg)
+	case common.DATA_OBJ_UNLINK_AN:
+		return handler.handleRemoveDataObject(msg)
+	case common.DATA_OBJ_TRUNCATE_AN:
+		return handler.handleTruncateDataObject(msg)
+	case common.DATA_OBJ_RENAME_AN:
+		return handler.handleRename(msg)
+	case common.TOUCH_APN:
+		return handler.handleTouch(msg)
+	case common.EXEC_MY_RULE_AN:
+		return handler.handleExecMyRule(msg)
+	case common.DATA_OBJ_CHKSUM_AN:
+		return handler.handleChecksum(msg)
+	case common.DATA_OBJ_COPY_AN:
+		return handler.handleCopyDataObject(msg)
+	case common.DATA_OBJ_REPL_AN:
+		return handler.handleReplicateDataObject(msg)
+	case common.DATA_OBJ_PHYMV_AN:
+		return handler.handlePhysicalMoveDataObject(msg)
+	case common.MOD_AVU_METADATA_AN:
+		return handler.handleModifyMetadata(msg)
+	case common.ATOMIC_APPLY_METADATA_OPERATIONS_APN:
+		return handler.handleAtomicMetadata(msg)
+	case common.MOD_ACCESS_CONTROL_AN:
+		return handler.handleModifyAccess(msg)
+	case common.GENERAL_ADMIN_AN:
+		return handler.handleGeneralAdmin(msg)
+	case common.TICKET_ADMIN_AN:
+		return handler.handleTicketAdmin(msg)
+	case common.END_TRANSACTION_AN:
+		// accepted, but nothing to do in memory
+		return makeReply(0, nil, nil)
+	default:
+		return makeReply(int32(common.SYS_UNMATCHED_API_NUM), nil, nil)
+	}
+}
+
+func (handler *mockConnectionHandler) handleAuthRequest() *message.IRODSMessage {
+	handler.challenge = make([]byte, 64)
+	_, err := rand.Read(handler.challenge)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	challenge := &message.IRODSMessageAuthChallengeResponse{
+		Challenge: base64.StdEncoding.EncodeToString(handler.challenge),
+	}
+
+	challengeMessage, err := challenge.GetMessage()
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return challengeMessage
+}
+
+func (handler *mockConnectionHandler) handleAuthResponse(msg *message.IRODSMessage) *message.IRODSMessage {
+	authResponse := message.IRODSMessageAuthResponse{}
+	err := authResponse.FromMessage(msg)
+	if err != nil || handler.challenge == nil {
+		return makeReply(int32(common.CAT_INVALID_AUTHENTICATION), nil, nil)
+	}
+
+	catalog := handler.server.catalog
+	catalog.mutex.Lock()
+	defer catalog.mutex.Unlock()
+
+	proxyUser, err := catalog.getUser(authResponse.Username)
+	if err != nil {
+		return makeReply(int32(common.CAT_INVALID_USER), nil, nil)
+	}
+
+	if auth.GenerateAuthResponse(handler.challenge, proxyUser.Password) != authResponse.Response {
+		return makeReply(int32(common.CAT_INVALID_AUTHENTICATION), nil, nil)
+	}
+
+	// proxy access is allowed for admins only, the client user can be of another zone
+	user := proxyUser
+	if handler.startup != nil && handler.isProxyAccess(proxyUser) {
+		if proxyUser.Type != types.IRODSUserRodsAdmin {
+			return makeReply(int32(common.CAT_INVALID_AUTHENTICATION), nil, nil)
+		}
+
+		user, err = catalog.getUser(handler.getClientIdentity().String())
+		if err != nil {
+			return makeReply(int32(common.CAT_INVALID_USER), nil, nil)
+		}
+	}
+
+	handler.user = user
+	return makeReply(0, nil, nil)
+}
+
+// getClientIdentity returns the client user given in the startup message, in the local zone if the zone is not given
+func (handler *mockConnectionHandler) getClientIdentity() types.IRODSUserIdentity {
+	zone := handler.startup.ClientRcatZone
+	if len(zone) == 0 {
+		zone = handler.server.zone
+	}
+	return types.NewIRODSUserIdentity(handler.startup.ClientUser, zone)
+}
+
+// isProxyAccess returns true if the client user is not the proxy user authenticated
+func (handler *mockConnectionHandler) isProxyAccess(proxyUser *mockUser) bool {
+	return !handler.getClientIdentity().Equals(types.NewIRODSUserIdentity(proxyUser.Name, proxyUser.Zone))
+}
+
+// getOwner returns the user key of the user authenticated, to be kept as owners of items created
+func (handler *mockConnectionHandler) getOwner() string {
+	return handler.server.catalog.userKey(handler.user)
+}
+
+func (handler *mockConnectionHandler) handleGenQuery(msg *message.IRODSMessage) *message.IRODSMessage {
+	query := message.IRODSMessageQueryRequest{}
+	err := query.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	zone, _ := getKeyVal(query.KeyVals, common.ZONE_KW)
+	if !handler.server.catalog.hasZone(zone) {
+		return makeReply(int32(common.SYS_INVALID_ZONE_NAME), nil, nil)
+	}
+
+	if handler.isACLRefused() {
+		for _, column := range query.Selects.Keys {
+			if isAccessColumn(common.ICATColumnNumber(column)) {
+				return makeReply(int32(common.CAT_NO_ACCESS_PERMISSION), nil, nil)
+			}
+		}
+	}
+
+	response, err := handler.server.catalog.runQuery(&query)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	body, err := marshalQueryResponse(response)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleSpecificQuery(msg *message.IRODSMessage) *message.IRODSMessage {
+	query := message.IRODSMessageQuerySpecificRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &query)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	zone, _ := getKeyVal(query.KeyVals, common.ZONE_KW)
+	if !handler.server.catalog.hasZone(zone) {
+		return makeReply(int32(common.SYS_INVALID_ZONE_NAME), nil, nil)
+	}
+
+	if query.SQL == "ShowCollAcls" && handler.isACLRefused() {
+		return makeReply(int32(common.CAT_NO_ACCESS_PERMISSION), nil, nil)
+	}
+
+	response, err := handler.server.catalog.runSpecificQuery(&query)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	body, err := marshalQueryResponse(response)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+// isACLRefused returns true if ACL queries of the user are refused for strict ACLs
+func (handler *mockConnectionHandler) isACLRefused() bool {
+	return handler.server.isStrictACLs() && handler.user.Type != types.IRODSUserRodsAdmin
+}
+
+// isAccessColumn returns true if the column is of collection or data object accesses
+func isAccessColumn(column common.ICATColumnNumber) bool {
+	for _, accessColumns := range [][]common.ICATColumnNumber{dataObjectAccessColumns, collectionAccessColumns} {
+		for _, accessColumn := range accessColumns {
+			if column == accessColumn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (handler *mockConnectionHandler) handleClientHints() *message.IRODSMessage {
+	strictACLs := "off"
+	if handler.server.isStrictACLs() {
+		strictACLs = "on"
+	}
+
+	hints := map[string]interface{}{
+		"hash_scheme":       "SHA256",
+		"match_hash_policy": "compatible",
+		"strict_acls":       strictACLs,
+		"specific_queries":  []string{"ShowCollAcls"},
+		"rules":             []interface{}{},
+		"plugins":           []interface{}{},
+	}
+
+	body, err := makeJSONBody(hints)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleMakeCollection(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageMakeCollectionRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	_, recurse := getKeyVal(request.KeyVals, common.RECURSIVE_OPR_KW)
+	err = handler.server.catalog.makeCollection(request.Name, handler.getOwner(), recurse)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleRemoveCollection(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageRemoveCollectionRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	_, recurse := getKeyVal(request.KeyVals, common.RECURSIVE_OPR_KW)
+	err = handler.server.catalog.removeCollection(request.Name, recurse)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleOpenDataObject(msg *message.IRODSMessage, create bool) *message.IRODSMessage {
+	request := message.IRODSMessageDataObjectRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	catalog := handler.server.catalog
+	dataType, _ := getKeyVal(request.KeyVals, common.DATA_TYPE_KW)
+
+	var obj *mockDataObject
+	if create {
+		_, force := getKeyVal(request.KeyVals, common.FORCE_FLAG_KW)
+		obj, err = catalog.createDataObject(request.Path, handler.getOwner(), dataType, force)
+	} else {
+		obj, err = catalog.getDataObject(request.Path)
+		if err != nil && request.OpenFlags&int(types.O_CREAT) != 0 {
+			obj, err = catalog.createDataObject(request.Path, handler.getOwner(), dataType, false)
+		}
+	}
+
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	// the leaf resource of the hierarchy must have a replica
+	resourceHierarchy, _ := getKeyVal(request.KeyVals, common.RESC_HIER_STR_KW)
+	if len(resourceHierarchy) > 0 && !create {
+		leaf := resourceHierarchy[strings.LastIndex(resourceHierarchy, ";")+1:]
+		if !obj.hasReplica(leaf) {
+			return makeReply(int32(common.SYS_RESC_DOES_NOT_EXIST), nil, nil)
+		}
+	}
+
+	if request.OpenFlags&int(types.O_TRUNC) != 0 {
+		obj.Data = []byte{}
+		obj.Checksum = ""
+		obj.ModifyTime = time.Now()
+	}
+
+	handler.server.countDataObjectOpen(resourceHierarchy)
+
+	fd := handler.nextDescriptor
+	handler.nextDescriptor++
+	_, registerChecksum := getKeyVal(request.KeyVals, common.REG_CHKSUM_KW)
+	verifyChecksum := ""
+	if _, ok := getKeyVal(request.KeyVals, common.VERIFY_CHKSUM_KW); ok {
+		verifyChecksum, _ = getKeyVal(request.KeyVals, common.CHKSUM_KW)
+	}
+
+	handler.descriptors[fd] = &mockFileDescriptor{
+		object:           obj,
+		offset:           0,
+		flags:            request.OpenFlags,
+		registerChecksum: registerChecksum,
+		verifyChecksum:   verifyChecksum,
+	}
+
+	return makeReply(int32(fd), nil, nil)
+}
+
+// handlePortalOperation answers a data object put or get with the number of threads set to the server, but without a port
+func (handler *mockConnectionHandler) handlePortalOperation(msg *message.IRODSMessage, put bool) *message.IRODSMessage {
+	request := message.IRODSMessageDataObjectRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	err = checkResource(request.KeyVals)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if !put {
+		_, err = handler.server.catalog.getDataObject(request.Path)
+		if err != nil {
+			return makeErrorReply(err)
+		}
+	}
+
+	fd := handler.nextDescriptor
+	handler.nextDescriptor++
+	handler.portals[fd] = request.Path
+
+	response := message.IRODSMessagePortalResponse{
+		FileDescriptor: fd,
+		Threads:        handler.server.takePortalThreads(request.Threads),
+		PortList:       &message.IRODSMessagePortList{},
+	}
+
+	body, err := xml.Marshal(&response)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleCompletePortalOperation(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageInt{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if _, ok := handler.portals[request.Value]; !ok {
+		return makeReply(int32(common.SYS_BAD_FILE_DESCRIPTOR), nil, nil)
+	}
+
+	delete(handler.portals, request.Value)
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) getDescriptor(msg *message.IRODSMessage) (*message.IRODSMessageOpenedDataObjectRequest, *mockFileDescriptor, error) {
+	request := message.IRODSMessageOpenedDataObjectRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	descriptor, ok := handler.descriptors[request.FileDescriptor]
+	if !ok {
+		return nil, nil, types.NewIRODSError(common.SYS_BAD_FILE_DESCRIPTOR)
+	}
+	return &request, descriptor, nil
+}
+
+func (handler *mockConnectionHandler) handleReadDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request, descriptor, err := handler.getDescriptor(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if handler.server.takeFailedRead() {
+		return makeReply(int32(common.UNIX_FILE_READ_ERR), nil, nil)
+	}
+
+	data := descriptor.object.Data
+	if descriptor.offset >= int64(len(data)) {
+		return makeReply(0, nil, nil)
+	}
+
+	end := descriptor.offset + request.Size
+	if end > int64(len(data)) {
+		end = int64(len(data))
+	}
+
+	buffer := make([]byte, end-descriptor.offset)
+	copy(buffer, data[descriptor.offset:end])
+	descriptor.offset = end
+
+	if len(buffer) > 0 && handler.server.takeCorruptedRead() {
+		buffer[0] ^= 0xff
+	}
+
+	return makeReply(int32(len(buffer)), nil, buffer)
+}
+
+func (handler *mockConnectionHandler) handleWriteDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	_, descriptor, err := handler.getDescriptor(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if descriptor.flags&(int(types.O_WRONLY)|int(types.O_RDWR)) == 0 {
+		return makeReply(int32(common.SYS_BAD_FILE_DESCRIPTOR), nil, nil)
+	}
+
+	obj := descriptor.object
+	if descriptor.flags&int(types.O_APPEND) != 0 {
+		descriptor.offset = int64(len(obj.Data))
+	}
+
+	end := descriptor.offset + int64(len(msg.Body.Bs))
+	if end > int64(len(obj.Data)) {
+		grown := make([]byte, end)
+		copy(grown, obj.Data)
+		obj.Data = grown
+	}
+
+	copy(obj.Data[descriptor.offset:end], msg.Body.Bs)
+	descriptor.offset = end
+	descriptor.bytesWritten += int64(len(msg.Body.Bs))
+	obj.Checksum = ""
+	obj.ModifyTime = time.Now()
+
+	return makeReply(int32(len(msg.Body.Bs)), nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleSeekDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request, descriptor, err := handler.getDescriptor(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	var offset int64
+	switch types.Whence(request.Whence) {
+	case types.SeekSet:
+		offset = request.Offset
+	case types.SeekCur:
+		offset = descriptor.offset + request.Offset
+	case types.SeekEnd:
+		offset = int64(len(descriptor.object.Data)) + request.Offset
+	default:
+		return makeReply(int32(common.SYS_API_INPUT_ERR), nil, nil)
+	}
+
+	if offset < 0 {
+		return makeReply(int32(common.SYS_API_INPUT_ERR), nil, nil)
+	}
+	descriptor.offset = offset
+
+	response := message.IRODSMessageSeekDataObjectResponse{
+		Offset: offset,
+	}
+
+	body, err := xml.Marshal(&response)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleCloseDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request, descriptor, err := handler.getDescriptor(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	delete(handler.descriptors, request.FileDescriptor)
+
+	if len(descriptor.verifyChecksum) > 0 {
+		// only sha2 checksums are computed
+		if getMockChecksum(descriptor.object.Data) != descriptor.verifyChecksum {
+			return makeReply(int32(common.USER_CHKSUM_MISMATCH), nil, nil)
+		}
+		descriptor.object.Checksum = descriptor.verifyChecksum
+	} else if descriptor.registerChecksum {
+		descriptor.object.Checksum = getMockChecksum(descriptor.object.Data)
+	}
+
+	return makeReply(0, nil, nil)
+}
+
+// handleGetDescriptorInfo returns info of an opened data object, e.g., replica access info used by parallel writes
+func (handler *mockConnectionHandler) handleGetDescriptorInfo(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageGetDescriptorInfoRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	descriptor, ok := handler.descriptors[request.FileDescriptor]
+	if !ok {
+		return makeReply(int32(common.SYS_BAD_FILE_DESCRIPTOR), nil, nil)
+	}
+
+	info := map[string]interface{}{
+		"replica_token": fmt.Sprintf("mock-replica-token-%d", descriptor.object.ID),
+		"data_size":     len(descriptor.object.Data),
+		"bytes_written": descriptor.bytesWritten,
+		"data_object_info": map[string]interface{}{
+			"object_path":        descriptor.object.GetPath(),
+			"resource_hierarchy": MockResourceName,
+			"replica_number":     0,
+		},
+	}
+
+	body, err := makeJSONBody(info)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleCloseDataObjectReplica(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageCloseDataObjectReplicaRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if _, ok := handler.descriptors[request.FileDescriptor]; !ok {
+		return makeReply(int32(common.SYS_BAD_FILE_DESCRIPTOR), nil, nil)
+	}
+
+	delete(handler.descriptors, request.FileDescriptor)
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleRemoveDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageDataObjectRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	err = handler.server.catalog.removeDataObject(request.Path)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+// handleTouch sets modify time of a data object, creating data objects is not supported
+func (handler *mockConnectionHandler) handleTouch(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageTouchRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	obj, err := handler.server.catalog.getDataObject(request.Path)
+	if err != nil {
+		return makeReply(int32(common.OBJ_PATH_DOES_NOT_EXIST), nil, nil)
+	}
+
+	if request.Options.SecondsSinceEpoch > 0 {
+		obj.ModifyTime = time.Unix(request.Options.SecondsSinceEpoch, 0)
+	} else {
+		obj.ModifyTime = time.Now()
+	}
+	return makeReply(0, nil, nil)
+}
+
+// handleExecMyRule does not run rules, it returns inputs having output labels as outputs
+// ruleExecOut has the rule as stdout
+func (handler *mockConnectionHandler) handleExecMyRule(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageExecMyRuleRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	inputs := map[string]message.IRODSMessageMsParam{}
+	if request.Params != nil {
+		for _, param := range request.Params.Params {
+			inputs[param.Label] = param
+		}
+	}
+
+	outputs := message.IRODSMessageMsParamArray{}
+	for _, label := range strings.Split(request.OutParamDesc, "%") {
+		if input, ok := inputs[label]; ok {
+			outputs.Params = append(outputs.Params, input)
+			continue
+		}
+
+		if label == "ruleExecOut" {
+			stdout := []byte(request.Rule + "\x00")
+			outputs.Params = append(outputs.Params, message.IRODSMessageMsParam{
+				Label: label,
+				Type:  message.MS_PARAM_TYPE_EXEC_CMD_OUT,
+				ExecCmdOut: &message.IRODSMessageExecCmdOut{
+					Buffers: []message.IRODSMessageBinBytesBuf{
+						{
+							Length: len(stdout),
+							Data:   base64.StdEncoding.EncodeToString(stdout),
+						},
+						{},
+					},
+				},
+			})
+		}
+	}
+	outputs.Length = len(outputs.Params)
+
+	body, err := xml.Marshal(outputs)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleTruncateDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageDataObjectRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	obj, err := handler.server.catalog.getDataObject(request.Path)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if request.Size < 0 {
+		return makeReply(int32(common.SYS_API_INPUT_ERR), nil, nil)
+	}
+
+	truncated := make([]byte, request.Size)
+	copy(truncated, obj.Data)
+	obj.Data = truncated
+	obj.Checksum = ""
+	obj.ModifyTime = time.Now()
+
+	return makeReply(0, nil, nil)
+}
+
+// mockCopyRequest decodes DataObjCopyInp_PI, request structs in message package only support marshaling
+type mockCopyRequest struct {
+	XMLName xml.Name                                `xml:"DataObjCopyInp_PI"`
+	Paths   []message.IRODSMessageDataObjectRequest `xml:"DataObjInp_PI"`
+}
+
+func (handler *mockConnectionHandler) getSourceAndDestPaths(msg *message.IRODSMessage) (*message.IRODSMessageDataObjectRequest, *message.IRODSMessageDataObjectRequest, error) {
+	request := mockCopyRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(request.Paths) != 2 {
+		return nil, nil, types.NewIRODSError(common.SYS_API_INPUT_ERR)
+	}
+	return &request.Paths[0], &request.Paths[1], nil
+}
+
+func (handler *mockConnectionHandler) handleRename(msg *message.IRODSMessage) *message.IRODSMessage {
+	src, dest, err := handler.getSourceAndDestPaths(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if handler.server.takeFailedRename(dest.Path) {
+		return makeReply(int32(common.SYS_INTERNAL_ERR), nil, nil)
+	}
+
+	resource, _ := getKeyVal(dest.KeyVals, common.DEST_RESC_NAME_KW)
+	_, force := getKeyVal(dest.KeyVals, common.FORCE_FLAG_KW)
+	err = handler.server.catalog.rename(src.Path, dest.Path, resource, force)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleCopyDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	src, dest, err := handler.getSourceAndDestPaths(msg)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	err = checkResource(dest.KeyVals)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	_, force := getKeyVal(dest.KeyVals, common.FORCE_FLAG_KW)
+	err = handler.server.catalog.copyDataObject(src.Path, dest.Path, handler.getOwner(), force)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleReplicateDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageReplicateDataObjectRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	resource, _ := getKeyVal(request.KeyVals, common.DEST_RESC_NAME_KW)
+	err = handler.server.catalog.replicateDataObject(request.Path, resource)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handlePhysicalMoveDataObject(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessagePhysicalMoveDataObjectRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	srcResource, _ := getKeyVal(request.KeyVals, common.RESC_NAME_KW)
+	destResource, _ := getKeyVal(request.KeyVals, common.DEST_RESC_NAME_KW)
+	err = handler.server.catalog.moveReplica(request.Path, srcResource, destResource)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+// mockChecksumResponse is a checksum response, message.IRODSMessageChecksumResponse has no root element name
+type mockChecksumResponse struct {
+	XMLName  xml.Name `xml:"STR_PI"`
+	Checksum string   `xml:"myStr"`
+}
+
+// getMockChecksum returns iRODS checksum string of sha256 digest of the data
+func getMockChecksum(data []byte) string {
+	digest := sha256.Sum256(data)
+	return "sha2:" + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+func (handler *mockConnectionHandler) handleChecksum(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageChecksumRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	err = checkResource(request.KeyVals)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	obj, err := handler.server.catalog.getDataObject(request.Path)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	// computed checksums are registered like iRODS does
+	obj.Checksum = getMockChecksum(obj.Data)
+
+	body, err := xml.Marshal(mockChecksumResponse{Checksum: obj.Checksum})
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, body, nil)
+}
+
+func (handler *mockConnectionHandler) handleModifyMetadata(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageModifyMetadataRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	meta := &types.IRODSMeta{
+		Name:  request.AttrName,
+		Value: request.AttrValue,
+		Units: request.AttrUnits,
+	}
+
+	if request.Operation == "rmi" {
+		avuID, err := strconv.ParseInt(request.AttrName, 10, 64)
+		if err != nil {
+			return makeReply(int32(common.CAT_INVALID_ARGUMENT), nil, nil)
+		}
+		meta.AVUID = avuID
+	}
+
+	if handler.server.isMetadataValueTooLong(meta.Value) {
+		return makeReply(int32(common.USER_STRLEN_TOOLONG), nil, nil)
+	}
+
+	err = handler.server.catalog.modifyMeta(request.Operation, types.IRODSMetaItemType(request.ItemType), request.ItemName, meta)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleAtomicMetadata(msg *message.IRODSMessage) *message.IRODSMessage {
+	if !handler.version.HasHigherVersionThan(4, 2, 8) {
+		return makeReply(int32(common.SYS_UNMATCHED_API_NUM), nil, nil)
+	}
+
+	request := message.IRODSMessageAtomicMetadataRequest{}
+	err := request.FromBytes(msg.Body.Message)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	itemTypes := map[string]types.IRODSMetaItemType{
+		"data_object": types.IRODSDataObjectMetaItemType,
+		"collection":  types.IRODSCollectionMetaItemType,
+		"resource":    types.IRODSResourceMetaItemType,
+		"user":        types.IRODSUserMetaItemType,
+	}
+
+	itemType, ok := itemTypes[request.EntityType]
+	if !ok {
+		return makeReply(int32(common.CAT_INVALID_ARGUMENT), nil, nil)
+	}
+
+	catalog := handler.server.catalog
+	holder, err := catalog.getMetaHolder(itemType, request.EntityName)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	// all or nothing
+	saved := append([]*types.IRODSMeta{}, *holder...)
+	for _, operation := range request.Operations {
+		meta := &types.IRODSMeta{
+			Name:  operation.Attribute,
+			Value: operation.Value,
+			Units: operation.Units,
+		}
+
+		if handler.server.isMetadataValueTooLong(meta.Value) {
+			*holder = saved
+			return makeReply(int32(common.USER_STRLEN_TOOLONG), nil, nil)
+		}
+
+		switch operation.Operation {
+		case "add":
+			err = catalog.modifyMeta("add", itemType, request.EntityName, meta)
+		case "remove":
+			err = catalog.modifyMeta("rm", itemType, request.EntityName, meta)
+		default:
+			err = types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+		}
+
+		if err != nil {
+			*holder = saved
+			return makeErrorReply(err)
+		}
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleModifyAccess(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageModifyAccessRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	accessLevel := strings.TrimPrefix(request.AccessLevel, "admin:")
+	if accessLevel == "inherit" || accessLevel == "noinherit" {
+		err = handler.server.catalog.setInheritance(request.Path, accessLevel == "inherit")
+	} else {
+		user := types.NewIRODSUserIdentity(request.UserName, request.Zone)
+		err = handler.server.catalog.setAccess(request.Path, user.String(), types.GetIRODSAccessLevelType(accessLevel), request.RecursiveFlag == 1)
+	}
+
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+func (handler *mockConnectionHandler) handleGeneralAdmin(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageAdminRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	if handler.user.Type != types.IRODSUserRodsAdmin {
+		return makeReply(int32(common.CAT_INSUFFICIENT_PRIVILEGE_LEVEL), nil, nil)
+	}
+
+	catalog := handler.server.catalog
+	// names may be in 'user#zone' form
+	name := strings.SplitN(request.Arg2, "#", 2)[0]
+
+	switch request.Action + " " + request.Target {
+	case "add user":
+		err = catalog.addUser(name, "", types.IRODSUserType(request.Arg3))
+	case "modify user":
+		err = catalog.modifyUser(name, request.Arg3, request.Arg4)
+	case "rm user":
+		err = catalog.removeUser(name)
+	case "modify group":
+		switch request.Arg3 {
+		case "add":
+			err = catalog.addGroupMember(name, request.Arg4)
+		case "remove":
+			err = catalog.removeGroupMember(name, request.Arg4)
+		default:
+			err = types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+		}
+	case "add specificQuery":
+		err = catalog.addSpecificQuery(request.Arg3, request.Arg2)
+	case "rm specificQuery":
+		err = catalog.removeSpecificQuery(request.Arg2)
+	default:
+		err = types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
+
+// handleTicketAdmin creates, modifies and deletes tickets, supplying a ticket is accepted, but not checked
+func (handler *mockConnectionHandler) handleTicketAdmin(msg *message.IRODSMessage) *message.IRODSMessage {
+	request := message.IRODSMessageTicketAdminRequest{}
+	err := xml.Unmarshal(msg.Body.Message, &request)
+	if err != nil {
+		return makeErrorReply(err)
+	}
+
+	catalog := handler.server.catalog
+
+	switch request.Action {
+	case "create":
+		err = catalog.createTicket(request.Ticket, types.TicketType(request.Arg3), request.Arg4, handler.getOwner())
+	case "mod":
+		err = catalog.modifyTicket(request.Ticket, request.Arg3, request.Arg4)
+	case "delete":
+		err = catalog.deleteTicket(request.Ticket)
+	case "session":
+		err = nil
+	default:
+		err = types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	if err != nil {
+		return makeErrorReply(err)
+	}
+	return makeReply(0, nil, nil)
+}
@@ -0,0 +1,75 @@
+package mock
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"golang.org/x/xerrors"
+)
+
+// mockRequestPackingInstructions maps APIs to packing instructions of their requests
+var mockRequestPackingInstructions = map[common.APINumber]string{
+	common.AUTH_RESPONSE_AN:      "authResponseInp_PI",
+	common.GEN_QUERY_AN:          "GenQueryInp_PI",
+	common.COLL_CREATE_AN:        "CollInpNew_PI",
+	common.RM_COLL_AN:            "CollInpNew_PI",
+	common.DATA_OBJ_CREATE_AN:    "DataObjInp_PI",
+	common.DATA_OBJ_OPEN_AN:      "DataObjInp_PI",
+	common.DATA_OBJ_GET_AN:       "DataObjInp_PI",
+	common.DATA_OBJ_PUT_AN:       "DataObjInp_PI",
+	common.OPR_COMPLETE_AN:       "INT_PI",
+	common.DATA_OBJ_UNLINK_AN:    "DataObjInp_PI",
+	common.DATA_OBJ_TRUNCATE_AN:  "DataObjInp_PI",
+	common.DATA_OBJ_READ_AN:      "OpenedDataObjInp_PI",
+	common.DATA_OBJ_WRITE_AN:     "OpenedDataObjInp_PI",
+	common.DATA_OBJ_LSEEK_AN:     "OpenedDataObjInp_PI",
+	common.DATA_OBJ_CLOSE_AN:     "OpenedDataObjInp_PI",
+	common.DATA_OBJ_RENAME_AN:    "DataObjCopyInp_PI",
+	common.DATA_OBJ_COPY_AN:      "DataObjCopyInp_PI",
+	common.MOD_AVU_METADATA_AN:   "ModAVUMetadataInp_PI",
+	common.END_TRANSACTION_AN:    "endTransactionInp_PI",
+	common.TICKET_ADMIN_AN:       "ticketAdminInp_PI",
+	common.MOD_ACCESS_CONTROL_AN: "modAccessControlInp_PI",
+}
+
+// isNativeProtocol returns true if the client asked for native protocol in startup pack
+func (handler *mockConnectionHandler) isNativeProtocol() bool {
+	return handler.startup != nil && handler.startup.Protocol == 0
+}
+
+// unpackNativeRequest translates a request in native packing into XML
+func (handler *mockConnectionHandler) unpackNativeRequest(msg *message.IRODSMessage) error {
+	if msg.Body == nil || len(msg.Body.Message) == 0 {
+		return nil
+	}
+
+	apiNumber := common.APINumber(msg.Header.IntInfo)
+	packingInstruction, ok := mockRequestPackingInstructions[apiNumber]
+	if !ok {
+		return xerrors.Errorf("unknown packing instruction for api %d", apiNumber)
+	}
+
+	xmlBytes, err := message.ConvertNativeToXML(msg.Body.Message, packingInstruction)
+	if err != nil {
+		return err
+	}
+
+	msg.Body.Message = xmlBytes
+	msg.Header.MessageLen = uint32(len(xmlBytes))
+	return nil
+}
+
+// packNativeReply translates a reply in XML into native packing
+func (handler *mockConnectionHandler) packNativeReply(msg *message.IRODSMessage) error {
+	if msg.Body == nil || len(msg.Body.Message) == 0 {
+		return nil
+	}
+
+	nativeBytes, err := message.ConvertXMLToNative(msg.Body.Message)
+	if err != nil {
+		return err
+	}
+
+	msg.Body.Message = nativeBytes
+	msg.Header.MessageLen = uint32(len(nativeBytes))
+	return nil
+}
@@ -0,0 +1,924 @@
+package mock
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// mockRow is a row of a GenQuery result, keyed by column
+type mockRow map[common.ICATColumnNumber]string
+
+// mockRowSource produces rows for a set of columns that are joined together
+type mockRowSource struct {
+	columns []common.ICATColumnNumber
+	rows    func(catalog *mockCatalog) []mockRow
+}
+
+var (
+	userColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_USER_ID, common.ICAT_COLUMN_USER_NAME, common.ICAT_COLUMN_USER_TYPE, common.ICAT_COLUMN_USER_ZONE,
+		common.ICAT_COLUMN_USER_INFO, common.ICAT_COLUMN_USER_COMMENT, common.ICAT_COLUMN_USER_CREATE_TIME, common.ICAT_COLUMN_USER_MODIFY_TIME,
+	}
+
+	collectionColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_COLL_ID, common.ICAT_COLUMN_COLL_NAME, common.ICAT_COLUMN_COLL_PARENT_NAME, common.ICAT_COLUMN_COLL_OWNER_NAME,
+		common.ICAT_COLUMN_COLL_OWNER_ZONE, common.ICAT_COLUMN_COLL_MAP_ID, common.ICAT_COLUMN_COLL_INHERITANCE, common.ICAT_COLUMN_COLL_COMMENTS,
+		common.ICAT_COLUMN_COLL_CREATE_TIME, common.ICAT_COLUMN_COLL_MODIFY_TIME,
+	}
+
+	dataObjectColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_D_DATA_ID, common.ICAT_COLUMN_D_COLL_ID, common.ICAT_COLUMN_DATA_NAME, common.ICAT_COLUMN_DATA_REPL_NUM,
+		common.ICAT_COLUMN_DATA_VERSION, common.ICAT_COLUMN_DATA_TYPE_NAME, common.ICAT_COLUMN_DATA_SIZE, common.ICAT_COLUMN_D_RESC_NAME,
+		common.ICAT_COLUMN_D_DATA_PATH, common.ICAT_COLUMN_D_OWNER_NAME, common.ICAT_COLUMN_D_OWNER_ZONE, common.ICAT_COLUMN_D_REPL_STATUS,
+		common.ICAT_COLUMN_D_DATA_STATUS, common.ICAT_COLUMN_D_DATA_CHECKSUM, common.ICAT_COLUMN_D_EXPIRY, common.ICAT_COLUMN_D_MAP_ID,
+		common.ICAT_COLUMN_D_COMMENTS, common.ICAT_COLUMN_D_CREATE_TIME, common.ICAT_COLUMN_D_MODIFY_TIME, common.ICAT_COLUMN_D_RESC_HIER,
+		common.ICAT_COLUMN_D_RESC_ID,
+	}
+
+	dataObjectMetaColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_META_DATA_ATTR_ID, common.ICAT_COLUMN_META_DATA_ATTR_NAME, common.ICAT_COLUMN_META_DATA_ATTR_VALUE,
+		common.ICAT_COLUMN_META_DATA_ATTR_UNITS, common.ICAT_COLUMN_META_DATA_CREATE_TIME, common.ICAT_COLUMN_META_DATA_MODIFY_TIME,
+	}
+
+	collectionMetaColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_META_COLL_ATTR_ID, common.ICAT_COLUMN_META_COLL_ATTR_NAME, common.ICAT_COLUMN_META_COLL_ATTR_VALUE,
+		common.ICAT_COLUMN_META_COLL_ATTR_UNITS, common.ICAT_COLUMN_META_COLL_CREATE_TIME, common.ICAT_COLUMN_META_COLL_MODIFY_TIME,
+	}
+
+	userMetaColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_META_USER_ATTR_ID, common.ICAT_COLUMN_META_USER_ATTR_NAME, common.ICAT_COLUMN_META_USER_ATTR_VALUE,
+		common.ICAT_COLUMN_META_USER_ATTR_UNITS, common.ICAT_COLUMN_META_USER_CREATE_TIME, common.ICAT_COLUMN_META_USER_MODIFY_TIME,
+	}
+
+	dataObjectAccessColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_DATA_ACCESS_TYPE, common.ICAT_COLUMN_DATA_ACCESS_NAME, common.ICAT_COLUMN_DATA_ACCESS_USER_ID, common.ICAT_COLUMN_DATA_ACCESS_DATA_ID,
+	}
+
+	collectionAccessColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_COLL_ACCESS_TYPE, common.ICAT_COLUMN_COLL_ACCESS_NAME, common.ICAT_COLUMN_COLL_ACCESS_USER_ID, common.ICAT_COLUMN_COLL_ACCESS_COLL_ID,
+	}
+
+	resourceMetaColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_META_RESC_ATTR_ID, common.ICAT_COLUMN_META_RESC_ATTR_NAME, common.ICAT_COLUMN_META_RESC_ATTR_VALUE,
+		common.ICAT_COLUMN_META_RESC_ATTR_UNITS, common.ICAT_COLUMN_META_RESC_CREATE_TIME, common.ICAT_COLUMN_META_RESC_MODIFY_TIME,
+	}
+
+	userGroupColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_COLL_USER_GROUP_ID, common.ICAT_COLUMN_COLL_USER_GROUP_NAME,
+	}
+
+	quotaUsageColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_QUOTA_USAGE_USER_ID, common.ICAT_COLUMN_QUOTA_USAGE_RESC_ID, common.ICAT_COLUMN_QUOTA_USAGE,
+		common.ICAT_COLUMN_QUOTA_USAGE_MODIFY_TIME, common.ICAT_COLUMN_QUOTA_USER_NAME, common.ICAT_COLUMN_QUOTA_USER_ZONE,
+		common.ICAT_COLUMN_QUOTA_RESC_NAME,
+	}
+
+	ticketColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_TICKET_ID, common.ICAT_COLUMN_TICKET_STRING, common.ICAT_COLUMN_TICKET_TYPE, common.ICAT_COLUMN_TICKET_OBJECT_TYPE,
+		common.ICAT_COLUMN_TICKET_USES_LIMIT, common.ICAT_COLUMN_TICKET_USES_COUNT, common.ICAT_COLUMN_TICKET_EXPIRY_TS,
+		common.ICAT_COLUMN_TICKET_WRITE_FILE_COUNT, common.ICAT_COLUMN_TICKET_WRITE_FILE_LIMIT, common.ICAT_COLUMN_TICKET_WRITE_BYTE_COUNT,
+		common.ICAT_COLUMN_TICKET_WRITE_BYTE_LIMIT, common.ICAT_COLUMN_TICKET_OWNER_NAME, common.ICAT_COLUMN_TICKET_OWNER_ZONE,
+	}
+
+	dataObjectTicketColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_TICKET_DATA_NAME, common.ICAT_COLUMN_TICKET_DATA_COLL_NAME,
+	}
+
+	collectionTicketColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_TICKET_COLL_NAME,
+	}
+
+	resourceColumns = []common.ICATColumnNumber{
+		common.ICAT_COLUMN_R_RESC_ID, common.ICAT_COLUMN_R_RESC_NAME, common.ICAT_COLUMN_R_ZONE_NAME, common.ICAT_COLUMN_R_TYPE_NAME,
+		common.ICAT_COLUMN_R_CLASS_NAME, common.ICAT_COLUMN_R_LOC, common.ICAT_COLUMN_R_VAULT_PATH, common.ICAT_COLUMN_R_RESC_CONTEXT,
+		common.ICAT_COLUMN_R_RESC_PARENT, common.ICAT_COLUMN_R_CREATE_TIME, common.ICAT_COLUMN_R_MODIFY_TIME,
+		common.ICAT_COLUMN_R_FREE_SPACE, common.ICAT_COLUMN_R_FREE_SPACE_TIME, common.ICAT_COLUMN_R_RESC_STATUS,
+	}
+)
+
+// mockRowSources lists all joins the mock server can answer
+var mockRowSources = []mockRowSource{
+	{
+		columns: userColumns,
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, user := range catalog.sortedUsers() {
+				rows = append(rows, userRow(user))
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(userColumns, userMetaColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, user := range catalog.sortedUsers() {
+				for _, meta := range user.Meta {
+					rows = append(rows, joinRows(userRow(user), metaRow(userMetaColumns, meta)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(userColumns, userGroupColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, user := range catalog.sortedUsers() {
+				// a user is a member of its own group
+				rows = append(rows, joinRows(userRow(user), mockRow{
+					common.ICAT_COLUMN_COLL_USER_GROUP_ID:   fmt.Sprintf("%d", user.ID),
+					common.ICAT_COLUMN_COLL_USER_GROUP_NAME: user.Name,
+				}))
+
+				for _, groupName := range user.Groups {
+					group, ok := catalog.users[groupName]
+					if !ok {
+						continue
+					}
+
+					rows = append(rows, joinRows(userRow(user), mockRow{
+						common.ICAT_COLUMN_COLL_USER_GROUP_ID:   fmt.Sprintf("%d", group.ID),
+						common.ICAT_COLUMN_COLL_USER_GROUP_NAME: group.Name,
+					}))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: collectionColumns,
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, coll := range catalog.sortedCollections() {
+				rows = append(rows, collectionRow(catalog, coll))
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(collectionColumns, collectionMetaColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, coll := range catalog.sortedCollections() {
+				for _, meta := range coll.Meta {
+					rows = append(rows, joinRows(collectionRow(catalog, coll), metaRow(collectionMetaColumns, meta)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(collectionColumns, collectionAccessColumns, userColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, coll := range catalog.sortedCollections() {
+				for _, access := range catalog.getAccesses(coll.Owner, coll.Access) {
+					accessRow := mockRow{
+						common.ICAT_COLUMN_COLL_ACCESS_TYPE:    getAccessTypeID(access.AccessLevel),
+						common.ICAT_COLUMN_COLL_ACCESS_NAME:    string(access.AccessLevel),
+						common.ICAT_COLUMN_COLL_ACCESS_USER_ID: fmt.Sprintf("%d", access.User.ID),
+						common.ICAT_COLUMN_COLL_ACCESS_COLL_ID: fmt.Sprintf("%d", coll.ID),
+					}
+					rows = append(rows, joinRows(collectionRow(catalog, coll), accessRow, userRow(access.User)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(collectionColumns, dataObjectColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, obj := range catalog.sortedDataObjects() {
+				for replNum := range obj.Replicas {
+					rows = append(rows, joinRows(collectionRow(catalog, obj.Collection), dataObjectReplicaRow(catalog, obj, replNum)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(collectionColumns, dataObjectColumns, dataObjectMetaColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, obj := range catalog.sortedDataObjects() {
+				for _, meta := range obj.Meta {
+					rows = append(rows, joinRows(collectionRow(catalog, obj.Collection), dataObjectRow(catalog, obj), metaRow(dataObjectMetaColumns, meta)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(collectionColumns, dataObjectColumns, dataObjectAccessColumns, userColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, obj := range catalog.sortedDataObjects() {
+				for _, access := range catalog.getAccesses(obj.Owner, obj.Access) {
+					accessRow := mockRow{
+						common.ICAT_COLUMN_DATA_ACCESS_TYPE:    getAccessTypeID(access.AccessLevel),
+						common.ICAT_COLUMN_DATA_ACCESS_NAME:    string(access.AccessLevel),
+						common.ICAT_COLUMN_DATA_ACCESS_USER_ID: fmt.Sprintf("%d", access.User.ID),
+						common.ICAT_COLUMN_DATA_ACCESS_DATA_ID: fmt.Sprintf("%d", obj.ID),
+					}
+					rows = append(rows, joinRows(collectionRow(catalog, obj.Collection), dataObjectRow(catalog, obj), accessRow, userRow(access.User)))
+				}
+			}
+			return rows
+		},
+	},
+	{
+		columns: resourceColumns,
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{resourceRow(catalog)}
+			for _, resource := range catalog.sortedResources() {
+				rows = append(rows, otherResourceRow(catalog, resource))
+			}
+			return rows
+		},
+	},
+	{
+		columns: quotaUsageColumns,
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, user := range catalog.sortedUsers() {
+				if user.QuotaUsageTime.IsZero() {
+					continue
+				}
+
+				rows = append(rows, mockRow{
+					common.ICAT_COLUMN_QUOTA_USAGE_USER_ID:     fmt.Sprintf("%d", user.ID),
+					common.ICAT_COLUMN_QUOTA_USAGE_RESC_ID:     "10000",
+					common.ICAT_COLUMN_QUOTA_USAGE:             fmt.Sprintf("%d", user.QuotaUsage),
+					common.ICAT_COLUMN_QUOTA_USAGE_MODIFY_TIME: getIRODSTimeString(user.QuotaUsageTime),
+					common.ICAT_COLUMN_QUOTA_USER_NAME:         user.Name,
+					common.ICAT_COLUMN_QUOTA_USER_ZONE:         user.Zone,
+					common.ICAT_COLUMN_QUOTA_RESC_NAME:         MockResourceName,
+				})
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(ticketColumns, dataObjectTicketColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, ticket := range catalog.sortedTickets() {
+				if ticket.ObjectType != types.ObjectTypeDataObject {
+					continue
+				}
+
+				rows = append(rows, joinRows(ticketRow(catalog, ticket), mockRow{
+					common.ICAT_COLUMN_TICKET_DATA_NAME:      util.GetIRODSPathFileName(ticket.Path),
+					common.ICAT_COLUMN_TICKET_DATA_COLL_NAME: util.GetIRODSPathDirname(ticket.Path),
+				}))
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(ticketColumns, collectionTicketColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, ticket := range catalog.sortedTickets() {
+				if ticket.ObjectType != types.ObjectTypeCollection {
+					continue
+				}
+
+				rows = append(rows, joinRows(ticketRow(catalog, ticket), mockRow{
+					common.ICAT_COLUMN_TICKET_COLL_NAME: ticket.Path,
+				}))
+			}
+			return rows
+		},
+	},
+	{
+		columns: concatColumns(resourceColumns, resourceMetaColumns),
+		rows: func(catalog *mockCatalog) []mockRow {
+			rows := []mockRow{}
+			for _, meta := range catalog.rescMeta {
+				rows = append(rows, joinRows(resourceRow(catalog), metaRow(resourceMetaColumns, meta)))
+			}
+			return rows
+		},
+	},
+}
+
+func concatColumns(columns ...[]common.ICATColumnNumber) []common.ICATColumnNumber {
+	all := []common.ICATColumnNumber{}
+	for _, c := range columns {
+		all = append(all, c...)
+	}
+	return all
+}
+
+func joinRows(rows ...mockRow) mockRow {
+	joined := mockRow{}
+	for _, row := range rows {
+		for k, v := range row {
+			joined[k] = v
+		}
+	}
+	return joined
+}
+
+// getAccessTypeID returns a token ID of an access level as stored in iCAT
+func getAccessTypeID(accessLevel types.IRODSAccessLevelType) string {
+	switch accessLevel {
+	case types.IRODSAccessLevelReadObject:
+		return "1050"
+	case types.IRODSAccessLevelModifyObject:
+		return "1120"
+	case types.IRODSAccessLevelOwner:
+		return "1200"
+	default:
+		return "1000"
+	}
+}
+
+func getIRODSTimeString(t time.Time) string {
+	if t.IsZero() {
+		return "00000000000"
+	}
+	return fmt.Sprintf("%011d", t.Unix())
+}
+
+func userRow(user *mockUser) mockRow {
+	return mockRow{
+		common.ICAT_COLUMN_USER_ID:          fmt.Sprintf("%d", user.ID),
+		common.ICAT_COLUMN_USER_NAME:        user.Name,
+		common.ICAT_COLUMN_USER_TYPE:        string(user.Type),
+		common.ICAT_COLUMN_USER_ZONE:        user.Zone,
+		common.ICAT_COLUMN_USER_INFO:        user.Info,
+		common.ICAT_COLUMN_USER_COMMENT:     user.Comment,
+		common.ICAT_COLUMN_USER_CREATE_TIME: getIRODSTimeString(user.CreateTime),
+		common.ICAT_COLUMN_USER_MODIFY_TIME: getIRODSTimeString(user.ModifyTime),
+	}
+}
+
+func collectionRow(catalog *mockCatalog, coll *mockCollection) mockRow {
+	parent := ""
+	if coll.Path != "/" {
+		parent = coll.Path[:strings.LastIndex(coll.Path, "/")]
+		if len(parent) == 0 {
+			parent = "/"
+		}
+	}
+
+	inheritance := "0"
+	if coll.Inheritance {
+		inheritance = "1"
+	}
+
+	ownerName, ownerZone := catalog.splitOwner(coll.Owner)
+
+	return mockRow{
+		common.ICAT_COLUMN_COLL_ID:          fmt.Sprintf("%d", coll.ID),
+		common.ICAT_COLUMN_COLL_NAME:        coll.Path,
+		common.ICAT_COLUMN_COLL_PARENT_NAME: parent,
+		common.ICAT_COLUMN_COLL_OWNER_NAME:  ownerName,
+		common.ICAT_COLUMN_COLL_OWNER_ZONE:  ownerZone,
+		common.ICAT_COLUMN_COLL_MAP_ID:      "0",
+		common.ICAT_COLUMN_COLL_INHERITANCE: inheritance,
+		common.ICAT_COLUMN_COLL_COMMENTS:    "",
+		common.ICAT_COLUMN_COLL_CREATE_TIME: getIRODSTimeString(coll.CreateTime),
+		common.ICAT_COLUMN_COLL_MODIFY_TIME: getIRODSTimeString(coll.ModifyTime),
+	}
+}
+
+func dataObjectRow(catalog *mockCatalog, obj *mockDataObject) mockRow {
+	return dataObjectReplicaRow(catalog, obj, 0)
+}
+
+func dataObjectReplicaRow(catalog *mockCatalog, obj *mockDataObject, replNum int) mockRow {
+	resource := MockResourceName
+	if replNum < len(obj.Replicas) {
+		resource = obj.Replicas[replNum]
+	}
+
+	size := int64(len(obj.Data))
+	status := "1"
+	if staleSize, ok := obj.Stale[resource]; ok {
+		size = staleSize
+		status = "0"
+	}
+
+	ownerName, ownerZone := catalog.splitOwner(obj.Owner)
+
+	return mockRow{
+		common.ICAT_COLUMN_D_DATA_ID:       fmt.Sprintf("%d", obj.ID),
+		common.ICAT_COLUMN_D_COLL_ID:       fmt.Sprintf("%d", obj.Collection.ID),
+		common.ICAT_COLUMN_DATA_NAME:       obj.Name,
+		common.ICAT_COLUMN_DATA_REPL_NUM:   fmt.Sprintf("%d", replNum),
+		common.ICAT_COLUMN_DATA_VERSION:    "",
+		common.ICAT_COLUMN_DATA_TYPE_NAME:  obj.DataType,
+		common.ICAT_COLUMN_DATA_SIZE:       fmt.Sprintf("%d", size),
+		common.ICAT_COLUMN_D_RESC_NAME:     resource,
+		common.ICAT_COLUMN_D_DATA_PATH:     MockVaultPath + obj.GetPath(),
+		common.ICAT_COLUMN_D_OWNER_NAME:    ownerName,
+		common.ICAT_COLUMN_D_OWNER_ZONE:    ownerZone,
+		common.ICAT_COLUMN_D_REPL_STATUS:   status,
+		common.ICAT_COLUMN_D_DATA_STATUS:   "",
+		common.ICAT_COLUMN_D_DATA_CHECKSUM: obj.Checksum,
+		common.ICAT_COLUMN_D_EXPIRY:        "",
+		common.ICAT_COLUMN_D_MAP_ID:        "0",
+		common.ICAT_COLUMN_D_COMMENTS:      "",
+		common.ICAT_COLUMN_D_CREATE_TIME:   getIRODSTimeString(obj.CreateTime),
+		common.ICAT_COLUMN_D_MODIFY_TIME:   getIRODSTimeString(obj.ModifyTime),
+		common.ICAT_COLUMN_D_RESC_HIER:     resource,
+		common.ICAT_COLUMN_D_RESC_ID:       "10000",
+	}
+}
+
+func ticketRow(catalog *mockCatalog, ticket *mockTicket) mockRow {
+	ownerName, ownerZone := catalog.splitOwner(ticket.Owner)
+
+	return mockRow{
+		common.ICAT_COLUMN_TICKET_ID:               fmt.Sprintf("%d", ticket.ID),
+		common.ICAT_COLUMN_TICKET_STRING:           ticket.Name,
+		common.ICAT_COLUMN_TICKET_TYPE:             string(ticket.Type),
+		common.ICAT_COLUMN_TICKET_OBJECT_TYPE:      string(ticket.ObjectType),
+		common.ICAT_COLUMN_TICKET_USES_LIMIT:       fmt.Sprintf("%d", ticket.UsesLimit),
+		common.ICAT_COLUMN_TICKET_USES_COUNT:       fmt.Sprintf("%d", ticket.UsesCount),
+		common.ICAT_COLUMN_TICKET_EXPIRY_TS:        "",
+		common.ICAT_COLUMN_TICKET_WRITE_FILE_COUNT: fmt.Sprintf("%d", ticket.WriteFileCount),
+		common.ICAT_COLUMN_TICKET_WRITE_FILE_LIMIT: fmt.Sprintf("%d", ticket.WriteFileLimit),
+		common.ICAT_COLUMN_TICKET_WRITE_BYTE_COUNT: fmt.Sprintf("%d", ticket.WriteByteCount),
+		common.ICAT_COLUMN_TICKET_WRITE_BYTE_LIMIT: fmt.Sprintf("%d", ticket.WriteByteLimit),
+		common.ICAT_COLUMN_TICKET_OWNER_NAME:       ownerName,
+		common.ICAT_COLUMN_TICKET_OWNER_ZONE:       ownerZone,
+	}
+}
+
+func resourceRow(catalog *mockCatalog) mockRow {
+	return mockRow{
+		common.ICAT_COLUMN_R_RESC_ID:         "10000",
+		common.ICAT_COLUMN_R_RESC_NAME:       MockResourceName,
+		common.ICAT_COLUMN_R_ZONE_NAME:       catalog.zone,
+		common.ICAT_COLUMN_R_TYPE_NAME:       "unixfilesystem",
+		common.ICAT_COLUMN_R_CLASS_NAME:      "cache",
+		common.ICAT_COLUMN_R_LOC:             "localhost",
+		common.ICAT_COLUMN_R_VAULT_PATH:      MockVaultPath,
+		common.ICAT_COLUMN_R_RESC_CONTEXT:    catalog.rescContext,
+		common.ICAT_COLUMN_R_RESC_PARENT:     "",
+		common.ICAT_COLUMN_R_CREATE_TIME:     getIRODSTimeString(time.Time{}),
+		common.ICAT_COLUMN_R_MODIFY_TIME:     getIRODSTimeString(time.Time{}),
+		common.ICAT_COLUMN_R_FREE_SPACE:      catalog.rescFreeSpace,
+		common.ICAT_COLUMN_R_FREE_SPACE_TIME: catalog.rescFreeSpaceTime,
+		common.ICAT_COLUMN_R_RESC_STATUS:     catalog.rescStatus,
+	}
+}
+
+// otherResourceRow makes a row for a resource other than the mock resource, space is not reported
+func otherResourceRow(catalog *mockCatalog, resource *mockResource) mockRow {
+	return mockRow{
+		common.ICAT_COLUMN_R_RESC_ID:         fmt.Sprintf("%d", resource.ID),
+		common.ICAT_COLUMN_R_RESC_NAME:       resource.Name,
+		common.ICAT_COLUMN_R_ZONE_NAME:       catalog.zone,
+		common.ICAT_COLUMN_R_TYPE_NAME:       resource.Type,
+		common.ICAT_COLUMN_R_CLASS_NAME:      resource.Class,
+		common.ICAT_COLUMN_R_LOC:             "localhost",
+		common.ICAT_COLUMN_R_VAULT_PATH:      MockVaultPath,
+		common.ICAT_COLUMN_R_RESC_CONTEXT:    "",
+		common.ICAT_COLUMN_R_RESC_PARENT:     "",
+		common.ICAT_COLUMN_R_CREATE_TIME:     getIRODSTimeString(time.Time{}),
+		common.ICAT_COLUMN_R_MODIFY_TIME:     getIRODSTimeString(time.Time{}),
+		common.ICAT_COLUMN_R_FREE_SPACE:      "",
+		common.ICAT_COLUMN_R_FREE_SPACE_TIME: "",
+		common.ICAT_COLUMN_R_RESC_STATUS:     resource.Status,
+	}
+}
+
+// metaRow makes a row for metadata, columns must be in order of id, name, value, units, create time, modify time
+func metaRow(columns []common.ICATColumnNumber, meta *types.IRODSMeta) mockRow {
+	return mockRow{
+		columns[0]: fmt.Sprintf("%d", meta.AVUID),
+		columns[1]: meta.Name,
+		columns[2]: meta.Value,
+		columns[3]: meta.Units,
+		columns[4]: getIRODSTimeString(meta.CreateTime),
+		columns[5]: getIRODSTimeString(meta.ModifyTime),
+	}
+}
+
+// mockCondition is a parsed GenQuery condition
+type mockCondition struct {
+	column   common.ICATColumnNumber
+	operator string
+	values   []string
+}
+
+var conditionRegex = regexp.MustCompile(`(?is)^\s*(not\s+like|like|not\s+in|in|between|<>|!=|>=|<=|=|>|<)\s*(.*)$`)
+
+// parseCondition parses a condition string, e.g. "= 'value'" or "in ('a', 'b')"
+func parseCondition(column common.ICATColumnNumber, condition string) (*mockCondition, error) {
+	matches := conditionRegex.FindStringSubmatch(condition)
+	if matches == nil {
+		return nil, xerrors.Errorf("failed to parse condition %q", condition)
+	}
+
+	operator := strings.ToLower(strings.Join(strings.Fields(matches[1]), " "))
+	operand := strings.TrimSpace(matches[2])
+
+	values := []string{}
+	for len(operand) > 0 {
+		start := strings.Index(operand, "'")
+		if start < 0 {
+			break
+		}
+
+		end := start + 1
+		value := strings.Builder{}
+		for end < len(operand) {
+			if operand[end] == '\'' {
+				// '' is an escaped quote
+				if end+1 < len(operand) && operand[end+1] == '\'' {
+					value.WriteByte('\'')
+					end += 2
+					continue
+				}
+				break
+			}
+			value.WriteByte(operand[end])
+			end++
+		}
+
+		values = append(values, value.String())
+		if end >= len(operand) {
+			break
+		}
+		operand = operand[end+1:]
+	}
+
+	if len(values) == 0 {
+		return nil, xerrors.Errorf("failed to parse condition value %q", condition)
+	}
+
+	return &mockCondition{
+		column:   column,
+		operator: operator,
+		values:   values,
+	}, nil
+}
+
+// Match returns true if the row matches the condition
+func (cond *mockCondition) Match(row mockRow) bool {
+	value := row[cond.column]
+
+	switch cond.operator {
+	case "=":
+		return value == cond.values[0]
+	case "<>", "!=":
+		return value != cond.values[0]
+	case "like":
+		return matchLike(value, cond.values[0])
+	case "not like":
+		return !matchLike(value, cond.values[0])
+	case "in", "not in":
+		for _, v := range cond.values {
+			if value == v {
+				return cond.operator == "in"
+			}
+		}
+		return cond.operator == "not in"
+	case "between":
+		if len(cond.values) < 2 {
+			return false
+		}
+		return compareValues(value, cond.values[0]) >= 0 && compareValues(value, cond.values[1]) <= 0
+	case ">":
+		return compareValues(value, cond.values[0]) > 0
+	case "<":
+		return compareValues(value, cond.values[0]) < 0
+	case ">=":
+		return compareValues(value, cond.values[0]) >= 0
+	case "<=":
+		return compareValues(value, cond.values[0]) <= 0
+	default:
+		return false
+	}
+}
+
+// compareValues compares numerically if possible, otherwise as strings
+func compareValues(a string, b string) int {
+	ai, aErr := strconv.ParseInt(a, 10, 64)
+	bi, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// matchLike matches a value against SQL like pattern
+func matchLike(value string, pattern string) bool {
+	sb := strings.Builder{}
+	sb.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+
+	matched, err := regexp.MatchString(sb.String(), value)
+	if err != nil {
+		return false
+	}
+	return matched
+}
+
+// mockQueryResponse mirrors message.IRODSMessageQueryResponse, but keeps empty values on marshal
+type mockQueryResponse struct {
+	XMLName        xml.Name        `xml:"GenQueryOut_PI"`
+	RowCount       int             `xml:"rowCnt"`
+	AttributeCount int             `xml:"attriCnt"`
+	ContinueIndex  int             `xml:"continueInx"`
+	TotalRowCount  int             `xml:"totalRowCount"`
+	SQLResult      []mockSQLResult `xml:"SqlResult_PI"`
+}
+
+type mockSQLResult struct {
+	XMLName        xml.Name `xml:"SqlResult_PI"`
+	AttributeIndex int      `xml:"attriInx"`
+	ResultLen      int      `xml:"reslen"`
+	Values         []string `xml:"value"`
+}
+
+// marshalQueryResponse returns bytes of GenQuery response
+func marshalQueryResponse(response *message.IRODSMessageQueryResponse) ([]byte, error) {
+	out := mockQueryResponse{
+		RowCount:       response.RowCount,
+		AttributeCount: response.AttributeCount,
+		ContinueIndex:  response.ContinueIndex,
+		TotalRowCount:  response.TotalRowCount,
+		SQLResult:      []mockSQLResult{},
+	}
+
+	for _, result := range response.SQLResult {
+		out.SQLResult = append(out.SQLResult, mockSQLResult{
+			AttributeIndex: result.AttributeIndex,
+			ResultLen:      result.ResultLen,
+			Values:         result.Values,
+		})
+	}
+
+	xmlBytes, err := xml.Marshal(&out)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// unescapeXMLText decodes xml entities in raw inner xml
+func unescapeXMLText(raw string) string {
+	var decoded string
+	err := xml.Unmarshal([]byte("<v>"+raw+"</v>"), &decoded)
+	if err != nil {
+		return raw
+	}
+	return decoded
+}
+
+// runQuery runs GenQuery against the catalog
+func (catalog *mockCatalog) runQuery(query *message.IRODSMessageQueryRequest) (*message.IRODSMessageQueryResponse, error) {
+	selects := query.Selects.Keys
+
+	referenced := map[common.ICATColumnNumber]bool{}
+	for _, s := range selects {
+		referenced[common.ICATColumnNumber(s)] = true
+	}
+
+	conditions := []*mockCondition{}
+	for idx, c := range query.Conditions.Keys {
+		if idx >= len(query.Conditions.Values) {
+			break
+		}
+
+		cond, err := parseCondition(common.ICATColumnNumber(c), unescapeXMLText(query.Conditions.Values[idx].Value))
+		if err != nil {
+			return nil, types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+		}
+
+		conditions = append(conditions, cond)
+		referenced[common.ICATColumnNumber(c)] = true
+	}
+
+	// pick the smallest join that covers all referenced columns
+	var source *mockRowSource
+	for idx := range mockRowSources {
+		candidate := &mockRowSources[idx]
+		available := map[common.ICATColumnNumber]bool{}
+		for _, c := range candidate.columns {
+			available[c] = true
+		}
+
+		covered := true
+		for c := range referenced {
+			if !available[c] {
+				covered = false
+				break
+			}
+		}
+
+		if covered && (source == nil || len(candidate.columns) < len(source.columns)) {
+			source = candidate
+		}
+	}
+
+	if source == nil {
+		return nil, types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	matchedRows := []mockRow{}
+	for _, row := range source.rows(catalog) {
+		matched := true
+		for _, cond := range conditions {
+			if !cond.Match(row) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			matchedRows = append(matchedRows, row)
+		}
+	}
+
+	// continue index is used as an offset
+	offset := query.ContinueIndex
+	if offset < 0 || offset > len(matchedRows) {
+		offset = len(matchedRows)
+	}
+	matchedRows = matchedRows[offset:]
+
+	if len(matchedRows) == 0 {
+		return nil, types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	continueIndex := 0
+	if query.MaxRows > 0 && len(matchedRows) > query.MaxRows {
+		matchedRows = matchedRows[:query.MaxRows]
+		continueIndex = offset + query.MaxRows
+	}
+
+	response := &message.IRODSMessageQueryResponse{
+		RowCount:       len(matchedRows),
+		AttributeCount: len(selects),
+		ContinueIndex:  continueIndex,
+		TotalRowCount:  len(matchedRows),
+		SQLResult:      []message.IRODSMessageSQLResult{},
+	}
+
+	for _, s := range selects {
+		values := []string{}
+		maxLen := 0
+		for _, row := range matchedRows {
+			v := row[common.ICATColumnNumber(s)]
+			values = append(values, v)
+			if len(v) > maxLen {
+				maxLen = len(v)
+			}
+		}
+
+		response.SQLResult = append(response.SQLResult, message.IRODSMessageSQLResult{
+			AttributeIndex: s,
+			ResultLen:      maxLen + 1,
+			Values:         values,
+		})
+	}
+
+	return response, nil
+}
+
+// addSpecificQuery registers a specific query by the alias
+func (catalog *mockCatalog) addSpecificQuery(alias string, sql string) error {
+	if len(alias) == 0 || len(sql) == 0 {
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	if _, ok := catalog.sqlQueries[alias]; ok {
+		return types.NewIRODSError(common.CAT_INVALID_ARGUMENT)
+	}
+
+	catalog.sqlQueries[alias] = sql
+	return nil
+}
+
+// removeSpecificQuery removes a specific query registered by the alias or the sql
+func (catalog *mockCatalog) removeSpecificQuery(aliasOrSQL string) error {
+	for alias, sql := range catalog.sqlQueries {
+		if alias == aliasOrSQL || sql == aliasOrSQL {
+			delete(catalog.sqlQueries, alias)
+			return nil
+		}
+	}
+	return types.NewIRODSError(common.CAT_UNKNOWN_SPECIFIC_QUERY)
+}
+
+// runSpecificQuery answers a specific query, only queries used by the client are known
+func (catalog *mockCatalog) runSpecificQuery(query *message.IRODSMessageQuerySpecificRequest) (*message.IRODSMessageQueryResponse, error) {
+	rows := [][]string{}
+
+	switch query.SQL {
+	case "ShowCollAcls":
+		// user name, user zone, access level, user type
+		coll, ok := catalog.collections[query.Arg1]
+		if !ok {
+			return nil, types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+		}
+
+		for _, access := range catalog.getAccesses(coll.Owner, coll.Access) {
+			rows = append(rows, []string{access.User.Name, access.User.Zone, string(access.AccessLevel), string(access.User.Type)})
+		}
+	case "ls":
+		// alias, sql
+		aliases := []string{}
+		for alias := range catalog.sqlQueries {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+
+		for _, alias := range aliases {
+			rows = append(rows, []string{alias, catalog.sqlQueries[alias]})
+		}
+	default:
+		// registered queries are known, but return no rows as sqls are not run
+		if _, ok := catalog.sqlQueries[query.SQL]; !ok {
+			return nil, types.NewIRODSError(common.CAT_UNKNOWN_SPECIFIC_QUERY)
+		}
+	}
+
+	// continue index is used as an offset
+	offset := query.ContinueIndex
+	if offset < 0 || offset > len(rows) {
+		offset = len(rows)
+	}
+	rows = rows[offset:]
+
+	if len(rows) == 0 {
+		return nil, types.NewIRODSError(common.CAT_NO_ROWS_FOUND)
+	}
+
+	continueIndex := 0
+	if query.MaxRows > 0 && len(rows) > query.MaxRows {
+		rows = rows[:query.MaxRows]
+		continueIndex = offset + query.MaxRows
+	}
+
+	response := &message.IRODSMessageQueryResponse{
+		RowCount:       len(rows),
+		AttributeCount: len(rows[0]),
+		ContinueIndex:  continueIndex,
+		TotalRowCount:  len(rows),
+		SQLResult:      []message.IRODSMessageSQLResult{},
+	}
+
+	for attr := 0; attr < len(rows[0]); attr++ {
+		values := []string{}
+		maxLen := 0
+		for _, row := range rows {
+			values = append(values, row[attr])
+			if len(row[attr]) > maxLen {
+				maxLen = len(row[attr])
+			}
+		}
+
+		response.SQLResult = append(response.SQLResult, message.IRODSMessageSQLResult{
+			AttributeIndex: attr,
+			ResultLen:      maxLen + 1,
+			Values:         values,
+		})
+	}
+
+	return response, nil
+}
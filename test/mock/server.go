@@ -0,0 +1,721 @@
+package mock
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MockServerReleaseVersion is a release version the mock server reports by default
+	MockServerReleaseVersion string = "rods4.2.11"
+	// MockServerAPIVersion is an api version the mock server reports
+	MockServerAPIVersion string = "d"
+)
+
+// IRODSMockServer is an in-memory iRODS server that speaks enough of the iRODS protocol
+// (auth, GenQuery, collection / data object operations, data I/O, metadata) for unit tests
+type IRODSMockServer struct {
+	zone      string
+	catalog   *mockCatalog
+	listener  net.Listener
+	sockets   map[net.Conn]bool
+	waitGroup sync.WaitGroup
+	mutex     sync.Mutex
+
+	// number of data object reads and writes left unanswered
+	stalledTransfers int
+	// number of data object reads answered with an error
+	failedReads int
+	// number of data object reads answered with corrupted data
+	corruptedReads int
+	// number of renames to the destination path answered with an error, by destination path
+	failedRenames map[string]int
+	// number of heartbeats received
+	heartbeats int
+	// delay of heartbeat replies
+	heartbeatDelay time.Duration
+	// release version reported in startup
+	releaseVersion string
+	// error code startups are refused with, 0 if startups are accepted
+	startupError common.ErrorCode
+	// true if ACL queries of users other than rodsadmins are refused
+	strictACLs bool
+	// number of threads returned to portal operations, the portal itself is not served
+	portalThreads int
+	// number of threads requested by the last portal operation
+	requestedPortalThreads int
+	// number of data objects opened
+	dataObjectOpens int
+	// resource hierarchy of the replica last opened, empty if the server picked one
+	lastOpenedResourceHierarchy string
+	// max length of metadata values stored, 0 if not limited
+	metaValueMaxLength int
+}
+
+// NewIRODSMockServer creates a new IRODSMockServer with an admin user
+func NewIRODSMockServer(zone string, adminUser string, adminPassword string) (*IRODSMockServer, error) {
+	catalog := newMockCatalog(zone)
+	err := catalog.addUser(adminUser, adminPassword, types.IRODSUserRodsAdmin)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to add admin user %s: %w", adminUser, err)
+	}
+
+	return &IRODSMockServer{
+		zone:           zone,
+		catalog:        catalog,
+		sockets:        map[net.Conn]bool{},
+		releaseVersion: MockServerReleaseVersion,
+	}, nil
+}
+
+// Start starts listening on a random local port
+func (server *IRODSMockServer) Start() error {
+	logger := log.WithFields(log.Fields{
+		"package":  "mock",
+		"struct":   "IRODSMockServer",
+		"function": "Start",
+	})
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.listener != nil {
+		return xerrors.Errorf("mock server is already running")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return xerrors.Errorf("failed to listen: %w", err)
+	}
+
+	server.listener = listener
+	logger.Debugf("Mock iRODS server listening on %s", listener.Addr().String())
+
+	server.waitGroup.Add(1)
+	go server.serve(listener)
+
+	return nil
+}
+
+// Stop closes the listener and all client connections
+func (server *IRODSMockServer) Stop() error {
+	server.mutex.Lock()
+	if server.listener == nil {
+		server.mutex.Unlock()
+		return nil
+	}
+
+	err := server.listener.Close()
+	server.listener = nil
+
+	for socket := range server.sockets {
+		socket.Close()
+	}
+	server.mutex.Unlock()
+
+	server.waitGroup.Wait()
+
+	if err != nil {
+		return xerrors.Errorf("failed to close listener: %w", err)
+	}
+	return nil
+}
+
+func (server *IRODSMockServer) serve(listener net.Listener) {
+	defer server.waitGroup.Done()
+
+	for {
+		socket, err := listener.Accept()
+		if err != nil {
+			// listener closed
+			return
+		}
+
+		server.mutex.Lock()
+		server.sockets[socket] = true
+		server.mutex.Unlock()
+
+		server.waitGroup.Add(1)
+		go func() {
+			defer server.waitGroup.Done()
+
+			handler := newMockConnectionHandler(server, socket)
+			handler.Serve()
+
+			server.mutex.Lock()
+			delete(server.sockets, socket)
+			server.mutex.Unlock()
+		}()
+	}
+}
+
+// StallDataTransfers leaves the next count data object reads and writes unanswered
+// the client hangs until it closes the connection
+func (server *IRODSMockServer) StallDataTransfers(count int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.stalledTransfers = count
+}
+
+// takeStalledTransfer returns true if a data object read or write is to be left unanswered
+func (server *IRODSMockServer) takeStalledTransfer() bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.stalledTransfers <= 0 {
+		return false
+	}
+
+	server.stalledTransfers--
+	return true
+}
+
+// DropConnections closes all client connections while listening for new connections, as a server restart does
+func (server *IRODSMockServer) DropConnections() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	for socket := range server.sockets {
+		socket.Close()
+	}
+}
+
+// FailDataReads answers the next count data object reads with UNIX_FILE_READ_ERR
+func (server *IRODSMockServer) FailDataReads(count int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.failedReads = count
+}
+
+// takeFailedRead returns true if a data object read is to be answered with an error
+func (server *IRODSMockServer) takeFailedRead() bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.failedReads <= 0 {
+		return false
+	}
+
+	server.failedReads--
+	return true
+}
+
+// FailRenamesTo answers the next count renames to the destination path with SYS_INTERNAL_ERR
+func (server *IRODSMockServer) FailRenamesTo(destPath string, count int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.failedRenames == nil {
+		server.failedRenames = map[string]int{}
+	}
+	server.failedRenames[destPath] = count
+}
+
+// takeFailedRename returns true if a rename to the destination path is to be answered with an error
+func (server *IRODSMockServer) takeFailedRename(destPath string) bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.failedRenames[destPath] <= 0 {
+		return false
+	}
+
+	server.failedRenames[destPath]--
+	return true
+}
+
+// CorruptDataReads flips the first byte of data answered to the next count data object reads
+func (server *IRODSMockServer) CorruptDataReads(count int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.corruptedReads = count
+}
+
+// takeCorruptedRead returns true if data answered to a data object read is to be corrupted
+func (server *IRODSMockServer) takeCorruptedRead() bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.corruptedReads <= 0 {
+		return false
+	}
+
+	server.corruptedReads--
+	return true
+}
+
+// SetReleaseVersion sets the release version reported to new connections, e.g., "rods4.2.11"
+func (server *IRODSMockServer) SetReleaseVersion(releaseVersion string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.releaseVersion = releaseVersion
+}
+
+// getReleaseVersion returns the release version reported to new connections
+func (server *IRODSMockServer) getReleaseVersion() string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.releaseVersion
+}
+
+// SetStartupError sets the error code new connections are refused with in startup, e.g., common.SYS_USER_NOT_ALLOWED_TO_CONN
+// 0 accepts new connections again
+func (server *IRODSMockServer) SetStartupError(code common.ErrorCode) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.startupError = code
+}
+
+// getStartupError returns the error code new connections are refused with
+func (server *IRODSMockServer) getStartupError() common.ErrorCode {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.startupError
+}
+
+// SetStrictACLs sets whether the server enforces strict ACLs
+// ACL queries of users other than rodsadmins are refused with CAT_NO_ACCESS_PERMISSION under strict ACLs
+func (server *IRODSMockServer) SetStrictACLs(strictACLs bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.strictACLs = strictACLs
+}
+
+// isStrictACLs returns true if the server enforces strict ACLs
+func (server *IRODSMockServer) isStrictACLs() bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.strictACLs
+}
+
+// SetPortalThreads sets the number of threads returned to data object puts and gets, capped by the number of threads requested
+// no port is returned, so clients transfer the data object by themselves with the number of threads
+func (server *IRODSMockServer) SetPortalThreads(threads int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.portalThreads = threads
+}
+
+// takePortalThreads returns the number of threads for a portal operation, recording the number of threads requested
+func (server *IRODSMockServer) takePortalThreads(requested int) int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.requestedPortalThreads = requested
+
+	if requested > 0 && requested < server.portalThreads {
+		return requested
+	}
+	return server.portalThreads
+}
+
+// GetRequestedPortalThreads returns the number of threads requested by the last data object put or get
+func (server *IRODSMockServer) GetRequestedPortalThreads() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.requestedPortalThreads
+}
+
+// GetDataObjectOpenCount returns the number of data objects opened or created
+func (server *IRODSMockServer) GetDataObjectOpenCount() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.dataObjectOpens
+}
+
+// GetLastOpenedResourceHierarchy returns the resource hierarchy of the replica last opened, empty if the server picked one
+func (server *IRODSMockServer) GetLastOpenedResourceHierarchy() string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.lastOpenedResourceHierarchy
+}
+
+// countDataObjectOpen counts a data object opened or created, with the resource hierarchy of the replica requested
+func (server *IRODSMockServer) countDataObjectOpen(resourceHierarchy string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.dataObjectOpens++
+	server.lastOpenedResourceHierarchy = resourceHierarchy
+}
+
+// SetMetadataValueMaxLength sets the max length of metadata values, longer values are refused with USER_STRLEN_TOOLONG
+// 0 does not limit lengths
+func (server *IRODSMockServer) SetMetadataValueMaxLength(maxLength int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.metaValueMaxLength = maxLength
+}
+
+// isMetadataValueTooLong returns true if the metadata value is longer than the max length set
+func (server *IRODSMockServer) isMetadataValueTooLong(value string) bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.metaValueMaxLength > 0 && len(value) > server.metaValueMaxLength
+}
+
+// GetHeartbeatCount returns the number of heartbeats received
+func (server *IRODSMockServer) GetHeartbeatCount() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.heartbeats
+}
+
+// SetHeartbeatDelay delays replies to heartbeats, e.g., to test operations during keepalive
+func (server *IRODSMockServer) SetHeartbeatDelay(delay time.Duration) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.heartbeatDelay = delay
+}
+
+// countHeartbeat counts a heartbeat received, returns the delay of the reply
+func (server *IRODSMockServer) countHeartbeat() time.Duration {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.heartbeats++
+	return server.heartbeatDelay
+}
+
+// GetZone returns zone name
+func (server *IRODSMockServer) GetZone() string {
+	return server.zone
+}
+
+// GetHost returns host the server is listening on
+func (server *IRODSMockServer) GetHost() string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.listener == nil {
+		return ""
+	}
+	return server.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// GetPort returns port the server is listening on
+func (server *IRODSMockServer) GetPort() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.listener == nil {
+		return 0
+	}
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+// GetAccount returns an account to connect to the server as the given user
+func (server *IRODSMockServer) GetAccount(user string) (*types.IRODSAccount, error) {
+	server.catalog.mutex.Lock()
+	mockUser, err := server.catalog.getUser(user)
+	server.catalog.mutex.Unlock()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find user %s: %w", user, err)
+	}
+
+	return types.CreateIRODSAccount(server.GetHost(), server.GetPort(), mockUser.Name, server.zone, types.AuthSchemeNative, mockUser.Password, MockResourceName)
+}
+
+// GetProxyAccount returns an account of the proxy user acting for the client user, users can be in 'user#zone' form
+func (server *IRODSMockServer) GetProxyAccount(proxyUser string, clientUser string) (*types.IRODSAccount, error) {
+	server.catalog.mutex.Lock()
+	mockProxyUser, err := server.catalog.getUser(proxyUser)
+	if err != nil {
+		server.catalog.mutex.Unlock()
+		return nil, xerrors.Errorf("failed to find user %s: %w", proxyUser, err)
+	}
+
+	mockClientUser, err := server.catalog.getUser(clientUser)
+	server.catalog.mutex.Unlock()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find user %s: %w", clientUser, err)
+	}
+
+	return types.CreateIRODSProxyAccount(server.GetHost(), server.GetPort(), mockClientUser.Name, mockClientUser.Zone, mockProxyUser.Name, mockProxyUser.Zone, types.AuthSchemeNative, mockProxyUser.Password, MockResourceName)
+}
+
+// AddRemoteZone adds a federated zone with its home collection, queries can be directed to the zone
+func (server *IRODSMockServer) AddRemoteZone(zone string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	server.catalog.remoteZones[zone] = true
+
+	err := server.catalog.makeCollection("/"+zone+"/home", "", true)
+	if err != nil {
+		return xerrors.Errorf("failed to make home collection of zone %s: %w", zone, err)
+	}
+	return nil
+}
+
+// AddUser adds a user, a home collection is created for non-group users
+func (server *IRODSMockServer) AddUser(name string, password string, userType types.IRODSUserType) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.addUser(name, password, userType)
+	if err != nil {
+		return xerrors.Errorf("failed to add user %s: %w", name, err)
+	}
+	return nil
+}
+
+// AddRemoteUser adds a user of a federated zone added by AddRemoteZone, a home collection is created in the zone for non-group users
+// the user is found as 'user#zone' only
+func (server *IRODSMockServer) AddRemoteUser(name string, zone string, password string, userType types.IRODSUserType) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.addRemoteUser(name, zone, password, userType)
+	if err != nil {
+		return xerrors.Errorf("failed to add user %s#%s: %w", name, zone, err)
+	}
+	return nil
+}
+
+// SetUserPassword changes the password of a user, connections authenticated before are kept
+func (server *IRODSMockServer) SetUserPassword(name string, password string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	user, err := server.catalog.getUser(name)
+	if err != nil {
+		return xerrors.Errorf("failed to find user %s: %w", name, err)
+	}
+
+	user.Password = password
+	user.ModifyTime = time.Now()
+	return nil
+}
+
+// SetUserComment sets the comment of a user or a group, e.g., a description of a group
+func (server *IRODSMockServer) SetUserComment(name string, comment string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.modifyUser(name, "comment", comment)
+	if err != nil {
+		return xerrors.Errorf("failed to set comment of user %s: %w", name, err)
+	}
+	return nil
+}
+
+// AddGroupMember adds a user to a group
+func (server *IRODSMockServer) AddGroupMember(group string, user string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.addGroupMember(group, user)
+	if err != nil {
+		return xerrors.Errorf("failed to add user %s to group %s: %w", user, group, err)
+	}
+	return nil
+}
+
+// MakeCollection creates a collection and its parents
+func (server *IRODSMockServer) MakeCollection(path string, owner string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.makeCollection(path, owner, true)
+	if err != nil {
+		return xerrors.Errorf("failed to make collection %s: %w", path, err)
+	}
+	return nil
+}
+
+// PutDataObject creates or overwrites a data object with the given content
+func (server *IRODSMockServer) PutDataObject(path string, owner string, data []byte) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	obj, err := server.catalog.createDataObject(path, owner, "", true)
+	if err != nil {
+		return xerrors.Errorf("failed to create data object %s: %w", path, err)
+	}
+
+	obj.Data = make([]byte, len(data))
+	copy(obj.Data, data)
+	return nil
+}
+
+// AddResource adds a resource data objects can be replicated to
+func (server *IRODSMockServer) AddResource(name string) {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	server.catalog.rescNames[name] = true
+}
+
+// SetResourceInfo adds a resource with its type, class and status, found by resource queries unlike resources added by AddResource
+func (server *IRODSMockServer) SetResourceInfo(name string, rescType string, rescClass string, status string) {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	server.catalog.rescNames[name] = true
+
+	resource, ok := server.catalog.rescInfo[name]
+	if !ok {
+		resource = &mockResource{
+			ID:   server.catalog.newID(),
+			Name: name,
+		}
+		server.catalog.rescInfo[name] = resource
+	}
+
+	resource.Type = rescType
+	resource.Class = rescClass
+	resource.Status = status
+}
+
+// GetReplicaResources returns resources having replicas of a data object in the order replicated
+func (server *IRODSMockServer) GetReplicaResources(path string) ([]string, error) {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	obj, err := server.catalog.getDataObject(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find data object %s: %w", path, types.NewFileNotFoundError(path))
+	}
+
+	replicas := make([]string, len(obj.Replicas))
+	copy(replicas, obj.Replicas)
+	return replicas, nil
+}
+
+// SetReplicaStale marks the replica of a data object on the resource stale, reporting the size it was left at
+func (server *IRODSMockServer) SetReplicaStale(path string, resource string, size int64) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	obj, err := server.catalog.getDataObject(path)
+	if err != nil {
+		return xerrors.Errorf("failed to find data object %s: %w", path, types.NewFileNotFoundError(path))
+	}
+
+	for _, replica := range obj.Replicas {
+		if replica == resource {
+			obj.Stale[resource] = size
+			return nil
+		}
+	}
+
+	return xerrors.Errorf("failed to find replica of data object %s on resource %s", path, resource)
+}
+
+// SetDataObjectChecksum sets the checksum string of a data object, e.g. "sha2:<base64 digest>"
+func (server *IRODSMockServer) SetDataObjectChecksum(path string, checksum string) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	obj, err := server.catalog.getDataObject(path)
+	if err != nil {
+		return xerrors.Errorf("failed to find data object %s: %w", path, types.NewFileNotFoundError(path))
+	}
+
+	obj.Checksum = checksum
+	return nil
+}
+
+// AddMetadata adds an AVU to a data object, a collection, a user or the resource
+func (server *IRODSMockServer) AddMetadata(itemType types.IRODSMetaItemType, name string, meta *types.IRODSMeta) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	err := server.catalog.modifyMeta("add", itemType, name, meta)
+	if err != nil {
+		return xerrors.Errorf("failed to add metadata to %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetDataObject returns content of a data object
+func (server *IRODSMockServer) GetDataObject(path string) ([]byte, error) {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	obj, err := server.catalog.getDataObject(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find data object %s: %w", path, types.NewFileNotFoundError(path))
+	}
+
+	data := make([]byte, len(obj.Data))
+	copy(data, obj.Data)
+	return data, nil
+}
+
+// SetTicketUsage sets the number of uses, files written and bytes written of a ticket
+func (server *IRODSMockServer) SetTicketUsage(name string, uses int64, writeFiles int64, writeBytes int64) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	ticket, ok := server.catalog.tickets[name]
+	if !ok {
+		return xerrors.Errorf("failed to find ticket %s", name)
+	}
+
+	ticket.UsesCount = uses
+	ticket.WriteFileCount = writeFiles
+	ticket.WriteByteCount = writeBytes
+	return nil
+}
+
+// SetQuotaUsage sets usage of a user or a group on the resource calculated at the time
+func (server *IRODSMockServer) SetQuotaUsage(name string, usage int64, calculatedTime time.Time) error {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	user, err := server.catalog.getUser(name)
+	if err != nil {
+		return xerrors.Errorf("failed to find user %s: %w", name, err)
+	}
+
+	user.QuotaUsage = usage
+	user.QuotaUsageTime = calculatedTime
+	return nil
+}
+
+// SetResourceSpace sets free space, capacity and status of the resource, negative values are not reported
+func (server *IRODSMockServer) SetResourceSpace(freeSpace int64, capacity int64, status string) {
+	server.catalog.mutex.Lock()
+	defer server.catalog.mutex.Unlock()
+
+	server.catalog.rescFreeSpace = ""
+	server.catalog.rescFreeSpaceTime = ""
+	if freeSpace >= 0 {
+		server.catalog.rescFreeSpace = fmt.Sprintf("%d", freeSpace)
+		server.catalog.rescFreeSpaceTime = getIRODSTimeString(time.Now())
+	}
+
+	server.catalog.rescContext = ""
+	if capacity >= 0 {
+		server.catalog.rescContext = fmt.Sprintf("capacity=%d", capacity)
+	}
+
+	server.catalog.rescStatus = status
+}
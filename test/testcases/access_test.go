@@ -0,0 +1,212 @@
+package testcases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccess(t *testing.T) {
+	t.Run("test EffectiveAccess", testEffectiveAccess)
+	t.Run("test AccessLevelOrder", testAccessLevelOrder)
+	t.Run("test LargeAccessList", testLargeAccessList)
+	t.Run("test CreateDirWithAccess", testCreateDirWithAccess)
+}
+
+func testEffectiveAccess(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("team", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+	err = mockServer.AddGroupMember("team", "alice")
+	failError(t, err)
+
+	sharedPath := "/mockzone/home/public/shared.txt"
+	err = mockServer.PutDataObject(sharedPath, "rods", []byte("shared content"))
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// owner
+	accessLevel, err := filesystem.Access(sharedPath, "rods")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevel)
+
+	// no access
+	accessLevel, err = filesystem.Access(sharedPath, "alice")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelNull, accessLevel)
+	assert.False(t, accessLevel.CanRead())
+
+	// via group
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.ChangeDataObjectAccess(conn, sharedPath, types.IRODSAccessLevelReadObject, "team", "mockzone", false)
+	failError(t, err)
+	filesystem.ReturnMetadataConnection(conn)
+	filesystem.ClearCache()
+
+	accessLevel, err = filesystem.Access(sharedPath, "alice#mockzone")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelReadObject, accessLevel)
+	assert.True(t, accessLevel.CanRead())
+	assert.False(t, accessLevel.CanWrite())
+
+	// the highest of user and group access
+	conn, err = filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.ChangeDataObjectAccess(conn, sharedPath, types.IRODSAccessLevelModifyObject, "alice", "mockzone", false)
+	failError(t, err)
+	filesystem.ReturnMetadataConnection(conn)
+	filesystem.ClearCache()
+
+	accessLevel, err = filesystem.Access(sharedPath, "alice")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelModifyObject, accessLevel)
+	assert.True(t, accessLevel.CanWrite())
+	assert.False(t, accessLevel.IsOwner())
+
+	// a path not existing resolves to the parent dir
+	accessLevel, err = filesystem.Access("/mockzone/home/alice/new.txt", "alice")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevel)
+
+	accessLevel, err = filesystem.Access("/mockzone/home/alice/new.txt", "rods")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelNull, accessLevel)
+}
+
+func testAccessLevelOrder(t *testing.T) {
+	assert.True(t, types.IRODSAccessLevelOwner.IsEqualOrHigherThan(types.IRODSAccessLevelModifyObject))
+	assert.True(t, types.IRODSAccessLevelModifyObject.IsEqualOrHigherThan(types.IRODSAccessLevelReadObject))
+	assert.True(t, types.IRODSAccessLevelReadObject.IsEqualOrHigherThan(types.IRODSAccessLevelReadObject))
+	assert.False(t, types.IRODSAccessLevelReadObject.IsEqualOrHigherThan(types.IRODSAccessLevelModifyObject))
+	assert.False(t, types.IRODSAccessLevelNull.CanRead())
+	assert.True(t, types.IRODSAccessLevelCurate.CanWrite())
+}
+
+func testLargeAccessList(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	sharedDir := "/mockzone/home/public/shared"
+	err := mockServer.MakeCollection(sharedDir, "rods")
+	failError(t, err)
+
+	sharedPath := sharedDir + "/shared.txt"
+	err = mockServer.PutDataObject(sharedPath, "rods", []byte("shared content"))
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	// more users than a query returns in a page
+	userNum := 1200
+	for i := 0; i < userNum; i++ {
+		user := fmt.Sprintf("user%04d", i)
+		err = mockServer.AddUser(user, "", types.IRODSUserRodsUser)
+		failError(t, err)
+
+		err = irods_fs.ChangeCollectionAccess(conn, sharedDir, types.IRODSAccessLevelReadObject, user, "mockzone", false, false)
+		failError(t, err)
+
+		err = irods_fs.ChangeDataObjectAccess(conn, sharedPath, types.IRODSAccessLevelReadObject, user, "mockzone", false)
+		failError(t, err)
+	}
+
+	// owner and users
+	collAccesses, err := irods_fs.ListCollectionAccesses(conn, sharedDir)
+	failError(t, err)
+	assert.Len(t, collAccesses, userNum+1)
+
+	collection, err := irods_fs.GetCollection(conn, sharedDir)
+	failError(t, err)
+
+	dataObjectAccesses, err := irods_fs.ListDataObjectAccesses(conn, collection, "shared.txt")
+	failError(t, err)
+	assert.Len(t, dataObjectAccesses, userNum+1)
+
+	accesses, err := irods_fs.ListAccessesForDataObjects(conn, collection)
+	failError(t, err)
+	assert.Len(t, accesses, userNum+1)
+
+	users := map[string]bool{}
+	for _, access := range dataObjectAccesses {
+		users[access.UserName] = true
+	}
+	assert.Len(t, users, userNum+1)
+	assert.True(t, users["user0000"])
+	assert.True(t, users[fmt.Sprintf("user%04d", userNum-1)])
+}
+
+func testCreateDirWithAccess(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "bob_password", types.IRODSUserRodsUser)
+	failError(t, err)
+	err = mockServer.AddUser("project_group", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	projectDir := homedir + "/project"
+
+	entry, err := filesystem.CreateDirWithAccess(projectDir, []*types.IRODSAccess{
+		{UserName: "project_group", AccessLevel: types.IRODSAccessLevelModifyObject},
+		{UserName: "bob", UserZone: "mockzone", AccessLevel: types.IRODSAccessLevelReadObject},
+	}, true)
+	failError(t, err)
+	assert.Equal(t, projectDir, entry.Path)
+	assert.Equal(t, fs.DirectoryEntry, entry.Type)
+	assert.True(t, entry.Inheritance)
+
+	accesses, err := filesystem.ListDirACLs(projectDir)
+	failError(t, err)
+
+	accessLevels := map[string]types.IRODSAccessLevelType{}
+	for _, access := range accesses {
+		accessLevels[access.UserName] = access.AccessLevel
+	}
+	assert.Equal(t, map[string]types.IRODSAccessLevelType{
+		"alice":         types.IRODSAccessLevelOwner,
+		"bob":           types.IRODSAccessLevelReadObject,
+		"project_group": types.IRODSAccessLevelModifyObject,
+	}, accessLevels)
+
+	_, err = filesystem.CreateDirWithAccess(projectDir, nil, false)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+
+	// the dir is not left without accesses
+	_, err = filesystem.CreateDirWithAccess(homedir+"/project2", []*types.IRODSAccess{
+		{UserName: "bob", AccessLevel: types.IRODSAccessLevelReadObject},
+		{UserName: "no_such_user", AccessLevel: types.IRODSAccessLevelReadObject},
+	}, true)
+	assert.Error(t, err)
+	assert.False(t, filesystem.ExistsDir(homedir+"/project2"))
+}
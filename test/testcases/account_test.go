@@ -0,0 +1,155 @@
+package testcases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount(t *testing.T) {
+	t.Run("test Normalize", testAccountNormalize)
+	t.Run("test Validate", testAccountValidate)
+	t.Run("test ConnectInvalidAccount", testConnectInvalidAccount)
+	t.Run("test SessionKeepsAccount", testSessionKeepsAccount)
+}
+
+func testAccountNormalize(t *testing.T) {
+	account, err := types.CreateIRODSAccount(" data.example.org ", 1247, "alice#tempZone", "", types.AuthSchemeNative, "password", " demoResc ")
+	failError(t, err)
+
+	// validates as normalized without modifying the account
+	err = account.Validate()
+	failError(t, err)
+	assert.Equal(t, " data.example.org ", account.Host)
+	assert.Equal(t, "alice#tempZone", account.ProxyUser)
+
+	err = account.Normalize()
+	failError(t, err)
+
+	assert.Equal(t, "data.example.org", account.Host)
+	assert.Equal(t, "alice", account.ProxyUser)
+	assert.Equal(t, "tempZone", account.ProxyZone)
+	assert.Equal(t, "alice", account.ClientUser)
+	assert.Equal(t, "tempZone", account.ClientZone)
+	assert.Equal(t, "demoResc", account.DefaultResource)
+
+	// client user defaults to proxy user
+	account, err = types.CreateIRODSProxyAccount("data.example.org", 1247, "", "", "rods", "tempZone", types.AuthSchemeNative, "password", "")
+	failError(t, err)
+
+	err = account.Normalize()
+	failError(t, err)
+
+	assert.Equal(t, "rods", account.ClientUser)
+	assert.Equal(t, "tempZone", account.ClientZone)
+	assert.False(t, account.UseProxyAccess())
+
+	// same zone in both forms
+	account, err = types.CreateIRODSAccount("data.example.org", 1247, "alice#tempZone", "tempZone", types.AuthSchemeNative, "password", "")
+	failError(t, err)
+
+	err = account.Normalize()
+	failError(t, err)
+	assert.Equal(t, "alice", account.ClientUser)
+
+	// conflicting zones
+	account, err = types.CreateIRODSAccount("data.example.org", 1247, "alice#otherZone", "tempZone", types.AuthSchemeNative, "password", "")
+	failError(t, err)
+
+	err = account.Normalize()
+	assert.Error(t, err)
+	assert.True(t, types.IsAccountValidationError(err))
+}
+
+func testAccountValidate(t *testing.T) {
+	newAccount := func() *types.IRODSAccount {
+		account, err := types.CreateIRODSAccount("data.example.org", 1247, "alice", "tempZone", types.AuthSchemeNative, "password", "demoResc")
+		failError(t, err)
+		return account
+	}
+
+	err := newAccount().Validate()
+	failError(t, err)
+
+	invalidCases := map[string]func(account *types.IRODSAccount){
+		"host":                  func(account *types.IRODSAccount) { account.Host = "" },
+		"port":                  func(account *types.IRODSAccount) { account.Port = 70000 },
+		"proxy user":            func(account *types.IRODSAccount) { account.ProxyUser = "alice smith" },
+		"client user":           func(account *types.IRODSAccount) { account.ClientUser = "bob/" },
+		"proxy zone":            func(account *types.IRODSAccount) { account.ProxyZone = "temp/Zone" },
+		"client zone":           func(account *types.IRODSAccount) { account.ClientZone = strings.Repeat("z", 64) },
+		"default resource":      func(account *types.IRODSAccount) { account.DefaultResource = "root;leaf" },
+		"authentication scheme": func(account *types.IRODSAccount) { account.AuthenticationScheme = types.AuthSchemeUnknown },
+		"SSL configuration": func(account *types.IRODSAccount) {
+			account.AuthenticationScheme = types.AuthSchemePAM
+			account.FixAuthConfiguration()
+		},
+	}
+
+	for field, invalidate := range invalidCases {
+		account := newAccount()
+		invalidate(account)
+
+		err = account.Validate()
+		assert.Error(t, err, field)
+		assert.True(t, types.IsAccountValidationError(err), field)
+		assert.True(t, types.IsConnectionConfigError(err), field)
+		assert.True(t, types.IsPermanantFailure(err), field)
+
+		validationErr, ok := err.(*types.AccountValidationError)
+		if assert.True(t, ok, field) {
+			assert.Equal(t, field, validationErr.Field)
+		}
+	}
+}
+
+func testConnectInvalidAccount(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	account.DefaultResource = "bad/resc"
+
+	// fails before talking to the server
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsAccountValidationError(err))
+	assert.True(t, types.IsConnectionConfigError(err))
+	assert.False(t, conn.IsConnected())
+}
+
+func testSessionKeepsAccount(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	account.ClientUser = "alice#mockzone"
+	account.ClientZone = ""
+
+	// sessions and file systems normalize a copy of the account
+	sess, err := session.NewIRODSSession(account, session.NewIRODSSessionConfigWithDefault("go-irodsclient-test"))
+	failError(t, err)
+	defer sess.Release()
+
+	assert.Equal(t, "alice#mockzone", account.ClientUser)
+	assert.Equal(t, "alice", sess.GetAccount().ClientUser)
+	assert.Equal(t, "mockzone", sess.GetAccount().ClientZone)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	assert.Equal(t, "alice#mockzone", account.ClientUser)
+	assert.True(t, filesystem.ExistsDir("/mockzone/home/alice"))
+}
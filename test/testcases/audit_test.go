@@ -0,0 +1,113 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("test AuditHandler", testAuditHandler)
+	t.Run("test AuditWriterHandler", testAuditWriterHandler)
+}
+
+func testAuditHandler(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	mutex := sync.Mutex{}
+	records := []*fs.AuditRecord{}
+	handlerID := filesystem.AddAuditHandler(func(record *fs.AuditRecord) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		records = append(records, record)
+	})
+
+	homedir := "/mockzone/home/alice"
+
+	err = filesystem.MakeDir(homedir+"/audit", false)
+	failError(t, err)
+
+	err = filesystem.RenameDirToDir(homedir+"/audit", homedir+"/audit2")
+	failError(t, err)
+
+	// reads are not recorded
+	_, err = filesystem.List(homedir)
+	failError(t, err)
+
+	err = filesystem.RemoveFile(homedir+"/not_exist.txt", true)
+	assert.Error(t, err)
+
+	mutex.Lock()
+	assert.Len(t, records, 3)
+	if len(records) == 3 {
+		assert.Equal(t, "MakeDir", records[0].Operation)
+		assert.Equal(t, homedir+"/audit", records[0].Path)
+		assert.Equal(t, "alice#mockzone", records[0].Account)
+		assert.Equal(t, fs.AuditResultSuccess, records[0].Result)
+		assert.False(t, records[0].Time.IsZero())
+
+		assert.Equal(t, "RenameDir", records[1].Operation)
+		assert.Equal(t, homedir+"/audit", records[1].Path)
+		assert.Equal(t, homedir+"/audit2", records[1].DestPath)
+
+		assert.Equal(t, "RemoveFile", records[2].Operation)
+		assert.Equal(t, fs.AuditResultFailure, records[2].Result)
+		assert.NotEmpty(t, records[2].Error)
+	}
+	mutex.Unlock()
+
+	filesystem.RemoveAuditHandler(handlerID)
+
+	err = filesystem.RemoveDir(homedir+"/audit2", false, false)
+	failError(t, err)
+
+	mutex.Lock()
+	assert.Len(t, records, 3)
+	mutex.Unlock()
+}
+
+func testAuditWriterHandler(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	buffer := &bytes.Buffer{}
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithAuditHandler(fs.NewAuditWriterHandler(buffer)))
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	err = filesystem.MakeDir(homedir+"/audit", false)
+	failError(t, err)
+
+	err = filesystem.AddMetadata(homedir+"/audit", "key", "value", "")
+	failError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	record := fs.AuditRecord{}
+	err = json.Unmarshal([]byte(lines[1]), &record)
+	failError(t, err)
+	assert.Equal(t, "AddMetadata", record.Operation)
+	assert.Equal(t, homedir+"/audit", record.Path)
+	assert.Equal(t, fs.AuditResultSuccess, record.Result)
+	assert.Empty(t, record.DestPath)
+}
@@ -0,0 +1,160 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkResult(t *testing.T) {
+	t.Run("test MetadataBulkResult", testMetadataBulkResult)
+	t.Run("test ChangeACLBulkResult", testChangeACLBulkResult)
+	t.Run("test MirrorDirBulkResult", testMirrorDirBulkResult)
+}
+
+func testMetadataBulkResult(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// cache metadata, then add the same metadata behind the cache so adding it to b.txt fails
+	metas, err := filesystem.ListMetadata(dir + "/sub/b.txt")
+	failError(t, err)
+	assert.Empty(t, metas)
+
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, dir+"/sub/b.txt", &types.IRODSMeta{Name: "key", Value: "value"})
+	failError(t, err)
+
+	_, err = filesystem.AddMetadataWithOptions(dir, "key", "value", "", &fs.MetadataOptions{Recursive: true})
+	assert.Error(t, err)
+
+	filesystem.ClearCache()
+	_, err = filesystem.DeleteMetadataByNameWithOptions(dir, "key", &fs.MetadataOptions{Recursive: true})
+	failError(t, err)
+
+	metas, err = filesystem.ListMetadata(dir + "/sub/b.txt")
+	failError(t, err)
+	assert.Empty(t, metas)
+
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, dir+"/sub/b.txt", &types.IRODSMeta{Name: "key", Value: "value"})
+	failError(t, err)
+
+	result, err := filesystem.AddMetadataWithResult(dir, "key", "value", "", &fs.MetadataOptions{Recursive: true, ContinueOnError: true})
+	failError(t, err)
+	assert.Len(t, result.Items, 4)
+	assert.True(t, result.HasFailure())
+	assert.Error(t, result.Err())
+
+	failed := result.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, dir+"/sub/b.txt", failed[0].Path)
+	assert.Equal(t, fs.FileSystemActionAddMetadata, failed[0].Action)
+
+	assert.Len(t, result.Succeeded(), 3)
+	assert.Len(t, result.Actions(), 3)
+
+	metas, err = filesystem.ListMetadata(dir + "/a.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+}
+
+func testChangeACLBulkResult(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "bob_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	options := &fs.ChangeACLOptions{Recursive: true, ContinueOnError: true}
+	result, err := filesystem.ChangeACLWithResult(dir, types.IRODSAccessLevelReadObject, "bob", "", options)
+	failError(t, err)
+	assert.Len(t, result.Items, 4)
+	assert.False(t, result.HasFailure())
+	assert.NoError(t, result.Err())
+	assert.Equal(t, dir, result.Items[0].Path)
+
+	accesses, err := filesystem.ListACLs(dir + "/sub/b.txt")
+	failError(t, err)
+
+	hasBob := false
+	for _, access := range accesses {
+		if access.UserName == "bob" {
+			hasBob = true
+		}
+	}
+	assert.True(t, hasBob)
+}
+
+func testMirrorDirBulkResult(t *testing.T) {
+	srcServer := startMockServer(t)
+	defer srcServer.Stop()
+
+	destServer := startMockServer(t)
+	defer destServer.Stop()
+
+	srcAccount, err := srcServer.GetAccount("alice")
+	failError(t, err)
+
+	destAccount, err := destServer.GetAccount("alice")
+	failError(t, err)
+
+	srcDir := "/mockzone/home/alice/data"
+	destDir := "/mockzone/home/alice/mirror"
+	makeDryRunTree(t, srcServer, srcDir)
+
+	err = destServer.MakeCollection(destDir, "alice")
+	failError(t, err)
+
+	err = destServer.PutDataObject(destDir+"/old.txt", "alice", []byte("old"))
+	failError(t, err)
+
+	srcFS, err := fs.NewFileSystemWithDefault(srcAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer srcFS.Release()
+
+	destFS, err := fs.NewFileSystemWithDefault(destAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer destFS.Release()
+
+	result, err := fs.MirrorDir(srcFS, destFS, srcDir, &fs.MirrorDirOptions{
+		DestPath:        destDir,
+		Delete:          true,
+		ContinueOnError: true,
+	})
+	failError(t, err)
+	assert.False(t, result.Results.HasFailure())
+
+	actions := map[string]fs.FileSystemActionType{}
+	for _, item := range result.Results.Items {
+		actions[item.Path] = item.Action
+	}
+
+	assert.Equal(t, map[string]fs.FileSystemActionType{
+		destDir + "/sub":       fs.FileSystemActionMakeDir,
+		destDir + "/a.txt":     fs.FileSystemActionCopyFile,
+		destDir + "/sub/b.txt": fs.FileSystemActionCopyFile,
+		destDir + "/old.txt":   fs.FileSystemActionRemoveFile,
+	}, actions)
+	assert.Equal(t, int64(len("content of a.txt")+len("content of sub/b.txt")), result.Results.TotalBytes())
+}
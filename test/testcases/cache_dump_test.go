@@ -0,0 +1,76 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheDump(t *testing.T) {
+	t.Run("test DumpCache", testDumpCache)
+}
+
+func testDumpCache(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	err = mockServer.MakeCollection(dir, "alice")
+	failError(t, err)
+
+	err = mockServer.PutDataObject(dir+"/a.txt", "alice", []byte("data"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithCacheTimeout(time.Hour))
+	failError(t, err)
+	defer filesystem.Release()
+
+	_, err = filesystem.List(dir)
+	failError(t, err)
+
+	_, err = filesystem.ListACLs(dir + "/a.txt")
+	failError(t, err)
+
+	assert.False(t, filesystem.Exists(dir+"/missing.txt"))
+
+	snapshot := filesystem.SnapshotCache()
+
+	keys := func(items []*fs.CacheDumpItem) []string {
+		k := []string{}
+		for _, item := range items {
+			k = append(k, item.Key)
+		}
+		return k
+	}
+
+	assert.Contains(t, keys(snapshot.Entries), dir+"/a.txt")
+	assert.Contains(t, keys(snapshot.Dirs), dir)
+	assert.Equal(t, []string{dir + "/missing.txt"}, keys(snapshot.NegativeEntries))
+	assert.Equal(t, []string{dir + "/a.txt"}, keys(snapshot.ACLs))
+
+	for _, item := range snapshot.Entries {
+		age, err := time.ParseDuration(item.Age)
+		failError(t, err)
+		assert.True(t, age >= 0 && age < time.Minute)
+
+		expiresIn, err := time.ParseDuration(item.ExpiresIn)
+		failError(t, err)
+		assert.True(t, expiresIn > 59*time.Minute)
+	}
+
+	buffer := bytes.Buffer{}
+	err = filesystem.DumpCache(&buffer)
+	failError(t, err)
+
+	dumped := map[string]interface{}{}
+	err = json.Unmarshal(buffer.Bytes(), &dumped)
+	failError(t, err)
+	assert.Len(t, dumped["negative_entries"], 1)
+}
@@ -0,0 +1,131 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeOwner(t *testing.T) {
+	t.Run("test ChangeOwner", testChangeOwner)
+	t.Run("test ChangeOwnerDryRun", testChangeOwnerDryRun)
+}
+
+func getAccessLevels(t *testing.T, filesystem *fs.FileSystem, path string) map[string]types.IRODSAccessLevelType {
+	accesses, err := filesystem.ListACLs(path)
+	failError(t, err)
+
+	accessLevels := map[string]types.IRODSAccessLevelType{}
+	for _, access := range accesses {
+		accessLevels[access.UserName] = access.AccessLevel
+	}
+	return accessLevels
+}
+
+func testChangeOwner(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "", types.IRODSUserRodsUser)
+	failError(t, err)
+	err = mockServer.AddUser("team", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	projectDir := homedir + "/project"
+	paths := []string{projectDir, projectDir + "/sub", projectDir + "/file.txt", projectDir + "/sub/file.txt"}
+
+	err = mockServer.MakeCollection(projectDir+"/sub", "alice")
+	failError(t, err)
+	for _, p := range paths[2:] {
+		err = mockServer.PutDataObject(p, "alice", []byte("hello world"))
+		failError(t, err)
+	}
+
+	err = filesystem.ChangeACL(projectDir+"/file.txt", types.IRODSAccessLevelOwner, "team", "")
+	failError(t, err)
+
+	// cache ACLs
+	accessLevels := getAccessLevels(t, filesystem, projectDir+"/file.txt")
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["alice"])
+
+	actions, err := filesystem.ChangeOwnerWithOptions(projectDir, "bob", "", &fs.ChangeOwnerOptions{
+		Recursive: true,
+		AdminFlag: true,
+	})
+	failError(t, err)
+	assert.Len(t, actions, len(paths)*2)
+	assert.Equal(t, fs.FileSystemActionChangeOwner, actions[0].Type)
+	assert.Equal(t, projectDir, actions[0].Path)
+	assert.Equal(t, "bob#mockzone", actions[0].Detail)
+	assert.Equal(t, fs.FileSystemActionChangeACL, actions[1].Type)
+	assert.Equal(t, projectDir, actions[1].Path)
+	assert.Equal(t, "alice#mockzone:null", actions[1].Detail)
+
+	for _, p := range paths {
+		accessLevels := getAccessLevels(t, filesystem, p)
+		assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["bob"], p)
+		assert.NotContains(t, accessLevels, "alice", p)
+	}
+
+	// groups are not previous owners
+	accessLevels = getAccessLevels(t, filesystem, projectDir+"/file.txt")
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["team"])
+
+	// the previous owner is kept
+	err = mockServer.PutDataObject(homedir+"/kept.txt", "alice", []byte("hello world"))
+	failError(t, err)
+
+	_, err = filesystem.ChangeOwnerWithOptions(homedir+"/kept.txt", "bob", "", &fs.ChangeOwnerOptions{
+		AdminFlag:          true,
+		KeepPreviousOwners: true,
+	})
+	failError(t, err)
+
+	accessLevels = getAccessLevels(t, filesystem, homedir+"/kept.txt")
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["bob"])
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["alice"])
+
+	err = filesystem.ChangeOwner(homedir+"/no_such_file.txt", "bob", "")
+	assert.Error(t, err)
+	assert.True(t, types.IsFileNotFoundError(err))
+}
+
+func testChangeOwnerDryRun(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	filePath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	actions, err := filesystem.ChangeOwnerWithOptions(filePath, "bob", "", &fs.ChangeOwnerOptions{
+		AdminFlag: true,
+		DryRun:    true,
+	})
+	failError(t, err)
+	assert.Len(t, actions, 2)
+
+	accessLevels := getAccessLevels(t, filesystem, filePath)
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["alice"])
+	assert.NotContains(t, accessLevels, "bob")
+}
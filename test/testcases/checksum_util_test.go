@@ -0,0 +1,104 @@
+package testcases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util/checksum"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	checksumUtilTestData      = "hello"
+	checksumUtilTestMD5Hex    = "5d41402abc4b2a76b9719d911017c592"
+	checksumUtilTestSHA256Hex = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	checksumUtilTestSHA256    = "sha2:LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="
+	checksumUtilTestSHA1      = "sha1:qvTGHdzF6KLavt4PO0gs2a6pQ00="
+)
+
+func TestChecksumUtil(t *testing.T) {
+	t.Run("test ParseChecksum", testParseChecksum)
+	t.Run("test ComputeChecksum", testComputeChecksum)
+	t.Run("test CompareChecksums", testCompareChecksums)
+}
+
+func testParseChecksum(t *testing.T) {
+	parsed, err := checksum.Parse(checksumUtilTestSHA256)
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, parsed.Algorithm)
+	assert.Equal(t, checksumUtilTestSHA256, parsed.IRODSChecksumString)
+
+	// hex digests of sha algorithms are given in iRODS format
+	parsed, err = checksum.Parse("SHA256:" + checksumUtilTestSHA256Hex)
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, parsed.Algorithm)
+	assert.Equal(t, checksumUtilTestSHA256, parsed.IRODSChecksumString)
+
+	// md5 with or without the algorithm name
+	parsed, err = checksum.Parse("md5:" + checksumUtilTestMD5Hex)
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmMD5, parsed.Algorithm)
+	assert.Equal(t, checksumUtilTestMD5Hex, parsed.IRODSChecksumString)
+
+	parsed, err = checksum.Parse(" " + checksumUtilTestMD5Hex + "\n")
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmMD5, parsed.Algorithm)
+
+	// algorithms told from digest sizes
+	parsed, err = checksum.Parse(checksumUtilTestSHA256Hex)
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, parsed.Algorithm)
+
+	formatted, err := checksum.Format(parsed.Algorithm, parsed.Checksum)
+	failError(t, err)
+	assert.Equal(t, checksumUtilTestSHA256, formatted)
+
+	_, err = checksum.Format(types.ChecksumAlgorithmSHA512, parsed.Checksum)
+	assert.Error(t, err)
+
+	for _, invalid := range []string{"", "md5:xyz", "crc32:" + checksumUtilTestMD5Hex, "sha1:" + checksumUtilTestMD5Hex} {
+		_, err = checksum.Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func testComputeChecksum(t *testing.T) {
+	computed, err := checksum.Compute(strings.NewReader(checksumUtilTestData), types.ChecksumAlgorithmSHA256)
+	failError(t, err)
+	assert.Equal(t, checksumUtilTestSHA256, computed.IRODSChecksumString)
+
+	computed, err = checksum.Compute(strings.NewReader(checksumUtilTestData), types.ChecksumAlgorithmSHA1)
+	failError(t, err)
+	assert.Equal(t, checksumUtilTestSHA1, computed.IRODSChecksumString)
+
+	computed, err = checksum.Compute(strings.NewReader(checksumUtilTestData), types.ChecksumAlgorithmMD5)
+	failError(t, err)
+	assert.Equal(t, checksumUtilTestMD5Hex, computed.IRODSChecksumString)
+
+	ok, err := checksum.Verify(strings.NewReader(checksumUtilTestData), checksumUtilTestSHA256)
+	failError(t, err)
+	assert.True(t, ok)
+
+	ok, err = checksum.Verify(strings.NewReader("hello!"), checksumUtilTestSHA256)
+	failError(t, err)
+	assert.False(t, ok)
+}
+
+func testCompareChecksums(t *testing.T) {
+	equal, err := checksum.Equal(checksumUtilTestSHA256, "sha256:"+checksumUtilTestSHA256Hex)
+	failError(t, err)
+	assert.True(t, equal)
+
+	equal, err = checksum.Equal(checksumUtilTestMD5Hex, "MD5:"+strings.ToUpper(checksumUtilTestMD5Hex))
+	failError(t, err)
+	assert.True(t, equal)
+
+	equal, err = checksum.Equal(checksumUtilTestMD5Hex, "00000000000000000000000000000000")
+	failError(t, err)
+	assert.False(t, equal)
+
+	// cannot be compared
+	_, err = checksum.Equal(checksumUtilTestSHA256, checksumUtilTestMD5Hex)
+	assert.Error(t, err)
+}
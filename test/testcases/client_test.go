@@ -0,0 +1,253 @@
+package testcases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/client"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	t.Run("test ClientFS", testClientFS)
+	t.Run("test ClientGenQuery", testClientGenQuery)
+	t.Run("test ClientSpecificQuery", testClientSpecificQuery)
+	t.Run("test ClientAdmin", testClientAdmin)
+	t.Run("test ClientQuotaUsage", testClientQuotaUsage)
+	t.Run("test ClientSpecificQueryAdmin", testClientSpecificQueryAdmin)
+	t.Run("test ClientShutdown", testClientShutdown)
+}
+
+func testClientFS(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	assert.Equal(t, account, irodsClient.GetAccount())
+
+	err = irodsClient.Ping()
+	failError(t, err)
+
+	homeDir := "/mockzone/home/alice"
+	err = irodsClient.FS().MakeDir(homeDir+"/client_dir", false)
+	failError(t, err)
+
+	assert.True(t, irodsClient.FS().ExistsDir(homeDir+"/client_dir"))
+}
+
+func testClientGenQuery(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	homeDir := "/mockzone/home/alice"
+	for _, name := range []string{"query_a", "query_b", "query_c"} {
+		err = irodsClient.FS().MakeDir(homeDir+"/"+name, false)
+		failError(t, err)
+	}
+
+	// values are in the order of selects
+	rows, err := irodsClient.Query().GenQuery([]common.ICATColumnNumber{common.ICAT_COLUMN_COLL_OWNER_NAME, common.ICAT_COLUMN_COLL_NAME}, map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: "like '" + homeDir + "/query_%'",
+	})
+	failError(t, err)
+	assert.Len(t, rows, 3)
+
+	names := []string{}
+	for _, row := range rows {
+		assert.Len(t, row, 2)
+		assert.Equal(t, "alice", row[0])
+		names = append(names, row[1])
+	}
+	assert.ElementsMatch(t, []string{homeDir + "/query_a", homeDir + "/query_b", homeDir + "/query_c"}, names)
+
+	// no rows
+	rows, err = irodsClient.Query().GenQuery([]common.ICATColumnNumber{common.ICAT_COLUMN_COLL_NAME}, map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: "= '" + homeDir + "/no_such_dir'",
+	})
+	failError(t, err)
+	assert.Empty(t, rows)
+
+	_, err = irodsClient.Query().GenQuery(nil, nil)
+	assert.Error(t, err)
+}
+
+func testClientSpecificQuery(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	rows, err := irodsClient.Query().SpecificQuery("ShowCollAcls", "/mockzone/home/alice")
+	failError(t, err)
+	assert.NotEmpty(t, rows)
+
+	found := false
+	for _, row := range rows {
+		assert.Len(t, row, 4)
+		if row[0] == "alice" {
+			found = true
+			assert.Equal(t, "mockzone", row[1])
+		}
+	}
+	assert.True(t, found)
+
+	_, err = irodsClient.Query().SpecificQuery("NoSuchQuery")
+	assert.Error(t, err)
+	assert.Equal(t, common.CAT_UNKNOWN_SPECIFIC_QUERY, types.GetIRODSErrorCode(err))
+}
+
+func testClientAdmin(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	// zone defaults to the client zone
+	user, err := irodsClient.Admin().GetUser("alice", "")
+	failError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	assert.Equal(t, "mockzone", user.Zone)
+
+	users, err := irodsClient.Admin().ListUsers()
+	failError(t, err)
+
+	names := []string{}
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	assert.Contains(t, names, "alice")
+}
+
+func testClientQuotaUsage(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	// not calculated yet
+	usages, err := irodsClient.Admin().ListQuotaUsage()
+	failError(t, err)
+	assert.Len(t, usages, 0)
+
+	calculatedTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	err = mockServer.SetQuotaUsage("alice", 1024, calculatedTime)
+	failError(t, err)
+	err = mockServer.SetQuotaUsage("rods", 2048, calculatedTime)
+	failError(t, err)
+
+	usages, err = irodsClient.Admin().ListQuotaUsage()
+	failError(t, err)
+	assert.Len(t, usages, 2)
+
+	usages, err = irodsClient.Admin().ListUserQuotaUsage("alice")
+	failError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "alice", usages[0].UserName)
+	assert.Equal(t, "mockzone", usages[0].UserZone)
+	assert.Equal(t, mock.MockResourceName, usages[0].RescName)
+	assert.Equal(t, int64(1024), usages[0].Usage)
+	assert.True(t, calculatedTime.Equal(usages[0].ModifyTime))
+}
+
+func testClientSpecificQueryAdmin(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	queries, err := irodsClient.Admin().ListSpecificQueries()
+	failError(t, err)
+	assert.Len(t, queries, 0)
+
+	sql := "select coll_name from r_coll_main where coll_name like ?"
+	err = irodsClient.Admin().AddSpecificQuery("listCollsLike", sql)
+	failError(t, err)
+
+	// alias already registered
+	err = irodsClient.Admin().AddSpecificQuery("listCollsLike", sql)
+	assert.Error(t, err)
+
+	queries, err = irodsClient.Admin().ListSpecificQueries()
+	failError(t, err)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, "listCollsLike", queries[0].Alias)
+	assert.Equal(t, sql, queries[0].SQL)
+
+	// registered queries can be run
+	_, err = irodsClient.Query().SpecificQuery("listCollsLike", "/mockzone/home/%")
+	failError(t, err)
+
+	err = irodsClient.Admin().RemoveSpecificQuery("listCollsLike")
+	failError(t, err)
+
+	_, err = irodsClient.Query().SpecificQuery("listCollsLike", "/mockzone/home/%")
+	assert.Equal(t, common.CAT_UNKNOWN_SPECIFIC_QUERY, types.GetIRODSErrorCode(err))
+
+	err = irodsClient.Admin().RemoveSpecificQuery("listCollsLike")
+	assert.Error(t, err)
+
+	// requires a rodsadmin account
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	aliceClient, err := client.NewClientWithDefault(aliceAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer aliceClient.Release()
+
+	err = aliceClient.Admin().AddSpecificQuery("listCollsLike", sql)
+	assert.Error(t, err)
+}
+
+func testClientShutdown(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+
+	err = irodsClient.Shutdown(context.Background())
+	failError(t, err)
+
+	_, err = irodsClient.Query().GenQuery([]common.ICATColumnNumber{common.ICAT_COLUMN_COLL_NAME}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/client"
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigOptions(t *testing.T) {
+	t.Run("test SessionConfigOptions", testSessionConfigOptions)
+	t.Run("test FileSystemConfigOptions", testFileSystemConfigOptions)
+	t.Run("test FileSystemWithNoCache", testFileSystemWithNoCache)
+	t.Run("test FileSystemMetadataConnections", testFileSystemMetadataConnections)
+}
+
+func testSessionConfigOptions(t *testing.T) {
+	config := session.NewIRODSSessionConfigWithOptions("go-irodsclient-test")
+	assert.Equal(t, session.NewIRODSSessionConfigWithDefault("go-irodsclient-test"), config)
+
+	config = session.NewIRODSSessionConfigWithOptions("go-irodsclient-test",
+		session.WithConnectionMax(20),
+		session.WithConnectionInitNumber(2),
+		session.WithOperationTimeout(time.Minute),
+		session.WithConnectionKeepaliveInterval(30*time.Second),
+		session.WithStartNewTransaction(false),
+		session.WithRequestRateLimit(100, 10),
+		session.WithTransferBufferSize(8*1024*1024),
+		session.WithTransferBlockSize(64*1024*1024),
+		session.WithTLSSessionCacheSize(-1),
+		session.WithLazyConnection(true),
+		session.WithOperationScheduling(2),
+		session.WithServerUnavailableRetry(time.Hour, time.Minute),
+	)
+	assert.Equal(t, "go-irodsclient-test", config.ApplicationName)
+	assert.Equal(t, 20, config.ConnectionMax)
+	assert.Equal(t, 2, config.ConnectionInitNumber)
+	assert.Equal(t, time.Minute, config.OperationTimeout)
+	assert.Equal(t, 30*time.Second, config.ConnectionKeepaliveInterval)
+	assert.False(t, config.StartNewTransaction)
+	assert.Equal(t, 100.0, config.RequestRateLimiter.GetRate())
+	assert.Equal(t, 10, config.RequestRateLimiter.GetBurst())
+	assert.Equal(t, 8*1024*1024, config.TransferBufferSize)
+	assert.Equal(t, int64(64*1024*1024), config.TransferBlockSize)
+	assert.Equal(t, -1, config.TLSSessionCacheSize)
+	assert.True(t, config.LazyConnection)
+	assert.True(t, config.OperationScheduling)
+	assert.Equal(t, 2, config.InteractiveConnectionReserve)
+	assert.Equal(t, time.Hour, config.ServerUnavailableRetryTimeout)
+	assert.Equal(t, time.Minute, config.ServerUnavailableRetryInterval)
+	// untouched
+	assert.Equal(t, session.IRODSSessionConnectionLifespanDefault, config.ConnectionLifespan)
+	assert.Equal(t, session.IRODSSessionTCPBufferSizeDefault, config.TcpBufferSize)
+	assert.Nil(t, session.NewIRODSSessionConfigWithDefault("go-irodsclient-test").RequestRateLimiter)
+
+	// connection max is clamped like the positional constructor
+	config = session.NewIRODSSessionConfigWithOptions("go-irodsclient-test", session.WithConnectionMax(1))
+	assert.Equal(t, session.IRODSSessionConnectionMaxMin, config.ConnectionMax)
+}
+
+func testFileSystemConfigOptions(t *testing.T) {
+	config := fs.NewFileSystemConfigWithOptions("go-irodsclient-test")
+	assert.Equal(t, fs.NewFileSystemConfigWithDefault("go-irodsclient-test"), config)
+
+	config = fs.NewFileSystemConfigWithOptions("go-irodsclient-test",
+		fs.WithConnectionMax(1),
+		fs.WithCacheTimeout(time.Second),
+		fs.WithCacheCleanupTime(2*time.Second),
+		fs.WithUnicodeNormalization(util.UnicodeNormalizationNFC),
+		fs.WithVersioning(true),
+		fs.WithNoCache(),
+		fs.WithTransferBufferSize(1024*1024),
+		fs.WithTransferBlockSize(16*1024*1024),
+		fs.WithLazyConnection(true),
+		fs.WithMetadataConnectionMax(1),
+		fs.WithMetadataOperationTimeout(10*time.Second),
+		fs.WithOperationScheduling(2),
+		fs.WithServerUnavailableRetry(time.Hour, time.Minute),
+	)
+	assert.Equal(t, fs.FileSystemConnectionMaxMin, config.ConnectionMax)
+	assert.Equal(t, fs.FileSystemConnectionMaxMin, config.MetadataConnectionMax)
+	assert.Equal(t, 10*time.Second, config.MetadataOperationTimeout)
+	assert.Equal(t, time.Second, config.CacheTimeout)
+	assert.Equal(t, 2*time.Second, config.CacheCleanupTime)
+	assert.Equal(t, util.UnicodeNormalizationNFC, config.UnicodeNormalization)
+	assert.True(t, config.Versioning)
+	assert.True(t, config.DisableCache)
+	assert.Equal(t, 1024*1024, config.TransferBufferSize)
+	assert.Equal(t, int64(16*1024*1024), config.TransferBlockSize)
+	assert.True(t, config.LazyConnection)
+	assert.True(t, config.OperationScheduling)
+	assert.Equal(t, 2, config.InteractiveConnectionReserve)
+	assert.Equal(t, time.Hour, config.ServerUnavailableRetryTimeout)
+	assert.Equal(t, time.Minute, config.ServerUnavailableRetryInterval)
+	// untouched
+	assert.True(t, config.StartNewTransaction)
+	assert.Equal(t, fs.FileSystemTimeoutDefault, config.OperationTimeout)
+}
+
+func testFileSystemWithNoCache(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	cached, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithCacheTimeout(time.Hour))
+	failError(t, err)
+	defer cached.Release()
+
+	uncached, err := client.NewClientWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer uncached.Release()
+
+	dirPath := "/mockzone/home/alice/no_cache_dir"
+
+	// both see nothing, the cached one remembers it
+	assert.False(t, cached.Exists(dirPath))
+	assert.False(t, uncached.FS().Exists(dirPath))
+
+	// changed behind the file systems
+	err = mockServer.MakeCollection(dirPath, "alice")
+	failError(t, err)
+
+	// the cached one is stale, the uncached one reads the catalog
+	assert.False(t, cached.Exists(dirPath))
+	assert.True(t, uncached.FS().Exists(dirPath))
+}
+
+func testFileSystemMetadataConnections(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test",
+		fs.WithConnectionInitNumber(1),
+		fs.WithMetadataConnectionInitNumber(3),
+		fs.WithMetadataConnectionMax(6),
+	)
+	failError(t, err)
+	defer filesystem.Release()
+
+	// pools are sized independently
+	assert.Equal(t, 4, filesystem.ConnectionTotal())
+
+	conns := []*connection.IRODSConnection{}
+	for i := 0; i < 6; i++ {
+		conn, err := filesystem.GetMetadataConnection()
+		failError(t, err)
+		conns = append(conns, conn)
+	}
+	assert.Equal(t, 7, filesystem.ConnectionTotal())
+
+	for _, conn := range conns {
+		filesystem.ReturnMetadataConnection(conn)
+	}
+	total := filesystem.ConnectionTotal()
+
+	// the connection for data transfers is still idle
+	ioConn, err := filesystem.GetIOConnection()
+	failError(t, err)
+	defer filesystem.ReturnIOConnection(ioConn)
+	assert.Equal(t, total, filesystem.ConnectionTotal())
+}
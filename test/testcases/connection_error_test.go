@@ -0,0 +1,183 @@
+package testcases
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionError(t *testing.T) {
+	t.Run("test ConnectionRefusedError", testConnectionRefusedError)
+	t.Run("test SSLNegotiationError", testSSLNegotiationError)
+	t.Run("test VersionIncompatibleError", testVersionIncompatibleError)
+	t.Run("test AuthError", testAuthError)
+	t.Run("test ServerUnavailableError", testServerUnavailableError)
+	t.Run("test ServerUnavailableRetry", testServerUnavailableRetry)
+}
+
+func testConnectionRefusedError(t *testing.T) {
+	mockServer := startMockServer(t)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	// nothing listens on the port
+	mockServer.Stop()
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionRefusedError(err))
+	// still a connection error, worth retrying
+	assert.True(t, types.IsConnectionError(err))
+	assert.False(t, types.IsPermanantFailure(err))
+}
+
+func testSSLNegotiationError(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sslConfig, err := types.CreateIRODSSSLConfigWithTLSConfig(&tls.Config{}, 32, "AES-256-CBC", 8, 16)
+	failError(t, err)
+
+	// the mock server refuses SSL
+	account.ClientServerNegotiation = true
+	account.CSNegotiationPolicy = types.CSNegotiationRequireSSL
+	account.SSLConfiguration = sslConfig
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsSSLNegotiationError(err))
+	assert.False(t, types.IsConnectionRefusedError(err))
+	assert.True(t, types.IsPermanantFailure(err))
+}
+
+func testVersionIncompatibleError(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.SetReleaseVersion("rods3.3.1")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsVersionIncompatibleError(err))
+	assert.True(t, types.IsPermanantFailure(err))
+	assert.False(t, conn.IsConnected())
+}
+
+func testAuthError(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	account.Password = "wrong_password"
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsAuthError(err))
+	assert.False(t, types.IsConnectionError(err))
+	assert.True(t, types.IsPermanantFailure(err))
+}
+
+func testServerUnavailableError(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	mockServer.SetStartupError(common.SYS_USER_NOT_ALLOWED_TO_CONN)
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsServerUnavailableError(err))
+	// still a connection error, worth retrying
+	assert.True(t, types.IsConnectionError(err))
+	assert.False(t, types.IsPermanantFailure(err))
+	assert.False(t, conn.IsConnected())
+
+	var unavailableErr *types.ServerUnavailableError
+	if assert.ErrorAs(t, err, &unavailableErr) {
+		assert.Equal(t, common.SYS_USER_NOT_ALLOWED_TO_CONN, unavailableErr.GetCode())
+	}
+
+	// other startup errors are not for connection control
+	mockServer.SetStartupError(common.SYS_INTERNAL_ERR)
+
+	conn = connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.False(t, types.IsServerUnavailableError(err))
+	assert.True(t, types.IsConnectionError(err))
+}
+
+func testServerUnavailableRetry(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	mockServer.SetStartupError(common.SYS_MAX_CONNECT_COUNT_EXCEEDED)
+
+	// fails right away without retry
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test", session.WithLazyConnection(true))
+	failError(t, err)
+
+	_, err = sess.AcquireConnection()
+	assert.Error(t, err)
+	assert.True(t, types.IsServerUnavailableError(err))
+	sess.Release()
+
+	// waits for the end of the maintenance
+	sess, err = session.NewIRODSSessionWithOptions(account, "go-irodsclient-test",
+		session.WithLazyConnection(true),
+		session.WithServerUnavailableRetry(10*time.Second, 20*time.Millisecond),
+	)
+	failError(t, err)
+	defer sess.Release()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		mockServer.SetStartupError(0)
+	}()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+	assert.True(t, conn.IsConnected())
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+
+	// gives up after the timeout
+	mockServer.SetStartupError(common.SYS_USER_NOT_ALLOWED_TO_CONN)
+
+	sess2, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test",
+		session.WithLazyConnection(true),
+		session.WithServerUnavailableRetry(100*time.Millisecond, 20*time.Millisecond),
+	)
+	failError(t, err)
+	defer sess2.Release()
+
+	_, err = sess2.AcquireUnmanagedConnection()
+	assert.Error(t, err)
+	assert.True(t, types.IsServerUnavailableError(err))
+}
@@ -0,0 +1,177 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyDir(t *testing.T) {
+	t.Run("test CopyDir", testCopyDir)
+	t.Run("test CopyDirWithAttributes", testCopyDirWithAttributes)
+}
+
+func makeCopyDirTestTree(t *testing.T, mockServer *mock.IRODSMockServer, srcPath string) {
+	err := mockServer.MakeCollection(srcPath+"/sub/deeper", "alice")
+	failError(t, err)
+
+	err = mockServer.PutDataObject(srcPath+"/a.txt", "alice", []byte("file a"))
+	failError(t, err)
+	err = mockServer.PutDataObject(srcPath+"/sub/b.txt", "alice", []byte("file b in sub"))
+	failError(t, err)
+	err = mockServer.PutDataObject(srcPath+"/sub/deeper/c.txt", "alice", []byte("file c in deeper"))
+	failError(t, err)
+}
+
+func testCopyDir(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src"
+	makeCopyDirTestTree(t, mockServer, srcPath)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// copy to a new dir
+	lastProcessed := int64(-1)
+	lastTotal := int64(-1)
+	callback := func(processed int64, total int64) {
+		assert.GreaterOrEqual(t, processed, lastProcessed)
+		lastProcessed = processed
+		lastTotal = total
+	}
+
+	err = filesystem.CopyDir(srcPath, homedir+"/dest", false, false, false, callback)
+	failError(t, err)
+
+	assert.Equal(t, int64(6+13+16), lastTotal)
+	assert.Equal(t, lastTotal, lastProcessed)
+
+	for path, content := range map[string]string{
+		"/dest/a.txt":            "file a",
+		"/dest/sub/b.txt":        "file b in sub",
+		"/dest/sub/deeper/c.txt": "file c in deeper",
+	} {
+		data, err := mockServer.GetDataObject(homedir + path)
+		failError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+
+	assert.True(t, filesystem.ExistsDir(homedir+"/dest/sub/deeper"))
+
+	// copy into an existing dir
+	err = filesystem.CopyDir(srcPath, homedir+"/dest", false, false, false, nil)
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(homedir+"/dest/src/sub/deeper/c.txt"))
+
+	// existing files are not overwritten without force
+	err = filesystem.CopyDirToDir(srcPath, homedir+"/dest", false, false, false, nil)
+	assert.Error(t, err)
+
+	err = mockServer.PutDataObject(srcPath+"/a.txt", "alice", []byte("file a updated"))
+	failError(t, err)
+
+	err = filesystem.CopyDirToDir(srcPath, homedir+"/dest", true, false, false, nil)
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(homedir + "/dest/a.txt")
+	failError(t, err)
+	assert.Equal(t, "file a updated", string(data))
+
+	// cannot copy a dir into itself
+	err = filesystem.CopyDirToDir(srcPath, srcPath+"/sub/copy", false, false, false, nil)
+	assert.Error(t, err)
+
+	// source must be a dir
+	err = filesystem.CopyDirToDir(srcPath+"/a.txt", homedir+"/dest2", false, false, false, nil)
+	assert.Error(t, err)
+}
+
+func testCopyDirWithAttributes(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src"
+	makeCopyDirTestTree(t, mockServer, srcPath)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.AddMetadata(srcPath+"/sub", "dir_attr", "dir_value", "")
+	failError(t, err)
+	err = filesystem.AddMetadata(srcPath+"/sub/b.txt", "file_attr", "file_value", "file_units")
+	failError(t, err)
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.ChangeDataObjectAccess(conn, srcPath+"/sub/b.txt", types.IRODSAccessLevelReadObject, "rods", "mockzone", false)
+	failError(t, err)
+	err = irods_fs.ChangeCollectionAccess(conn, srcPath+"/sub", types.IRODSAccessLevelModifyObject, "rods", "mockzone", false, false)
+	failError(t, err)
+	filesystem.ReturnMetadataConnection(conn)
+
+	// attributes are not copied by default
+	err = filesystem.CopyDirToDir(srcPath, homedir+"/plain", false, false, false, nil)
+	failError(t, err)
+
+	metas, err := filesystem.ListMetadata(homedir + "/plain/sub/b.txt")
+	failError(t, err)
+	assert.Len(t, metas, 0)
+
+	accesses, err := filesystem.ListACLs(homedir + "/plain/sub/b.txt")
+	failError(t, err)
+	assert.Len(t, accesses, 1)
+
+	// copy with metadata and ACLs
+	err = filesystem.CopyDirToDir(srcPath, homedir+"/dest", false, true, true, nil)
+	failError(t, err)
+
+	metas, err = filesystem.ListMetadata(homedir + "/dest/sub/b.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "file_attr", metas[0].Name)
+	assert.Equal(t, "file_value", metas[0].Value)
+	assert.Equal(t, "file_units", metas[0].Units)
+
+	metas, err = filesystem.ListMetadata(homedir + "/dest/sub")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "dir_attr", metas[0].Name)
+
+	accessLevels := map[string]types.IRODSAccessLevelType{}
+	accesses, err = filesystem.ListACLs(homedir + "/dest/sub/b.txt")
+	failError(t, err)
+	for _, access := range accesses {
+		accessLevels[access.UserName] = access.AccessLevel
+	}
+	assert.Equal(t, map[string]types.IRODSAccessLevelType{
+		"alice": types.IRODSAccessLevelOwner,
+		"rods":  types.IRODSAccessLevelReadObject,
+	}, accessLevels)
+
+	accessLevels = map[string]types.IRODSAccessLevelType{}
+	accesses, err = filesystem.ListACLs(homedir + "/dest/sub")
+	failError(t, err)
+	for _, access := range accesses {
+		accessLevels[access.UserName] = access.AccessLevel
+	}
+	assert.Equal(t, map[string]types.IRODSAccessLevelType{
+		"alice": types.IRODSAccessLevelOwner,
+		"rods":  types.IRODSAccessLevelModifyObject,
+	}, accessLevels)
+}
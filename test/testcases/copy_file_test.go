@@ -0,0 +1,145 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFile(t *testing.T) {
+	t.Run("test CopyFileWithOptions", testCopyFileWithOptions)
+	t.Run("test CopyFileWithAttributes", testCopyFileWithAttributes)
+}
+
+func testCopyFileWithOptions(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("source content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// copy with checksum verification to the resource
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/dest.txt", &fs.CopyFileOptions{
+		Resource:       "demoResc",
+		VerifyChecksum: true,
+	})
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(homedir + "/dest.txt")
+	failError(t, err)
+	assert.Equal(t, "source content", string(data))
+
+	entry, err := filesystem.StatFile(homedir + "/dest.txt")
+	failError(t, err)
+	assert.NotEmpty(t, entry.CheckSum)
+
+	// unknown resource
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/dest2.txt", &fs.CopyFileOptions{
+		Resource: "unknownResc",
+	})
+	assert.Error(t, err)
+	assert.False(t, filesystem.ExistsFile(homedir+"/dest2.txt"))
+
+	// overwrite requires the overwrite policy
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("updated source content"))
+	failError(t, err)
+
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/dest.txt", &fs.CopyFileOptions{})
+	assert.Error(t, err)
+
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/dest.txt", &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		VerifyChecksum:  true,
+	})
+	failError(t, err)
+
+	data, err = mockServer.GetDataObject(homedir + "/dest.txt")
+	failError(t, err)
+	assert.Equal(t, "updated source content", string(data))
+
+	// copy into a dir
+	err = mockServer.MakeCollection(homedir+"/dir", "alice")
+	failError(t, err)
+
+	_, err = filesystem.CopyFileWithOptions(srcPath, homedir+"/dir", nil)
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(homedir+"/dir/src.txt"))
+}
+
+func testCopyFileWithAttributes(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("source content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.AddMetadata(srcPath, "attr", "value", "units")
+	failError(t, err)
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.ChangeDataObjectAccess(conn, srcPath, types.IRODSAccessLevelModifyObject, "rods", "mockzone", false)
+	failError(t, err)
+	filesystem.ReturnMetadataConnection(conn)
+
+	// metadata only
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/meta.txt", &fs.CopyFileOptions{
+		CopyMetadata: true,
+	})
+	failError(t, err)
+
+	metas, err := filesystem.ListMetadata(homedir + "/meta.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "attr", metas[0].Name)
+	assert.Equal(t, "value", metas[0].Value)
+	assert.Equal(t, "units", metas[0].Units)
+
+	accesses, err := filesystem.ListACLs(homedir + "/meta.txt")
+	failError(t, err)
+	assert.Len(t, accesses, 1)
+
+	// ACLs only
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, homedir+"/acl.txt", &fs.CopyFileOptions{
+		CopyACLs: true,
+	})
+	failError(t, err)
+
+	metas, err = filesystem.ListMetadata(homedir + "/acl.txt")
+	failError(t, err)
+	assert.Len(t, metas, 0)
+
+	accessLevels := map[string]types.IRODSAccessLevelType{}
+	accesses, err = filesystem.ListACLs(homedir + "/acl.txt")
+	failError(t, err)
+	for _, access := range accesses {
+		accessLevels[access.UserName] = access.AccessLevel
+	}
+	assert.Equal(t, map[string]types.IRODSAccessLevelType{
+		"alice": types.IRODSAccessLevelOwner,
+		"rods":  types.IRODSAccessLevelModifyObject,
+	}, accessLevels)
+}
@@ -0,0 +1,225 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("test RemoveDirDryRun", testRemoveDirDryRun)
+	t.Run("test ChangeACLDryRun", testChangeACLDryRun)
+	t.Run("test MetadataDryRun", testMetadataDryRun)
+	t.Run("test MirrorDirDryRun", testMirrorDirDryRun)
+}
+
+func makeDryRunTree(t *testing.T, mockServer *mock.IRODSMockServer, dir string) {
+	err := mockServer.MakeCollection(dir+"/sub", "alice")
+	failError(t, err)
+
+	for _, name := range []string{"a.txt", "sub/b.txt"} {
+		err = mockServer.PutDataObject(dir+"/"+name, "alice", []byte("content of "+name))
+		failError(t, err)
+	}
+}
+
+func testRemoveDirDryRun(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// not empty
+	_, err = filesystem.RemoveDirWithOptions(dir, &fs.RemoveDirOptions{DryRun: true})
+	assert.Error(t, err)
+	assert.True(t, types.IsCollectionNotEmptyError(err))
+
+	actions, err := filesystem.RemoveDirWithOptions(dir, &fs.RemoveDirOptions{Recurse: true, Force: true, DryRun: true})
+	failError(t, err)
+	assert.Equal(t, []*fs.FileSystemAction{
+		{Type: fs.FileSystemActionRemoveFile, Path: dir + "/a.txt"},
+		{Type: fs.FileSystemActionRemoveFile, Path: dir + "/sub/b.txt"},
+		{Type: fs.FileSystemActionRemoveDir, Path: dir + "/sub"},
+		{Type: fs.FileSystemActionRemoveDir, Path: dir},
+	}, actions)
+
+	assert.True(t, filesystem.ExistsDir(dir+"/sub"))
+	assert.True(t, filesystem.ExistsFile(dir+"/sub/b.txt"))
+
+	_, err = filesystem.RemoveDirWithOptions(dir, &fs.RemoveDirOptions{Recurse: true, Force: true})
+	failError(t, err)
+	assert.False(t, filesystem.Exists(dir))
+}
+
+func testChangeACLDryRun(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "bob_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	hasBobAccess := func(path string) bool {
+		accesses, err := filesystem.ListACLs(path)
+		failError(t, err)
+		for _, access := range accesses {
+			if access.UserName == "bob" {
+				return true
+			}
+		}
+		return false
+	}
+
+	options := &fs.ChangeACLOptions{Recursive: true, DryRun: true}
+	actions, err := filesystem.ChangeACLWithOptions(dir, types.IRODSAccessLevelReadObject, "bob", "", options)
+	failError(t, err)
+	assert.Len(t, actions, 4)
+	for _, action := range actions {
+		assert.Equal(t, fs.FileSystemActionChangeACL, action.Type)
+		assert.Equal(t, "bob#mockzone:read_object", action.Detail)
+	}
+	assert.False(t, hasBobAccess(dir))
+	assert.False(t, hasBobAccess(dir+"/sub/b.txt"))
+
+	options.DryRun = false
+	_, err = filesystem.ChangeACLWithOptions(dir, types.IRODSAccessLevelReadObject, "bob", "", options)
+	failError(t, err)
+	assert.True(t, hasBobAccess(dir))
+	assert.True(t, hasBobAccess(dir+"/sub/b.txt"))
+}
+
+func testMetadataDryRun(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, dir+"/a.txt", &types.IRODSMeta{Name: "key", Value: "value"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	options := &fs.MetadataOptions{Recursive: true, DryRun: true}
+
+	// a.txt already has the metadata
+	actions, err := filesystem.AddMetadataWithOptions(dir, "key", "value", "", options)
+	failError(t, err)
+	assert.Len(t, actions, 3)
+	for _, action := range actions {
+		assert.Equal(t, fs.FileSystemActionAddMetadata, action.Type)
+		assert.NotEqual(t, dir+"/a.txt", action.Path)
+	}
+
+	metas, err := filesystem.ListMetadata(dir + "/sub/b.txt")
+	failError(t, err)
+	assert.Empty(t, metas)
+
+	actions, err = filesystem.DeleteMetadataByNameWithOptions(dir, "key", options)
+	failError(t, err)
+	assert.Equal(t, []*fs.FileSystemAction{
+		{Type: fs.FileSystemActionDeleteMetadata, Path: dir + "/a.txt", Detail: "key"},
+	}, actions)
+
+	metas, err = filesystem.ListMetadata(dir + "/a.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+
+	options.DryRun = false
+	_, err = filesystem.AddMetadataWithOptions(dir, "key", "value", "", options)
+	failError(t, err)
+
+	metas, err = filesystem.ListMetadata(dir + "/sub/b.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+}
+
+func testMirrorDirDryRun(t *testing.T) {
+	srcServer := startMockServer(t)
+	defer srcServer.Stop()
+
+	destServer := startMockServer(t)
+	defer destServer.Stop()
+
+	srcAccount, err := srcServer.GetAccount("alice")
+	failError(t, err)
+
+	destAccount, err := destServer.GetAccount("alice")
+	failError(t, err)
+
+	srcDir := "/mockzone/home/alice/data"
+	destDir := "/mockzone/home/alice/mirror"
+	makeDryRunTree(t, srcServer, srcDir)
+
+	err = destServer.MakeCollection(destDir, "alice")
+	failError(t, err)
+
+	err = srcServer.AddMetadata(types.IRODSDataObjectMetaItemType, srcDir+"/a.txt", &types.IRODSMeta{Name: "key", Value: "value"})
+	failError(t, err)
+
+	err = destServer.PutDataObject(destDir+"/old.txt", "alice", []byte("old"))
+	failError(t, err)
+
+	srcFS, err := fs.NewFileSystemWithDefault(srcAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer srcFS.Release()
+
+	destFS, err := fs.NewFileSystemWithDefault(destAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer destFS.Release()
+
+	options := &fs.MirrorDirOptions{
+		DestPath:       destDir,
+		MirrorMetadata: true,
+		Delete:         true,
+		DryRun:         true,
+	}
+
+	result, err := fs.MirrorDir(srcFS, destFS, srcDir, options)
+	failError(t, err)
+	assert.Equal(t, []string{destDir + "/sub"}, result.CreatedDirs)
+	assert.ElementsMatch(t, []string{destDir + "/a.txt", destDir + "/sub/b.txt"}, result.CopiedFiles)
+	assert.Equal(t, []string{destDir + "/a.txt"}, result.MetadataUpdated)
+	assert.Equal(t, []string{destDir + "/old.txt"}, result.Removed)
+
+	assert.False(t, destFS.Exists(destDir+"/sub"))
+	assert.False(t, destFS.Exists(destDir+"/a.txt"))
+	assert.True(t, destFS.Exists(destDir+"/old.txt"))
+
+	options.DryRun = false
+	actual, err := fs.MirrorDir(srcFS, destFS, srcDir, options)
+	failError(t, err)
+
+	// durations differ from dry-run
+	assert.Equal(t, result.Results.Actions(), actual.Results.Actions())
+	result.Results, actual.Results = nil, nil
+	assert.Equal(t, result, actual)
+
+	assert.True(t, destFS.Exists(destDir+"/sub/b.txt"))
+	assert.False(t, destFS.Exists(destDir+"/old.txt"))
+}
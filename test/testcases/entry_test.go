@@ -0,0 +1,233 @@
+package testcases
+
+import (
+	"encoding/json"
+	iofs "io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry(t *testing.T) {
+	t.Run("test EntryFileInfo", testEntryFileInfo)
+	t.Run("test EntryJSON", testEntryJSON)
+	t.Run("test TypesJSON", testTypesJSON)
+	t.Run("test EntryOwnerZoneAndReplicas", testEntryOwnerZoneAndReplicas)
+	t.Run("test EntryReplicaSummary", testEntryReplicaSummary)
+}
+
+func testEntryFileInfo(t *testing.T) {
+	modTime := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	fileEntry := &fs.Entry{
+		ID:         100,
+		Type:       fs.FileEntry,
+		Name:       "test.txt",
+		Path:       "/mockzone/home/alice/test.txt",
+		Owner:      "alice",
+		Size:       1024,
+		ModifyTime: modTime,
+	}
+
+	var fileInfo os.FileInfo = fileEntry.ToFileInfo()
+	assert.Equal(t, "test.txt", fileInfo.Name())
+	assert.Equal(t, int64(1024), fileInfo.Size())
+	assert.Equal(t, modTime, fileInfo.ModTime())
+	assert.False(t, fileInfo.IsDir())
+	assert.True(t, fileInfo.Mode().IsRegular())
+	assert.Equal(t, fileEntry, fileInfo.Sys())
+
+	dirEntry := &fs.Entry{
+		ID:   101,
+		Type: fs.DirectoryEntry,
+		Name: "dir",
+		Path: "/mockzone/home/alice/dir",
+		Size: 4096,
+	}
+
+	var dirFileInfo iofs.DirEntry = dirEntry.ToFileInfo()
+	assert.Equal(t, "dir", dirFileInfo.Name())
+	assert.True(t, dirFileInfo.IsDir())
+	assert.Equal(t, iofs.ModeDir, dirFileInfo.Type())
+
+	info, err := dirFileInfo.Info()
+	failError(t, err)
+	assert.True(t, info.Mode().IsDir())
+	assert.Equal(t, int64(0), info.Size())
+
+	dirEntries := fs.ToDirEntries([]*fs.Entry{fileEntry, dirEntry})
+	assert.Len(t, dirEntries, 2)
+	assert.Equal(t, "test.txt", dirEntries[0].Name())
+	assert.True(t, dirEntries[1].IsDir())
+}
+
+func testEntryJSON(t *testing.T) {
+	entry := &fs.Entry{
+		ID:                100,
+		Type:              fs.FileEntry,
+		Name:              "test.txt",
+		Path:              "/mockzone/home/alice/test.txt",
+		Owner:             "alice",
+		Size:              1024,
+		DataType:          "generic",
+		CreateTime:        time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+		ModifyTime:        time.Date(2022, 3, 5, 5, 6, 7, 0, time.UTC),
+		CheckSumAlgorithm: types.ChecksumAlgorithmMD5,
+		CheckSum:          []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	bytes, err := json.Marshal(entry)
+	failError(t, err)
+
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(bytes, &fields)
+	failError(t, err)
+	assert.Equal(t, "test.txt", fields["name"])
+	assert.Equal(t, "file", fields["type"])
+	assert.Equal(t, "2022-03-04T05:06:07Z", fields["create_time"])
+
+	decoded := fs.Entry{}
+	err = json.Unmarshal(bytes, &decoded)
+	failError(t, err)
+	assert.Equal(t, *entry, decoded)
+}
+
+func testTypesJSON(t *testing.T) {
+	meta := &types.IRODSMeta{
+		AVUID: 10,
+		Name:  "tier",
+		Value: "archive",
+		Units: "",
+	}
+
+	bytes, err := json.Marshal(meta)
+	failError(t, err)
+	assert.Contains(t, string(bytes), `"avu_id":10`)
+	assert.Contains(t, string(bytes), `"name":"tier"`)
+
+	access := &types.IRODSAccess{
+		Path:        "/mockzone/home/alice",
+		UserName:    "alice",
+		UserZone:    "mockzone",
+		UserType:    types.IRODSUserRodsUser,
+		AccessLevel: types.IRODSAccessLevelOwner,
+	}
+
+	bytes, err = json.Marshal(access)
+	failError(t, err)
+	assert.Contains(t, string(bytes), `"user_name":"alice"`)
+	assert.Contains(t, string(bytes), `"access_level":"own"`)
+
+	user := &types.IRODSUser{
+		ID:   5,
+		Name: "alice",
+		Zone: "mockzone",
+		Type: types.IRODSUserRodsUser,
+	}
+
+	bytes, err = json.Marshal(user)
+	failError(t, err)
+	assert.Contains(t, string(bytes), `"type":"rodsuser"`)
+	assert.NotContains(t, string(bytes), `"info"`)
+
+	decoded := types.IRODSUser{}
+	err = json.Unmarshal(bytes, &decoded)
+	failError(t, err)
+	assert.Equal(t, *user, decoded)
+}
+
+func testEntryOwnerZoneAndReplicas(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	err = filesystem.MakeDir(homedir+"/dir1", false)
+	failError(t, err)
+
+	handle, err := filesystem.CreateFile(homedir+"/dir1/file1.txt", "", "w")
+	failError(t, err)
+	_, err = handle.Write([]byte("hello"))
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.SetAccessInherit(conn, homedir+"/dir1", true, false, false)
+	filesystem.ReturnMetadataConnection(conn)
+	failError(t, err)
+
+	filesystem.ClearCache()
+
+	dirEntry, err := filesystem.Stat(homedir + "/dir1")
+	failError(t, err)
+	assert.Equal(t, "alice", dirEntry.Owner)
+	assert.Equal(t, "mockzone", dirEntry.OwnerZone)
+	assert.True(t, dirEntry.Inheritance)
+
+	fileEntry, err := filesystem.Stat(homedir + "/dir1/file1.txt")
+	failError(t, err)
+	assert.Equal(t, "mockzone", fileEntry.OwnerZone)
+	assert.Equal(t, 1, fileEntry.ReplicaCount)
+	assert.False(t, fileEntry.HasStaleReplica)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "mockzone", entries[0].OwnerZone)
+	assert.True(t, entries[0].Inheritance)
+}
+
+func testEntryReplicaSummary(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("diskResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	filePath := homedir + "/file.txt"
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello"))
+	failError(t, err)
+
+	err = filesystem.ReplicateFileToResources(filePath, []string{"diskResc"}, false)
+	failError(t, err)
+
+	// a good and a stale replica, entries are made from the good one
+	err = mockServer.SetReplicaStale(filePath, mock.MockResourceName, 3)
+	failError(t, err)
+
+	entry, err := filesystem.Stat(filePath)
+	failError(t, err)
+	assert.Equal(t, 2, entry.ReplicaCount)
+	assert.True(t, entry.HasStaleReplica)
+	assert.False(t, entry.OnlyStaleReplicas)
+	assert.Equal(t, int64(5), entry.Size)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, 2, entries[0].ReplicaCount)
+		assert.True(t, entries[0].HasStaleReplica)
+	}
+}
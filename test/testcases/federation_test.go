@@ -0,0 +1,207 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/client"
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFederation(t *testing.T) {
+	t.Run("test FederatedListing", testFederatedListing)
+	t.Run("test QueryWithZone", testQueryWithZone)
+	t.Run("test UserIdentity", testUserIdentity)
+	t.Run("test ProxyAccountZones", testProxyAccountZones)
+	t.Run("test ProxyAccessAcrossZones", testProxyAccessAcrossZones)
+}
+
+func testFederatedListing(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddRemoteZone("remotezone")
+	failError(t, err)
+
+	remoteDir := "/remotezone/home/bob"
+	err = mockServer.MakeCollection(remoteDir+"/dir1", "rods")
+	failError(t, err)
+	err = mockServer.PutDataObject(remoteDir+"/file1.txt", "rods", []byte("hello world"))
+	failError(t, err)
+
+	// not federated, queries for the zone are rejected
+	err = mockServer.MakeCollection("/unknownzone/home/bob", "rods")
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	entries, err := filesystem.List(remoteDir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	assert.ElementsMatch(t, []string{"dir1", "file1.txt"}, names)
+
+	entry, err := filesystem.Stat(remoteDir + "/file1.txt")
+	failError(t, err)
+	assert.Equal(t, int64(11), entry.Size)
+
+	_, err = filesystem.List("/unknownzone/home/bob")
+	assert.Error(t, err)
+	assert.Equal(t, common.SYS_INVALID_ZONE_NAME, types.GetIRODSErrorCode(err))
+
+	// local zone
+	_, err = filesystem.List("/mockzone/home/alice")
+	failError(t, err)
+}
+
+func testQueryWithZone(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddRemoteZone("remotezone")
+	failError(t, err)
+
+	err = mockServer.MakeCollection("/remotezone/home/bob", "rods")
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsClient, err := client.NewClientWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer irodsClient.Release()
+
+	selects := []common.ICATColumnNumber{common.ICAT_COLUMN_COLL_NAME}
+	conditions := map[common.ICATColumnNumber]string{
+		common.ICAT_COLUMN_COLL_NAME: "= '/remotezone/home/bob'",
+	}
+
+	rows, err := irodsClient.Query().GenQueryWithZone("remotezone", selects, conditions)
+	failError(t, err)
+	assert.Equal(t, [][]string{{"/remotezone/home/bob"}}, rows)
+
+	_, err = irodsClient.Query().GenQueryWithZone("unknownzone", selects, conditions)
+	assert.Equal(t, common.SYS_INVALID_ZONE_NAME, types.GetIRODSErrorCode(err))
+
+	_, err = irodsClient.Query().SpecificQueryWithZone("unknownzone", "ShowCollAcls", "/remotezone/home/bob")
+	assert.Equal(t, common.SYS_INVALID_ZONE_NAME, types.GetIRODSErrorCode(err))
+
+	// the zone of the server connected
+	rows, err = irodsClient.Query().GenQueryWithZone("", selects, conditions)
+	failError(t, err)
+	assert.Len(t, rows, 1)
+}
+
+func testUserIdentity(t *testing.T) {
+	identity, err := types.ParseIRODSUserIdentity("bob#remotezone", "mockzone")
+	failError(t, err)
+	assert.Equal(t, types.NewIRODSUserIdentity("bob", "remotezone"), identity)
+	assert.Equal(t, "bob#remotezone", identity.String())
+
+	// the default zone is used if zone is not given
+	identity, err = types.ParseIRODSUserIdentity("bob", "mockzone")
+	failError(t, err)
+	assert.Equal(t, "bob#mockzone", identity.String())
+
+	identity, err = types.ParseIRODSUserIdentity("bob#", "mockzone")
+	failError(t, err)
+	assert.Equal(t, "mockzone", identity.Zone)
+
+	assert.Equal(t, "bob", types.NewIRODSUserIdentity("bob", "").String())
+	assert.False(t, identity.Equals(types.NewIRODSUserIdentity("bob", "remotezone")))
+
+	_, err = types.ParseIRODSUserIdentity("#remotezone", "mockzone")
+	assert.Error(t, err)
+
+	_, err = types.ParseIRODSUserIdentity("bob#remotezone#other", "mockzone")
+	assert.Error(t, err)
+}
+
+func testProxyAccountZones(t *testing.T) {
+	account, err := types.CreateIRODSProxyAccount("localhost", 1247, "rods", "remotezone", "rods", "mockzone", types.AuthSchemeNative, "rods_password", "")
+	failError(t, err)
+
+	// the same name in another zone is another user
+	assert.True(t, account.UseProxyAccess())
+	assert.Equal(t, "rods#mockzone", account.GetProxyIdentity().String())
+	assert.Equal(t, "rods#remotezone", account.GetClientIdentity().String())
+
+	account, err = types.CreateIRODSProxyAccount("localhost", 1247, "rods", "mockzone", "rods", "mockzone", types.AuthSchemeNative, "rods_password", "")
+	failError(t, err)
+	assert.False(t, account.UseProxyAccess())
+}
+
+func testProxyAccessAcrossZones(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddRemoteZone("remotezone")
+	failError(t, err)
+
+	// same name as the local user
+	err = mockServer.AddRemoteUser("alice", "remotezone", "", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	account, err := mockServer.GetProxyAccount("rods", "alice#remotezone")
+	failError(t, err)
+	assert.True(t, account.UseProxyAccess())
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/remotezone/home/alice"
+
+	err = filesystem.MakeDir(homedir+"/dir1", false)
+	failError(t, err)
+
+	handle, err := filesystem.CreateFile(homedir+"/dir1/file1.txt", "", "w")
+	failError(t, err)
+	_, err = handle.Write([]byte("hello world"))
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	// items created are owned by the client user, not the proxy user or the local user of the same name
+	for _, p := range []string{homedir + "/dir1", homedir + "/dir1/file1.txt"} {
+		entry, err := filesystem.Stat(p)
+		failError(t, err)
+		assert.Equal(t, "alice", entry.Owner)
+		assert.Equal(t, "remotezone", entry.OwnerZone)
+	}
+
+	err = filesystem.ChangeACL(homedir+"/dir1/file1.txt", types.IRODSAccessLevelReadObject, "alice", "mockzone")
+	failError(t, err)
+
+	accesses, err := filesystem.ListACLs(homedir + "/dir1/file1.txt")
+	failError(t, err)
+
+	identities := map[string]types.IRODSAccessLevelType{}
+	for _, access := range accesses {
+		identities[access.GetUserIdentity().String()] = access.AccessLevel
+	}
+	assert.Equal(t, map[string]types.IRODSAccessLevelType{
+		"alice#remotezone": types.IRODSAccessLevelOwner,
+		"alice#mockzone":   types.IRODSAccessLevelReadObject,
+	}, identities)
+
+	// users without zones are in the client zone
+	accessLevel, err := filesystem.Access(homedir+"/dir1/file1.txt", "alice")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevel)
+
+	accessLevel, err = filesystem.Access(homedir+"/dir1/file1.txt", "alice#mockzone")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelReadObject, accessLevel)
+}
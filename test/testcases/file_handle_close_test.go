@@ -0,0 +1,74 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileHandleClose(t *testing.T) {
+	t.Run("test CloseWithResultAfterWrite", testCloseWithResultAfterWrite)
+	t.Run("test CloseWithResultChecksum", testCloseWithResultChecksum)
+}
+
+func testCloseWithResultAfterWrite(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+
+	handle, err := filesystem.CreateFile(irodsPath, "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte("hello world"))
+	failError(t, err)
+
+	result, err := handle.CloseWithResult()
+	failError(t, err)
+	assert.Equal(t, irodsPath, result.Path)
+	assert.Equal(t, int64(11), result.Size)
+	assert.Equal(t, int64(0), result.ReplicaNumber)
+	assert.Nil(t, result.Checksum)
+
+	entry, err := filesystem.Stat(irodsPath)
+	failError(t, err)
+	assert.Equal(t, int64(11), entry.Size)
+}
+
+func testCloseWithResultChecksum(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	err = mockServer.SetDataObjectChecksum(irodsPath, "sha2:uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	handle, err := filesystem.OpenFile(irodsPath, "", "r")
+	failError(t, err)
+
+	result, err := handle.CloseWithResult()
+	failError(t, err)
+	assert.Equal(t, int64(11), result.Size)
+	if assert.NotNil(t, result.Checksum) {
+		assert.Equal(t, types.ChecksumAlgorithmSHA256, result.Checksum.Algorithm)
+	}
+}
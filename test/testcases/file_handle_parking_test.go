@@ -0,0 +1,80 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileHandleParking(t *testing.T) {
+	t.Run("test ParkIdleReadHandle", testParkIdleReadHandle)
+	t.Run("test ParkIdleWriteHandle", testParkIdleWriteHandle)
+}
+
+func testParkIdleReadHandle(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithFileHandleIdleTimeout(100*time.Millisecond))
+	failError(t, err)
+	defer filesystem.Release()
+
+	handle, err := filesystem.OpenFile(irodsPath, "", "r")
+	failError(t, err)
+	defer handle.Close()
+
+	buffer := make([]byte, 6)
+	_, err = handle.Read(buffer)
+	failError(t, err)
+	assert.False(t, handle.IsParked())
+
+	time.Sleep(300 * time.Millisecond)
+	assert.True(t, handle.IsParked())
+	assert.Equal(t, int64(6), handle.GetOffset())
+
+	readLen, _ := handle.Read(buffer)
+	assert.Equal(t, "world", string(buffer[:readLen]))
+	assert.False(t, handle.IsParked())
+}
+
+func testParkIdleWriteHandle(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithFileHandleIdleTimeout(100*time.Millisecond))
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+
+	handle, err := filesystem.CreateFile(irodsPath, "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte("hello "))
+	failError(t, err)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.True(t, handle.IsParked())
+
+	_, err = handle.Write([]byte("world"))
+	failError(t, err)
+
+	err = handle.Close()
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(irodsPath)
+	failError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
@@ -0,0 +1,127 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileHandleReopen(t *testing.T) {
+	t.Run("test FileDescriptorInfo", testFileDescriptorInfo)
+	t.Run("test ReopenFileHandle", testReopenFileHandle)
+	t.Run("test ReopenFileHandleTransparently", testReopenFileHandleTransparently)
+}
+
+func testFileDescriptorInfo(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+
+	handle, err := filesystem.CreateFile(irodsPath, "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte("hello"))
+	failError(t, err)
+
+	info, err := handle.GetFileDescriptorInfo()
+	failError(t, err)
+	assert.Equal(t, irodsPath, info.Path)
+	assert.Equal(t, int64(5), info.BytesWritten)
+	assert.NotEmpty(t, info.ReplicaToken)
+
+	err = handle.Close()
+	failError(t, err)
+}
+
+func testReopenFileHandle(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	handle, err := filesystem.OpenFile(irodsPath, "", "r")
+	failError(t, err)
+	defer handle.Close()
+
+	buffer := make([]byte, 6)
+	_, err = handle.Read(buffer)
+	failError(t, err)
+
+	// server restarts
+	mockServer.DropConnections()
+
+	_, err = handle.Read(buffer)
+	assert.Error(t, err)
+
+	err = handle.Reopen()
+	failError(t, err)
+	assert.Equal(t, int64(6), handle.GetOffset())
+
+	readLen, _ := handle.Read(buffer)
+	assert.Equal(t, "world", string(buffer[:readLen]))
+}
+
+func testReopenFileHandleTransparently(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithReopenFileHandles(true))
+	failError(t, err)
+	defer filesystem.Release()
+
+	readHandle, err := filesystem.OpenFile(irodsPath, "", "r")
+	failError(t, err)
+	defer readHandle.Close()
+
+	buffer := make([]byte, 6)
+	_, err = readHandle.Read(buffer)
+	failError(t, err)
+
+	mockServer.DropConnections()
+
+	readLen, _ := readHandle.ReadAt(buffer, 6)
+	assert.Equal(t, "world", string(buffer[:readLen]))
+
+	writeHandle, err := filesystem.OpenFile(irodsPath, "", "r+")
+	failError(t, err)
+
+	_, err = writeHandle.Write([]byte("HELLO"))
+	failError(t, err)
+
+	mockServer.DropConnections()
+
+	_, err = writeHandle.WriteAt([]byte("WORLD"), 6)
+	failError(t, err)
+
+	err = writeHandle.Close()
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(irodsPath)
+	failError(t, err)
+	assert.Equal(t, "HELLO WORLD", string(data))
+}
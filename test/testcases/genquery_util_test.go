@@ -0,0 +1,71 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenQueryUtil(t *testing.T) {
+	t.Run("test MakeGenQueryConditions", testMakeGenQueryConditions)
+	t.Run("test QueryPathsWithQuotes", testQueryPathsWithQuotes)
+}
+
+func testMakeGenQueryConditions(t *testing.T) {
+	assert.Equal(t, "it''s", util.EscapeGenQueryValue("it's"))
+	assert.Equal(t, "'it''s'", util.QuoteGenQueryValue("it's"))
+
+	assert.Equal(t, "= '/zone/home/o''brien'", util.MakeGenQueryEqualCondition("/zone/home/o'brien"))
+	assert.Equal(t, "<> 'rodsgroup'", util.MakeGenQueryCondition("<>", "rodsgroup"))
+	assert.Equal(t, "like 'lab%'", util.MakeGenQueryLikeCondition("lab%"))
+
+	// wildcards in literal parts are escaped
+	assert.Equal(t, `100\%\_done\\`, util.EscapeGenQueryLikePattern(`100%_done\`))
+	assert.Equal(t, `like '/zone/home/a\_b/%'`, util.MakeGenQueryPrefixCondition("/zone/home/a_b/"))
+
+	assert.Equal(t, "in ('a', 'b''c')", util.MakeGenQueryInCondition([]string{"a", "b'c"}))
+	assert.Equal(t, "in ()", util.MakeGenQueryInCondition([]string{}))
+}
+
+func testQueryPathsWithQuotes(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	quotedDir := homedir + "/o'brien's"
+
+	err = mockServer.MakeCollection(quotedDir, "alice")
+	failError(t, err)
+
+	err = mockServer.PutDataObject(quotedDir+"/it's.txt", "alice", []byte("content"))
+	failError(t, err)
+
+	err = mockServer.PutDataObject(homedir+"/plain.txt", "alice", []byte("content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	entry, err := filesystem.Stat(quotedDir)
+	failError(t, err)
+	assert.Equal(t, fs.DirectoryEntry, entry.Type)
+
+	entries, err := filesystem.List(quotedDir)
+	failError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, quotedDir+"/it's.txt", entries[0].Path)
+	}
+
+	exists, err := filesystem.ExistsBatch([]string{quotedDir, quotedDir + "/it's.txt", homedir + "/plain.txt", homedir + "/missing's"})
+	failError(t, err)
+	assert.True(t, exists[quotedDir])
+	assert.True(t, exists[quotedDir+"/it's.txt"])
+	assert.True(t, exists[homedir+"/plain.txt"])
+	assert.False(t, exists[homedir+"/missing's"])
+}
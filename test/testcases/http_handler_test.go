@@ -0,0 +1,138 @@
+package testcases
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	t.Run("test ServeDataObject", testHTTPHandlerServeDataObject)
+	t.Run("test Errors", testHTTPHandlerErrors)
+}
+
+func testHTTPHandlerServeDataObject(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	content := []byte("hello iRODS over HTTP")
+	digest := sha256.Sum256(content)
+
+	err = mockServer.PutDataObject(homedir+"/hello.txt", "alice", content)
+	failError(t, err)
+	err = mockServer.SetDataObjectChecksum(homedir+"/hello.txt", "sha2:"+base64.StdEncoding.EncodeToString(digest[:]))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	server := httptest.NewServer(fs.NewHTTPHandler(filesystem, homedir))
+	defer server.Close()
+
+	etag := "\"" + hex.EncodeToString(digest[:]) + "\""
+
+	// GET
+	resp, err := http.Get(server.URL + "/hello.txt")
+	failError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	failError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, content, body)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+	assert.Equal(t, etag, resp.Header.Get("ETag"))
+	assert.NotEmpty(t, resp.Header.Get("Last-Modified"))
+
+	// Range
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/hello.txt", nil)
+	failError(t, err)
+	req.Header.Set("Range", "bytes=6-10")
+
+	resp, err = http.DefaultClient.Do(req)
+	failError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	failError(t, err)
+
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, content[6:11], body)
+	assert.Equal(t, "bytes 6-10/21", resp.Header.Get("Content-Range"))
+
+	// HEAD
+	resp, err = http.Head(server.URL + "/hello.txt")
+	failError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(len(content)), resp.ContentLength)
+
+	// If-None-Match
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/hello.txt", nil)
+	failError(t, err)
+	req.Header.Set("If-None-Match", etag)
+
+	resp, err = http.DefaultClient.Do(req)
+	failError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func testHTTPHandlerErrors(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	err = mockServer.PutDataObject(homedir+"/hello.txt", "alice", []byte("hello"))
+	failError(t, err)
+	err = mockServer.MakeCollection(homedir+"/dir", "alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	server := httptest.NewServer(fs.NewHTTPHandler(filesystem, homedir))
+	defer server.Close()
+
+	// no checksum, no etag
+	resp, err := http.Get(server.URL + "/hello.txt")
+	failError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("ETag"))
+
+	resp, err = http.Get(server.URL + "/missing.txt")
+	failError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/dir")
+	failError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/hello.txt", "text/plain", strings.NewReader("data"))
+	failError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET, HEAD", resp.Header.Get("Allow"))
+}
@@ -0,0 +1,104 @@
+package testcases
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyVal(t *testing.T) {
+	t.Run("test KeyVals", testKeyVals)
+	t.Run("test KeyValsToSSKeyVal", testKeyValsToSSKeyVal)
+	t.Run("test KeyValsAsConnectionKeywords", testKeyValsAsConnectionKeywords)
+}
+
+func testKeyVals(t *testing.T) {
+	keyVals := types.NewIRODSKeyVals(
+		types.KeyValForce(),
+		types.KeyValDestResource("resc1"),
+		types.KeyValReplicaNumber(2),
+	)
+	assert.Equal(t, 3, keyVals.Len())
+
+	value, ok := keyVals.Get(common.FORCE_FLAG_KW)
+	assert.True(t, ok)
+	assert.Empty(t, value)
+
+	value, ok = keyVals.Get(common.REPL_NUM_KW)
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+
+	// later values override, the order is kept
+	keyVals.Add(types.KeyValDestResource("resc2"))
+	assert.Equal(t, 3, keyVals.Len())
+	assert.Equal(t, types.KeyValDestResource("resc2"), keyVals.GetKeyVals()[1])
+
+	keyVals.Remove(common.FORCE_FLAG_KW)
+	assert.False(t, keyVals.Has(common.FORCE_FLAG_KW))
+	assert.Equal(t, map[common.KeyWord]string{
+		common.DEST_RESC_NAME_KW: "resc2",
+		common.REPL_NUM_KW:       "2",
+	}, keyVals.ToMap())
+
+	// map keys are sorted
+	fromMap := types.NewIRODSKeyValsFromMap(keyVals.ToMap())
+	assert.Equal(t, []types.IRODSKeyVal{
+		types.KeyValDestResource("resc2"),
+		types.KeyValReplicaNumber(2),
+	}, fromMap.GetKeyVals())
+}
+
+func testKeyValsToSSKeyVal(t *testing.T) {
+	keyVals := types.NewIRODSKeyVals(
+		types.KeyValForce(),
+		types.KeyValDataType(types.GENERIC_DT),
+		types.KeyValTicket("ticket1"),
+	)
+
+	ssKeyVal := message.NewIRODSMessageSSKeyValFromKeyVals(keyVals)
+	assert.Equal(t, 3, ssKeyVal.Length)
+	assert.Equal(t, []string{string(common.FORCE_FLAG_KW), string(common.DATA_TYPE_KW), string(common.TICKET_KW)}, ssKeyVal.Keys)
+
+	xmlBytes, err := xml.Marshal(ssKeyVal)
+	failError(t, err)
+
+	decoded := message.IRODSMessageSSKeyVal{}
+	err = xml.Unmarshal(xmlBytes, &decoded)
+	failError(t, err)
+
+	assert.Equal(t, keyVals.GetKeyVals(), decoded.GetKeyVals().GetKeyVals())
+}
+
+func testKeyValsAsConnectionKeywords(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("otherResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	keyVals := types.NewIRODSKeyVals(types.KeyValDestResource("otherResc"))
+
+	_, err = filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", &fs.RenameFileOptions{
+		Keywords: keyVals.ToMap(),
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsRenameNotSupportedError(err))
+	assert.True(t, filesystem.ExistsFile(srcPath))
+}
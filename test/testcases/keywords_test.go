@@ -0,0 +1,193 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeywords(t *testing.T) {
+	t.Run("test ConnectionKeywords", testConnectionKeywords)
+	t.Run("test SessionKeywordsNotShared", testSessionKeywordsNotShared)
+	t.Run("test RenameWithKeywords", testRenameWithKeywords)
+	t.Run("test CopyWithKeywords", testCopyWithKeywords)
+}
+
+func testConnectionKeywords(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("otherResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	conn, err := filesystem.GetMetadataConnectionWithKeywords(map[common.KeyWord]string{
+		common.DEST_RESC_NAME_KW: "otherResc",
+	})
+	failError(t, err)
+
+	// the file is not in the resource
+	err = irods_fs.MoveDataObject(conn, srcPath, homedir+"/renamed.txt")
+	assert.Error(t, err)
+	assert.True(t, types.IsRenameNotSupportedError(err))
+
+	// keywords given by the request come first
+	err = irods_fs.MoveDataObjectToResource(conn, srcPath, homedir+"/renamed.txt", "no_such_resc", false)
+	assert.Error(t, err)
+	assert.False(t, types.IsRenameNotSupportedError(err))
+
+	filesystem.ReturnMetadataConnection(conn)
+	assert.Empty(t, conn.GetKeywords())
+
+	conn, err = filesystem.GetMetadataConnection()
+	failError(t, err)
+	defer filesystem.ReturnMetadataConnection(conn)
+
+	err = irods_fs.MoveDataObject(conn, srcPath, homedir+"/renamed.txt")
+	failError(t, err)
+}
+
+func testSessionKeywordsNotShared(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sessionConfig := session.NewIRODSSessionConfigWithOptions("go-irodsclient-test", session.WithConnectionMax(session.IRODSSessionConnectionMaxMin))
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	keywords := map[common.KeyWord]string{
+		common.FORCE_FLAG_KW: "",
+	}
+
+	connections := []*connection.IRODSConnection{}
+	for i := 0; i < session.IRODSSessionConnectionMaxMin-1; i++ {
+		conn, err := sess.AcquireConnection()
+		failError(t, err)
+		connections = append(connections, conn)
+	}
+
+	keywordConn, err := sess.AcquireConnectionWithKeywords(keywords)
+	failError(t, err)
+	assert.Equal(t, keywords, keywordConn.GetKeywords())
+
+	// no idle connection left
+	_, err = sess.AcquireConnectionWithKeywords(keywords)
+	assert.Error(t, err)
+
+	// shared connections are not the one with keywords
+	for i := 0; i < session.IRODSSessionConnectionMaxMin*2; i++ {
+		conn, err := sess.AcquireConnection()
+		failError(t, err)
+		assert.NotSame(t, keywordConn, conn)
+		assert.Empty(t, conn.GetKeywords())
+		connections = append(connections, conn)
+	}
+
+	multiConns, err := sess.AcquireConnectionsMulti(2)
+	failError(t, err)
+	for _, conn := range multiConns {
+		assert.NotSame(t, keywordConn, conn)
+	}
+	connections = append(connections, multiConns...)
+
+	err = sess.ReturnConnection(keywordConn)
+	failError(t, err)
+	assert.Empty(t, keywordConn.GetKeywords())
+
+	for _, conn := range connections {
+		err = sess.ReturnConnection(conn)
+		failError(t, err)
+	}
+}
+
+func testRenameWithKeywords(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("otherResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	_, err = filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", &fs.RenameFileOptions{
+		Keywords: map[common.KeyWord]string{
+			common.DEST_RESC_NAME_KW: "otherResc",
+		},
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsRenameNotSupportedError(err))
+	assert.True(t, filesystem.ExistsFile(srcPath))
+
+	// keywords are not kept by the connection
+	newPath, err := filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", nil)
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(newPath))
+}
+
+func testCopyWithKeywords(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+	destPath := homedir + "/copy.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, destPath, &fs.CopyFileOptions{
+		Keywords: map[common.KeyWord]string{
+			common.DEST_RESC_NAME_KW: "no_such_resc",
+		},
+	})
+	assert.Error(t, err)
+	assert.False(t, filesystem.ExistsFile(destPath))
+
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, destPath, &fs.CopyFileOptions{
+		Keywords: map[common.KeyWord]string{
+			common.DEST_RESC_NAME_KW: mock.MockResourceName,
+		},
+	})
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(destPath))
+}
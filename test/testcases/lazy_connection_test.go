@@ -0,0 +1,61 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyConnection(t *testing.T) {
+	t.Run("test LazyConnectionServerDown", testLazyConnectionServerDown)
+	t.Run("test LazyConnectionInitNumber", testLazyConnectionInitNumber)
+}
+
+func testLazyConnectionServerDown(t *testing.T) {
+	mockServer := startMockServer(t)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	// nothing listens on the port
+	mockServer.Stop()
+
+	_, err = fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithConnectionInitNumber(1))
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionRefusedError(err))
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithConnectionInitNumber(1), fs.WithLazyConnection(true))
+	failError(t, err)
+	defer filesystem.Release()
+
+	// the error is returned by the first operation
+	_, err = filesystem.Stat("/mockzone/home/alice")
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionRefusedError(err))
+}
+
+func testLazyConnectionInitNumber(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test", session.WithConnectionInitNumber(2), session.WithLazyConnection(true))
+	failError(t, err)
+	defer sess.Release()
+
+	assert.Equal(t, 0, sess.ConnectionTotal())
+
+	// initial connections are created at the first acquire
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+	assert.Equal(t, 2, sess.ConnectionTotal())
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+	assert.Equal(t, 2, sess.ConnectionTotal())
+}
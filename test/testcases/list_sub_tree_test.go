@@ -0,0 +1,152 @@
+package testcases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSubTree(t *testing.T) {
+	t.Run("test ListSubTree", testListSubTree)
+	t.Run("test ListSubTreeParallel", testListSubTreeParallel)
+}
+
+func testListSubTree(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	rootDir := homedir + "/tree_1"
+
+	dirs := []string{rootDir, rootDir + "/a", rootDir + "/a/b", rootDir + "/a/b/c", rootDir + "/d"}
+	for _, dir := range dirs {
+		err = mockServer.MakeCollection(dir, "alice")
+		failError(t, err)
+
+		for i := 0; i < 2; i++ {
+			err = mockServer.PutDataObject(fmt.Sprintf("%s/file%d.txt", dir, i), "alice", []byte("hello world"))
+			failError(t, err)
+		}
+	}
+
+	// matches "tree_1/%" as "_" is a wildcard
+	err = mockServer.MakeCollection(homedir+"/treeX1", "alice")
+	failError(t, err)
+	err = mockServer.PutDataObject(homedir+"/treeX1/file.txt", "alice", []byte("hello world"))
+	failError(t, err)
+
+	listCount := filesystem.GetMetrics().GetCounterForList()
+
+	entries, err := filesystem.ListSubTree(rootDir)
+	failError(t, err)
+
+	// a list of collections and a list of data objects for the whole tree
+	assert.Equal(t, uint64(2), filesystem.GetMetrics().GetCounterForList()-listCount)
+
+	entryPaths := []string{}
+	for _, entry := range entries {
+		entryPaths = append(entryPaths, entry.Path)
+	}
+
+	expectedPaths := append([]string{}, dirs[1:]...)
+	for _, dir := range dirs {
+		expectedPaths = append(expectedPaths, dir+"/file0.txt", dir+"/file1.txt")
+	}
+	assert.ElementsMatch(t, expectedPaths, entryPaths)
+
+	// dirs first, parents before children
+	for idx, entry := range entries[:len(dirs)-1] {
+		assert.Equal(t, fs.DirectoryEntry, entry.Type)
+		assert.Equal(t, dirs[idx+1], entry.Path)
+	}
+
+	for _, entry := range entries[len(dirs)-1:] {
+		assert.Equal(t, fs.FileEntry, entry.Type)
+		assert.Equal(t, int64(11), entry.Size)
+	}
+
+	// listing dirs in the tree is served from cache
+	listCount = filesystem.GetMetrics().GetCounterForList()
+
+	subEntries, err := filesystem.List(rootDir + "/a/b")
+	failError(t, err)
+	assert.Len(t, subEntries, 3)
+
+	subEntries, err = filesystem.List(rootDir + "/a/b/c")
+	failError(t, err)
+	assert.Len(t, subEntries, 2)
+
+	assert.Equal(t, uint64(0), filesystem.GetMetrics().GetCounterForList()-listCount)
+
+	// not a dir
+	_, err = filesystem.ListSubTree(rootDir + "/file0.txt")
+	assert.Error(t, err)
+}
+
+func testListSubTreeParallel(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	rootDir := homedir + "/tree"
+
+	dirs := []string{rootDir}
+	for i := 0; i < 4; i++ {
+		dirs = append(dirs, fmt.Sprintf("%s/dir%d", rootDir, i))
+		for j := 0; j < 3; j++ {
+			dirs = append(dirs, fmt.Sprintf("%s/dir%d/sub%d", rootDir, i, j))
+		}
+	}
+
+	expectedPaths := append([]string{}, dirs[1:]...)
+	for _, dir := range dirs {
+		err = mockServer.MakeCollection(dir, "alice")
+		failError(t, err)
+
+		err = mockServer.PutDataObject(dir+"/file.txt", "alice", []byte("hello world"))
+		failError(t, err)
+		expectedPaths = append(expectedPaths, dir+"/file.txt")
+	}
+
+	listCount := filesystem.GetMetrics().GetCounterForList()
+
+	entries, err := filesystem.ListSubTreeWithOptions(rootDir, &fs.ListSubTreeOptions{Concurrency: 4})
+	failError(t, err)
+
+	// collections and data objects of each dir
+	assert.Equal(t, uint64(len(dirs)*2), filesystem.GetMetrics().GetCounterForList()-listCount)
+
+	entryPaths := []string{}
+	for _, entry := range entries {
+		entryPaths = append(entryPaths, entry.Path)
+	}
+	assert.ElementsMatch(t, expectedPaths, entryPaths)
+
+	// same order as listing the tree with a few queries
+	sequentialEntries, err := filesystem.ListSubTree(rootDir)
+	failError(t, err)
+	assert.Len(t, entries, len(sequentialEntries))
+	for idx, entry := range sequentialEntries {
+		assert.Equal(t, entry.Path, entries[idx].Path)
+		assert.Equal(t, entry.Type, entries[idx].Type)
+	}
+
+	_, err = filesystem.ListSubTreeWithOptions(homedir+"/no_such_dir", &fs.ListSubTreeOptions{Concurrency: 4})
+	assert.Error(t, err)
+}
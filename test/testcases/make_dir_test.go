@@ -0,0 +1,60 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeDir(t *testing.T) {
+	t.Run("test MakeDirAll", testMakeDirAll)
+}
+
+func testMakeDirAll(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// create with intermediate dirs
+	entry, created, err := filesystem.MakeDirAll(homedir + "/a/b/c")
+	failError(t, err)
+	assert.Equal(t, fs.DirectoryEntry, entry.Type)
+	assert.Equal(t, homedir+"/a/b/c", entry.Path)
+	assert.Greater(t, entry.ID, int64(0))
+	assert.Equal(t, []string{homedir + "/a", homedir + "/a/b", homedir + "/a/b/c"}, created)
+
+	assert.True(t, filesystem.ExistsDir(homedir+"/a/b"))
+
+	entries, err := filesystem.List(homedir + "/a")
+	failError(t, err)
+	assert.Len(t, entries, 1)
+
+	// existing dir is tolerated
+	existing, created, err := filesystem.MakeDirAll(homedir + "/a/b/c")
+	failError(t, err)
+	assert.Equal(t, entry.ID, existing.ID)
+	assert.Empty(t, created)
+
+	// only missing dirs are reported
+	_, created, err = filesystem.MakeDirAll(homedir + "/a/d")
+	failError(t, err)
+	assert.Equal(t, []string{homedir + "/a/d"}, created)
+
+	// a file in the path
+	err = mockServer.PutDataObject(homedir+"/file", "alice", []byte("content"))
+	failError(t, err)
+
+	_, _, err = filesystem.MakeDirAll(homedir + "/file/sub")
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+}
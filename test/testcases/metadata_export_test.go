@@ -0,0 +1,164 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+func TestMetadataExport(t *testing.T) {
+	t.Run("test WalkMetadata", testWalkMetadata)
+	t.Run("test ExportMetadata", testExportMetadata)
+}
+
+func testWalkMetadata(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	rootDir := homedir + "/meta_tree"
+
+	for _, dir := range []string{rootDir + "/sub", homedir + "/metaXtree"} {
+		err = mockServer.MakeCollection(dir, "alice")
+		failError(t, err)
+	}
+
+	files := []string{rootDir + "/file.txt", rootDir + "/sub/file.txt", homedir + "/metaXtree/file.txt"}
+	for _, file := range files {
+		err = mockServer.PutDataObject(file, "alice", []byte("hello world"))
+		failError(t, err)
+	}
+
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, rootDir, &types.IRODSMeta{Name: "project", Value: "alpha"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, rootDir+"/sub", &types.IRODSMeta{Name: "stage", Value: "raw"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, homedir+"/metaXtree", &types.IRODSMeta{Name: "project", Value: "other"})
+	failError(t, err)
+
+	// more AVUs than a page of a query
+	for i := 0; i < common.MaxQueryRows+10; i++ {
+		err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, files[0], &types.IRODSMeta{Name: "index", Value: fmt.Sprintf("%d", i), Units: "n"})
+		failError(t, err)
+	}
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, files[1], &types.IRODSMeta{Name: "size", Value: "11", Units: "bytes"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, files[2], &types.IRODSMeta{Name: "size", Value: "11", Units: "bytes"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	records := []*fs.MetadataRecord{}
+	err = filesystem.WalkMetadata(rootDir, func(record *fs.MetadataRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	failError(t, err)
+	assert.Len(t, records, common.MaxQueryRows+10+3)
+
+	// dirs first
+	assert.Equal(t, &fs.MetadataRecord{Path: rootDir, Type: fs.DirectoryEntry, Name: "project", Value: "alpha"}, records[0])
+	assert.Equal(t, &fs.MetadataRecord{Path: rootDir + "/sub", Type: fs.DirectoryEntry, Name: "stage", Value: "raw"}, records[1])
+
+	recordsPerPath := map[string]int{}
+	for _, record := range records[2:] {
+		assert.Equal(t, fs.FileEntry, record.Type)
+		recordsPerPath[record.Path]++
+	}
+	assert.Equal(t, map[string]int{files[0]: common.MaxQueryRows + 10, files[1]: 1}, recordsPerPath)
+
+	// stops at the first error
+	stopErr := xerrors.Errorf("stop")
+	count := 0
+	err = filesystem.WalkMetadata(rootDir, func(record *fs.MetadataRecord) error {
+		count++
+		if record.Type == fs.FileEntry {
+			return stopErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, 3, count)
+
+	// the connection is usable after the walk stopped
+	entries, err := filesystem.List(rootDir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	// not a dir
+	err = filesystem.WalkMetadata(files[0], func(record *fs.MetadataRecord) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+func testExportMetadata(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	rootDir := homedir + "/meta_tree"
+	filePath := rootDir + "/file, with comma.txt"
+
+	err = mockServer.MakeCollection(rootDir, "alice")
+	failError(t, err)
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, rootDir, &types.IRODSMeta{Name: "project", Value: "alpha"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, filePath, &types.IRODSMeta{Name: "note", Value: "says \"hi\"", Units: "text"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// csv
+	buffer := bytes.Buffer{}
+	count, err := filesystem.ExportMetadata(rootDir, &buffer, fs.MetadataExportFormatCSV)
+	failError(t, err)
+	assert.Equal(t, 2, count)
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	failError(t, err)
+	assert.Equal(t, [][]string{
+		{"path", "type", "name", "value", "units"},
+		{rootDir, "directory", "project", "alpha", ""},
+		{filePath, "file", "note", "says \"hi\"", "text"},
+	}, rows)
+
+	// json lines
+	buffer.Reset()
+	count, err = filesystem.ExportMetadata(rootDir, &buffer, fs.MetadataExportFormatJSONL)
+	failError(t, err)
+	assert.Equal(t, 2, count)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	record := fs.MetadataRecord{}
+	err = json.Unmarshal([]byte(lines[1]), &record)
+	failError(t, err)
+	assert.Equal(t, fs.MetadataRecord{Path: filePath, Type: fs.FileEntry, Name: "note", Value: "says \"hi\"", Units: "text"}, record)
+
+	_, err = filesystem.ExportMetadata(rootDir, &buffer, "xml")
+	assert.Error(t, err)
+}
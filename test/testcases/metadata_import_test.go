@@ -0,0 +1,178 @@
+package testcases
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataImport(t *testing.T) {
+	t.Run("test ImportMetadataAtomic", testImportMetadataAtomic)
+	t.Run("test ImportMetadataPerAVU", testImportMetadataPerAVU)
+	t.Run("test ImportMetadataFromReader", testImportMetadataFromReader)
+}
+
+func getMetadataStrings(t *testing.T, filesystem *fs.FileSystem, path string) []string {
+	metas, err := filesystem.ListMetadata(path)
+	failError(t, err)
+
+	avus := []string{}
+	for _, meta := range metas {
+		avus = append(avus, meta.Name+"="+meta.Value+":"+meta.Units)
+	}
+	return avus
+}
+
+func testImportMetadataAtomic(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	dirPath := homedir + "/dir"
+	filePath := homedir + "/file.txt"
+
+	err = mockServer.MakeCollection(dirPath, "alice")
+	failError(t, err)
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, filePath, &types.IRODSMeta{Name: "size", Value: "11", Units: "bytes"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// cache metadata
+	assert.Empty(t, getMetadataStrings(t, filesystem, dirPath))
+
+	results, err := filesystem.ImportMetadata([]*fs.MetadataRecord{
+		{Path: dirPath, Name: "project", Value: "alpha"},
+		{Path: filePath, Type: fs.FileEntry, Name: "stage", Value: "raw"},
+		{Path: dirPath, Type: fs.DirectoryEntry, Name: "owner", Value: "alice", Units: "user"},
+		// already exists, fails all AVUs of the file
+		{Path: filePath, Type: fs.FileEntry, Name: "size", Value: "11", Units: "bytes"},
+		{Path: homedir + "/no_such_file.txt", Name: "stage", Value: "raw"},
+		{Path: dirPath, Name: "empty"},
+	})
+	failError(t, err)
+	assert.Len(t, results, 6)
+
+	assert.NoError(t, results[0].Error)
+	assert.Equal(t, "project", results[0].Record.Name)
+	assert.Error(t, results[1].Error)
+	assert.NoError(t, results[2].Error)
+	assert.Error(t, results[3].Error)
+	assert.Error(t, results[4].Error)
+	assert.True(t, types.IsFileNotFoundError(results[4].Error))
+	assert.Error(t, results[5].Error)
+
+	assert.ElementsMatch(t, []string{"project=alpha:", "owner=alice:user"}, getMetadataStrings(t, filesystem, dirPath))
+	assert.ElementsMatch(t, []string{"size=11:bytes"}, getMetadataStrings(t, filesystem, filePath))
+}
+
+func testImportMetadataPerAVU(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	// no atomic metadata operations
+	mockServer.SetReleaseVersion("rods4.2.7")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filePath := "/mockzone/home/alice/file.txt"
+
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, filePath, &types.IRODSMeta{Name: "size", Value: "11", Units: "bytes"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	results, err := filesystem.ImportMetadata([]*fs.MetadataRecord{
+		{Path: filePath, Name: "stage", Value: "raw"},
+		{Path: filePath, Name: "size", Value: "11", Units: "bytes"},
+		{Path: filePath, Name: "project", Value: "alpha"},
+	})
+	failError(t, err)
+	assert.Len(t, results, 3)
+
+	assert.NoError(t, results[0].Error)
+	assert.Error(t, results[1].Error)
+	assert.NoError(t, results[2].Error)
+
+	assert.ElementsMatch(t, []string{"size=11:bytes", "stage=raw:", "project=alpha:"}, getMetadataStrings(t, filesystem, filePath))
+}
+
+func testImportMetadataFromReader(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcDir := homedir + "/src"
+	destDir := homedir + "/dest"
+
+	for _, dir := range []string{srcDir, destDir} {
+		err = mockServer.MakeCollection(dir, "alice")
+		failError(t, err)
+		err = mockServer.PutDataObject(dir+"/file.txt", "alice", []byte("hello world"))
+		failError(t, err)
+	}
+
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, srcDir, &types.IRODSMeta{Name: "project", Value: "alpha"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, srcDir+"/file.txt", &types.IRODSMeta{Name: "note", Value: "says \"hi\"", Units: "text"})
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	for _, format := range []fs.MetadataExportFormat{fs.MetadataExportFormatCSV, fs.MetadataExportFormatJSONL} {
+		buffer := bytes.Buffer{}
+		_, err = filesystem.ExportMetadata(srcDir, &buffer, format)
+		failError(t, err)
+
+		// import to the other dir
+		exported := strings.ReplaceAll(buffer.String(), srcDir, destDir)
+
+		results := []*fs.MetadataImportResult{}
+		count, err := filesystem.ImportMetadataFromReader(strings.NewReader(exported), format, func(result *fs.MetadataImportResult) {
+			results = append(results, result)
+		})
+		failError(t, err)
+
+		if format == fs.MetadataExportFormatCSV {
+			assert.Equal(t, 2, count)
+			assert.Len(t, results, 2)
+			assert.Equal(t, &fs.MetadataRecord{Path: destDir + "/file.txt", Type: fs.FileEntry, Name: "note", Value: "says \"hi\"", Units: "text"}, results[1].Record)
+		} else {
+			// already imported
+			assert.Equal(t, 0, count)
+			assert.Len(t, results, 2)
+			assert.Error(t, results[0].Error)
+		}
+	}
+
+	assert.Equal(t, []string{"project=alpha:"}, getMetadataStrings(t, filesystem, destDir))
+	assert.Equal(t, []string{"note=says \"hi\":text"}, getMetadataStrings(t, filesystem, destDir+"/file.txt"))
+
+	// header without a name column
+	_, err = filesystem.ImportMetadataFromReader(strings.NewReader("path,value\n"+destDir+",x\n"), fs.MetadataExportFormatCSV, nil)
+	assert.Error(t, err)
+
+	_, err = filesystem.ImportMetadataFromReader(strings.NewReader(""), "xml", nil)
+	assert.Error(t, err)
+}
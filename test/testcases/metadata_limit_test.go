@@ -0,0 +1,170 @@
+package testcases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataLimit(t *testing.T) {
+	t.Run("test ValidateMetadata", testValidateMetadata)
+	t.Run("test SplitAndJoinMetadata", testSplitAndJoinMetadata)
+	t.Run("test AddLargeMetadata", testAddLargeMetadata)
+	t.Run("test MetadataRefusedByServer", testMetadataRefusedByServer)
+}
+
+func testValidateMetadata(t *testing.T) {
+	meta := &types.IRODSMeta{
+		Name:  "key",
+		Value: strings.Repeat("v", types.IRODSMetaValueMaxLength),
+		Units: "units",
+	}
+	assert.NoError(t, meta.Validate())
+
+	meta.Value += "v"
+	err := meta.Validate()
+	assert.True(t, types.IsMetadataTooLargeError(err))
+
+	meta.Value = "value"
+	meta.Units = strings.Repeat("u", types.IRODSMetaUnitsMaxLength+1)
+	err = meta.Validate()
+	assert.True(t, types.IsMetadataTooLargeError(err))
+	assert.Equal(t, "units", err.(*types.MetadataTooLargeError).Field)
+}
+
+func testSplitAndJoinMetadata(t *testing.T) {
+	// multi-byte characters are not split
+	meta := &types.IRODSMeta{
+		Name:  "key",
+		Value: strings.Repeat("abcé世", 10),
+		Units: "json",
+	}
+
+	chunks := types.SplitIRODSMeta(meta, 16)
+	assert.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk.Value), 16)
+		assert.True(t, strings.HasPrefix(chunk.Units, "json"))
+	}
+
+	plain := &types.IRODSMeta{Name: "other", Value: "value", Units: ""}
+	assert.Len(t, types.SplitIRODSMeta(plain, 16), 1)
+
+	joined, err := types.JoinIRODSMeta(append([]*types.IRODSMeta{plain}, chunks...))
+	failError(t, err)
+	if assert.Len(t, joined, 2) {
+		assert.Equal(t, plain, joined[0])
+		assert.Equal(t, meta.Name, joined[1].Name)
+		assert.Equal(t, meta.Value, joined[1].Value)
+		assert.Equal(t, meta.Units, joined[1].Units)
+	}
+
+	// missing chunks
+	_, err = types.JoinIRODSMeta(chunks[1:])
+	assert.Error(t, err)
+
+	// values of the same name, units and number of chunks are joined separately
+	otherMeta := &types.IRODSMeta{
+		Name:  "key",
+		Value: strings.Repeat("xyzé世", 10),
+		Units: "json",
+	}
+
+	otherChunks := types.SplitIRODSMeta(otherMeta, 16)
+	assert.Len(t, otherChunks, len(chunks))
+
+	joined, err = types.JoinIRODSMeta(append(append([]*types.IRODSMeta{}, chunks...), otherChunks...))
+	failError(t, err)
+	if assert.Len(t, joined, 2) {
+		assert.ElementsMatch(t, []string{meta.Value, otherMeta.Value}, []string{joined[0].Value, joined[1].Value})
+	}
+}
+
+func testAddLargeMetadata(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	value := "{\"data\": \"" + strings.Repeat("x", 2*types.IRODSMetaValueMaxLength) + "\"}"
+
+	err = filesystem.AddMetadata(irodsPath, "blob", value, "json")
+	assert.True(t, types.IsMetadataTooLargeError(err))
+
+	err = filesystem.AddLargeMetadata(irodsPath, "blob", value, "json")
+	failError(t, err)
+
+	metadata, err := filesystem.ListMetadata(irodsPath)
+	failError(t, err)
+	assert.Len(t, metadata, 3)
+
+	joined, err := types.JoinIRODSMeta(metadata)
+	failError(t, err)
+	if assert.Len(t, joined, 1) {
+		assert.Equal(t, value, joined[0].Value)
+		assert.Equal(t, "json", joined[0].Units)
+	}
+
+	// another large value of the same name and units
+	otherValue := "{\"data\": \"" + strings.Repeat("y", 2*types.IRODSMetaValueMaxLength) + "\"}"
+
+	err = filesystem.AddLargeMetadata(irodsPath, "blob", otherValue, "json")
+	failError(t, err)
+
+	metadata, err = filesystem.ListMetadata(irodsPath)
+	failError(t, err)
+	assert.Len(t, metadata, 6)
+
+	joined, err = types.JoinIRODSMeta(metadata)
+	failError(t, err)
+	if assert.Len(t, joined, 2) {
+		assert.ElementsMatch(t, []string{value, otherValue}, []string{joined[0].Value, joined[1].Value})
+	}
+
+	err = filesystem.DeleteMetadataByName(irodsPath, "blob")
+	failError(t, err)
+
+	metadata, err = filesystem.ListMetadata(irodsPath)
+	failError(t, err)
+	assert.Empty(t, metadata)
+}
+
+func testMetadataRefusedByServer(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	mockServer.SetMetadataValueMaxLength(100)
+
+	err = filesystem.AddMetadata(irodsPath, "key", strings.Repeat("v", 200), "")
+	assert.True(t, types.IsMetadataTooLargeError(err))
+
+	var tooLargeErr *types.MetadataTooLargeError
+	if assert.ErrorAs(t, err, &tooLargeErr) {
+		assert.Equal(t, "value", tooLargeErr.Field)
+		assert.Equal(t, 200, tooLargeErr.Length)
+		assert.Equal(t, 0, tooLargeErr.MaxLength)
+	}
+}
@@ -0,0 +1,184 @@
+package testcases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	t.Run("test ListMetadataPaging", testListMetadataPaging)
+	t.Run("test SearchByMetaWithUnits", testSearchByMetaWithUnits)
+	t.Run("test SearchByMetaEntryType", testSearchByMetaEntryType)
+}
+
+func testListMetadataPaging(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	filePath := homedir + "/many_avus.txt"
+
+	err = mockServer.PutDataObject(filePath, "alice", []byte("content"))
+	failError(t, err)
+
+	// more than a page
+	avuNum := common.MaxQueryRows*2 + 10
+	for i := 0; i < avuNum; i++ {
+		meta := &types.IRODSMeta{
+			Name:  fmt.Sprintf("attr%d", i),
+			Value: "value",
+		}
+
+		err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, filePath, meta)
+		failError(t, err)
+		err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, homedir, meta)
+		failError(t, err)
+	}
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	for _, p := range []string{filePath, homedir} {
+		metas, err := filesystem.ListMetadata(p)
+		failError(t, err)
+		assert.Len(t, metas, avuNum)
+
+		names := map[string]bool{}
+		for _, meta := range metas {
+			names[meta.Name] = true
+			assert.False(t, meta.CreateTime.IsZero())
+			assert.False(t, meta.ModifyTime.IsZero())
+		}
+		assert.Len(t, names, avuNum)
+	}
+
+	// uncleaned path hits the same cache entry
+	metas, err := filesystem.ListMetadata(homedir + "//many_avus.txt")
+	failError(t, err)
+	assert.Len(t, metas, avuNum)
+}
+
+func testSearchByMetaWithUnits(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	dirPath := homedir + "/dir"
+	fileAPath := homedir + "/a.txt"
+	fileBPath := homedir + "/b.txt"
+
+	err = mockServer.PutDataObject(fileAPath, "alice", []byte("a"))
+	failError(t, err)
+	err = mockServer.PutDataObject(fileBPath, "alice", []byte("b"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.MakeDir(dirPath, false)
+	failError(t, err)
+
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, fileAPath, &types.IRODSMeta{Name: "size", Value: "10", Units: "ns:kb"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, fileBPath, &types.IRODSMeta{Name: "size", Value: "10", Units: "ns:mb"})
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, dirPath, &types.IRODSMeta{Name: "size", Value: "10", Units: "ns:kb"})
+	failError(t, err)
+
+	getPaths := func(entries []*fs.Entry) []string {
+		paths := []string{}
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		return paths
+	}
+
+	// units are ignored
+	entries, err := filesystem.SearchByMeta("size", "10")
+	failError(t, err)
+	assert.ElementsMatch(t, []string{dirPath, fileAPath, fileBPath}, getPaths(entries))
+
+	// exact units
+	entries, err = filesystem.SearchByMetaWithUnits("size", "10", "ns:kb")
+	failError(t, err)
+	assert.ElementsMatch(t, []string{dirPath, fileAPath}, getPaths(entries))
+
+	entries, err = filesystem.SearchByMetaWithUnits("size", "10", "ns:gb")
+	failError(t, err)
+	assert.Empty(t, entries)
+
+	// wildcard units
+	entries, err = filesystem.SearchByMetaWithUnitsWildcard("size", "1%", "ns:%")
+	failError(t, err)
+	assert.ElementsMatch(t, []string{dirPath, fileAPath, fileBPath}, getPaths(entries))
+
+	entries, err = filesystem.SearchByMetaWithUnitsWildcard("size", "%", "%mb")
+	failError(t, err)
+	assert.ElementsMatch(t, []string{fileBPath}, getPaths(entries))
+}
+
+func testSearchByMetaEntryType(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	dirPath := homedir + "/dir"
+	filePath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(filePath, "alice", []byte("content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.MakeDir(dirPath, false)
+	failError(t, err)
+
+	meta := &types.IRODSMeta{Name: "project", Value: "p1"}
+	err = mockServer.AddMetadata(types.IRODSDataObjectMetaItemType, filePath, meta)
+	failError(t, err)
+	err = mockServer.AddMetadata(types.IRODSCollectionMetaItemType, dirPath, meta)
+	failError(t, err)
+
+	entries, err := filesystem.SearchByMetaWithOptions("project", "p1", nil)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	entries, err = filesystem.SearchByMetaWithOptions("project", "p1", &fs.SearchByMetaOptions{
+		EntryType: fs.FileEntry,
+	})
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, filePath, entries[0].Path)
+	assert.Equal(t, fs.FileEntry, entries[0].Type)
+
+	entries, err = filesystem.SearchByMetaWithOptions("project", "p1", &fs.SearchByMetaOptions{
+		EntryType: fs.DirectoryEntry,
+	})
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, dirPath, entries[0].Path)
+	assert.Equal(t, fs.DirectoryEntry, entries[0].Type)
+
+	_, err = filesystem.SearchByMetaWithOptions("project", "p1", &fs.SearchByMetaOptions{
+		EntryType: "symlink",
+	})
+	assert.Error(t, err)
+}
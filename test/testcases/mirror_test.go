@@ -0,0 +1,137 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirror(t *testing.T) {
+	t.Run("test MirrorDir", testMirrorDir)
+}
+
+func testMirrorDir(t *testing.T) {
+	srcServer := startMockServer(t)
+	defer srcServer.Stop()
+
+	destServer := startMockServer(t)
+	defer destServer.Stop()
+
+	srcAccount, err := srcServer.GetAccount("alice")
+	failError(t, err)
+
+	destAccount, err := destServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcDir := homedir + "/data"
+	destDir := homedir + "/mirror"
+
+	err = srcServer.MakeCollection(srcDir+"/sub", "alice")
+	failError(t, err)
+
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/c.txt"} {
+		err = srcServer.PutDataObject(srcDir+"/"+name, "alice", []byte("content of "+name))
+		failError(t, err)
+	}
+
+	err = srcServer.AddMetadata(types.IRODSDataObjectMetaItemType, srcDir+"/a.txt", &types.IRODSMeta{Name: "key", Value: "value", Units: "units"})
+	failError(t, err)
+	err = srcServer.AddMetadata(types.IRODSCollectionMetaItemType, srcDir+"/sub", &types.IRODSMeta{Name: "dirkey", Value: "dirvalue"})
+	failError(t, err)
+
+	srcFS, err := fs.NewFileSystemWithDefault(srcAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer srcFS.Release()
+
+	destFS, err := fs.NewFileSystemWithDefault(destAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer destFS.Release()
+
+	options := &fs.MirrorDirOptions{
+		DestPath:       destDir,
+		MirrorMetadata: true,
+		Delete:         true,
+	}
+
+	// initial mirror
+	result, err := fs.MirrorDir(srcFS, destFS, srcDir, options)
+	failError(t, err)
+	assert.Equal(t, []string{destDir, destDir + "/sub"}, result.CreatedDirs)
+	assert.ElementsMatch(t, []string{destDir + "/a.txt", destDir + "/sub/b.txt", destDir + "/sub/c.txt"}, result.CopiedFiles)
+	assert.ElementsMatch(t, []string{destDir + "/a.txt", destDir + "/sub"}, result.MetadataUpdated)
+
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/c.txt"} {
+		data, err := destServer.GetDataObject(destDir + "/" + name)
+		failError(t, err)
+		assert.Equal(t, "content of "+name, string(data))
+	}
+
+	metas, err := destFS.ListMetadata(destDir + "/a.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "key", metas[0].Name)
+	assert.Equal(t, "value", metas[0].Value)
+	assert.Equal(t, "units", metas[0].Units)
+
+	// nothing changed
+	result, err = fs.MirrorDir(srcFS, destFS, srcDir, options)
+	failError(t, err)
+	assert.Empty(t, result.CreatedDirs)
+	assert.Empty(t, result.CopiedFiles)
+	assert.Len(t, result.SkippedFiles, 3)
+	assert.Empty(t, result.MetadataUpdated)
+	assert.Empty(t, result.Removed)
+
+	// change the source file in the same size, add extra entries and AVUs to the dest
+	err = srcServer.PutDataObject(srcDir+"/a.txt", "alice", []byte("CONTENT OF a.txt"))
+	failError(t, err)
+	srcFS.ClearCache()
+
+	err = destFS.MakeDir(destDir+"/old", false)
+	failError(t, err)
+	err = destServer.PutDataObject(destDir+"/old/x.txt", "alice", []byte("old"))
+	failError(t, err)
+	err = destServer.PutDataObject(destDir+"/sub/extra.txt", "alice", []byte("extra"))
+	failError(t, err)
+	err = destFS.AddMetadata(destDir+"/sub/b.txt", "extra", "value", "")
+	failError(t, err)
+	destFS.ClearCache()
+
+	result, err = fs.MirrorDir(srcFS, destFS, srcDir, options)
+	failError(t, err)
+	assert.Equal(t, []string{destDir + "/a.txt"}, result.CopiedFiles)
+	assert.ElementsMatch(t, []string{destDir + "/sub/b.txt", destDir + "/sub/c.txt"}, result.SkippedFiles)
+	assert.Equal(t, []string{destDir + "/sub/b.txt"}, result.MetadataUpdated)
+	assert.ElementsMatch(t, []string{destDir + "/old", destDir + "/sub/extra.txt"}, result.Removed)
+
+	data, err := destServer.GetDataObject(destDir + "/a.txt")
+	failError(t, err)
+	assert.Equal(t, "CONTENT OF a.txt", string(data))
+
+	// AVUs are kept on overwrite
+	metas, err = destFS.ListMetadata(destDir + "/a.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+
+	metas, err = destFS.ListMetadata(destDir + "/sub/b.txt")
+	failError(t, err)
+	assert.Empty(t, metas)
+
+	assert.False(t, destFS.ExistsDir(destDir+"/old"))
+	assert.False(t, destFS.ExistsFile(destDir+"/sub/extra.txt"))
+
+	// conflicts are not resolved without delete
+	err = destFS.RemoveFile(destDir+"/sub/c.txt", true)
+	failError(t, err)
+	err = destFS.MakeDir(destDir+"/sub/c.txt", false)
+	failError(t, err)
+
+	_, err = fs.MirrorDir(srcFS, destFS, srcDir, &fs.MirrorDirOptions{
+		DestPath: destDir,
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+}
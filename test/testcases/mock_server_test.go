@@ -0,0 +1,310 @@
+package testcases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockServer(t *testing.T) {
+	t.Run("test MockServerAuth", testMockServerAuth)
+	t.Run("test MockServerFileSystem", testMockServerFileSystem)
+	t.Run("test MockServerPing", testMockServerPing)
+	t.Run("test MockServerKeepalive", testMockServerKeepalive)
+	t.Run("test MockServerHeartbeat", testMockServerHeartbeat)
+	t.Run("test MockServerShutdown", testMockServerShutdown)
+}
+
+func startMockServer(t *testing.T) *mock.IRODSMockServer {
+	mockServer, err := mock.NewIRODSMockServer("mockzone", "rods", "rods_password")
+	failError(t, err)
+
+	err = mockServer.AddUser("alice", "alice_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	err = mockServer.Start()
+	failError(t, err)
+
+	return mockServer
+}
+
+func testMockServerAuth(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	filesystem.Release()
+
+	account.Password = "wrong_password"
+	filesystem, err = fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	if err == nil {
+		// connections may be established lazily
+		_, err = filesystem.Stat("/mockzone/home/alice")
+		filesystem.Release()
+	}
+	assert.Error(t, err)
+}
+
+func testMockServerFileSystem(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.PutDataObject("/mockzone/home/alice/existing.txt", "alice", []byte("hello"))
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	// stat & list
+	entry, err := filesystem.Stat(homedir + "/existing.txt")
+	failError(t, err)
+	assert.Equal(t, int64(5), entry.Size)
+	assert.Equal(t, "alice", entry.Owner)
+
+	err = filesystem.MakeDir(homedir+"/dir1/dir2", true)
+	failError(t, err)
+	assert.True(t, filesystem.ExistsDir(homedir+"/dir1/dir2"))
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	// write & read
+	handle, err := filesystem.CreateFile(homedir+"/dir1/new.txt", "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte("mock iRODS data"))
+	failError(t, err)
+	failError(t, handle.Close())
+
+	data, err := mockServer.GetDataObject(homedir + "/dir1/new.txt")
+	failError(t, err)
+	assert.Equal(t, "mock iRODS data", string(data))
+
+	handle, err = filesystem.OpenFile(homedir+"/dir1/new.txt", "", "r")
+	failError(t, err)
+
+	buffer := make([]byte, 100)
+	readLen, err := handle.ReadAt(buffer, 5)
+	if err != io.EOF {
+		failError(t, err)
+	}
+	assert.Equal(t, "iRODS data", string(buffer[:readLen]))
+	failError(t, handle.Close())
+
+	// metadata
+	err = filesystem.AddMetadata(homedir+"/dir1/new.txt", "key", "value", "units")
+	failError(t, err)
+
+	metas, err := filesystem.ListMetadata(homedir + "/dir1/new.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "key", metas[0].Name)
+	assert.Equal(t, "value", metas[0].Value)
+	assert.Equal(t, "units", metas[0].Units)
+
+	// rename & remove
+	err = filesystem.RenameFile(homedir+"/dir1/new.txt", homedir+"/renamed.txt")
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(homedir+"/renamed.txt"))
+	assert.False(t, filesystem.ExistsFile(homedir+"/dir1/new.txt"))
+
+	err = filesystem.RemoveFile(homedir+"/renamed.txt", true)
+	failError(t, err)
+	assert.False(t, filesystem.ExistsFile(homedir+"/renamed.txt"))
+
+	err = filesystem.RemoveDir(homedir+"/dir1", true, true)
+	failError(t, err)
+	assert.False(t, filesystem.ExistsDir(homedir+"/dir1"))
+}
+
+func testMockServerPing(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSession(account, session.NewIRODSSessionConfigWithDefault("go-irodsclient-test"))
+	failError(t, err)
+	defer sess.Release()
+
+	err = sess.Ping()
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.Ping()
+	failError(t, err)
+
+	// wrong password
+	wrongAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+	wrongAccount.Password = "wrong_password"
+
+	wrongSess, err := session.NewIRODSSession(wrongAccount, session.NewIRODSSessionConfigWithDefault("go-irodsclient-test"))
+	if err == nil {
+		defer wrongSess.Release()
+		err = wrongSess.Ping()
+	}
+	assert.Error(t, err)
+
+	// server is gone
+	mockServer.Stop()
+
+	err = filesystem.Ping()
+	assert.Error(t, err)
+}
+
+func testMockServerKeepalive(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sessConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessConfig.ConnectionKeepaliveInterval = 100 * time.Millisecond
+
+	sess, err := session.NewIRODSSession(account, sessConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+
+	bytesSent := sess.GetMetrics().GetBytesSent()
+
+	time.Sleep(500 * time.Millisecond)
+
+	// heartbeats were sent on the idle connection
+	assert.Greater(t, sess.GetMetrics().GetBytesSent(), bytesSent)
+	assert.Greater(t, mockServer.GetHeartbeatCount(), 0)
+
+	reusedConn, err := sess.AcquireConnection()
+	failError(t, err)
+	defer sess.ReturnConnection(reusedConn)
+
+	assert.True(t, reusedConn.IsConnected())
+}
+
+func testMockServerHeartbeat(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sessConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessConfig.ConnectionKeepaliveInterval = 100 * time.Millisecond
+
+	sess, err := session.NewIRODSSession(account, sessConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	err = conn.Heartbeat()
+	failError(t, err)
+	assert.Equal(t, 1, mockServer.GetHeartbeatCount())
+
+	// heartbeats are skipped while the connection is in use
+	conn.Lock()
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, mockServer.GetHeartbeatCount())
+	conn.Unlock()
+
+	// an occupied connection not in use receives heartbeats
+	time.Sleep(300 * time.Millisecond)
+	assert.Greater(t, mockServer.GetHeartbeatCount(), 1)
+
+	// the connection is still usable
+	_, err = irods_fs.GetCollection(conn, "/mockzone/home/alice")
+	failError(t, err)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+}
+
+func testMockServerShutdown(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+
+	handle, err := filesystem.CreateFile(homedir+"/shutdown.txt", "", "w")
+	failError(t, err)
+	_, err = handle.Write([]byte("hello"))
+	failError(t, err)
+
+	// an in-flight operation that completes before the deadline
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		filesystem.ReturnMetadataConnection(conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = filesystem.Shutdown(ctx)
+	failError(t, err)
+
+	// no new operations are accepted
+	_, err = filesystem.Stat(homedir + "/other.txt")
+	assert.Error(t, err)
+	assert.True(t, types.IsSessionShutdownError(err))
+
+	// open file handle was flushed
+	newFilesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer newFilesystem.Release()
+
+	entry, err := newFilesystem.Stat(homedir + "/shutdown.txt")
+	failError(t, err)
+	assert.Equal(t, int64(5), entry.Size)
+
+	// an in-flight operation that does not complete before the deadline
+	conn, err = newFilesystem.GetMetadataConnection()
+	failError(t, err)
+
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer shortCancel()
+
+	err = newFilesystem.Shutdown(shortCtx)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, conn.IsConnected())
+}
@@ -0,0 +1,184 @@
+package testcases
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipartUpload(t *testing.T) {
+	t.Run("test UploadParts", testMultipartUploadParts)
+	t.Run("test IncompleteParts", testMultipartUploadIncompleteParts)
+	t.Run("test Abort", testMultipartUploadAbort)
+}
+
+func makeMultipartTestData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func testMultipartUploadParts(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/multipart.bin"
+
+	// existing target is replaced
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+
+	partSize := int64(1024)
+	data := makeMultipartTestData(int(partSize)*3 + 100)
+
+	upload, err := filesystem.InitiateMultipartUpload(targetPath, "", partSize)
+	failError(t, err)
+	assert.NotEmpty(t, upload.GetID())
+	assert.Equal(t, targetPath, upload.GetPath())
+
+	// upload parts in reverse order in parallel
+	wg := sync.WaitGroup{}
+	for partNumber := 4; partNumber >= 1; partNumber-- {
+		start := int64(partNumber-1) * partSize
+		end := start + partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+
+		wg.Add(1)
+		go func(partNumber int, part []byte) {
+			defer wg.Done()
+
+			size, err := upload.UploadPart(partNumber, bytes.NewReader(part))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(part)), size)
+		}(partNumber, data[start:end])
+	}
+	wg.Wait()
+
+	// oversized part is rejected
+	_, err = upload.UploadPart(2, bytes.NewReader(make([]byte, partSize+1)))
+	assert.Error(t, err)
+
+	// re-upload part 2
+	_, err = upload.UploadPart(2, bytes.NewReader(data[partSize:partSize*2]))
+	failError(t, err)
+
+	parts := upload.GetUploadedParts()
+	assert.Len(t, parts, 4)
+	assert.Equal(t, int64(100), parts[4])
+
+	entry, err := upload.Complete()
+	failError(t, err)
+	assert.Equal(t, targetPath, entry.Path)
+	assert.Equal(t, int64(len(data)), entry.Size)
+
+	content, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, data, content)
+
+	// no temporary data objects remain
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+
+	// closed upload cannot be used
+	_, err = upload.UploadPart(1, bytes.NewReader(data[:partSize]))
+	assert.Error(t, err)
+	_, err = upload.Complete()
+	assert.Error(t, err)
+}
+
+func testMultipartUploadIncompleteParts(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/multipart.bin"
+
+	partSize := int64(512)
+
+	upload, err := filesystem.InitiateMultipartUpload(targetPath, "", partSize)
+	failError(t, err)
+
+	// no parts
+	_, err = upload.Complete()
+	assert.Error(t, err)
+
+	// missing part 2
+	_, err = upload.UploadPart(1, bytes.NewReader(makeMultipartTestData(int(partSize))))
+	failError(t, err)
+	_, err = upload.UploadPart(3, bytes.NewReader(makeMultipartTestData(10)))
+	failError(t, err)
+
+	_, err = upload.Complete()
+	assert.Error(t, err)
+
+	// short part 2 is not the last part
+	_, err = upload.UploadPart(2, bytes.NewReader(makeMultipartTestData(10)))
+	failError(t, err)
+
+	_, err = upload.Complete()
+	assert.Error(t, err)
+
+	// fix part 2
+	_, err = upload.UploadPart(2, bytes.NewReader(makeMultipartTestData(int(partSize))))
+	failError(t, err)
+
+	entry, err := upload.Complete()
+	failError(t, err)
+	assert.Equal(t, partSize*2+10, entry.Size)
+}
+
+func testMultipartUploadAbort(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/multipart.bin"
+
+	upload, err := filesystem.InitiateMultipartUpload(targetPath, "", 256)
+	failError(t, err)
+
+	_, err = upload.UploadPart(1, bytes.NewReader(makeMultipartTestData(256)))
+	failError(t, err)
+
+	err = upload.Abort()
+	failError(t, err)
+
+	assert.False(t, filesystem.Exists(targetPath))
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 0)
+
+	err = upload.Abort()
+	assert.Error(t, err)
+}
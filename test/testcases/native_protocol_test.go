@@ -0,0 +1,196 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNativeProtocol(t *testing.T) {
+	t.Run("test NativePackingEmptyKeyVal", testNativePackingEmptyKeyVal)
+	t.Run("test NativePackingRoundTrip", testNativePackingRoundTrip)
+	t.Run("test NativePackingMessageStructs", testNativePackingMessageStructs)
+	t.Run("test NativeProtocolAccount", testNativeProtocolAccount)
+	t.Run("test NativeProtocolFileSystem", testNativeProtocolFileSystem)
+}
+
+func testNativePackingEmptyKeyVal(t *testing.T) {
+	nativeBytes, err := message.ConvertXMLToNative([]byte("<KeyValPair_PI><ssLen>0</ssLen></KeyValPair_PI>"))
+	failError(t, err)
+
+	expected := &bytes.Buffer{}
+	binary.Write(expected, binary.BigEndian, int32(0))
+	expected.WriteString("%@#ANULLSTR$%\x00")
+	expected.WriteString("%@#ANULLSTR$%\x00")
+	assert.Equal(t, expected.Bytes(), nativeBytes)
+
+	xmlBytes, err := message.ConvertNativeToXML(nativeBytes, "KeyValPair_PI")
+	failError(t, err)
+	assert.Equal(t, "<KeyValPair_PI><ssLen>0</ssLen></KeyValPair_PI>", string(xmlBytes))
+}
+
+func testNativePackingRoundTrip(t *testing.T) {
+	messages := []string{
+		"<GenQueryInp_PI><maxRows>500</maxRows><continueInx>0</continueInx><partialStartIndex>0</partialStartIndex><options>0</options><KeyValPair_PI><ssLen>1</ssLen><keyWord>zone</keyWord><svalue>tempZone</svalue></KeyValPair_PI><InxIvalPair_PI><iiLen>2</iiLen><inx>501</inx><inx>403</inx><ivalue>1</ivalue><ivalue>1</ivalue></InxIvalPair_PI><InxValPair_PI><isLen>1</isLen><inx>501</inx><svalue>= &#39;/tempZone/home&#39;</svalue></InxValPair_PI></GenQueryInp_PI>",
+		"<DataObjCopyInp_PI><DataObjInp_PI><objPath>/tempZone/home/rods/a.txt</objPath><createMode>0</createMode><openFlags>0</openFlags><offset>0</offset><dataSize>0</dataSize><numThreads>0</numThreads><oprType>11</oprType><KeyValPair_PI><ssLen>0</ssLen></KeyValPair_PI></DataObjInp_PI><DataObjInp_PI><objPath>/tempZone/home/rods/b.txt</objPath><createMode>0</createMode><openFlags>0</openFlags><offset>0</offset><dataSize>0</dataSize><numThreads>0</numThreads><oprType>12</oprType><KeyValPair_PI><ssLen>0</ssLen></KeyValPair_PI></DataObjInp_PI></DataObjCopyInp_PI>",
+		// challenge is a fixed size binary buffer of 64 bytes
+		"<authRequestOut_PI><challenge>" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("c"), 64)) + "</challenge></authRequestOut_PI>",
+	}
+
+	for _, msg := range messages {
+		nativeBytes, err := message.ConvertXMLToNative([]byte(msg))
+		failError(t, err)
+
+		name := msg[1:bytes.IndexByte([]byte(msg), '>')]
+		xmlBytes, err := message.ConvertNativeToXML(nativeBytes, name)
+		failError(t, err)
+		assert.Equal(t, msg, string(xmlBytes))
+	}
+
+	_, err := message.ConvertXMLToNative([]byte("<NoSuchMessage_PI></NoSuchMessage_PI>"))
+	assert.Error(t, err)
+
+	_, err = message.ConvertNativeToXML([]byte{0, 0}, "GenQueryInp_PI")
+	assert.Error(t, err)
+}
+
+func testNativePackingMessageStructs(t *testing.T) {
+	query := message.NewIRODSMessageQueryRequest(500, 0, 0, 0)
+	query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
+	query.AddCondition(common.ICAT_COLUMN_COLL_NAME, "= '/tempZone/home/o''brien & co'")
+	query.AddKeyVal(common.ZONE_KW, "tempZone")
+
+	touch := message.NewIRODSMessageTouchRequest("/tempZone/home/rods/a.txt", true, 0)
+
+	// message structs are packed directly, as their XML is translated
+	for _, request := range []interface {
+		GetBytes() ([]byte, error)
+	}{query, touch} {
+		xmlBytes, err := request.GetBytes()
+		failError(t, err)
+
+		expected, err := message.ConvertXMLToNative(xmlBytes)
+		failError(t, err)
+
+		nativeBytes, err := message.MarshalNative(request)
+		failError(t, err)
+		assert.Equal(t, expected, nativeBytes)
+	}
+
+	responseXML := "<GenQueryOut_PI><rowCnt>2</rowCnt><attriCnt>1</attriCnt><continueInx>0</continueInx><totalRowCount>2</totalRowCount><SqlResult_PI><attriInx>501</attriInx><reslen>32</reslen><value>/tempZone/home/a &amp; b</value><value>/tempZone/home/c</value></SqlResult_PI></GenQueryOut_PI>"
+	nativeBytes, err := message.ConvertXMLToNative([]byte(responseXML))
+	failError(t, err)
+
+	// native packing has results of all MAX_SQL_ATTR columns, compare with its translated XML
+	xmlBytes, err := message.ConvertNativeToXML(nativeBytes, "GenQueryOut_PI")
+	failError(t, err)
+
+	expected := message.IRODSMessageQueryResponse{}
+	err = xml.Unmarshal(xmlBytes, &expected)
+	failError(t, err)
+
+	response := message.IRODSMessageQueryResponse{}
+	err = message.UnmarshalNative(nativeBytes, "GenQueryOut_PI", &response)
+	failError(t, err)
+	assert.Equal(t, expected, response)
+	assert.Equal(t, []string{"/tempZone/home/a & b", "/tempZone/home/c"}, response.SQLResult[0].Values)
+
+	err = message.UnmarshalNative([]byte{0, 0}, "GenQueryOut_PI", &response)
+	assert.Error(t, err)
+}
+
+func testNativeProtocolAccount(t *testing.T) {
+	yamlBytes := []byte(`
+host:
+  hostname: irods.example.com
+  port: 1247
+user:
+  username: alice
+  password: alice_password
+  zone: example
+auth_scheme: native
+protocol: native
+`)
+
+	account, err := types.CreateIRODSAccountFromYAML(yamlBytes)
+	failError(t, err)
+	assert.Equal(t, types.IRODSProtocolNative, account.Protocol)
+
+	startup := message.NewIRODSMessageStartupPack(account, "go-irodsclient-test", false)
+	assert.Equal(t, 0, startup.Protocol)
+
+	_, err = types.GetIRODSProtocol("binary")
+	assert.Error(t, err)
+}
+
+func testNativeProtocolFileSystem(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.PutDataObject("/mockzone/home/alice/existing.txt", "alice", []byte("hello"))
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+	account.Protocol = types.IRODSProtocolNative
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	entry, err := filesystem.Stat(homedir + "/existing.txt")
+	failError(t, err)
+	assert.Equal(t, int64(5), entry.Size)
+
+	err = filesystem.MakeDir(homedir+"/dir1", true)
+	failError(t, err)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	handle, err := filesystem.CreateFile(homedir+"/dir1/new.txt", "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte("native data"))
+	failError(t, err)
+	failError(t, handle.Close())
+
+	handle, err = filesystem.OpenFile(homedir+"/dir1/new.txt", "", "r")
+	failError(t, err)
+
+	buffer := make([]byte, 100)
+	readLen, err := handle.ReadAt(buffer, 0)
+	if err != io.EOF {
+		failError(t, err)
+	}
+	assert.Equal(t, "native data", string(buffer[:readLen]))
+	failError(t, handle.Close())
+
+	err = filesystem.AddMetadata(homedir+"/dir1/new.txt", "key", "value", "")
+	failError(t, err)
+
+	metas, err := filesystem.ListMetadata(homedir + "/dir1/new.txt")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "", metas[0].Units)
+
+	err = filesystem.RenameFile(homedir+"/dir1/new.txt", homedir+"/renamed.txt")
+	failError(t, err)
+	assert.True(t, filesystem.ExistsFile(homedir+"/renamed.txt"))
+
+	err = filesystem.RemoveDir(homedir+"/dir1", true, true)
+	failError(t, err)
+	assert.False(t, filesystem.ExistsDir(homedir+"/dir1"))
+}
@@ -0,0 +1,65 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegativeEntryCache(t *testing.T) {
+	t.Run("test NegativeEntryCacheSettings", testNegativeEntryCacheSettings)
+}
+
+func testNegativeEntryCacheSettings(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homeDir := "/mockzone/home/alice"
+	incomingDir := homeDir + "/incoming"
+	shortDir := homeDir + "/short"
+
+	for _, dir := range []string{incomingDir, shortDir} {
+		err = mockServer.MakeCollection(dir, "alice")
+		failError(t, err)
+	}
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test",
+		fs.WithCacheTimeout(time.Hour),
+		fs.WithNegativeEntryCacheSettings([]fs.NegativeEntryCacheSetting{
+			{Path: incomingDir, Inherit: true, Disable: true},
+			{Path: shortDir, Inherit: true, Timeout: 100 * time.Millisecond},
+		}),
+	)
+	failError(t, err)
+	defer filesystem.Release()
+
+	paths := []string{homeDir + "/a.txt", incomingDir + "/sub/a.txt", shortDir + "/a.txt"}
+	for _, p := range paths {
+		assert.False(t, filesystem.Exists(p))
+	}
+
+	// created by another client
+	err = mockServer.MakeCollection(incomingDir+"/sub", "alice")
+	failError(t, err)
+
+	for _, p := range paths {
+		err = mockServer.PutDataObject(p, "alice", []byte("data"))
+		failError(t, err)
+	}
+
+	// missing entries are not cached in incoming
+	assert.True(t, filesystem.Exists(incomingDir+"/sub/a.txt"))
+	assert.False(t, filesystem.Exists(shortDir+"/a.txt"))
+	assert.False(t, filesystem.Exists(homeDir+"/a.txt"))
+
+	time.Sleep(200 * time.Millisecond)
+
+	// short timeout expired, the default is still cached
+	assert.True(t, filesystem.Exists(shortDir+"/a.txt"))
+	assert.False(t, filesystem.Exists(homeDir+"/a.txt"))
+}
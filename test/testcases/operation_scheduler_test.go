@@ -0,0 +1,155 @@
+package testcases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationScheduler(t *testing.T) {
+	t.Run("test SchedulerReserve", testSchedulerReserve)
+	t.Run("test SchedulerPriorityOrder", testSchedulerPriorityOrder)
+	t.Run("test SessionOperationScheduling", testSessionOperationScheduling)
+}
+
+func testSchedulerReserve(t *testing.T) {
+	scheduler := session.NewOperationScheduler(2, 1)
+
+	err := scheduler.Acquire(context.Background(), session.OperationPriorityBackground)
+	failError(t, err)
+
+	// the last slot is for interactive operations
+	assert.False(t, scheduler.TryAcquire(session.OperationPriorityBackground))
+	assert.False(t, scheduler.TryAcquire(session.OperationPriorityNormal))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = scheduler.Acquire(ctx, session.OperationPriorityNormal)
+	assert.Error(t, err)
+	assert.Equal(t, 0, scheduler.Waiting())
+
+	assert.True(t, scheduler.TryAcquire(session.OperationPriorityInteractive))
+	assert.Equal(t, 2, scheduler.Running())
+
+	scheduler.Release()
+	scheduler.Release()
+	assert.Equal(t, 0, scheduler.Running())
+}
+
+func testSchedulerPriorityOrder(t *testing.T) {
+	scheduler := session.NewOperationScheduler(1, 0)
+
+	err := scheduler.Acquire(context.Background(), session.OperationPriorityBackground)
+	failError(t, err)
+
+	order := make(chan session.OperationPriority, 3)
+	wait := func(priority session.OperationPriority) {
+		acquireErr := scheduler.Acquire(context.Background(), priority)
+		if acquireErr != nil {
+			return
+		}
+
+		order <- priority
+		scheduler.Release()
+	}
+
+	go wait(session.OperationPriorityBackground)
+	waitForWaiters(t, scheduler, 1)
+	go wait(session.OperationPriorityNormal)
+	waitForWaiters(t, scheduler, 2)
+	go wait(session.OperationPriorityInteractive)
+	waitForWaiters(t, scheduler, 3)
+
+	scheduler.Release()
+
+	assert.Equal(t, session.OperationPriorityInteractive, <-order)
+	assert.Equal(t, session.OperationPriorityNormal, <-order)
+	assert.Equal(t, session.OperationPriorityBackground, <-order)
+}
+
+func waitForWaiters(t *testing.T, scheduler *session.OperationScheduler, waiters int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for scheduler.Waiting() < waiters {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d waiters", waiters)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func testSessionOperationScheduling(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test",
+		session.WithConnectionMax(session.IRODSSessionConnectionMaxMin),
+		session.WithOperationTimeout(100*time.Millisecond),
+		session.WithOperationScheduling(1),
+	)
+	failError(t, err)
+	defer sess.Release()
+
+	connections := []*connection.IRODSConnection{}
+	for i := 0; i < session.IRODSSessionConnectionMaxMin-1; i++ {
+		conn, err := sess.AcquireConnection()
+		failError(t, err)
+		connections = append(connections, conn)
+	}
+
+	// waits instead of sharing a connection in use, the last connection is for interactive operations
+	_, err = sess.AcquireConnection()
+	assert.Error(t, err)
+
+	interactiveConn, err := sess.AcquireConnectionWithPriority(session.OperationPriorityInteractive)
+	failError(t, err)
+	for _, conn := range connections {
+		assert.NotSame(t, conn, interactiveConn)
+	}
+
+	err = sess.ReturnConnection(interactiveConn)
+	failError(t, err)
+
+	// a waiting operation gets the connection returned
+	acquired := make(chan *connection.IRODSConnection, 1)
+	go func() {
+		conn, acquireErr := sess.AcquireConnection()
+		if acquireErr != nil {
+			acquired <- nil
+			return
+		}
+		acquired <- conn
+	}()
+
+	err = sess.ReturnConnection(connections[0])
+	failError(t, err)
+
+	conn := <-acquired
+	if conn == nil {
+		t.Fatal("failed to get the connection returned")
+	}
+	connections[0] = conn
+
+	for _, conn := range connections {
+		err = sess.ReturnConnection(conn)
+		failError(t, err)
+	}
+
+	// parallel operations take free connections only
+	multiConns, err := sess.AcquireConnectionsMulti(session.IRODSSessionConnectionMaxMin)
+	failError(t, err)
+	assert.Len(t, multiConns, session.IRODSSessionConnectionMaxMin-1)
+
+	for _, conn := range multiConns {
+		err = sess.ReturnConnection(conn)
+		failError(t, err)
+	}
+	assert.Equal(t, 0, sess.ConnectionsInUse())
+}
@@ -0,0 +1,231 @@
+package testcases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverwritePolicy(t *testing.T) {
+	t.Run("test UploadOverwritePolicy", testUploadOverwritePolicy)
+	t.Run("test CopyOverwritePolicy", testCopyOverwritePolicy)
+	t.Run("test RenameOverwritePolicy", testRenameOverwritePolicy)
+}
+
+func testUploadOverwritePolicy(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localPath := filepath.Join(t.TempDir(), "file.txt")
+	err = os.WriteFile(localPath, []byte("new content"), 0644)
+	failError(t, err)
+
+	// error is the default
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, nil)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+
+	// rename with suffix
+	result, err := filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyRenameWithSuffix,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/file (1).txt", result.IRODSPath)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "old content", string(data))
+
+	data, err = mockServer.GetDataObject(homedir + "/file (1).txt")
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	// version
+	result, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyVersion,
+	})
+	failError(t, err)
+	assert.Equal(t, targetPath, result.IRODSPath)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	versions, err := filesystem.List(fs.GetVersionsDirPath(targetPath))
+	failError(t, err)
+	assert.Len(t, versions, 1)
+	assert.True(t, strings.HasPrefix(versions[0].Name, "file.txt."))
+
+	data, err = mockServer.GetDataObject(versions[0].Path)
+	failError(t, err)
+	assert.Equal(t, "old content", string(data))
+
+	// overwrite
+	err = os.WriteFile(localPath, []byte("newer content"), 0644)
+	failError(t, err)
+
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	})
+	failError(t, err)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "newer content", string(data))
+}
+
+func testCopyOverwritePolicy(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+	targetPath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("new content"))
+	failError(t, err)
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// error
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, targetPath, &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyError,
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+
+	// rename with suffix
+	copyPath, err := filesystem.CopyFileToFileWithOptions(srcPath, targetPath, &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyRenameWithSuffix,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/file (1).txt", copyPath)
+
+	// a second rename takes the next suffix
+	copyPath, err = filesystem.CopyFileToFileWithOptions(srcPath, targetPath, &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyRenameWithSuffix,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/file (2).txt", copyPath)
+
+	// version
+	copyPath, err = filesystem.CopyFileToFileWithOptions(srcPath, targetPath, &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyVersion,
+	})
+	failError(t, err)
+	assert.Equal(t, targetPath, copyPath)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	versions, err := filesystem.List(fs.GetVersionsDirPath(targetPath))
+	failError(t, err)
+	assert.Len(t, versions, 1)
+
+	// overwrite
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("newer content"))
+	failError(t, err)
+
+	_, err = filesystem.CopyFileToFileWithOptions(srcPath, targetPath, &fs.CopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	})
+	failError(t, err)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "newer content", string(data))
+}
+
+func testRenameOverwritePolicy(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/file.txt"
+
+	for _, name := range []string{"src1.txt", "src2.txt", "src3.txt", "src4.txt"} {
+		err = mockServer.PutDataObject(homedir+"/"+name, "alice", []byte("content of "+name))
+		failError(t, err)
+	}
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// error is the default
+	_, err = filesystem.RenameFileToFileWithOptions(homedir+"/src1.txt", targetPath, nil)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+	assert.True(t, filesystem.ExistsFile(homedir+"/src1.txt"))
+
+	// rename with suffix
+	newPath, err := filesystem.RenameFileToFileWithOptions(homedir+"/src1.txt", targetPath, &fs.RenameFileOptions{
+		OverwritePolicy: fs.OverwritePolicyRenameWithSuffix,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/file (1).txt", newPath)
+	assert.False(t, filesystem.ExistsFile(homedir+"/src1.txt"))
+
+	// version
+	newPath, err = filesystem.RenameFileToFileWithOptions(homedir+"/src2.txt", targetPath, &fs.RenameFileOptions{
+		OverwritePolicy: fs.OverwritePolicyVersion,
+	})
+	failError(t, err)
+	assert.Equal(t, targetPath, newPath)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "content of src2.txt", string(data))
+
+	versions, err := filesystem.List(fs.GetVersionsDirPath(targetPath))
+	failError(t, err)
+	assert.Len(t, versions, 1)
+
+	// overwrite
+	_, err = filesystem.RenameFileToFileWithOptions(homedir+"/src3.txt", targetPath, &fs.RenameFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	})
+	failError(t, err)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "content of src3.txt", string(data))
+
+	// unknown policy
+	_, err = filesystem.RenameFileToFileWithOptions(homedir+"/src4.txt", targetPath, &fs.RenameFileOptions{
+		OverwritePolicy: "unknown",
+	})
+	assert.Error(t, err)
+	assert.True(t, filesystem.ExistsFile(homedir+"/src4.txt"))
+}
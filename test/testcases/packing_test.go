@@ -0,0 +1,108 @@
+package testcases
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacking(t *testing.T) {
+	t.Run("test MarshalIRODSMessage", testMarshalIRODSMessage)
+	t.Run("test UnmarshalIRODSMessage", testUnmarshalIRODSMessage)
+	t.Run("test PackingInstructionTag", testPackingInstructionTag)
+	t.Run("test InvalidPackingTag", testInvalidPackingTag)
+}
+
+type testTaggedRequest struct {
+	XMLName xml.Name `xml:"endTransactionInp_PI"`
+	Action  string   `xml:"arg0"`
+	Arg     string   `xml:"arg1"`
+	Data    []byte   `xml:"-" irods:"bs"`
+}
+
+type testTaggedResponse struct {
+	XMLName xml.Name `xml:"testTaggedOut_PI" irods:"pi=STR_PI"`
+	Value   string   `xml:"myStr"`
+	Result  int      `xml:"-" irods:"intinfo"`
+	Data    []byte   `xml:"-" irods:"bs"`
+}
+
+type testInvalidTaggedResponse struct {
+	Result string `irods:"intinfo"`
+}
+
+func testMarshalIRODSMessage(t *testing.T) {
+	request := testTaggedRequest{
+		Action: "commit",
+		Data:   []byte("binary stream"),
+	}
+
+	msg, err := message.MarshalIRODSMessage(&request, common.END_TRANSACTION_AN)
+	failError(t, err)
+
+	assert.Equal(t, message.RODS_MESSAGE_API_REQ_TYPE, msg.Body.Type)
+	assert.Equal(t, int32(common.END_TRANSACTION_AN), msg.Body.IntInfo)
+	assert.Equal(t, "<endTransactionInp_PI><arg0>commit</arg0><arg1></arg1></endTransactionInp_PI>", string(msg.Body.Message))
+	assert.Equal(t, []byte("binary stream"), msg.Body.Bs)
+	assert.Equal(t, uint32(len(msg.Body.Message)), msg.Header.MessageLen)
+	assert.Equal(t, uint32(len(request.Data)), msg.Header.BsLen)
+
+	// existing messages are built with the same framework
+	endTransaction := message.NewIRODSMessageEndTransactionRequest(true)
+	msg2, err := endTransaction.GetMessage()
+	failError(t, err)
+	assert.Equal(t, msg.Body.Message, msg2.Body.Message)
+	assert.Nil(t, msg2.Body.Bs)
+}
+
+func testUnmarshalIRODSMessage(t *testing.T) {
+	msgBody := message.IRODSMessageBody{
+		Type:    message.RODS_MESSAGE_API_REPLY_TYPE,
+		Message: []byte("<testTaggedOut_PI><myStr>hello</myStr></testTaggedOut_PI>"),
+		Bs:      []byte("data"),
+		IntInfo: 7,
+	}
+	msgIn := message.IRODSMessage{
+		Body: &msgBody,
+	}
+
+	response := testTaggedResponse{}
+	err := message.UnmarshalIRODSMessage(&msgIn, &response)
+	failError(t, err)
+	assert.Equal(t, "hello", response.Value)
+	assert.Equal(t, 7, response.Result)
+	assert.Equal(t, []byte("data"), response.Data)
+
+	// error response without message
+	msgBody = message.IRODSMessageBody{
+		Type:    message.RODS_MESSAGE_API_REPLY_TYPE,
+		IntInfo: int32(common.CAT_NO_ROWS_FOUND),
+	}
+
+	queryResponse := message.IRODSMessageQueryResponse{}
+	err = queryResponse.FromMessage(&msgIn)
+	failError(t, err)
+	assert.Equal(t, int(common.CAT_NO_ROWS_FOUND), queryResponse.Result)
+	assert.Error(t, queryResponse.CheckError())
+
+	err = message.UnmarshalIRODSMessage(&message.IRODSMessage{}, &response)
+	assert.Error(t, err)
+}
+
+func testPackingInstructionTag(t *testing.T) {
+	assert.Equal(t, "STR_PI", message.GetPackingInstructionName(&testTaggedResponse{}))
+	assert.Equal(t, "GenQueryOut_PI", message.GetPackingInstructionName(&message.IRODSMessageQueryResponse{}))
+	assert.Equal(t, "PortalOprOut_PI", message.GetPackingInstructionName(&message.IRODSMessageGetDataObjectResponse{}))
+	assert.Equal(t, "", message.GetPackingInstructionName(&message.IRODSMessageModifyMetadataResponse{}))
+}
+
+func testInvalidPackingTag(t *testing.T) {
+	_, err := message.MarshalIRODSMessage(&testInvalidTaggedResponse{}, common.END_TRANSACTION_AN)
+	assert.Error(t, err)
+
+	_, err = message.MarshalIRODSMessage(testTaggedRequest{}, common.END_TRANSACTION_AN)
+	assert.Error(t, err)
+}
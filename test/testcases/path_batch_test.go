@@ -0,0 +1,135 @@
+package testcases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathBatch(t *testing.T) {
+	t.Run("test ExistsBatch", testExistsBatch)
+	t.Run("test AccessBatch", testAccessBatch)
+}
+
+func testExistsBatch(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	err = mockServer.MakeCollection(homedir+"/dir", "alice")
+	failError(t, err)
+
+	// more paths than a query takes at once
+	paths := []string{homedir, homedir + "/dir", "/mockzone/home/public", homedir + "/nodir/file.txt"}
+	for i := 0; i < irods_fs.PathBatchSizeMax+50; i++ {
+		filePath := fmt.Sprintf("%s/file%03d.txt", homedir, i)
+		if i%2 == 0 {
+			err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+			failError(t, err)
+		}
+		paths = append(paths, filePath)
+	}
+
+	// duplicates
+	paths = append(paths, homedir+"/file000.txt", homedir+"/file001.txt")
+
+	exists, err := filesystem.ExistsBatch(paths)
+	failError(t, err)
+	assert.Len(t, exists, len(paths)-2)
+
+	for _, p := range paths {
+		assert.Equal(t, filesystem.Exists(p), exists[p], p)
+	}
+
+	assert.True(t, exists[homedir+"/dir"])
+	assert.True(t, exists[homedir+"/file000.txt"])
+	assert.False(t, exists[homedir+"/file001.txt"])
+	assert.False(t, exists[homedir+"/nodir/file.txt"])
+
+	// from cache
+	exists, err = filesystem.ExistsBatch([]string{homedir + "/file000.txt", homedir + "/file001.txt"})
+	failError(t, err)
+	assert.True(t, exists[homedir+"/file000.txt"])
+	assert.False(t, exists[homedir+"/file001.txt"])
+}
+
+func testAccessBatch(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("team", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+	err = mockServer.AddGroupMember("team", "alice")
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	sharedDir := "/mockzone/home/public/shared"
+	err = mockServer.MakeCollection(sharedDir, "rods")
+	failError(t, err)
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+
+	paths := []string{sharedDir, sharedDir + "/new.txt", "/mockzone/home/alice", "/mockzone/home/alice/new.txt", "/mockzone/nodir/new.txt"}
+	for i := 0; i < irods_fs.PathBatchSizeMax+50; i++ {
+		filePath := fmt.Sprintf("%s/file%03d.txt", sharedDir, i)
+		err = mockServer.PutDataObject(filePath, "rods", []byte("shared content"))
+		failError(t, err)
+
+		switch i % 3 {
+		case 1:
+			err = irods_fs.ChangeDataObjectAccess(conn, filePath, types.IRODSAccessLevelReadObject, "team", "mockzone", false)
+			failError(t, err)
+		case 2:
+			err = irods_fs.ChangeDataObjectAccess(conn, filePath, types.IRODSAccessLevelModifyObject, "alice", "mockzone", false)
+			failError(t, err)
+		}
+
+		paths = append(paths, filePath)
+	}
+	filesystem.ReturnMetadataConnection(conn)
+
+	accessLevels, err := filesystem.AccessBatch(paths, "alice")
+	failError(t, err)
+	assert.Len(t, accessLevels, len(paths))
+
+	for _, p := range paths {
+		if p == "/mockzone/nodir/new.txt" {
+			// Access fails as the parent dir does not exist
+			continue
+		}
+
+		accessLevel, err := filesystem.Access(p, "alice")
+		failError(t, err)
+		assert.Equal(t, accessLevel, accessLevels[p], p)
+	}
+
+	assert.Equal(t, types.IRODSAccessLevelNull, accessLevels[sharedDir+"/file000.txt"])
+	assert.Equal(t, types.IRODSAccessLevelReadObject, accessLevels[sharedDir+"/file001.txt"])
+	assert.Equal(t, types.IRODSAccessLevelModifyObject, accessLevels[sharedDir+"/file002.txt"])
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels["/mockzone/home/alice/new.txt"])
+
+	// parent dir not existing
+	assert.Equal(t, types.IRODSAccessLevelNull, accessLevels["/mockzone/nodir/new.txt"])
+
+	accessLevels, err = filesystem.AccessBatch([]string{sharedDir + "/file000.txt"}, "rods")
+	failError(t, err)
+	assert.Equal(t, types.IRODSAccessLevelOwner, accessLevels[sharedDir+"/file000.txt"])
+}
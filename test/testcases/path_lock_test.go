@@ -0,0 +1,161 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathLock(t *testing.T) {
+	t.Run("test FileLocks", testFileLocks)
+	t.Run("test ConcurrentMakeDir", testConcurrentMakeDir)
+	t.Run("test ConcurrentStatAndRename", testConcurrentStatAndRename)
+}
+
+func testFileLocks(t *testing.T) {
+	locks := fs.NewFileLocks()
+
+	lockedPaths := locks.LockFiles([]string{"/zone/b", "/zone/a", "/zone/b"})
+	assert.Equal(t, []string{"/zone/a", "/zone/b"}, lockedPaths)
+
+	acquired := make(chan bool)
+	go func() {
+		locks.RLock("/zone/a")
+		acquired <- true
+		locks.RUnlock("/zone/a")
+	}()
+
+	select {
+	case <-acquired:
+		assert.Fail(t, "read lock must wait for write lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	err := locks.UnlockFiles(lockedPaths)
+	failError(t, err)
+
+	select {
+	case <-acquired:
+	case <-time.After(1 * time.Second):
+		assert.Fail(t, "read lock must be acquired after unlock")
+	}
+
+	// opposite order must not deadlock
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+
+			paths := []string{"/zone/a", "/zone/b"}
+			if idx%2 == 0 {
+				paths = []string{"/zone/b", "/zone/a"}
+			}
+
+			locked := locks.LockFiles(paths)
+			locks.UnlockFiles(locked)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func testConcurrentMakeDir(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	newdir := homedir + "/concurrent"
+
+	// list once to populate dir cache
+	_, err = filesystem.List(homedir)
+	failError(t, err)
+
+	workers := 10
+	errs := make([]error, workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			errs[idx] = filesystem.MakeDir(newdir, false)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.True(t, types.IsFileAlreadyExistError(err), fmt.Sprintf("unexpected error %v", err))
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, newdir, entries[0].Path)
+}
+
+func testConcurrentStatAndRename(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	handle, err := filesystem.CreateFile(homedir+"/file0.txt", "", "w")
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	renames := 10
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < renames; i++ {
+			err := filesystem.RenameFileToFile(fmt.Sprintf("%s/file%d.txt", homedir, i), fmt.Sprintf("%s/file%d.txt", homedir, i+1))
+			assert.NoError(t, err)
+		}
+		close(done)
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			for i := 0; i <= renames; i++ {
+				filesystem.Stat(fmt.Sprintf("%s/file%d.txt", homedir, i))
+			}
+		}
+	}
+
+	// cache must agree with the server after concurrent stat and rename
+	for i := 0; i < renames; i++ {
+		assert.False(t, filesystem.ExistsFile(fmt.Sprintf("%s/file%d.txt", homedir, i)))
+	}
+	assert.True(t, filesystem.ExistsFile(fmt.Sprintf("%s/file%d.txt", homedir, renames)))
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+}
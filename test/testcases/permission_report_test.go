@@ -0,0 +1,75 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionReport(t *testing.T) {
+	t.Run("test GetPermissionReport", testGetPermissionReport)
+}
+
+func testGetPermissionReport(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	rootPath := homedir + "/project"
+
+	err = mockServer.MakeCollection(rootPath+"/sub/deep", "alice")
+	failError(t, err)
+	for _, p := range []string{rootPath + "/a.txt", rootPath + "/sub/b.txt", rootPath + "/sub/deep/c.txt"} {
+		err = mockServer.PutDataObject(p, "alice", []byte("content"))
+		failError(t, err)
+	}
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	conn, err := filesystem.GetMetadataConnection()
+	failError(t, err)
+	err = irods_fs.ChangeCollectionAccess(conn, rootPath+"/sub", types.IRODSAccessLevelReadObject, "rods", "mockzone", false, false)
+	failError(t, err)
+	err = irods_fs.ChangeDataObjectAccess(conn, rootPath+"/sub/deep/c.txt", types.IRODSAccessLevelModifyObject, "rods", "mockzone", false)
+	failError(t, err)
+	filesystem.ReturnMetadataConnection(conn)
+
+	report, err := filesystem.GetPermissionReport(rootPath)
+	failError(t, err)
+	assert.Equal(t, rootPath, report.RootPath)
+
+	paths := []string{}
+	for _, entry := range report.Entries {
+		paths = append(paths, entry.Path)
+		assert.NotEmpty(t, entry.Accesses)
+	}
+	assert.Equal(t, []string{
+		rootPath,
+		rootPath + "/a.txt",
+		rootPath + "/sub",
+		rootPath + "/sub/b.txt",
+		rootPath + "/sub/deep",
+		rootPath + "/sub/deep/c.txt",
+	}, paths)
+
+	assert.Equal(t, fs.DirectoryEntry, report.Entries[2].Type)
+	assert.Equal(t, fs.FileEntry, report.Entries[5].Type)
+
+	byUser := report.GetAccessesByUser()
+	assert.Len(t, byUser["alice#mockzone"], 6)
+
+	rodsAccesses := byUser["rods#mockzone"]
+	assert.Len(t, rodsAccesses, 2)
+	assert.Equal(t, rootPath+"/sub", rodsAccesses[0].Path)
+	assert.Equal(t, types.IRODSAccessLevelReadObject, rodsAccesses[0].AccessLevel)
+	assert.Equal(t, rootPath+"/sub/deep/c.txt", rodsAccesses[1].Path)
+	assert.Equal(t, types.IRODSAccessLevelModifyObject, rodsAccesses[1].AccessLevel)
+}
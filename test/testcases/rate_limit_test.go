@@ -0,0 +1,69 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("test RateLimiter", testRateLimiter)
+	t.Run("test RateLimiterBurst", testRateLimiterBurst)
+	t.Run("test FileSystemRequestRateLimit", testFileSystemRequestRateLimit)
+}
+
+func testRateLimiter(t *testing.T) {
+	limiter := util.NewRateLimiter(50, 1)
+
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		limiter.Wait()
+	}
+
+	// first is allowed immediately, others wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+}
+
+func testRateLimiterBurst(t *testing.T) {
+	limiter := util.NewRateLimiter(1, 5)
+	assert.Equal(t, 5, limiter.GetBurst())
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		limiter.Wait()
+	}
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// no limit
+	limiter = util.NewRateLimiter(0, 1)
+
+	start = time.Now()
+	for i := 0; i < 100; i++ {
+		limiter.Wait()
+	}
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func testFileSystemRequestRateLimit(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache(), fs.WithRequestRateLimit(20, 1))
+	failError(t, err)
+	defer filesystem.Release()
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		_, err = filesystem.Stat("/mockzone/home/alice")
+		failError(t, err)
+	}
+
+	// at least 10 requests at 20 requests per second
+	assert.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+}
@@ -0,0 +1,192 @@
+package testcases
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawRequest(t *testing.T) {
+	t.Run("test RequestRaw", testRequestRaw)
+	t.Run("test RequestRawNative", testRequestRawNative)
+	t.Run("test RequestRawWithBs", testRequestRawWithBs)
+	t.Run("test RegisterPackingInstruction", testRegisterPackingInstruction)
+	t.Run("test CustomMessageNative", testCustomMessageNative)
+}
+
+// customEndTransactionRequest is a user-defined message type that is not in the message package
+type customEndTransactionRequest struct {
+	XMLName  xml.Name `xml:"customEndTransactionInp_PI"`
+	Action   string   `xml:"arg0"`
+	Argument string   `xml:"arg1"`
+}
+
+func (msg *customEndTransactionRequest) GetMessage() (*message.IRODSMessage, error) {
+	bytes, err := xml.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return message.NewIRODSMessageRawRequest(common.END_TRANSACTION_AN, bytes, nil).GetMessage()
+}
+
+func connectMockServer(t *testing.T, account *types.IRODSAccount) *connection.IRODSConnection {
+	conn := connection.NewIRODSConnection(account, 30*time.Second, "go-irodsclient-test")
+	err := conn.Connect()
+	failError(t, err)
+	return conn
+}
+
+func testRequestRaw(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	conn := connectMockServer(t, account)
+	defer conn.Disconnect()
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	response, err := conn.RequestRaw(common.END_TRANSACTION_AN, []byte("<endTransactionInp_PI><arg0>commit</arg0><arg1></arg1></endTransactionInp_PI>"), nil, nil)
+	failError(t, err)
+	assert.Equal(t, 0, response.Result)
+	assert.NoError(t, response.CheckError())
+
+	// an API that the server does not know
+	response, err = conn.RequestRaw(common.APINumber(99999), nil, nil, nil)
+	failError(t, err)
+	assert.Error(t, response.CheckError())
+	assert.Equal(t, common.SYS_UNMATCHED_API_NUM, types.GetIRODSErrorCode(response.CheckError()))
+}
+
+func testRequestRawNative(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+	account.Protocol = types.IRODSProtocolNative
+
+	conn := connectMockServer(t, account)
+	defer conn.Disconnect()
+
+	conn.Lock()
+	defer conn.Unlock()
+	assert.True(t, conn.IsNativeProtocol())
+
+	packedInput, err := message.ConvertXMLToNative([]byte("<endTransactionInp_PI><arg0>commit</arg0><arg1></arg1></endTransactionInp_PI>"))
+	failError(t, err)
+
+	response, err := conn.RequestRaw(common.END_TRANSACTION_AN, packedInput, nil, nil)
+	failError(t, err)
+	assert.NoError(t, response.CheckError())
+}
+
+func testRequestRawWithBs(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	path := "/mockzone/home/alice/raw.txt"
+	err = mockServer.PutDataObject(path, "alice", []byte{})
+	failError(t, err)
+
+	conn := connectMockServer(t, account)
+	defer conn.Disconnect()
+
+	data := []byte("hello raw")
+
+	// data to write is sent in the byte stream of the request
+	handle, _, err := irods_fs.OpenDataObject(conn, path, "", "w")
+	failError(t, err)
+
+	packedInput, err := xml.Marshal(message.NewIRODSMessageWriteDataObjectRequest(handle.FileDescriptor, data).IRODSMessageOpenedDataObjectRequest)
+	failError(t, err)
+
+	conn.Lock()
+	response, err := conn.RequestRaw(common.DATA_OBJ_WRITE_AN, packedInput, data, nil)
+	conn.Unlock()
+	failError(t, err)
+	failError(t, response.CheckError())
+	assert.Equal(t, len(data), response.Result)
+
+	err = irods_fs.CloseDataObject(conn, handle)
+	failError(t, err)
+
+	// data read is returned in the byte stream of the response
+	handle, _, err = irods_fs.OpenDataObject(conn, path, "", "r")
+	failError(t, err)
+
+	packedInput, err = xml.Marshal(message.NewIRODSMessageReadDataObjectRequest(handle.FileDescriptor, len(data)))
+	failError(t, err)
+
+	responseBsBuffer := make([]byte, len(data))
+	conn.Lock()
+	response, err = conn.RequestRaw(common.DATA_OBJ_READ_AN, packedInput, nil, responseBsBuffer)
+	conn.Unlock()
+	failError(t, err)
+	failError(t, response.CheckError())
+	assert.Equal(t, len(data), response.Result)
+	assert.Equal(t, data, response.Bs[:response.Result])
+
+	err = irods_fs.CloseDataObject(conn, handle)
+	failError(t, err)
+}
+
+func testRegisterPackingInstruction(t *testing.T) {
+	err := message.RegisterPackingInstruction("testCustomInp_PI", "str *path; int flags; struct KeyValPair_PI;")
+	failError(t, err)
+	assert.True(t, message.HasPackingInstruction("testCustomInp_PI"))
+
+	xmlString := "<testCustomInp_PI><path>/mockzone/home/alice</path><flags>3</flags><KeyValPair_PI><ssLen>1</ssLen><keyWord>forceFlag</keyWord><svalue></svalue></KeyValPair_PI></testCustomInp_PI>"
+	nativeBytes, err := message.ConvertXMLToNative([]byte(xmlString))
+	failError(t, err)
+
+	xmlBytes, err := message.ConvertNativeToXML(nativeBytes, "testCustomInp_PI")
+	failError(t, err)
+	assert.Equal(t, xmlString, string(xmlBytes))
+
+	err = message.RegisterPackingInstruction("testInvalidInp_PI", "float value;")
+	assert.Error(t, err)
+	assert.False(t, message.HasPackingInstruction("testInvalidInp_PI"))
+
+	err = message.RegisterPackingInstruction("testInvalidInp_PI", "str *value[NAME_LEN;")
+	assert.Error(t, err)
+}
+
+func testCustomMessageNative(t *testing.T) {
+	err := message.RegisterPackingInstruction("customEndTransactionInp_PI", "str *arg0; str *arg1;")
+	failError(t, err)
+
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+	account.Protocol = types.IRODSProtocolNative
+
+	conn := connectMockServer(t, account)
+	defer conn.Disconnect()
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	request := &customEndTransactionRequest{
+		Action: "commit",
+	}
+	response := message.IRODSMessageRawResponse{}
+	err = conn.RequestAndCheck(request, &response, nil)
+	failError(t, err)
+}
@@ -0,0 +1,104 @@
+package testcases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveDir(t *testing.T) {
+	t.Run("test RemoveDirAsync", testRemoveDirAsync)
+	t.Run("test RemoveDirCancel", testRemoveDirCancel)
+}
+
+func makeRemoveDirTestTree(t *testing.T, mockServer *mock.IRODSMockServer, dirPath string, fileNum int) {
+	err := mockServer.MakeCollection(dirPath, "alice")
+	failError(t, err)
+	err = mockServer.MakeCollection(dirPath+"/sub", "alice")
+	failError(t, err)
+
+	for i := 0; i < fileNum; i++ {
+		p := fmt.Sprintf("%s/file%d.txt", dirPath, i)
+		if i%2 == 1 {
+			p = fmt.Sprintf("%s/sub/file%d.txt", dirPath, i)
+		}
+
+		err = mockServer.PutDataObject(p, "alice", []byte("content"))
+		failError(t, err)
+	}
+}
+
+func testRemoveDirAsync(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	dirPath := homedir + "/dir"
+	makeRemoveDirTestTree(t, mockServer, dirPath, 10)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	progress := []int64{}
+	result := filesystem.RemoveDirAsync(context.Background(), dirPath, true, func(processed int64, total int64) {
+		assert.Equal(t, int64(10), total)
+		progress = append(progress, processed)
+	})
+
+	err = <-result
+	failError(t, err)
+
+	assert.Len(t, progress, 11)
+	assert.Equal(t, int64(10), progress[len(progress)-1])
+	assert.False(t, filesystem.ExistsDir(dirPath))
+	assert.False(t, filesystem.ExistsDir(dirPath+"/sub"))
+}
+
+func testRemoveDirCancel(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	dirPath := homedir + "/dir"
+	makeRemoveDirTestTree(t, mockServer, dirPath, 10)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = filesystem.RemoveDirWithContext(ctx, dirPath, true, func(processed int64, total int64) {
+		if processed == 3 {
+			cancel()
+		}
+	})
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.True(t, filesystem.ExistsDir(dirPath))
+
+	remaining := 0
+	for _, p := range []string{dirPath, dirPath + "/sub"} {
+		entries, err := filesystem.List(p)
+		failError(t, err)
+		for _, entry := range entries {
+			if entry.Type == fs.FileEntry {
+				remaining++
+			}
+		}
+	}
+	assert.Equal(t, 7, remaining)
+}
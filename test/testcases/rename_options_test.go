@@ -0,0 +1,149 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameOptions(t *testing.T) {
+	t.Run("test RenameForce", testRenameForce)
+	t.Run("test RenameToResource", testRenameToResource)
+	t.Run("test RenameAcrossZones", testRenameAcrossZones)
+}
+
+func testRenameForce(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+	targetPath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("new content"))
+	failError(t, err)
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+	err = mockServer.PutDataObject(homedir+"/src2.txt", "alice", []byte("new content"))
+	failError(t, err)
+	err = mockServer.MakeCollection(homedir+"/dir", "alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// cache the dir entries
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 4)
+
+	// the server refuses without force
+	err = filesystem.RenameFileToFile(srcPath, targetPath)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+
+	newPath, err := filesystem.RenameFileToFileWithOptions(srcPath, targetPath, &fs.RenameFileOptions{
+		Force: true,
+	})
+	failError(t, err)
+	assert.Equal(t, targetPath, newPath)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	assert.False(t, filesystem.ExistsFile(srcPath))
+
+	entry, err := filesystem.Stat(targetPath)
+	failError(t, err)
+	assert.Equal(t, int64(11), entry.Size)
+
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 3)
+
+	// a dir is not replaced
+	_, err = filesystem.RenameFileToFileWithOptions(homedir+"/src2.txt", homedir+"/dir", &fs.RenameFileOptions{
+		Force: true,
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+	assert.True(t, filesystem.ExistsDir(homedir+"/dir"))
+	assert.True(t, filesystem.ExistsFile(homedir+"/src2.txt"))
+}
+
+func testRenameToResource(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("otherResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// the file is not in the resource, the server refuses a cross-device rename
+	_, err = filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", &fs.RenameFileOptions{
+		Resource: "otherResc",
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsRenameNotSupportedError(err))
+	assert.True(t, filesystem.ExistsFile(srcPath))
+
+	// unknown resource is not a cross-device rename
+	_, err = filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", &fs.RenameFileOptions{
+		Resource: "no_such_resc",
+	})
+	assert.Error(t, err)
+	assert.False(t, types.IsRenameNotSupportedError(err))
+
+	newPath, err := filesystem.RenameFileToFileWithOptions(srcPath, homedir+"/renamed.txt", &fs.RenameFileOptions{
+		Resource: mock.MockResourceName,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/renamed.txt", newPath)
+	assert.False(t, filesystem.ExistsFile(srcPath))
+	assert.True(t, filesystem.ExistsFile(newPath))
+}
+
+func testRenameAcrossZones(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddRemoteZone("remotezone")
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/src.txt"
+
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.RenameFileToFile(srcPath, "/remotezone/home/src.txt")
+	assert.Error(t, err)
+	assert.True(t, types.IsRenameNotSupportedError(err))
+	assert.True(t, filesystem.ExistsFile(srcPath))
+}
@@ -0,0 +1,478 @@
+package testcases
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResource(t *testing.T) {
+	t.Run("test ResourceMetadata", testResourceMetadata)
+	t.Run("test SearchResourcesByMeta", testSearchResourcesByMeta)
+	t.Run("test GetAvailableSpace", testGetAvailableSpace)
+	t.Run("test ReplicateFileToResources", testReplicateFileToResources)
+	t.Run("test MoveReplica", testMoveReplica)
+	t.Run("test StatStaleReplicas", testStatStaleReplicas)
+	t.Run("test ListWithReplicas", testListWithReplicas)
+	t.Run("test ListFilteredByReplica", testListFilteredByReplica)
+	t.Run("test SelectReadReplica", testSelectReadReplica)
+}
+
+func testResourceMetadata(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.AddResourceMetadata(mock.MockResourceName, "tier", "archive", "")
+	failError(t, err)
+	err = filesystem.AddResourceMetadata(mock.MockResourceName, "site", "tucson", "")
+	failError(t, err)
+
+	metas, err := filesystem.ListResourceMetadata(mock.MockResourceName)
+	failError(t, err)
+	assert.Len(t, metas, 2)
+
+	err = filesystem.DeleteResourceMetadataByName(mock.MockResourceName, "site")
+	failError(t, err)
+
+	metas, err = filesystem.ListResourceMetadata(mock.MockResourceName)
+	failError(t, err)
+	assert.Len(t, metas, 1)
+	assert.Equal(t, "tier", metas[0].Name)
+	assert.Equal(t, "archive", metas[0].Value)
+
+	err = filesystem.DeleteResourceMetadata(mock.MockResourceName, metas[0].AVUID)
+	failError(t, err)
+
+	metas, err = filesystem.ListResourceMetadata(mock.MockResourceName)
+	failError(t, err)
+	assert.Len(t, metas, 0)
+
+	err = filesystem.AddResourceMetadata("no_such_resc", "tier", "archive", "")
+	assert.Error(t, err)
+}
+
+func testSearchResourcesByMeta(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.AddResourceMetadata(mock.MockResourceName, "tier", "archive", "")
+	failError(t, err)
+
+	resources, err := filesystem.SearchResourcesByMeta("tier", "archive")
+	failError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Equal(t, mock.MockResourceName, resources[0].Name)
+	assert.Equal(t, "mockzone", resources[0].Zone)
+
+	resources, err = filesystem.SearchResourcesByMetaWildcard("tier", "arch%")
+	failError(t, err)
+	assert.Len(t, resources, 1)
+
+	resources, err = filesystem.SearchResourcesByMeta("tier", "hot")
+	failError(t, err)
+	assert.Len(t, resources, 0)
+}
+
+func testGetAvailableSpace(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	// not reported
+	space, err := filesystem.GetAvailableSpace("")
+	failError(t, err)
+	assert.Equal(t, mock.MockResourceName, space.Name)
+	assert.Equal(t, int64(-1), space.FreeSpace)
+	assert.Equal(t, int64(-1), space.Capacity)
+	assert.True(t, space.FreeSpaceTime.IsZero())
+	assert.False(t, space.IsDown())
+	assert.True(t, space.HasSpaceFor(1024))
+
+	mockServer.SetResourceSpace(1000, 4000, types.IRODSResourceStatusUp)
+
+	space, err = filesystem.GetAvailableSpace(mock.MockResourceName)
+	failError(t, err)
+	assert.Equal(t, int64(1000), space.FreeSpace)
+	assert.Equal(t, int64(4000), space.Capacity)
+	assert.Equal(t, types.IRODSResourceStatusUp, space.Status)
+	assert.False(t, space.FreeSpaceTime.IsZero())
+	assert.True(t, space.HasSpaceFor(1000))
+	assert.False(t, space.HasSpaceFor(1001))
+
+	mockServer.SetResourceSpace(0, -1, types.IRODSResourceStatusDown)
+
+	space, err = filesystem.GetAvailableSpace(mock.MockResourceName)
+	failError(t, err)
+	assert.Equal(t, int64(0), space.FreeSpace)
+	assert.Equal(t, int64(-1), space.Capacity)
+	assert.True(t, space.IsDown())
+
+	_, err = filesystem.GetAvailableSpace("no_such_resc")
+	assert.Error(t, err)
+}
+
+func testReplicateFileToResources(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("replResc1")
+	mockServer.AddResource("replResc2")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	filePath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	err = filesystem.ReplicateFileToResources(filePath, []string{"replResc1", "replResc2", "replResc1"}, false)
+	failError(t, err)
+
+	replicas, err := mockServer.GetReplicaResources(filePath)
+	failError(t, err)
+	assert.Equal(t, []string{mock.MockResourceName, "replResc1", "replResc2"}, replicas)
+
+	// replicates to others when it fails for some
+	mockServer.AddResource("replResc3")
+	err = filesystem.ReplicateFileToResources(filePath, []string{"noResc", "replResc3", "badResc"}, false)
+	assert.Error(t, err)
+	assert.True(t, types.IsReplicationError(err))
+
+	var replErr *types.ReplicationError
+	assert.True(t, errors.As(err, &replErr))
+	assert.Equal(t, []string{"noResc", "badResc"}, replErr.GetFailedResources())
+	assert.Equal(t, common.SYS_RESC_DOES_NOT_EXIST, types.GetIRODSErrorCode(replErr.Errors["noResc"]))
+
+	replicas, err = mockServer.GetReplicaResources(filePath)
+	failError(t, err)
+	assert.Contains(t, replicas, "replResc3")
+
+	err = filesystem.ReplicateFileToResources(filePath, nil, false)
+	assert.Error(t, err)
+}
+
+func testMoveReplica(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("tapeResc")
+	mockServer.AddResource("diskResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	filePath := "/mockzone/home/alice/file.txt"
+	data := []byte("hello world")
+	err = mockServer.PutDataObject(filePath, "alice", data)
+	failError(t, err)
+
+	err = filesystem.MoveReplica(filePath, mock.MockResourceName, "tapeResc")
+	failError(t, err)
+
+	replicas, err := mockServer.GetReplicaResources(filePath)
+	failError(t, err)
+	assert.Equal(t, []string{"tapeResc"}, replicas)
+
+	// path and content are kept
+	stored, err := mockServer.GetDataObject(filePath)
+	failError(t, err)
+	assert.Equal(t, data, stored)
+	assert.True(t, filesystem.ExistsFile(filePath))
+
+	// no replica on the source
+	err = filesystem.MoveReplica(filePath, mock.MockResourceName, "diskResc")
+	assert.Error(t, err)
+	assert.Equal(t, common.SYS_REPLICA_DOES_NOT_EXIST, types.GetIRODSErrorCode(err))
+
+	// dest not existing
+	err = filesystem.MoveReplica(filePath, "tapeResc", "noResc")
+	assert.Error(t, err)
+
+	err = filesystem.MoveReplica("/mockzone/home/alice/nofile.txt", "tapeResc", "diskResc")
+	assert.Error(t, err)
+
+	replicas, err = mockServer.GetReplicaResources(filePath)
+	failError(t, err)
+	assert.Equal(t, []string{"tapeResc"}, replicas)
+}
+
+func testStatStaleReplicas(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("diskResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	filePath := homedir + "/file.txt"
+	data := []byte("hello world")
+	err = mockServer.PutDataObject(filePath, "alice", data)
+	failError(t, err)
+
+	err = filesystem.ReplicateFileToResources(filePath, []string{"diskResc"}, false)
+	failError(t, err)
+
+	// the first replica is stale, the good one is used
+	err = mockServer.SetReplicaStale(filePath, mock.MockResourceName, 5)
+	failError(t, err)
+
+	entry, err := filesystem.Stat(filePath)
+	failError(t, err)
+	assert.Equal(t, int64(len(data)), entry.Size)
+	assert.False(t, entry.OnlyStaleReplicas)
+	// counted from all replicas, not only the master replica
+	assert.Equal(t, 2, entry.ReplicaCount)
+	assert.True(t, entry.HasStaleReplica)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, int64(len(data)), entries[0].Size)
+		assert.Equal(t, 2, entries[0].ReplicaCount)
+		assert.True(t, entries[0].HasStaleReplica)
+		assert.False(t, entries[0].OnlyStaleReplicas)
+	}
+
+	// all replicas are stale, the file is still found
+	err = mockServer.SetReplicaStale(filePath, "diskResc", 3)
+	failError(t, err)
+
+	entry, err = filesystem.Stat(filePath)
+	failError(t, err)
+	assert.Equal(t, int64(5), entry.Size)
+	assert.True(t, entry.OnlyStaleReplicas)
+	assert.True(t, entry.HasStaleReplica)
+	assert.Equal(t, 2, entry.ReplicaCount)
+
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+
+	found := false
+	for _, e := range entries {
+		if e.Path == filePath {
+			found = true
+			assert.True(t, e.OnlyStaleReplicas)
+		}
+	}
+	assert.True(t, found)
+}
+
+func testListWithReplicas(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("diskResc")
+	mockServer.AddResource("tapeResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	filePath := homedir + "/file.txt"
+	err = mockServer.PutDataObject(filePath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	err = filesystem.MakeDir(homedir+"/dir", false)
+	failError(t, err)
+
+	err = filesystem.ReplicateFileToResources(filePath, []string{"diskResc", "tapeResc"}, false)
+	failError(t, err)
+
+	err = mockServer.SetReplicaStale(filePath, mock.MockResourceName, 5)
+	failError(t, err)
+
+	entries, err := filesystem.ListWithOptions(homedir, &fs.ListOptions{WithReplicas: true})
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		if entry.Type == fs.DirectoryEntry {
+			assert.Empty(t, entry.Replicas)
+			continue
+		}
+
+		assert.Equal(t, filePath, entry.Path)
+		assert.Len(t, entry.Replicas, 3)
+		assert.Equal(t, 3, entry.ReplicaCount)
+		assert.True(t, entry.HasStaleReplica)
+		assert.False(t, entry.OnlyStaleReplicas)
+
+		// the good replica is the master
+		assert.Equal(t, "diskResc", entry.Replicas[0].ResourceName)
+		assert.Equal(t, int64(11), entry.Size)
+
+		resources := []string{}
+		for _, replica := range entry.Replicas {
+			resources = append(resources, replica.ResourceName)
+		}
+		assert.ElementsMatch(t, []string{mock.MockResourceName, "diskResc", "tapeResc"}, resources)
+	}
+
+	// without replicas
+	entries, err = filesystem.ListWithOptions(homedir, nil)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		assert.Empty(t, entry.Replicas)
+	}
+}
+
+func testListFilteredByReplica(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.AddResource("tapeResc")
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	replicatedPath := homedir + "/replicated.txt"
+	singlePath := homedir + "/single.txt"
+
+	err = mockServer.PutDataObject(replicatedPath, "alice", []byte("hello world"))
+	failError(t, err)
+	err = mockServer.PutDataObject(singlePath, "alice", []byte("hello"))
+	failError(t, err)
+
+	err = filesystem.ReplicateFileToResources(replicatedPath, []string{"tapeResc"}, false)
+	failError(t, err)
+
+	// files not having a replica in the resource are not listed
+	entries, err := filesystem.ListWithOptions(homedir, &fs.ListOptions{Resource: "tapeResc", WithReplicas: true})
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, replicatedPath, entries[0].Path)
+	assert.Len(t, entries[0].Replicas, 1)
+	assert.Equal(t, "tapeResc", entries[0].Replicas[0].ResourceName)
+
+	entries, err = filesystem.ListWithOptions(homedir, &fs.ListOptions{FilterByReplicaNumber: true, ReplicaNumber: 0})
+	failError(t, err)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, 1, entry.ReplicaCount)
+		assert.Empty(t, entry.Replicas)
+	}
+
+	entries, err = filesystem.ListWithOptions(homedir, &fs.ListOptions{FilterByReplicaNumber: true, ReplicaNumber: 1})
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, replicatedPath, entries[0].Path)
+
+	// filtered listings are not cached
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func testSelectReadReplica(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	mockServer.SetResourceInfo("tapeResc", types.IRODSResourceTypeUnivMSS, types.IRODSResourceClassArchive, types.IRODSResourceStatusUp)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	replicatedPath := homedir + "/replicated.txt"
+	singlePath := homedir + "/single.txt"
+
+	err = mockServer.PutDataObject(replicatedPath, "alice", []byte("hello world"))
+	failError(t, err)
+	err = mockServer.PutDataObject(singlePath, "alice", []byte("hello"))
+	failError(t, err)
+
+	readFile := func(filesystem *fs.FileSystem, path string, mode types.FileOpenMode) string {
+		handle, err := filesystem.OpenFile(path, "", string(mode))
+		failError(t, err)
+		defer handle.Close()
+
+		buffer := make([]byte, 64)
+		readLen, _ := handle.Read(buffer)
+		return string(buffer[:readLen])
+	}
+
+	plainFilesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer plainFilesystem.Release()
+
+	err = plainFilesystem.ReplicateFileToResources(replicatedPath, []string{"tapeResc"}, false)
+	failError(t, err)
+
+	// the server picks a replica by default
+	assert.Equal(t, "hello world", readFile(plainFilesystem, replicatedPath, types.FileOpenModeReadOnly))
+	assert.Empty(t, mockServer.GetLastOpenedResourceHierarchy())
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache(), fs.WithSelectReadReplica(true))
+	failError(t, err)
+	defer filesystem.Release()
+
+	// the replica not in the archive is read
+	assert.Equal(t, "hello world", readFile(filesystem, replicatedPath, types.FileOpenModeReadOnly))
+	assert.Equal(t, mock.MockResourceName, mockServer.GetLastOpenedResourceHierarchy())
+
+	// nothing to select from
+	assert.Equal(t, "hello", readFile(filesystem, singlePath, types.FileOpenModeReadOnly))
+	assert.Empty(t, mockServer.GetLastOpenedResourceHierarchy())
+
+	// not for writes
+	handle, err := filesystem.OpenFile(replicatedPath, "", string(types.FileOpenModeReadWrite))
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+	assert.Empty(t, mockServer.GetLastOpenedResourceHierarchy())
+
+	// the archive is better than a resource down
+	mockServer.SetResourceSpace(-1, -1, types.IRODSResourceStatusDown)
+	assert.Equal(t, "hello world", readFile(filesystem, replicatedPath, types.FileOpenModeReadOnly))
+	assert.Equal(t, "tapeResc", mockServer.GetLastOpenedResourceHierarchy())
+}
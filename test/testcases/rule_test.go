@@ -0,0 +1,91 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule(t *testing.T) {
+	t.Run("test ExecuteRule", testExecuteRule)
+	t.Run("test ExecuteRuleUnsupportedParam", testExecuteRuleUnsupportedParam)
+}
+
+func testExecuteRule(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	rule := `main { writeLine("stdout", "<a & b>"); }`
+
+	// the mock server returns the rule as stdout
+	outputs, err := filesystem.ExecuteRule(rule, nil, nil)
+	failError(t, err)
+
+	execOut, err := outputs.GetExecOut("ruleExecOut")
+	failError(t, err)
+	assert.Equal(t, rule, string(execOut.Stdout))
+	assert.Empty(t, execOut.Stderr)
+
+	// the mock server echoes inputs having output labels
+	inputs := types.IRODSRuleParams{
+		"*str":    `"quoted" <str> & more`,
+		"*int":    42,
+		"*kvp":    map[string]string{"a": "1", "b": "<2> & \"3\""},
+		"*buf":    []byte{0x00, 0x01, 0xfe, 0xff},
+		"*unused": "unused",
+	}
+
+	outputs, err = filesystem.ExecuteRuleWithOptions(rule, inputs, []string{"*str", "*int", "*kvp", "*buf"}, &fs.ExecuteRuleOptions{
+		RuleEngineInstance: "irods_rule_engine_plugin-irods_rule_language-instance",
+	})
+	failError(t, err)
+	assert.Len(t, outputs, 4)
+	assert.False(t, outputs.Has("*unused"))
+
+	str, err := outputs.GetString("*str")
+	failError(t, err)
+	assert.Equal(t, inputs["*str"], str)
+
+	intValue, err := outputs.GetInt("*int")
+	failError(t, err)
+	assert.Equal(t, 42, intValue)
+
+	keyVals, err := outputs.GetKeyVals("*kvp")
+	failError(t, err)
+	assert.Equal(t, inputs["*kvp"], keyVals)
+
+	buf, err := outputs.GetBytes("*buf")
+	failError(t, err)
+	assert.Equal(t, inputs["*buf"], buf)
+
+	// mismatched types
+	_, err = outputs.GetInt("*str")
+	assert.Error(t, err)
+
+	_, err = outputs.GetString("*missing")
+	assert.Error(t, err)
+}
+
+func testExecuteRuleUnsupportedParam(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	_, err = filesystem.ExecuteRule("main { }", types.IRODSRuleParams{"*float": 1.5}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package testcases
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionManager(t *testing.T) {
+	t.Run("test SessionLookup", testSessionManagerLookup)
+	t.Run("test SessionCredentials", testSessionManagerCredentials)
+	t.Run("test ConcurrentGetSession", testSessionManagerConcurrentGetSession)
+	t.Run("test ConnectionMaxTotal", testSessionManagerConnectionMaxTotal)
+	t.Run("test EvictIdleSessions", testSessionManagerEvictIdleSessions)
+}
+
+func testSessionManagerLookup(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	rodsAccount, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	manager := session.NewIRODSSessionManager(session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test"))
+	defer manager.Release()
+
+	aliceSess, err := manager.GetSession(aliceAccount)
+	failError(t, err)
+
+	rodsSess, err := manager.GetSession(rodsAccount)
+	failError(t, err)
+	assert.NotEqual(t, aliceSess, rodsSess)
+
+	sameSess, err := manager.GetSession(aliceAccount)
+	failError(t, err)
+	assert.Equal(t, aliceSess, sameSess)
+
+	foundSess, ok := manager.GetSessionByKey(session.GetAccountKey(aliceAccount))
+	assert.True(t, ok)
+	assert.Equal(t, aliceSess, foundSess)
+	assert.ElementsMatch(t, []string{session.GetAccountKey(aliceAccount), session.GetAccountKey(rodsAccount)}, manager.GetAccountKeys())
+
+	manager.ReleaseSession(session.GetAccountKey(aliceAccount))
+
+	_, ok = manager.GetSessionByKey(session.GetAccountKey(aliceAccount))
+	assert.False(t, ok)
+
+	_, err = aliceSess.AcquireConnection()
+	assert.Error(t, err)
+	assert.True(t, types.IsSessionShutdownError(err))
+}
+
+func testSessionManagerCredentials(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	manager := session.NewIRODSSessionManager(session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test"))
+	defer manager.Release()
+
+	aliceSess, err := manager.GetSession(aliceAccount)
+	failError(t, err)
+
+	// a wrong password never gets the session of alice
+	wrongAccount := *aliceAccount
+	wrongAccount.Password = "wrong_password"
+	assert.NotEqual(t, session.GetAccountKey(aliceAccount), session.GetAccountKey(&wrongAccount))
+	assert.NotContains(t, session.GetAccountKey(aliceAccount), aliceAccount.Password)
+
+	wrongSess, err := manager.GetSession(&wrongAccount)
+	if err == nil {
+		assert.NotEqual(t, aliceSess, wrongSess)
+	}
+
+	// the session moves to the key of the rotated password
+	err = mockServer.SetUserPassword("alice", "rotated_password")
+	failError(t, err)
+
+	rotatedAccount := *aliceAccount
+	rotatedAccount.Password = "rotated_password"
+	err = aliceSess.UpdateAccount(&rotatedAccount)
+	failError(t, err)
+
+	_, ok := manager.GetSessionByKey(session.GetAccountKey(aliceAccount))
+	assert.False(t, ok)
+
+	rotatedSess, err := manager.GetSession(&rotatedAccount)
+	failError(t, err)
+	assert.Equal(t, aliceSess, rotatedSess)
+
+	rodsAccount, err := mockServer.GetAccount("rods")
+	failError(t, err)
+	err = aliceSess.UpdateAccount(rodsAccount)
+	assert.Error(t, err)
+}
+
+func testSessionManagerConcurrentGetSession(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	manager := session.NewIRODSSessionManager(session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test"))
+	defer manager.Release()
+
+	sessions := make([]*session.IRODSSession, 8)
+	errs := make([]error, 8)
+
+	wg := sync.WaitGroup{}
+	for i := range sessions {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			sessions[idx], errs[idx] = manager.GetSession(aliceAccount)
+		}(i)
+	}
+	wg.Wait()
+
+	// sessions created concurrently are dropped, all callers get the same session
+	for i := range sessions {
+		failError(t, errs[i])
+		assert.Equal(t, sessions[0], sessions[i])
+	}
+	assert.Len(t, manager.GetAccountKeys(), 1)
+}
+
+func testSessionManagerConnectionMaxTotal(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	rodsAccount, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	config := session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test")
+	config.ConnectionMaxTotal = 2
+
+	manager := session.NewIRODSSessionManager(config)
+	defer manager.Release()
+
+	aliceSess, err := manager.GetSession(aliceAccount)
+	failError(t, err)
+
+	rodsSess, err := manager.GetSession(rodsAccount)
+	failError(t, err)
+
+	// alice leaves an idle connection
+	aliceConn, err := aliceSess.AcquireConnection()
+	failError(t, err)
+	err = aliceSess.ReturnConnection(aliceConn)
+	failError(t, err)
+
+	rodsConn1, err := rodsSess.AcquireConnection()
+	failError(t, err)
+	defer rodsSess.ReturnConnection(rodsConn1)
+	assert.Equal(t, 2, manager.ConnectionTotal())
+
+	// the idle connection of alice is evicted
+	rodsConn2, err := rodsSess.AcquireConnection()
+	failError(t, err)
+	defer rodsSess.ReturnConnection(rodsConn2)
+	assert.NotEqual(t, rodsConn1, rodsConn2)
+	assert.Equal(t, 0, aliceSess.ConnectionTotal())
+	assert.Equal(t, 2, manager.ConnectionTotal())
+
+	// nothing to evict, share an in-use connection
+	rodsConn3, err := rodsSess.AcquireConnection()
+	failError(t, err)
+	defer rodsSess.ReturnConnection(rodsConn3)
+	assert.Contains(t, []interface{}{rodsConn1, rodsConn2}, rodsConn3)
+	assert.Equal(t, 2, manager.ConnectionTotal())
+
+	// alice cannot get a connection
+	_, err = aliceSess.AcquireConnection()
+	assert.Error(t, err)
+}
+
+func testSessionManagerEvictIdleSessions(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	rodsAccount, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	config := session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test")
+	config.SessionIdleTimeout = 200 * time.Millisecond
+
+	manager := session.NewIRODSSessionManager(config)
+	defer manager.Release()
+
+	aliceSess, err := manager.GetSession(aliceAccount)
+	failError(t, err)
+
+	aliceConn, err := aliceSess.AcquireConnection()
+	failError(t, err)
+	err = aliceSess.ReturnConnection(aliceConn)
+	failError(t, err)
+
+	rodsSess, err := manager.GetSession(rodsAccount)
+	failError(t, err)
+
+	// rods keeps a connection in use
+	rodsConn, err := rodsSess.AcquireConnection()
+	failError(t, err)
+
+	time.Sleep(300 * time.Millisecond)
+	manager.EvictIdleSessions()
+
+	_, ok := manager.GetSessionByKey(session.GetAccountKey(aliceAccount))
+	assert.False(t, ok)
+
+	_, ok = manager.GetSessionByKey(session.GetAccountKey(rodsAccount))
+	assert.True(t, ok)
+	assert.Equal(t, 1, manager.ConnectionTotal())
+
+	err = rodsSess.ReturnConnection(rodsConn)
+	failError(t, err)
+}
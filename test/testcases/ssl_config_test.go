@@ -0,0 +1,216 @@
+package testcases
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSLConfig(t *testing.T) {
+	t.Run("test GetTLSConfig", testGetTLSConfig)
+	t.Run("test GetTLSConfigWithCustomTLSConfig", testGetTLSConfigWithCustomTLSConfig)
+	t.Run("test SSLConfigFromYAML", testSSLConfigFromYAML)
+	t.Run("test TLSConfigCache", testTLSConfigCache)
+	t.Run("test TLSSessionResumption", testTLSSessionResumption)
+}
+
+func testGetTLSConfig(t *testing.T) {
+	sslConfig, err := types.CreateIRODSSSLConfig("", "", 32, "AES-256-CBC", 8, 16)
+	failError(t, err)
+
+	tlsConfig, err := sslConfig.GetTLSConfig("irods.example.com", false)
+	failError(t, err)
+	assert.Equal(t, "irods.example.com", tlsConfig.ServerName)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+
+	sslConfig.ServerName = "override.example.com"
+	sslConfig.InsecureSkipVerify = true
+	sslConfig.MinVersion = tls.VersionTLS12
+	sslConfig.CipherSuites = []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("irods.example.com", false)
+	failError(t, err)
+	assert.Equal(t, "override.example.com", tlsConfig.ServerName)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+
+	sslConfig.CACertificate = []byte("not a certificate")
+	_, err = sslConfig.GetTLSConfig("irods.example.com", false)
+	assert.Error(t, err)
+
+	sslConfig.CACertificate = nil
+	sslConfig.ClientCertificate = []byte("not a certificate")
+	_, err = sslConfig.GetTLSConfig("irods.example.com", false)
+	assert.Error(t, err)
+}
+
+func testGetTLSConfigWithCustomTLSConfig(t *testing.T) {
+	custom := &tls.Config{
+		ServerName: "custom.example.com",
+		MinVersion: tls.VersionTLS13,
+	}
+
+	sslConfig, err := types.CreateIRODSSSLConfigWithTLSConfig(custom, 32, "AES-256-CBC", 8, 16)
+	failError(t, err)
+
+	tlsConfig, err := sslConfig.GetTLSConfig("irods.example.com", true)
+	failError(t, err)
+	assert.Equal(t, "custom.example.com", tlsConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+
+	// custom config must not be modified
+	assert.False(t, custom.InsecureSkipVerify)
+	assert.Nil(t, custom.RootCAs)
+}
+
+func testSSLConfigFromYAML(t *testing.T) {
+	yamlBytes := []byte(`
+host:
+  hostname: irods.example.com
+  port: 1247
+user:
+  username: alice
+  password: alice_password
+  zone: example
+auth_scheme: native
+cs_negotiation: true
+cs_negotiation_policy: CS_NEG_REQUIRE
+ssl:
+  key_size: 32
+  algorithm: AES-256-CBC
+  salt_size: 8
+  hash_rounds: 16
+  server_name: override.example.com
+  insecure_skip_verify: true
+  min_version: "1.2"
+  cipher_suites:
+    - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+`)
+
+	account, err := types.CreateIRODSAccountFromYAML(yamlBytes)
+	failError(t, err)
+
+	sslConfig := account.SSLConfiguration
+	assert.NotNil(t, sslConfig)
+	assert.Equal(t, "override.example.com", sslConfig.ServerName)
+	assert.True(t, sslConfig.InsecureSkipVerify)
+	assert.Equal(t, uint16(tls.VersionTLS12), sslConfig.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, sslConfig.CipherSuites)
+
+	_, err = types.GetTLSVersion("1.4")
+	assert.Error(t, err)
+
+	_, err = types.GetTLSCipherSuites([]string{"NO_SUCH_SUITE"})
+	assert.Error(t, err)
+}
+
+func testTLSConfigCache(t *testing.T) {
+	sslConfig, err := types.CreateIRODSSSLConfig("", "", 32, "AES-256-CBC", 8, 16)
+	failError(t, err)
+
+	cache := connection.NewTLSConfigCache(0)
+	assert.True(t, cache.SupportSessionResumption())
+
+	tlsConfig, err := cache.GetTLSConfig(sslConfig, "irods.example.com", false)
+	failError(t, err)
+	assert.NotNil(t, tlsConfig.ClientSessionCache)
+
+	// built once
+	tlsConfig2, err := cache.GetTLSConfig(sslConfig, "irods.example.com", false)
+	failError(t, err)
+	assert.Same(t, tlsConfig, tlsConfig2)
+
+	tlsConfig3, err := cache.GetTLSConfig(sslConfig, "other.example.com", false)
+	failError(t, err)
+	assert.NotSame(t, tlsConfig, tlsConfig3)
+	assert.Equal(t, "other.example.com", tlsConfig3.ServerName)
+
+	cache = connection.NewTLSConfigCache(-1)
+	assert.False(t, cache.SupportSessionResumption())
+
+	tlsConfig, err = cache.GetTLSConfig(sslConfig, "irods.example.com", false)
+	failError(t, err)
+	assert.Nil(t, tlsConfig.ClientSessionCache)
+}
+
+// newTestTLSCertificate creates a self-signed certificate for localhost
+func newTestTLSCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	failError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	failError(t, err)
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func testTLSSessionResumption(t *testing.T) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{newTestTLSCertificate(t)},
+	})
+	failError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			// the handshake is done on the first write, session tickets are sent with it
+			conn.Write([]byte("ok"))
+			conn.Close()
+		}
+	}()
+
+	sslConfig, err := types.CreateIRODSSSLConfig("", "", 32, "AES-256-CBC", 8, 16)
+	failError(t, err)
+
+	dial := func(cache *connection.TLSConfigCache) bool {
+		tlsConfig, err := cache.GetTLSConfig(sslConfig, "localhost", true)
+		failError(t, err)
+
+		conn, err := tls.Dial("tcp", listener.Addr().String(), tlsConfig)
+		failError(t, err)
+		defer conn.Close()
+
+		// read to receive session tickets
+		_, err = io.ReadAll(conn)
+		failError(t, err)
+
+		return conn.ConnectionState().DidResume
+	}
+
+	cache := connection.NewTLSConfigCache(0)
+	assert.False(t, dial(cache))
+	assert.True(t, dial(cache))
+
+	cache = connection.NewTLSConfigCache(-1)
+	assert.False(t, dial(cache))
+	assert.False(t, dial(cache))
+}
@@ -0,0 +1,154 @@
+package testcases
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamCopy(t *testing.T) {
+	t.Run("test StreamCopyFileToFileSystem", testStreamCopyFileToFileSystem)
+	t.Run("test StreamCopyBothWays", testStreamCopyBothWays)
+}
+
+func testStreamCopyFileToFileSystem(t *testing.T) {
+	srcServer := startMockServer(t)
+	defer srcServer.Stop()
+
+	destServer := startMockServer(t)
+	defer destServer.Stop()
+
+	srcAccount, err := srcServer.GetAccount("alice")
+	failError(t, err)
+
+	destAccount, err := destServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	srcPath := homedir + "/file.bin"
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1000)
+	err = srcServer.PutDataObject(srcPath, "alice", content)
+	failError(t, err)
+
+	srcFS, err := fs.NewFileSystemWithDefault(srcAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer srcFS.Release()
+
+	destFS, err := fs.NewFileSystemWithDefault(destAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer destFS.Release()
+
+	for _, taskNum := range []int{1, 3} {
+		destDir := fmt.Sprintf("%s/dir%d", homedir, taskNum)
+		err = destFS.MakeDir(destDir, false)
+		failError(t, err)
+
+		copied := int64(0)
+		copyPath, err := srcFS.StreamCopyFileToFileSystem(srcPath, destFS, destDir, &fs.StreamCopyFileOptions{
+			TaskNum: taskNum,
+			Callback: func(processed int64, total int64) {
+				copied = processed
+			},
+		})
+		failError(t, err)
+		assert.Equal(t, destDir+"/file.bin", copyPath)
+		assert.Equal(t, int64(len(content)), copied)
+
+		data, err := destServer.GetDataObject(copyPath)
+		failError(t, err)
+		assert.Equal(t, content, data)
+
+		entry, err := destFS.StatFile(copyPath)
+		failError(t, err)
+		assert.Equal(t, int64(len(content)), entry.Size)
+	}
+
+	// source server is not changed
+	assert.False(t, srcFS.ExistsDir(homedir+"/dir1"))
+
+	// existing dest file
+	_, err = srcFS.StreamCopyFileToFileSystem(srcPath, destFS, homedir+"/dir1/file.bin", nil)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileAlreadyExistError(err))
+
+	copyPath, err := srcFS.StreamCopyFileToFileSystem(srcPath, destFS, homedir+"/dir1/file.bin", &fs.StreamCopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyRenameWithSuffix,
+	})
+	failError(t, err)
+	assert.Equal(t, homedir+"/dir1/file (1).bin", copyPath)
+
+	// missing source file
+	_, err = srcFS.StreamCopyFileToFileSystem(homedir+"/missing.bin", destFS, homedir, nil)
+	assert.Error(t, err)
+	assert.True(t, types.IsFileNotFoundError(err))
+}
+
+func testStreamCopyBothWays(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	path1 := homedir + "/file1.bin"
+	path2 := homedir + "/file2.bin"
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 100)
+	for _, path := range []string{path1, path2} {
+		err = mockServer.PutDataObject(path, "alice", content)
+		failError(t, err)
+	}
+
+	fs1, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer fs1.Release()
+
+	fs2, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer fs2.Release()
+
+	options := &fs.StreamCopyFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	}
+
+	// copies in opposite directions at the same time, on a file system and across file systems
+	copyBothWays := func(fsA *fs.FileSystem, fsB *fs.FileSystem) {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, copyErr := fsA.StreamCopyFileToFileSystem(path1, fsB, path2, options)
+				assert.NoError(t, copyErr)
+			}()
+			go func() {
+				defer wg.Done()
+				_, copyErr := fsB.StreamCopyFileToFileSystem(path2, fsA, path1, options)
+				assert.NoError(t, copyErr)
+			}()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(30 * time.Second):
+			t.Fatal("copies in opposite directions deadlocked")
+		}
+	}
+
+	copyBothWays(fs1, fs1)
+	copyBothWays(fs1, fs2)
+}
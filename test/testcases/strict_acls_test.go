@@ -0,0 +1,89 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictACLs(t *testing.T) {
+	t.Run("test ClientHints", testClientHints)
+	t.Run("test StrictACLsListing", testStrictACLsListing)
+}
+
+func testClientHints(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	hints, err := filesystem.GetClientHints()
+	failError(t, err)
+	assert.Equal(t, "SHA256", hints.HashScheme)
+	assert.False(t, hints.IsStrictACLs())
+	assert.False(t, filesystem.IsStrictACLs())
+
+	// hints are kept for the life of the file system
+	mockServer.SetStrictACLs(true)
+	assert.False(t, filesystem.IsStrictACLs())
+
+	strictFS, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer strictFS.Release()
+
+	assert.True(t, strictFS.IsStrictACLs())
+}
+
+func testStrictACLsListing(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	dir := "/mockzone/home/alice/data"
+	makeDryRunTree(t, mockServer, dir)
+
+	mockServer.SetStrictACLs(true)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	_, err = filesystem.ListACLs(dir + "/a.txt")
+	assert.Error(t, err)
+	assert.True(t, types.IsPermissionError(err))
+
+	// listings do not fail, ACLs are left out
+	accesses, err := filesystem.ListACLsForEntries(dir)
+	failError(t, err)
+	assert.Empty(t, accesses)
+
+	entries, err := filesystem.List(dir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+
+	report, err := filesystem.GetPermissionReport(dir)
+	failError(t, err)
+
+	paths := []string{}
+	for _, entry := range report.Entries {
+		paths = append(paths, entry.Path)
+		assert.Empty(t, entry.Accesses)
+	}
+	assert.Equal(t, []string{dir, dir + "/a.txt", dir + "/sub", dir + "/sub/b.txt"}, paths)
+
+	// refused ACLs are not cached as empty
+	mockServer.SetStrictACLs(false)
+
+	accesses, err = filesystem.ListACLs(dir + "/a.txt")
+	failError(t, err)
+	assert.NotEmpty(t, accesses)
+}
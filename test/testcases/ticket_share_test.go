@@ -0,0 +1,107 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketShare(t *testing.T) {
+	t.Run("test CreateTicketShare", testCreateTicketShare)
+	t.Run("test NewFileSystemForTicketShare", testNewFileSystemForTicketShare)
+}
+
+func testCreateTicketShare(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	expirationTime := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	share, err := filesystem.CreateTicketShare(irodsPath, expirationTime)
+	failError(t, err)
+	assert.Equal(t, irodsPath, share.Path)
+	assert.Equal(t, "mockzone", share.Zone)
+	assert.Equal(t, expirationTime, share.ExpirationTime)
+	assert.NotEmpty(t, share.Ticket)
+	assert.False(t, share.IsExpired())
+
+	tickets, err := filesystem.ListTicketsForPath(irodsPath)
+	failError(t, err)
+	if assert.Len(t, tickets, 1) {
+		assert.Equal(t, share.Ticket, tickets[0].Name)
+		assert.Equal(t, types.TicketTypeRead, tickets[0].Type)
+	}
+
+	shareURL, err := share.GetURL()
+	failError(t, err)
+
+	shareAccount, sharePath, err := util.ParseIRODSURL(shareURL)
+	failError(t, err)
+	assert.Equal(t, irodsPath, sharePath)
+	assert.Equal(t, fs.TicketShareUser, shareAccount.ClientUser)
+	assert.Equal(t, share.Ticket, shareAccount.Ticket)
+
+	// shares created back-to-back get different tickets
+	otherShare, err := filesystem.CreateTicketShare(irodsPath, time.Time{})
+	failError(t, err)
+	assert.NotEqual(t, share.Ticket, otherShare.Ticket)
+	assert.Len(t, otherShare.Ticket, 22)
+
+	// creating a ticket for a missing path fails
+	_, err = filesystem.CreateTicketShare("/mockzone/home/alice/missing.txt", time.Time{})
+	assert.Error(t, err)
+}
+
+func testNewFileSystemForTicketShare(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser(fs.TicketShareUser, "", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	irodsPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(irodsPath, "alice", []byte("hello world"))
+	failError(t, err)
+
+	share, err := filesystem.CreateTicketShare(irodsPath, time.Time{})
+	failError(t, err)
+
+	shareFilesystem, err := fs.NewFileSystemForTicketShare(share, "go-irodsclient-test")
+	failError(t, err)
+	defer shareFilesystem.Release()
+
+	handle, err := shareFilesystem.OpenFile(share.Path, "", "r")
+	failError(t, err)
+	defer handle.Close()
+
+	buffer := make([]byte, 32)
+	readLen, _ := handle.Read(buffer)
+	assert.Equal(t, "hello world", string(buffer[:readLen]))
+
+	// expired shares are refused
+	share.ExpirationTime = time.Now().Add(-time.Minute)
+	_, err = fs.NewFileSystemForTicketShare(share, "go-irodsclient-test")
+	assert.Error(t, err)
+}
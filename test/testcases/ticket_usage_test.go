@@ -0,0 +1,167 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketUsage(t *testing.T) {
+	t.Run("test ListTicketsWithUsage", testListTicketsWithUsage)
+	t.Run("test ListTicketsForPath", testListTicketsForPath)
+	t.Run("test ExtendAndResetTicketLimits", testExtendAndResetTicketLimits)
+}
+
+func testListTicketsWithUsage(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	err = mockServer.PutDataObject(homedir+"/file.txt", "alice", []byte("hello world"))
+	failError(t, err)
+
+	err = filesystem.CreateTicket("file_ticket", types.TicketTypeRead, homedir+"/file.txt")
+	failError(t, err)
+	err = filesystem.ModifyTicketUseLimit("file_ticket", 10)
+	failError(t, err)
+
+	err = filesystem.CreateTicket("dir_ticket", types.TicketTypeWrite, homedir)
+	failError(t, err)
+	err = filesystem.ModifyTicketWriteFileLimit("dir_ticket", 5)
+	failError(t, err)
+	err = filesystem.ModifyTicketWriteByteLimit("dir_ticket", 1024)
+	failError(t, err)
+
+	err = mockServer.SetTicketUsage("file_ticket", 3, 0, 0)
+	failError(t, err)
+	err = mockServer.SetTicketUsage("dir_ticket", 1, 2, 512)
+	failError(t, err)
+
+	tickets, err := filesystem.ListTickets()
+	failError(t, err)
+	assert.Len(t, tickets, 2)
+
+	ticketMap := map[string]*types.IRODSTicket{}
+	for _, ticket := range tickets {
+		ticketMap[ticket.Name] = ticket
+	}
+
+	fileTicket := ticketMap["file_ticket"]
+	assert.NotNil(t, fileTicket)
+	assert.Equal(t, homedir+"/file.txt", fileTicket.Path)
+	assert.Equal(t, types.ObjectTypeDataObject, fileTicket.ObjectType)
+	assert.Equal(t, "alice", fileTicket.Owner)
+	assert.Equal(t, int64(10), fileTicket.UsesLimit)
+	assert.Equal(t, int64(3), fileTicket.UsesCount)
+
+	dirTicket := ticketMap["dir_ticket"]
+	assert.NotNil(t, dirTicket)
+	assert.Equal(t, homedir, dirTicket.Path)
+	assert.True(t, dirTicket.IsReadWrite())
+	assert.Equal(t, int64(5), dirTicket.WriteFileLimit)
+	assert.Equal(t, int64(2), dirTicket.WriteFileCount)
+	assert.Equal(t, int64(1024), dirTicket.WriteByteLimit)
+	assert.Equal(t, int64(512), dirTicket.WriteByteCount)
+}
+
+func testListTicketsForPath(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	for _, name := range []string{"file1.txt", "file2.txt"} {
+		err = mockServer.PutDataObject(homedir+"/"+name, "alice", []byte("hello world"))
+		failError(t, err)
+	}
+
+	err = filesystem.CreateTicket("file1_ticket_a", types.TicketTypeRead, homedir+"/file1.txt")
+	failError(t, err)
+	err = filesystem.CreateTicket("file1_ticket_b", types.TicketTypeRead, homedir+"/file1.txt")
+	failError(t, err)
+	err = filesystem.CreateTicket("file2_ticket", types.TicketTypeRead, homedir+"/file2.txt")
+	failError(t, err)
+	err = filesystem.CreateTicket("dir_ticket", types.TicketTypeRead, homedir)
+	failError(t, err)
+
+	tickets, err := filesystem.ListTicketsForPath(homedir + "/file1.txt")
+	failError(t, err)
+	assert.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		assert.Equal(t, homedir+"/file1.txt", ticket.Path)
+	}
+
+	tickets, err = filesystem.ListTicketsForPath(homedir + "/")
+	failError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, "dir_ticket", tickets[0].Name)
+
+	err = filesystem.DeleteTicket("file2_ticket")
+	failError(t, err)
+
+	tickets, err = filesystem.ListTicketsForPath(homedir + "/file2.txt")
+	failError(t, err)
+	assert.Len(t, tickets, 0)
+}
+
+func testExtendAndResetTicketLimits(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	err = filesystem.CreateTicket("upload_ticket", types.TicketTypeWrite, homedir)
+	failError(t, err)
+	err = filesystem.ModifyTicketWriteFileLimit("upload_ticket", 5)
+	failError(t, err)
+	err = filesystem.ModifyTicketWriteByteLimit("upload_ticket", 1024)
+	failError(t, err)
+
+	err = mockServer.SetTicketUsage("upload_ticket", 4, 4, 100)
+	failError(t, err)
+
+	// 10 more files from now, bytes are allowed already, uses are unlimited
+	ticket, err := filesystem.ExtendTicketLimits("upload_ticket", &fs.TicketLimits{
+		Uses:       10,
+		WriteFiles: 10,
+		WriteBytes: 512,
+	})
+	failError(t, err)
+	assert.Equal(t, int64(0), ticket.UsesLimit)
+	assert.Equal(t, int64(14), ticket.WriteFileLimit)
+	assert.Equal(t, int64(1024), ticket.WriteByteLimit)
+
+	ticket, err = filesystem.ResetTicketLimits("upload_ticket", &fs.TicketLimits{
+		Uses:       2,
+		WriteBytes: 512,
+	})
+	failError(t, err)
+	assert.Equal(t, int64(6), ticket.UsesLimit)
+	assert.Equal(t, int64(0), ticket.WriteFileLimit)
+	assert.Equal(t, int64(612), ticket.WriteByteLimit)
+
+	_, err = filesystem.ExtendTicketLimits("no_such_ticket", &fs.TicketLimits{Uses: 1})
+	assert.Error(t, err)
+}
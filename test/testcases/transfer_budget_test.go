@@ -0,0 +1,130 @@
+package testcases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferBudget(t *testing.T) {
+	t.Run("test TransferBudgetAcquire", testTransferBudgetAcquire)
+	t.Run("test TransferBudgetWait", testTransferBudgetWait)
+	t.Run("test FileSystemTransferConcurrencyMax", testFileSystemTransferConcurrencyMax)
+}
+
+func testTransferBudgetAcquire(t *testing.T) {
+	budget := fs.NewTransferBudget(4)
+	assert.Equal(t, 4, budget.GetMax())
+
+	assert.Equal(t, 3, budget.Acquire(3))
+	// only one is left
+	assert.Equal(t, 1, budget.Acquire(4))
+	assert.Equal(t, 4, budget.GetInUse())
+
+	budget.Release(3)
+	assert.Equal(t, 1, budget.GetInUse())
+
+	// asks for one at least
+	assert.Equal(t, 1, budget.Acquire(0))
+
+	budget.Release(2)
+	assert.Equal(t, 0, budget.GetInUse())
+
+	assert.Equal(t, 1, fs.NewTransferBudget(0).GetMax())
+}
+
+func testTransferBudgetWait(t *testing.T) {
+	budget := fs.NewTransferBudget(2)
+	assert.Equal(t, 2, budget.Acquire(2))
+
+	acquired := make(chan int)
+	go func() {
+		acquired <- budget.Acquire(2)
+	}()
+
+	select {
+	case <-acquired:
+		assert.Fail(t, "acquired while the budget is used up")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	budget.Release(1)
+
+	select {
+	case taken := <-acquired:
+		assert.Equal(t, 1, taken)
+	case <-time.After(time.Second):
+		assert.Fail(t, "not acquired after release")
+	}
+
+	budget.Release(2)
+	assert.Equal(t, 0, budget.GetInUse())
+}
+
+func testFileSystemTransferConcurrencyMax(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	assert.Nil(t, filesystem.GetTransferBudget())
+	filesystem.Release()
+
+	filesystem, err = fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithTransferConcurrencyMax(2))
+	failError(t, err)
+	defer filesystem.Release()
+
+	budget := filesystem.GetTransferBudget()
+	assert.NotNil(t, budget)
+	assert.Equal(t, 2, budget.GetMax())
+
+	homedir := "/mockzone/home/alice"
+	localDir := t.TempDir()
+
+	mutex := sync.Mutex{}
+	maxInUse := 0
+	callback := func(processed int64, total int64) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		inUse := budget.GetInUse()
+		if inUse > maxInUse {
+			maxInUse = inUse
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		localPath := filepath.Join(localDir, fmt.Sprintf("file%d.txt", i))
+		err = os.WriteFile(localPath, []byte("hello world"), 0644)
+		failError(t, err)
+
+		wg.Add(1)
+		go func(localPath string, irodsPath string) {
+			defer wg.Done()
+
+			errs <- filesystem.UploadFile(localPath, irodsPath, "", false, callback)
+		}(localPath, fmt.Sprintf("%s/file%d.txt", homedir, i))
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		failError(t, err)
+	}
+
+	assert.Greater(t, maxInUse, 0)
+	assert.LessOrEqual(t, maxInUse, 2)
+	assert.Equal(t, 0, budget.GetInUse())
+}
@@ -0,0 +1,79 @@
+package testcases
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferSize(t *testing.T) {
+	t.Run("test NumTasksWithBlockSize", testNumTasksWithBlockSize)
+	t.Run("test ConnectionTransferBufferSize", testConnectionTransferBufferSize)
+	t.Run("test FileSystemTransferBufferSize", testFileSystemTransferBufferSize)
+}
+
+func testNumTasksWithBlockSize(t *testing.T) {
+	assert.Equal(t, 1, util.GetNumTasksForParallelTransferWithBlockSize(1024, 1024))
+	assert.Equal(t, 2, util.GetNumTasksForParallelTransferWithBlockSize(1025, 1024))
+	assert.Equal(t, 3, util.GetNumTasksForParallelTransferWithBlockSize(3*1024, 1024))
+	assert.Equal(t, util.TransferTaskMaxNum, util.GetNumTasksForParallelTransferWithBlockSize(100*1024, 1024))
+
+	// default block size
+	assert.Equal(t, util.GetNumTasksForParallelTransfer(100*1024*1024), util.GetNumTasksForParallelTransferWithBlockSize(100*1024*1024, 0))
+	assert.Equal(t, 1, util.GetNumTasksForParallelTransferWithBlockSize(util.TransferTaskMinLength, 0))
+}
+
+func testConnectionTransferBufferSize(t *testing.T) {
+	conn := connection.NewIRODSConnection(nil, time.Minute, "go-irodsclient-test")
+	assert.Equal(t, common.ReadWriteBufferSize, conn.GetTransferBufferSize())
+
+	conn.SetTransferBufferSize(1024)
+	assert.Equal(t, 1024, conn.GetTransferBufferSize())
+
+	conn.SetTransferBufferSize(0)
+	assert.Equal(t, common.ReadWriteBufferSize, conn.GetTransferBufferSize())
+}
+
+func testFileSystemTransferBufferSize(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	// data is transferred in many small requests
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithTransferBufferSize(7))
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	data := bytes.Repeat([]byte("hello world "), 100)
+
+	localDir := t.TempDir()
+	localPath := filepath.Join(localDir, "upload.txt")
+	err = os.WriteFile(localPath, data, 0644)
+	failError(t, err)
+
+	err = filesystem.UploadFile(localPath, homedir+"/file.txt", "", false, nil)
+	failError(t, err)
+
+	stored, err := mockServer.GetDataObject(homedir + "/file.txt")
+	failError(t, err)
+	assert.Equal(t, data, stored)
+
+	downloadPath := filepath.Join(localDir, "download.txt")
+	err = filesystem.DownloadFile(homedir+"/file.txt", "", downloadPath, nil)
+	failError(t, err)
+
+	downloaded, err := os.ReadFile(downloadPath)
+	failError(t, err)
+	assert.Equal(t, data, downloaded)
+}
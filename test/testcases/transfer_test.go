@@ -0,0 +1,767 @@
+package testcases
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransfer(t *testing.T) {
+	t.Run("test UploadSkipIdentical", testUploadSkipIdentical)
+	t.Run("test DownloadSkipIdentical", testDownloadSkipIdentical)
+	t.Run("test UploadWithTempName", testUploadWithTempName)
+	t.Run("test UploadWithAttributes", testUploadWithAttributes)
+	t.Run("test DownloadWithTempName", testDownloadWithTempName)
+	t.Run("test UploadStallRetry", testUploadStallRetry)
+	t.Run("test DownloadStallRetry", testDownloadStallRetry)
+	t.Run("test UploadPreserveModifyTime", testUploadPreserveModifyTime)
+	t.Run("test DownloadPreserveModifyTime", testDownloadPreserveModifyTime)
+	t.Run("test UploadComputeChecksum", testUploadComputeChecksum)
+	t.Run("test UploadRegisterChecksum", testUploadRegisterChecksum)
+	t.Run("test DownloadParallelWithVerify", testDownloadParallelWithVerify)
+	t.Run("test DownloadWithServerThreads", testDownloadWithServerThreads)
+	t.Run("test UploadWithServerThreads", testUploadWithServerThreads)
+}
+
+func testUploadSkipIdentical(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+
+	err = os.WriteFile(localPath, []byte("upload content"), 0644)
+	failError(t, err)
+
+	options := &fs.UploadFileOptions{
+		SkipIdentical:   true,
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	}
+
+	// no target
+	result, err := filesystem.UploadFileWithOptions(localPath, homedir, options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusTransferred, result.Status)
+	assert.Equal(t, homedir+"/upload.txt", result.IRODSPath)
+	assert.Equal(t, int64(14), result.Size)
+
+	// identical target, checksum is computed by the server
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir, options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusSkipped, result.Status)
+
+	// same size, different content
+	err = os.WriteFile(localPath, []byte("UPLOAD CONTENT"), 0644)
+	failError(t, err)
+
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir+"/upload.txt", options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusTransferred, result.Status)
+
+	data, err := mockServer.GetDataObject(homedir + "/upload.txt")
+	failError(t, err)
+	assert.Equal(t, "UPLOAD CONTENT", string(data))
+
+	// identical, but skipping is not requested
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir+"/upload.txt", &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+	})
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusTransferred, result.Status)
+}
+
+func testDownloadSkipIdentical(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	err = mockServer.PutDataObject(homedir+"/download.txt", "alice", []byte("download content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localDir := t.TempDir()
+	localPath := filepath.Join(localDir, "download.txt")
+
+	callbackTotal := int64(0)
+	options := &fs.DownloadFileOptions{
+		SkipIdentical: true,
+		Callback: func(processed int64, total int64) {
+			callbackTotal = total
+		},
+	}
+
+	// no target
+	result, err := filesystem.DownloadFileWithOptions(homedir+"/download.txt", localDir, options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusTransferred, result.Status)
+	assert.Equal(t, localPath, result.LocalPath)
+
+	data, err := os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, "download content", string(data))
+
+	// identical target
+	callbackTotal = 0
+	result, err = filesystem.DownloadFileWithOptions(homedir+"/download.txt", localPath, options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusSkipped, result.Status)
+	assert.Equal(t, int64(16), callbackTotal)
+
+	// different size
+	err = os.WriteFile(localPath, []byte("short"), 0644)
+	failError(t, err)
+
+	result, err = filesystem.DownloadFileWithOptions(homedir+"/download.txt", localPath, options)
+	failError(t, err)
+	assert.Equal(t, fs.TransferStatusTransferred, result.Status)
+
+	data, err = os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, "download content", string(data))
+}
+
+func testUploadWithTempName(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/upload.txt"
+
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("old content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	err = os.WriteFile(localPath, []byte("new content"), 0644)
+	failError(t, err)
+
+	// failing verification keeps the target
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		Resource:        "unknownResc",
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		UseTempName:     true,
+		VerifyChecksum:  true,
+	})
+	assert.Error(t, err)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "old content", string(data))
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+
+	// overwrite via temp name
+	result, err := filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		UseTempName:     true,
+		VerifyChecksum:  true,
+	})
+	failError(t, err)
+	assert.Equal(t, targetPath, result.IRODSPath)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "upload.txt", entries[0].Name)
+
+	// failing final rename restores the existing file
+	err = os.WriteFile(localPath, []byte("newer content"), 0644)
+	failError(t, err)
+
+	mockServer.FailRenamesTo(targetPath, 1)
+
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		UseTempName:     true,
+	})
+	assert.Error(t, err)
+
+	data, err = mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "new content", string(data))
+
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+
+	// new file via temp name
+	_, err = filesystem.UploadFileWithOptions(localPath, homedir+"/new.txt", &fs.UploadFileOptions{
+		UseTempName: true,
+	})
+	failError(t, err)
+
+	entries, err = filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func testDownloadWithTempName(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	err = mockServer.PutDataObject(homedir+"/download.txt", "alice", []byte("download content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localDir := t.TempDir()
+	localPath := filepath.Join(localDir, "download.txt")
+
+	err = os.WriteFile(localPath, []byte("old"), 0644)
+	failError(t, err)
+
+	options := &fs.DownloadFileOptions{
+		UseTempName: true,
+		Sync:        true,
+	}
+
+	// failing download keeps the local file
+	err = os.Mkdir(localPath+".part", 0755)
+	failError(t, err)
+	err = os.WriteFile(filepath.Join(localPath+".part", "blocker"), []byte{}, 0644)
+	failError(t, err)
+
+	_, err = filesystem.DownloadFileWithOptions(homedir+"/download.txt", localPath, options)
+	assert.Error(t, err)
+
+	data, err := os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, "old", string(data))
+
+	err = os.RemoveAll(localPath + ".part")
+	failError(t, err)
+
+	// download via temp name
+	result, err := filesystem.DownloadFileWithOptions(homedir+"/download.txt", localDir, options)
+	failError(t, err)
+	assert.Equal(t, localPath, result.LocalPath)
+
+	data, err = os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, "download content", string(data))
+
+	entries, err := os.ReadDir(localDir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func testUploadWithAttributes(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	err = os.WriteFile(localPath, []byte("upload content"), 0644)
+	failError(t, err)
+
+	for _, useTempName := range []bool{false, true} {
+		irodsPath := homedir + "/upload.txt"
+		if useTempName {
+			irodsPath = homedir + "/upload_temp.txt"
+		}
+
+		_, err = filesystem.UploadFileWithOptions(localPath, irodsPath, &fs.UploadFileOptions{
+			UseTempName: useTempName,
+			Metadata: []*types.IRODSMeta{
+				{Name: "study", Value: "s1", Units: ""},
+				{Name: "sample", Value: "x1", Units: "id"},
+			},
+			ACLs: []*types.IRODSAccess{
+				{UserName: "rods", UserZone: "mockzone", AccessLevel: types.IRODSAccessLevelReadObject},
+			},
+		})
+		failError(t, err)
+
+		metas, err := filesystem.ListMetadata(irodsPath)
+		failError(t, err)
+		assert.Len(t, metas, 2)
+
+		accessLevels := map[string]types.IRODSAccessLevelType{}
+		accesses, err := filesystem.ListACLs(irodsPath)
+		failError(t, err)
+		for _, access := range accesses {
+			accessLevels[access.UserName] = access.AccessLevel
+		}
+		assert.Equal(t, map[string]types.IRODSAccessLevelType{
+			"alice": types.IRODSAccessLevelOwner,
+			"rods":  types.IRODSAccessLevelReadObject,
+		}, accessLevels)
+	}
+
+	// failing to apply attributes fails the upload, the temp file is removed
+	_, err = filesystem.UploadFileWithOptions(localPath, homedir+"/bad.txt", &fs.UploadFileOptions{
+		UseTempName: true,
+		ACLs: []*types.IRODSAccess{
+			{UserName: "nobody", UserZone: "mockzone", AccessLevel: types.IRODSAccessLevelReadObject},
+		},
+	})
+	assert.Error(t, err)
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func testUploadStallRetry(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	targetPath := "/mockzone/home/alice/file.txt"
+	localPath := filepath.Join(t.TempDir(), "file.txt")
+	err = os.WriteFile(localPath, []byte("content"), 0644)
+	failError(t, err)
+
+	// stalls more than retries
+	mockServer.StallDataTransfers(2)
+
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		StallTimeout:    200 * time.Millisecond,
+		StallRetries:    1,
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsTransferStalledError(err))
+
+	// stalls less than retries
+	mockServer.StallDataTransfers(1)
+
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+		OverwritePolicy: fs.OverwritePolicyOverwrite,
+		StallTimeout:    200 * time.Millisecond,
+		StallRetries:    1,
+	})
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "content", string(data))
+}
+
+func testDownloadStallRetry(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	srcPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	localPath := filepath.Join(t.TempDir(), "file.txt")
+
+	// no retry
+	mockServer.StallDataTransfers(1)
+
+	_, err = filesystem.DownloadFileWithOptions(srcPath, localPath, &fs.DownloadFileOptions{
+		UseTempName:  true,
+		StallTimeout: 200 * time.Millisecond,
+	})
+	assert.Error(t, err)
+	assert.True(t, types.IsTransferStalledError(err))
+
+	_, err = os.Stat(localPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// retry
+	mockServer.StallDataTransfers(1)
+
+	_, err = filesystem.DownloadFileWithOptions(srcPath, localPath, &fs.DownloadFileOptions{
+		UseTempName:  true,
+		StallTimeout: 200 * time.Millisecond,
+		StallRetries: 2,
+	})
+	failError(t, err)
+
+	data, err := os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, "content", string(data))
+}
+
+func testUploadPreserveModifyTime(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	localPath := filepath.Join(t.TempDir(), "file.txt")
+	err = os.WriteFile(localPath, []byte("content"), 0644)
+	failError(t, err)
+
+	modifyTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = os.Chtimes(localPath, time.Now(), modifyTime)
+	failError(t, err)
+
+	for _, useTempName := range []bool{false, true} {
+		targetPath := fmt.Sprintf("%s/file_%t.txt", homedir, useTempName)
+
+		_, err = filesystem.UploadFileWithOptions(localPath, targetPath, &fs.UploadFileOptions{
+			UseTempName:        useTempName,
+			PreserveModifyTime: true,
+		})
+		failError(t, err)
+
+		entry, err := filesystem.StatFile(targetPath)
+		failError(t, err)
+		assert.Equal(t, modifyTime.Unix(), entry.ModifyTime.Unix())
+	}
+
+	// not preserved by default
+	targetPath := homedir + "/file.txt"
+	_, err = filesystem.UploadFileWithOptions(localPath, targetPath, nil)
+	failError(t, err)
+
+	entry, err := filesystem.StatFile(targetPath)
+	failError(t, err)
+	assert.NotEqual(t, modifyTime.Unix(), entry.ModifyTime.Unix())
+}
+
+func testDownloadPreserveModifyTime(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	srcPath := "/mockzone/home/alice/file.txt"
+	err = mockServer.PutDataObject(srcPath, "alice", []byte("content"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	modifyTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = filesystem.TouchFile(srcPath, modifyTime)
+	failError(t, err)
+
+	localDir := t.TempDir()
+	for _, useTempName := range []bool{false, true} {
+		localPath := filepath.Join(localDir, fmt.Sprintf("file_%t.txt", useTempName))
+
+		_, err = filesystem.DownloadFileWithOptions(srcPath, localPath, &fs.DownloadFileOptions{
+			UseTempName:        useTempName,
+			PreserveModifyTime: true,
+		})
+		failError(t, err)
+
+		stat, err := os.Stat(localPath)
+		failError(t, err)
+		assert.Equal(t, modifyTime.Unix(), stat.ModTime().Unix())
+	}
+}
+
+func testUploadComputeChecksum(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithTransferBufferSize(5))
+	failError(t, err)
+	defer filesystem.Release()
+
+	content := []byte("checksum computed while uploading")
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	err = os.WriteFile(localPath, content, 0644)
+	failError(t, err)
+
+	sha256Sum := sha256.Sum256(content)
+	md5Sum := md5.Sum(content)
+
+	// not computed by default
+	result, err := filesystem.UploadFileWithOptions(localPath, homedir+"/default.txt", &fs.UploadFileOptions{})
+	failError(t, err)
+	assert.Empty(t, result.Checksum)
+	assert.Equal(t, types.ChecksumAlgorithmUnknown, result.ChecksumAlgorithm)
+
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir+"/sha256.txt", &fs.UploadFileOptions{
+		ComputeChecksum: true,
+	})
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, result.ChecksumAlgorithm)
+	assert.Equal(t, sha256Sum[:], result.Checksum)
+
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir+"/md5.txt", &fs.UploadFileOptions{
+		ComputeChecksum:   true,
+		ChecksumAlgorithm: types.ChecksumAlgorithmMD5,
+	})
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmMD5, result.ChecksumAlgorithm)
+	assert.Equal(t, md5Sum[:], result.Checksum)
+
+	// verification uses the checksum computed while uploading
+	result, err = filesystem.UploadFileWithOptions(localPath, homedir+"/verify.txt", &fs.UploadFileOptions{
+		VerifyChecksum: true,
+		StallTimeout:   time.Minute,
+	})
+	failError(t, err)
+	assert.Equal(t, sha256Sum[:], result.Checksum)
+}
+
+func testUploadRegisterChecksum(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	content := []byte("checksum registered on upload")
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	err = os.WriteFile(localPath, content, 0644)
+	failError(t, err)
+
+	sha256Sum := sha256.Sum256(content)
+
+	// no checksum by default
+	_, err = filesystem.UploadFileWithOptions(localPath, homedir+"/default.txt", &fs.UploadFileOptions{})
+	failError(t, err)
+
+	entry, err := filesystem.Stat(homedir + "/default.txt")
+	failError(t, err)
+	assert.Empty(t, entry.CheckSum)
+
+	_, err = filesystem.UploadFileWithOptions(localPath, homedir+"/register.txt", &fs.UploadFileOptions{
+		RegisterChecksum: true,
+	})
+	failError(t, err)
+
+	entry, err = filesystem.Stat(homedir + "/register.txt")
+	failError(t, err)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, entry.CheckSumAlgorithm)
+	assert.Equal(t, sha256Sum[:], entry.CheckSum)
+
+	result, err := filesystem.UploadFileWithOptions(localPath, homedir+"/verify.txt", &fs.UploadFileOptions{
+		VerifyChecksumOnServer: true,
+		StallTimeout:           time.Minute,
+	})
+	failError(t, err)
+	assert.Equal(t, sha256Sum[:], result.Checksum)
+
+	entry, err = filesystem.Stat(homedir + "/verify.txt")
+	failError(t, err)
+	assert.Equal(t, sha256Sum[:], entry.CheckSum)
+
+	// the server computes a checksum differing from the md5 checksum given
+	_, err = filesystem.UploadFileWithOptions(localPath, homedir+"/mismatch.txt", &fs.UploadFileOptions{
+		VerifyChecksumOnServer: true,
+		ChecksumAlgorithm:      types.ChecksumAlgorithmMD5,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, common.USER_CHKSUM_MISMATCH, types.GetIRODSErrorCode(err))
+}
+
+func testDownloadParallelWithVerify(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	data := make([]byte, 10*1024+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	irodsPath := "/mockzone/home/alice/file.bin"
+	err = mockServer.PutDataObject(irodsPath, "alice", data)
+	failError(t, err)
+
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	verifyOptions := &irods_fs.DownloadVerifyOptions{
+		BlockSize:    1024,
+		BlockRetries: 1,
+	}
+
+	// failed reads are retried by block
+	mockServer.FailDataReads(2)
+
+	result, err := filesystem.DownloadFileParallelWithVerify(irodsPath, "", localPath, 3, verifyOptions, nil)
+	failError(t, err)
+	assert.Equal(t, 11, result.Blocks)
+	assert.Equal(t, 2, result.RetriedBlocks)
+	assert.Equal(t, 0, result.RepairedBlocks)
+
+	localData, err := os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, data, localData)
+
+	// corrupted blocks are found by checksum and repaired
+	mockServer.CorruptDataReads(1)
+
+	result, err = filesystem.DownloadFileParallelWithVerify(irodsPath, "", localPath, 3, verifyOptions, nil)
+	failError(t, err)
+	assert.Equal(t, 0, result.RetriedBlocks)
+	assert.Equal(t, 1, result.RepairedBlocks)
+	assert.Equal(t, types.ChecksumAlgorithmSHA256, result.Checksum.Algorithm)
+
+	localData, err = os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, data, localData)
+
+	// failing more than retries
+	mockServer.FailDataReads(3)
+
+	_, err = filesystem.DownloadFileParallelWithVerify(irodsPath, "", localPath, 1, &irods_fs.DownloadVerifyOptions{BlockSize: 1024}, nil)
+	assert.Error(t, err)
+	mockServer.FailDataReads(0)
+}
+
+func testDownloadWithServerThreads(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test")
+	failError(t, err)
+	defer sess.Release()
+
+	data := make([]byte, 10*1024+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	irodsPath := "/mockzone/home/alice/file.bin"
+	err = mockServer.PutDataObject(irodsPath, "alice", data)
+	failError(t, err)
+
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+
+	// the server caps the number of threads requested
+	mockServer.SetPortalThreads(2)
+
+	err = irods_fs.DownloadDataObjectFromResourceServerWithThreads(sess, irodsPath, "", localPath, int64(len(data)), 4, nil)
+	failError(t, err)
+	assert.Equal(t, 4, mockServer.GetRequestedPortalThreads())
+	assert.Equal(t, 2, mockServer.GetDataObjectOpenCount())
+
+	localData, err := os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, data, localData)
+
+	// the server disables parallel transfer
+	mockServer.SetPortalThreads(0)
+
+	err = irods_fs.DownloadDataObjectFromResourceServerWithThreads(sess, irodsPath, "", localPath, int64(len(data)), 4, nil)
+	failError(t, err)
+	assert.Equal(t, 3, mockServer.GetDataObjectOpenCount())
+
+	localData, err = os.ReadFile(localPath)
+	failError(t, err)
+	assert.Equal(t, data, localData)
+}
+
+func testUploadWithServerThreads(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test")
+	failError(t, err)
+	defer sess.Release()
+
+	data := make([]byte, 10*1024+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	err = os.WriteFile(localPath, data, 0644)
+	failError(t, err)
+
+	irodsPath := "/mockzone/home/alice/file.bin"
+
+	mockServer.SetPortalThreads(2)
+
+	err = irods_fs.UploadDataObjectToResourceServerWithThreads(sess, localPath, irodsPath, "", 4, false, nil)
+	failError(t, err)
+	assert.Equal(t, 4, mockServer.GetRequestedPortalThreads())
+
+	irodsData, err := mockServer.GetDataObject(irodsPath)
+	failError(t, err)
+	assert.Equal(t, data, irodsData)
+}
@@ -0,0 +1,95 @@
+package testcases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	nfcName = "café.txt"  // é as a single code point
+	nfdName = "café.txt" // e + combining acute accent
+)
+
+func TestUnicodePath(t *testing.T) {
+	t.Run("test NormalizeIRODSPath", testNormalizeIRODSPath)
+	t.Run("test ValidateIRODSPath", testValidateIRODSPath)
+	t.Run("test FileSystemUnicodeNormalization", testFileSystemUnicodeNormalization)
+}
+
+func testNormalizeIRODSPath(t *testing.T) {
+	assert.NotEqual(t, nfcName, nfdName)
+
+	assert.Equal(t, "/zone/home/"+nfcName, util.NormalizeIRODSPath("/zone/home/"+nfdName, util.UnicodeNormalizationNFC))
+	assert.Equal(t, "/zone/home/"+nfdName, util.NormalizeIRODSPath("/zone/home/"+nfcName, util.UnicodeNormalizationNFD))
+	assert.Equal(t, "/zone/home/"+nfdName, util.NormalizeIRODSPath("/zone/home/"+nfdName, util.UnicodeNormalizationNone))
+
+	assert.True(t, util.IsNormalizedIRODSPath(nfcName, util.UnicodeNormalizationNFC))
+	assert.False(t, util.IsNormalizedIRODSPath(nfdName, util.UnicodeNormalizationNFC))
+
+	form, err := util.GetUnicodeNormalizationForm("nfd")
+	failError(t, err)
+	assert.Equal(t, util.UnicodeNormalizationNFD, form)
+
+	_, err = util.GetUnicodeNormalizationForm("nfkc")
+	assert.Error(t, err)
+}
+
+func testValidateIRODSPath(t *testing.T) {
+	assert.NoError(t, util.ValidateIRODSPath("/"))
+	assert.NoError(t, util.ValidateIRODSPath("/zone/home/"+nfdName))
+	assert.NoError(t, util.ValidateIRODSPath("/zone/home/dir/"))
+
+	assert.Error(t, util.ValidateIRODSPath("zone/home"))
+	assert.Error(t, util.ValidateIRODSPath("/zone//home"))
+	assert.Error(t, util.ValidateIRODSPath("/zone/../home"))
+	assert.Error(t, util.ValidateIRODSPath("/zone/home/a\x00b"))
+	assert.Error(t, util.ValidateIRODSPath("/zone/home/\xff"))
+
+	// paths of at most MaxPathLength bytes are valid
+	longPath := "/zone/home/" + strings.Repeat("a", common.MaxPathLength-len("/zone/home/"))
+	assert.NoError(t, util.ValidateIRODSPath(longPath))
+	assert.Error(t, util.ValidateIRODSPath(longPath+"a"))
+
+	assert.NoError(t, util.ValidateIRODSObjectName("a `name` with 'quotes' & spaces"))
+	assert.Error(t, util.ValidateIRODSObjectName(""))
+	assert.Error(t, util.ValidateIRODSObjectName("a/b"))
+	assert.Error(t, util.ValidateIRODSObjectName(".."))
+}
+
+func testFileSystemUnicodeNormalization(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	config := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+	config.UnicodeNormalization = util.UnicodeNormalizationNFC
+
+	filesystem, err := fs.NewFileSystem(account, config)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	handle, err := filesystem.CreateFile(homedir+"/"+nfdName, "", "w")
+	failError(t, err)
+	failError(t, handle.Close())
+
+	// stored as NFC
+	_, err = mockServer.GetDataObject(homedir + "/" + nfcName)
+	failError(t, err)
+
+	// both forms refer to the same data object
+	assert.True(t, filesystem.ExistsFile(homedir+"/"+nfcName))
+	assert.True(t, filesystem.ExistsFile(homedir+"/"+nfdName))
+
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,215 @@
+package testcases
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateAccount(t *testing.T) {
+	t.Run("test SessionUpdateAccount", testSessionUpdateAccount)
+	t.Run("test FileSystemUpdateAccount", testFileSystemUpdateAccount)
+	t.Run("test ManagedSessionUpdateAccount", testManagedSessionUpdateAccount)
+	t.Run("test UpdateAccountDuringKeepalive", testUpdateAccountDuringKeepalive)
+	t.Run("test FileSystemUpdateAccountConcurrently", testFileSystemUpdateAccountConcurrently)
+}
+
+func testSessionUpdateAccount(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sess, err := session.NewIRODSSessionWithOptions(account, "go-irodsclient-test")
+	failError(t, err)
+	defer sess.Release()
+
+	idleConn, err := sess.AcquireConnection()
+	failError(t, err)
+	busyConn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	err = sess.ReturnConnection(idleConn)
+	failError(t, err)
+	assert.Equal(t, 2, sess.ConnectionTotal())
+
+	// the password is rotated
+	err = mockServer.SetUserPassword("alice", "rotated_password")
+	failError(t, err)
+
+	newAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	err = sess.UpdateAccount(newAccount)
+	failError(t, err)
+	assert.Equal(t, newAccount, sess.GetAccount())
+
+	// the idle connection is closed, the connection in use is closed when returned
+	assert.Equal(t, 1, sess.ConnectionTotal())
+	assert.True(t, busyConn.IsConnected())
+
+	err = sess.ReturnConnection(busyConn)
+	failError(t, err)
+	assert.False(t, busyConn.IsConnected())
+	assert.Equal(t, 0, sess.ConnectionTotal())
+
+	// new connections authenticate with the rotated password
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+	assert.Equal(t, "rotated_password", conn.GetAccount().Password)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+	assert.Equal(t, 1, sess.ConnectionTotal())
+}
+
+func testFileSystemUpdateAccount(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+	_, err = filesystem.Stat(homedir)
+	failError(t, err)
+
+	err = mockServer.SetUserPassword("alice", "rotated_password")
+	failError(t, err)
+
+	// connections authenticated before are not affected
+	_, err = filesystem.Stat(homedir)
+	failError(t, err)
+
+	// connections of the old password are closed
+	err = filesystem.UpdateAccount(account)
+	failError(t, err)
+
+	_, err = filesystem.Stat(homedir)
+	assert.Error(t, err)
+	assert.True(t, types.IsAuthError(err))
+
+	newAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	// the authentication failure of the old password does not block the new password
+	err = filesystem.UpdateAccount(newAccount)
+	failError(t, err)
+
+	_, err = filesystem.Stat(homedir)
+	failError(t, err)
+}
+
+func testManagedSessionUpdateAccount(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	manager := session.NewIRODSSessionManager(session.NewIRODSSessionManagerConfigWithDefault("go-irodsclient-test"))
+	defer manager.Release()
+
+	sess, err := manager.GetSession(account)
+	failError(t, err)
+
+	newAccount := *account
+	newAccount.Password = "rotated_password"
+	err = sess.UpdateAccount(&newAccount)
+	failError(t, err)
+
+	// sessions of a manager are kept per user
+	otherAccount := *account
+	otherAccount.ClientUser = "bob"
+	otherAccount.ProxyUser = "bob"
+	err = sess.UpdateAccount(&otherAccount)
+	assert.Error(t, err)
+	assert.Equal(t, &newAccount, sess.GetAccount())
+}
+
+func testUpdateAccountDuringKeepalive(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	sessConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessConfig.ConnectionKeepaliveInterval = 100 * time.Millisecond
+
+	sess, err := session.NewIRODSSession(account, sessConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+
+	// keep the idle connection out of the idle list during keepalive
+	mockServer.SetHeartbeatDelay(500 * time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for mockServer.GetHeartbeatCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Greater(t, mockServer.GetHeartbeatCount(), 0)
+
+	newAccount := *account
+	newAccount.Password = "rotated_password"
+	err = sess.UpdateAccount(&newAccount)
+	failError(t, err)
+
+	mockServer.SetHeartbeatDelay(0)
+	time.Sleep(700 * time.Millisecond)
+
+	// the connection of the old account is closed instead of being put back
+	assert.Equal(t, 0, sess.ConnectionTotal())
+}
+
+func testFileSystemUpdateAccountConcurrently(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithOptions(account, "go-irodsclient-test", fs.WithNoCache())
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := "/mockzone/home/alice"
+
+	// accounts are read while being updated, run with -race
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			err := filesystem.UpdateAccount(account)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+
+			_, err := filesystem.GetUser("alice", "")
+			assert.NoError(t, err)
+
+			_, err = filesystem.Stat(homedir)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
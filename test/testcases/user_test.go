@@ -0,0 +1,433 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser(t *testing.T) {
+	t.Run("test GetUser", testGetUser)
+	t.Run("test GetUserWithCache", testGetUserWithCache)
+	t.Run("test ListUsersByNameWildcard", testListUsersByNameWildcard)
+	t.Run("test UserGroupMetadata", testUserGroupMetadata)
+	t.Run("test SetUserType", testSetUserType)
+	t.Run("test DisableUser", testDisableUser)
+	t.Run("test GroupMemberCache", testGroupMemberCache)
+	t.Run("test GetGroupWithCache", testGetGroupWithCache)
+}
+
+func testGetUser(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	conn := connection.NewIRODSConnection(account, 30*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	failError(t, err)
+	defer conn.Disconnect()
+
+	user, err := irods_fs.GetUser(conn, "alice", "mockzone")
+	failError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	assert.Equal(t, "mockzone", user.Zone)
+	assert.Equal(t, types.IRODSUserRodsUser, user.Type)
+	assert.Greater(t, user.ID, int64(0))
+	assert.False(t, user.CreateTime.IsZero())
+	assert.False(t, user.ModifyTime.IsZero())
+
+	// client zone is used if zone is not given
+	user, err = irods_fs.GetUser(conn, "rods", "")
+	failError(t, err)
+	assert.Equal(t, "rods", user.Name)
+	assert.True(t, user.IsAdminUser())
+
+	_, err = irods_fs.GetUser(conn, "no_such_user", "")
+	assert.Error(t, err)
+	assert.True(t, types.IsUserNotFoundError(err))
+
+	_, err = irods_fs.GetUser(conn, "alice", "otherzone")
+	assert.Error(t, err)
+	assert.True(t, types.IsUserNotFoundError(err))
+}
+
+func testGetUserWithCache(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	user, err := filesystem.GetUser("alice", "")
+	failError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	assert.Equal(t, "mockzone", user.Zone)
+
+	// served from cache
+	mockServer.Stop()
+
+	cachedUser, err := filesystem.GetUser("alice", "mockzone")
+	failError(t, err)
+	assert.Equal(t, user, cachedUser)
+}
+
+func testListUsersByNameWildcard(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("alicia", "alicia_password", types.IRODSUserRodsUser)
+	failError(t, err)
+	err = mockServer.AddUser("bob", "bob_password", types.IRODSUserRodsUser)
+	failError(t, err)
+	err = mockServer.AddUser("alice_lab", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	users, err := filesystem.ListUsersByNameWildcard("ali%")
+	failError(t, err)
+
+	userNames := []string{}
+	for _, user := range users {
+		userNames = append(userNames, user.Name)
+	}
+	assert.ElementsMatch(t, []string{"alice", "alicia"}, userNames)
+
+	users, err = filesystem.ListUsersByNameWildcard("b_b")
+	failError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "bob", users[0].Name)
+
+	users, err = filesystem.ListUsersByNameWildcard("carol%")
+	failError(t, err)
+	assert.Len(t, users, 0)
+
+	groups, err := filesystem.ListGroupsByNameWildcard("ali%")
+	failError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "alice_lab", groups[0].Name)
+	assert.True(t, groups[0].IsGroup())
+}
+
+func testUserGroupMetadata(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	err := mockServer.AddUser("bob", "bob_password", types.IRODSUserRodsUser)
+	failError(t, err)
+	err = mockServer.AddUser("lab", "", types.IRODSUserRodsGroup)
+	failError(t, err)
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.AddUserMetadata("alice", "orcid", "0000-0002-1825-0097", "")
+	failError(t, err)
+	err = filesystem.AddUserMetadata("bob", "orcid", "0000-0001-5109-3700", "")
+	failError(t, err)
+	err = filesystem.AddGroupMetadata("lab", "orcid", "0000-0002-0000-0000", "")
+	failError(t, err)
+	err = filesystem.AddGroupMetadata("lab", "project", "genomics", "")
+	failError(t, err)
+
+	// group metadata
+	metas, err := filesystem.ListGroupMetadata("lab")
+	failError(t, err)
+	assert.Len(t, metas, 2)
+
+	err = filesystem.DeleteGroupMetadataByName("lab", "project")
+	failError(t, err)
+
+	metas, err = filesystem.ListGroupMetadata("lab")
+	failError(t, err)
+	assert.Len(t, metas, 1)
+
+	err = filesystem.DeleteGroupMetadata("lab", metas[0].AVUID)
+	failError(t, err)
+
+	metas, err = filesystem.ListGroupMetadata("lab")
+	failError(t, err)
+	assert.Len(t, metas, 0)
+
+	err = filesystem.AddGroupMetadata("lab", "project", "genomics", "")
+	failError(t, err)
+
+	// search
+	users, err := filesystem.SearchUsersByMeta("orcid", "0000-0002-1825-0097")
+	failError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "alice", users[0].Name)
+
+	users, err = filesystem.SearchUsersByMetaWildcard("orcid", "0000-%")
+	failError(t, err)
+	assert.Len(t, users, 2)
+
+	groups, err := filesystem.SearchGroupsByMeta("project", "genomics")
+	failError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "lab", groups[0].Name)
+	assert.True(t, groups[0].IsGroup())
+
+	groups, err = filesystem.SearchGroupsByMetaWildcard("project", "gen%")
+	failError(t, err)
+	assert.Len(t, groups, 1)
+
+	groups, err = filesystem.SearchGroupsByMeta("orcid", "0000-0002-1825-0097")
+	failError(t, err)
+	assert.Len(t, groups, 0)
+}
+
+func testSetUserType(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.CreateUser("bob", "", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	// cached
+	user, err := filesystem.GetUser("bob", "")
+	failError(t, err)
+	assert.True(t, user.IsUser())
+
+	users, err := filesystem.ListUsers()
+	failError(t, err)
+	assert.Len(t, users, 3)
+
+	err = filesystem.SetUserType("bob", "", types.IRODSUserGroupAdmin)
+	failError(t, err)
+
+	user, err = filesystem.GetUser("bob", "")
+	failError(t, err)
+	assert.True(t, user.IsAdminGroup())
+
+	// a user cannot become a group
+	err = filesystem.SetUserType("bob", "", types.IRODSUserRodsGroup)
+	assert.Error(t, err)
+
+	err = filesystem.RemoveUser("bob", "")
+	failError(t, err)
+
+	_, err = filesystem.GetUser("bob", "")
+	assert.Error(t, err)
+	assert.True(t, types.IsUserNotFoundError(err))
+
+	users, err = filesystem.ListUsers()
+	failError(t, err)
+	assert.Len(t, users, 2)
+
+	// only admins can change users
+	aliceAccount, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	aliceFilesystem, err := fs.NewFileSystemWithDefault(aliceAccount, "go-irodsclient-test")
+	failError(t, err)
+	defer aliceFilesystem.Release()
+
+	err = aliceFilesystem.SetUserType("alice", "", types.IRODSUserRodsAdmin)
+	assert.Error(t, err)
+}
+
+func testDisableUser(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	user, err := filesystem.GetUser("alice", "")
+	failError(t, err)
+	assert.False(t, user.IsDisabled())
+
+	err = filesystem.SetUserInfo("alice", "", "staff since 2020")
+	failError(t, err)
+
+	// other info is kept
+	err = filesystem.DisableUser("alice", "")
+	failError(t, err)
+
+	user, err = filesystem.GetUser("alice", "")
+	failError(t, err)
+	assert.True(t, user.IsDisabled())
+	assert.Equal(t, "staff since 2020 "+types.IRODSUserInfoDisabled, user.Info)
+
+	err = filesystem.DisableUser("alice", "")
+	failError(t, err)
+
+	err = filesystem.EnableUser("alice", "")
+	failError(t, err)
+
+	user, err = filesystem.GetUser("alice", "")
+	failError(t, err)
+	assert.False(t, user.IsDisabled())
+	assert.Equal(t, "staff since 2020", user.Info)
+
+	assert.Equal(t, types.IRODSUserInfoDisabled, types.MarkIRODSUserInfoDisabled(""))
+	assert.Equal(t, "", types.UnmarkIRODSUserInfoDisabled(types.IRODSUserInfoDisabled))
+	assert.Equal(t, "not-disabled", types.UnmarkIRODSUserInfoDisabled("not-disabled"))
+}
+
+func testGroupMemberCache(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	groups, err := filesystem.ListGroups()
+	failError(t, err)
+	assert.Len(t, groups, 0)
+
+	err = filesystem.CreateGroup("lab")
+	failError(t, err)
+
+	groups, err = filesystem.ListGroups()
+	failError(t, err)
+	assert.Len(t, groups, 1)
+
+	// cached, a group is a member of itself
+	members, err := filesystem.ListGroupUsers("lab")
+	failError(t, err)
+	assert.Len(t, members, 1)
+
+	userGroups, err := filesystem.ListUserGroups("alice")
+	failError(t, err)
+	assert.Len(t, userGroups, 0)
+
+	err = filesystem.AddGroupMember("lab", "alice", "")
+	failError(t, err)
+
+	members, err = filesystem.ListGroupUsers("lab")
+	failError(t, err)
+	assert.Len(t, members, 2)
+
+	userGroups, err = filesystem.ListUserGroups("alice")
+	failError(t, err)
+	assert.Len(t, userGroups, 1)
+
+	// members are listed with their types
+	err = filesystem.SetUserType("alice", "", types.IRODSUserGroupAdmin)
+	failError(t, err)
+
+	members, err = filesystem.ListGroupUsers("lab")
+	failError(t, err)
+	for _, member := range members {
+		assert.Equal(t, member.Name == "alice", member.IsAdminGroup())
+	}
+
+	err = filesystem.RemoveGroupMember("lab", "alice", "")
+	failError(t, err)
+
+	members, err = filesystem.ListGroupUsers("lab")
+	failError(t, err)
+	assert.Len(t, members, 1)
+
+	userGroups, err = filesystem.ListUserGroups("alice")
+	failError(t, err)
+	assert.Len(t, userGroups, 0)
+
+	err = filesystem.AddGroupMember("lab", "alice", "")
+	failError(t, err)
+
+	userGroups, err = filesystem.ListUserGroups("alice")
+	failError(t, err)
+	assert.Len(t, userGroups, 1)
+
+	// the group is gone from its members
+	err = filesystem.RemoveUser("lab", "")
+	failError(t, err)
+
+	userGroups, err = filesystem.ListUserGroups("alice")
+	failError(t, err)
+	assert.Len(t, userGroups, 0)
+
+	groups, err = filesystem.ListGroups()
+	failError(t, err)
+	assert.Len(t, groups, 0)
+}
+
+func testGetGroupWithCache(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("rods")
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	err = filesystem.CreateGroup("lab")
+	failError(t, err)
+	err = mockServer.SetUserComment("lab", "lab members")
+	failError(t, err)
+	err = filesystem.AddGroupMember("lab", "alice", "")
+	failError(t, err)
+
+	group, err := filesystem.GetGroup("lab")
+	failError(t, err)
+	assert.Equal(t, "lab", group.Name)
+	assert.Equal(t, "mockzone", group.Zone)
+	assert.Equal(t, "lab members", group.Description)
+	assert.Equal(t, 1, group.MemberCount)
+	assert.Greater(t, group.ID, int64(0))
+
+	// cached, changes behind the filesystem are not seen
+	err = mockServer.SetUserComment("lab", "changed")
+	failError(t, err)
+
+	group, err = filesystem.GetGroup("lab")
+	failError(t, err)
+	assert.Equal(t, "lab members", group.Description)
+
+	// member updates invalidate the cache
+	err = filesystem.AddGroupMember("lab", "rods", "")
+	failError(t, err)
+
+	group, err = filesystem.GetGroup("lab")
+	failError(t, err)
+	assert.Equal(t, "changed", group.Description)
+	assert.Equal(t, 2, group.MemberCount)
+
+	_, err = filesystem.GetGroup("no_such_group")
+	assert.Error(t, err)
+	assert.True(t, types.IsUserNotFoundError(err))
+
+	// a user is not a group
+	_, err = filesystem.GetGroup("alice")
+	assert.Error(t, err)
+}
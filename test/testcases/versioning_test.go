@@ -0,0 +1,123 @@
+package testcases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersioning(t *testing.T) {
+	t.Run("test VersioningOnWrite", testVersioningOnWrite)
+	t.Run("test VersioningDisabled", testVersioningDisabled)
+}
+
+func testVersioningOnWrite(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("version 1"))
+	failError(t, err)
+
+	config := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+	config.Versioning = true
+
+	filesystem, err := fs.NewFileSystem(account, config)
+	failError(t, err)
+	defer filesystem.Release()
+
+	// open with truncation
+	handle, err := filesystem.OpenFile(targetPath, "", "w")
+	failError(t, err)
+	_, err = handle.Write([]byte("version 2"))
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	// overwriting upload
+	localPath := filepath.Join(t.TempDir(), "file.txt")
+	err = os.WriteFile(localPath, []byte("version 3"), 0644)
+	failError(t, err)
+
+	err = filesystem.UploadFile(localPath, targetPath, "", false, nil)
+	failError(t, err)
+
+	// opening to append does not make a version
+	handle, err = filesystem.OpenFile(targetPath, "", "a")
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	data, err := mockServer.GetDataObject(targetPath)
+	failError(t, err)
+	assert.Equal(t, "version 3", string(data))
+
+	versions, err := filesystem.ListFileVersions(targetPath)
+	failError(t, err)
+	assert.Len(t, versions, 2)
+
+	for idx, expected := range []string{"version 1", "version 2"} {
+		data, err := mockServer.GetDataObject(versions[idx].Path)
+		failError(t, err)
+		assert.Equal(t, expected, string(data))
+
+		metas, err := filesystem.ListMetadata(versions[idx].Path)
+		failError(t, err)
+		assert.Len(t, metas, 1)
+		assert.Equal(t, fs.VersionAttributeName, metas[0].Name)
+		assert.Equal(t, targetPath, metas[0].Value)
+	}
+
+	// versions of other files are not listed
+	err = mockServer.PutDataObject(homedir+"/other.txt", "alice", []byte("other"))
+	failError(t, err)
+
+	handle, err = filesystem.CreateFile(homedir+"/other.txt", "", "w")
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	versions, err = filesystem.ListFileVersions(targetPath)
+	failError(t, err)
+	assert.Len(t, versions, 2)
+
+	versions, err = filesystem.ListFileVersions(homedir + "/other.txt")
+	failError(t, err)
+	assert.Len(t, versions, 1)
+}
+
+func testVersioningDisabled(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+	targetPath := homedir + "/file.txt"
+
+	err = mockServer.PutDataObject(targetPath, "alice", []byte("version 1"))
+	failError(t, err)
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	handle, err := filesystem.OpenFile(targetPath, "", "w")
+	failError(t, err)
+	err = handle.Close()
+	failError(t, err)
+
+	versions, err := filesystem.ListFileVersions(targetPath)
+	failError(t, err)
+	assert.Len(t, versions, 0)
+	assert.False(t, filesystem.ExistsDir(fs.GetVersionsDirPath(targetPath)))
+}
@@ -0,0 +1,103 @@
+package testcases
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	xmlTestNames = []string{
+		"plain.txt",
+		"a&b<c>d.txt",
+		"double\"quote'single`backtick.txt",
+		"tab\tnewline\ncarriage\rreturn",
+		"유니코드 パス ünïcödé 😀",
+		"&amp;&quot;&apos;",
+	}
+)
+
+func TestXML(t *testing.T) {
+	t.Run("test EncodeIRODSXML", testEncodeIRODSXML)
+	t.Run("test DecodeIRODSXML", testDecodeIRODSXML)
+	t.Run("test XMLRoundTrip", testXMLRoundTrip)
+	t.Run("test TalksCorrectXML", testTalksCorrectXML)
+}
+
+func testEncodeIRODSXML(t *testing.T) {
+	request := message.NewIRODSMessageMakeCollectionRequest("/zone/home/a\"b'c`d\te", false)
+	xmlBytes, err := request.GetBytes()
+	failError(t, err)
+
+	encoded, err := message.EncodeIRODSXML(xmlBytes, true)
+	failError(t, err)
+	assert.Contains(t, string(encoded), "<collName>/zone/home/a&quot;b&apos;c`d\te</collName>")
+
+	encoded, err = message.EncodeIRODSXML(xmlBytes, false)
+	failError(t, err)
+	assert.Contains(t, string(encoded), "<collName>/zone/home/a&quot;b'c&apos;d\te</collName>")
+
+	_, err = message.EncodeIRODSXML([]byte{0xff, 0xfe}, true)
+	assert.ErrorIs(t, err, message.ErrInvalidUTF8)
+}
+
+func testDecodeIRODSXML(t *testing.T) {
+	// servers older than 4.2.9 send ` as &apos; and ' as is
+	decoded, err := message.DecodeIRODSXML([]byte("<value>a&apos;b'c&quot;d</value>"), false)
+	failError(t, err)
+
+	sqlResult := message.IRODSMessageSQLResult{}
+	err = decodeXMLValue(decoded, &sqlResult)
+	failError(t, err)
+	assert.Equal(t, []string{"a`b'c\"d"}, sqlResult.Values)
+
+	decoded, err = message.DecodeIRODSXML([]byte("<value>a&apos;b`c&quot;d\x01</value>"), true)
+	failError(t, err)
+
+	sqlResult = message.IRODSMessageSQLResult{}
+	err = decodeXMLValue(decoded, &sqlResult)
+	failError(t, err)
+	assert.Equal(t, []string{"a'b`c\"d�"}, sqlResult.Values)
+}
+
+func testXMLRoundTrip(t *testing.T) {
+	for _, correctXML := range []bool{true, false} {
+		for _, name := range xmlTestNames {
+			sqlResult := message.IRODSMessageSQLResult{
+				AttributeIndex: 1,
+				ResultLen:      1,
+				Values:         []string{name},
+			}
+
+			xmlBytes, err := xml.Marshal(&sqlResult)
+			failError(t, err)
+
+			encoded, err := message.EncodeIRODSXML(xmlBytes, correctXML)
+			failError(t, err)
+
+			// servers echo values back in the same dialect
+			decoded, err := message.DecodeIRODSXML(encoded, correctXML)
+			failError(t, err)
+
+			result := message.IRODSMessageSQLResult{}
+			err = xml.Unmarshal(decoded, &result)
+			failError(t, err)
+			assert.Equal(t, []string{name}, result.Values)
+		}
+	}
+}
+
+func testTalksCorrectXML(t *testing.T) {
+	assert.True(t, message.TalksCorrectXML(nil))
+	assert.True(t, message.TalksCorrectXML(&types.IRODSVersion{ReleaseVersion: "rods4.3.0"}))
+	assert.True(t, message.TalksCorrectXML(&types.IRODSVersion{ReleaseVersion: "rods4.2.9"}))
+	assert.False(t, message.TalksCorrectXML(&types.IRODSVersion{ReleaseVersion: "rods4.2.8"}))
+	assert.True(t, message.TalksCorrectXML(&types.IRODSVersion{ReleaseVersion: "unknown"}))
+}
+
+func decodeXMLValue(value []byte, sqlResult *message.IRODSMessageSQLResult) error {
+	return xml.Unmarshal([]byte("<SqlResult_PI>"+string(value)+"</SqlResult_PI>"), sqlResult)
+}
@@ -0,0 +1,130 @@
+package webdav
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"golang.org/x/xerrors"
+
+	x_webdav "golang.org/x/net/webdav"
+)
+
+// FileInfo is file info of an iRODS entry, implements os.FileInfo and webdav.ETager
+type FileInfo struct {
+	*fs.EntryFileInfo
+}
+
+func newFileInfo(entry *fs.Entry) *FileInfo {
+	return &FileInfo{
+		EntryFileInfo: entry.ToFileInfo(),
+	}
+}
+
+// ETag returns an entity tag made from the checksum, falls back to the default if the checksum is not available
+func (info *FileInfo) ETag(ctx context.Context) (string, error) {
+	etag := fs.GetETag(info.GetEntry())
+	if len(etag) == 0 {
+		return "", x_webdav.ErrNotImplemented
+	}
+	return etag, nil
+}
+
+// File is an opened iRODS data object, implements webdav.File
+type File struct {
+	*fs.FileHandle
+}
+
+func newFile(handle *fs.FileHandle) *File {
+	return &File{
+		FileHandle: handle,
+	}
+}
+
+// Readdir always fails since a file is not a directory
+func (file *File) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, xerrors.Errorf("failed to read dir, %s is not a directory", file.GetEntry().Path)
+}
+
+// Stat returns file info of the file at the time it is opened
+func (file *File) Stat() (os.FileInfo, error) {
+	return newFileInfo(file.GetEntry()), nil
+}
+
+// Dir is an opened iRODS collection, implements webdav.File
+type Dir struct {
+	filesystem *fs.FileSystem
+	entry      *fs.Entry
+	children   []*fs.Entry
+	listed     bool
+}
+
+func newDir(filesystem *fs.FileSystem, entry *fs.Entry) *Dir {
+	return &Dir{
+		filesystem: filesystem,
+		entry:      entry,
+		children:   nil,
+		listed:     false,
+	}
+}
+
+// Close closes the directory
+func (dir *Dir) Close() error {
+	return nil
+}
+
+// Read always fails since a directory has no content
+func (dir *Dir) Read(buffer []byte) (int, error) {
+	return 0, xerrors.Errorf("failed to read, %s is a directory", dir.entry.Path)
+}
+
+// Write always fails since a directory has no content
+func (dir *Dir) Write(data []byte) (int, error) {
+	return 0, xerrors.Errorf("failed to write, %s is a directory", dir.entry.Path)
+}
+
+// Seek rewinds directory listing if offset is 0 from start, fails otherwise
+func (dir *Dir) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, xerrors.Errorf("failed to seek, %s is a directory", dir.entry.Path)
+	}
+
+	dir.children = nil
+	dir.listed = false
+	return 0, nil
+}
+
+// Readdir returns file info of up to count entries in the directory, all remaining entries if count <= 0
+func (dir *Dir) Readdir(count int) ([]os.FileInfo, error) {
+	if !dir.listed {
+		children, err := dir.filesystem.List(dir.entry.Path)
+		if err != nil {
+			return nil, fs.ToOSError("readdir", dir.entry.Path, err)
+		}
+
+		dir.children = children
+		dir.listed = true
+	}
+
+	if count > 0 && len(dir.children) == 0 {
+		return nil, io.EOF
+	}
+
+	entries := dir.children
+	if count > 0 && count < len(entries) {
+		entries = entries[:count]
+	}
+	dir.children = dir.children[len(entries):]
+
+	infos := make([]os.FileInfo, len(entries))
+	for idx, entry := range entries {
+		infos[idx] = newFileInfo(entry)
+	}
+	return infos, nil
+}
+
+// Stat returns file info of the directory
+func (dir *Dir) Stat() (os.FileInfo, error) {
+	return newFileInfo(dir.entry), nil
+}
@@ -0,0 +1,26 @@
+module github.com/cyverse/go-irodsclient/webdav
+
+go 1.18
+
+require (
+	github.com/cyverse/go-irodsclient v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/net v0.10.0
+	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/hashicorp/go-rootcerts v1.0.2 // indirect
+	github.com/mitchellh/go-homedir v1.1.0 // indirect
+	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rs/xid v1.3.0 // indirect
+	github.com/sirupsen/logrus v1.7.0 // indirect
+	golang.org/x/sys v0.8.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// the adapter is versioned with the client in this repository
+replace github.com/cyverse/go-irodsclient => ../
@@ -0,0 +1,145 @@
+package webdav
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+
+	x_webdav "golang.org/x/net/webdav"
+)
+
+// FileSystem is a WebDAV file system backed by iRODS, implements webdav.FileSystem
+// names are resolved under the root path
+type FileSystem struct {
+	filesystem *fs.FileSystem
+	rootPath   string
+}
+
+// NewFileSystem creates a new FileSystem serving iRODS entries under rootPath
+func NewFileSystem(filesystem *fs.FileSystem, rootPath string) *FileSystem {
+	return &FileSystem{
+		filesystem: filesystem,
+		rootPath:   rootPath,
+	}
+}
+
+// NewHandler creates a WebDAV http handler serving iRODS entries under rootPath with an in-memory lock system
+func NewHandler(filesystem *fs.FileSystem, rootPath string, urlPrefix string) *x_webdav.Handler {
+	return &x_webdav.Handler{
+		Prefix:     urlPrefix,
+		FileSystem: NewFileSystem(filesystem, rootPath),
+		LockSystem: x_webdav.NewMemLS(),
+	}
+}
+
+// getIRODSPath returns an iRODS path of the name, the name cannot go above the root path
+func (davfs *FileSystem) getIRODSPath(name string) string {
+	return path.Join(davfs.rootPath, path.Clean("/"+name))
+}
+
+// Mkdir creates a directory, the parent directory must exist
+func (davfs *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	err := davfs.filesystem.MakeDir(davfs.getIRODSPath(name), false)
+	if err != nil {
+		return fs.ToOSError("mkdir", name, err)
+	}
+	return nil
+}
+
+// OpenFile opens a file or a directory
+func (davfs *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (x_webdav.File, error) {
+	irodsPath := davfs.getIRODSPath(name)
+
+	entry, err := davfs.filesystem.Stat(irodsPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) {
+			return nil, fs.ToOSError("open", name, err)
+		}
+
+		if flag&os.O_CREATE == 0 {
+			return nil, fs.ToOSError("open", name, err)
+		}
+
+		handle, err := davfs.filesystem.CreateFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+		if err != nil {
+			return nil, fs.ToOSError("open", name, err)
+		}
+		return newFile(handle), nil
+	}
+
+	if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+		return nil, fs.ToOSError("open", name, types.NewFileAlreadyExistError(irodsPath))
+	}
+
+	if entry.IsDir() {
+		if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: xerrors.Errorf("%s is a directory", irodsPath)}
+		}
+		return newDir(davfs.filesystem, entry), nil
+	}
+
+	handle, err := davfs.filesystem.OpenFile(irodsPath, "", string(types.GetFileOpenModeFromFlag(flag)))
+	if err != nil {
+		return nil, fs.ToOSError("open", name, err)
+	}
+	return newFile(handle), nil
+}
+
+// RemoveAll removes a file or a directory recursively, it is not an error if the name does not exist
+func (davfs *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	irodsPath := davfs.getIRODSPath(name)
+
+	entry, err := davfs.filesystem.Stat(irodsPath)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			return nil
+		}
+		return fs.ToOSError("removeall", name, err)
+	}
+
+	if entry.IsDir() {
+		err = davfs.filesystem.RemoveDir(irodsPath, true, true)
+	} else {
+		err = davfs.filesystem.RemoveFile(irodsPath, true)
+	}
+
+	if err != nil {
+		return fs.ToOSError("removeall", name, err)
+	}
+	return nil
+}
+
+// Rename renames a file or a directory to the exact new name
+func (davfs *FileSystem) Rename(ctx context.Context, oldName string, newName string) error {
+	irodsSrcPath := davfs.getIRODSPath(oldName)
+	irodsDestPath := davfs.getIRODSPath(newName)
+
+	entry, err := davfs.filesystem.Stat(irodsSrcPath)
+	if err != nil {
+		return fs.ToOSError("rename", oldName, err)
+	}
+
+	if entry.IsDir() {
+		err = davfs.filesystem.RenameDirToDir(irodsSrcPath, irodsDestPath)
+	} else {
+		err = davfs.filesystem.RenameFileToFile(irodsSrcPath, irodsDestPath)
+	}
+
+	if err != nil {
+		return fs.ToOSError("rename", oldName, err)
+	}
+	return nil
+}
+
+// Stat returns file info of a file or a directory
+func (davfs *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	entry, err := davfs.filesystem.Stat(davfs.getIRODSPath(name))
+	if err != nil {
+		return nil, fs.ToOSError("stat", name, err)
+	}
+	return newFileInfo(entry), nil
+}
@@ -0,0 +1,206 @@
+package webdav_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/test/mock"
+	"github.com/cyverse/go-irodsclient/webdav"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebDAV(t *testing.T) {
+	t.Run("test WebDAVFileSystem", testWebDAVFileSystem)
+	t.Run("test WebDAVHandler", testWebDAVHandler)
+}
+
+func doWebDAVRequest(t *testing.T, method string, url string, body string, headers map[string]string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	failError(t, err)
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	failError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	failError(t, err)
+
+	return resp, string(respBody)
+}
+
+func testWebDAVFileSystem(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	davfs := webdav.NewFileSystem(filesystem, homedir)
+	ctx := context.Background()
+
+	err = davfs.Mkdir(ctx, "/dir", 0755)
+	failError(t, err)
+
+	err = davfs.Mkdir(ctx, "/dir", 0755)
+	assert.True(t, os.IsExist(err))
+
+	err = davfs.Mkdir(ctx, "/missing/dir", 0755)
+	assert.True(t, os.IsNotExist(err))
+
+	// write
+	file, err := davfs.OpenFile(ctx, "/dir/test.txt", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	failError(t, err)
+	_, err = file.Write([]byte("hello webdav"))
+	failError(t, err)
+	err = file.Close()
+	failError(t, err)
+
+	_, err = davfs.OpenFile(ctx, "/dir/test.txt", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	assert.True(t, os.IsExist(err))
+
+	// read
+	file, err = davfs.OpenFile(ctx, "/dir/test.txt", os.O_RDONLY, 0)
+	failError(t, err)
+	data, err := io.ReadAll(file)
+	failError(t, err)
+	assert.Equal(t, "hello webdav", string(data))
+	err = file.Close()
+	failError(t, err)
+
+	info, err := davfs.Stat(ctx, "/dir/test.txt")
+	failError(t, err)
+	assert.Equal(t, "test.txt", info.Name())
+	assert.Equal(t, int64(12), info.Size())
+
+	// list
+	dir, err := davfs.OpenFile(ctx, "/dir", os.O_RDONLY, 0)
+	failError(t, err)
+
+	infos, err := dir.Readdir(1)
+	failError(t, err)
+	assert.Len(t, infos, 1)
+	assert.Equal(t, "test.txt", infos[0].Name())
+
+	_, err = dir.Readdir(1)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = dir.Read(make([]byte, 10))
+	assert.Error(t, err)
+	err = dir.Close()
+	failError(t, err)
+
+	// rename
+	err = davfs.Rename(ctx, "/dir/test.txt", "/dir/renamed.txt")
+	failError(t, err)
+
+	_, err = davfs.Stat(ctx, "/dir/test.txt")
+	assert.True(t, os.IsNotExist(err))
+
+	err = davfs.Rename(ctx, "/dir", "/dir2")
+	failError(t, err)
+
+	info, err = davfs.Stat(ctx, "/dir2/renamed.txt")
+	failError(t, err)
+	assert.False(t, info.IsDir())
+
+	// remove
+	err = davfs.RemoveAll(ctx, "/dir2")
+	failError(t, err)
+
+	_, err = davfs.Stat(ctx, "/dir2")
+	assert.True(t, os.IsNotExist(err))
+
+	err = davfs.RemoveAll(ctx, "/dir2")
+	failError(t, err)
+
+	// cannot escape the root path
+	info, err = davfs.Stat(ctx, "/../../")
+	failError(t, err)
+	assert.Equal(t, "alice", info.Name())
+}
+
+func testWebDAVHandler(t *testing.T) {
+	mockServer := startMockServer(t)
+	defer mockServer.Stop()
+
+	account, err := mockServer.GetAccount("alice")
+	failError(t, err)
+
+	homedir := "/mockzone/home/alice"
+
+	filesystem, err := fs.NewFileSystemWithDefault(account, "go-irodsclient-test")
+	failError(t, err)
+	defer filesystem.Release()
+
+	server := httptest.NewServer(webdav.NewHandler(filesystem, homedir, "/dav"))
+	defer server.Close()
+
+	resp, _ := doWebDAVRequest(t, "MKCOL", server.URL+"/dav/docs", "", nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, _ = doWebDAVRequest(t, "MKCOL", server.URL+"/dav/docs", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, _ = doWebDAVRequest(t, "MKCOL", server.URL+"/dav/missing/docs", "", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, _ = doWebDAVRequest(t, http.MethodPut, server.URL+"/dav/docs/readme.txt", "read me", nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, body := doWebDAVRequest(t, http.MethodGet, server.URL+"/dav/docs/readme.txt", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "read me", body)
+
+	resp, body = doWebDAVRequest(t, "PROPFIND", server.URL+"/dav/docs", "", map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Contains(t, body, "/dav/docs/readme.txt")
+
+	resp, _ = doWebDAVRequest(t, "MOVE", server.URL+"/dav/docs/readme.txt", "", map[string]string{"Destination": server.URL + "/dav/docs/moved.txt"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, _ = doWebDAVRequest(t, http.MethodGet, server.URL+"/dav/docs/readme.txt", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = doWebDAVRequest(t, http.MethodDelete, server.URL+"/dav/docs", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	assert.False(t, filesystem.Exists(homedir+"/docs"))
+}
+
+// startMockServer starts a mock iRODS server with users rods and alice, as test/testcases does
+func startMockServer(t *testing.T) *mock.IRODSMockServer {
+	mockServer, err := mock.NewIRODSMockServer("mockzone", "rods", "rods_password")
+	failError(t, err)
+
+	err = mockServer.AddUser("alice", "alice_password", types.IRODSUserRodsUser)
+	failError(t, err)
+
+	err = mockServer.Start()
+	failError(t, err)
+
+	return mockServer
+}
+
+func failError(t *testing.T, err error) {
+	if err != nil {
+		t.Errorf("%+v", err)
+		t.FailNow()
+	}
+}